@@ -0,0 +1,19 @@
+package apikey
+
+import "errors"
+
+const (
+	ParamID = "id"
+)
+
+// keyPrefix starts every API key, so that leaked keys are easy to recognize
+// and told apart from JWTs.
+const keyPrefix = "umk_"
+
+// prefixLength is how much of a key is stored in clear and listed, enough to
+// tell keys apart without being able to use them.
+const prefixLength = 12
+
+var (
+	ErrKeyNotFound = errors.New("API key not found")
+)
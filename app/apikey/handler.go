@@ -0,0 +1,90 @@
+package apikey
+
+import (
+	"context"
+	"user-management/logger"
+	"user-management/response"
+
+	echo "github.com/labstack/echo/v4"
+)
+
+type Usecase interface {
+	CreateKey(ctx context.Context, req CreateRequest) (*response.StdResp[any], error)
+	FindKeys(ctx context.Context) (*response.StdResp[any], error)
+	RevokeKey(ctx context.Context, id string) (*response.StdResp[any], error)
+}
+
+type Handler interface {
+	CreateKey(c echo.Context) error
+	FindKeys(c echo.Context) error
+	RevokeKey(c echo.Context) error
+}
+
+type handler struct {
+	usecase Usecase
+}
+
+func NewHandler(u Usecase) *handler {
+	return &handler{
+		usecase: u,
+	}
+}
+
+func (h *handler) CreateKey(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	var request CreateRequest
+	err = c.Bind(&request)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Bind request error: %v", err.Error())
+		return c.JSON(response.UnexpectedRequest().WithHTTPStatus())
+	}
+
+	if resp := request.RequestValidation(); !resp.IsSuccess() {
+		return c.JSON(resp.WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.CreateKey(ctx, request)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+func (h *handler) FindKeys(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.FindKeys(ctx)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+func (h *handler) RevokeKey(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	paramId := c.Param(ParamID)
+	if respValidate := IdValidation(paramId); !respValidate.IsSuccess() {
+		return c.JSON(respValidate.WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.RevokeKey(ctx, paramId)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
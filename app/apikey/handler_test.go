@@ -0,0 +1,129 @@
+package apikey_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-management/app/apikey"
+	"user-management/auth"
+	"user-management/logger"
+	"user-management/response"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockUsecase struct {
+	mock.Mock
+}
+
+func (m *mockUsecase) CreateKey(ctx context.Context, req apikey.CreateRequest) (*response.StdResp[any], error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) FindKeys(ctx context.Context) (*response.StdResp[any], error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) RevokeKey(ctx context.Context, id string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func newTestContext(method, target string, body any) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	var req *http.Request
+	if body == nil {
+		req = httptest.NewRequest(method, target, nil)
+	} else {
+		j, _ := json.Marshal(body)
+		req = httptest.NewRequest(method, target, bytes.NewReader(j))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	ctx := context.WithValue(c.Request().Context(), logger.LogContext, logger.NewZap())
+	c.SetRequest(req.WithContext(ctx))
+	return c, rec
+}
+
+func TestHandlerCreateKey(t *testing.T) {
+	req := apikey.CreateRequest{Name: "sync", Scopes: []string{auth.ScopeUsersRead}}
+	c, rec := newTestContext(http.MethodPost, "/api-keys", req)
+	mockUc := new(mockUsecase)
+	mockUc.On("CreateKey", mock.Anything, req).Return(response.SuccessWithData(apikey.CreateResponse{ID: "1", Key: "umk_abc"}), nil)
+
+	err := apikey.NewHandler(mockUc).CreateKey(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "umk_abc")
+}
+
+func TestHandlerCreateKey_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		req  apikey.CreateRequest
+	}{
+		{"missing name", apikey.CreateRequest{Scopes: []string{auth.ScopeUsersRead}}},
+		{"missing scopes", apikey.CreateRequest{Name: "sync"}},
+		{"unknown scope", apikey.CreateRequest{Name: "sync", Scopes: []string{"users:admin"}}},
+		{"invalid tenant", apikey.CreateRequest{Name: "sync", Scopes: []string{auth.ScopeUsersRead}, TenantID: "acme"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newTestContext(http.MethodPost, "/api-keys", tt.req)
+			mockUc := new(mockUsecase)
+
+			err := apikey.NewHandler(mockUc).CreateKey(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			mockUc.AssertNotCalled(t, "CreateKey")
+		})
+	}
+}
+
+func TestHandlerFindKeys_ServiceError(t *testing.T) {
+	c, rec := newTestContext(http.MethodGet, "/api-keys", nil)
+	mockUc := new(mockUsecase)
+	mockUc.On("FindKeys", mock.Anything).Return((*response.StdResp[any])(nil), errors.New("db down"))
+
+	err := apikey.NewHandler(mockUc).FindKeys(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestHandlerRevokeKey(t *testing.T) {
+	c, rec := newTestContext(http.MethodDelete, "/api-keys/60d5ec49f1a2c8b1f8e4e1b2", nil)
+	c.SetParamNames(apikey.ParamID)
+	c.SetParamValues("60d5ec49f1a2c8b1f8e4e1b2")
+	mockUc := new(mockUsecase)
+	mockUc.On("RevokeKey", mock.Anything, "60d5ec49f1a2c8b1f8e4e1b2").Return(response.APIKeyNotFound(), nil)
+
+	err := apikey.NewHandler(mockUc).RevokeKey(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHandlerRevokeKey_InvalidId(t *testing.T) {
+	c, rec := newTestContext(http.MethodDelete, "/api-keys/bad", nil)
+	c.SetParamNames(apikey.ParamID)
+	c.SetParamValues("bad")
+	mockUc := new(mockUsecase)
+
+	err := apikey.NewHandler(mockUc).RevokeKey(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUc.AssertNotCalled(t, "RevokeKey")
+}
@@ -0,0 +1,73 @@
+package apikey
+
+import (
+	"context"
+	"time"
+	"user-management/config"
+	"user-management/storage"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type repository struct {
+	mc  storage.DatabaseConn
+	cfg config.MongoConfig
+}
+
+func NewRepository(mc storage.DatabaseConn, cfg config.MongoConfig) *repository {
+	return &repository{
+		mc:  mc,
+		cfg: cfg,
+	}
+}
+
+func (r *repository) CreateKey(ctx context.Context, key APIKey) (string, error) {
+	key.CreatedAt = time.Now()
+	ior, err := r.mc.Collection(r.cfg.APIKeyCollection).InsertOne(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	return ior.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+// FindKeyByHash looks a key up in every tenant, since the key is what tells
+// the tenant.
+func (r *repository) FindKeyByHash(ctx context.Context, keyHash string) (APIKey, error) {
+	var key APIKey
+	err := r.mc.Collection(r.cfg.APIKeyCollection).FindOne(ctx, bson.M{"key_hash": keyHash}).Decode(&key)
+	if err == mongo.ErrNoDocuments {
+		return key, ErrKeyNotFound
+	}
+	return key, err
+}
+
+// FindKeys lists the keys of ctx's tenant, oldest first.
+func (r *repository) FindKeys(ctx context.Context) ([]APIKey, error) {
+	opts := options.Find().SetSort(bson.D{bson.E{Key: "created_at", Value: 1}})
+	cursor, err := r.mc.Collection(r.cfg.APIKeyCollection).Find(ctx, storage.TenantFilter(ctx, bson.M{}), opts)
+	if err != nil {
+		return nil, err
+	}
+	var keys []APIKey
+	err = cursor.All(ctx, &keys)
+	return keys, err
+}
+
+// RevokeKey marks a key of ctx's tenant revoked. Keys already revoked are not
+// matched.
+func (r *repository) RevokeKey(ctx context.Context, id primitive.ObjectID, at time.Time) (int64, error) {
+	filter := storage.TenantFilter(ctx, bson.M{"_id": id, "revoked_at": nil})
+	result, err := r.mc.Collection(r.cfg.APIKeyCollection).UpdateOne(ctx, filter, bson.M{"$set": bson.M{"revoked_at": at}})
+	if err != nil {
+		return 0, err
+	}
+	return result.MatchedCount, nil
+}
+
+func (r *repository) TouchKey(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	_, err := r.mc.Collection(r.cfg.APIKeyCollection).UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"last_used_at": at}})
+	return err
+}
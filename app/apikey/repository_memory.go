@@ -0,0 +1,85 @@
+package apikey
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+	"user-management/auth"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryRepository keeps keys in process memory, for when the service runs
+// without a database.
+type memoryRepository struct {
+	mu   sync.Mutex
+	keys []APIKey
+}
+
+func NewMemoryRepository() *memoryRepository {
+	return &memoryRepository{}
+}
+
+func (r *memoryRepository) CreateKey(ctx context.Context, key APIKey) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if key.ID.IsZero() {
+		key.ID = primitive.NewObjectID()
+	}
+	key.CreatedAt = time.Now()
+	key.Scopes = slices.Clone(key.Scopes)
+	r.keys = append(r.keys, key)
+	return key.ID.Hex(), nil
+}
+
+func (r *memoryRepository) FindKeyByHash(ctx context.Context, keyHash string) (APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, k := range r.keys {
+		if k.KeyHash == keyHash {
+			return k, nil
+		}
+	}
+	return APIKey{}, ErrKeyNotFound
+}
+
+func (r *memoryRepository) FindKeys(ctx context.Context) ([]APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var keys []APIKey
+	for _, k := range r.keys {
+		if inTenant(ctx, k) {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+func (r *memoryRepository) RevokeKey(ctx context.Context, id primitive.ObjectID, at time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := slices.IndexFunc(r.keys, func(k APIKey) bool {
+		return k.ID == id && k.RevokedAt == nil && inTenant(ctx, k)
+	})
+	if i < 0 {
+		return 0, nil
+	}
+	r.keys[i].RevokedAt = &at
+	return 1, nil
+}
+
+func (r *memoryRepository) TouchKey(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i := slices.IndexFunc(r.keys, func(k APIKey) bool { return k.ID == id }); i >= 0 {
+		r.keys[i].LastUsedAt = &at
+	}
+	return nil
+}
+
+// inTenant mirrors storage.TenantFilter for a single key.
+func inTenant(ctx context.Context, k APIKey) bool {
+	tenantID, scoped := auth.TenantFromContext(ctx)
+	return !scoped || k.TenantID == tenantID
+}
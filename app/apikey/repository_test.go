@@ -0,0 +1,76 @@
+package apikey_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-management/app/apikey"
+	"user-management/auth"
+	"user-management/config"
+	"user-management/storage"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func newTestRepository(mt *mtest.T) apikey.Repository {
+	dbConn := storage.NewMongoConn(mt.Client, mt.Client.Database("testdb"))
+	return apikey.NewRepository(dbConn, config.MongoConfig{
+		Database:         "testdb",
+		APIKeyCollection: "api_keys",
+	})
+}
+
+func TestRepository_FindKeyByHash(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("found in any tenant", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(1, "testdb.api_keys", mtest.FirstBatch, bson.D{
+			bson.E{Key: "_id", Value: primitive.NewObjectID()},
+			bson.E{Key: "tenant_id", Value: "t1"},
+			bson.E{Key: "name", Value: "sync"},
+			bson.E{Key: "key_hash", Value: "h1"},
+			bson.E{Key: "scopes", Value: bson.A{"users:read"}},
+		}))
+
+		key, err := repo.FindKeyByHash(auth.WithTenant(context.Background(), "t2"), "h1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "t1", key.TenantID)
+		assert.Equal(t, []string{"users:read"}, key.Scopes)
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		assert.Equal(t, "h1", filter.Lookup("key_hash").StringValue())
+		_, err = filter.LookupErr("tenant_id")
+		assert.Error(t, err)
+	})
+
+	mt.Run("not found", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.api_keys", mtest.FirstBatch))
+
+		_, err := repo.FindKeyByHash(context.Background(), "h1")
+
+		assert.ErrorIs(t, err, apikey.ErrKeyNotFound)
+	})
+}
+
+func TestRepository_RevokeKey(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("tenant scoped and not yet revoked", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}, {Key: "nModified", Value: 1}})
+
+		revoked, err := repo.RevokeKey(auth.WithTenant(context.Background(), "t1"), primitive.NewObjectID(), time.Now())
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), revoked)
+		updates := mt.GetStartedEvent().Command.Lookup("updates").Array()
+		filter := updates.Index(0).Value().Document().Lookup("q").Document()
+		assert.Equal(t, "t1", filter.Lookup("tenant_id").StringValue())
+		assert.Equal(t, bson.TypeNull, filter.Lookup("revoked_at").Type)
+	})
+}
@@ -0,0 +1,169 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	"user-management/app/tenant"
+	"user-management/auth"
+	"user-management/config"
+	"user-management/opaque"
+	"user-management/response"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Repository interface {
+	CreateKey(ctx context.Context, key APIKey) (string, error)
+	FindKeyByHash(ctx context.Context, keyHash string) (APIKey, error)
+	FindKeys(ctx context.Context) ([]APIKey, error)
+	RevokeKey(ctx context.Context, id primitive.ObjectID, at time.Time) (int64, error)
+	TouchKey(ctx context.Context, id primitive.ObjectID, at time.Time) error
+}
+
+type TenantRepository interface {
+	FindTenantById(ctx context.Context, id string) (tenant.FindTenantResponse, error)
+}
+
+type usecase struct {
+	cfg     config.APIKeyConfig
+	repo    Repository
+	tenants TenantRepository
+}
+
+func NewUsecase(cfg config.APIKeyConfig, r Repository, tr TenantRepository) *usecase {
+	return &usecase{
+		cfg:     cfg,
+		repo:    r,
+		tenants: tr,
+	}
+}
+
+// CreateKey issues a key for a service. Tenant admins create keys for their
+// own tenant; platform admins choose one or leave it empty for a platform key.
+// The key itself is only ever returned here.
+func (u *usecase) CreateKey(ctx context.Context, req CreateRequest) (*response.StdResp[any], error) {
+	now := time.Now()
+	expiresAt := now.Add(u.cfg.DefaultTTL)
+	if req.ExpiresAt != nil {
+		expiresAt = *req.ExpiresAt
+	}
+	if !expiresAt.After(now) || expiresAt.Sub(now) > u.cfg.MaxTTL {
+		return response.InvalidData("expires_at"), nil
+	}
+	tenantID, scoped := auth.TenantFromContext(ctx)
+	if !scoped {
+		tenantID = req.TenantID
+	}
+	raw, err := newKey()
+	if err != nil {
+		return nil, err
+	}
+	key := APIKey{
+		TenantID:  tenantID,
+		Name:      strings.TrimSpace(req.Name),
+		Prefix:    raw[:prefixLength],
+		KeyHash:   opaque.Hash(raw),
+		Scopes:    auth.UniqueScopes(req.Scopes),
+		ExpiresAt: expiresAt,
+	}
+	if claims, err := auth.FromContext(ctx); err == nil {
+		key.CreatedBy = claims.UserID
+	}
+	id, err := u.repo.CreateKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return response.SuccessWithData(CreateResponse{ID: id, Key: raw, Prefix: key.Prefix, ExpiresAt: expiresAt}), nil
+}
+
+func (u *usecase) FindKeys(ctx context.Context) (*response.StdResp[any], error) {
+	keys, err := u.repo.FindKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]FindKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		items = append(items, FindKeyResponse{
+			ID:         k.ID.Hex(),
+			TenantID:   k.TenantID,
+			Name:       k.Name,
+			Prefix:     k.Prefix,
+			Scopes:     k.Scopes,
+			CreatedBy:  k.CreatedBy,
+			CreatedAt:  k.CreatedAt,
+			ExpiresAt:  k.ExpiresAt,
+			LastUsedAt: k.LastUsedAt,
+			RevokedAt:  k.RevokedAt,
+		})
+	}
+	return response.SuccessWithData(items), nil
+}
+
+// RevokeKey stops a key from being used. The key stays listed, with when it
+// was revoked.
+func (u *usecase) RevokeKey(ctx context.Context, id string) (*response.StdResp[any], error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return response.APIKeyNotFound(), nil
+	}
+	revoked, err := u.repo.RevokeKey(ctx, oid, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if revoked == 0 {
+		return response.APIKeyNotFound(), nil
+	}
+	return response.Success(), nil
+}
+
+// AuthenticateAPIKey returns the claims a request made with raw acts under:
+// an admin of the key's tenant, limited to the key's scopes. It returns nil
+// for anything that is not a key, for unknown, revoked and expired keys and
+// for keys whose tenant is gone or disabled.
+func (u *usecase) AuthenticateAPIKey(ctx context.Context, raw string) (*auth.Claims, error) {
+	if !strings.HasPrefix(raw, keyPrefix) {
+		return nil, nil
+	}
+	key, err := u.repo.FindKeyByHash(ctx, opaque.Hash(raw))
+	if err != nil {
+		if errors.Is(err, ErrKeyNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	now := time.Now()
+	if key.RevokedAt != nil || !now.Before(key.ExpiresAt) {
+		return nil, nil
+	}
+	if key.TenantID != "" {
+		t, err := u.tenants.FindTenantById(ctx, key.TenantID)
+		if err != nil {
+			if errors.Is(err, tenant.ErrTenantNotFound) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		if t.Disabled {
+			return nil, nil
+		}
+	}
+	// Record use at most once per interval, so busy keys do not write on
+	// every request.
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= u.cfg.TouchInterval {
+		if err := u.repo.TouchKey(ctx, key.ID, now); err != nil {
+			return nil, err
+		}
+	}
+	return &auth.Claims{TenantID: key.TenantID, Role: auth.RoleAdmin, Scopes: slices.Clone(key.Scopes)}, nil
+}
+
+func newKey() (string, error) {
+	key, err := opaque.New()
+	if err != nil {
+		return "", err
+	}
+	return keyPrefix + key, nil
+}
@@ -0,0 +1,167 @@
+package apikey_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"user-management/app/apikey"
+	"user-management/app/tenant"
+	"user-management/auth"
+	"user-management/config"
+	"user-management/response"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const acmeTenantID = "60d5ec49f1a2c8b1f8e4e1b2"
+
+var testCfg = config.APIKeyConfig{DefaultTTL: 24 * time.Hour, MaxTTL: 48 * time.Hour, TouchInterval: time.Minute}
+
+type fakeTenants map[string]tenant.FindTenantResponse
+
+func (f fakeTenants) FindTenantById(ctx context.Context, id string) (tenant.FindTenantResponse, error) {
+	t, ok := f[id]
+	if !ok {
+		return t, tenant.ErrTenantNotFound
+	}
+	return t, nil
+}
+
+type usecase interface {
+	apikey.Usecase
+	AuthenticateAPIKey(ctx context.Context, key string) (*auth.Claims, error)
+}
+
+func newTestUsecase(tenants fakeTenants) (usecase, apikey.Repository) {
+	repo := apikey.NewMemoryRepository()
+	return apikey.NewUsecase(testCfg, repo, tenants), repo
+}
+
+func adminContext() context.Context {
+	return auth.WithClaims(context.Background(), &auth.Claims{UserID: "admin-1", TenantID: acmeTenantID, Role: auth.RoleAdmin})
+}
+
+func createKey(t *testing.T, uc usecase, req apikey.CreateRequest) apikey.CreateResponse {
+	t.Helper()
+	resp, err := uc.CreateKey(adminContext(), req)
+	require.NoError(t, err)
+	require.True(t, resp.IsSuccess(), resp.Message)
+	return resp.Data.(apikey.CreateResponse)
+}
+
+func TestUsecaseCreateKey(t *testing.T) {
+	uc, _ := newTestUsecase(fakeTenants{acmeTenantID: {Id: acmeTenantID}})
+
+	created := createKey(t, uc, apikey.CreateRequest{Name: " Nightly sync ", TenantID: "ignored", Scopes: []string{auth.ScopeUsersRead, auth.ScopeUsersRead}})
+	assert.True(t, strings.HasPrefix(created.Key, "umk_"))
+	assert.Equal(t, created.Key[:12], created.Prefix)
+	assert.WithinDuration(t, time.Now().Add(testCfg.DefaultTTL), created.ExpiresAt, time.Minute)
+
+	resp, err := uc.FindKeys(adminContext())
+	require.NoError(t, err)
+	keys := resp.Data.([]apikey.FindKeyResponse)
+	require.Len(t, keys, 1)
+	assert.Equal(t, created.ID, keys[0].ID)
+	assert.Equal(t, "Nightly sync", keys[0].Name)
+	assert.Equal(t, created.Prefix, keys[0].Prefix)
+	assert.Equal(t, []string{auth.ScopeUsersRead}, keys[0].Scopes)
+	assert.Equal(t, acmeTenantID, keys[0].TenantID)
+	assert.Equal(t, "admin-1", keys[0].CreatedBy)
+	assert.Nil(t, keys[0].LastUsedAt)
+}
+
+func TestUsecaseCreateKey_InvalidExpiry(t *testing.T) {
+	uc, _ := newTestUsecase(fakeTenants{})
+
+	for _, expiresAt := range []time.Time{time.Now().Add(-time.Hour), time.Now().Add(testCfg.MaxTTL + time.Hour)} {
+		resp, err := uc.CreateKey(adminContext(), apikey.CreateRequest{Name: "sync", Scopes: []string{auth.ScopeUsersRead}, ExpiresAt: &expiresAt})
+		require.NoError(t, err)
+		assert.Equal(t, response.InvalidData("expires_at"), resp)
+	}
+}
+
+func TestUsecaseAuthenticateAPIKey(t *testing.T) {
+	uc, _ := newTestUsecase(fakeTenants{acmeTenantID: {Id: acmeTenantID}})
+	created := createKey(t, uc, apikey.CreateRequest{Name: "sync", Scopes: []string{auth.ScopeUsersRead}})
+
+	claims, err := uc.AuthenticateAPIKey(context.Background(), created.Key)
+	require.NoError(t, err)
+	require.NotNil(t, claims)
+	assert.Equal(t, acmeTenantID, claims.TenantID)
+	assert.Equal(t, auth.RoleAdmin, claims.Role)
+	assert.Equal(t, []string{auth.ScopeUsersRead}, claims.Scopes)
+
+	resp, err := uc.FindKeys(adminContext())
+	require.NoError(t, err)
+	assert.NotNil(t, resp.Data.([]apikey.FindKeyResponse)[0].LastUsedAt)
+
+	claims, err = uc.AuthenticateAPIKey(context.Background(), created.Key+"x")
+	assert.NoError(t, err)
+	assert.Nil(t, claims)
+
+	// A user JWT is not an API key.
+	claims, err = uc.AuthenticateAPIKey(context.Background(), "eyJhbGciOiJIUzI1NiJ9.e30.x")
+	assert.NoError(t, err)
+	assert.Nil(t, claims)
+}
+
+// expiredRepository returns every key as if it had expired a minute ago.
+type expiredRepository struct {
+	apikey.Repository
+}
+
+func (r expiredRepository) FindKeyByHash(ctx context.Context, keyHash string) (apikey.APIKey, error) {
+	key, err := r.Repository.FindKeyByHash(ctx, keyHash)
+	key.ExpiresAt = time.Now().Add(-time.Minute)
+	return key, err
+}
+
+func TestUsecaseAuthenticateAPIKey_Expired(t *testing.T) {
+	repo := expiredRepository{apikey.NewMemoryRepository()}
+	uc := apikey.NewUsecase(testCfg, repo, fakeTenants{acmeTenantID: {Id: acmeTenantID}})
+	created := createKey(t, uc, apikey.CreateRequest{Name: "sync", Scopes: []string{auth.ScopeUsersRead}})
+
+	claims, err := uc.AuthenticateAPIKey(context.Background(), created.Key)
+
+	assert.NoError(t, err)
+	assert.Nil(t, claims)
+}
+
+func TestUsecaseAuthenticateAPIKey_DisabledTenant(t *testing.T) {
+	uc, _ := newTestUsecase(fakeTenants{acmeTenantID: {Id: acmeTenantID, Disabled: true}})
+	created := createKey(t, uc, apikey.CreateRequest{Name: "sync", Scopes: []string{auth.ScopeUsersRead}})
+
+	claims, err := uc.AuthenticateAPIKey(context.Background(), created.Key)
+
+	assert.NoError(t, err)
+	assert.Nil(t, claims)
+}
+
+func TestUsecaseRevokeKey(t *testing.T) {
+	uc, _ := newTestUsecase(fakeTenants{acmeTenantID: {Id: acmeTenantID}})
+	created := createKey(t, uc, apikey.CreateRequest{Name: "sync", Scopes: []string{auth.ScopeUsersRead}})
+
+	// Admins of other tenants cannot see the key.
+	other := auth.WithClaims(context.Background(), &auth.Claims{TenantID: "60d5ec49f1a2c8b1f8e4e1b3", Role: auth.RoleAdmin})
+	resp, err := uc.RevokeKey(other, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, response.APIKeyNotFound(), resp)
+
+	resp, err = uc.RevokeKey(adminContext(), created.ID)
+	require.NoError(t, err)
+	assert.True(t, resp.IsSuccess())
+
+	resp, err = uc.RevokeKey(adminContext(), created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, response.APIKeyNotFound(), resp)
+
+	claims, err := uc.AuthenticateAPIKey(context.Background(), created.Key)
+	assert.NoError(t, err)
+	assert.Nil(t, claims)
+
+	resp, err = uc.FindKeys(adminContext())
+	require.NoError(t, err)
+	assert.NotNil(t, resp.Data.([]apikey.FindKeyResponse)[0].RevokedAt)
+}
@@ -0,0 +1,27 @@
+package avatar
+
+const (
+	ParamID   = "id"
+	ParamSize = "size"
+	// FormField is the multipart field the upload is read from.
+	FormField = "avatar"
+
+	// ContentType is the type every avatar is served as; uploads are
+	// re-encoded to it whatever format they came in.
+	ContentType = "image/jpeg"
+	jpegQuality = 85
+
+	// CacheControl lets clients and proxies keep an avatar forever: a new
+	// upload gets a new id and therefore new URLs.
+	CacheControl = "public, max-age=31536000, immutable"
+
+	// URLPrefix is where avatars are served; see URLs.
+	URLPrefix = "/avatars/"
+)
+
+// Sizes are the edge lengths, in pixels, of the square thumbnails made from
+// each upload.
+var Sizes = []int{64, 128, 256}
+
+// Accepted upload formats, as detected from the content.
+var acceptedTypes = []string{"image/jpeg", "image/png", "image/gif"}
@@ -0,0 +1,103 @@
+package avatar
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"user-management/logger"
+	"user-management/response"
+
+	echo "github.com/labstack/echo/v4"
+)
+
+type Usecase interface {
+	UploadAvatar(ctx context.Context, r io.Reader) (*response.StdResp[any], error)
+	FindAvatar(ctx context.Context, id string, size int) ([]byte, error)
+}
+
+type Handler interface {
+	UploadAvatar(c echo.Context) error
+	FindAvatar(c echo.Context) error
+}
+
+type handler struct {
+	usecase Usecase
+}
+
+func NewHandler(u Usecase) *handler {
+	return &handler{
+		usecase: u,
+	}
+}
+
+// UploadAvatar serves PUT /me/avatar. The image is read from the FormField
+// part of a multipart body and streamed to the usecase, which enforces the
+// size limit, so the request is never buffered whole.
+func (h *handler) UploadAvatar(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	mr, err := c.Request().MultipartReader()
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Read multipart error: %v", err)
+		return c.JSON(response.UnexpectedRequest().WithHTTPStatus())
+	}
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return c.JSON(response.MandatoryMissing(FormField).WithHTTPStatus())
+		}
+		if err != nil {
+			zlog.Sugar().Infof("[Handler] Read multipart error: %v", err)
+			return c.JSON(response.UnexpectedRequest().WithHTTPStatus())
+		}
+		if part.FormName() != FormField {
+			continue
+		}
+		resp, err := h.usecase.UploadAvatar(ctx, part)
+		if err != nil {
+			zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+			return c.JSON(response.InternalServerError().WithHTTPStatus())
+		}
+		return c.JSON(resp.WithHTTPStatus())
+	}
+}
+
+// FindAvatar serves GET /avatars/:id/:size. The content behind a URL never
+// changes, so responses can be cached for good and revalidated by ETag.
+func (h *handler) FindAvatar(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	id := c.Param(ParamID)
+	size, err := strconv.Atoi(c.Param(ParamSize))
+	if err != nil {
+		return c.JSON(response.AvatarNotFound().WithHTTPStatus())
+	}
+	etag := `"` + id + "-" + strconv.Itoa(size) + `"`
+	header := c.Response().Header()
+	header.Set("Cache-Control", CacheControl)
+	header.Set("ETag", etag)
+	if c.Request().Header.Get("If-None-Match") == etag {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	data, err := h.usecase.FindAvatar(ctx, id, size)
+	if err != nil {
+		header.Del("Cache-Control")
+		header.Del("ETag")
+		if errors.Is(err, ErrNotFound) {
+			return c.JSON(response.AvatarNotFound().WithHTTPStatus())
+		}
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	header.Set("X-Content-Type-Options", "nosniff")
+	return c.Blob(http.StatusOK, ContentType, data)
+}
@@ -0,0 +1,150 @@
+package avatar_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"user-management/app/avatar"
+	"user-management/logger"
+	"user-management/response"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockUsecase struct {
+	mock.Mock
+}
+
+func (m *mockUsecase) UploadAvatar(ctx context.Context, r io.Reader) (*response.StdResp[any], error) {
+	data, _ := io.ReadAll(r)
+	args := m.Called(ctx, data)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) FindAvatar(ctx context.Context, id string, size int) ([]byte, error) {
+	args := m.Called(ctx, id, size)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+func newTestContext(req *http.Request) (echo.Context, *httptest.ResponseRecorder) {
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	ctx := context.WithValue(c.Request().Context(), logger.LogContext, logger.NewZap())
+	c.SetRequest(req.WithContext(ctx))
+	return c, rec
+}
+
+// multipartRequest builds a PUT /me/avatar request with one file part.
+func multipartRequest(field string, data []byte) *http.Request {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	w.WriteField("note", "ignored")
+	part, _ := w.CreateFormFile(field, "me.png")
+	part.Write(data)
+	w.Close()
+	req := httptest.NewRequest(http.MethodPut, "/me/avatar", &body)
+	req.Header.Set(echo.HeaderContentType, w.FormDataContentType())
+	return req
+}
+
+func TestHandlerUploadAvatar(t *testing.T) {
+	c, rec := newTestContext(multipartRequest(avatar.FormField, []byte("image")))
+
+	mockUc := new(mockUsecase)
+	mockUc.On("UploadAvatar", mock.Anything, []byte("image")).Return(response.SuccessWithData(avatar.UploadResponse{Avatar: avatar.URLs("abc")}), nil)
+
+	err := avatar.NewHandler(mockUc).UploadAvatar(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "/avatars/abc/64")
+}
+
+func TestHandlerUploadAvatar_MissingFile(t *testing.T) {
+	c, rec := newTestContext(multipartRequest("picture", []byte("image")))
+
+	mockUc := new(mockUsecase)
+
+	err := avatar.NewHandler(mockUc).UploadAvatar(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), response.MandatoryMissing(avatar.FormField).Message)
+}
+
+func TestHandlerUploadAvatar_NotMultipart(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPut, "/me/avatar", strings.NewReader("{}"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c, rec := newTestContext(req)
+
+	err := avatar.NewHandler(new(mockUsecase)).UploadAvatar(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func findAvatarContext(id, size string, header http.Header) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, "/avatars/"+id+"/"+size, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	c, rec := newTestContext(req)
+	c.SetParamNames(avatar.ParamID, avatar.ParamSize)
+	c.SetParamValues(id, size)
+	return c, rec
+}
+
+func TestHandlerFindAvatar(t *testing.T) {
+	c, rec := findAvatarContext("abc", "64", nil)
+
+	mockUc := new(mockUsecase)
+	mockUc.On("FindAvatar", mock.Anything, "abc", 64).Return([]byte("jpeg"), nil)
+
+	err := avatar.NewHandler(mockUc).FindAvatar(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "jpeg", rec.Body.String())
+	assert.Equal(t, avatar.ContentType, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, avatar.CacheControl, rec.Header().Get("Cache-Control"))
+	assert.Equal(t, `"abc-64"`, rec.Header().Get("ETag"))
+}
+
+func TestHandlerFindAvatar_NotModified(t *testing.T) {
+	c, rec := findAvatarContext("abc", "64", http.Header{"If-None-Match": {`"abc-64"`}})
+
+	mockUc := new(mockUsecase)
+
+	err := avatar.NewHandler(mockUc).FindAvatar(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	mockUc.AssertNotCalled(t, "FindAvatar", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandlerFindAvatar_NotFound(t *testing.T) {
+	c, rec := findAvatarContext("abc", "64", nil)
+
+	mockUc := new(mockUsecase)
+	mockUc.On("FindAvatar", mock.Anything, "abc", 64).Return(nil, avatar.ErrNotFound)
+
+	err := avatar.NewHandler(mockUc).FindAvatar(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, rec.Header().Get("Cache-Control"))
+}
+
+func TestHandlerFindAvatar_Error(t *testing.T) {
+	c, rec := findAvatarContext("abc", "64", nil)
+
+	mockUc := new(mockUsecase)
+	mockUc.On("FindAvatar", mock.Anything, "abc", 64).Return(nil, errors.New("db down"))
+
+	err := avatar.NewHandler(mockUc).FindAvatar(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
@@ -0,0 +1,104 @@
+package avatar
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"net/http"
+	"slices"
+
+	_ "image/gif"
+	_ "image/png"
+)
+
+var (
+	ErrUnsupportedType = errors.New("unsupported image type")
+	ErrTooManyPixels   = errors.New("image has too many pixels")
+	ErrUndecodable     = errors.New("image cannot be decoded")
+)
+
+// Thumbnails decodes an uploaded image and returns it as a JPEG thumbnail for
+// each of Sizes. The type is detected from the content rather than trusted
+// from the request, and images over maxPixels are refused before decoding.
+func Thumbnails(data []byte, maxPixels int) (map[int][]byte, error) {
+	if !slices.Contains(acceptedTypes, http.DetectContentType(data)) {
+		return nil, ErrUnsupportedType
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUndecodable
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, ErrUndecodable
+	}
+	if cfg.Width > maxPixels/cfg.Height {
+		return nil, ErrTooManyPixels
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUndecodable
+	}
+	thumbs := make(map[int][]byte, len(Sizes))
+	// Scaling the largest thumbnail down is much cheaper than going back to
+	// the upload for every size.
+	for _, size := range slices.Backward(slices.Sorted(slices.Values(Sizes))) {
+		thumb := thumbnail(src, size)
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, err
+		}
+		thumbs[size] = buf.Bytes()
+		src = thumb
+	}
+	return thumbs, nil
+}
+
+// thumbnail crops the centre square of src and scales it to size x size.
+// Each target pixel averages the source pixels it covers, which keeps
+// downscaled images smooth; smaller images are scaled up the same way.
+// Transparent areas are laid over white, since JPEG has no alpha.
+func thumbnail(src image.Image, size int) *image.RGBA {
+	b := src.Bounds()
+	side := min(b.Dx(), b.Dy())
+	x0 := b.Min.X + (b.Dx()-side)/2
+	y0 := b.Min.Y + (b.Dy()-side)/2
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		sy0, sy1 := span(y, size, side)
+		for x := 0; x < size; x++ {
+			sx0, sx1 := span(x, size, side)
+			var r, g, bl, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					cr, cg, cb, ca := src.At(x0+sx, y0+sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			// Colours are premultiplied, so adding the missing coverage as
+			// white composites the pixel over a white background.
+			white := n*0xffff - a
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8((r + white) / n >> 8),
+				G: uint8((g + white) / n >> 8),
+				B: uint8((bl + white) / n >> 8),
+				A: 0xff,
+			})
+		}
+	}
+	return dst
+}
+
+// span returns the source range [from, to) that target pixel i of n covers
+// when side source pixels are mapped onto n. It is never empty.
+func span(i, n, side int) (int, int) {
+	from := i * side / n
+	to := (i + 1) * side / n
+	if to <= from {
+		to = from + 1
+	}
+	return from, to
+}
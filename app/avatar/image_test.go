@@ -0,0 +1,77 @@
+package avatar_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+	"user-management/app/avatar"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pngImage encodes a w x h PNG filled with c.
+func pngImage(t *testing.T, w, h int, c color.Color) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestThumbnails(t *testing.T) {
+	thumbs, err := avatar.Thumbnails(pngImage(t, 300, 200, color.NRGBA{R: 200, A: 0xff}), 1_000_000)
+	require.NoError(t, err)
+
+	assert.Len(t, thumbs, len(avatar.Sizes))
+	for _, size := range avatar.Sizes {
+		img, err := jpeg.Decode(bytes.NewReader(thumbs[size]))
+		require.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, size, size), img.Bounds())
+		r, g, b, _ := img.At(size/2, size/2).RGBA()
+		assert.InDelta(t, 200, r>>8, 8)
+		assert.InDelta(t, 0, g>>8, 8)
+		assert.InDelta(t, 0, b>>8, 8)
+	}
+}
+
+func TestThumbnails_TransparentBecomesWhite(t *testing.T) {
+	thumbs, err := avatar.Thumbnails(pngImage(t, 10, 10, color.NRGBA{}), 1_000_000)
+	require.NoError(t, err)
+
+	img, err := jpeg.Decode(bytes.NewReader(thumbs[avatar.Sizes[0]]))
+	require.NoError(t, err)
+	r, g, b, _ := img.At(0, 0).RGBA()
+	assert.Greater(t, r>>8, uint32(245))
+	assert.Greater(t, g>>8, uint32(245))
+	assert.Greater(t, b>>8, uint32(245))
+}
+
+func TestThumbnails_Rejected(t *testing.T) {
+	_, err := avatar.Thumbnails([]byte("<svg xmlns='http://www.w3.org/2000/svg'/>"), 1_000_000)
+	assert.ErrorIs(t, err, avatar.ErrUnsupportedType)
+
+	_, err = avatar.Thumbnails(pngImage(t, 200, 100, color.White), 10_000)
+	assert.ErrorIs(t, err, avatar.ErrTooManyPixels)
+
+	truncated := pngImage(t, 20, 20, color.White)[:8]
+	_, err = avatar.Thumbnails(truncated, 1_000_000)
+	assert.ErrorIs(t, err, avatar.ErrUndecodable)
+}
+
+func TestURLs(t *testing.T) {
+	assert.Nil(t, avatar.URLs(""))
+	assert.Equal(t, map[string]string{
+		"64":  "/avatars/abc/64",
+		"128": "/avatars/abc/128",
+		"256": "/avatars/abc/256",
+	}, avatar.URLs("abc"))
+}
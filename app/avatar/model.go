@@ -0,0 +1,29 @@
+package avatar
+
+import (
+	"strconv"
+)
+
+// UploadResponse lists the URL of each size of the new avatar, keyed by size.
+type UploadResponse struct {
+	Avatar map[string]string `json:"avatar"`
+}
+
+// URLs returns the URL of each size of avatar id, keyed by size, or nil when
+// id is empty. The URLs are relative to the HTTP server.
+func URLs(id string) map[string]string {
+	if id == "" {
+		return nil
+	}
+	urls := make(map[string]string, len(Sizes))
+	for _, size := range Sizes {
+		s := strconv.Itoa(size)
+		urls[s] = URLPrefix + id + "/" + s
+	}
+	return urls
+}
+
+// key names the blob holding size of avatar id.
+func key(id string, size int) string {
+	return "avatars/" + id + "/" + strconv.Itoa(size) + ".jpg"
+}
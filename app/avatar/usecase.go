@@ -0,0 +1,131 @@
+package avatar
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"regexp"
+	"slices"
+	"user-management/auth"
+	"user-management/blob"
+	"user-management/config"
+	"user-management/logger"
+	"user-management/response"
+)
+
+// Avatar ids are random, so that URLs cannot be guessed, and change on every
+// upload, so that cached images never go stale.
+var idPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+var ErrNotFound = errors.New("avatar not found")
+
+// UserAvatars records which avatar a user has.
+type UserAvatars interface {
+	// SetAvatar makes avatarID the avatar of user userID and returns the
+	// one it replaces, if any.
+	SetAvatar(ctx context.Context, userID, avatarID string) (string, error)
+}
+
+type usecase struct {
+	cfg   config.AvatarConfig
+	store blob.Store
+	users UserAvatars
+}
+
+func NewUsecase(cfg config.AvatarConfig, store blob.Store, users UserAvatars) *usecase {
+	return &usecase{
+		cfg:   cfg,
+		store: store,
+		users: users,
+	}
+}
+
+// UploadAvatar replaces the caller's avatar with the image read from r.
+func (u *usecase) UploadAvatar(ctx context.Context, r io.Reader) (*response.StdResp[any], error) {
+	claims, err := auth.FromContext(ctx)
+	if err != nil {
+		return response.Forbidden(), nil
+	}
+	data, err := io.ReadAll(io.LimitReader(r, u.cfg.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > u.cfg.MaxSize {
+		return response.InvalidAvatar("file is too large"), nil
+	}
+	thumbs, err := Thumbnails(data, u.cfg.MaxPixels)
+	switch {
+	case errors.Is(err, ErrUnsupportedType):
+		return response.InvalidAvatar("only JPEG, PNG and GIF images are accepted"), nil
+	case errors.Is(err, ErrTooManyPixels):
+		return response.InvalidAvatar("image is too large"), nil
+	case errors.Is(err, ErrUndecodable):
+		return response.InvalidAvatar("image cannot be decoded"), nil
+	case err != nil:
+		return nil, err
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	for _, size := range Sizes {
+		if err := u.store.Put(ctx, key(id, size), thumbs[size]); err != nil {
+			u.deleteBlobs(ctx, id)
+			return nil, err
+		}
+	}
+	previous, err := u.users.SetAvatar(ctx, claims.UserID, id)
+	if err != nil {
+		u.deleteBlobs(ctx, id)
+		return nil, err
+	}
+	if previous != "" {
+		u.deleteBlobs(ctx, previous)
+	}
+	return response.SuccessWithData(UploadResponse{Avatar: URLs(id)}), nil
+}
+
+// FindAvatar returns size of avatar id as a JPEG image, or ErrNotFound.
+func (u *usecase) FindAvatar(ctx context.Context, id string, size int) ([]byte, error) {
+	if !idPattern.MatchString(id) || !slices.Contains(Sizes, size) {
+		return nil, ErrNotFound
+	}
+	data, err := u.store.Get(ctx, key(id, size))
+	if errors.Is(err, blob.ErrNotFound) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+// DeleteAvatar removes the images of avatar id. It does not touch the user
+// the avatar belongs to.
+func (u *usecase) DeleteAvatar(ctx context.Context, id string) error {
+	var errs []error
+	for _, size := range Sizes {
+		if err := u.store.Delete(ctx, key(id, size)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deleteBlobs removes the images of avatar id during an upload. Failures
+// leave unreachable blobs behind, so they are logged rather than returned.
+func (u *usecase) deleteBlobs(ctx context.Context, id string) {
+	if err := u.DeleteAvatar(ctx, id); err != nil {
+		if zlog, logErr := logger.FromContext(ctx); logErr == nil {
+			zlog.Sugar().Warnf("[Usecase] Delete avatar %s: %v", id, err)
+		}
+	}
+}
+
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,126 @@
+package avatar_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image/color"
+	"strconv"
+	"strings"
+	"testing"
+	"user-management/app/avatar"
+	"user-management/auth"
+	"user-management/blob"
+	"user-management/config"
+	"user-management/response"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockUsers struct {
+	mock.Mock
+}
+
+func (m *mockUsers) SetAvatar(ctx context.Context, userID, avatarID string) (string, error) {
+	args := m.Called(ctx, userID, avatarID)
+	return args.String(0), args.Error(1)
+}
+
+var testConfig = config.AvatarConfig{MaxSize: 1 << 20, MaxPixels: 1_000_000}
+
+func userContext() context.Context {
+	return auth.WithClaims(context.Background(), &auth.Claims{UserID: "u1", TenantID: "t1", Role: auth.RoleUser})
+}
+
+func newTestUsecase(t *testing.T) (avatar.Usecase, blob.Store, *mockUsers) {
+	store, err := blob.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	users := new(mockUsers)
+	return avatar.NewUsecase(testConfig, store, users), store, users
+}
+
+func TestUsecaseUploadAvatar(t *testing.T) {
+	uc, store, users := newTestUsecase(t)
+	ctx := userContext()
+	previous := strings.Repeat("a", 32)
+	for _, size := range avatar.Sizes {
+		require.NoError(t, store.Put(ctx, "avatars/"+previous+"/"+strconv.Itoa(size)+".jpg", []byte("old")))
+	}
+	var id string
+	users.On("SetAvatar", mock.Anything, "u1", mock.Anything).Run(func(args mock.Arguments) {
+		id = args.String(2)
+	}).Return(previous, nil)
+
+	resp, err := uc.UploadAvatar(ctx, bytes.NewReader(pngImage(t, 40, 40, color.Black)))
+
+	require.NoError(t, err)
+	assert.Equal(t, response.SuccessWithData(avatar.UploadResponse{Avatar: avatar.URLs(id)}), resp)
+	for _, size := range avatar.Sizes {
+		data, err := uc.FindAvatar(ctx, id, size)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, data)
+		_, err = uc.FindAvatar(ctx, previous, size)
+		assert.ErrorIs(t, err, avatar.ErrNotFound, "previous avatar is deleted")
+	}
+}
+
+func TestUsecaseUploadAvatar_SetAvatarFails(t *testing.T) {
+	uc, _, users := newTestUsecase(t)
+	var id string
+	users.On("SetAvatar", mock.Anything, "u1", mock.Anything).Run(func(args mock.Arguments) {
+		id = args.String(2)
+	}).Return("", errors.New("db down"))
+
+	resp, err := uc.UploadAvatar(userContext(), bytes.NewReader(pngImage(t, 40, 40, color.Black)))
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	_, err = uc.FindAvatar(context.Background(), id, avatar.Sizes[0])
+	assert.ErrorIs(t, err, avatar.ErrNotFound, "uploaded images are cleaned up")
+}
+
+func TestUsecaseUploadAvatar_Invalid(t *testing.T) {
+	uc, _, users := newTestUsecase(t)
+
+	tests := []struct {
+		name string
+		data []byte
+		want *response.StdResp[any]
+	}{
+		{"too large", make([]byte, testConfig.MaxSize+1), response.InvalidAvatar("file is too large")},
+		{"not an image", []byte("hello"), response.InvalidAvatar("only JPEG, PNG and GIF images are accepted")},
+		{"too many pixels", pngImage(t, 2000, 1000, color.White), response.InvalidAvatar("image is too large")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := uc.UploadAvatar(userContext(), bytes.NewReader(tt.data))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, resp)
+		})
+	}
+	users.AssertNotCalled(t, "SetAvatar", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUsecaseUploadAvatar_Unauthenticated(t *testing.T) {
+	uc, _, _ := newTestUsecase(t)
+
+	resp, err := uc.UploadAvatar(context.Background(), bytes.NewReader(nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.Forbidden(), resp)
+}
+
+func TestUsecaseFindAvatar_NotFound(t *testing.T) {
+	uc, _, _ := newTestUsecase(t)
+	id := strings.Repeat("b", 32)
+
+	for _, tt := range []struct {
+		id   string
+		size int
+	}{{id, 64}, {id, 65}, {"../../etc", 64}} {
+		_, err := uc.FindAvatar(context.Background(), tt.id, tt.size)
+		assert.ErrorIs(t, err, avatar.ErrNotFound)
+	}
+}
@@ -0,0 +1,26 @@
+package federation
+
+import (
+	"errors"
+	"regexp"
+)
+
+const (
+	ParamProvider = "provider"
+)
+
+// StateCookie binds a sign-in started at the login endpoint to the browser
+// that completes it at the callback.
+const StateCookie = "federation_state"
+
+var defaultScopes = []string{"openid", "email", "profile"}
+
+// providerIDPattern keeps provider ids usable as a URL path segment.
+var providerIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+var (
+	ErrProviderNotFound = errors.New("Identity provider not found")
+	ErrStateNotFound    = errors.New("Login state not found")
+	ErrLinkNotFound     = errors.New("Identity link not found")
+	ErrLinkExists       = errors.New("Identity link already exists")
+)
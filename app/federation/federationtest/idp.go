@@ -0,0 +1,252 @@
+// Package federationtest runs a mock OpenID Connect provider on httptest, so
+// federated login can be exercised end to end without a real identity
+// provider.
+package federationtest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+	"user-management/app/federation"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	ClientID     = "test-client"
+	ClientSecret = "test-secret"
+)
+
+// Identity is the user the mock provider signs in.
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type pendingCode struct {
+	identity    Identity
+	nonce       string
+	challenge   string
+	redirectURI string
+}
+
+// IdP is a mock provider. Every authorization request is approved at once
+// for Identity, unless Deny is set. ExtraClaims are merged into the ID tokens
+// it issues, after the standard ones, to forge broken tokens.
+type IdP struct {
+	Server *httptest.Server
+	// Issuer is the provider's issuer, the URL of Server.
+	Issuer string
+
+	mu          sync.Mutex
+	identity    Identity
+	deny        bool
+	extraClaims map[string]any
+	key         *rsa.PrivateKey
+	kid         string
+	codes       map[string]pendingCode
+}
+
+// NewIdP starts a mock provider that is closed when t ends.
+func NewIdP(t testing.TB) *IdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp := &IdP{key: key, kid: "key-1", codes: map[string]pendingCode{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", idp.discovery)
+	mux.HandleFunc("/jwks", idp.jwks)
+	mux.HandleFunc("/authorize", idp.authorize)
+	mux.HandleFunc("/token", idp.token)
+	idp.Server = httptest.NewServer(mux)
+	idp.Issuer = idp.Server.URL
+	t.Cleanup(idp.Server.Close)
+	return idp
+}
+
+// Config returns a provider configuration for the mock provider with id.
+func (i *IdP) Config(id string) federation.ProviderConfig {
+	return federation.ProviderConfig{
+		ID:           id,
+		Issuer:       i.Issuer,
+		ClientID:     ClientID,
+		ClientSecret: ClientSecret,
+		Scopes:       []string{"openid", "email", "profile"},
+	}
+}
+
+func (i *IdP) SetIdentity(identity Identity) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.identity = identity
+}
+
+func (i *IdP) SetDeny(deny bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.deny = deny
+}
+
+func (i *IdP) SetExtraClaims(claims map[string]any) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.extraClaims = claims
+}
+
+// RotateKey replaces the signing key with a new one under kid.
+func (i *IdP) RotateKey(kid string) error {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return err
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.key, i.kid = key, kid
+	return nil
+}
+
+// Follow plays the browser at the provider: it requests authURL and returns
+// the callback URL the provider redirects back to.
+func (i *IdP) Follow(authURL string) (*url.URL, error) {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		return nil, fmt.Errorf("authorize answered %d", resp.StatusCode)
+	}
+	return url.Parse(resp.Header.Get("Location"))
+}
+
+// SignIDToken signs claims as the provider would, for tokens that do not go
+// through the authorization flow.
+func (i *IdP) SignIDToken(claims jwt.MapClaims) (string, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = i.kid
+	return token.SignedString(i.key)
+}
+
+func (i *IdP) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                 i.Issuer,
+		"authorization_endpoint": i.Issuer + "/authorize",
+		"token_endpoint":         i.Issuer + "/token",
+		"jwks_uri":               i.Issuer + "/jwks",
+	})
+}
+
+func (i *IdP) jwks(w http.ResponseWriter, r *http.Request) {
+	i.mu.Lock()
+	pub := i.key.PublicKey
+	kid := i.kid
+	i.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]any{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": kid,
+		"use": "sig",
+		"alg": "RS256",
+		"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}}})
+}
+
+func (i *IdP) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	redirectURI, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil || q.Get("client_id") != ClientID || q.Get("response_type") != "code" || q.Get("code_challenge_method") != "S256" {
+		http.Error(w, "bad authorization request", http.StatusBadRequest)
+		return
+	}
+	params := url.Values{"state": {q.Get("state")}}
+	i.mu.Lock()
+	if i.deny {
+		params.Set("error", "access_denied")
+	} else {
+		code := rand.Text()
+		i.codes[code] = pendingCode{
+			identity:    i.identity,
+			nonce:       q.Get("nonce"),
+			challenge:   q.Get("code_challenge"),
+			redirectURI: q.Get("redirect_uri"),
+		}
+		params.Set("code", code)
+	}
+	i.mu.Unlock()
+	redirectURI.RawQuery = params.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (i *IdP) token(w http.ResponseWriter, r *http.Request) {
+	id, secret, ok := r.BasicAuth()
+	if !ok || id != url.QueryEscape(ClientID) || secret != url.QueryEscape(ClientSecret) {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	i.mu.Lock()
+	code, ok := i.codes[r.PostForm.Get("code")]
+	delete(i.codes, r.PostForm.Get("code"))
+	extra := i.extraClaims
+	i.mu.Unlock()
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || code.redirectURI != r.PostForm.Get("redirect_uri") || base64.RawURLEncoding.EncodeToString(sum[:]) != code.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            i.Issuer,
+		"sub":            code.identity.Subject,
+		"aud":            ClientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"nonce":          code.nonce,
+		"email":          code.identity.Email,
+		"email_verified": code.identity.EmailVerified,
+		"name":           code.identity.Name,
+	}
+	for k, v := range extra {
+		claims[k] = v
+	}
+	idToken, err := i.SignIDToken(claims)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": rand.Text(),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,120 @@
+package federation
+
+import (
+	"context"
+	"net/http"
+	"user-management/app/user"
+	"user-management/logger"
+	"user-management/response"
+
+	echo "github.com/labstack/echo/v4"
+)
+
+type Usecase interface {
+	FindProviders(ctx context.Context) (*response.StdResp[any], error)
+	StartLogin(ctx context.Context, providerID string) (*response.StdResp[any], error)
+	CompleteLogin(ctx context.Context, providerID string, req CallbackRequest, boundState string) (*response.StdResp[any], error)
+}
+
+type Handler interface {
+	FindProviders(c echo.Context) error
+	Login(c echo.Context) error
+	Callback(c echo.Context) error
+}
+
+type handler struct {
+	usecase Usecase
+}
+
+func NewHandler(u Usecase) *handler {
+	return &handler{
+		usecase: u,
+	}
+}
+
+func (h *handler) FindProviders(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.FindProviders(ctx)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+// Login sends the browser to the provider, binding the sign-in to it with a
+// cookie that only the callback receives.
+func (h *handler) Login(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	providerID := c.Param(ParamProvider)
+
+	resp, err := h.usecase.StartLogin(ctx, providerID)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	if !resp.IsSuccess() {
+		return c.JSON(resp.WithHTTPStatus())
+	}
+	result := resp.Data.(StartResult)
+	c.SetCookie(&http.Cookie{
+		Name:     StateCookie,
+		Value:    result.State,
+		Path:     "/federation/" + providerID + "/callback",
+		HttpOnly: true,
+		Secure:   true,
+		// Lax, so the cookie comes along on the provider's redirect back.
+		SameSite: http.SameSiteLaxMode,
+	})
+	return c.Redirect(http.StatusFound, result.RedirectURL)
+}
+
+// Callback completes the sign-in and answers like /login, setting the token
+// cookie.
+func (h *handler) Callback(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	providerID := c.Param(ParamProvider)
+	var request CallbackRequest
+	if err := c.Bind(&request); err != nil {
+		zlog.Sugar().Infof("[Handler] Bind request error: %v", err)
+		return c.JSON(response.UnexpectedRequest().WithHTTPStatus())
+	}
+	boundState := ""
+	if cookie, err := c.Cookie(StateCookie); err == nil {
+		boundState = cookie.Value
+	}
+	// The state is single use whatever the outcome.
+	c.SetCookie(&http.Cookie{
+		Name:     StateCookie,
+		Path:     "/federation/" + providerID + "/callback",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	resp, err := h.usecase.CompleteLogin(ctx, providerID, request, boundState)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	if !resp.IsSuccess() {
+		return c.JSON(resp.WithHTTPStatus())
+	}
+
+	c.SetCookie(user.NewTokenCookie(resp.Data.(*user.SignInResponse)))
+	return c.JSON(resp.WithHTTPStatus())
+}
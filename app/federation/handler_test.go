@@ -0,0 +1,128 @@
+package federation_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"user-management/app/federation"
+	"user-management/app/user"
+	"user-management/logger"
+	"user-management/response"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockUsecase struct {
+	mock.Mock
+}
+
+func (m *mockUsecase) FindProviders(ctx context.Context) (*response.StdResp[any], error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) StartLogin(ctx context.Context, providerID string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, providerID)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) CompleteLogin(ctx context.Context, providerID string, req federation.CallbackRequest, boundState string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, providerID, req, boundState)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func newTestContext(target string, cookies ...*http.Cookie) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	ctx := context.WithValue(c.Request().Context(), logger.LogContext, logger.NewZap())
+	c.SetRequest(req.WithContext(ctx))
+	c.SetParamNames(federation.ParamProvider)
+	c.SetParamValues("acme")
+	return c, rec
+}
+
+func findCookie(rec *httptest.ResponseRecorder, name string) *http.Cookie {
+	for _, cookie := range rec.Result().Cookies() {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func TestHandlerLogin(t *testing.T) {
+	c, rec := newTestContext("/federation/acme/login")
+	mockUc := new(mockUsecase)
+	mockUc.On("StartLogin", mock.Anything, "acme").Return(response.SuccessWithData(federation.StartResult{
+		RedirectURL: "https://acme.okta.com/authorize?state=s1",
+		State:       "s1",
+	}), nil)
+
+	err := federation.NewHandler(mockUc).Login(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, "https://acme.okta.com/authorize?state=s1", rec.Header().Get(echo.HeaderLocation))
+	cookie := findCookie(rec, federation.StateCookie)
+	if assert.NotNil(t, cookie) {
+		assert.Equal(t, "s1", cookie.Value)
+		assert.Equal(t, "/federation/acme/callback", cookie.Path)
+		assert.True(t, cookie.HttpOnly)
+		assert.Equal(t, http.SameSiteLaxMode, cookie.SameSite)
+	}
+}
+
+func TestHandlerLogin_UnknownProvider(t *testing.T) {
+	c, rec := newTestContext("/federation/acme/login")
+	mockUc := new(mockUsecase)
+	mockUc.On("StartLogin", mock.Anything, "acme").Return(response.ProviderNotFound(), nil)
+
+	err := federation.NewHandler(mockUc).Login(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Nil(t, findCookie(rec, federation.StateCookie))
+}
+
+func TestHandlerCallback(t *testing.T) {
+	c, rec := newTestContext("/federation/acme/callback?code=c1&state=s1", &http.Cookie{Name: federation.StateCookie, Value: "s1"})
+	expAt := time.Now().Add(time.Hour)
+	mockUc := new(mockUsecase)
+	mockUc.On("CompleteLogin", mock.Anything, "acme", federation.CallbackRequest{Code: "c1", State: "s1"}, "s1").
+		Return(response.SuccessWithData(&user.SignInResponse{Token: "jwt", ExpiresAt: expAt}), nil)
+
+	err := federation.NewHandler(mockUc).Callback(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"token":"jwt"`)
+	if cookie := findCookie(rec, "token"); assert.NotNil(t, cookie) {
+		assert.Equal(t, "jwt", cookie.Value)
+	}
+	if cookie := findCookie(rec, federation.StateCookie); assert.NotNil(t, cookie) {
+		assert.Equal(t, -1, cookie.MaxAge)
+	}
+	mockUc.AssertExpectations(t)
+}
+
+func TestHandlerCallback_Failed(t *testing.T) {
+	c, rec := newTestContext("/federation/acme/callback?error=access_denied&state=s1")
+	mockUc := new(mockUsecase)
+	mockUc.On("CompleteLogin", mock.Anything, "acme", federation.CallbackRequest{State: "s1", Error: "access_denied"}, "").
+		Return(response.LoginFail(), nil)
+
+	err := federation.NewHandler(mockUc).Callback(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Nil(t, findCookie(rec, "token"))
+}
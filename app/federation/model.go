@@ -0,0 +1,99 @@
+package federation
+
+import (
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ProviderConfig describes an upstream OpenID Connect provider, as listed in
+// the providers file. Users signing in through it belong to the tenant with
+// the slug Tenant, or are platform users when it is empty.
+//
+// An identity is matched to a local user by its link first. Without one, a
+// user with the same verified email is linked when LinkByEmail is set, and a
+// new user with Role is created when Provision is set. Emails count as
+// verified when the ID token says so, or always when TrustEmail is set, for
+// providers that only issue verified addresses but omit the claim.
+type ProviderConfig struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Issuer       string   `json:"issuer"`
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret"`
+	Scopes       []string `json:"scopes"`
+	Tenant       string   `json:"tenant"`
+	LinkByEmail  bool     `json:"link_by_email"`
+	Provision    bool     `json:"provision"`
+	TrustEmail   bool     `json:"trust_email"`
+	Role         string   `json:"role"`
+}
+
+// IdentityLink ties the identity Subject at Provider to a local user.
+type IdentityLink struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Provider    string             `bson:"provider" json:"provider"`
+	Subject     string             `bson:"subject" json:"subject"`
+	UserID      string             `bson:"user_id" json:"user_id"`
+	TenantID    string             `bson:"tenant_id,omitempty" json:"tenant_id,omitempty"`
+	Email       string             `bson:"email,omitempty" json:"email,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	LastLoginAt *time.Time         `bson:"last_login_at,omitempty" json:"last_login_at,omitempty"`
+}
+
+// LoginState remembers a sign-in sent to a provider until it comes back to
+// the callback. Only the hash of the state parameter is stored; the nonce
+// and PKCE verifier never leave the service.
+type LoginState struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"`
+	StateHash    string             `bson:"state_hash"`
+	Provider     string             `bson:"provider"`
+	Nonce        string             `bson:"nonce"`
+	CodeVerifier string             `bson:"code_verifier"`
+	ExpiresAt    time.Time          `bson:"expires_at"`
+}
+
+type FindProviderResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// CallbackRequest is what the provider sends back to the callback. Error is
+// set instead of Code when the user did not sign in.
+type CallbackRequest struct {
+	Code             string `query:"code"`
+	State            string `query:"state"`
+	Error            string `query:"error"`
+	ErrorDescription string `query:"error_description"`
+}
+
+// StartResult tells the handler where to send the browser, and the state to
+// bind to it.
+type StartResult struct {
+	RedirectURL string
+	State       string
+}
+
+// IDTokenClaims are the claims read from an upstream ID token.
+type IDTokenClaims struct {
+	Nonce             string `json:"nonce"`
+	Email             string `json:"email"`
+	EmailVerified     any    `json:"email_verified"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+	AuthorizedParty   string `json:"azp"`
+	jwt.RegisteredClaims
+}
+
+// Verified reports whether email_verified is true. Some providers send it as
+// the string "true".
+func (c IDTokenClaims) Verified() bool {
+	switch v := c.EmailVerified.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
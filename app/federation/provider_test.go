@@ -0,0 +1,102 @@
+package federation_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+	"user-management/app/federation"
+	"user-management/app/federation/federationtest"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func idTokenClaims(idp *federationtest.IdP) jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":   idp.Issuer,
+		"sub":   "okta|alice",
+		"aud":   federationtest.ClientID,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Minute).Unix(),
+		"nonce": "n-1",
+		"email": "alice@acme.com",
+	}
+}
+
+func TestProviderVerifyIDToken(t *testing.T) {
+	idp := federationtest.NewIdP(t)
+	p := federation.NewProvider(idp.Config("acme"), "https://users.example.com/federation/acme/callback", http.DefaultClient)
+	raw, err := idp.SignIDToken(idTokenClaims(idp))
+	require.NoError(t, err)
+
+	claims, err := p.VerifyIDToken(context.Background(), raw, "n-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "okta|alice", claims.Subject)
+	assert.Equal(t, "alice@acme.com", claims.Email)
+}
+
+func TestProviderVerifyIDToken_Rejects(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		change jwt.MapClaims
+	}{
+		{name: "no subject", change: jwt.MapClaims{"sub": ""}},
+		{name: "no expiry", change: jwt.MapClaims{"exp": nil}},
+		{name: "issued in the future", change: jwt.MapClaims{"iat": time.Now().Add(time.Hour).Unix()}},
+		{name: "several audiences without azp", change: jwt.MapClaims{"aud": []string{federationtest.ClientID, "other"}}},
+		{name: "azp of another client", change: jwt.MapClaims{"aud": []string{federationtest.ClientID, "other"}, "azp": "other"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := federationtest.NewIdP(t)
+			p := federation.NewProvider(idp.Config("acme"), "https://users.example.com/federation/acme/callback", http.DefaultClient)
+			claims := idTokenClaims(idp)
+			for k, v := range tt.change {
+				if v == nil {
+					delete(claims, k)
+				} else {
+					claims[k] = v
+				}
+			}
+			raw, err := idp.SignIDToken(claims)
+			require.NoError(t, err)
+
+			_, err = p.VerifyIDToken(context.Background(), raw, "n-1")
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestProviderVerifyIDToken_KeyRotation(t *testing.T) {
+	idp := federationtest.NewIdP(t)
+	p := federation.NewProvider(idp.Config("acme"), "https://users.example.com/federation/acme/callback", http.DefaultClient)
+	// No keys fetched yet, so the rotated key is found on first use.
+	require.NoError(t, idp.RotateKey("key-2"))
+	raw, err := idp.SignIDToken(idTokenClaims(idp))
+	require.NoError(t, err)
+	_, err = p.VerifyIDToken(context.Background(), raw, "n-1")
+	require.NoError(t, err)
+
+	// A second rotation within the refresh interval is not fetched, so forged
+	// key ids cannot make every request call the provider.
+	require.NoError(t, idp.RotateKey("key-3"))
+	raw, err = idp.SignIDToken(idTokenClaims(idp))
+	require.NoError(t, err)
+	_, err = p.VerifyIDToken(context.Background(), raw, "n-1")
+	assert.ErrorContains(t, err, "unknown signing key")
+}
+
+func TestProviderAuthCodeURL_IssuerMismatch(t *testing.T) {
+	idp := federationtest.NewIdP(t)
+	pc := idp.Config("acme")
+	pc.Issuer = idp.Issuer + "/"
+	p := federation.NewProvider(pc, "https://users.example.com/federation/acme/callback", http.DefaultClient)
+
+	_, err := p.AuthCodeURL(context.Background(), "state", "nonce", "verifier")
+
+	assert.ErrorContains(t, err, "discovery document is for issuer")
+}
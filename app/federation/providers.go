@@ -0,0 +1,74 @@
+package federation
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"user-management/auth"
+	"user-management/config"
+)
+
+// LoadProviders reads the providers file in cfg. It returns no providers
+// when there is none.
+func LoadProviders(cfg config.FederationConfig) ([]*Provider, error) {
+	if strings.TrimSpace(cfg.Providers) == "" {
+		return nil, nil
+	}
+	var configs []ProviderConfig
+	if err := json.Unmarshal([]byte(cfg.Providers), &configs); err != nil {
+		return nil, fmt.Errorf("parse providers: %w", err)
+	}
+	client := &http.Client{Timeout: cfg.HTTPTimeout}
+	base := strings.TrimSuffix(cfg.BaseURL, "/")
+	var providers []*Provider
+	seen := map[string]bool{}
+	for i, pc := range configs {
+		if err := validateProvider(&pc); err != nil {
+			return nil, fmt.Errorf("provider %d: %w", i+1, err)
+		}
+		if seen[pc.ID] {
+			return nil, fmt.Errorf("provider %d: duplicate id %q", i+1, pc.ID)
+		}
+		seen[pc.ID] = true
+		providers = append(providers, NewProvider(pc, CallbackURL(base, pc.ID), client))
+	}
+	return providers, nil
+}
+
+// CallbackURL is the redirect URI to register with provider id.
+func CallbackURL(baseURL, id string) string {
+	return strings.TrimSuffix(baseURL, "/") + "/federation/" + id + "/callback"
+}
+
+// validateProvider checks pc and fills in its defaults.
+func validateProvider(pc *ProviderConfig) error {
+	if !providerIDPattern.MatchString(pc.ID) {
+		return fmt.Errorf("id %q must be lowercase letters, digits and dashes", pc.ID)
+	}
+	if u, err := url.Parse(pc.Issuer); err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return fmt.Errorf("issuer %q is not a URL", pc.Issuer)
+	}
+	if pc.ClientID == "" {
+		return fmt.Errorf("client_id is required")
+	}
+	if pc.Name == "" {
+		pc.Name = pc.ID
+	}
+	if len(pc.Scopes) == 0 {
+		pc.Scopes = defaultScopes
+	}
+	if !slices.Contains(pc.Scopes, "openid") {
+		pc.Scopes = append([]string{"openid"}, pc.Scopes...)
+	}
+	switch pc.Role {
+	case "":
+		pc.Role = auth.RoleUser
+	case auth.RoleUser, auth.RoleAdmin:
+	default:
+		return fmt.Errorf("role %q must be %s or %s", pc.Role, auth.RoleUser, auth.RoleAdmin)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package federation_test
+
+import (
+	"testing"
+	"user-management/app/federation"
+	"user-management/auth"
+	"user-management/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadProviders(t *testing.T) {
+	providers, err := federation.LoadProviders(config.FederationConfig{
+		BaseURL: "https://users.example.com/",
+		Providers: `[
+			{"id": "acme", "issuer": "https://acme.okta.com", "client_id": "c1", "client_secret": "s1", "tenant": "acme", "provision": true},
+			{"id": "globex", "name": "Globex", "issuer": "https://login.globex.com", "client_id": "c2", "scopes": ["email"], "role": "admin"}
+		]`,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, providers, 2)
+	acme := providers[0].Config()
+	assert.Equal(t, "acme", acme.Name)
+	assert.Equal(t, []string{"openid", "email", "profile"}, acme.Scopes)
+	assert.Equal(t, auth.RoleUser, acme.Role)
+	assert.True(t, acme.Provision)
+	globex := providers[1].Config()
+	assert.Equal(t, "Globex", globex.Name)
+	assert.Equal(t, []string{"openid", "email"}, globex.Scopes)
+	assert.Equal(t, auth.RoleAdmin, globex.Role)
+}
+
+func TestLoadProviders_None(t *testing.T) {
+	providers, err := federation.LoadProviders(config.FederationConfig{})
+
+	assert.NoError(t, err)
+	assert.Empty(t, providers)
+}
+
+func TestLoadProviders_Invalid(t *testing.T) {
+	for name, providers := range map[string]string{
+		"not json":      `{`,
+		"bad id":        `[{"id": "Acme Corp", "issuer": "https://acme.okta.com", "client_id": "c1"}]`,
+		"bad issuer":    `[{"id": "acme", "issuer": "acme.okta.com", "client_id": "c1"}]`,
+		"no client id":  `[{"id": "acme", "issuer": "https://acme.okta.com"}]`,
+		"unknown role":  `[{"id": "acme", "issuer": "https://acme.okta.com", "client_id": "c1", "role": "root"}]`,
+		"duplicate ids": `[{"id": "acme", "issuer": "https://a.example.com", "client_id": "c1"}, {"id": "acme", "issuer": "https://b.example.com", "client_id": "c2"}]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := federation.LoadProviders(config.FederationConfig{Providers: providers})
+
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package federation
+
+import (
+	"context"
+	"time"
+	"user-management/config"
+	"user-management/storage"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type repository struct {
+	mc  storage.DatabaseConn
+	cfg config.MongoConfig
+}
+
+func NewRepository(mc storage.DatabaseConn, cfg config.MongoConfig) *repository {
+	return &repository{
+		mc:  mc,
+		cfg: cfg,
+	}
+}
+
+func (r *repository) CreateState(ctx context.Context, state LoginState) error {
+	_, err := r.mc.Collection(r.cfg.FederationStateCollection).InsertOne(ctx, state)
+	return err
+}
+
+// ClaimState removes and returns the state with stateHash if it has not
+// expired at now, so a callback can only be completed once.
+func (r *repository) ClaimState(ctx context.Context, stateHash string, now time.Time) (LoginState, error) {
+	var state LoginState
+	filter := bson.M{"state_hash": stateHash, "expires_at": bson.M{"$gt": now}}
+	err := r.mc.Collection(r.cfg.FederationStateCollection).FindOneAndDelete(ctx, filter).Decode(&state)
+	if err == mongo.ErrNoDocuments {
+		return state, ErrStateNotFound
+	}
+	return state, err
+}
+
+func (r *repository) FindLink(ctx context.Context, provider, subject string) (IdentityLink, error) {
+	var link IdentityLink
+	err := r.mc.Collection(r.cfg.IdentityLinkCollection).FindOne(ctx, bson.M{"provider": provider, "subject": subject}).Decode(&link)
+	if err == mongo.ErrNoDocuments {
+		return link, ErrLinkNotFound
+	}
+	return link, err
+}
+
+func (r *repository) CreateLink(ctx context.Context, link IdentityLink) error {
+	link.CreatedAt = time.Now()
+	_, err := r.mc.Collection(r.cfg.IdentityLinkCollection).InsertOne(ctx, link)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrLinkExists
+	}
+	return err
+}
+
+func (r *repository) UpdateLinkLogin(ctx context.Context, provider, subject string, at time.Time) error {
+	_, err := r.mc.Collection(r.cfg.IdentityLinkCollection).UpdateOne(ctx,
+		bson.M{"provider": provider, "subject": subject},
+		bson.M{"$set": bson.M{"last_login_at": at}})
+	return err
+}
+
+// FindLinksByUser lists the identities linked to userID, oldest first.
+func (r *repository) FindLinksByUser(ctx context.Context, userID string) ([]IdentityLink, error) {
+	opts := options.Find().SetSort(bson.D{bson.E{Key: "created_at", Value: 1}})
+	cursor, err := r.mc.Collection(r.cfg.IdentityLinkCollection).Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var links []IdentityLink
+	err = cursor.All(ctx, &links)
+	return links, err
+}
+
+func (r *repository) DeleteLinksByUser(ctx context.Context, userID string) (int64, error) {
+	result, err := r.mc.Collection(r.cfg.IdentityLinkCollection).DeleteMany(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func (r *repository) DeleteLink(ctx context.Context, provider, subject string) error {
+	_, err := r.mc.Collection(r.cfg.IdentityLinkCollection).DeleteOne(ctx, bson.M{"provider": provider, "subject": subject})
+	return err
+}
@@ -0,0 +1,115 @@
+package federation
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryRepository keeps identity links and login states in process memory,
+// for when the service runs without a database.
+type memoryRepository struct {
+	mu     sync.Mutex
+	states map[string]LoginState
+	links  map[string]IdentityLink
+}
+
+func NewMemoryRepository() *memoryRepository {
+	return &memoryRepository{states: map[string]LoginState{}, links: map[string]IdentityLink{}}
+}
+
+func (r *memoryRepository) CreateState(ctx context.Context, state LoginState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.states[state.StateHash] = state
+	return nil
+}
+
+func (r *memoryRepository) ClaimState(ctx context.Context, stateHash string, now time.Time) (LoginState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	state, ok := r.states[stateHash]
+	delete(r.states, stateHash)
+	if !ok || !state.ExpiresAt.After(now) {
+		return LoginState{}, ErrStateNotFound
+	}
+	return state, nil
+}
+
+func (r *memoryRepository) FindLink(ctx context.Context, provider, subject string) (IdentityLink, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	link, ok := r.links[linkKey(provider, subject)]
+	if !ok {
+		return IdentityLink{}, ErrLinkNotFound
+	}
+	return link, nil
+}
+
+func (r *memoryRepository) CreateLink(ctx context.Context, link IdentityLink) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := linkKey(link.Provider, link.Subject)
+	if _, ok := r.links[key]; ok {
+		return ErrLinkExists
+	}
+	if link.ID.IsZero() {
+		link.ID = primitive.NewObjectID()
+	}
+	link.CreatedAt = time.Now()
+	r.links[key] = link
+	return nil
+}
+
+func (r *memoryRepository) UpdateLinkLogin(ctx context.Context, provider, subject string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := linkKey(provider, subject)
+	if link, ok := r.links[key]; ok {
+		link.LastLoginAt = &at
+		r.links[key] = link
+	}
+	return nil
+}
+
+func (r *memoryRepository) DeleteLink(ctx context.Context, provider, subject string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.links, linkKey(provider, subject))
+	return nil
+}
+
+func (r *memoryRepository) FindLinksByUser(ctx context.Context, userID string) ([]IdentityLink, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var links []IdentityLink
+	for _, link := range r.links {
+		if link.UserID == userID {
+			links = append(links, link)
+		}
+	}
+	slices.SortFunc(links, func(a, b IdentityLink) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return links, nil
+}
+
+func (r *memoryRepository) DeleteLinksByUser(ctx context.Context, userID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deleted int64
+	for key, link := range r.links {
+		if link.UserID == userID {
+			delete(r.links, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func linkKey(provider, subject string) string {
+	return provider + "\x00" + subject
+}
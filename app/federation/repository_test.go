@@ -0,0 +1,122 @@
+package federation_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-management/app/federation"
+	"user-management/config"
+	"user-management/storage"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func newTestRepository(mt *mtest.T) federation.Repository {
+	dbConn := storage.NewMongoConn(mt.Client, mt.Client.Database("testdb"))
+	return federation.NewRepository(dbConn, config.MongoConfig{
+		Database:                  "testdb",
+		IdentityLinkCollection:    "identity_links",
+		FederationStateCollection: "federation_states",
+	})
+}
+
+func TestRepository_ClaimState(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("removes unexpired state", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: bson.D{
+				{Key: "_id", Value: primitive.NewObjectID()},
+				{Key: "state_hash", Value: "h1"},
+				{Key: "provider", Value: "acme"},
+				{Key: "nonce", Value: "n1"},
+			}},
+		})
+
+		state, err := repo.ClaimState(context.Background(), "h1", time.Now())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "acme", state.Provider)
+		assert.Equal(t, "n1", state.Nonce)
+		cmd := mt.GetStartedEvent().Command
+		assert.True(t, cmd.Lookup("remove").Boolean())
+		query := cmd.Lookup("query").Document()
+		assert.Equal(t, "h1", query.Lookup("state_hash").StringValue())
+		assert.Equal(t, bson.TypeDateTime, query.Lookup("expires_at", "$gt").Type)
+	})
+
+	mt.Run("already claimed", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+
+		_, err := repo.ClaimState(context.Background(), "h1", time.Now())
+
+		assert.ErrorIs(t, err, federation.ErrStateNotFound)
+	})
+}
+
+func TestRepository_FindLink(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("found", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(1, "testdb.identity_links", mtest.FirstBatch, bson.D{
+			bson.E{Key: "_id", Value: primitive.NewObjectID()},
+			bson.E{Key: "provider", Value: "acme"},
+			bson.E{Key: "subject", Value: "okta|alice"},
+			bson.E{Key: "user_id", Value: "u1"},
+		}))
+
+		link, err := repo.FindLink(context.Background(), "acme", "okta|alice")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "u1", link.UserID)
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		assert.Equal(t, "acme", filter.Lookup("provider").StringValue())
+		assert.Equal(t, "okta|alice", filter.Lookup("subject").StringValue())
+	})
+
+	mt.Run("not found", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.identity_links", mtest.FirstBatch))
+
+		_, err := repo.FindLink(context.Background(), "acme", "okta|alice")
+
+		assert.ErrorIs(t, err, federation.ErrLinkNotFound)
+	})
+}
+
+func TestRepository_CreateLink_Duplicate(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("duplicate", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 11000, Message: "duplicate key"}))
+
+		err := repo.CreateLink(context.Background(), federation.IdentityLink{Provider: "acme", Subject: "okta|alice", UserID: "u1"})
+
+		assert.ErrorIs(t, err, federation.ErrLinkExists)
+	})
+}
+
+func TestRepository_DeleteLinksByUser(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("deletes all links of the user", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 2}})
+
+		count, err := repo.DeleteLinksByUser(context.Background(), "u1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+		deletes := mt.GetStartedEvent().Command.Lookup("deletes").Array()
+		query := deletes.Index(0).Value().Document().Lookup("q").Document()
+		assert.Equal(t, "u1", query.Lookup("user_id").StringValue())
+	})
+}
@@ -0,0 +1,298 @@
+package federation
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+	"user-management/app/tenant"
+	"user-management/app/user"
+	"user-management/auth"
+	"user-management/config"
+	"user-management/logger"
+	"user-management/opaque"
+	"user-management/response"
+)
+
+type Repository interface {
+	CreateState(ctx context.Context, state LoginState) error
+	// ClaimState removes and returns an unexpired state, so it is used once.
+	ClaimState(ctx context.Context, stateHash string, now time.Time) (LoginState, error)
+	FindLink(ctx context.Context, provider, subject string) (IdentityLink, error)
+	CreateLink(ctx context.Context, link IdentityLink) error
+	UpdateLinkLogin(ctx context.Context, provider, subject string, at time.Time) error
+	DeleteLink(ctx context.Context, provider, subject string) error
+	FindLinksByUser(ctx context.Context, userID string) ([]IdentityLink, error)
+	DeleteLinksByUser(ctx context.Context, userID string) (int64, error)
+}
+
+// Authenticator issues the service's own login tokens to users a provider
+// has authenticated.
+type Authenticator interface {
+	LoginFederated(ctx context.Context, userID, provider string) (*response.StdResp[any], error)
+}
+
+type UserRepository interface {
+	CreateUser(ctx context.Context, user user.User) (string, error)
+	FindUserByEmail(ctx context.Context, email string) (user.User, error)
+	FindUserById(ctx context.Context, id string) (user.FindUserResponse, error)
+}
+
+type TenantRepository interface {
+	FindTenantBySlug(ctx context.Context, slug string) (tenant.FindTenantResponse, error)
+}
+
+type usecase struct {
+	cfg       config.FederationConfig
+	providers []*Provider
+	repo      Repository
+	userRepo  UserRepository
+	users     Authenticator
+	tenants   TenantRepository
+}
+
+func NewUsecase(cfg config.FederationConfig, providers []*Provider, r Repository, ur UserRepository, a Authenticator, tr TenantRepository) *usecase {
+	return &usecase{
+		cfg:       cfg,
+		providers: providers,
+		repo:      r,
+		userRepo:  ur,
+		users:     a,
+		tenants:   tr,
+	}
+}
+
+// FindProviders lists the configured providers, for login pages to offer.
+func (u *usecase) FindProviders(ctx context.Context) (*response.StdResp[any], error) {
+	items := make([]FindProviderResponse, 0, len(u.providers))
+	for _, p := range u.providers {
+		items = append(items, FindProviderResponse{ID: p.cfg.ID, Name: p.cfg.Name})
+	}
+	return response.SuccessWithData(items), nil
+}
+
+// StartLogin remembers a new sign-in and returns where to send the browser
+// to sign in at the provider.
+func (u *usecase) StartLogin(ctx context.Context, providerID string) (*response.StdResp[any], error) {
+	p := u.provider(providerID)
+	if p == nil {
+		return response.ProviderNotFound(), nil
+	}
+	var state, nonce, verifier string
+	for _, s := range []*string{&state, &nonce, &verifier} {
+		var err error
+		if *s, err = opaque.New(); err != nil {
+			return nil, err
+		}
+	}
+	redirectURL, err := p.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return nil, err
+	}
+	err = u.repo.CreateState(ctx, LoginState{
+		StateHash:    opaque.Hash(state),
+		Provider:     providerID,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(u.cfg.StateTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.SuccessWithData(StartResult{RedirectURL: redirectURL, State: state}), nil
+}
+
+// CompleteLogin handles the provider's redirect back to the callback. The
+// state must match the one bound to the browser by StartLogin. The code is
+// exchanged for an ID token, whose identity is matched to a local user, who
+// is then logged in like any other. Why a login failed is logged, not told.
+func (u *usecase) CompleteLogin(ctx context.Context, providerID string, req CallbackRequest, boundState string) (*response.StdResp[any], error) {
+	p := u.provider(providerID)
+	if p == nil {
+		return response.ProviderNotFound(), nil
+	}
+	if req.Error != "" {
+		return fail(ctx, "provider %s returned %s: %s", providerID, req.Error, req.ErrorDescription)
+	}
+	if req.State == "" || subtle.ConstantTimeCompare([]byte(req.State), []byte(boundState)) != 1 {
+		return fail(ctx, "callback of provider %s does not match the browser's state", providerID)
+	}
+	state, err := u.repo.ClaimState(ctx, opaque.Hash(req.State), time.Now())
+	if err != nil {
+		if errors.Is(err, ErrStateNotFound) {
+			return fail(ctx, "login state for provider %s is unknown or expired", providerID)
+		}
+		return nil, err
+	}
+	if state.Provider != providerID {
+		return fail(ctx, "login state of provider %s used at provider %s", state.Provider, providerID)
+	}
+
+	rawIDToken, err := p.Exchange(ctx, req.Code, state.CodeVerifier)
+	if err != nil {
+		return fail(ctx, "exchange code at provider %s: %v", providerID, err)
+	}
+	claims, err := p.VerifyIDToken(ctx, rawIDToken, state.Nonce)
+	if err != nil {
+		return fail(ctx, "verify id token of provider %s: %v", providerID, err)
+	}
+
+	tenantID := ""
+	if p.cfg.Tenant != "" {
+		t, err := u.tenants.FindTenantBySlug(ctx, p.cfg.Tenant)
+		if err != nil {
+			if errors.Is(err, tenant.ErrTenantNotFound) {
+				return fail(ctx, "tenant %s of provider %s does not exist", p.cfg.Tenant, providerID)
+			}
+			return nil, err
+		}
+		if t.Disabled {
+			return fail(ctx, "tenant %s of provider %s is disabled", p.cfg.Tenant, providerID)
+		}
+		tenantID = t.Id
+	}
+	ctx = auth.WithTenant(ctx, tenantID)
+
+	userID, err := u.resolveUser(ctx, p, tenantID, claims)
+	if err != nil {
+		return nil, err
+	}
+	if userID == "" {
+		return response.LoginFail(), nil
+	}
+	resp, err := u.users.LoginFederated(ctx, userID, providerID)
+	if err != nil || !resp.IsSuccess() {
+		return resp, err
+	}
+	if err := u.repo.UpdateLinkLogin(ctx, providerID, claims.Subject, time.Now()); err != nil {
+		if zlog, logErr := logger.FromContext(ctx); logErr == nil {
+			zlog.Sugar().Warnf("[Usecase] Update login of identity %s at %s: %v", claims.Subject, providerID, err)
+		}
+	}
+	return resp, nil
+}
+
+// resolveUser finds the local user for the identity in claims, linking or
+// provisioning one as the provider allows. It returns an empty id, having
+// logged why, when the identity cannot be used to log in.
+func (u *usecase) resolveUser(ctx context.Context, p *Provider, tenantID string, claims IDTokenClaims) (string, error) {
+	providerID := p.cfg.ID
+	link, err := u.repo.FindLink(ctx, providerID, claims.Subject)
+	switch {
+	case err == nil:
+		_, err := u.userRepo.FindUserById(ctx, link.UserID)
+		if err == nil && link.TenantID == tenantID {
+			return link.UserID, nil
+		}
+		if err != nil && !errors.Is(err, user.ErrUserNotFound) {
+			return "", err
+		}
+		// The user was deleted or the provider moved to another tenant;
+		// the identity is matched afresh.
+		if err := u.repo.DeleteLink(ctx, providerID, claims.Subject); err != nil {
+			return "", err
+		}
+	case !errors.Is(err, ErrLinkNotFound):
+		return "", err
+	}
+
+	email := strings.TrimSpace(claims.Email)
+	if email == "" || !(claims.Verified() || p.cfg.TrustEmail) {
+		logFailure(ctx, "identity %s at %s has no linked user and no verified email", claims.Subject, providerID)
+		return "", nil
+	}
+	existing, err := u.userRepo.FindUserByEmail(ctx, email)
+	switch {
+	case err == nil:
+		if !p.cfg.LinkByEmail {
+			logFailure(ctx, "identity %s at %s matches user %s but linking by email is off", claims.Subject, providerID, existing.ID.Hex())
+			return "", nil
+		}
+		return u.link(ctx, providerID, tenantID, claims, existing.ID.Hex())
+	case !errors.Is(err, user.ErrUserOrPasswordIsWrong):
+		return "", err
+	}
+
+	if !p.cfg.Provision {
+		logFailure(ctx, "identity %s at %s has no user and provisioning is off", claims.Subject, providerID)
+		return "", nil
+	}
+	// Provisioned users have no password; they can only log in through a
+	// provider until an admin resets one.
+	userID, err := u.userRepo.CreateUser(ctx, user.User{
+		TenantID: tenantID,
+		Name:     displayName(claims),
+		Email:    email,
+		Role:     p.cfg.Role,
+		Status:   user.StatusActive,
+	})
+	if err != nil {
+		if errors.Is(err, user.ErrEmailAlreadyExists) {
+			logFailure(ctx, "identity %s at %s: %s was registered meanwhile", claims.Subject, providerID, email)
+			return "", nil
+		}
+		return "", err
+	}
+	return u.link(ctx, providerID, tenantID, claims, userID)
+}
+
+// link records that the identity in claims is userID. When a concurrent
+// callback linked it first, that link wins.
+func (u *usecase) link(ctx context.Context, providerID, tenantID string, claims IDTokenClaims, userID string) (string, error) {
+	err := u.repo.CreateLink(ctx, IdentityLink{
+		Provider: providerID,
+		Subject:  claims.Subject,
+		UserID:   userID,
+		TenantID: tenantID,
+		Email:    claims.Email,
+	})
+	if errors.Is(err, ErrLinkExists) {
+		existing, err := u.repo.FindLink(ctx, providerID, claims.Subject)
+		return existing.UserID, err
+	}
+	if err != nil {
+		return "", err
+	}
+	return userID, nil
+}
+
+func (u *usecase) provider(id string) *Provider {
+	for _, p := range u.providers {
+		if p.cfg.ID == id {
+			return p
+		}
+	}
+	return nil
+}
+
+func fail(ctx context.Context, format string, args ...any) (*response.StdResp[any], error) {
+	logFailure(ctx, format, args...)
+	return response.LoginFail(), nil
+}
+
+func logFailure(ctx context.Context, format string, args ...any) {
+	if zlog, err := logger.FromContext(ctx); err == nil {
+		zlog.Sugar().Infof("[Usecase] Federated login failed: "+format, args...)
+	}
+}
+
+func displayName(claims IDTokenClaims) string {
+	for _, name := range []string{claims.Name, claims.PreferredUsername} {
+		if name = strings.TrimSpace(name); name != "" {
+			return name
+		}
+	}
+	email := strings.TrimSpace(claims.Email)
+	if at := strings.LastIndex(email, "@"); at > 0 {
+		return email[:at]
+	}
+	return email
+}
+
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,304 @@
+package federation_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+	"user-management/app/federation"
+	"user-management/app/federation/federationtest"
+	"user-management/app/tenant"
+	"user-management/app/user"
+	"user-management/auth"
+	"user-management/config"
+	"user-management/response"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const acmeTenantID = "60d5ec49f1a2c8b1f8e4e1b2"
+
+var testCfg = config.FederationConfig{BaseURL: "https://users.example.com", StateTTL: time.Minute}
+
+// fakeLogins logs users in like user.Usecase.LoginFederated.
+type fakeLogins struct {
+	users   user.Repository
+	logins  []string
+	tenants []string
+}
+
+func (f *fakeLogins) LoginFederated(ctx context.Context, userID, provider string) (*response.StdResp[any], error) {
+	if _, err := f.users.FindUserById(ctx, userID); err != nil {
+		return response.LoginFail(), nil
+	}
+	tenantID, _ := auth.TenantFromContext(ctx)
+	f.logins = append(f.logins, userID)
+	f.tenants = append(f.tenants, tenantID)
+	return response.SuccessWithData(&user.SignInResponse{Token: "token-" + userID}), nil
+}
+
+type fakeTenants map[string]tenant.FindTenantResponse
+
+func (f fakeTenants) FindTenantBySlug(ctx context.Context, slug string) (tenant.FindTenantResponse, error) {
+	t, ok := f[slug]
+	if !ok {
+		return t, tenant.ErrTenantNotFound
+	}
+	return t, nil
+}
+
+type fixture struct {
+	idp     *federationtest.IdP
+	uc      federation.Usecase
+	repo    federation.Repository
+	users   user.Repository
+	logins  *fakeLogins
+	tenants fakeTenants
+}
+
+// newFixture wires a usecase to a mock provider "acme" configured by
+// configure.
+func newFixture(t *testing.T, configure func(*federation.ProviderConfig)) *fixture {
+	t.Helper()
+	idp := federationtest.NewIdP(t)
+	pc := idp.Config("acme")
+	pc.Tenant = "acme"
+	pc.Role = auth.RoleUser
+	if configure != nil {
+		configure(&pc)
+	}
+	f := &fixture{
+		idp:     idp,
+		repo:    federation.NewMemoryRepository(),
+		users:   user.NewMemoryRepository(),
+		tenants: fakeTenants{"acme": {Id: acmeTenantID, Slug: "acme"}},
+	}
+	f.logins = &fakeLogins{users: f.users}
+	provider := federation.NewProvider(pc, federation.CallbackURL(testCfg.BaseURL, "acme"), http.DefaultClient)
+	f.uc = federation.NewUsecase(testCfg, []*federation.Provider{provider}, f.repo, f.users, f.logins, f.tenants)
+	return f
+}
+
+// login runs the whole flow for the provider's current identity.
+func (f *fixture) login(t *testing.T) *response.StdResp[any] {
+	t.Helper()
+	resp, err := f.uc.StartLogin(context.Background(), "acme")
+	require.NoError(t, err)
+	require.True(t, resp.IsSuccess())
+	start := resp.Data.(federation.StartResult)
+	callback, err := f.idp.Follow(start.RedirectURL)
+	require.NoError(t, err)
+
+	resp, err = f.uc.CompleteLogin(context.Background(), "acme", callbackRequest(callback), start.State)
+	require.NoError(t, err)
+	return resp
+}
+
+func callbackRequest(u *url.URL) federation.CallbackRequest {
+	q := u.Query()
+	return federation.CallbackRequest{Code: q.Get("code"), State: q.Get("state"), Error: q.Get("error")}
+}
+
+var alice = federationtest.Identity{Subject: "okta|alice", Email: "alice@acme.com", EmailVerified: true, Name: "Alice"}
+
+func TestUsecaseStartLogin(t *testing.T) {
+	f := newFixture(t, nil)
+
+	resp, err := f.uc.StartLogin(context.Background(), "acme")
+
+	require.NoError(t, err)
+	start := resp.Data.(federation.StartResult)
+	u, err := url.Parse(start.RedirectURL)
+	require.NoError(t, err)
+	q := u.Query()
+	assert.Equal(t, f.idp.Issuer+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+	assert.Equal(t, federationtest.ClientID, q.Get("client_id"))
+	assert.Equal(t, "https://users.example.com/federation/acme/callback", q.Get("redirect_uri"))
+	assert.Equal(t, "openid email profile", q.Get("scope"))
+	assert.Equal(t, start.State, q.Get("state"))
+	assert.Equal(t, "S256", q.Get("code_challenge_method"))
+	assert.NotEmpty(t, q.Get("code_challenge"))
+	assert.NotEmpty(t, q.Get("nonce"))
+}
+
+func TestUsecaseStartLogin_UnknownProvider(t *testing.T) {
+	f := newFixture(t, nil)
+
+	resp, err := f.uc.StartLogin(context.Background(), "other")
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.ProviderNotFound(), resp)
+}
+
+func TestUsecaseCompleteLogin_ProvisionsAndLinks(t *testing.T) {
+	f := newFixture(t, func(pc *federation.ProviderConfig) { pc.Provision = true })
+	f.idp.SetIdentity(alice)
+
+	resp := f.login(t)
+
+	require.True(t, resp.IsSuccess())
+	require.Len(t, f.logins.logins, 1)
+	userID := f.logins.logins[0]
+	assert.Equal(t, []string{acmeTenantID}, f.logins.tenants)
+	found, err := f.users.FindUserById(context.Background(), userID)
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", found.Name)
+	assert.Equal(t, "alice@acme.com", found.Email)
+	assert.Equal(t, acmeTenantID, found.TenantID)
+	assert.Equal(t, auth.RoleUser, found.Role)
+	link, err := f.repo.FindLink(context.Background(), "acme", alice.Subject)
+	require.NoError(t, err)
+	assert.Equal(t, userID, link.UserID)
+	assert.NotNil(t, link.LastLoginAt)
+
+	// The second login follows the link, even with a new email.
+	f.idp.SetIdentity(federationtest.Identity{Subject: alice.Subject, Email: "alice@new.acme.com", EmailVerified: true})
+	resp = f.login(t)
+
+	require.True(t, resp.IsSuccess())
+	assert.Equal(t, []string{userID, userID}, f.logins.logins)
+}
+
+func TestUsecaseCompleteLogin_LinkByEmail(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		linkByEmail bool
+		trustEmail  bool
+		verified    bool
+		linked      bool
+	}{
+		{name: "verified email links", linkByEmail: true, verified: true, linked: true},
+		{name: "trusted email links", linkByEmail: true, trustEmail: true, linked: true},
+		{name: "unverified email does not link", linkByEmail: true},
+		{name: "linking off", verified: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t, func(pc *federation.ProviderConfig) {
+				pc.LinkByEmail = tt.linkByEmail
+				pc.TrustEmail = tt.trustEmail
+				pc.Provision = true
+			})
+			ctx := auth.WithTenant(context.Background(), acmeTenantID)
+			existing, err := f.users.CreateUser(ctx, user.User{TenantID: acmeTenantID, Name: "Alice", Email: "Alice@acme.com", Password: "hash"})
+			require.NoError(t, err)
+			f.idp.SetIdentity(federationtest.Identity{Subject: alice.Subject, Email: alice.Email, EmailVerified: tt.verified})
+
+			resp := f.login(t)
+
+			if !tt.linked {
+				assert.Equal(t, response.LoginFail(), resp)
+				assert.Empty(t, f.logins.logins)
+				_, err := f.repo.FindLink(ctx, "acme", alice.Subject)
+				assert.ErrorIs(t, err, federation.ErrLinkNotFound)
+				return
+			}
+			require.True(t, resp.IsSuccess())
+			assert.Equal(t, []string{existing}, f.logins.logins)
+		})
+	}
+}
+
+func TestUsecaseCompleteLogin_NoProvisioning(t *testing.T) {
+	f := newFixture(t, nil)
+	f.idp.SetIdentity(alice)
+
+	resp := f.login(t)
+
+	assert.Equal(t, response.LoginFail(), resp)
+	assert.Empty(t, f.logins.logins)
+}
+
+func TestUsecaseCompleteLogin_StaleLinkIsReplaced(t *testing.T) {
+	f := newFixture(t, func(pc *federation.ProviderConfig) { pc.Provision = true })
+	f.idp.SetIdentity(alice)
+	require.True(t, f.login(t).IsSuccess())
+	_, err := f.users.DeleteUser(context.Background(), f.logins.logins[0])
+	require.NoError(t, err)
+
+	resp := f.login(t)
+
+	require.True(t, resp.IsSuccess())
+	require.Len(t, f.logins.logins, 2)
+	assert.NotEqual(t, f.logins.logins[0], f.logins.logins[1])
+	link, err := f.repo.FindLink(context.Background(), "acme", alice.Subject)
+	require.NoError(t, err)
+	assert.Equal(t, f.logins.logins[1], link.UserID)
+}
+
+func TestUsecaseCompleteLogin_Rejected(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		setup func(f *fixture)
+		// tamper changes the callback before it is completed.
+		tamper func(req *federation.CallbackRequest, boundState *string)
+	}{
+		{name: "provider denied", setup: func(f *fixture) { f.idp.SetDeny(true) }},
+		{name: "state not bound to browser", tamper: func(req *federation.CallbackRequest, boundState *string) { *boundState = "" }},
+		{name: "state mismatch", tamper: func(req *federation.CallbackRequest, boundState *string) {
+			req.State = "forged"
+			*boundState = "forged"
+		}},
+		{name: "wrong audience", setup: func(f *fixture) { f.idp.SetExtraClaims(map[string]any{"aud": "other-client"}) }},
+		{name: "wrong issuer", setup: func(f *fixture) { f.idp.SetExtraClaims(map[string]any{"iss": "https://evil.example.com"}) }},
+		{name: "wrong nonce", setup: func(f *fixture) { f.idp.SetExtraClaims(map[string]any{"nonce": "replayed"}) }},
+		{name: "expired", setup: func(f *fixture) {
+			f.idp.SetExtraClaims(map[string]any{"exp": time.Now().Add(-time.Hour).Unix()})
+		}},
+		{name: "disabled tenant", setup: func(f *fixture) {
+			f.tenants["acme"] = tenant.FindTenantResponse{Id: acmeTenantID, Slug: "acme", Disabled: true}
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t, func(pc *federation.ProviderConfig) { pc.Provision = true })
+			f.idp.SetIdentity(alice)
+			if tt.setup != nil {
+				tt.setup(f)
+			}
+			resp, err := f.uc.StartLogin(context.Background(), "acme")
+			require.NoError(t, err)
+			start := resp.Data.(federation.StartResult)
+			callback, err := f.idp.Follow(start.RedirectURL)
+			require.NoError(t, err)
+			req, boundState := callbackRequest(callback), start.State
+			if tt.tamper != nil {
+				tt.tamper(&req, &boundState)
+			}
+
+			resp, err = f.uc.CompleteLogin(context.Background(), "acme", req, boundState)
+
+			require.NoError(t, err)
+			assert.Equal(t, response.LoginFail(), resp)
+			assert.Empty(t, f.logins.logins)
+		})
+	}
+}
+
+func TestUsecaseCompleteLogin_CallbackIsSingleUse(t *testing.T) {
+	f := newFixture(t, func(pc *federation.ProviderConfig) { pc.Provision = true })
+	f.idp.SetIdentity(alice)
+	resp, err := f.uc.StartLogin(context.Background(), "acme")
+	require.NoError(t, err)
+	start := resp.Data.(federation.StartResult)
+	callback, err := f.idp.Follow(start.RedirectURL)
+	require.NoError(t, err)
+	resp, err = f.uc.CompleteLogin(context.Background(), "acme", callbackRequest(callback), start.State)
+	require.NoError(t, err)
+	require.True(t, resp.IsSuccess())
+
+	resp, err = f.uc.CompleteLogin(context.Background(), "acme", callbackRequest(callback), start.State)
+
+	require.NoError(t, err)
+	assert.Equal(t, response.LoginFail(), resp)
+	assert.Len(t, f.logins.logins, 1)
+}
+
+func TestUsecaseFindProviders(t *testing.T) {
+	f := newFixture(t, func(pc *federation.ProviderConfig) { pc.Name = "Acme SSO" })
+
+	resp, err := f.uc.FindProviders(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, []federation.FindProviderResponse{{ID: "acme", Name: "Acme SSO"}}, resp.Data)
+}
@@ -0,0 +1,14 @@
+package group
+
+import "errors"
+
+const (
+	ParamID     = "id"
+	ParamUserID = "userId"
+)
+
+var (
+	ErrGroupAlreadyExists  = errors.New("Group name already exists")
+	ErrGroupNotFound       = errors.New("Group not found")
+	ErrMemberAlreadyExists = errors.New("User is already a member")
+)
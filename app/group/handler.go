@@ -0,0 +1,262 @@
+package group
+
+import (
+	"context"
+	"user-management/logger"
+	"user-management/pagination"
+	"user-management/response"
+
+	echo "github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Usecase interface {
+	CreateGroup(ctx context.Context, req CreateRequest) (*response.StdResp[any], error)
+	FindGroups(ctx context.Context, page pagination.Request) (*response.StdResp[any], error)
+	FindGroupById(ctx context.Context, id string) (*response.StdResp[any], error)
+	UpdateGroup(ctx context.Context, id primitive.ObjectID, req UpdateRequest) (*response.StdResp[any], error)
+	DeleteGroup(ctx context.Context, id string) (*response.StdResp[any], error)
+	AddMember(ctx context.Context, groupID, userID primitive.ObjectID) (*response.StdResp[any], error)
+	RemoveMember(ctx context.Context, groupID, userID primitive.ObjectID) (*response.StdResp[any], error)
+	FindMembers(ctx context.Context, groupID primitive.ObjectID, page pagination.Request) (*response.StdResp[any], error)
+	FindUserGroups(ctx context.Context, userID primitive.ObjectID, page pagination.Request) (*response.StdResp[any], error)
+}
+
+type Handler interface {
+	CreateGroup(c echo.Context) error
+	FindGroups(c echo.Context) error
+	FindGroupById(c echo.Context) error
+	UpdateGroup(c echo.Context) error
+	DeleteGroup(c echo.Context) error
+	AddMember(c echo.Context) error
+	RemoveMember(c echo.Context) error
+	FindMembers(c echo.Context) error
+	FindUserGroups(c echo.Context) error
+}
+
+type handler struct {
+	usecase Usecase
+}
+
+func NewHandler(u Usecase) *handler {
+	return &handler{
+		usecase: u,
+	}
+}
+
+func (h *handler) CreateGroup(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	var request CreateRequest
+	err = c.Bind(&request)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Bind request error: %v", err.Error())
+		return c.JSON(response.UnexpectedRequest().WithHTTPStatus())
+	}
+
+	if resp := request.RequestValidation(); !resp.IsSuccess() {
+		return c.JSON(resp.WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.CreateGroup(ctx, request)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+func (h *handler) FindGroups(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	page, respValidate := pagination.Parse(c.QueryParam(pagination.QueryPage), c.QueryParam(pagination.QueryLimit))
+	if !respValidate.IsSuccess() {
+		return c.JSON(respValidate.WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.FindGroups(ctx, page)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+func (h *handler) FindGroupById(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	paramId := c.Param(ParamID)
+	if respValidate := IdValidation(paramId, ParamID); !respValidate.IsSuccess() {
+		return c.JSON(respValidate.WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.FindGroupById(ctx, paramId)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+func (h *handler) UpdateGroup(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	var request UpdateRequest
+	groupID, err := primitive.ObjectIDFromHex(c.Param(ParamID))
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Param id parsing error: %v", err.Error())
+		return c.JSON(response.InvalidData(ParamID).WithHTTPStatus())
+	}
+	err = c.Bind(&request)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Bind request error: %v", err)
+		return c.JSON(response.UnexpectedRequest().WithHTTPStatus())
+	}
+
+	if resp := request.RequestValidation(); !resp.IsSuccess() {
+		return c.JSON(resp.WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.UpdateGroup(ctx, groupID, request)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+func (h *handler) DeleteGroup(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	paramId := c.Param(ParamID)
+	if respValidate := IdValidation(paramId, ParamID); !respValidate.IsSuccess() {
+		return c.JSON(respValidate.WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.DeleteGroup(ctx, paramId)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+func (h *handler) AddMember(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	var request AddMemberRequest
+	groupID, err := primitive.ObjectIDFromHex(c.Param(ParamID))
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Param id parsing error: %v", err.Error())
+		return c.JSON(response.InvalidData(ParamID).WithHTTPStatus())
+	}
+	err = c.Bind(&request)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Bind request error: %v", err)
+		return c.JSON(response.UnexpectedRequest().WithHTTPStatus())
+	}
+
+	if resp := request.RequestValidation(); !resp.IsSuccess() {
+		return c.JSON(resp.WithHTTPStatus())
+	}
+	userID, _ := primitive.ObjectIDFromHex(request.UserID)
+
+	resp, err := h.usecase.AddMember(ctx, groupID, userID)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+func (h *handler) RemoveMember(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	groupID, err := primitive.ObjectIDFromHex(c.Param(ParamID))
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Param id parsing error: %v", err.Error())
+		return c.JSON(response.InvalidData(ParamID).WithHTTPStatus())
+	}
+	userID, err := primitive.ObjectIDFromHex(c.Param(ParamUserID))
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Param userId parsing error: %v", err.Error())
+		return c.JSON(response.InvalidData(ParamUserID).WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.RemoveMember(ctx, groupID, userID)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+func (h *handler) FindMembers(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	groupID, err := primitive.ObjectIDFromHex(c.Param(ParamID))
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Param id parsing error: %v", err.Error())
+		return c.JSON(response.InvalidData(ParamID).WithHTTPStatus())
+	}
+	page, respValidate := pagination.Parse(c.QueryParam(pagination.QueryPage), c.QueryParam(pagination.QueryLimit))
+	if !respValidate.IsSuccess() {
+		return c.JSON(respValidate.WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.FindMembers(ctx, groupID, page)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+// FindUserGroups serves GET /users/:id/groups, so the user id comes from ParamID.
+func (h *handler) FindUserGroups(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	userID, err := primitive.ObjectIDFromHex(c.Param(ParamID))
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Param id parsing error: %v", err.Error())
+		return c.JSON(response.InvalidData(ParamID).WithHTTPStatus())
+	}
+	page, respValidate := pagination.Parse(c.QueryParam(pagination.QueryPage), c.QueryParam(pagination.QueryLimit))
+	if !respValidate.IsSuccess() {
+		return c.JSON(respValidate.WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.FindUserGroups(ctx, userID, page)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
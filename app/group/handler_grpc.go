@@ -0,0 +1,234 @@
+package group
+
+import (
+	"context"
+	"time"
+	groupgrpc "user-management/app/group/grpc/gen/go/group/v1"
+	"user-management/pagination"
+	"user-management/response"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type GrpcHandler struct {
+	usecase Usecase
+}
+
+func NewGrpcHandler(u Usecase) *GrpcHandler {
+	return &GrpcHandler{usecase: u}
+}
+
+func (h *GrpcHandler) CreateGroup(ctx context.Context, req *groupgrpc.CreateGroupRequest) (*groupgrpc.CreateGroupResponse, error) {
+	request := CreateRequest{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	if resp := request.RequestValidation(); !resp.IsSuccess() {
+		return &groupgrpc.CreateGroupResponse{Code: resp.Code, Message: resp.Message}, nil
+	}
+
+	resp, err := h.usecase.CreateGroup(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccess() {
+		return &groupgrpc.CreateGroupResponse{Code: resp.Code, Message: resp.Message}, nil
+	}
+	return &groupgrpc.CreateGroupResponse{
+		Code:    resp.Code,
+		Message: resp.Message,
+		Data: &groupgrpc.CreateGroupResponse_Data{
+			Id: resp.Data.(CreateResponse).Id,
+		},
+	}, nil
+}
+
+func (h *GrpcHandler) GetGroup(ctx context.Context, req *groupgrpc.GetGroupRequest) (*groupgrpc.GetGroupResponse, error) {
+	if resp := IdValidation(req.Id, ParamID); !resp.IsSuccess() {
+		return &groupgrpc.GetGroupResponse{Code: resp.Code, Message: resp.Message}, nil
+	}
+
+	resp, err := h.usecase.FindGroupById(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccess() {
+		return &groupgrpc.GetGroupResponse{Code: resp.Code, Message: resp.Message}, nil
+	}
+	return &groupgrpc.GetGroupResponse{
+		Code:    resp.Code,
+		Message: resp.Message,
+		Data:    toGrpcGroup(resp.Data.(FindGroupResponse)),
+	}, nil
+}
+
+func (h *GrpcHandler) ListGroups(ctx context.Context, req *groupgrpc.ListGroupsRequest) (*groupgrpc.ListGroupsResponse, error) {
+	resp, err := h.usecase.FindGroups(ctx, toPageRequest(req.Page))
+	if err != nil {
+		return nil, err
+	}
+	return toGrpcGroupList(resp), nil
+}
+
+func (h *GrpcHandler) UpdateGroup(ctx context.Context, req *groupgrpc.UpdateGroupRequest) (*groupgrpc.StatusResponse, error) {
+	groupID, err := primitive.ObjectIDFromHex(req.Id)
+	if err != nil {
+		return toGrpcStatus(response.InvalidData(ParamID)), nil
+	}
+	request := UpdateRequest{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	if resp := request.RequestValidation(); !resp.IsSuccess() {
+		return toGrpcStatus(resp), nil
+	}
+
+	resp, err := h.usecase.UpdateGroup(ctx, groupID, request)
+	if err != nil {
+		return nil, err
+	}
+	return toGrpcStatus(resp), nil
+}
+
+func (h *GrpcHandler) DeleteGroup(ctx context.Context, req *groupgrpc.DeleteGroupRequest) (*groupgrpc.StatusResponse, error) {
+	if resp := IdValidation(req.Id, ParamID); !resp.IsSuccess() {
+		return toGrpcStatus(resp), nil
+	}
+
+	resp, err := h.usecase.DeleteGroup(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return toGrpcStatus(resp), nil
+}
+
+func (h *GrpcHandler) AddMember(ctx context.Context, req *groupgrpc.MemberRequest) (*groupgrpc.StatusResponse, error) {
+	groupID, userID, resp := parseMemberRequest(req)
+	if !resp.IsSuccess() {
+		return toGrpcStatus(resp), nil
+	}
+
+	resp, err := h.usecase.AddMember(ctx, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toGrpcStatus(resp), nil
+}
+
+func (h *GrpcHandler) RemoveMember(ctx context.Context, req *groupgrpc.MemberRequest) (*groupgrpc.StatusResponse, error) {
+	groupID, userID, resp := parseMemberRequest(req)
+	if !resp.IsSuccess() {
+		return toGrpcStatus(resp), nil
+	}
+
+	resp, err := h.usecase.RemoveMember(ctx, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toGrpcStatus(resp), nil
+}
+
+func (h *GrpcHandler) ListMembers(ctx context.Context, req *groupgrpc.ListMembersRequest) (*groupgrpc.ListMembersResponse, error) {
+	groupID, err := primitive.ObjectIDFromHex(req.GroupId)
+	if err != nil {
+		resp := response.InvalidData("group_id")
+		return &groupgrpc.ListMembersResponse{Code: resp.Code, Message: resp.Message}, nil
+	}
+
+	resp, err := h.usecase.FindMembers(ctx, groupID, toPageRequest(req.Page))
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccess() {
+		return &groupgrpc.ListMembersResponse{Code: resp.Code, Message: resp.Message}, nil
+	}
+
+	result := resp.Data.(pagination.Result[MemberResponse])
+	items := make([]*groupgrpc.Member, 0, len(result.Items))
+	for _, m := range result.Items {
+		items = append(items, &groupgrpc.Member{
+			UserId:  m.UserID,
+			Name:    m.Name,
+			Email:   m.Email,
+			AddedAt: m.AddedAt.Format(time.RFC3339),
+		})
+	}
+	return &groupgrpc.ListMembersResponse{
+		Code:    resp.Code,
+		Message: resp.Message,
+		Data: &groupgrpc.ListMembersResponse_Data{
+			Items: items,
+			Page:  result.Page,
+			Limit: result.Limit,
+			Total: result.Total,
+		},
+	}, nil
+}
+
+func (h *GrpcHandler) ListUserGroups(ctx context.Context, req *groupgrpc.ListUserGroupsRequest) (*groupgrpc.ListGroupsResponse, error) {
+	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		resp := response.InvalidData("user_id")
+		return &groupgrpc.ListGroupsResponse{Code: resp.Code, Message: resp.Message}, nil
+	}
+
+	resp, err := h.usecase.FindUserGroups(ctx, userID, toPageRequest(req.Page))
+	if err != nil {
+		return nil, err
+	}
+	return toGrpcGroupList(resp), nil
+}
+
+func parseMemberRequest(req *groupgrpc.MemberRequest) (primitive.ObjectID, primitive.ObjectID, *response.StdResp[any]) {
+	groupID, err := primitive.ObjectIDFromHex(req.GroupId)
+	if err != nil {
+		return groupID, primitive.NilObjectID, response.InvalidData("group_id")
+	}
+	userID, err := primitive.ObjectIDFromHex(req.UserId)
+	if err != nil {
+		return groupID, userID, response.InvalidData("user_id")
+	}
+	return groupID, userID, response.Success()
+}
+
+func toPageRequest(p *groupgrpc.PageRequest) pagination.Request {
+	return pagination.Request{Page: p.GetPage(), Limit: p.GetLimit()}.Normalize()
+}
+
+func toGrpcStatus(resp *response.StdResp[any]) *groupgrpc.StatusResponse {
+	return &groupgrpc.StatusResponse{
+		Code:    resp.Code,
+		Message: resp.Message,
+	}
+}
+
+func toGrpcGroup(g FindGroupResponse) *groupgrpc.Group {
+	return &groupgrpc.Group{
+		Id:          g.Id,
+		TenantId:    g.TenantID,
+		Name:        g.Name,
+		Description: g.Description,
+		CreatedAt:   g.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func toGrpcGroupList(resp *response.StdResp[any]) *groupgrpc.ListGroupsResponse {
+	if !resp.IsSuccess() {
+		return &groupgrpc.ListGroupsResponse{Code: resp.Code, Message: resp.Message}
+	}
+	result := resp.Data.(pagination.Result[FindGroupResponse])
+	items := make([]*groupgrpc.Group, 0, len(result.Items))
+	for _, g := range result.Items {
+		items = append(items, toGrpcGroup(g))
+	}
+	return &groupgrpc.ListGroupsResponse{
+		Code:    resp.Code,
+		Message: resp.Message,
+		Data: &groupgrpc.ListGroupsResponse_Data{
+			Items: items,
+			Page:  result.Page,
+			Limit: result.Limit,
+			Total: result.Total,
+		},
+	}
+}
@@ -0,0 +1,105 @@
+package group_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-management/app/group"
+	groupgrpc "user-management/app/group/grpc/gen/go/group/v1"
+	"user-management/pagination"
+	"user-management/response"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGrpcHandler_CreateGroup(t *testing.T) {
+	mockUc := new(mockUsecase)
+	handler := group.NewGrpcHandler(mockUc)
+
+	ctx := context.Background()
+	mockUc.On("CreateGroup", ctx, group.CreateRequest{Name: "ops"}).Return(
+		response.SuccessWithData(group.CreateResponse{Id: "g1"}), nil)
+
+	resp, err := handler.CreateGroup(ctx, &groupgrpc.CreateGroupRequest{Name: "ops"})
+	assert.NoError(t, err)
+	assert.Equal(t, response.Success().Code, resp.Code)
+	assert.Equal(t, "g1", resp.Data.Id)
+}
+
+func TestGrpcHandler_CreateGroup_InvalidRequest(t *testing.T) {
+	mockUc := new(mockUsecase)
+	handler := group.NewGrpcHandler(mockUc)
+
+	resp, err := handler.CreateGroup(context.Background(), &groupgrpc.CreateGroupRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, response.MandatoryMissing("name").Code, resp.Code)
+	mockUc.AssertNotCalled(t, "CreateGroup", mock.Anything, mock.Anything)
+}
+
+func TestGrpcHandler_GetGroup(t *testing.T) {
+	mockUc := new(mockUsecase)
+	handler := group.NewGrpcHandler(mockUc)
+
+	id := primitive.NewObjectID().Hex()
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockUc.On("FindGroupById", mock.Anything, id).Return(
+		response.SuccessWithData(group.FindGroupResponse{Id: id, Name: "ops", CreatedAt: createdAt}), nil)
+
+	resp, err := handler.GetGroup(context.Background(), &groupgrpc.GetGroupRequest{Id: id})
+	assert.NoError(t, err)
+	assert.Equal(t, "ops", resp.Data.Name)
+	assert.Equal(t, "2025-01-01T00:00:00Z", resp.Data.CreatedAt)
+}
+
+func TestGrpcHandler_GetGroup_Error(t *testing.T) {
+	mockUc := new(mockUsecase)
+	handler := group.NewGrpcHandler(mockUc)
+
+	id := primitive.NewObjectID().Hex()
+	mockUc.On("FindGroupById", mock.Anything, id).Return((*response.StdResp[any])(nil), errors.New("db down"))
+
+	resp, err := handler.GetGroup(context.Background(), &groupgrpc.GetGroupRequest{Id: id})
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+}
+
+func TestGrpcHandler_ListMembers_DefaultPage(t *testing.T) {
+	mockUc := new(mockUsecase)
+	handler := group.NewGrpcHandler(mockUc)
+
+	groupID := primitive.NewObjectID()
+	page := pagination.Request{Page: 1, Limit: pagination.DefaultLimit}
+	members := []group.MemberResponse{{UserID: "u1", Name: "Alice", Email: "alice@example.com"}}
+	mockUc.On("FindMembers", mock.Anything, groupID, page).Return(
+		response.SuccessWithData(pagination.NewResult(members, page, 1)), nil)
+
+	resp, err := handler.ListMembers(context.Background(), &groupgrpc.ListMembersRequest{GroupId: groupID.Hex()})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Data.Items, 1)
+	assert.Equal(t, "alice@example.com", resp.Data.Items[0].Email)
+	assert.Equal(t, int64(1), resp.Data.Total)
+}
+
+func TestGrpcHandler_AddMember_InvalidUser(t *testing.T) {
+	mockUc := new(mockUsecase)
+	handler := group.NewGrpcHandler(mockUc)
+
+	resp, err := handler.AddMember(context.Background(), &groupgrpc.MemberRequest{GroupId: primitive.NewObjectID().Hex(), UserId: "bad"})
+	assert.NoError(t, err)
+	assert.Equal(t, response.InvalidData("user_id").Message, resp.Message)
+}
+
+func TestGrpcHandler_RemoveMember(t *testing.T) {
+	mockUc := new(mockUsecase)
+	handler := group.NewGrpcHandler(mockUc)
+
+	groupID, userID := primitive.NewObjectID(), primitive.NewObjectID()
+	mockUc.On("RemoveMember", mock.Anything, groupID, userID).Return(response.MemberNotFound(), nil)
+
+	resp, err := handler.RemoveMember(context.Background(), &groupgrpc.MemberRequest{GroupId: groupID.Hex(), UserId: userID.Hex()})
+	assert.NoError(t, err)
+	assert.Equal(t, response.MemberNotFound().Code, resp.Code)
+}
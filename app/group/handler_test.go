@@ -0,0 +1,166 @@
+package group_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-management/app/group"
+	"user-management/logger"
+	"user-management/pagination"
+	"user-management/response"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockUsecase struct {
+	mock.Mock
+}
+
+func (m *mockUsecase) CreateGroup(ctx context.Context, req group.CreateRequest) (*response.StdResp[any], error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) FindGroups(ctx context.Context, page pagination.Request) (*response.StdResp[any], error) {
+	args := m.Called(ctx, page)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) FindGroupById(ctx context.Context, id string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) UpdateGroup(ctx context.Context, id primitive.ObjectID, req group.UpdateRequest) (*response.StdResp[any], error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) DeleteGroup(ctx context.Context, id string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) AddMember(ctx context.Context, groupID, userID primitive.ObjectID) (*response.StdResp[any], error) {
+	args := m.Called(ctx, groupID, userID)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) RemoveMember(ctx context.Context, groupID, userID primitive.ObjectID) (*response.StdResp[any], error) {
+	args := m.Called(ctx, groupID, userID)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) FindMembers(ctx context.Context, groupID primitive.ObjectID, page pagination.Request) (*response.StdResp[any], error) {
+	args := m.Called(ctx, groupID, page)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) FindUserGroups(ctx context.Context, userID primitive.ObjectID, page pagination.Request) (*response.StdResp[any], error) {
+	args := m.Called(ctx, userID, page)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func newTestContext(method, target string, body any) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	var b []byte
+	if body != nil {
+		b, _ = json.Marshal(body)
+	}
+	req := httptest.NewRequest(method, target, bytes.NewReader(b))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	ctx := context.WithValue(c.Request().Context(), logger.LogContext, logger.NewZap())
+	c.SetRequest(req.WithContext(ctx))
+	return c, rec
+}
+
+func TestHandlerCreateGroup(t *testing.T) {
+	reqBody := group.CreateRequest{Name: "ops", Description: "on call"}
+	c, rec := newTestContext(http.MethodPost, "/groups", reqBody)
+
+	mockUc := new(mockUsecase)
+	mockUc.On("CreateGroup", mock.Anything, reqBody).Return(response.SuccessWithData(group.CreateResponse{Id: "abc123"}), nil)
+
+	err := group.NewHandler(mockUc).CreateGroup(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "abc123")
+}
+
+func TestHandlerFindGroups_Pagination(t *testing.T) {
+	c, rec := newTestContext(http.MethodGet, "/groups?page=2&limit=5", nil)
+
+	mockUc := new(mockUsecase)
+	page := pagination.Request{Page: 2, Limit: 5}
+	mockUc.On("FindGroups", mock.Anything, page).Return(response.SuccessWithData(pagination.NewResult([]group.FindGroupResponse{}, page, 5)), nil)
+
+	err := group.NewHandler(mockUc).FindGroups(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"total":5`)
+}
+
+func TestHandlerFindGroups_InvalidLimit(t *testing.T) {
+	c, rec := newTestContext(http.MethodGet, "/groups?limit=1000", nil)
+
+	mockUc := new(mockUsecase)
+
+	err := group.NewHandler(mockUc).FindGroups(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), response.InvalidData(pagination.QueryLimit).Message)
+}
+
+func TestHandlerAddMember(t *testing.T) {
+	groupID, userID := primitive.NewObjectID(), primitive.NewObjectID()
+	c, rec := newTestContext(http.MethodPost, "/groups/"+groupID.Hex()+"/members", group.AddMemberRequest{UserID: userID.Hex()})
+	c.SetParamNames(group.ParamID)
+	c.SetParamValues(groupID.Hex())
+
+	mockUc := new(mockUsecase)
+	mockUc.On("AddMember", mock.Anything, groupID, userID).Return(response.Success(), nil)
+
+	err := group.NewHandler(mockUc).AddMember(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUc.AssertExpectations(t)
+}
+
+func TestHandlerRemoveMember_InvalidUserId(t *testing.T) {
+	groupID := primitive.NewObjectID()
+	c, rec := newTestContext(http.MethodDelete, "/groups/"+groupID.Hex()+"/members/bad", nil)
+	c.SetParamNames(group.ParamID, group.ParamUserID)
+	c.SetParamValues(groupID.Hex(), "bad")
+
+	mockUc := new(mockUsecase)
+
+	err := group.NewHandler(mockUc).RemoveMember(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), response.InvalidData(group.ParamUserID).Message)
+}
+
+func TestHandlerFindUserGroups(t *testing.T) {
+	userID := primitive.NewObjectID()
+	c, rec := newTestContext(http.MethodGet, "/users/"+userID.Hex()+"/groups", nil)
+	c.SetParamNames(group.ParamID)
+	c.SetParamValues(userID.Hex())
+
+	mockUc := new(mockUsecase)
+	page := pagination.Request{Page: 1, Limit: pagination.DefaultLimit}
+	result := pagination.NewResult([]group.FindGroupResponse{{Id: "g1", Name: "ops"}}, page, 1)
+	mockUc.On("FindUserGroups", mock.Anything, userID, page).Return(response.SuccessWithData(result), nil)
+
+	err := group.NewHandler(mockUc).FindUserGroups(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "ops")
+}
@@ -0,0 +1,55 @@
+package group
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Group struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TenantID    string             `bson:"tenant_id,omitempty" json:"tenant_id,omitempty"`
+	Name        string             `bson:"name" json:"name"`
+	Description string             `bson:"description" json:"description"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+}
+
+type Member struct {
+	GroupID  primitive.ObjectID `bson:"group_id"`
+	UserID   primitive.ObjectID `bson:"user_id"`
+	TenantID string             `bson:"tenant_id,omitempty"`
+	AddedAt  time.Time          `bson:"added_at"`
+}
+
+type CreateRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type CreateResponse struct {
+	Id string `json:"id"`
+}
+
+type UpdateRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type AddMemberRequest struct {
+	UserID string `json:"user_id"`
+}
+
+type FindGroupResponse struct {
+	Id          string    `bson:"_id" json:"id"`
+	TenantID    string    `bson:"tenant_id,omitempty" json:"tenant_id,omitempty"`
+	Name        string    `bson:"name" json:"name"`
+	Description string    `bson:"description" json:"description"`
+	CreatedAt   time.Time `bson:"created_at" json:"created_at"`
+}
+
+type MemberResponse struct {
+	UserID  string    `bson:"user_id" json:"user_id"`
+	Name    string    `bson:"name" json:"name"`
+	Email   string    `bson:"email" json:"email"`
+	AddedAt time.Time `bson:"added_at" json:"added_at"`
+}
@@ -0,0 +1,235 @@
+package group
+
+import (
+	"context"
+	"time"
+	"user-management/config"
+	"user-management/pagination"
+	"user-management/storage"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type repository struct {
+	mc  storage.DatabaseConn
+	cfg config.MongoConfig
+}
+
+func NewRepository(mc storage.DatabaseConn, cfg config.MongoConfig) *repository {
+	return &repository{
+		mc:  mc,
+		cfg: cfg,
+	}
+}
+
+func (r *repository) CreateGroup(ctx context.Context, group Group) (string, error) {
+	group.CreatedAt = time.Now()
+	ior, err := r.mc.Collection(r.cfg.GroupCollection).InsertOne(ctx, group)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return "", ErrGroupAlreadyExists
+		}
+		return "", err
+	}
+	return ior.InsertedID.(primitive.ObjectID).Hex(), err
+}
+
+func (r *repository) FindGroupById(ctx context.Context, id string) (FindGroupResponse, error) {
+	var group FindGroupResponse
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return group, err
+	}
+	err = r.mc.Collection(r.cfg.GroupCollection).FindOne(ctx, storage.TenantFilter(ctx, bson.M{"_id": oid})).Decode(&group)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return group, ErrGroupNotFound
+		}
+		return group, err
+	}
+	return group, err
+}
+
+// FindGroupByName finds the group of ctx's tenant named name.
+func (r *repository) FindGroupByName(ctx context.Context, name string) (FindGroupResponse, error) {
+	var group FindGroupResponse
+	err := r.mc.Collection(r.cfg.GroupCollection).FindOne(ctx, storage.TenantFilter(ctx, bson.M{"name": name})).Decode(&group)
+	if err == mongo.ErrNoDocuments {
+		return group, ErrGroupNotFound
+	}
+	return group, err
+}
+
+func (r *repository) FindGroups(ctx context.Context, page pagination.Request) ([]FindGroupResponse, int64, error) {
+	filter := storage.TenantFilter(ctx, bson.M{})
+	total, err := r.mc.Collection(r.cfg.GroupCollection).CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	sort := bson.D{bson.E{Key: "name", Value: 1}}
+	opts := options.Find().SetSort(sort).SetSkip(page.Skip()).SetLimit(page.Limit)
+	cursor, err := r.mc.Collection(r.cfg.GroupCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	var groups []FindGroupResponse
+	err = cursor.All(ctx, &groups)
+	return groups, total, err
+}
+
+func (r *repository) UpdateGroup(ctx context.Context, group Group) (int64, error) {
+	updateFields := bson.M{}
+	if group.Name != "" {
+		updateFields["name"] = group.Name
+	}
+	if group.Description != "" {
+		updateFields["description"] = group.Description
+	}
+	update := bson.M{"$set": updateFields}
+	result, err := r.mc.Collection(r.cfg.GroupCollection).UpdateOne(ctx, storage.TenantFilter(ctx, bson.M{"_id": group.ID}), update)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return 0, ErrGroupAlreadyExists
+		}
+		return 0, err
+	}
+	return result.MatchedCount, nil
+}
+
+func (r *repository) DeleteGroup(ctx context.Context, id string) (int64, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return 0, err
+	}
+	result, err := r.mc.Collection(r.cfg.GroupCollection).DeleteOne(ctx, storage.TenantFilter(ctx, bson.M{"_id": oid}))
+	if err != nil {
+		return 0, err
+	}
+	if result.DeletedCount == 0 {
+		return 0, nil
+	}
+	_, err = r.mc.Collection(r.cfg.GroupMemberCollection).DeleteMany(ctx, bson.M{"group_id": oid})
+	return result.DeletedCount, err
+}
+
+func (r *repository) AddMember(ctx context.Context, member Member) error {
+	member.AddedAt = time.Now()
+	_, err := r.mc.Collection(r.cfg.GroupMemberCollection).InsertOne(ctx, member)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrMemberAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *repository) RemoveMember(ctx context.Context, groupID, userID primitive.ObjectID) (int64, error) {
+	filter := storage.TenantFilter(ctx, bson.M{"group_id": groupID, "user_id": userID})
+	result, err := r.mc.Collection(r.cfg.GroupMemberCollection).DeleteOne(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// RemoveUserMemberships takes the user out of every group.
+func (r *repository) RemoveUserMemberships(ctx context.Context, userID string) (int64, error) {
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return 0, err
+	}
+	result, err := r.mc.Collection(r.cfg.GroupMemberCollection).DeleteMany(ctx, storage.TenantFilter(ctx, bson.M{"user_id": oid}))
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// FindMembers returns one page of memberships. Names and emails are filled in
+// by the usecase, since users may live in a different storage backend.
+func (r *repository) FindMembers(ctx context.Context, groupID primitive.ObjectID, page pagination.Request) ([]MemberResponse, int64, error) {
+	filter := storage.TenantFilter(ctx, bson.M{"group_id": groupID})
+	total, err := r.mc.Collection(r.cfg.GroupMemberCollection).CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$sort", Value: bson.D{{Key: "added_at", Value: 1}}}},
+		{{Key: "$skip", Value: page.Skip()}},
+		{{Key: "$limit", Value: page.Limit}},
+		{{Key: "$project", Value: bson.M{
+			"_id":      0,
+			"user_id":  bson.M{"$toString": "$user_id"},
+			"added_at": 1,
+		}}},
+	}
+	cursor, err := r.mc.Collection(r.cfg.GroupMemberCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, err
+	}
+	var members []MemberResponse
+	err = cursor.All(ctx, &members)
+	return members, total, err
+}
+
+func (r *repository) FindUserGroups(ctx context.Context, userID primitive.ObjectID, page pagination.Request) ([]FindGroupResponse, int64, error) {
+	filter := storage.TenantFilter(ctx, bson.M{"user_id": userID})
+	total, err := r.mc.Collection(r.cfg.GroupMemberCollection).CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	pipeline := append(r.userGroupsPipeline(filter),
+		bson.D{{Key: "$skip", Value: page.Skip()}},
+		bson.D{{Key: "$limit", Value: page.Limit}},
+	)
+	cursor, err := r.mc.Collection(r.cfg.GroupMemberCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, err
+	}
+	var groups []FindGroupResponse
+	err = cursor.All(ctx, &groups)
+	return groups, total, err
+}
+
+func (r *repository) FindGroupNamesByUser(ctx context.Context, userID string) ([]string, error) {
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+	filter := storage.TenantFilter(ctx, bson.M{"user_id": oid})
+	cursor, err := r.mc.Collection(r.cfg.GroupMemberCollection).Aggregate(ctx, r.userGroupsPipeline(filter))
+	if err != nil {
+		return nil, err
+	}
+	var groups []FindGroupResponse
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(groups))
+	for _, g := range groups {
+		names = append(names, g.Name)
+	}
+	return names, nil
+}
+
+// userGroupsPipeline resolves the memberships matched by filter into their groups,
+// sorted by group name.
+func (r *repository) userGroupsPipeline(filter bson.M) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         r.cfg.GroupCollection,
+			"localField":   "group_id",
+			"foreignField": "_id",
+			"as":           "group",
+		}}},
+		{{Key: "$unwind", Value: "$group"}},
+		{{Key: "$replaceRoot", Value: bson.M{"newRoot": "$group"}}},
+		{{Key: "$sort", Value: bson.D{{Key: "name", Value: 1}}}},
+	}
+}
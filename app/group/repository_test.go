@@ -0,0 +1,211 @@
+package group_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-management/app/group"
+	"user-management/app/user"
+	"user-management/auth"
+	"user-management/config"
+	"user-management/pagination"
+	"user-management/storage"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// testRepository also covers the group lookup that Login uses for token claims,
+// the membership removal done by erasure and the name lookup of SCIM filters.
+type testRepository interface {
+	group.Repository
+	user.GroupRepository
+	RemoveUserMemberships(ctx context.Context, userID string) (int64, error)
+	FindGroupByName(ctx context.Context, name string) (group.FindGroupResponse, error)
+}
+
+func newTestRepository(mt *mtest.T) testRepository {
+	dbConn := storage.NewMongoConn(mt.Client, mt.Client.Database("testdb"))
+	return group.NewRepository(dbConn, config.MongoConfig{
+		Database:              "testdb",
+		UserCollection:        "users",
+		GroupCollection:       "groups",
+		GroupMemberCollection: "group_members",
+	})
+}
+
+func TestRepository_CreateGroup(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("success", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		id, err := repo.CreateGroup(context.Background(), group.Group{ID: primitive.NewObjectID(), Name: "ops"})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, id)
+	})
+
+	mt.Run("duplicate name", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+			Index:   0,
+			Code:    11000,
+			Message: "duplicate key error",
+		}))
+
+		_, err := repo.CreateGroup(context.Background(), group.Group{Name: "ops"})
+
+		assert.Equal(t, group.ErrGroupAlreadyExists, err)
+	})
+}
+
+func TestRepository_FindGroupByName(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("found in tenant", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		oid := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(1, "testdb.groups", mtest.FirstBatch, bson.D{
+			bson.E{Key: "_id", Value: oid},
+			bson.E{Key: "tenant_id", Value: "t1"},
+			bson.E{Key: "name", Value: "ops"},
+		}))
+
+		found, err := repo.FindGroupByName(auth.WithTenant(context.Background(), "t1"), "ops")
+
+		assert.NoError(t, err)
+		assert.Equal(t, oid.Hex(), found.Id)
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		assert.Equal(t, "ops", filter.Lookup("name").StringValue())
+		assert.Equal(t, "t1", filter.Lookup("tenant_id").StringValue())
+	})
+
+	mt.Run("not found", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.groups", mtest.FirstBatch))
+
+		_, err := repo.FindGroupByName(context.Background(), "ops")
+
+		assert.ErrorIs(t, err, group.ErrGroupNotFound)
+	})
+}
+
+func TestRepository_FindGroups(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("paged and tenant scoped", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		oid := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(1, "testdb.groups", mtest.FirstBatch, bson.D{
+				bson.E{Key: "n", Value: int64(3)},
+			}),
+			mtest.CreateCursorResponse(0, "testdb.groups", mtest.FirstBatch, bson.D{
+				bson.E{Key: "_id", Value: oid},
+				bson.E{Key: "tenant_id", Value: "t1"},
+				bson.E{Key: "name", Value: "ops"},
+			}),
+		)
+
+		ctx := auth.WithTenant(context.Background(), "t1")
+		groups, total, err := repo.FindGroups(ctx, pagination.Request{Page: 3, Limit: 1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Equal(t, []group.FindGroupResponse{{Id: oid.Hex(), TenantID: "t1", Name: "ops"}}, groups)
+		_ = mt.GetStartedEvent() // count
+		find := mt.GetStartedEvent()
+		assert.Equal(t, int64(2), find.Command.Lookup("skip").Int64())
+		assert.Equal(t, "t1", find.Command.Lookup("filter", "tenant_id").StringValue())
+	})
+}
+
+func TestRepository_AddMember(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("duplicate member", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+			Index:   0,
+			Code:    11000,
+			Message: "duplicate key error",
+		}))
+
+		err := repo.AddMember(context.Background(), group.Member{GroupID: primitive.NewObjectID(), UserID: primitive.NewObjectID()})
+
+		assert.Equal(t, group.ErrMemberAlreadyExists, err)
+	})
+}
+
+func TestRepository_FindMembers(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("success", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		userID := primitive.NewObjectID()
+		addedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(1, "testdb.group_members", mtest.FirstBatch, bson.D{
+				bson.E{Key: "n", Value: int64(1)},
+			}),
+			mtest.CreateCursorResponse(0, "testdb.group_members", mtest.FirstBatch, bson.D{
+				bson.E{Key: "user_id", Value: userID.Hex()},
+				bson.E{Key: "added_at", Value: addedAt},
+			}),
+		)
+
+		members, total, err := repo.FindMembers(context.Background(), primitive.NewObjectID(), pagination.Request{Page: 1, Limit: 20})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, []group.MemberResponse{{UserID: userID.Hex(), AddedAt: addedAt}}, members)
+	})
+}
+
+func TestRepository_FindGroupNamesByUser(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("success", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.group_members", mtest.FirstBatch,
+			bson.D{bson.E{Key: "_id", Value: primitive.NewObjectID()}, bson.E{Key: "name", Value: "engineering"}},
+			bson.D{bson.E{Key: "_id", Value: primitive.NewObjectID()}, bson.E{Key: "name", Value: "ops"}},
+		))
+
+		names, err := repo.FindGroupNamesByUser(context.Background(), primitive.NewObjectID().Hex())
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"engineering", "ops"}, names)
+	})
+}
+
+func TestRepository_RemoveUserMemberships(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("tenant scoped", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 2}})
+		uid := primitive.NewObjectID()
+
+		removed, err := repo.RemoveUserMemberships(auth.WithTenant(context.Background(), "t1"), uid.Hex())
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), removed)
+		del := mt.GetStartedEvent().Command.Lookup("deletes").Array().Index(0).Value().Document()
+		filter := del.Lookup("q").Document()
+		assert.Equal(t, uid, filter.Lookup("user_id").ObjectID())
+		assert.Equal(t, "t1", filter.Lookup("tenant_id").StringValue())
+	})
+
+	mt.Run("invalid id", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+
+		_, err := repo.RemoveUserMemberships(context.Background(), "nope")
+
+		assert.Error(t, err)
+	})
+}
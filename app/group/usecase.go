@@ -0,0 +1,220 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"user-management/app/user"
+	"user-management/auth"
+	"user-management/pagination"
+	"user-management/response"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Repository interface {
+	CreateGroup(ctx context.Context, group Group) (string, error)
+	FindGroupById(ctx context.Context, id string) (FindGroupResponse, error)
+	FindGroups(ctx context.Context, page pagination.Request) ([]FindGroupResponse, int64, error)
+	UpdateGroup(ctx context.Context, group Group) (int64, error)
+	DeleteGroup(ctx context.Context, id string) (int64, error)
+	AddMember(ctx context.Context, member Member) error
+	RemoveMember(ctx context.Context, groupID, userID primitive.ObjectID) (int64, error)
+	FindMembers(ctx context.Context, groupID primitive.ObjectID, page pagination.Request) ([]MemberResponse, int64, error)
+	FindUserGroups(ctx context.Context, userID primitive.ObjectID, page pagination.Request) ([]FindGroupResponse, int64, error)
+}
+
+type UserRepository interface {
+	FindUserById(ctx context.Context, id string) (user.FindUserResponse, error)
+	FindUsersByIds(ctx context.Context, ids []string) ([]user.FindUserResponse, error)
+}
+
+type usecase struct {
+	repo     Repository
+	userRepo UserRepository
+}
+
+func NewUsecase(r Repository, ur UserRepository) *usecase {
+	return &usecase{
+		repo:     r,
+		userRepo: ur,
+	}
+}
+
+func (u *usecase) CreateGroup(ctx context.Context, req CreateRequest) (*response.StdResp[any], error) {
+	if !isAdmin(ctx) {
+		return response.Forbidden(), nil
+	}
+	tenantID, _ := auth.TenantFromContext(ctx)
+	gid, err := u.repo.CreateGroup(ctx, Group{
+		TenantID:    tenantID,
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		if errors.Is(err, ErrGroupAlreadyExists) {
+			return response.DuplicatedGroup(), nil
+		}
+		return nil, err
+	}
+	return response.SuccessWithData(CreateResponse{gid}), nil
+}
+
+func (u *usecase) FindGroups(ctx context.Context, page pagination.Request) (*response.StdResp[any], error) {
+	groups, total, err := u.repo.FindGroups(ctx, page)
+	if err != nil {
+		return nil, err
+	}
+	return response.SuccessWithData(pagination.NewResult(groups, page, total)), nil
+}
+
+func (u *usecase) FindGroupById(ctx context.Context, id string) (*response.StdResp[any], error) {
+	group, err := u.repo.FindGroupById(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrGroupNotFound) {
+			return response.GroupNotFound(), nil
+		}
+		return nil, err
+	}
+	return response.SuccessWithData(group), nil
+}
+
+func (u *usecase) UpdateGroup(ctx context.Context, id primitive.ObjectID, req UpdateRequest) (*response.StdResp[any], error) {
+	if !isAdmin(ctx) {
+		return response.Forbidden(), nil
+	}
+	updateCount, err := u.repo.UpdateGroup(ctx, Group{
+		ID:          id,
+		Name:        req.Name,
+		Description: req.Description,
+	})
+	if err != nil {
+		if errors.Is(err, ErrGroupAlreadyExists) {
+			return response.DuplicatedGroup(), nil
+		}
+		return nil, err
+	}
+	if updateCount == 0 {
+		return response.GroupNotFound(), nil
+	}
+	return response.Success(), nil
+}
+
+func (u *usecase) DeleteGroup(ctx context.Context, id string) (*response.StdResp[any], error) {
+	if !isAdmin(ctx) {
+		return response.Forbidden(), nil
+	}
+	delCount, err := u.repo.DeleteGroup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if delCount == 0 {
+		return response.GroupNotFound(), nil
+	}
+	return response.Success(), nil
+}
+
+func (u *usecase) AddMember(ctx context.Context, groupID, userID primitive.ObjectID) (*response.StdResp[any], error) {
+	if !isAdmin(ctx) {
+		return response.Forbidden(), nil
+	}
+	group, err := u.repo.FindGroupById(ctx, groupID.Hex())
+	if err != nil {
+		if errors.Is(err, ErrGroupNotFound) {
+			return response.GroupNotFound(), nil
+		}
+		return nil, err
+	}
+	if _, err := u.userRepo.FindUserById(ctx, userID.Hex()); err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return response.UserNotFound(), nil
+		}
+		return nil, err
+	}
+
+	err = u.repo.AddMember(ctx, Member{
+		GroupID:  groupID,
+		UserID:   userID,
+		TenantID: group.TenantID,
+	})
+	if err != nil {
+		if errors.Is(err, ErrMemberAlreadyExists) {
+			return response.DuplicatedMember(), nil
+		}
+		return nil, err
+	}
+	return response.Success(), nil
+}
+
+func (u *usecase) RemoveMember(ctx context.Context, groupID, userID primitive.ObjectID) (*response.StdResp[any], error) {
+	if !isAdmin(ctx) {
+		return response.Forbidden(), nil
+	}
+	delCount, err := u.repo.RemoveMember(ctx, groupID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if delCount == 0 {
+		return response.MemberNotFound(), nil
+	}
+	return response.Success(), nil
+}
+
+func (u *usecase) FindMembers(ctx context.Context, groupID primitive.ObjectID, page pagination.Request) (*response.StdResp[any], error) {
+	if _, err := u.repo.FindGroupById(ctx, groupID.Hex()); err != nil {
+		if errors.Is(err, ErrGroupNotFound) {
+			return response.GroupNotFound(), nil
+		}
+		return nil, err
+	}
+	members, total, err := u.repo.FindMembers(ctx, groupID, page)
+	if err != nil {
+		return nil, err
+	}
+	members, err = u.withUserDetails(ctx, members)
+	if err != nil {
+		return nil, err
+	}
+	return response.SuccessWithData(pagination.NewResult(members, page, total)), nil
+}
+
+// withUserDetails fills in the name and email of each member, loading the
+// users of the page in one query. Deleting a user removes their memberships
+// before the user, so members without a user only remain from deletes made
+// before that; they are left out.
+func (u *usecase) withUserDetails(ctx context.Context, members []MemberResponse) ([]MemberResponse, error) {
+	ids := make([]string, len(members))
+	for i, m := range members {
+		ids[i] = m.UserID
+	}
+	users, err := u.userRepo.FindUsersByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]user.FindUserResponse, len(users))
+	for _, usr := range users {
+		byID[usr.Id] = usr
+	}
+	out := make([]MemberResponse, 0, len(members))
+	for _, m := range members {
+		usr, ok := byID[m.UserID]
+		if !ok {
+			continue
+		}
+		m.Name, m.Email = usr.Name, usr.Email
+		out = append(out, m)
+	}
+	return out, nil
+}
+
+func (u *usecase) FindUserGroups(ctx context.Context, userID primitive.ObjectID, page pagination.Request) (*response.StdResp[any], error) {
+	groups, total, err := u.repo.FindUserGroups(ctx, userID, page)
+	if err != nil {
+		return nil, err
+	}
+	return response.SuccessWithData(pagination.NewResult(groups, page, total)), nil
+}
+
+func isAdmin(ctx context.Context) bool {
+	claims, err := auth.FromContext(ctx)
+	return err == nil && claims.IsAdmin()
+}
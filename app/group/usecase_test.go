@@ -0,0 +1,233 @@
+package group_test
+
+import (
+	"context"
+	"testing"
+	"user-management/app/group"
+	"user-management/app/user"
+	"user-management/auth"
+	"user-management/pagination"
+	"user-management/response"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockRepo struct {
+	mock.Mock
+}
+
+func (m *mockRepo) CreateGroup(ctx context.Context, g group.Group) (string, error) {
+	args := m.Called(ctx, g)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockRepo) FindGroupById(ctx context.Context, id string) (group.FindGroupResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(group.FindGroupResponse), args.Error(1)
+}
+
+func (m *mockRepo) FindGroups(ctx context.Context, page pagination.Request) ([]group.FindGroupResponse, int64, error) {
+	args := m.Called(ctx, page)
+	return args.Get(0).([]group.FindGroupResponse), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *mockRepo) UpdateGroup(ctx context.Context, g group.Group) (int64, error) {
+	args := m.Called(ctx, g)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockRepo) DeleteGroup(ctx context.Context, id string) (int64, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockRepo) AddMember(ctx context.Context, member group.Member) error {
+	args := m.Called(ctx, member)
+	return args.Error(0)
+}
+
+func (m *mockRepo) RemoveMember(ctx context.Context, groupID, userID primitive.ObjectID) (int64, error) {
+	args := m.Called(ctx, groupID, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockRepo) FindMembers(ctx context.Context, groupID primitive.ObjectID, page pagination.Request) ([]group.MemberResponse, int64, error) {
+	args := m.Called(ctx, groupID, page)
+	return args.Get(0).([]group.MemberResponse), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *mockRepo) FindUserGroups(ctx context.Context, userID primitive.ObjectID, page pagination.Request) ([]group.FindGroupResponse, int64, error) {
+	args := m.Called(ctx, userID, page)
+	return args.Get(0).([]group.FindGroupResponse), args.Get(1).(int64), args.Error(2)
+}
+
+type mockUserRepo struct {
+	mock.Mock
+}
+
+func (m *mockUserRepo) FindUserById(ctx context.Context, id string) (user.FindUserResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(user.FindUserResponse), args.Error(1)
+}
+
+func (m *mockUserRepo) FindUsersByIds(ctx context.Context, ids []string) ([]user.FindUserResponse, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]user.FindUserResponse), args.Error(1)
+}
+
+func adminContext(tenantID string) context.Context {
+	return auth.WithClaims(context.Background(), &auth.Claims{TenantID: tenantID, Role: auth.RoleAdmin})
+}
+
+func TestUsecaseCreateGroup(t *testing.T) {
+	repo := new(mockRepo)
+	uc := group.NewUsecase(repo, new(mockUserRepo))
+
+	repo.On("CreateGroup", mock.Anything, mock.MatchedBy(func(g group.Group) bool {
+		return g.Name == "engineering" && g.TenantID == "t1"
+	})).Return("abc123", nil)
+
+	resp, err := uc.CreateGroup(adminContext("t1"), group.CreateRequest{Name: "engineering"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "abc123", resp.Data.(group.CreateResponse).Id)
+	repo.AssertExpectations(t)
+}
+
+func TestUsecaseCreateGroup_Forbidden(t *testing.T) {
+	repo := new(mockRepo)
+	uc := group.NewUsecase(repo, new(mockUserRepo))
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{TenantID: "t1", Role: auth.RoleUser})
+
+	resp, err := uc.CreateGroup(ctx, group.CreateRequest{Name: "engineering"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.Forbidden(), resp)
+	repo.AssertNotCalled(t, "CreateGroup", mock.Anything, mock.Anything)
+}
+
+func TestUsecaseCreateGroup_Duplicated(t *testing.T) {
+	repo := new(mockRepo)
+	uc := group.NewUsecase(repo, new(mockUserRepo))
+
+	repo.On("CreateGroup", mock.Anything, mock.Anything).Return("", group.ErrGroupAlreadyExists)
+
+	resp, err := uc.CreateGroup(adminContext(""), group.CreateRequest{Name: "engineering"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.DuplicatedGroup(), resp)
+}
+
+func TestUsecaseFindGroups(t *testing.T) {
+	repo := new(mockRepo)
+	uc := group.NewUsecase(repo, new(mockUserRepo))
+
+	page := pagination.Request{Page: 2, Limit: 1}
+	repo.On("FindGroups", mock.Anything, page).Return([]group.FindGroupResponse{{Name: "ops"}}, int64(2), nil)
+
+	resp, err := uc.FindGroups(context.Background(), page)
+
+	assert.NoError(t, err)
+	result := resp.Data.(pagination.Result[group.FindGroupResponse])
+	assert.Len(t, result.Items, 1)
+	assert.Equal(t, int64(2), result.Total)
+	assert.Equal(t, int64(2), result.Page)
+}
+
+func TestUsecaseAddMember(t *testing.T) {
+	repo := new(mockRepo)
+	userRepo := new(mockUserRepo)
+	uc := group.NewUsecase(repo, userRepo)
+
+	groupID, userID := primitive.NewObjectID(), primitive.NewObjectID()
+	repo.On("FindGroupById", mock.Anything, groupID.Hex()).Return(group.FindGroupResponse{Id: groupID.Hex(), TenantID: "t1"}, nil)
+	userRepo.On("FindUserById", mock.Anything, userID.Hex()).Return(user.FindUserResponse{Id: userID.Hex()}, nil)
+	repo.On("AddMember", mock.Anything, group.Member{GroupID: groupID, UserID: userID, TenantID: "t1"}).Return(nil)
+
+	resp, err := uc.AddMember(adminContext("t1"), groupID, userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.Success(), resp)
+	repo.AssertExpectations(t)
+	userRepo.AssertExpectations(t)
+}
+
+func TestUsecaseAddMember_UserNotFound(t *testing.T) {
+	repo := new(mockRepo)
+	userRepo := new(mockUserRepo)
+	uc := group.NewUsecase(repo, userRepo)
+
+	groupID, userID := primitive.NewObjectID(), primitive.NewObjectID()
+	repo.On("FindGroupById", mock.Anything, groupID.Hex()).Return(group.FindGroupResponse{Id: groupID.Hex()}, nil)
+	userRepo.On("FindUserById", mock.Anything, userID.Hex()).Return(user.FindUserResponse{}, user.ErrUserNotFound)
+
+	resp, err := uc.AddMember(adminContext(""), groupID, userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.UserNotFound(), resp)
+	repo.AssertNotCalled(t, "AddMember", mock.Anything, mock.Anything)
+}
+
+func TestUsecaseAddMember_Duplicated(t *testing.T) {
+	repo := new(mockRepo)
+	userRepo := new(mockUserRepo)
+	uc := group.NewUsecase(repo, userRepo)
+
+	groupID, userID := primitive.NewObjectID(), primitive.NewObjectID()
+	repo.On("FindGroupById", mock.Anything, groupID.Hex()).Return(group.FindGroupResponse{Id: groupID.Hex()}, nil)
+	userRepo.On("FindUserById", mock.Anything, userID.Hex()).Return(user.FindUserResponse{Id: userID.Hex()}, nil)
+	repo.On("AddMember", mock.Anything, mock.Anything).Return(group.ErrMemberAlreadyExists)
+
+	resp, err := uc.AddMember(adminContext(""), groupID, userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.DuplicatedMember(), resp)
+}
+
+func TestUsecaseRemoveMember_NotFound(t *testing.T) {
+	repo := new(mockRepo)
+	uc := group.NewUsecase(repo, new(mockUserRepo))
+
+	groupID, userID := primitive.NewObjectID(), primitive.NewObjectID()
+	repo.On("RemoveMember", mock.Anything, groupID, userID).Return(int64(0), nil)
+
+	resp, err := uc.RemoveMember(adminContext(""), groupID, userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.MemberNotFound(), resp)
+}
+
+func TestUsecaseFindMembers_GroupNotFound(t *testing.T) {
+	repo := new(mockRepo)
+	uc := group.NewUsecase(repo, new(mockUserRepo))
+
+	groupID := primitive.NewObjectID()
+	repo.On("FindGroupById", mock.Anything, groupID.Hex()).Return(group.FindGroupResponse{}, group.ErrGroupNotFound)
+
+	resp, err := uc.FindMembers(context.Background(), groupID, pagination.Request{Page: 1, Limit: 20})
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.GroupNotFound(), resp)
+	repo.AssertNotCalled(t, "FindMembers", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUsecaseFindMembers_FillsUserDetails(t *testing.T) {
+	repo := new(mockRepo)
+	userRepo := new(mockUserRepo)
+	uc := group.NewUsecase(repo, userRepo)
+
+	groupID := primitive.NewObjectID()
+	page := pagination.Request{Page: 1, Limit: 20}
+	repo.On("FindGroupById", mock.Anything, groupID.Hex()).Return(group.FindGroupResponse{Id: groupID.Hex()}, nil)
+	repo.On("FindMembers", mock.Anything, groupID, page).Return([]group.MemberResponse{{UserID: "u1"}, {UserID: "gone"}}, int64(2), nil)
+	userRepo.On("FindUsersByIds", mock.Anything, []string{"u1", "gone"}).Return([]user.FindUserResponse{{Id: "u1", Name: "Alice", Email: "alice@example.com"}}, nil)
+
+	resp, err := uc.FindMembers(context.Background(), groupID, page)
+
+	assert.NoError(t, err)
+	want := []group.MemberResponse{{UserID: "u1", Name: "Alice", Email: "alice@example.com"}}
+	assert.Equal(t, response.SuccessWithData(pagination.NewResult(want, page, 2)), resp)
+	userRepo.AssertNotCalled(t, "FindUserById", mock.Anything, mock.Anything)
+}
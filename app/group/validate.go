@@ -0,0 +1,41 @@
+package group
+
+import (
+	"strings"
+	"user-management/response"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (r CreateRequest) RequestValidation() *response.StdResp[any] {
+	if checkLen(r.Name) == 0 {
+		return response.MandatoryMissing("name")
+	}
+	return response.Success()
+}
+
+func (r UpdateRequest) RequestValidation() *response.StdResp[any] {
+	if checkLen(r.Name) == 0 && checkLen(r.Description) == 0 {
+		return response.MandatoryMissing("name or description")
+	}
+	return response.Success()
+}
+
+func (r AddMemberRequest) RequestValidation() *response.StdResp[any] {
+	if checkLen(r.UserID) == 0 {
+		return response.MandatoryMissing("user_id")
+	}
+	return IdValidation(r.UserID, "user_id")
+}
+
+func IdValidation(id, field string) *response.StdResp[any] {
+	_, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return response.InvalidData(field)
+	}
+	return response.Success()
+}
+
+func checkLen(s string) int {
+	return len([]rune(strings.TrimSpace(s)))
+}
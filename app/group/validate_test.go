@@ -0,0 +1,38 @@
+package group_test
+
+import (
+	"testing"
+	"user-management/app/group"
+	"user-management/response"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCreateRequest_RequestValidation(t *testing.T) {
+	assert.Equal(t, response.Success(), group.CreateRequest{Name: "ops"}.RequestValidation())
+	assert.Equal(t, response.MandatoryMissing("name"), group.CreateRequest{Name: " "}.RequestValidation())
+}
+
+func TestUpdateRequest_RequestValidation(t *testing.T) {
+	assert.Equal(t, response.Success(), group.UpdateRequest{Description: "on call"}.RequestValidation())
+	assert.Equal(t, response.MandatoryMissing("name or description"), group.UpdateRequest{}.RequestValidation())
+}
+
+func TestAddMemberRequest_RequestValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    group.AddMemberRequest
+		wantCode *response.StdResp[any]
+	}{
+		{"Valid", group.AddMemberRequest{UserID: primitive.NewObjectID().Hex()}, response.Success()},
+		{"Missing user", group.AddMemberRequest{}, response.MandatoryMissing("user_id")},
+		{"Invalid user", group.AddMemberRequest{UserID: "abc"}, response.InvalidData("user_id")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantCode, tt.input.RequestValidation())
+		})
+	}
+}
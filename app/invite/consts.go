@@ -0,0 +1,19 @@
+package invite
+
+import "errors"
+
+const (
+	ParamID     = "id"
+	QueryStatus = "status"
+)
+
+const (
+	StatusPending  = "pending"
+	StatusAccepted = "accepted"
+	StatusRevoked  = "revoked"
+	StatusExpired  = "expired"
+)
+
+var (
+	ErrInviteNotFound = errors.New("Invite not found")
+)
@@ -0,0 +1,126 @@
+package invite
+
+import (
+	"context"
+	"user-management/logger"
+	"user-management/pagination"
+	"user-management/response"
+
+	echo "github.com/labstack/echo/v4"
+)
+
+type Usecase interface {
+	CreateInvite(ctx context.Context, req CreateRequest) (*response.StdResp[any], error)
+	AcceptInvite(ctx context.Context, req AcceptRequest) (*response.StdResp[any], error)
+	FindInvites(ctx context.Context, status string, page pagination.Request) (*response.StdResp[any], error)
+	RevokeInvite(ctx context.Context, id string) (*response.StdResp[any], error)
+}
+
+type Handler interface {
+	CreateInvite(c echo.Context) error
+	AcceptInvite(c echo.Context) error
+	FindInvites(c echo.Context) error
+	RevokeInvite(c echo.Context) error
+}
+
+type handler struct {
+	usecase Usecase
+}
+
+func NewHandler(u Usecase) *handler {
+	return &handler{
+		usecase: u,
+	}
+}
+
+func (h *handler) CreateInvite(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	var request CreateRequest
+	err = c.Bind(&request)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Bind request error: %v", err.Error())
+		return c.JSON(response.UnexpectedRequest().WithHTTPStatus())
+	}
+
+	if resp := request.RequestValidation(); !resp.IsSuccess() {
+		return c.JSON(resp.WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.CreateInvite(ctx, request)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+func (h *handler) AcceptInvite(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	var request AcceptRequest
+	err = c.Bind(&request)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Bind request error: %v", err.Error())
+		return c.JSON(response.UnexpectedRequest().WithHTTPStatus())
+	}
+
+	if resp := request.RequestValidation(); !resp.IsSuccess() {
+		return c.JSON(resp.WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.AcceptInvite(ctx, request)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+func (h *handler) FindInvites(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	status := c.QueryParam(QueryStatus)
+	if respValidate := StatusValidation(status); !respValidate.IsSuccess() {
+		return c.JSON(respValidate.WithHTTPStatus())
+	}
+	page, respValidate := pagination.Parse(c.QueryParam(pagination.QueryPage), c.QueryParam(pagination.QueryLimit))
+	if !respValidate.IsSuccess() {
+		return c.JSON(respValidate.WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.FindInvites(ctx, status, page)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+func (h *handler) RevokeInvite(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	paramId := c.Param(ParamID)
+	if respValidate := IdValidation(paramId); !respValidate.IsSuccess() {
+		return c.JSON(respValidate.WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.RevokeInvite(ctx, paramId)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
@@ -0,0 +1,121 @@
+package invite_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-management/app/invite"
+	"user-management/logger"
+	"user-management/pagination"
+	"user-management/response"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockUsecase struct {
+	mock.Mock
+}
+
+func (m *mockUsecase) CreateInvite(ctx context.Context, req invite.CreateRequest) (*response.StdResp[any], error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) AcceptInvite(ctx context.Context, req invite.AcceptRequest) (*response.StdResp[any], error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) FindInvites(ctx context.Context, status string, page pagination.Request) (*response.StdResp[any], error) {
+	args := m.Called(ctx, status, page)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) RevokeInvite(ctx context.Context, id string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func newTestContext(method, target string, body any) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	var b []byte
+	if body != nil {
+		b, _ = json.Marshal(body)
+	}
+	req := httptest.NewRequest(method, target, bytes.NewReader(b))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	ctx := context.WithValue(c.Request().Context(), logger.LogContext, logger.NewZap())
+	c.SetRequest(req.WithContext(ctx))
+	return c, rec
+}
+
+func TestHandlerCreateInvite(t *testing.T) {
+	reqBody := invite.CreateRequest{Email: "new@example.com", Role: "user"}
+	c, rec := newTestContext(http.MethodPost, "/invites", reqBody)
+
+	mockUc := new(mockUsecase)
+	mockUc.On("CreateInvite", mock.Anything, reqBody).Return(response.SuccessWithData(invite.CreateResponse{Id: "inv1"}), nil)
+
+	err := invite.NewHandler(mockUc).CreateInvite(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "inv1")
+}
+
+func TestHandlerAcceptInvite_Invalid(t *testing.T) {
+	reqBody := invite.AcceptRequest{Token: "tok", Name: "New", Password: "secret"}
+	c, rec := newTestContext(http.MethodPost, "/invites/accept", reqBody)
+
+	mockUc := new(mockUsecase)
+	mockUc.On("AcceptInvite", mock.Anything, reqBody).Return(response.InvalidInvite(), nil)
+
+	err := invite.NewHandler(mockUc).AcceptInvite(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), response.InvalidInvite().Message)
+}
+
+func TestHandlerFindInvites(t *testing.T) {
+	c, rec := newTestContext(http.MethodGet, "/invites?status=pending&limit=5", nil)
+
+	mockUc := new(mockUsecase)
+	page := pagination.Request{Page: 1, Limit: 5}
+	mockUc.On("FindInvites", mock.Anything, invite.StatusPending, page).Return(response.SuccessWithData(pagination.NewResult([]invite.FindInviteResponse{}, page, 0)), nil)
+
+	err := invite.NewHandler(mockUc).FindInvites(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUc.AssertExpectations(t)
+}
+
+func TestHandlerFindInvites_InvalidStatus(t *testing.T) {
+	c, rec := newTestContext(http.MethodGet, "/invites?status=unknown", nil)
+
+	err := invite.NewHandler(new(mockUsecase)).FindInvites(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), response.InvalidData(invite.QueryStatus).Message)
+}
+
+func TestHandlerRevokeInvite(t *testing.T) {
+	id := primitive.NewObjectID().Hex()
+	c, rec := newTestContext(http.MethodDelete, "/invites/"+id, nil)
+	c.SetParamNames(invite.ParamID)
+	c.SetParamValues(id)
+
+	mockUc := new(mockUsecase)
+	mockUc.On("RevokeInvite", mock.Anything, id).Return(response.Success(), nil)
+
+	err := invite.NewHandler(mockUc).RevokeInvite(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUc.AssertExpectations(t)
+}
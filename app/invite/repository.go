@@ -0,0 +1,139 @@
+package invite
+
+import (
+	"context"
+	"time"
+	"user-management/config"
+	"user-management/pagination"
+	"user-management/storage"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type repository struct {
+	mc  storage.DatabaseConn
+	cfg config.MongoConfig
+}
+
+func NewRepository(mc storage.DatabaseConn, cfg config.MongoConfig) *repository {
+	return &repository{
+		mc:  mc,
+		cfg: cfg,
+	}
+}
+
+func (r *repository) CreateInvite(ctx context.Context, invite Invite) (string, error) {
+	invite.CreatedAt = time.Now()
+	ior, err := r.mc.Collection(r.cfg.InviteCollection).InsertOne(ctx, invite)
+	if err != nil {
+		return "", err
+	}
+	return ior.InsertedID.(primitive.ObjectID).Hex(), err
+}
+
+func (r *repository) FindInviteById(ctx context.Context, id string) (Invite, error) {
+	var invite Invite
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return invite, err
+	}
+	return r.findOne(ctx, storage.TenantFilter(ctx, bson.M{"_id": oid}))
+}
+
+// FindInviteByTokenHash is not tenant scoped: the token itself identifies the tenant.
+func (r *repository) FindInviteByTokenHash(ctx context.Context, tokenHash string) (Invite, error) {
+	return r.findOne(ctx, bson.M{"token_hash": tokenHash})
+}
+
+func (r *repository) FindInvites(ctx context.Context, status string, page pagination.Request) ([]Invite, int64, error) {
+	filter := storage.TenantFilter(ctx, statusFilter(status, time.Now()))
+	total, err := r.mc.Collection(r.cfg.InviteCollection).CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	sort := bson.D{bson.E{Key: "created_at", Value: -1}}
+	opts := options.Find().SetSort(sort).SetSkip(page.Skip()).SetLimit(page.Limit)
+	cursor, err := r.mc.Collection(r.cfg.InviteCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	var invites []Invite
+	err = cursor.All(ctx, &invites)
+	return invites, total, err
+}
+
+// RevokeInvite revokes a pending invite and reports whether one was revoked.
+func (r *repository) RevokeInvite(ctx context.Context, id primitive.ObjectID) (int64, error) {
+	filter := storage.TenantFilter(ctx, bson.M{"_id": id, "accepted_at": nil, "revoked_at": nil})
+	update := bson.M{"$set": bson.M{"revoked_at": time.Now()}}
+	result, err := r.mc.Collection(r.cfg.InviteCollection).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// DeleteInvitesByEmail removes every invite sent to email in ctx's tenant,
+// whatever its state. Invites keep the address as it was typed, so it is
+// compared case-insensitively.
+func (r *repository) DeleteInvitesByEmail(ctx context.Context, email string) (int64, error) {
+	opts := options.Delete().SetCollation(&options.Collation{Locale: "en", Strength: 2})
+	result, err := r.mc.Collection(r.cfg.InviteCollection).DeleteMany(ctx, storage.TenantFilter(ctx, bson.M{"email": email}), opts)
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// MarkAccepted claims a pending, unexpired invite. Only one caller can claim it.
+func (r *repository) MarkAccepted(ctx context.Context, id primitive.ObjectID) (int64, error) {
+	now := time.Now()
+	filter := bson.M{
+		"_id":         id,
+		"accepted_at": nil,
+		"revoked_at":  nil,
+		"expires_at":  bson.M{"$gt": now},
+	}
+	update := bson.M{"$set": bson.M{"accepted_at": now}}
+	result, err := r.mc.Collection(r.cfg.InviteCollection).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// UnmarkAccepted releases a claim made by MarkAccepted when the user could not be created.
+func (r *repository) UnmarkAccepted(ctx context.Context, id primitive.ObjectID) error {
+	update := bson.M{"$unset": bson.M{"accepted_at": ""}}
+	_, err := r.mc.Collection(r.cfg.InviteCollection).UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
+
+func (r *repository) findOne(ctx context.Context, filter bson.M) (Invite, error) {
+	var invite Invite
+	err := r.mc.Collection(r.cfg.InviteCollection).FindOne(ctx, filter).Decode(&invite)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return invite, ErrInviteNotFound
+		}
+		return invite, err
+	}
+	return invite, err
+}
+
+func statusFilter(status string, now time.Time) bson.M {
+	switch status {
+	case StatusPending:
+		return bson.M{"accepted_at": nil, "revoked_at": nil, "expires_at": bson.M{"$gt": now}}
+	case StatusAccepted:
+		return bson.M{"accepted_at": bson.M{"$ne": nil}}
+	case StatusRevoked:
+		return bson.M{"accepted_at": nil, "revoked_at": bson.M{"$ne": nil}}
+	case StatusExpired:
+		return bson.M{"accepted_at": nil, "revoked_at": nil, "expires_at": bson.M{"$lte": now}}
+	}
+	return bson.M{}
+}
@@ -0,0 +1,141 @@
+package invite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-management/app/invite"
+	"user-management/auth"
+	"user-management/config"
+	"user-management/pagination"
+	"user-management/storage"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func newTestRepository(mt *mtest.T) invite.Repository {
+	dbConn := storage.NewMongoConn(mt.Client, mt.Client.Database("testdb"))
+	return invite.NewRepository(dbConn, config.MongoConfig{
+		Database:         "testdb",
+		InviteCollection: "invites",
+	})
+}
+
+func TestRepository_CreateInvite(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("success", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		id, err := repo.CreateInvite(context.Background(), invite.Invite{ID: primitive.NewObjectID(), Email: "a@example.com"})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, id)
+	})
+}
+
+func TestRepository_FindInviteByTokenHash(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("not found", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.invites", mtest.FirstBatch))
+
+		_, err := repo.FindInviteByTokenHash(auth.WithTenant(context.Background(), "t1"), "hash")
+
+		assert.Equal(t, invite.ErrInviteNotFound, err)
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		_, lookupErr := filter.LookupErr("tenant_id")
+		assert.Error(t, lookupErr)
+	})
+}
+
+func TestRepository_FindInvites(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("pending and tenant scoped", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		oid := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(1, "testdb.invites", mtest.FirstBatch, bson.D{
+				bson.E{Key: "n", Value: int64(1)},
+			}),
+			mtest.CreateCursorResponse(0, "testdb.invites", mtest.FirstBatch, bson.D{
+				bson.E{Key: "_id", Value: oid},
+				bson.E{Key: "tenant_id", Value: "t1"},
+				bson.E{Key: "email", Value: "a@example.com"},
+				bson.E{Key: "expires_at", Value: time.Now().Add(time.Hour)},
+			}),
+		)
+
+		ctx := auth.WithTenant(context.Background(), "t1")
+		invites, total, err := repo.FindInvites(ctx, invite.StatusPending, pagination.Request{Page: 1, Limit: 10})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Len(t, invites, 1)
+		assert.Equal(t, oid, invites[0].ID)
+		mt.GetStartedEvent() // count
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		assert.Equal(t, "t1", filter.Lookup("tenant_id").StringValue())
+		assert.Equal(t, bson.TypeNull, filter.Lookup("accepted_at").Type)
+	})
+}
+
+func TestRepository_MarkAccepted(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("claimed", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(bson.D{
+			bson.E{Key: "ok", Value: 1},
+			bson.E{Key: "n", Value: 1},
+			bson.E{Key: "nModified", Value: 1},
+		})
+
+		count, err := repo.MarkAccepted(context.Background(), primitive.NewObjectID())
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	mt.Run("already claimed", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(bson.D{
+			bson.E{Key: "ok", Value: 1},
+			bson.E{Key: "n", Value: 0},
+			bson.E{Key: "nModified", Value: 0},
+		})
+
+		count, err := repo.MarkAccepted(context.Background(), primitive.NewObjectID())
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+	})
+}
+
+func TestRepository_DeleteInvitesByEmail(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("case-insensitive and tenant scoped", func(mt *mtest.T) {
+		repo := invite.NewRepository(storage.NewMongoConn(mt.Client, mt.Client.Database("testdb")), config.MongoConfig{
+			Database:         "testdb",
+			InviteCollection: "invites",
+		})
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 2}})
+
+		deleted, err := repo.DeleteInvitesByEmail(auth.WithTenant(context.Background(), "t1"), "Bob@example.com")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+		del := mt.GetStartedEvent().Command.Lookup("deletes").Array().Index(0).Value().Document()
+		q := del.Lookup("q").Document()
+		assert.Equal(t, "Bob@example.com", q.Lookup("email").StringValue())
+		assert.Equal(t, "t1", q.Lookup("tenant_id").StringValue())
+		assert.Equal(t, int32(2), del.Lookup("collation").Document().Lookup("strength").Int32())
+	})
+}
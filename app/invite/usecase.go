@@ -0,0 +1,205 @@
+package invite
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+	"user-management/app/user"
+	"user-management/auth"
+	"user-management/config"
+	"user-management/notifier"
+	"user-management/opaque"
+	"user-management/pagination"
+	"user-management/passhash"
+	"user-management/response"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Repository interface {
+	CreateInvite(ctx context.Context, invite Invite) (string, error)
+	FindInviteById(ctx context.Context, id string) (Invite, error)
+	FindInviteByTokenHash(ctx context.Context, tokenHash string) (Invite, error)
+	FindInvites(ctx context.Context, status string, page pagination.Request) ([]Invite, int64, error)
+	RevokeInvite(ctx context.Context, id primitive.ObjectID) (int64, error)
+	MarkAccepted(ctx context.Context, id primitive.ObjectID) (int64, error)
+	UnmarkAccepted(ctx context.Context, id primitive.ObjectID) error
+}
+
+type UserRepository interface {
+	CreateUser(ctx context.Context, user user.User) (string, error)
+	FindUserByEmail(ctx context.Context, email string) (user.User, error)
+}
+
+type usecase struct {
+	cfg      config.InviteConfig
+	policy   *user.PasswordPolicy
+	hasher   passhash.Hasher
+	repo     Repository
+	userRepo UserRepository
+	notifier notifier.Notifier
+}
+
+func NewUsecase(cfg config.InviteConfig, policy *user.PasswordPolicy, hasher passhash.Hasher, r Repository, ur UserRepository, n notifier.Notifier) *usecase {
+	return &usecase{
+		cfg:      cfg,
+		policy:   policy,
+		hasher:   hasher,
+		repo:     r,
+		userRepo: ur,
+		notifier: n,
+	}
+}
+
+func (u *usecase) CreateInvite(ctx context.Context, req CreateRequest) (*response.StdResp[any], error) {
+	tenantID, scoped := auth.TenantFromContext(ctx)
+	if !scoped {
+		tenantID = req.TenantID
+	}
+	role := req.Role
+	if role == "" {
+		role = auth.RoleUser
+	}
+
+	_, err := u.userRepo.FindUserByEmail(auth.WithTenant(ctx, tenantID), req.Email)
+	if err == nil {
+		return response.DuplicatedRegistration(), nil
+	}
+	if !errors.Is(err, user.ErrUserOrPasswordIsWrong) {
+		return nil, err
+	}
+
+	token, err := opaque.New()
+	if err != nil {
+		return nil, err
+	}
+	invitedBy := ""
+	if claims, err := auth.FromContext(ctx); err == nil {
+		invitedBy = claims.UserID
+	}
+	expAt := time.Now().Add(u.cfg.TTL)
+	id, err := u.repo.CreateInvite(ctx, Invite{
+		TenantID:  tenantID,
+		Email:     req.Email,
+		Role:      role,
+		TokenHash: opaque.Hash(token),
+		InvitedBy: invitedBy,
+		ExpiresAt: expAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.notifier.SendInvitation(ctx, notifier.Invitation{
+		Email:     req.Email,
+		Role:      role,
+		TenantID:  tenantID,
+		Token:     token,
+		AcceptURL: u.cfg.AcceptURL + "?token=" + url.QueryEscape(token),
+		ExpiresAt: expAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.SuccessWithData(CreateResponse{Id: id, ExpiresAt: expAt}), nil
+}
+
+func (u *usecase) AcceptInvite(ctx context.Context, req AcceptRequest) (*response.StdResp[any], error) {
+	invite, err := u.repo.FindInviteByTokenHash(ctx, opaque.Hash(req.Token))
+	if err != nil {
+		if errors.Is(err, ErrInviteNotFound) {
+			return response.InvalidInvite(), nil
+		}
+		return nil, err
+	}
+	if invite.Status(time.Now()) != StatusPending {
+		return response.InvalidInvite(), nil
+	}
+	violations, err := u.policy.Check(req.Password, req.Name, invite.Email)
+	if err != nil {
+		return nil, err
+	}
+	if len(violations) > 0 {
+		return response.WeakPassword(violations), nil
+	}
+
+	claimed, err := u.repo.MarkAccepted(ctx, invite.ID)
+	if err != nil {
+		return nil, err
+	}
+	if claimed == 0 {
+		return response.InvalidInvite(), nil
+	}
+
+	hashedPassword, err := u.hasher.Hash(req.Password)
+	if err != nil {
+		return nil, errors.Join(err, u.repo.UnmarkAccepted(ctx, invite.ID))
+	}
+	uid, err := u.userRepo.CreateUser(auth.WithTenant(ctx, invite.TenantID), user.User{
+		TenantID: invite.TenantID,
+		Name:     req.Name,
+		Email:    invite.Email,
+		Password: hashedPassword,
+		Role:     invite.Role,
+	})
+	if err != nil {
+		if unmarkErr := u.repo.UnmarkAccepted(ctx, invite.ID); unmarkErr != nil {
+			return nil, errors.Join(err, unmarkErr)
+		}
+		if errors.Is(err, user.ErrEmailAlreadyExists) {
+			return response.DuplicatedRegistration(), nil
+		}
+		return nil, err
+	}
+	return response.SuccessWithData(AcceptResponse{UserId: uid}), nil
+}
+
+func (u *usecase) FindInvites(ctx context.Context, status string, page pagination.Request) (*response.StdResp[any], error) {
+	invites, total, err := u.repo.FindInvites(ctx, status, page)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	items := make([]FindInviteResponse, 0, len(invites))
+	for _, i := range invites {
+		items = append(items, toFindInviteResponse(i, now))
+	}
+	return response.SuccessWithData(pagination.NewResult(items, page, total)), nil
+}
+
+func (u *usecase) RevokeInvite(ctx context.Context, id string) (*response.StdResp[any], error) {
+	invite, err := u.repo.FindInviteById(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrInviteNotFound) {
+			return response.InviteNotFound(), nil
+		}
+		return nil, err
+	}
+	if invite.Status(time.Now()) != StatusPending {
+		return response.InvalidInvite(), nil
+	}
+	revoked, err := u.repo.RevokeInvite(ctx, invite.ID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked == 0 {
+		return response.InvalidInvite(), nil
+	}
+	return response.Success(), nil
+}
+
+func toFindInviteResponse(i Invite, now time.Time) FindInviteResponse {
+	return FindInviteResponse{
+		Id:         i.ID.Hex(),
+		TenantID:   i.TenantID,
+		Email:      i.Email,
+		Role:       i.Role,
+		Status:     i.Status(now),
+		InvitedBy:  i.InvitedBy,
+		ExpiresAt:  i.ExpiresAt,
+		AcceptedAt: i.AcceptedAt,
+		RevokedAt:  i.RevokedAt,
+		CreatedAt:  i.CreatedAt,
+	}
+}
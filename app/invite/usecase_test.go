@@ -0,0 +1,318 @@
+package invite_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+	"user-management/app/invite"
+	"user-management/app/user"
+	"user-management/auth"
+	"user-management/config"
+	"user-management/notifier"
+	"user-management/opaque"
+	"user-management/pagination"
+	"user-management/passhash"
+	"user-management/response"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type mockRepo struct {
+	mock.Mock
+}
+
+func (m *mockRepo) CreateInvite(ctx context.Context, i invite.Invite) (string, error) {
+	args := m.Called(ctx, i)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockRepo) FindInviteById(ctx context.Context, id string) (invite.Invite, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(invite.Invite), args.Error(1)
+}
+
+func (m *mockRepo) FindInviteByTokenHash(ctx context.Context, tokenHash string) (invite.Invite, error) {
+	args := m.Called(ctx, tokenHash)
+	return args.Get(0).(invite.Invite), args.Error(1)
+}
+
+func (m *mockRepo) FindInvites(ctx context.Context, status string, page pagination.Request) ([]invite.Invite, int64, error) {
+	args := m.Called(ctx, status, page)
+	return args.Get(0).([]invite.Invite), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *mockRepo) RevokeInvite(ctx context.Context, id primitive.ObjectID) (int64, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockRepo) MarkAccepted(ctx context.Context, id primitive.ObjectID) (int64, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockRepo) UnmarkAccepted(ctx context.Context, id primitive.ObjectID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type mockUserRepo struct {
+	mock.Mock
+}
+
+func (m *mockUserRepo) CreateUser(ctx context.Context, u user.User) (string, error) {
+	args := m.Called(ctx, u)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockUserRepo) FindUserByEmail(ctx context.Context, email string) (user.User, error) {
+	args := m.Called(ctx, email)
+	return args.Get(0).(user.User), args.Error(1)
+}
+
+type mockNotifier struct {
+	mock.Mock
+}
+
+func (m *mockNotifier) SendInvitation(ctx context.Context, inv notifier.Invitation) error {
+	args := m.Called(ctx, inv)
+	return args.Error(0)
+}
+
+var testConfig = config.InviteConfig{TTL: time.Hour, AcceptURL: "http://localhost/accept"}
+
+// openPolicy accepts any password, for tests that are not about the policy.
+var openPolicy, _ = user.NewPasswordPolicy(config.PasswordPolicyConfig{})
+
+var testHasher, _ = passhash.NewHasher(config.PasswordHashConfig{Algorithm: passhash.AlgorithmBcrypt, BcryptCost: bcrypt.MinCost})
+
+func adminContext(tenantID string) context.Context {
+	return auth.WithClaims(context.Background(), &auth.Claims{UserID: "admin1", TenantID: tenantID, Role: auth.RoleAdmin})
+}
+
+func pendingInvite() invite.Invite {
+	return invite.Invite{
+		ID:        primitive.NewObjectID(),
+		TenantID:  "t1",
+		Email:     "new@example.com",
+		Role:      auth.RoleUser,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+func TestUsecaseCreateInvite(t *testing.T) {
+	repo, userRepo, n := new(mockRepo), new(mockUserRepo), new(mockNotifier)
+	uc := invite.NewUsecase(testConfig, openPolicy, testHasher, repo, userRepo, n)
+
+	var stored invite.Invite
+	userRepo.On("FindUserByEmail", mock.Anything, "new@example.com").Return(user.User{}, user.ErrUserOrPasswordIsWrong)
+	repo.On("CreateInvite", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(invite.Invite)
+	}).Return("inv1", nil)
+	n.On("SendInvitation", mock.Anything, mock.Anything).Return(nil)
+
+	resp, err := uc.CreateInvite(adminContext("t1"), invite.CreateRequest{TenantID: "other", Email: "new@example.com"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "inv1", resp.Data.(invite.CreateResponse).Id)
+	assert.Equal(t, "t1", stored.TenantID)
+	assert.Equal(t, auth.RoleUser, stored.Role)
+	assert.Equal(t, "admin1", stored.InvitedBy)
+
+	sent := n.Calls[0].Arguments.Get(1).(notifier.Invitation)
+	assert.NotEmpty(t, sent.Token)
+	assert.Equal(t, opaque.Hash(sent.Token), stored.TokenHash)
+	assert.Equal(t, testConfig.AcceptURL+"?token="+url.QueryEscape(sent.Token), sent.AcceptURL)
+	assert.Equal(t, stored.ExpiresAt, sent.ExpiresAt)
+}
+
+func TestUsecaseCreateInvite_UserExists(t *testing.T) {
+	repo, userRepo, n := new(mockRepo), new(mockUserRepo), new(mockNotifier)
+	uc := invite.NewUsecase(testConfig, openPolicy, testHasher, repo, userRepo, n)
+
+	userRepo.On("FindUserByEmail", mock.Anything, "old@example.com").Return(user.User{Email: "old@example.com"}, nil)
+
+	resp, err := uc.CreateInvite(adminContext("t1"), invite.CreateRequest{Email: "old@example.com"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.DuplicatedRegistration(), resp)
+	repo.AssertNotCalled(t, "CreateInvite", mock.Anything, mock.Anything)
+	n.AssertNotCalled(t, "SendInvitation", mock.Anything, mock.Anything)
+}
+
+func TestUsecaseAcceptInvite(t *testing.T) {
+	repo, userRepo := new(mockRepo), new(mockUserRepo)
+	uc := invite.NewUsecase(testConfig, openPolicy, testHasher, repo, userRepo, new(mockNotifier))
+
+	inv := pendingInvite()
+	repo.On("FindInviteByTokenHash", mock.Anything, opaque.Hash("tok")).Return(inv, nil)
+	repo.On("MarkAccepted", mock.Anything, inv.ID).Return(int64(1), nil)
+	userRepo.On("CreateUser", mock.MatchedBy(func(ctx context.Context) bool {
+		tenantID, scoped := auth.TenantFromContext(ctx)
+		return scoped && tenantID == "t1"
+	}), mock.MatchedBy(func(u user.User) bool {
+		return u.Email == inv.Email && u.TenantID == "t1" && u.Role == auth.RoleUser && u.Password != "secret"
+	})).Return("u1", nil)
+
+	resp, err := uc.AcceptInvite(context.Background(), invite.AcceptRequest{Token: "tok", Name: "New", Password: "secret"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "u1", resp.Data.(invite.AcceptResponse).UserId)
+	repo.AssertNotCalled(t, "UnmarkAccepted", mock.Anything, mock.Anything)
+}
+
+func TestUsecaseAcceptInvite_WeakPassword(t *testing.T) {
+	repo, userRepo := new(mockRepo), new(mockUserRepo)
+	policy, err := user.NewPasswordPolicy(config.PasswordPolicyConfig{MinLength: 8})
+	assert.NoError(t, err)
+	uc := invite.NewUsecase(testConfig, policy, testHasher, repo, userRepo, new(mockNotifier))
+	repo.On("FindInviteByTokenHash", mock.Anything, opaque.Hash("tok")).Return(pendingInvite(), nil)
+
+	resp, err := uc.AcceptInvite(context.Background(), invite.AcceptRequest{Token: "tok", Name: "New", Password: "secret"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "4016", resp.Code)
+	repo.AssertNotCalled(t, "MarkAccepted", mock.Anything, mock.Anything)
+	userRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+}
+
+func TestUsecaseAcceptInvite_Invalid(t *testing.T) {
+	now := time.Now()
+	expired := pendingInvite()
+	expired.ExpiresAt = now.Add(-time.Minute)
+	revoked := pendingInvite()
+	revoked.RevokedAt = &now
+
+	tests := []struct {
+		name   string
+		invite invite.Invite
+		err    error
+	}{
+		{"Unknown token", invite.Invite{}, invite.ErrInviteNotFound},
+		{"Expired", expired, nil},
+		{"Revoked", revoked, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, userRepo := new(mockRepo), new(mockUserRepo)
+			uc := invite.NewUsecase(testConfig, openPolicy, testHasher, repo, userRepo, new(mockNotifier))
+			repo.On("FindInviteByTokenHash", mock.Anything, mock.Anything).Return(tt.invite, tt.err)
+
+			resp, err := uc.AcceptInvite(context.Background(), invite.AcceptRequest{Token: "tok", Name: "New", Password: "secret"})
+
+			assert.NoError(t, err)
+			assert.Equal(t, response.InvalidInvite(), resp)
+			userRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUsecaseAcceptInvite_AlreadyClaimed(t *testing.T) {
+	repo, userRepo := new(mockRepo), new(mockUserRepo)
+	uc := invite.NewUsecase(testConfig, openPolicy, testHasher, repo, userRepo, new(mockNotifier))
+
+	inv := pendingInvite()
+	repo.On("FindInviteByTokenHash", mock.Anything, mock.Anything).Return(inv, nil)
+	repo.On("MarkAccepted", mock.Anything, inv.ID).Return(int64(0), nil)
+
+	resp, err := uc.AcceptInvite(context.Background(), invite.AcceptRequest{Token: "tok", Name: "New", Password: "secret"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.InvalidInvite(), resp)
+	userRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+}
+
+func TestUsecaseAcceptInvite_CreateUserFails(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantResp *response.StdResp[any]
+		wantErr  bool
+	}{
+		{"Email taken", user.ErrEmailAlreadyExists, response.DuplicatedRegistration(), false},
+		{"Database error", errors.New("db down"), nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, userRepo := new(mockRepo), new(mockUserRepo)
+			uc := invite.NewUsecase(testConfig, openPolicy, testHasher, repo, userRepo, new(mockNotifier))
+
+			inv := pendingInvite()
+			repo.On("FindInviteByTokenHash", mock.Anything, mock.Anything).Return(inv, nil)
+			repo.On("MarkAccepted", mock.Anything, inv.ID).Return(int64(1), nil)
+			repo.On("UnmarkAccepted", mock.Anything, inv.ID).Return(nil)
+			userRepo.On("CreateUser", mock.Anything, mock.Anything).Return("", tt.err)
+
+			resp, err := uc.AcceptInvite(context.Background(), invite.AcceptRequest{Token: "tok", Name: "New", Password: "secret"})
+
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.wantResp, resp)
+			repo.AssertCalled(t, "UnmarkAccepted", mock.Anything, inv.ID)
+		})
+	}
+}
+
+func TestUsecaseFindInvites(t *testing.T) {
+	repo := new(mockRepo)
+	uc := invite.NewUsecase(testConfig, openPolicy, testHasher, repo, new(mockUserRepo), new(mockNotifier))
+
+	page := pagination.Request{Page: 1, Limit: 10}
+	repo.On("FindInvites", mock.Anything, invite.StatusPending, page).Return([]invite.Invite{pendingInvite()}, int64(1), nil)
+
+	resp, err := uc.FindInvites(adminContext("t1"), invite.StatusPending, page)
+
+	assert.NoError(t, err)
+	result := resp.Data.(pagination.Result[invite.FindInviteResponse])
+	assert.Len(t, result.Items, 1)
+	assert.Equal(t, invite.StatusPending, result.Items[0].Status)
+}
+
+func TestUsecaseRevokeInvite(t *testing.T) {
+	repo := new(mockRepo)
+	uc := invite.NewUsecase(testConfig, openPolicy, testHasher, repo, new(mockUserRepo), new(mockNotifier))
+
+	inv := pendingInvite()
+	repo.On("FindInviteById", mock.Anything, inv.ID.Hex()).Return(inv, nil)
+	repo.On("RevokeInvite", mock.Anything, inv.ID).Return(int64(1), nil)
+
+	resp, err := uc.RevokeInvite(adminContext("t1"), inv.ID.Hex())
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.Success(), resp)
+}
+
+func TestUsecaseRevokeInvite_NotPending(t *testing.T) {
+	repo := new(mockRepo)
+	uc := invite.NewUsecase(testConfig, openPolicy, testHasher, repo, new(mockUserRepo), new(mockNotifier))
+
+	inv := pendingInvite()
+	now := time.Now()
+	inv.AcceptedAt = &now
+	repo.On("FindInviteById", mock.Anything, inv.ID.Hex()).Return(inv, nil)
+
+	resp, err := uc.RevokeInvite(adminContext("t1"), inv.ID.Hex())
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.InvalidInvite(), resp)
+	repo.AssertNotCalled(t, "RevokeInvite", mock.Anything, mock.Anything)
+}
+
+func TestUsecaseRevokeInvite_NotFound(t *testing.T) {
+	repo := new(mockRepo)
+	uc := invite.NewUsecase(testConfig, openPolicy, testHasher, repo, new(mockUserRepo), new(mockNotifier))
+
+	id := primitive.NewObjectID().Hex()
+	repo.On("FindInviteById", mock.Anything, id).Return(invite.Invite{}, invite.ErrInviteNotFound)
+
+	resp, err := uc.RevokeInvite(adminContext("t1"), id)
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.InviteNotFound(), resp)
+}
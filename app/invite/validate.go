@@ -0,0 +1,66 @@
+package invite
+
+import (
+	"net/mail"
+	"strings"
+	"user-management/auth"
+	"user-management/response"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (r CreateRequest) RequestValidation() *response.StdResp[any] {
+	if checkLen(r.Email) == 0 {
+		return response.MandatoryMissing("email")
+	}
+	if !isValidEmail(r.Email) {
+		return response.InvalidData("email")
+	}
+	if r.Role != "" && r.Role != auth.RoleAdmin && r.Role != auth.RoleUser {
+		return response.InvalidData("role")
+	}
+	if r.TenantID != "" {
+		if _, err := primitive.ObjectIDFromHex(r.TenantID); err != nil {
+			return response.InvalidData("tenant_id")
+		}
+	}
+	return response.Success()
+}
+
+func (r AcceptRequest) RequestValidation() *response.StdResp[any] {
+	if checkLen(r.Token) == 0 {
+		return response.MandatoryMissing("token")
+	}
+	if checkLen(r.Name) == 0 {
+		return response.MandatoryMissing("name")
+	}
+	if checkLen(r.Password) == 0 {
+		return response.MandatoryMissing("password")
+	}
+	return response.Success()
+}
+
+func StatusValidation(status string) *response.StdResp[any] {
+	switch status {
+	case "", StatusPending, StatusAccepted, StatusRevoked, StatusExpired:
+		return response.Success()
+	}
+	return response.InvalidData(QueryStatus)
+}
+
+func IdValidation(id string) *response.StdResp[any] {
+	_, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return response.InvalidData(ParamID)
+	}
+	return response.Success()
+}
+
+func checkLen(s string) int {
+	return len([]rune(strings.TrimSpace(s)))
+}
+
+func isValidEmail(email string) bool {
+	_, err := mail.ParseAddress(email)
+	return err == nil
+}
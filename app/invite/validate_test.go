@@ -0,0 +1,41 @@
+package invite_test
+
+import (
+	"testing"
+	"user-management/app/invite"
+	"user-management/response"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateRequest_RequestValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    invite.CreateRequest
+		wantCode *response.StdResp[any]
+	}{
+		{"Valid", invite.CreateRequest{Email: "a@example.com"}, response.Success()},
+		{"Missing email", invite.CreateRequest{}, response.MandatoryMissing("email")},
+		{"Invalid email", invite.CreateRequest{Email: "nope"}, response.InvalidData("email")},
+		{"Invalid role", invite.CreateRequest{Email: "a@example.com", Role: "root"}, response.InvalidData("role")},
+		{"Invalid tenant", invite.CreateRequest{Email: "a@example.com", TenantID: "t1"}, response.InvalidData("tenant_id")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantCode, tt.input.RequestValidation())
+		})
+	}
+}
+
+func TestAcceptRequest_RequestValidation(t *testing.T) {
+	assert.Equal(t, response.Success(), invite.AcceptRequest{Token: "t", Name: "n", Password: "p"}.RequestValidation())
+	assert.Equal(t, response.MandatoryMissing("token"), invite.AcceptRequest{Name: "n", Password: "p"}.RequestValidation())
+	assert.Equal(t, response.MandatoryMissing("password"), invite.AcceptRequest{Token: "t", Name: "n"}.RequestValidation())
+}
+
+func TestStatusValidation(t *testing.T) {
+	assert.Equal(t, response.Success(), invite.StatusValidation(""))
+	assert.Equal(t, response.Success(), invite.StatusValidation(invite.StatusExpired))
+	assert.Equal(t, response.InvalidData(invite.QueryStatus), invite.StatusValidation("unknown"))
+}
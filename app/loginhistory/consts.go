@@ -0,0 +1,14 @@
+package loginhistory
+
+const (
+	ParamID = "id"
+)
+
+// Reasons a login attempt failed, as stored in Attempt.Reason.
+const (
+	ReasonUnknownTenant   = "unknown_tenant"
+	ReasonTenantDisabled  = "tenant_disabled"
+	ReasonUnknownUser     = "unknown_user"
+	ReasonWrongPassword   = "wrong_password"
+	ReasonAccountInactive = "account_inactive"
+)
@@ -0,0 +1,75 @@
+package loginhistory
+
+import (
+	"context"
+	"user-management/logger"
+	"user-management/pagination"
+	"user-management/response"
+
+	echo "github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Usecase interface {
+	FindLogins(ctx context.Context, userID string, page pagination.Request) (*response.StdResp[any], error)
+	FindMyLogins(ctx context.Context, page pagination.Request) (*response.StdResp[any], error)
+}
+
+type Handler interface {
+	FindLogins(c echo.Context) error
+	FindMyLogins(c echo.Context) error
+}
+
+type handler struct {
+	usecase Usecase
+}
+
+func NewHandler(u Usecase) *handler {
+	return &handler{
+		usecase: u,
+	}
+}
+
+// FindLogins serves GET /users/:id/logins.
+func (h *handler) FindLogins(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	userID := c.Param(ParamID)
+	if !primitive.IsValidObjectID(userID) {
+		return c.JSON(response.InvalidData(ParamID).WithHTTPStatus())
+	}
+	page, respValidate := pagination.Parse(c.QueryParam(pagination.QueryPage), c.QueryParam(pagination.QueryLimit))
+	if !respValidate.IsSuccess() {
+		return c.JSON(respValidate.WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.FindLogins(ctx, userID, page)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+// FindMyLogins serves GET /me/logins for the caller.
+func (h *handler) FindMyLogins(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	page, respValidate := pagination.Parse(c.QueryParam(pagination.QueryPage), c.QueryParam(pagination.QueryLimit))
+	if !respValidate.IsSuccess() {
+		return c.JSON(respValidate.WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.FindMyLogins(ctx, page)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
@@ -0,0 +1,89 @@
+package loginhistory_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-management/app/loginhistory"
+	"user-management/logger"
+	"user-management/pagination"
+	"user-management/response"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockUsecase struct {
+	mock.Mock
+}
+
+func (m *mockUsecase) FindLogins(ctx context.Context, userID string, page pagination.Request) (*response.StdResp[any], error) {
+	args := m.Called(ctx, userID, page)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) FindMyLogins(ctx context.Context, page pagination.Request) (*response.StdResp[any], error) {
+	args := m.Called(ctx, page)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func newTestContext(method, target string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(method, target, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	ctx := context.WithValue(c.Request().Context(), logger.LogContext, logger.NewZap())
+	c.SetRequest(req.WithContext(ctx))
+	return c, rec
+}
+
+func TestHandlerFindLogins(t *testing.T) {
+	id := primitive.NewObjectID().Hex()
+	c, rec := newTestContext(http.MethodGet, "/users/"+id+"/logins?limit=5")
+	c.SetParamNames(loginhistory.ParamID)
+	c.SetParamValues(id)
+
+	mockUc := new(mockUsecase)
+	page := pagination.Request{Page: 1, Limit: 5}
+	mockUc.On("FindLogins", mock.Anything, id, page).Return(response.SuccessWithData(pagination.NewResult([]loginhistory.Attempt{}, page, 0)), nil)
+
+	err := loginhistory.NewHandler(mockUc).FindLogins(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUc.AssertExpectations(t)
+}
+
+func TestHandlerFindLogins_InvalidID(t *testing.T) {
+	c, rec := newTestContext(http.MethodGet, "/users/nope/logins")
+	c.SetParamNames(loginhistory.ParamID)
+	c.SetParamValues("nope")
+
+	err := loginhistory.NewHandler(new(mockUsecase)).FindLogins(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), response.InvalidData(loginhistory.ParamID).Message)
+}
+
+func TestHandlerFindMyLogins(t *testing.T) {
+	c, rec := newTestContext(http.MethodGet, "/me/logins?page=2")
+
+	mockUc := new(mockUsecase)
+	page := pagination.Request{Page: 2, Limit: pagination.DefaultLimit}
+	mockUc.On("FindMyLogins", mock.Anything, page).Return(response.SuccessWithData(pagination.NewResult([]loginhistory.Attempt{}, page, 0)), nil)
+
+	err := loginhistory.NewHandler(mockUc).FindMyLogins(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUc.AssertExpectations(t)
+}
+
+func TestHandlerFindMyLogins_InvalidLimit(t *testing.T) {
+	c, rec := newTestContext(http.MethodGet, "/me/logins?limit=1000")
+
+	err := loginhistory.NewHandler(new(mockUsecase)).FindMyLogins(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,26 @@
+package loginhistory
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Attempt is one call to Login. UserID is empty when no user matched the
+// email; Reason is empty for successful attempts. Provider names the external
+// identity provider of a federated login and is empty for password logins.
+type Attempt struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TenantID  string             `bson:"tenant_id,omitempty" json:"tenant_id,omitempty"`
+	UserID    string             `bson:"user_id,omitempty" json:"user_id,omitempty"`
+	Email     string             `bson:"email" json:"email"`
+	Success   bool               `bson:"success" json:"success"`
+	Reason    string             `bson:"reason,omitempty" json:"reason,omitempty"`
+	Provider  string             `bson:"provider,omitempty" json:"provider,omitempty"`
+	IP        string             `bson:"ip,omitempty" json:"ip,omitempty"`
+	UserAgent string             `bson:"user_agent,omitempty" json:"user_agent,omitempty"`
+	Protocol  string             `bson:"protocol,omitempty" json:"protocol,omitempty"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	// ExpiresAt is when the TTL index removes the attempt.
+	ExpiresAt time.Time `bson:"expires_at" json:"-"`
+}
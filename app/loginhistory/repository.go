@@ -0,0 +1,68 @@
+package loginhistory
+
+import (
+	"context"
+	"user-management/config"
+	"user-management/pagination"
+	"user-management/storage"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type repository struct {
+	mc  storage.DatabaseConn
+	cfg config.MongoConfig
+}
+
+func NewRepository(mc storage.DatabaseConn, cfg config.MongoConfig) *repository {
+	return &repository{
+		mc:  mc,
+		cfg: cfg,
+	}
+}
+
+func (r *repository) CreateAttempt(ctx context.Context, attempt Attempt) error {
+	_, err := r.mc.Collection(r.cfg.LoginHistoryCollection).InsertOne(ctx, attempt)
+	return err
+}
+
+// FindAllAttemptsByUser lists every attempt of the user, newest first.
+func (r *repository) FindAllAttemptsByUser(ctx context.Context, userID string) ([]Attempt, error) {
+	filter := storage.TenantFilter(ctx, bson.M{"user_id": userID})
+	opts := options.Find().SetSort(bson.D{bson.E{Key: "created_at", Value: -1}})
+	cursor, err := r.mc.Collection(r.cfg.LoginHistoryCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var attempts []Attempt
+	err = cursor.All(ctx, &attempts)
+	return attempts, err
+}
+
+// DeleteAttemptsByUser removes every attempt of the user.
+func (r *repository) DeleteAttemptsByUser(ctx context.Context, userID string) (int64, error) {
+	result, err := r.mc.Collection(r.cfg.LoginHistoryCollection).DeleteMany(ctx, storage.TenantFilter(ctx, bson.M{"user_id": userID}))
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// FindAttemptsByUser lists the user's attempts, newest first.
+func (r *repository) FindAttemptsByUser(ctx context.Context, userID string, page pagination.Request) ([]Attempt, int64, error) {
+	filter := storage.TenantFilter(ctx, bson.M{"user_id": userID})
+	total, err := r.mc.Collection(r.cfg.LoginHistoryCollection).CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	sort := bson.D{bson.E{Key: "created_at", Value: -1}}
+	opts := options.Find().SetSort(sort).SetSkip(page.Skip()).SetLimit(page.Limit)
+	cursor, err := r.mc.Collection(r.cfg.LoginHistoryCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	var attempts []Attempt
+	err = cursor.All(ctx, &attempts)
+	return attempts, total, err
+}
@@ -0,0 +1,127 @@
+package loginhistory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-management/app/loginhistory"
+	"user-management/auth"
+	"user-management/config"
+	"user-management/pagination"
+	"user-management/storage"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// testRepository also covers the lookups and deletes done for privacy requests.
+type testRepository interface {
+	loginhistory.Repository
+	FindAllAttemptsByUser(ctx context.Context, userID string) ([]loginhistory.Attempt, error)
+	DeleteAttemptsByUser(ctx context.Context, userID string) (int64, error)
+}
+
+func newTestRepository(mt *mtest.T) testRepository {
+	dbConn := storage.NewMongoConn(mt.Client, mt.Client.Database("testdb"))
+	return loginhistory.NewRepository(dbConn, config.MongoConfig{
+		Database:               "testdb",
+		LoginHistoryCollection: "login_history",
+	})
+}
+
+func TestRepository_CreateAttempt(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("success", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		err := repo.CreateAttempt(context.Background(), loginhistory.Attempt{Email: "a@example.com", ExpiresAt: time.Now()})
+
+		assert.NoError(t, err)
+		doc := mt.GetStartedEvent().Command.Lookup("documents").Array().Index(0).Value().Document()
+		assert.Equal(t, "a@example.com", doc.Lookup("email").StringValue())
+		assert.Equal(t, bson.TypeDateTime, doc.Lookup("expires_at").Type)
+	})
+}
+
+func TestRepository_FindAttemptsByUser(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("newest first and tenant scoped", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		oid := primitive.NewObjectID()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(1, "testdb.login_history", mtest.FirstBatch, bson.D{
+				bson.E{Key: "n", Value: int64(1)},
+			}),
+			mtest.CreateCursorResponse(0, "testdb.login_history", mtest.FirstBatch, bson.D{
+				bson.E{Key: "_id", Value: oid},
+				bson.E{Key: "tenant_id", Value: "t1"},
+				bson.E{Key: "user_id", Value: "u1"},
+				bson.E{Key: "success", Value: false},
+				bson.E{Key: "reason", Value: loginhistory.ReasonWrongPassword},
+			}),
+		)
+
+		ctx := auth.WithTenant(context.Background(), "t1")
+		attempts, total, err := repo.FindAttemptsByUser(ctx, "u1", pagination.Request{Page: 2, Limit: 10})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Len(t, attempts, 1)
+		assert.Equal(t, oid, attempts[0].ID)
+		assert.Equal(t, loginhistory.ReasonWrongPassword, attempts[0].Reason)
+		mt.GetStartedEvent() // count
+		find := mt.GetStartedEvent().Command
+		filter := find.Lookup("filter").Document()
+		assert.Equal(t, "t1", filter.Lookup("tenant_id").StringValue())
+		assert.Equal(t, "u1", filter.Lookup("user_id").StringValue())
+		assert.Equal(t, int32(-1), find.Lookup("sort").Document().Lookup("created_at").Int32())
+		assert.Equal(t, int64(10), find.Lookup("skip").Int64())
+	})
+}
+
+func TestRepository_FindAllAttemptsByUser(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("newest first and tenant scoped", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.login_history", mtest.FirstBatch, bson.D{
+			bson.E{Key: "_id", Value: primitive.NewObjectID()},
+			bson.E{Key: "user_id", Value: "u1"},
+			bson.E{Key: "success", Value: true},
+		}))
+
+		attempts, err := repo.FindAllAttemptsByUser(auth.WithTenant(context.Background(), "t1"), "u1")
+
+		assert.NoError(t, err)
+		assert.Len(t, attempts, 1)
+		find := mt.GetStartedEvent().Command
+		filter := find.Lookup("filter").Document()
+		assert.Equal(t, "t1", filter.Lookup("tenant_id").StringValue())
+		assert.Equal(t, "u1", filter.Lookup("user_id").StringValue())
+		assert.Equal(t, int32(-1), find.Lookup("sort").Document().Lookup("created_at").Int32())
+		_, err = find.LookupErr("limit")
+		assert.Error(t, err)
+	})
+}
+
+func TestRepository_DeleteAttemptsByUser(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("tenant scoped", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 3}})
+
+		deleted, err := repo.DeleteAttemptsByUser(auth.WithTenant(context.Background(), "t1"), "u1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), deleted)
+		filter := mt.GetStartedEvent().Command.Lookup("deletes").Array().Index(0).Value().Document().Lookup("q").Document()
+		assert.Equal(t, "t1", filter.Lookup("tenant_id").StringValue())
+		assert.Equal(t, "u1", filter.Lookup("user_id").StringValue())
+	})
+}
@@ -0,0 +1,64 @@
+package loginhistory
+
+import (
+	"context"
+	"time"
+	"user-management/auth"
+	"user-management/config"
+	"user-management/pagination"
+	"user-management/response"
+)
+
+type Repository interface {
+	CreateAttempt(ctx context.Context, attempt Attempt) error
+	FindAttemptsByUser(ctx context.Context, userID string, page pagination.Request) ([]Attempt, int64, error)
+}
+
+type usecase struct {
+	cfg  config.LoginHistoryConfig
+	repo Repository
+}
+
+func NewUsecase(cfg config.LoginHistoryConfig, r Repository) *usecase {
+	return &usecase{
+		cfg:  cfg,
+		repo: r,
+	}
+}
+
+// RecordLogin stores attempt, stamped with the current time and the expiry
+// the retention allows.
+func (u *usecase) RecordLogin(ctx context.Context, attempt Attempt) error {
+	attempt.CreatedAt = time.Now()
+	attempt.ExpiresAt = attempt.CreatedAt.Add(u.cfg.Retention)
+	return u.repo.CreateAttempt(ctx, attempt)
+}
+
+// FindLogins lists the attempts of another user for admins, or the caller's
+// own attempts for anyone.
+func (u *usecase) FindLogins(ctx context.Context, userID string, page pagination.Request) (*response.StdResp[any], error) {
+	claims, err := auth.FromContext(ctx)
+	if err != nil {
+		return response.Unauthorized(), nil
+	}
+	if !claims.IsAdmin() && claims.UserID != userID {
+		return response.Forbidden(), nil
+	}
+	return u.findLogins(ctx, userID, page)
+}
+
+func (u *usecase) FindMyLogins(ctx context.Context, page pagination.Request) (*response.StdResp[any], error) {
+	claims, err := auth.FromContext(ctx)
+	if err != nil || claims.UserID == "" {
+		return response.Unauthorized(), nil
+	}
+	return u.findLogins(ctx, claims.UserID, page)
+}
+
+func (u *usecase) findLogins(ctx context.Context, userID string, page pagination.Request) (*response.StdResp[any], error) {
+	attempts, total, err := u.repo.FindAttemptsByUser(ctx, userID, page)
+	if err != nil {
+		return nil, err
+	}
+	return response.SuccessWithData(pagination.NewResult(attempts, page, total)), nil
+}
@@ -0,0 +1,102 @@
+package loginhistory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-management/app/loginhistory"
+	"user-management/auth"
+	"user-management/config"
+	"user-management/pagination"
+	"user-management/response"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockRepo struct {
+	mock.Mock
+}
+
+func (m *mockRepo) CreateAttempt(ctx context.Context, attempt loginhistory.Attempt) error {
+	args := m.Called(ctx, attempt)
+	return args.Error(0)
+}
+
+func (m *mockRepo) FindAttemptsByUser(ctx context.Context, userID string, page pagination.Request) ([]loginhistory.Attempt, int64, error) {
+	args := m.Called(ctx, userID, page)
+	return args.Get(0).([]loginhistory.Attempt), args.Get(1).(int64), args.Error(2)
+}
+
+var testConfig = config.LoginHistoryConfig{Retention: 24 * time.Hour}
+
+func claimsContext(userID, role string) context.Context {
+	return auth.WithClaims(context.Background(), &auth.Claims{UserID: userID, TenantID: "t1", Role: role})
+}
+
+func TestUsecaseRecordLogin(t *testing.T) {
+	repo := new(mockRepo)
+	uc := loginhistory.NewUsecase(testConfig, repo)
+
+	repo.On("CreateAttempt", mock.Anything, mock.MatchedBy(func(a loginhistory.Attempt) bool {
+		return a.Email == "a@example.com" && !a.CreatedAt.IsZero() && a.ExpiresAt.Equal(a.CreatedAt.Add(24*time.Hour))
+	})).Return(nil)
+
+	err := uc.RecordLogin(context.Background(), loginhistory.Attempt{Email: "a@example.com"})
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestUsecaseFindLogins(t *testing.T) {
+	page := pagination.Request{Page: 1, Limit: 10}
+	attempts := []loginhistory.Attempt{{UserID: "u2", Email: "b@example.com", Success: true}}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want *response.StdResp[any]
+	}{
+		{"admin", claimsContext("u1", auth.RoleAdmin), response.SuccessWithData(pagination.NewResult(attempts, page, 1))},
+		{"self", claimsContext("u2", auth.RoleUser), response.SuccessWithData(pagination.NewResult(attempts, page, 1))},
+		{"other user", claimsContext("u1", auth.RoleUser), response.Forbidden()},
+		{"anonymous", context.Background(), response.Unauthorized()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mockRepo)
+			repo.On("FindAttemptsByUser", mock.Anything, "u2", page).Return(attempts, int64(1), nil)
+
+			resp, err := loginhistory.NewUsecase(testConfig, repo).FindLogins(tt.ctx, "u2", page)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, resp)
+		})
+	}
+}
+
+func TestUsecaseFindMyLogins(t *testing.T) {
+	repo := new(mockRepo)
+	uc := loginhistory.NewUsecase(testConfig, repo)
+
+	page := pagination.Request{Page: 2, Limit: 5}
+	repo.On("FindAttemptsByUser", mock.Anything, "u1", page).Return([]loginhistory.Attempt(nil), int64(5), nil)
+
+	resp, err := uc.FindMyLogins(claimsContext("u1", auth.RoleUser), page)
+
+	assert.NoError(t, err)
+	result := resp.Data.(pagination.Result[loginhistory.Attempt])
+	assert.Empty(t, result.Items)
+	assert.Equal(t, int64(5), result.Total)
+	repo.AssertExpectations(t)
+}
+
+func TestUsecaseFindMyLogins_NoClaims(t *testing.T) {
+	repo := new(mockRepo)
+
+	resp, err := loginhistory.NewUsecase(testConfig, repo).FindMyLogins(context.Background(), pagination.Request{Page: 1, Limit: 5})
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.Unauthorized(), resp)
+	repo.AssertNotCalled(t, "FindAttemptsByUser", mock.Anything, mock.Anything, mock.Anything)
+}
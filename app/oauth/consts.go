@@ -0,0 +1,15 @@
+package oauth
+
+import "errors"
+
+const (
+	ParamID = "id"
+)
+
+const (
+	GrantTypeClientCredentials = "client_credentials"
+)
+
+var (
+	ErrClientNotFound = errors.New("Client not found")
+)
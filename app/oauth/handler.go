@@ -0,0 +1,173 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"user-management/app/oidc"
+	"user-management/logger"
+	"user-management/response"
+
+	echo "github.com/labstack/echo/v4"
+)
+
+type Usecase interface {
+	CreateClient(ctx context.Context, req CreateClientRequest) (*response.StdResp[any], error)
+	FindClients(ctx context.Context) (*response.StdResp[any], error)
+	FindClientById(ctx context.Context, id string) (*response.StdResp[any], error)
+	DeleteClient(ctx context.Context, id string) (*response.StdResp[any], error)
+	Token(ctx context.Context, req TokenRequest, creds oidc.ClientCredentials) (TokenResponse, error)
+}
+
+type Handler interface {
+	CreateClient(c echo.Context) error
+	FindClients(c echo.Context) error
+	FindClientById(c echo.Context) error
+	DeleteClient(c echo.Context) error
+	Token(c echo.Context) error
+}
+
+type handler struct {
+	usecase Usecase
+}
+
+func NewHandler(u Usecase) *handler {
+	return &handler{
+		usecase: u,
+	}
+}
+
+func (h *handler) CreateClient(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	var request CreateClientRequest
+	err = c.Bind(&request)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Bind request error: %v", err.Error())
+		return c.JSON(response.UnexpectedRequest().WithHTTPStatus())
+	}
+
+	if resp := request.RequestValidation(); !resp.IsSuccess() {
+		return c.JSON(resp.WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.CreateClient(ctx, request)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+func (h *handler) FindClients(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.FindClients(ctx)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+func (h *handler) FindClientById(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	paramId := c.Param(ParamID)
+	if respValidate := IdValidation(paramId); !respValidate.IsSuccess() {
+		return c.JSON(respValidate.WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.FindClientById(ctx, paramId)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+func (h *handler) DeleteClient(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	paramId := c.Param(ParamID)
+	if respValidate := IdValidation(paramId); !respValidate.IsSuccess() {
+		return c.JSON(respValidate.WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.DeleteClient(ctx, paramId)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+func (h *handler) Token(c echo.Context) error {
+	var request TokenRequest
+	if err := c.Bind(&request); err != nil {
+		return h.oauthError(c, &oidc.Error{Code: oidc.ErrorInvalidRequest, Description: response.UnexpectedRequest().Message})
+	}
+	creds, err := clientCredentials(c, request.ClientID, request.ClientSecret)
+	if err != nil {
+		return h.oauthError(c, err)
+	}
+
+	resp, err := h.usecase.Token(c.Request().Context(), request, creds)
+	if err != nil {
+		return h.oauthError(c, err)
+	}
+	noStore(c)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// oauthError answers with err as an OAuth error response. Other errors are
+// logged and hidden behind server_error.
+func (h *handler) oauthError(c echo.Context, err error) error {
+	var oauthErr *oidc.Error
+	if errors.As(err, &oauthErr) {
+		noStore(c)
+		return c.JSON(oauthErr.HTTPStatus(), oauthErr)
+	}
+	if zlog, logErr := logger.FromContext(c.Request().Context()); logErr == nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+	}
+	return c.JSON(http.StatusInternalServerError, &oidc.Error{Code: oidc.ErrorServerError})
+}
+
+// clientCredentials reads the client's credentials from HTTP Basic
+// authentication, falling back to the client_id and client_secret parameters.
+// Basic credentials are form encoded (RFC 6749 section 2.3.1).
+func clientCredentials(c echo.Context, id, secret string) (oidc.ClientCredentials, error) {
+	basicID, basicSecret, ok := c.Request().BasicAuth()
+	if !ok {
+		return oidc.ClientCredentials{ID: id, Secret: secret}, nil
+	}
+	var err error
+	creds := oidc.ClientCredentials{}
+	if creds.ID, err = url.QueryUnescape(basicID); err != nil {
+		return creds, &oidc.Error{Code: oidc.ErrorInvalidClient, Description: "Client authentication failed."}
+	}
+	if creds.Secret, err = url.QueryUnescape(basicSecret); err != nil {
+		return creds, &oidc.Error{Code: oidc.ErrorInvalidClient, Description: "Client authentication failed."}
+	}
+	return creds, nil
+}
+
+func noStore(c echo.Context) {
+	c.Response().Header().Set("Cache-Control", "no-store")
+	c.Response().Header().Set("Pragma", "no-cache")
+}
@@ -0,0 +1,155 @@
+package oauth_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"user-management/app/oauth"
+	"user-management/app/oidc"
+	"user-management/auth"
+	"user-management/logger"
+	"user-management/response"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockUsecase struct {
+	mock.Mock
+}
+
+func (m *mockUsecase) CreateClient(ctx context.Context, req oauth.CreateClientRequest) (*response.StdResp[any], error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) FindClients(ctx context.Context) (*response.StdResp[any], error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) FindClientById(ctx context.Context, id string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) DeleteClient(ctx context.Context, id string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) Token(ctx context.Context, req oauth.TokenRequest, creds oidc.ClientCredentials) (oauth.TokenResponse, error) {
+	args := m.Called(ctx, req, creds)
+	return args.Get(0).(oauth.TokenResponse), args.Error(1)
+}
+
+func newTestContext(req *http.Request) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	ctx := context.WithValue(c.Request().Context(), logger.LogContext, logger.NewZap())
+	c.SetRequest(req.WithContext(ctx))
+	return c, rec
+}
+
+func newJSONContext(method, target string, body any) (echo.Context, *httptest.ResponseRecorder) {
+	j, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, target, bytes.NewReader(j))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	return newTestContext(req)
+}
+
+func newTokenContext(form url.Values) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(form.Encode()))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	return newTestContext(req)
+}
+
+func TestHandlerToken_BasicAuth(t *testing.T) {
+	c, rec := newTokenContext(url.Values{"grant_type": {"client_credentials"}, "scope": {"users:read"}})
+	c.Request().SetBasicAuth("client-1", "s%3Dcret")
+	mockUc := new(mockUsecase)
+	mockUc.On("Token", mock.Anything, oauth.TokenRequest{GrantType: "client_credentials", Scope: "users:read"}, oidc.ClientCredentials{ID: "client-1", Secret: "s=cret"}).
+		Return(oauth.TokenResponse{AccessToken: "jwt", TokenType: "Bearer", ExpiresIn: 900, Scope: "users:read"}, nil)
+
+	err := oauth.NewHandler(mockUc).Token(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+	var resp oauth.TokenResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "jwt", resp.AccessToken)
+}
+
+func TestHandlerToken_FormCredentials(t *testing.T) {
+	c, rec := newTokenContext(url.Values{"grant_type": {"client_credentials"}, "client_id": {"client-1"}, "client_secret": {"secret"}})
+	mockUc := new(mockUsecase)
+	mockUc.On("Token", mock.Anything, mock.Anything, oidc.ClientCredentials{ID: "client-1", Secret: "secret"}).
+		Return(oauth.TokenResponse{}, &oidc.Error{Code: oidc.ErrorInvalidClient, Description: "Client authentication failed."})
+
+	err := oauth.NewHandler(mockUc).Token(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	var resp oidc.Error
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, oidc.ErrorInvalidClient, resp.Code)
+}
+
+func TestHandlerToken_ServiceError(t *testing.T) {
+	c, rec := newTokenContext(url.Values{"grant_type": {"client_credentials"}})
+	mockUc := new(mockUsecase)
+	mockUc.On("Token", mock.Anything, mock.Anything, mock.Anything).Return(oauth.TokenResponse{}, errors.New("db down"))
+
+	err := oauth.NewHandler(mockUc).Token(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, rec.Body.String(), oidc.ErrorServerError)
+}
+
+func TestHandlerCreateClient_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		req  oauth.CreateClientRequest
+	}{
+		{"missing name", oauth.CreateClientRequest{Scopes: []string{auth.ScopeUsersRead}}},
+		{"missing scopes", oauth.CreateClientRequest{Name: "Billing"}},
+		{"unknown scope", oauth.CreateClientRequest{Name: "Billing", Scopes: []string{"openid"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newJSONContext(http.MethodPost, "/oauth/clients", tt.req)
+			mockUc := new(mockUsecase)
+
+			err := oauth.NewHandler(mockUc).CreateClient(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			mockUc.AssertNotCalled(t, "CreateClient")
+		})
+	}
+}
+
+func TestHandlerDeleteClient_InvalidId(t *testing.T) {
+	req := httptest.NewRequest(http.MethodDelete, "/oauth/clients/bad", nil)
+	c, rec := newTestContext(req)
+	c.SetParamNames(oauth.ParamID)
+	c.SetParamValues("bad")
+	mockUc := new(mockUsecase)
+
+	err := oauth.NewHandler(mockUc).DeleteClient(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUc.AssertNotCalled(t, "DeleteClient")
+}
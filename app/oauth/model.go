@@ -0,0 +1,59 @@
+package oauth
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Client is a service that gets access tokens with its own credentials,
+// through the client_credentials grant. Its id is the OAuth client_id and only
+// its secret's hash is stored. Its tokens act as an admin of TenantID, limited
+// to the scopes they were granted out of Scopes.
+type Client struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty"`
+	TenantID   string             `bson:"tenant_id,omitempty"`
+	Name       string             `bson:"name"`
+	SecretHash string             `bson:"secret_hash"`
+	Scopes     []string           `bson:"scopes"`
+	CreatedBy  string             `bson:"created_by,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at"`
+}
+
+type CreateClientRequest struct {
+	TenantID string   `json:"tenant_id"`
+	Name     string   `json:"name"`
+	Scopes   []string `json:"scopes"`
+}
+
+// CreateClientResponse carries the client secret, which is shown only once.
+type CreateClientResponse struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+}
+
+type FindClientResponse struct {
+	ClientID  string    `json:"client_id"`
+	TenantID  string    `json:"tenant_id,omitempty"`
+	Name      string    `json:"name"`
+	Scopes    []string  `json:"scopes"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TokenRequest is a client credentials token request (RFC 6749 section 4.4).
+// Scope lists the scopes wanted, separated by spaces; all of the client's
+// scopes are granted when it is empty.
+type TokenRequest struct {
+	GrantType    string `form:"grant_type"`
+	Scope        string `form:"scope"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+}
+
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Scope       string `json:"scope"`
+}
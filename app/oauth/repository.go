@@ -0,0 +1,67 @@
+package oauth
+
+import (
+	"context"
+	"time"
+	"user-management/config"
+	"user-management/storage"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type repository struct {
+	mc  storage.DatabaseConn
+	cfg config.MongoConfig
+}
+
+func NewRepository(mc storage.DatabaseConn, cfg config.MongoConfig) *repository {
+	return &repository{
+		mc:  mc,
+		cfg: cfg,
+	}
+}
+
+func (r *repository) CreateClient(ctx context.Context, client Client) (string, error) {
+	client.CreatedAt = time.Now()
+	ior, err := r.mc.Collection(r.cfg.OAuthClientCollection).InsertOne(ctx, client)
+	if err != nil {
+		return "", err
+	}
+	return ior.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+func (r *repository) FindClientById(ctx context.Context, id string) (Client, error) {
+	var client Client
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return client, ErrClientNotFound
+	}
+	err = r.mc.Collection(r.cfg.OAuthClientCollection).FindOne(ctx, storage.TenantFilter(ctx, bson.M{"_id": oid})).Decode(&client)
+	if err == mongo.ErrNoDocuments {
+		return client, ErrClientNotFound
+	}
+	return client, err
+}
+
+// FindClients lists the clients of ctx's tenant, oldest first.
+func (r *repository) FindClients(ctx context.Context) ([]Client, error) {
+	opts := options.Find().SetSort(bson.D{bson.E{Key: "created_at", Value: 1}})
+	cursor, err := r.mc.Collection(r.cfg.OAuthClientCollection).Find(ctx, storage.TenantFilter(ctx, bson.M{}), opts)
+	if err != nil {
+		return nil, err
+	}
+	var clients []Client
+	err = cursor.All(ctx, &clients)
+	return clients, err
+}
+
+func (r *repository) DeleteClient(ctx context.Context, id primitive.ObjectID) (int64, error) {
+	result, err := r.mc.Collection(r.cfg.OAuthClientCollection).DeleteOne(ctx, storage.TenantFilter(ctx, bson.M{"_id": id}))
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
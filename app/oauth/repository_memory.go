@@ -0,0 +1,83 @@
+package oauth
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+	"user-management/auth"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryRepository keeps clients in process memory, for when the service runs
+// without a database.
+type memoryRepository struct {
+	mu      sync.Mutex
+	clients []Client
+}
+
+func NewMemoryRepository() *memoryRepository {
+	return &memoryRepository{}
+}
+
+func (r *memoryRepository) CreateClient(ctx context.Context, client Client) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if client.ID.IsZero() {
+		client.ID = primitive.NewObjectID()
+	}
+	client.CreatedAt = time.Now()
+	client.Scopes = slices.Clone(client.Scopes)
+	r.clients = append(r.clients, client)
+	return client.ID.Hex(), nil
+}
+
+func (r *memoryRepository) FindClientById(ctx context.Context, id string) (Client, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Client{}, ErrClientNotFound
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i := r.indexOf(ctx, oid); i >= 0 {
+		return r.clients[i], nil
+	}
+	return Client{}, ErrClientNotFound
+}
+
+func (r *memoryRepository) FindClients(ctx context.Context) ([]Client, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var clients []Client
+	for _, c := range r.clients {
+		if inTenant(ctx, c) {
+			clients = append(clients, c)
+		}
+	}
+	return clients, nil
+}
+
+func (r *memoryRepository) DeleteClient(ctx context.Context, id primitive.ObjectID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.indexOf(ctx, id)
+	if i < 0 {
+		return 0, nil
+	}
+	r.clients = slices.Delete(r.clients, i, i+1)
+	return 1, nil
+}
+
+// indexOf finds client id in ctx's tenant. r.mu must be held.
+func (r *memoryRepository) indexOf(ctx context.Context, id primitive.ObjectID) int {
+	return slices.IndexFunc(r.clients, func(c Client) bool {
+		return c.ID == id && inTenant(ctx, c)
+	})
+}
+
+// inTenant mirrors storage.TenantFilter for a single client.
+func inTenant(ctx context.Context, c Client) bool {
+	tenantID, scoped := auth.TenantFromContext(ctx)
+	return !scoped || c.TenantID == tenantID
+}
@@ -0,0 +1,79 @@
+package oauth_test
+
+import (
+	"context"
+	"testing"
+	"user-management/app/oauth"
+	"user-management/auth"
+	"user-management/config"
+	"user-management/storage"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func newTestRepository(mt *mtest.T) oauth.Repository {
+	dbConn := storage.NewMongoConn(mt.Client, mt.Client.Database("testdb"))
+	return oauth.NewRepository(dbConn, config.MongoConfig{
+		Database:              "testdb",
+		OAuthClientCollection: "oauth_clients",
+	})
+}
+
+func TestRepository_FindClientById(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("found", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(1, "testdb.oauth_clients", mtest.FirstBatch, bson.D{
+			bson.E{Key: "_id", Value: id},
+			bson.E{Key: "tenant_id", Value: "t1"},
+			bson.E{Key: "name", Value: "Billing"},
+			bson.E{Key: "secret_hash", Value: "h1"},
+			bson.E{Key: "scopes", Value: bson.A{"users:read"}},
+		}))
+
+		client, err := repo.FindClientById(context.Background(), id.Hex())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "t1", client.TenantID)
+		assert.Equal(t, []string{"users:read"}, client.Scopes)
+	})
+
+	mt.Run("malformed id", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+
+		_, err := repo.FindClientById(context.Background(), "billing")
+
+		assert.ErrorIs(t, err, oauth.ErrClientNotFound)
+	})
+
+	mt.Run("not found", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.oauth_clients", mtest.FirstBatch))
+
+		_, err := repo.FindClientById(context.Background(), primitive.NewObjectID().Hex())
+
+		assert.ErrorIs(t, err, oauth.ErrClientNotFound)
+	})
+}
+
+func TestRepository_DeleteClient(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("tenant scoped", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}})
+
+		deleted, err := repo.DeleteClient(auth.WithTenant(context.Background(), "t1"), primitive.NewObjectID())
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+		deletes := mt.GetStartedEvent().Command.Lookup("deletes").Array()
+		filter := deletes.Index(0).Value().Document().Lookup("q").Document()
+		assert.Equal(t, "t1", filter.Lookup("tenant_id").StringValue())
+	})
+}
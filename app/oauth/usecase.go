@@ -0,0 +1,248 @@
+package oauth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	"user-management/app/oidc"
+	"user-management/app/tenant"
+	"user-management/auth"
+	"user-management/config"
+	"user-management/opaque"
+	"user-management/response"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Repository interface {
+	CreateClient(ctx context.Context, client Client) (string, error)
+	FindClientById(ctx context.Context, id string) (Client, error)
+	FindClients(ctx context.Context) ([]Client, error)
+	DeleteClient(ctx context.Context, id primitive.ObjectID) (int64, error)
+}
+
+type TenantRepository interface {
+	FindTenantById(ctx context.Context, id string) (tenant.FindTenantResponse, error)
+}
+
+type usecase struct {
+	cfg     config.OAuthConfig
+	jwtKey  string
+	repo    Repository
+	tenants TenantRepository
+}
+
+func NewUsecase(cfg config.OAuthConfig, jwtKey string, r Repository, tr TenantRepository) *usecase {
+	return &usecase{
+		cfg:     cfg,
+		jwtKey:  jwtKey,
+		repo:    r,
+		tenants: tr,
+	}
+}
+
+// CreateClient registers a service client. Tenant admins register clients
+// for their own tenant; platform admins choose one or leave it empty for a
+// platform client. The secret is only ever returned here.
+func (u *usecase) CreateClient(ctx context.Context, req CreateClientRequest) (*response.StdResp[any], error) {
+	tenantID, scoped := auth.TenantFromContext(ctx)
+	if !scoped {
+		tenantID = req.TenantID
+	}
+	secret, err := opaque.New()
+	if err != nil {
+		return nil, err
+	}
+	client := Client{
+		TenantID:   tenantID,
+		Name:       strings.TrimSpace(req.Name),
+		SecretHash: opaque.Hash(secret),
+		Scopes:     auth.UniqueScopes(req.Scopes),
+	}
+	if claims, err := auth.FromContext(ctx); err == nil {
+		client.CreatedBy = claims.UserID
+	}
+	id, err := u.repo.CreateClient(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	return response.SuccessWithData(CreateClientResponse{ClientID: id, ClientSecret: secret}), nil
+}
+
+func (u *usecase) FindClients(ctx context.Context) (*response.StdResp[any], error) {
+	clients, err := u.repo.FindClients(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]FindClientResponse, 0, len(clients))
+	for _, c := range clients {
+		items = append(items, toFindClientResponse(c))
+	}
+	return response.SuccessWithData(items), nil
+}
+
+func (u *usecase) FindClientById(ctx context.Context, id string) (*response.StdResp[any], error) {
+	client, err := u.repo.FindClientById(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrClientNotFound) {
+			return response.ClientNotFound(), nil
+		}
+		return nil, err
+	}
+	return response.SuccessWithData(toFindClientResponse(client)), nil
+}
+
+// DeleteClient removes a client. It gets no more tokens, and ClientActive
+// stops the auth middlewares accepting those already issued.
+func (u *usecase) DeleteClient(ctx context.Context, id string) (*response.StdResp[any], error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return response.ClientNotFound(), nil
+	}
+	deleted, err := u.repo.DeleteClient(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+	if deleted == 0 {
+		return response.ClientNotFound(), nil
+	}
+	return response.Success(), nil
+}
+
+// Token issues an access token for the client credentials grant: a JWT that
+// the auth middlewares accept, acting as an admin of the client's tenant and
+// limited to the granted scopes. Errors the client should see are returned as
+// *oidc.Error.
+func (u *usecase) Token(ctx context.Context, req TokenRequest, creds oidc.ClientCredentials) (TokenResponse, error) {
+	client, err := u.authenticateClient(ctx, creds)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+	if req.GrantType != GrantTypeClientCredentials {
+		return TokenResponse{}, &oidc.Error{Code: oidc.ErrorUnsupportedGrantType, Description: "grant_type must be client_credentials."}
+	}
+	granted, err := grantedScopes(client.Scopes, req.Scope)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+	enabled, err := u.tenantEnabled(ctx, client.TenantID)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+	if !enabled {
+		return TokenResponse{}, &oidc.Error{Code: oidc.ErrorUnauthorizedClient, Description: "The client's tenant is disabled."}
+	}
+
+	now := time.Now()
+	clientID := client.ID.Hex()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256,
+		auth.Claims{
+			TenantID: client.TenantID,
+			Role:     auth.RoleAdmin,
+			ClientID: clientID,
+			Scopes:   granted,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   clientID,
+				IssuedAt:  jwt.NewNumericDate(now),
+				ExpiresAt: jwt.NewNumericDate(now.Add(u.cfg.TokenTTL)),
+			},
+		},
+	)
+	signedToken, err := token.SignedString([]byte(u.jwtKey))
+	if err != nil {
+		return TokenResponse{}, err
+	}
+	return TokenResponse{
+		AccessToken: signedToken,
+		TokenType:   oidc.TokenTypeBearer,
+		ExpiresIn:   int64(u.cfg.TokenTTL.Seconds()),
+		Scope:       strings.Join(granted, " "),
+	}, nil
+}
+
+// ClientActive reports whether tokens issued to clientID may still be used:
+// the client has not been deleted and its tenant is not disabled.
+func (u *usecase) ClientActive(ctx context.Context, clientID string) (bool, error) {
+	client, err := u.repo.FindClientById(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, ErrClientNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return u.tenantEnabled(ctx, client.TenantID)
+}
+
+// tenantEnabled reports whether tenantID exists and is not disabled. Platform
+// clients, with no tenant, always pass.
+func (u *usecase) tenantEnabled(ctx context.Context, tenantID string) (bool, error) {
+	if tenantID == "" {
+		return true, nil
+	}
+	t, err := u.tenants.FindTenantById(ctx, tenantID)
+	if err != nil {
+		if errors.Is(err, tenant.ErrTenantNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return !t.Disabled, nil
+}
+
+// authenticateClient checks the client's secret. Every service client is
+// confidential, so one is always needed.
+func (u *usecase) authenticateClient(ctx context.Context, creds oidc.ClientCredentials) (Client, error) {
+	failed := &oidc.Error{Code: oidc.ErrorInvalidClient, Description: "Client authentication failed."}
+	if creds.Secret == "" {
+		return Client{}, failed
+	}
+	client, err := u.repo.FindClientById(ctx, creds.ID)
+	if err != nil {
+		if errors.Is(err, ErrClientNotFound) {
+			return Client{}, failed
+		}
+		return Client{}, err
+	}
+	if subtle.ConstantTimeCompare([]byte(opaque.Hash(creds.Secret)), []byte(client.SecretHash)) != 1 {
+		return Client{}, failed
+	}
+	return client, nil
+}
+
+// grantedScopes returns the scopes asked for in scope, or all of allowed when
+// scope is empty. Asking for a scope outside allowed is an error rather than
+// being ignored, so a client never gets less than it asked for silently.
+func grantedScopes(allowed []string, scope string) ([]string, error) {
+	requested := strings.Fields(scope)
+	if len(requested) == 0 {
+		requested = allowed
+	}
+	granted := []string{}
+	for _, s := range requested {
+		if !slices.Contains(allowed, s) {
+			return nil, &oidc.Error{Code: oidc.ErrorInvalidScope, Description: "Scope " + s + " is not allowed for this client."}
+		}
+		if !slices.Contains(granted, s) {
+			granted = append(granted, s)
+		}
+	}
+	if len(granted) == 0 {
+		return nil, &oidc.Error{Code: oidc.ErrorInvalidScope, Description: "The client has no scopes."}
+	}
+	return granted, nil
+}
+
+func toFindClientResponse(c Client) FindClientResponse {
+	return FindClientResponse{
+		ClientID:  c.ID.Hex(),
+		TenantID:  c.TenantID,
+		Name:      c.Name,
+		Scopes:    c.Scopes,
+		CreatedBy: c.CreatedBy,
+		CreatedAt: c.CreatedAt,
+	}
+}
@@ -0,0 +1,219 @@
+package oauth_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-management/app/oauth"
+	"user-management/app/oidc"
+	"user-management/app/tenant"
+	"user-management/auth"
+	"user-management/config"
+	"user-management/response"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	acmeTenantID = "60d5ec49f1a2c8b1f8e4e1b2"
+	testJwtKey   = "test-secret"
+)
+
+var testCfg = config.OAuthConfig{TokenTTL: 15 * time.Minute}
+
+type fakeTenants map[string]tenant.FindTenantResponse
+
+func (f fakeTenants) FindTenantById(ctx context.Context, id string) (tenant.FindTenantResponse, error) {
+	t, ok := f[id]
+	if !ok {
+		return t, tenant.ErrTenantNotFound
+	}
+	return t, nil
+}
+
+type usecase interface {
+	oauth.Usecase
+	ClientActive(ctx context.Context, clientID string) (bool, error)
+}
+
+func newTestUsecase(tenants fakeTenants) usecase {
+	return oauth.NewUsecase(testCfg, testJwtKey, oauth.NewMemoryRepository(), tenants)
+}
+
+func adminContext() context.Context {
+	return auth.WithClaims(context.Background(), &auth.Claims{UserID: "admin-1", TenantID: acmeTenantID, Role: auth.RoleAdmin})
+}
+
+func createClient(t *testing.T, uc usecase, scopes ...string) oidc.ClientCredentials {
+	t.Helper()
+	resp, err := uc.CreateClient(adminContext(), oauth.CreateClientRequest{Name: "Billing", Scopes: scopes})
+	require.NoError(t, err)
+	created := resp.Data.(oauth.CreateClientResponse)
+	return oidc.ClientCredentials{ID: created.ClientID, Secret: created.ClientSecret}
+}
+
+func parseToken(t *testing.T, token string) *auth.Claims {
+	t.Helper()
+	claims := &auth.Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return []byte(testJwtKey), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	require.NoError(t, err)
+	return claims
+}
+
+func assertOAuthError(t *testing.T, err error, code string) {
+	t.Helper()
+	var oauthErr *oidc.Error
+	require.ErrorAs(t, err, &oauthErr)
+	assert.Equal(t, code, oauthErr.Code)
+}
+
+func TestUsecaseCreateClient(t *testing.T) {
+	uc := newTestUsecase(fakeTenants{})
+
+	resp, err := uc.CreateClient(adminContext(), oauth.CreateClientRequest{Name: " Billing ", TenantID: "ignored", Scopes: []string{auth.ScopeUsersRead, auth.ScopeUsersRead}})
+	require.NoError(t, err)
+	created := resp.Data.(oauth.CreateClientResponse)
+	assert.NotEmpty(t, created.ClientSecret)
+
+	resp, err = uc.FindClients(adminContext())
+	require.NoError(t, err)
+	clients := resp.Data.([]oauth.FindClientResponse)
+	require.Len(t, clients, 1)
+	assert.Equal(t, created.ClientID, clients[0].ClientID)
+	assert.Equal(t, "Billing", clients[0].Name)
+	assert.Equal(t, acmeTenantID, clients[0].TenantID)
+	assert.Equal(t, []string{auth.ScopeUsersRead}, clients[0].Scopes)
+	assert.Equal(t, "admin-1", clients[0].CreatedBy)
+}
+
+func TestUsecaseToken(t *testing.T) {
+	uc := newTestUsecase(fakeTenants{acmeTenantID: {Id: acmeTenantID}})
+	creds := createClient(t, uc, auth.ScopeUsersRead, auth.ScopeGroupsRead)
+
+	resp, err := uc.Token(context.Background(), oauth.TokenRequest{GrantType: "client_credentials"}, creds)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer", resp.TokenType)
+	assert.Equal(t, int64(900), resp.ExpiresIn)
+	assert.Equal(t, "users:read groups:read", resp.Scope)
+	claims := parseToken(t, resp.AccessToken)
+	assert.Equal(t, creds.ID, claims.ClientID)
+	assert.Equal(t, creds.ID, claims.Subject)
+	assert.Equal(t, acmeTenantID, claims.TenantID)
+	assert.Equal(t, auth.RoleAdmin, claims.Role)
+	assert.Empty(t, claims.UserID)
+	assert.Equal(t, []string{auth.ScopeUsersRead, auth.ScopeGroupsRead}, claims.Scopes)
+	assert.True(t, claims.IsScoped())
+}
+
+func TestUsecaseToken_PlatformClient(t *testing.T) {
+	uc := newTestUsecase(fakeTenants{})
+	platformAdmin := auth.WithClaims(context.Background(), &auth.Claims{UserID: "admin-1", Role: auth.RoleAdmin})
+	resp, err := uc.CreateClient(platformAdmin, oauth.CreateClientRequest{Name: "Billing", Scopes: []string{auth.ScopeUsersWrite}})
+	require.NoError(t, err)
+	created := resp.Data.(oauth.CreateClientResponse)
+
+	token, err := uc.Token(context.Background(), oauth.TokenRequest{GrantType: "client_credentials"}, oidc.ClientCredentials{ID: created.ClientID, Secret: created.ClientSecret})
+
+	require.NoError(t, err)
+	claims := parseToken(t, token.AccessToken)
+	assert.Empty(t, claims.TenantID)
+	assert.False(t, claims.IsPlatformAdmin())
+}
+
+func TestUsecaseToken_RequestedScope(t *testing.T) {
+	uc := newTestUsecase(fakeTenants{acmeTenantID: {Id: acmeTenantID}})
+	creds := createClient(t, uc, auth.ScopeUsersRead, auth.ScopeGroupsRead)
+
+	resp, err := uc.Token(context.Background(), oauth.TokenRequest{GrantType: "client_credentials", Scope: "groups:read"}, creds)
+	require.NoError(t, err)
+	assert.Equal(t, "groups:read", resp.Scope)
+	assert.Equal(t, []string{auth.ScopeGroupsRead}, parseToken(t, resp.AccessToken).Scopes)
+
+	_, err = uc.Token(context.Background(), oauth.TokenRequest{GrantType: "client_credentials", Scope: "groups:read users:write"}, creds)
+	assertOAuthError(t, err, oidc.ErrorInvalidScope)
+}
+
+func TestUsecaseToken_InvalidClient(t *testing.T) {
+	uc := newTestUsecase(fakeTenants{acmeTenantID: {Id: acmeTenantID}})
+	creds := createClient(t, uc, auth.ScopeUsersRead)
+	req := oauth.TokenRequest{GrantType: "client_credentials"}
+
+	tests := []struct {
+		name  string
+		creds oidc.ClientCredentials
+	}{
+		{"wrong secret", oidc.ClientCredentials{ID: creds.ID, Secret: "wrong"}},
+		{"no secret", oidc.ClientCredentials{ID: creds.ID}},
+		{"unknown client", oidc.ClientCredentials{ID: "60d5ec49f1a2c8b1f8e4e1b9", Secret: creds.Secret}},
+		{"malformed id", oidc.ClientCredentials{ID: "billing", Secret: creds.Secret}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.Token(context.Background(), req, tt.creds)
+			assertOAuthError(t, err, oidc.ErrorInvalidClient)
+		})
+	}
+}
+
+func TestUsecaseToken_UnsupportedGrantType(t *testing.T) {
+	uc := newTestUsecase(fakeTenants{acmeTenantID: {Id: acmeTenantID}})
+	creds := createClient(t, uc, auth.ScopeUsersRead)
+
+	_, err := uc.Token(context.Background(), oauth.TokenRequest{GrantType: "password"}, creds)
+
+	assertOAuthError(t, err, oidc.ErrorUnsupportedGrantType)
+}
+
+func TestUsecaseToken_DisabledTenant(t *testing.T) {
+	uc := newTestUsecase(fakeTenants{acmeTenantID: {Id: acmeTenantID, Disabled: true}})
+	creds := createClient(t, uc, auth.ScopeUsersRead)
+
+	_, err := uc.Token(context.Background(), oauth.TokenRequest{GrantType: "client_credentials"}, creds)
+
+	assertOAuthError(t, err, oidc.ErrorUnauthorizedClient)
+}
+
+func TestUsecaseDeleteClient(t *testing.T) {
+	uc := newTestUsecase(fakeTenants{acmeTenantID: {Id: acmeTenantID}})
+	creds := createClient(t, uc, auth.ScopeUsersRead)
+
+	// Admins of other tenants cannot see the client.
+	other := auth.WithClaims(context.Background(), &auth.Claims{TenantID: "60d5ec49f1a2c8b1f8e4e1b3", Role: auth.RoleAdmin})
+	resp, err := uc.DeleteClient(other, creds.ID)
+	require.NoError(t, err)
+	assert.Equal(t, response.ClientNotFound(), resp)
+
+	resp, err = uc.DeleteClient(adminContext(), creds.ID)
+	require.NoError(t, err)
+	assert.True(t, resp.IsSuccess())
+
+	_, err = uc.Token(context.Background(), oauth.TokenRequest{GrantType: "client_credentials"}, creds)
+	assertOAuthError(t, err, oidc.ErrorInvalidClient)
+}
+
+func TestUsecaseClientActive(t *testing.T) {
+	tenants := fakeTenants{acmeTenantID: {Id: acmeTenantID}}
+	uc := newTestUsecase(tenants)
+	creds := createClient(t, uc, auth.ScopeUsersRead)
+
+	active, err := uc.ClientActive(context.Background(), creds.ID)
+	require.NoError(t, err)
+	assert.True(t, active)
+
+	tenants[acmeTenantID] = tenant.FindTenantResponse{Id: acmeTenantID, Disabled: true}
+	active, err = uc.ClientActive(context.Background(), creds.ID)
+	require.NoError(t, err)
+	assert.False(t, active)
+
+	tenants[acmeTenantID] = tenant.FindTenantResponse{Id: acmeTenantID}
+	_, err = uc.DeleteClient(adminContext(), creds.ID)
+	require.NoError(t, err)
+	active, err = uc.ClientActive(context.Background(), creds.ID)
+	require.NoError(t, err)
+	assert.False(t, active)
+}
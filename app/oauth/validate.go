@@ -0,0 +1,41 @@
+package oauth
+
+import (
+	"strings"
+	"user-management/auth"
+	"user-management/response"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (r CreateClientRequest) RequestValidation() *response.StdResp[any] {
+	if checkLen(r.Name) == 0 {
+		return response.MandatoryMissing("name")
+	}
+	if len(r.Scopes) == 0 {
+		return response.MandatoryMissing("scopes")
+	}
+	for _, s := range r.Scopes {
+		if !auth.ValidScope(s) {
+			return response.InvalidData("scopes")
+		}
+	}
+	if r.TenantID != "" {
+		if _, err := primitive.ObjectIDFromHex(r.TenantID); err != nil {
+			return response.InvalidData("tenant_id")
+		}
+	}
+	return response.Success()
+}
+
+func IdValidation(id string) *response.StdResp[any] {
+	_, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return response.InvalidData(ParamID)
+	}
+	return response.Success()
+}
+
+func checkLen(s string) int {
+	return len([]rune(strings.TrimSpace(s)))
+}
@@ -0,0 +1,47 @@
+package oidc
+
+import "errors"
+
+const (
+	ParamID = "id"
+)
+
+const (
+	ScopeOpenID  = "openid"
+	ScopeProfile = "profile"
+	ScopeEmail   = "email"
+)
+
+// scopes lists the scopes the provider grants, in the order they are shown on
+// the consent page. Requested scopes outside it are ignored.
+var scopes = []string{ScopeOpenID, ScopeProfile, ScopeEmail}
+
+const (
+	ResponseTypeCode           = "code"
+	GrantTypeAuthorizationCode = "authorization_code"
+	CodeChallengeMethodS256    = "S256"
+	PromptNone                 = "none"
+	DecisionAllow              = "allow"
+	TokenTypeBearer            = "Bearer"
+)
+
+// OAuth 2.0 error codes (RFC 6749, RFC 6750 and OpenID Connect Core).
+const (
+	ErrorInvalidRequest          = "invalid_request"
+	ErrorInvalidClient           = "invalid_client"
+	ErrorInvalidGrant            = "invalid_grant"
+	ErrorInvalidScope            = "invalid_scope"
+	ErrorInvalidToken            = "invalid_token"
+	ErrorUnauthorizedClient      = "unauthorized_client"
+	ErrorUnsupportedGrantType    = "unsupported_grant_type"
+	ErrorUnsupportedResponseType = "unsupported_response_type"
+	ErrorAccessDenied            = "access_denied"
+	ErrorLoginRequired           = "login_required"
+	ErrorServerError             = "server_error"
+)
+
+var (
+	ErrClientNotFound = errors.New("Client not found")
+	ErrCodeNotFound   = errors.New("Authorization code not found")
+	ErrTokenNotFound  = errors.New("Access token not found")
+)
@@ -0,0 +1,284 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"user-management/logger"
+	"user-management/response"
+
+	echo "github.com/labstack/echo/v4"
+)
+
+type Usecase interface {
+	CreateClient(ctx context.Context, req CreateClientRequest) (*response.StdResp[any], error)
+	FindClients(ctx context.Context) (*response.StdResp[any], error)
+	FindClientById(ctx context.Context, id string) (*response.StdResp[any], error)
+	DeleteClient(ctx context.Context, id string) (*response.StdResp[any], error)
+	StartAuthorization(ctx context.Context, req AuthorizeRequest) (AuthorizeResult, error)
+	CompleteAuthorization(ctx context.Context, req AuthorizeRequest, form LoginForm) (AuthorizeResult, error)
+	Token(ctx context.Context, req TokenRequest, creds ClientCredentials) (TokenResponse, error)
+	UserInfo(ctx context.Context, accessToken string) (UserInfo, error)
+	Introspect(ctx context.Context, creds ClientCredentials, accessToken string) (Introspection, error)
+	Revoke(ctx context.Context, creds ClientCredentials, accessToken string) error
+	Discovery() Discovery
+	JWKS() JWKS
+}
+
+type Handler interface {
+	CreateClient(c echo.Context) error
+	FindClients(c echo.Context) error
+	FindClientById(c echo.Context) error
+	DeleteClient(c echo.Context) error
+	Authorize(c echo.Context) error
+	SubmitAuthorize(c echo.Context) error
+	Token(c echo.Context) error
+	UserInfo(c echo.Context) error
+	Introspect(c echo.Context) error
+	Revoke(c echo.Context) error
+	Discovery(c echo.Context) error
+	JWKS(c echo.Context) error
+}
+
+type handler struct {
+	usecase Usecase
+}
+
+func NewHandler(u Usecase) *handler {
+	return &handler{
+		usecase: u,
+	}
+}
+
+func (h *handler) CreateClient(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	var request CreateClientRequest
+	err = c.Bind(&request)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Bind request error: %v", err.Error())
+		return c.JSON(response.UnexpectedRequest().WithHTTPStatus())
+	}
+
+	if resp := request.RequestValidation(); !resp.IsSuccess() {
+		return c.JSON(resp.WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.CreateClient(ctx, request)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+func (h *handler) FindClients(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.FindClients(ctx)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+func (h *handler) FindClientById(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	paramId := c.Param(ParamID)
+	if respValidate := IdValidation(paramId); !respValidate.IsSuccess() {
+		return c.JSON(respValidate.WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.FindClientById(ctx, paramId)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+func (h *handler) DeleteClient(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	paramId := c.Param(ParamID)
+	if respValidate := IdValidation(paramId); !respValidate.IsSuccess() {
+		return c.JSON(respValidate.WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.DeleteClient(ctx, paramId)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+// Authorize shows the sign-in and consent page for an authorization request.
+func (h *handler) Authorize(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, response.InternalServerError().Message)
+	}
+	var request AuthorizeRequest
+	if err := c.Bind(&request); err != nil {
+		zlog.Sugar().Infof("[Handler] Bind request error: %v", err.Error())
+		return renderAuthorize(c, request, LoginForm{}, AuthorizeResult{Error: response.UnexpectedRequest().Message})
+	}
+
+	result, err := h.usecase.StartAuthorization(ctx, request)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.String(http.StatusInternalServerError, response.InternalServerError().Message)
+	}
+	return renderAuthorize(c, request, LoginForm{}, result)
+}
+
+// SubmitAuthorize handles the sign-in form, which posts the authorization
+// request back along with the credentials and the user's decision.
+func (h *handler) SubmitAuthorize(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, response.InternalServerError().Message)
+	}
+	var request AuthorizeRequest
+	var form LoginForm
+	if err := errors.Join(c.Bind(&request), c.Bind(&form)); err != nil {
+		zlog.Sugar().Infof("[Handler] Bind request error: %v", err.Error())
+		return renderAuthorize(c, request, form, AuthorizeResult{Error: response.UnexpectedRequest().Message})
+	}
+
+	result, err := h.usecase.CompleteAuthorization(ctx, request, form)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.String(http.StatusInternalServerError, response.InternalServerError().Message)
+	}
+	return renderAuthorize(c, request, form, result)
+}
+
+func (h *handler) Token(c echo.Context) error {
+	var request TokenRequest
+	if err := c.Bind(&request); err != nil {
+		return h.oauthError(c, &Error{Code: ErrorInvalidRequest, Description: response.UnexpectedRequest().Message})
+	}
+	creds, err := clientCredentials(c, request.ClientID, request.ClientSecret)
+	if err != nil {
+		return h.oauthError(c, err)
+	}
+
+	resp, err := h.usecase.Token(c.Request().Context(), request, creds)
+	if err != nil {
+		return h.oauthError(c, err)
+	}
+	noStore(c)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// UserInfo takes the access token from the Authorization header only.
+func (h *handler) UserInfo(c echo.Context) error {
+	accessToken, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+	if !ok || accessToken == "" {
+		c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+		return c.JSON(http.StatusUnauthorized, &Error{Code: ErrorInvalidRequest, Description: "A bearer token is required."})
+	}
+
+	resp, err := h.usecase.UserInfo(c.Request().Context(), accessToken)
+	if err != nil {
+		return h.oauthError(c, err)
+	}
+	noStore(c)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *handler) Introspect(c echo.Context) error {
+	creds, err := clientCredentials(c, c.FormValue("client_id"), c.FormValue("client_secret"))
+	if err != nil {
+		return h.oauthError(c, err)
+	}
+
+	resp, err := h.usecase.Introspect(c.Request().Context(), creds, c.FormValue("token"))
+	if err != nil {
+		return h.oauthError(c, err)
+	}
+	noStore(c)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *handler) Revoke(c echo.Context) error {
+	creds, err := clientCredentials(c, c.FormValue("client_id"), c.FormValue("client_secret"))
+	if err != nil {
+		return h.oauthError(c, err)
+	}
+
+	if err := h.usecase.Revoke(c.Request().Context(), creds, c.FormValue("token")); err != nil {
+		return h.oauthError(c, err)
+	}
+	return c.NoContent(http.StatusOK)
+}
+
+func (h *handler) Discovery(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.usecase.Discovery())
+}
+
+func (h *handler) JWKS(c echo.Context) error {
+	return c.JSON(http.StatusOK, h.usecase.JWKS())
+}
+
+// oauthError answers with err as an OAuth error response. Other errors are
+// logged and hidden behind server_error.
+func (h *handler) oauthError(c echo.Context, err error) error {
+	var oauthErr *Error
+	if errors.As(err, &oauthErr) {
+		if oauthErr.Code == ErrorInvalidToken {
+			c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+		}
+		noStore(c)
+		return c.JSON(oauthErr.HTTPStatus(), oauthErr)
+	}
+	if zlog, logErr := logger.FromContext(c.Request().Context()); logErr == nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+	}
+	return c.JSON(http.StatusInternalServerError, &Error{Code: ErrorServerError})
+}
+
+// clientCredentials reads the client's credentials from HTTP Basic
+// authentication, falling back to the client_id and client_secret parameters.
+// Basic credentials are form encoded (RFC 6749 section 2.3.1).
+func clientCredentials(c echo.Context, id, secret string) (ClientCredentials, error) {
+	basicID, basicSecret, ok := c.Request().BasicAuth()
+	if !ok {
+		return ClientCredentials{ID: id, Secret: secret}, nil
+	}
+	var err error
+	creds := ClientCredentials{}
+	if creds.ID, err = url.QueryUnescape(basicID); err != nil {
+		return creds, &Error{Code: ErrorInvalidClient, Description: "Client authentication failed."}
+	}
+	if creds.Secret, err = url.QueryUnescape(basicSecret); err != nil {
+		return creds, &Error{Code: ErrorInvalidClient, Description: "Client authentication failed."}
+	}
+	return creds, nil
+}
+
+func noStore(c echo.Context) {
+	c.Response().Header().Set("Cache-Control", "no-store")
+	c.Response().Header().Set("Pragma", "no-cache")
+}
@@ -0,0 +1,347 @@
+package oidc_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"user-management/app/oidc"
+	"user-management/logger"
+	"user-management/response"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockUsecase struct {
+	mock.Mock
+}
+
+func (m *mockUsecase) CreateClient(ctx context.Context, req oidc.CreateClientRequest) (*response.StdResp[any], error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) FindClients(ctx context.Context) (*response.StdResp[any], error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) FindClientById(ctx context.Context, id string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) DeleteClient(ctx context.Context, id string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) StartAuthorization(ctx context.Context, req oidc.AuthorizeRequest) (oidc.AuthorizeResult, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(oidc.AuthorizeResult), args.Error(1)
+}
+
+func (m *mockUsecase) CompleteAuthorization(ctx context.Context, req oidc.AuthorizeRequest, form oidc.LoginForm) (oidc.AuthorizeResult, error) {
+	args := m.Called(ctx, req, form)
+	return args.Get(0).(oidc.AuthorizeResult), args.Error(1)
+}
+
+func (m *mockUsecase) Token(ctx context.Context, req oidc.TokenRequest, creds oidc.ClientCredentials) (oidc.TokenResponse, error) {
+	args := m.Called(ctx, req, creds)
+	return args.Get(0).(oidc.TokenResponse), args.Error(1)
+}
+
+func (m *mockUsecase) UserInfo(ctx context.Context, accessToken string) (oidc.UserInfo, error) {
+	args := m.Called(ctx, accessToken)
+	return args.Get(0).(oidc.UserInfo), args.Error(1)
+}
+
+func (m *mockUsecase) Introspect(ctx context.Context, creds oidc.ClientCredentials, accessToken string) (oidc.Introspection, error) {
+	args := m.Called(ctx, creds, accessToken)
+	return args.Get(0).(oidc.Introspection), args.Error(1)
+}
+
+func (m *mockUsecase) Revoke(ctx context.Context, creds oidc.ClientCredentials, accessToken string) error {
+	return m.Called(ctx, creds, accessToken).Error(0)
+}
+
+func (m *mockUsecase) Discovery() oidc.Discovery {
+	return m.Called().Get(0).(oidc.Discovery)
+}
+
+func (m *mockUsecase) JWKS() oidc.JWKS {
+	return m.Called().Get(0).(oidc.JWKS)
+}
+
+func newTestContext(method, target string, body any) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	var req *http.Request
+	switch b := body.(type) {
+	case url.Values:
+		req = httptest.NewRequest(method, target, strings.NewReader(b.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	case nil:
+		req = httptest.NewRequest(method, target, nil)
+	default:
+		j, _ := json.Marshal(body)
+		req = httptest.NewRequest(method, target, bytes.NewReader(j))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	}
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	ctx := context.WithValue(c.Request().Context(), logger.LogContext, logger.NewZap())
+	c.SetRequest(req.WithContext(ctx))
+	return c, rec
+}
+
+func TestHandlerAuthorize_Page(t *testing.T) {
+	c, rec := newTestContext(http.MethodGet, `/oauth2/authorize?client_id=c1&response_type=code&state="><script>`, nil)
+	req := oidc.AuthorizeRequest{ClientID: "c1", ResponseType: "code", State: `"><script>`}
+	mockUc := new(mockUsecase)
+	mockUc.On("StartAuthorization", mock.Anything, req).Return(oidc.AuthorizeResult{
+		Client: oidc.Client{Name: "Wiki"},
+		Scopes: []string{oidc.ScopeOpenID, oidc.ScopeEmail},
+	}, nil)
+
+	err := oidc.NewHandler(mockUc).Authorize(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, "Sign in to Wiki")
+	assert.Contains(t, body, "Your email address")
+	assert.Contains(t, body, `name="tenant"`)
+	assert.NotContains(t, body, "<script>")
+	assert.Equal(t, "DENY", rec.Header().Get("X-Frame-Options"))
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+}
+
+func TestHandlerAuthorize_Error(t *testing.T) {
+	c, rec := newTestContext(http.MethodGet, "/oauth2/authorize?client_id=nope", nil)
+	mockUc := new(mockUsecase)
+	mockUc.On("StartAuthorization", mock.Anything, mock.Anything).Return(oidc.AuthorizeResult{Error: "The application is not registered."}, nil)
+
+	err := oidc.NewHandler(mockUc).Authorize(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "The application is not registered.")
+	assert.NotContains(t, rec.Body.String(), "<form")
+}
+
+func TestHandlerSubmitAuthorize(t *testing.T) {
+	c, rec := newTestContext(http.MethodPost, "/oauth2/authorize", url.Values{
+		"client_id":    {"c1"},
+		"redirect_uri": {"https://wiki.example.com/callback"},
+		"state":        {"xyz"},
+		"tenant":       {"acme"},
+		"email":        {"alice@example.com"},
+		"password":     {"pass123"},
+		"decision":     {"allow"},
+	})
+	req := oidc.AuthorizeRequest{ClientID: "c1", RedirectURI: "https://wiki.example.com/callback", State: "xyz"}
+	form := oidc.LoginForm{Tenant: "acme", Email: "alice@example.com", Password: "pass123", Decision: "allow"}
+	mockUc := new(mockUsecase)
+	mockUc.On("CompleteAuthorization", mock.Anything, req, form).Return(oidc.AuthorizeResult{RedirectURL: "https://wiki.example.com/callback?code=abc&state=xyz"}, nil)
+
+	err := oidc.NewHandler(mockUc).SubmitAuthorize(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, "https://wiki.example.com/callback?code=abc&state=xyz", rec.Header().Get(echo.HeaderLocation))
+	mockUc.AssertExpectations(t)
+}
+
+func TestHandlerSubmitAuthorize_LoginFailed(t *testing.T) {
+	c, rec := newTestContext(http.MethodPost, "/oauth2/authorize", url.Values{
+		"email":    {"alice@example.com"},
+		"password": {"wrong"},
+	})
+	mockUc := new(mockUsecase)
+	mockUc.On("CompleteAuthorization", mock.Anything, mock.Anything, mock.Anything).Return(oidc.AuthorizeResult{
+		Client:     oidc.Client{Name: "Wiki"},
+		TenantSlug: "acme",
+		LoginError: "Login failed",
+	}, nil)
+
+	err := oidc.NewHandler(mockUc).SubmitAuthorize(c)
+
+	assert.NoError(t, err)
+	body := rec.Body.String()
+	assert.Contains(t, body, "Login failed")
+	assert.Contains(t, body, `value="alice@example.com"`)
+	assert.NotContains(t, body, "wrong")
+	assert.NotContains(t, body, `name="tenant"`)
+}
+
+func TestHandlerToken_BasicAuth(t *testing.T) {
+	c, rec := newTestContext(http.MethodPost, "/oauth2/token", url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {"abc"},
+		"redirect_uri":  {"https://wiki.example.com/callback"},
+		"code_verifier": {"verifier"},
+	})
+	c.Request().SetBasicAuth("c1", url.QueryEscape("s3cret+/="))
+	req := oidc.TokenRequest{GrantType: "authorization_code", Code: "abc", RedirectURI: "https://wiki.example.com/callback", CodeVerifier: "verifier"}
+	mockUc := new(mockUsecase)
+	mockUc.On("Token", mock.Anything, req, oidc.ClientCredentials{ID: "c1", Secret: "s3cret+/="}).Return(oidc.TokenResponse{AccessToken: "at", TokenType: "Bearer"}, nil)
+
+	err := oidc.NewHandler(mockUc).Token(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"access_token":"at"`)
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+}
+
+func TestHandlerToken_Error(t *testing.T) {
+	c, rec := newTestContext(http.MethodPost, "/oauth2/token", url.Values{"client_id": {"c1"}, "code": {"abc"}})
+	mockUc := new(mockUsecase)
+	mockUc.On("Token", mock.Anything, mock.Anything, oidc.ClientCredentials{ID: "c1"}).Return(oidc.TokenResponse{}, &oidc.Error{Code: oidc.ErrorInvalidClient, Description: "Client authentication failed."})
+
+	err := oidc.NewHandler(mockUc).Token(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.JSONEq(t, `{"error":"invalid_client","error_description":"Client authentication failed."}`, rec.Body.String())
+}
+
+func TestHandlerToken_ServiceError(t *testing.T) {
+	c, rec := newTestContext(http.MethodPost, "/oauth2/token", url.Values{"client_id": {"c1"}})
+	mockUc := new(mockUsecase)
+	mockUc.On("Token", mock.Anything, mock.Anything, mock.Anything).Return(oidc.TokenResponse{}, errors.New("db down"))
+
+	err := oidc.NewHandler(mockUc).Token(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.JSONEq(t, `{"error":"server_error"}`, rec.Body.String())
+}
+
+func TestHandlerUserInfo(t *testing.T) {
+	c, rec := newTestContext(http.MethodGet, "/userinfo", nil)
+	c.Request().Header.Set(echo.HeaderAuthorization, "Bearer at")
+	mockUc := new(mockUsecase)
+	mockUc.On("UserInfo", mock.Anything, "at").Return(oidc.UserInfo{Subject: "u1", Email: "alice@example.com"}, nil)
+
+	err := oidc.NewHandler(mockUc).UserInfo(c)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"sub":"u1","email":"alice@example.com"}`, rec.Body.String())
+}
+
+func TestHandlerUserInfo_InvalidToken(t *testing.T) {
+	c, rec := newTestContext(http.MethodGet, "/userinfo", nil)
+	c.Request().Header.Set(echo.HeaderAuthorization, "Bearer at")
+	mockUc := new(mockUsecase)
+	mockUc.On("UserInfo", mock.Anything, "at").Return(oidc.UserInfo{}, &oidc.Error{Code: oidc.ErrorInvalidToken})
+
+	err := oidc.NewHandler(mockUc).UserInfo(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, `Bearer error="invalid_token"`, rec.Header().Get(echo.HeaderWWWAuthenticate))
+}
+
+func TestHandlerUserInfo_NoToken(t *testing.T) {
+	c, rec := newTestContext(http.MethodGet, "/userinfo", nil)
+
+	err := oidc.NewHandler(new(mockUsecase)).UserInfo(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "Bearer", rec.Header().Get(echo.HeaderWWWAuthenticate))
+}
+
+func TestHandlerIntrospect(t *testing.T) {
+	c, rec := newTestContext(http.MethodPost, "/oauth2/introspect", url.Values{"token": {"at"}, "client_id": {"rs"}, "client_secret": {"s"}})
+	mockUc := new(mockUsecase)
+	mockUc.On("Introspect", mock.Anything, oidc.ClientCredentials{ID: "rs", Secret: "s"}, "at").Return(oidc.Introspection{}, nil)
+
+	err := oidc.NewHandler(mockUc).Introspect(c)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"active":false}`, rec.Body.String())
+}
+
+func TestHandlerRevoke(t *testing.T) {
+	c, rec := newTestContext(http.MethodPost, "/oauth2/revoke", url.Values{"token": {"at"}, "client_id": {"spa"}})
+	mockUc := new(mockUsecase)
+	mockUc.On("Revoke", mock.Anything, oidc.ClientCredentials{ID: "spa"}, "at").Return(nil)
+
+	err := oidc.NewHandler(mockUc).Revoke(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUc.AssertExpectations(t)
+}
+
+func TestHandlerCreateClient(t *testing.T) {
+	reqBody := oidc.CreateClientRequest{Name: "Wiki", RedirectURIs: []string{"https://wiki.example.com/callback"}}
+	c, rec := newTestContext(http.MethodPost, "/oauth2/clients", reqBody)
+	mockUc := new(mockUsecase)
+	mockUc.On("CreateClient", mock.Anything, reqBody).Return(response.SuccessWithData(oidc.CreateClientResponse{ClientID: "c1", ClientSecret: "s"}), nil)
+
+	err := oidc.NewHandler(mockUc).CreateClient(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"client_secret":"s"`)
+}
+
+func TestHandlerCreateClient_InvalidRedirectURI(t *testing.T) {
+	c, rec := newTestContext(http.MethodPost, "/oauth2/clients", oidc.CreateClientRequest{Name: "Wiki", RedirectURIs: []string{"http://wiki.example.com/callback"}})
+	mockUc := new(mockUsecase)
+
+	err := oidc.NewHandler(mockUc).CreateClient(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUc.AssertNotCalled(t, "CreateClient")
+}
+
+func TestHandlerDeleteClient_InvalidId(t *testing.T) {
+	c, rec := newTestContext(http.MethodDelete, "/oauth2/clients/bad", nil)
+	c.SetParamNames(oidc.ParamID)
+	c.SetParamValues("bad")
+	mockUc := new(mockUsecase)
+
+	err := oidc.NewHandler(mockUc).DeleteClient(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUc.AssertNotCalled(t, "DeleteClient")
+}
+
+func TestHandlerFindClientById(t *testing.T) {
+	id := primitive.NewObjectID().Hex()
+	c, rec := newTestContext(http.MethodGet, "/oauth2/clients/"+id, nil)
+	c.SetParamNames(oidc.ParamID)
+	c.SetParamValues(id)
+	mockUc := new(mockUsecase)
+	mockUc.On("FindClientById", mock.Anything, id).Return(response.ClientNotFound(), nil)
+
+	err := oidc.NewHandler(mockUc).FindClientById(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHandlerDiscovery(t *testing.T) {
+	c, rec := newTestContext(http.MethodGet, "/.well-known/openid-configuration", nil)
+	mockUc := new(mockUsecase)
+	mockUc.On("Discovery").Return(oidc.Discovery{Issuer: "https://id.example.com"})
+
+	err := oidc.NewHandler(mockUc).Discovery(c)
+
+	assert.NoError(t, err)
+	assert.Contains(t, rec.Body.String(), `"issuer":"https://id.example.com"`)
+}
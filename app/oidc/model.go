@@ -0,0 +1,216 @@
+package oidc
+
+import (
+	"net/http"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Client is an application that signs users in through the provider. Its id
+// is the OAuth client_id. Confidential clients authenticate with a secret,
+// kept as a hash; public clients such as single page apps have none and rely
+// on PKCE alone. A client with a TenantID only signs in users of that tenant;
+// one without signs in users of any tenant and platform users.
+type Client struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty"`
+	TenantID     string             `bson:"tenant_id,omitempty"`
+	Name         string             `bson:"name"`
+	RedirectURIs []string           `bson:"redirect_uris"`
+	SecretHash   string             `bson:"secret_hash,omitempty"`
+	CreatedBy    string             `bson:"created_by,omitempty"`
+	CreatedAt    time.Time          `bson:"created_at"`
+}
+
+func (c Client) Public() bool {
+	return c.SecretHash == ""
+}
+
+// AuthorizationCode is issued by the authorization endpoint and redeemed once
+// at the token endpoint. Only its hash is stored.
+type AuthorizationCode struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty"`
+	CodeHash      string             `bson:"code_hash"`
+	ClientID      string             `bson:"client_id"`
+	UserID        string             `bson:"user_id"`
+	TenantID      string             `bson:"tenant_id,omitempty"`
+	SessionID     string             `bson:"session_id,omitempty"`
+	RedirectURI   string             `bson:"redirect_uri"`
+	Scope         string             `bson:"scope"`
+	Nonce         string             `bson:"nonce,omitempty"`
+	CodeChallenge string             `bson:"code_challenge"`
+	AuthTime      time.Time          `bson:"auth_time"`
+	ExpiresAt     time.Time          `bson:"expires_at"`
+}
+
+// AccessToken is an opaque bearer token for /userinfo. Only its hash is
+// stored. It stops working with the login session it was issued under.
+type AccessToken struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	TokenHash string             `bson:"token_hash"`
+	ClientID  string             `bson:"client_id"`
+	UserID    string             `bson:"user_id"`
+	TenantID  string             `bson:"tenant_id,omitempty"`
+	SessionID string             `bson:"session_id,omitempty"`
+	Scope     string             `bson:"scope"`
+	IssuedAt  time.Time          `bson:"issued_at"`
+	ExpiresAt time.Time          `bson:"expires_at"`
+}
+
+type CreateClientRequest struct {
+	TenantID     string   `json:"tenant_id"`
+	Name         string   `json:"name"`
+	RedirectURIs []string `json:"redirect_uris"`
+	Public       bool     `json:"public"`
+}
+
+// CreateClientResponse carries the client secret, which is shown only once.
+type CreateClientResponse struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+type FindClientResponse struct {
+	ClientID     string    `json:"client_id"`
+	TenantID     string    `json:"tenant_id,omitempty"`
+	Name         string    `json:"name"`
+	RedirectURIs []string  `json:"redirect_uris"`
+	Public       bool      `json:"public"`
+	CreatedBy    string    `json:"created_by,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// AuthorizeRequest holds the parameters of an authorization request. They come
+// in the query string and are carried through the sign-in form as hidden
+// fields.
+type AuthorizeRequest struct {
+	ResponseType        string `query:"response_type" form:"response_type"`
+	ClientID            string `query:"client_id" form:"client_id"`
+	RedirectURI         string `query:"redirect_uri" form:"redirect_uri"`
+	Scope               string `query:"scope" form:"scope"`
+	State               string `query:"state" form:"state"`
+	Nonce               string `query:"nonce" form:"nonce"`
+	CodeChallenge       string `query:"code_challenge" form:"code_challenge"`
+	CodeChallengeMethod string `query:"code_challenge_method" form:"code_challenge_method"`
+	Prompt              string `query:"prompt" form:"prompt"`
+}
+
+// LoginForm is what the user enters on the sign-in page. Decision is
+// DecisionAllow when the user consents.
+type LoginForm struct {
+	Tenant   string `form:"tenant"`
+	Email    string `form:"email"`
+	Password string `form:"password"`
+	Decision string `form:"decision"`
+}
+
+// AuthorizeResult tells the handler how to answer an authorization request:
+// redirect to RedirectURL, show the sign-in page for Client, or, when the
+// client or its redirect URI cannot be trusted, show Error to the user.
+type AuthorizeResult struct {
+	RedirectURL string
+	Client      Client
+	Scopes      []string
+	// TenantSlug is set for clients bound to a tenant; the sign-in page then
+	// does not ask for one.
+	TenantSlug string
+	// LoginError explains why the last sign-in attempt failed.
+	LoginError string
+	Error      string
+}
+
+type TokenRequest struct {
+	GrantType    string `form:"grant_type"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+}
+
+// ClientCredentials identify the client calling the token, introspection or
+// revocation endpoint. Public clients give no secret.
+type ClientCredentials struct {
+	ID     string
+	Secret string
+}
+
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+	IDToken     string `json:"id_token"`
+	Scope       string `json:"scope"`
+}
+
+// IDTokenClaims is the payload of an ID token. Name and Email are only set
+// when the profile and email scopes were granted.
+type IDTokenClaims struct {
+	Nonce     string           `json:"nonce,omitempty"`
+	AuthTime  *jwt.NumericDate `json:"auth_time,omitempty"`
+	AtHash    string           `json:"at_hash,omitempty"`
+	SessionID string           `json:"sid,omitempty"`
+	Name      string           `json:"name,omitempty"`
+	Email     string           `json:"email,omitempty"`
+	jwt.RegisteredClaims
+}
+
+type UserInfo struct {
+	Subject string `json:"sub"`
+	Name    string `json:"name,omitempty"`
+	Email   string `json:"email,omitempty"`
+}
+
+// Introspection is a token introspection response (RFC 7662). Only Active is
+// set for tokens that are unknown, expired or no longer usable.
+type Introspection struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	Issuer    string `json:"iss,omitempty"`
+}
+
+// Discovery is the OpenID Provider metadata served at
+// /.well-known/openid-configuration.
+type Discovery struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserinfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JwksURI                           string   `json:"jwks_uri"`
+	IntrospectionEndpoint             string   `json:"introspection_endpoint"`
+	RevocationEndpoint                string   `json:"revocation_endpoint"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+// Error is an OAuth 2.0 error response (RFC 6749 section 5.2). The token,
+// userinfo, introspection and revocation endpoints answer with it instead of
+// the usual response envelope, as their clients expect.
+type Error struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+func (e *Error) HTTPStatus() int {
+	switch e.Code {
+	case ErrorInvalidClient, ErrorInvalidToken:
+		return http.StatusUnauthorized
+	}
+	return http.StatusBadRequest
+}
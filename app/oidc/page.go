@@ -0,0 +1,94 @@
+package oidc
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+
+	echo "github.com/labstack/echo/v4"
+)
+
+// scopeDescriptions tells the user on the consent page what each scope shares.
+var scopeDescriptions = map[string]string{
+	ScopeOpenID:  "Your user id",
+	ScopeProfile: "Your name",
+	ScopeEmail:   "Your email address",
+}
+
+var pageTemplate = template.Must(template.New("authorize").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Sign in</title>
+<style>
+body { font-family: sans-serif; max-width: 24rem; margin: 4rem auto; padding: 0 1rem; }
+label, input, button { display: block; width: 100%; box-sizing: border-box; }
+input { margin: .25rem 0 1rem; padding: .5rem; }
+button { margin-top: .5rem; padding: .5rem; }
+.error { color: #b00020; }
+</style>
+</head>
+<body>
+{{if .Error}}
+<h1>Sign-in request rejected</h1>
+<p class="error">{{.Error}}</p>
+{{else}}
+<h1>Sign in to {{.Client.Name}}</h1>
+<p>{{.Client.Name}} will be able to see:</p>
+<ul>{{range .ScopeDescriptions}}<li>{{.}}</li>{{end}}</ul>
+{{if .LoginError}}<p class="error">{{.LoginError}}</p>{{end}}
+<form method="post" action="authorize">
+{{with .Request}}
+<input type="hidden" name="response_type" value="{{.ResponseType}}">
+<input type="hidden" name="client_id" value="{{.ClientID}}">
+<input type="hidden" name="redirect_uri" value="{{.RedirectURI}}">
+<input type="hidden" name="scope" value="{{.Scope}}">
+<input type="hidden" name="state" value="{{.State}}">
+<input type="hidden" name="nonce" value="{{.Nonce}}">
+<input type="hidden" name="code_challenge" value="{{.CodeChallenge}}">
+<input type="hidden" name="code_challenge_method" value="{{.CodeChallengeMethod}}">
+{{end}}
+{{if not .TenantSlug}}<label>Tenant <input name="tenant" value="{{.Form.Tenant}}" placeholder="Leave empty for platform accounts"></label>{{end}}
+<label>Email <input type="email" name="email" value="{{.Form.Email}}" required autofocus></label>
+<label>Password <input type="password" name="password" required></label>
+<button type="submit" name="decision" value="allow">Allow</button>
+<button type="submit" name="decision" value="deny" formnovalidate>Deny</button>
+</form>
+{{end}}
+</body>
+</html>
+`))
+
+type page struct {
+	AuthorizeResult
+	Request           AuthorizeRequest
+	Form              LoginForm
+	ScopeDescriptions []string
+}
+
+// renderAuthorize answers an authorization request as result says: with a
+// redirect, an error page or the sign-in page.
+func renderAuthorize(c echo.Context, req AuthorizeRequest, form LoginForm, result AuthorizeResult) error {
+	if result.RedirectURL != "" {
+		return c.Redirect(http.StatusFound, result.RedirectURL)
+	}
+	status := http.StatusOK
+	if result.Error != "" {
+		status = http.StatusBadRequest
+	}
+	p := page{AuthorizeResult: result, Request: req, Form: LoginForm{Tenant: form.Tenant, Email: form.Email}}
+	for _, s := range result.Scopes {
+		p.ScopeDescriptions = append(p.ScopeDescriptions, scopeDescriptions[s])
+	}
+	var buf bytes.Buffer
+	if err := pageTemplate.Execute(&buf, p); err != nil {
+		return err
+	}
+	// The page takes a password, so it must not be framed or cached.
+	h := c.Response().Header()
+	h.Set("Cache-Control", "no-store")
+	h.Set("X-Frame-Options", "DENY")
+	h.Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; frame-ancestors 'none'")
+	return c.HTMLBlob(status, buf.Bytes())
+}
@@ -0,0 +1,138 @@
+package oidc
+
+import (
+	"context"
+	"time"
+	"user-management/config"
+	"user-management/storage"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type repository struct {
+	mc  storage.DatabaseConn
+	cfg config.MongoConfig
+}
+
+func NewRepository(mc storage.DatabaseConn, cfg config.MongoConfig) *repository {
+	return &repository{
+		mc:  mc,
+		cfg: cfg,
+	}
+}
+
+func (r *repository) CreateClient(ctx context.Context, client Client) (string, error) {
+	client.CreatedAt = time.Now()
+	ior, err := r.mc.Collection(r.cfg.OIDCClientCollection).InsertOne(ctx, client)
+	if err != nil {
+		return "", err
+	}
+	return ior.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+func (r *repository) FindClientById(ctx context.Context, id string) (Client, error) {
+	var client Client
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return client, ErrClientNotFound
+	}
+	err = r.mc.Collection(r.cfg.OIDCClientCollection).FindOne(ctx, storage.TenantFilter(ctx, bson.M{"_id": oid})).Decode(&client)
+	if err == mongo.ErrNoDocuments {
+		return client, ErrClientNotFound
+	}
+	return client, err
+}
+
+// FindClients lists the clients of ctx's tenant, oldest first.
+func (r *repository) FindClients(ctx context.Context) ([]Client, error) {
+	opts := options.Find().SetSort(bson.D{bson.E{Key: "created_at", Value: 1}})
+	cursor, err := r.mc.Collection(r.cfg.OIDCClientCollection).Find(ctx, storage.TenantFilter(ctx, bson.M{}), opts)
+	if err != nil {
+		return nil, err
+	}
+	var clients []Client
+	err = cursor.All(ctx, &clients)
+	return clients, err
+}
+
+func (r *repository) DeleteClient(ctx context.Context, id primitive.ObjectID) (int64, error) {
+	result, err := r.mc.Collection(r.cfg.OIDCClientCollection).DeleteOne(ctx, storage.TenantFilter(ctx, bson.M{"_id": id}))
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func (r *repository) CreateCode(ctx context.Context, code AuthorizationCode) error {
+	_, err := r.mc.Collection(r.cfg.OIDCCodeCollection).InsertOne(ctx, code)
+	return err
+}
+
+// ClaimCode removes and returns the code with codeHash if it has not expired
+// at now, so a code can only be redeemed once.
+func (r *repository) ClaimCode(ctx context.Context, codeHash string, now time.Time) (AuthorizationCode, error) {
+	var code AuthorizationCode
+	filter := bson.M{"code_hash": codeHash, "expires_at": bson.M{"$gt": now}}
+	err := r.mc.Collection(r.cfg.OIDCCodeCollection).FindOneAndDelete(ctx, filter).Decode(&code)
+	if err == mongo.ErrNoDocuments {
+		return code, ErrCodeNotFound
+	}
+	return code, err
+}
+
+func (r *repository) CreateToken(ctx context.Context, token AccessToken) error {
+	_, err := r.mc.Collection(r.cfg.OIDCTokenCollection).InsertOne(ctx, token)
+	return err
+}
+
+// FindTokenByHash returns the access token with tokenHash if it has not
+// expired at now.
+func (r *repository) FindTokenByHash(ctx context.Context, tokenHash string, now time.Time) (AccessToken, error) {
+	var token AccessToken
+	filter := bson.M{"token_hash": tokenHash, "expires_at": bson.M{"$gt": now}}
+	err := r.mc.Collection(r.cfg.OIDCTokenCollection).FindOne(ctx, filter).Decode(&token)
+	if err == mongo.ErrNoDocuments {
+		return token, ErrTokenNotFound
+	}
+	return token, err
+}
+
+// DeleteToken revokes the access token with tokenHash if it was issued to
+// clientID.
+func (r *repository) DeleteToken(ctx context.Context, tokenHash, clientID string) (int64, error) {
+	result, err := r.mc.Collection(r.cfg.OIDCTokenCollection).DeleteOne(ctx, bson.M{"token_hash": tokenHash, "client_id": clientID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// DeleteCodesByUser removes the unredeemed authorization codes issued for
+// userID.
+func (r *repository) DeleteCodesByUser(ctx context.Context, userID string) (int64, error) {
+	result, err := r.mc.Collection(r.cfg.OIDCCodeCollection).DeleteMany(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// DeleteTokensByUser revokes every access token issued for userID.
+func (r *repository) DeleteTokensByUser(ctx context.Context, userID string) (int64, error) {
+	result, err := r.mc.Collection(r.cfg.OIDCTokenCollection).DeleteMany(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func (r *repository) DeleteTokensByClient(ctx context.Context, clientID string) (int64, error) {
+	result, err := r.mc.Collection(r.cfg.OIDCTokenCollection).DeleteMany(ctx, bson.M{"client_id": clientID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,169 @@
+package oidc
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+	"user-management/auth"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryRepository keeps clients, codes and tokens in process memory, for
+// when the service runs without a database.
+type memoryRepository struct {
+	mu      sync.Mutex
+	clients []Client
+	codes   map[string]AuthorizationCode
+	tokens  map[string]AccessToken
+}
+
+func NewMemoryRepository() *memoryRepository {
+	return &memoryRepository{codes: map[string]AuthorizationCode{}, tokens: map[string]AccessToken{}}
+}
+
+func (r *memoryRepository) CreateClient(ctx context.Context, client Client) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if client.ID.IsZero() {
+		client.ID = primitive.NewObjectID()
+	}
+	client.CreatedAt = time.Now()
+	r.clients = append(r.clients, client)
+	return client.ID.Hex(), nil
+}
+
+func (r *memoryRepository) FindClientById(ctx context.Context, id string) (Client, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Client{}, ErrClientNotFound
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i := r.indexOf(ctx, oid); i >= 0 {
+		return r.clients[i], nil
+	}
+	return Client{}, ErrClientNotFound
+}
+
+func (r *memoryRepository) FindClients(ctx context.Context) ([]Client, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var clients []Client
+	for _, c := range r.clients {
+		if inTenant(ctx, c) {
+			clients = append(clients, c)
+		}
+	}
+	return clients, nil
+}
+
+func (r *memoryRepository) DeleteClient(ctx context.Context, id primitive.ObjectID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.indexOf(ctx, id)
+	if i < 0 {
+		return 0, nil
+	}
+	r.clients = slices.Delete(r.clients, i, i+1)
+	return 1, nil
+}
+
+func (r *memoryRepository) CreateCode(ctx context.Context, code AuthorizationCode) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.codes[code.CodeHash] = code
+	return nil
+}
+
+func (r *memoryRepository) ClaimCode(ctx context.Context, codeHash string, now time.Time) (AuthorizationCode, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	code, ok := r.codes[codeHash]
+	delete(r.codes, codeHash)
+	if !ok || !code.ExpiresAt.After(now) {
+		return AuthorizationCode{}, ErrCodeNotFound
+	}
+	return code, nil
+}
+
+func (r *memoryRepository) CreateToken(ctx context.Context, token AccessToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens[token.TokenHash] = token
+	return nil
+}
+
+func (r *memoryRepository) FindTokenByHash(ctx context.Context, tokenHash string, now time.Time) (AccessToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, ok := r.tokens[tokenHash]
+	if !ok || !token.ExpiresAt.After(now) {
+		return AccessToken{}, ErrTokenNotFound
+	}
+	return token, nil
+}
+
+func (r *memoryRepository) DeleteToken(ctx context.Context, tokenHash, clientID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if token, ok := r.tokens[tokenHash]; !ok || token.ClientID != clientID {
+		return 0, nil
+	}
+	delete(r.tokens, tokenHash)
+	return 1, nil
+}
+
+func (r *memoryRepository) DeleteCodesByUser(ctx context.Context, userID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deleted int64
+	for hash, code := range r.codes {
+		if code.UserID == userID {
+			delete(r.codes, hash)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (r *memoryRepository) DeleteTokensByUser(ctx context.Context, userID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deleted int64
+	for hash, token := range r.tokens {
+		if token.UserID == userID {
+			delete(r.tokens, hash)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (r *memoryRepository) DeleteTokensByClient(ctx context.Context, clientID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deleted int64
+	for hash, token := range r.tokens {
+		if token.ClientID == clientID {
+			delete(r.tokens, hash)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// indexOf returns the position of the client with id in ctx's tenant, or -1.
+// The caller must hold the lock.
+func (r *memoryRepository) indexOf(ctx context.Context, id primitive.ObjectID) int {
+	return slices.IndexFunc(r.clients, func(c Client) bool {
+		return c.ID == id && inTenant(ctx, c)
+	})
+}
+
+// inTenant mirrors storage.TenantFilter for a single client.
+func inTenant(ctx context.Context, c Client) bool {
+	tenantID, scoped := auth.TenantFromContext(ctx)
+	return !scoped || c.TenantID == tenantID
+}
@@ -0,0 +1,167 @@
+package oidc_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-management/app/oidc"
+	"user-management/auth"
+	"user-management/config"
+	"user-management/storage"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func newTestRepository(mt *mtest.T) oidc.Repository {
+	dbConn := storage.NewMongoConn(mt.Client, mt.Client.Database("testdb"))
+	return oidc.NewRepository(dbConn, config.MongoConfig{
+		Database:             "testdb",
+		OIDCClientCollection: "oidc_clients",
+		OIDCCodeCollection:   "oidc_codes",
+		OIDCTokenCollection:  "oidc_tokens",
+	})
+}
+
+func TestRepository_FindClientById(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("tenant scoped", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		oid := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(1, "testdb.oidc_clients", mtest.FirstBatch, bson.D{
+			bson.E{Key: "_id", Value: oid},
+			bson.E{Key: "tenant_id", Value: "t1"},
+			bson.E{Key: "name", Value: "Wiki"},
+			bson.E{Key: "redirect_uris", Value: bson.A{"https://wiki.example.com/callback"}},
+		}))
+
+		client, err := repo.FindClientById(auth.WithTenant(context.Background(), "t1"), oid.Hex())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Wiki", client.Name)
+		assert.Equal(t, []string{"https://wiki.example.com/callback"}, client.RedirectURIs)
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		assert.Equal(t, "t1", filter.Lookup("tenant_id").StringValue())
+	})
+
+	mt.Run("not found", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.oidc_clients", mtest.FirstBatch))
+
+		_, err := repo.FindClientById(context.Background(), primitive.NewObjectID().Hex())
+
+		assert.ErrorIs(t, err, oidc.ErrClientNotFound)
+	})
+
+	mt.Run("invalid id", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+
+		_, err := repo.FindClientById(context.Background(), "bad")
+
+		assert.ErrorIs(t, err, oidc.ErrClientNotFound)
+	})
+}
+
+func TestRepository_ClaimCode(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("removes unexpired code", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: bson.D{
+				{Key: "_id", Value: primitive.NewObjectID()},
+				{Key: "code_hash", Value: "h1"},
+				{Key: "client_id", Value: "c1"},
+				{Key: "user_id", Value: "u1"},
+			}},
+		})
+
+		code, err := repo.ClaimCode(context.Background(), "h1", time.Now())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "c1", code.ClientID)
+		assert.Equal(t, "u1", code.UserID)
+		cmd := mt.GetStartedEvent().Command
+		assert.True(t, cmd.Lookup("remove").Boolean())
+		query := cmd.Lookup("query").Document()
+		assert.Equal(t, "h1", query.Lookup("code_hash").StringValue())
+		assert.Equal(t, bson.TypeDateTime, query.Lookup("expires_at", "$gt").Type)
+	})
+
+	mt.Run("already claimed", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+
+		_, err := repo.ClaimCode(context.Background(), "h1", time.Now())
+
+		assert.ErrorIs(t, err, oidc.ErrCodeNotFound)
+	})
+}
+
+func TestRepository_FindTokenByHash(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("unexpired only", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(1, "testdb.oidc_tokens", mtest.FirstBatch, bson.D{
+			bson.E{Key: "_id", Value: primitive.NewObjectID()},
+			bson.E{Key: "token_hash", Value: "h1"},
+			bson.E{Key: "client_id", Value: "c1"},
+			bson.E{Key: "scope", Value: "openid email"},
+		}))
+
+		token, err := repo.FindTokenByHash(context.Background(), "h1", time.Now())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "openid email", token.Scope)
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		assert.Equal(t, "h1", filter.Lookup("token_hash").StringValue())
+		assert.Equal(t, bson.TypeDateTime, filter.Lookup("expires_at", "$gt").Type)
+	})
+
+	mt.Run("not found", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.oidc_tokens", mtest.FirstBatch))
+
+		_, err := repo.FindTokenByHash(context.Background(), "h1", time.Now())
+
+		assert.ErrorIs(t, err, oidc.ErrTokenNotFound)
+	})
+}
+
+func TestRepository_DeleteToken(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("only the issuing client's token", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}})
+
+		deleted, err := repo.DeleteToken(context.Background(), "h1", "c1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+		q := mt.GetStartedEvent().Command.Lookup("deletes").Array().Index(0).Value().Document().Lookup("q").Document()
+		assert.Equal(t, "h1", q.Lookup("token_hash").StringValue())
+		assert.Equal(t, "c1", q.Lookup("client_id").StringValue())
+	})
+}
+
+func TestRepository_DeleteTokensByUser(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("every token of the user", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 2}})
+
+		deleted, err := repo.DeleteTokensByUser(context.Background(), "u1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+		q := mt.GetStartedEvent().Command.Lookup("deletes").Array().Index(0).Value().Document().Lookup("q").Document()
+		assert.Equal(t, "u1", q.Lookup("user_id").StringValue())
+	})
+}
@@ -0,0 +1,91 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// Signer signs ID tokens with RS256 and publishes the public key for clients
+// to verify them with.
+type Signer struct {
+	key *rsa.PrivateKey
+	kid string
+}
+
+// NewSigner loads the RSA private key in pemKey, PKCS #1 or PKCS #8 encoded.
+// An empty pemKey generates a new key.
+func NewSigner(pemKey string) (*Signer, error) {
+	var key *rsa.PrivateKey
+	if pemKey == "" {
+		var err error
+		if key, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+			return nil, err
+		}
+	} else {
+		block, _ := pem.Decode([]byte(pemKey))
+		if block == nil {
+			return nil, errors.New("oidc signing key is not PEM encoded")
+		}
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			if parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+				return nil, errors.New("oidc signing key is not an RSA private key")
+			}
+		}
+		var ok bool
+		if key, ok = parsed.(*rsa.PrivateKey); !ok {
+			return nil, errors.New("oidc signing key is not an RSA private key")
+		}
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(der)
+	return &Signer{key: key, kid: base64.RawURLEncoding.EncodeToString(sum[:12])}, nil
+}
+
+func (s *Signer) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = s.kid
+	return token.SignedString(s.key)
+}
+
+// JWK is a public key in JSON Web Key format (RFC 7517).
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the key set served at the jwks_uri.
+func (s *Signer) JWKS() JWKS {
+	return JWKS{Keys: []JWK{{
+		KeyType:   "RSA",
+		Use:       "sig",
+		Algorithm: jwt.SigningMethodRS256.Alg(),
+		KeyID:     s.kid,
+		Modulus:   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+		Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+	}}}
+}
+
+// PublicKey is the key ID tokens verify against.
+func (s *Signer) PublicKey() *rsa.PublicKey {
+	return &s.key.PublicKey
+}
@@ -0,0 +1,72 @@
+package oidc_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"user-management/app/oidc"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSigner_PEM(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	for name, block := range map[string]*pem.Block{
+		"pkcs1": {Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)},
+		"pkcs8": {Type: "PRIVATE KEY", Bytes: pkcs8},
+	} {
+		t.Run(name, func(t *testing.T) {
+			signer, err := oidc.NewSigner(string(pem.EncodeToMemory(block)))
+			require.NoError(t, err)
+
+			assert.True(t, key.PublicKey.Equal(signer.PublicKey()))
+			jwk := signer.JWKS().Keys[0]
+			assert.Equal(t, "RSA", jwk.KeyType)
+			assert.Equal(t, "RS256", jwk.Algorithm)
+			n, err := base64.RawURLEncoding.DecodeString(jwk.Modulus)
+			require.NoError(t, err)
+			assert.Equal(t, 0, new(big.Int).SetBytes(n).Cmp(key.N))
+			e, err := base64.RawURLEncoding.DecodeString(jwk.Exponent)
+			require.NoError(t, err)
+			assert.Equal(t, int64(key.E), new(big.Int).SetBytes(e).Int64())
+		})
+	}
+}
+
+func TestNewSigner_Invalid(t *testing.T) {
+	_, err := oidc.NewSigner("not a key")
+	assert.Error(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	require.NoError(t, err)
+	_, err = oidc.NewSigner(string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})))
+	assert.EqualError(t, err, "oidc signing key is not an RSA private key")
+}
+
+func TestSigner_Sign(t *testing.T) {
+	token, err := testSigner.Sign(jwt.RegisteredClaims{Subject: "u1"})
+	require.NoError(t, err)
+
+	claims := jwt.RegisteredClaims{}
+	parsed, err := jwt.ParseWithClaims(token, &claims, func(tok *jwt.Token) (any, error) {
+		return testSigner.PublicKey(), nil
+	}, jwt.WithValidMethods([]string{"RS256"}))
+
+	require.NoError(t, err)
+	assert.Equal(t, "u1", claims.Subject)
+	assert.Equal(t, testSigner.JWKS().Keys[0].KeyID, parsed.Header["kid"])
+}
@@ -0,0 +1,506 @@
+package oidc_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+	"user-management/app/oidc"
+	"user-management/app/tenant"
+	"user-management/app/user"
+	"user-management/auth"
+	"user-management/config"
+	"user-management/response"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testJwtKey   = "testsecret"
+	testPassword = "pass123"
+	testVerifier = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	aliceID      = "60d5ec49f1a2c8b1f8e4e1a1"
+)
+
+var testSigner = func() *oidc.Signer {
+	s, err := oidc.NewSigner("")
+	if err != nil {
+		panic(err)
+	}
+	return s
+}()
+
+func challenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// fakeUsers signs users in like user.Usecase.Login, issuing login tokens with
+// a session per user.
+type fakeUsers struct {
+	users    map[string]user.FindUserResponse
+	inactive map[string]bool
+	logins   []user.SignInRequest
+}
+
+func newFakeUsers() *fakeUsers {
+	return &fakeUsers{
+		users: map[string]user.FindUserResponse{
+			aliceID: {Id: aliceID, TenantID: "t1", Name: "Alice", Email: "alice@example.com"},
+		},
+		inactive: map[string]bool{},
+	}
+}
+
+func (f *fakeUsers) Login(ctx context.Context, req user.SignInRequest) (*response.StdResp[any], error) {
+	f.logins = append(f.logins, req)
+	for id, u := range f.users {
+		if u.Email == req.Email && req.Password == testPassword {
+			token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, auth.Claims{
+				UserID:    id,
+				TenantID:  u.TenantID,
+				SessionID: "session-" + id,
+			}).SignedString([]byte(testJwtKey))
+			if err != nil {
+				return nil, err
+			}
+			return response.SuccessWithData(&user.SignInResponse{Token: token}), nil
+		}
+	}
+	return response.LoginFail(), nil
+}
+
+func (f *fakeUsers) UserActive(ctx context.Context, userID string) (bool, error) {
+	_, ok := f.users[userID]
+	return ok && !f.inactive[userID], nil
+}
+
+func (f *fakeUsers) FindUserById(ctx context.Context, id string) (user.FindUserResponse, error) {
+	u, ok := f.users[id]
+	if !ok {
+		return u, user.ErrUserNotFound
+	}
+	return u, nil
+}
+
+// fakeSessions holds the revoked session ids.
+type fakeSessions map[string]bool
+
+func (s fakeSessions) TouchSession(ctx context.Context, id string) (bool, error) {
+	return !s[id], nil
+}
+
+type fakeTenants map[string]tenant.FindTenantResponse
+
+func (t fakeTenants) FindTenantById(ctx context.Context, id string) (tenant.FindTenantResponse, error) {
+	found, ok := t[id]
+	if !ok {
+		return found, tenant.ErrTenantNotFound
+	}
+	return found, nil
+}
+
+type testProvider struct {
+	uc       oidc.Usecase
+	users    *fakeUsers
+	sessions fakeSessions
+}
+
+func newTestProvider() testProvider {
+	users := newFakeUsers()
+	sessions := fakeSessions{}
+	uc := oidc.NewUsecase(config.OIDCConfig{
+		Issuer:   "https://id.example.com/",
+		CodeTTL:  time.Minute,
+		TokenTTL: time.Hour,
+	}, testJwtKey, testSigner, oidc.NewMemoryRepository(), users, users, sessions, fakeTenants{
+		"t1": {Id: "t1", Slug: "acme"},
+	})
+	return testProvider{uc: uc, users: users, sessions: sessions}
+}
+
+func adminContext() context.Context {
+	return auth.WithClaims(context.Background(), &auth.Claims{UserID: "admin1", Role: auth.RoleAdmin})
+}
+
+// createClient registers a client and returns its credentials.
+func (p testProvider) createClient(t *testing.T, req oidc.CreateClientRequest) oidc.ClientCredentials {
+	if req.Name == "" {
+		req.Name = "Wiki"
+	}
+	if req.RedirectURIs == nil {
+		req.RedirectURIs = []string{"https://wiki.example.com/callback"}
+	}
+	resp, err := p.uc.CreateClient(adminContext(), req)
+	require.NoError(t, err)
+	created := resp.Data.(oidc.CreateClientResponse)
+	return oidc.ClientCredentials{ID: created.ClientID, Secret: created.ClientSecret}
+}
+
+func authorizeRequest(clientID string) oidc.AuthorizeRequest {
+	return oidc.AuthorizeRequest{
+		ResponseType:        oidc.ResponseTypeCode,
+		ClientID:            clientID,
+		RedirectURI:         "https://wiki.example.com/callback",
+		Scope:               "openid profile email offline_access",
+		State:               "xyz",
+		Nonce:               "n-0S6",
+		CodeChallenge:       challenge(testVerifier),
+		CodeChallengeMethod: oidc.CodeChallengeMethodS256,
+	}
+}
+
+// authorize signs alice in and returns the code sent back to the client.
+func (p testProvider) authorize(t *testing.T, clientID string) string {
+	result, err := p.uc.CompleteAuthorization(context.Background(), authorizeRequest(clientID), oidc.LoginForm{
+		Tenant:   "acme",
+		Email:    "alice@example.com",
+		Password: testPassword,
+		Decision: oidc.DecisionAllow,
+	})
+	require.NoError(t, err)
+	require.Empty(t, result.LoginError)
+	redirect, err := url.Parse(result.RedirectURL)
+	require.NoError(t, err)
+	assert.Equal(t, "xyz", redirect.Query().Get("state"))
+	return redirect.Query().Get("code")
+}
+
+func tokenRequest(code string) oidc.TokenRequest {
+	return oidc.TokenRequest{
+		GrantType:    oidc.GrantTypeAuthorizationCode,
+		Code:         code,
+		RedirectURI:  "https://wiki.example.com/callback",
+		CodeVerifier: testVerifier,
+	}
+}
+
+func assertOAuthError(t *testing.T, err error, code string) {
+	t.Helper()
+	var oauthErr *oidc.Error
+	if assert.ErrorAs(t, err, &oauthErr) {
+		assert.Equal(t, code, oauthErr.Code)
+	}
+}
+
+func TestUsecaseAuthorizationCodeFlow(t *testing.T) {
+	p := newTestProvider()
+	creds := p.createClient(t, oidc.CreateClientRequest{})
+	ctx := context.Background()
+
+	result, err := p.uc.StartAuthorization(ctx, authorizeRequest(creds.ID))
+	require.NoError(t, err)
+	assert.Equal(t, "Wiki", result.Client.Name)
+	assert.Equal(t, []string{oidc.ScopeOpenID, oidc.ScopeProfile, oidc.ScopeEmail}, result.Scopes)
+
+	code := p.authorize(t, creds.ID)
+	tokens, err := p.uc.Token(ctx, tokenRequest(code), creds)
+	require.NoError(t, err)
+	assert.Equal(t, oidc.TokenTypeBearer, tokens.TokenType)
+	assert.Equal(t, int64(3600), tokens.ExpiresIn)
+	assert.Equal(t, "openid profile email", tokens.Scope)
+
+	claims := &oidc.IDTokenClaims{}
+	_, err = jwt.ParseWithClaims(tokens.IDToken, claims, func(*jwt.Token) (interface{}, error) {
+		return testSigner.PublicKey(), nil
+	}, jwt.WithIssuer("https://id.example.com"), jwt.WithAudience(creds.ID), jwt.WithValidMethods([]string{"RS256"}))
+	require.NoError(t, err)
+	assert.Equal(t, aliceID, claims.Subject)
+	assert.Equal(t, "n-0S6", claims.Nonce)
+	assert.Equal(t, "Alice", claims.Name)
+	assert.Equal(t, "alice@example.com", claims.Email)
+	assert.Equal(t, "session-"+aliceID, claims.SessionID)
+	sum := sha256.Sum256([]byte(tokens.AccessToken))
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(sum[:16]), claims.AtHash)
+
+	info, err := p.uc.UserInfo(ctx, tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, oidc.UserInfo{Subject: aliceID, Name: "Alice", Email: "alice@example.com"}, info)
+
+	introspection, err := p.uc.Introspect(ctx, creds, tokens.AccessToken)
+	require.NoError(t, err)
+	assert.True(t, introspection.Active)
+	assert.Equal(t, creds.ID, introspection.ClientID)
+	assert.Equal(t, aliceID, introspection.Subject)
+
+	// Codes are single use.
+	_, err = p.uc.Token(ctx, tokenRequest(code), creds)
+	assertOAuthError(t, err, oidc.ErrorInvalidGrant)
+
+	require.NoError(t, p.uc.Revoke(ctx, creds, tokens.AccessToken))
+	introspection, err = p.uc.Introspect(ctx, creds, tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, oidc.Introspection{}, introspection)
+	_, err = p.uc.UserInfo(ctx, tokens.AccessToken)
+	assertOAuthError(t, err, oidc.ErrorInvalidToken)
+}
+
+func TestUsecaseStartAuthorization_Rejected(t *testing.T) {
+	p := newTestProvider()
+	creds := p.createClient(t, oidc.CreateClientRequest{})
+
+	unknown := authorizeRequest("60d5ec49f1a2c8b1f8e4e1ff")
+	result, err := p.uc.StartAuthorization(context.Background(), unknown)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.Error)
+	assert.Empty(t, result.RedirectURL)
+
+	badRedirect := authorizeRequest(creds.ID)
+	badRedirect.RedirectURI = "https://evil.example.com/callback"
+	result, err = p.uc.StartAuthorization(context.Background(), badRedirect)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.Error)
+	assert.Empty(t, result.RedirectURL)
+}
+
+func TestUsecaseStartAuthorization_ErrorRedirects(t *testing.T) {
+	p := newTestProvider()
+	creds := p.createClient(t, oidc.CreateClientRequest{})
+	tests := map[string]struct {
+		modify func(*oidc.AuthorizeRequest)
+		error  string
+	}{
+		"token response type": {func(r *oidc.AuthorizeRequest) { r.ResponseType = "token" }, oidc.ErrorUnsupportedResponseType},
+		"no openid scope":     {func(r *oidc.AuthorizeRequest) { r.Scope = "profile" }, oidc.ErrorInvalidScope},
+		"no code challenge":   {func(r *oidc.AuthorizeRequest) { r.CodeChallenge = "" }, oidc.ErrorInvalidRequest},
+		"plain challenge":     {func(r *oidc.AuthorizeRequest) { r.CodeChallengeMethod = "plain" }, oidc.ErrorInvalidRequest},
+		"prompt none":         {func(r *oidc.AuthorizeRequest) { r.Prompt = "none" }, oidc.ErrorLoginRequired},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := authorizeRequest(creds.ID)
+			tt.modify(&req)
+
+			result, err := p.uc.StartAuthorization(context.Background(), req)
+
+			assert.NoError(t, err)
+			redirect, err := url.Parse(result.RedirectURL)
+			require.NoError(t, err)
+			assert.Equal(t, "wiki.example.com", redirect.Host)
+			assert.Equal(t, tt.error, redirect.Query().Get("error"))
+			assert.Equal(t, "xyz", redirect.Query().Get("state"))
+		})
+	}
+}
+
+func TestUsecaseCompleteAuthorization_Denied(t *testing.T) {
+	p := newTestProvider()
+	creds := p.createClient(t, oidc.CreateClientRequest{})
+
+	result, err := p.uc.CompleteAuthorization(context.Background(), authorizeRequest(creds.ID), oidc.LoginForm{Decision: "deny"})
+
+	assert.NoError(t, err)
+	assert.Contains(t, result.RedirectURL, "error=access_denied")
+	assert.Empty(t, p.users.logins)
+}
+
+func TestUsecaseCompleteAuthorization_LoginFailed(t *testing.T) {
+	p := newTestProvider()
+	creds := p.createClient(t, oidc.CreateClientRequest{})
+
+	result, err := p.uc.CompleteAuthorization(context.Background(), authorizeRequest(creds.ID), oidc.LoginForm{
+		Email:    "alice@example.com",
+		Password: "wrong",
+		Decision: oidc.DecisionAllow,
+	})
+
+	assert.NoError(t, err)
+	assert.Empty(t, result.RedirectURL)
+	assert.Equal(t, response.LoginFail().Message, result.LoginError)
+	assert.Equal(t, "Wiki", result.Client.Name)
+}
+
+func TestUsecaseCompleteAuthorization_TenantClient(t *testing.T) {
+	p := newTestProvider()
+	creds := p.createClient(t, oidc.CreateClientRequest{TenantID: "t1"})
+
+	result, err := p.uc.StartAuthorization(context.Background(), authorizeRequest(creds.ID))
+	require.NoError(t, err)
+	assert.Equal(t, "acme", result.TenantSlug)
+
+	_, err = p.uc.CompleteAuthorization(context.Background(), authorizeRequest(creds.ID), oidc.LoginForm{
+		Tenant:   "other",
+		Email:    "alice@example.com",
+		Password: testPassword,
+		Decision: oidc.DecisionAllow,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "acme", p.users.logins[0].Tenant)
+}
+
+func TestUsecaseCompleteAuthorization_OtherTenant(t *testing.T) {
+	p := newTestProvider()
+	p.users.users[aliceID] = user.FindUserResponse{Id: aliceID, TenantID: "t2", Email: "alice@example.com"}
+	creds := p.createClient(t, oidc.CreateClientRequest{TenantID: "t1"})
+
+	result, err := p.uc.CompleteAuthorization(context.Background(), authorizeRequest(creds.ID), oidc.LoginForm{
+		Email:    "alice@example.com",
+		Password: testPassword,
+		Decision: oidc.DecisionAllow,
+	})
+
+	assert.NoError(t, err)
+	assert.Empty(t, result.RedirectURL)
+	assert.Equal(t, response.LoginFail().Message, result.LoginError)
+}
+
+func TestUsecaseToken_Rejected(t *testing.T) {
+	p := newTestProvider()
+	creds := p.createClient(t, oidc.CreateClientRequest{})
+	other := p.createClient(t, oidc.CreateClientRequest{Name: "Other"})
+	tests := map[string]struct {
+		modify func(*oidc.TokenRequest, *oidc.ClientCredentials)
+		error  string
+	}{
+		"wrong secret":   {func(r *oidc.TokenRequest, c *oidc.ClientCredentials) { c.Secret = "wrong" }, oidc.ErrorInvalidClient},
+		"unknown client": {func(r *oidc.TokenRequest, c *oidc.ClientCredentials) { c.ID = "nope" }, oidc.ErrorInvalidClient},
+		"other client":   {func(r *oidc.TokenRequest, c *oidc.ClientCredentials) { *c = other }, oidc.ErrorInvalidGrant},
+		"wrong grant":    {func(r *oidc.TokenRequest, c *oidc.ClientCredentials) { r.GrantType = "password" }, oidc.ErrorUnsupportedGrantType},
+		"wrong redirect": {func(r *oidc.TokenRequest, c *oidc.ClientCredentials) { r.RedirectURI += "/other" }, oidc.ErrorInvalidGrant},
+		"wrong verifier": {func(r *oidc.TokenRequest, c *oidc.ClientCredentials) { r.CodeVerifier = strings.Repeat("a", 43) }, oidc.ErrorInvalidGrant},
+		"no verifier":    {func(r *oidc.TokenRequest, c *oidc.ClientCredentials) { r.CodeVerifier = "" }, oidc.ErrorInvalidGrant},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req, c := tokenRequest(p.authorize(t, creds.ID)), creds
+			tt.modify(&req, &c)
+
+			_, err := p.uc.Token(context.Background(), req, c)
+
+			assertOAuthError(t, err, tt.error)
+		})
+	}
+}
+
+func TestUsecaseToken_PublicClient(t *testing.T) {
+	p := newTestProvider()
+	creds := p.createClient(t, oidc.CreateClientRequest{Public: true})
+	assert.Empty(t, creds.Secret)
+
+	tokens, err := p.uc.Token(context.Background(), tokenRequest(p.authorize(t, creds.ID)), creds)
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+
+	// Public clients may revoke their tokens but not introspect them.
+	_, err = p.uc.Introspect(context.Background(), creds, tokens.AccessToken)
+	assertOAuthError(t, err, oidc.ErrorInvalidClient)
+	assert.NoError(t, p.uc.Revoke(context.Background(), creds, tokens.AccessToken))
+
+	creds.Secret = "made-up"
+	_, err = p.uc.Token(context.Background(), tokenRequest(p.authorize(t, creds.ID)), creds)
+	assertOAuthError(t, err, oidc.ErrorInvalidClient)
+}
+
+func TestUsecaseToken_UserInactive(t *testing.T) {
+	p := newTestProvider()
+	creds := p.createClient(t, oidc.CreateClientRequest{})
+	code := p.authorize(t, creds.ID)
+	p.users.inactive[aliceID] = true
+
+	_, err := p.uc.Token(context.Background(), tokenRequest(code), creds)
+
+	assertOAuthError(t, err, oidc.ErrorInvalidGrant)
+}
+
+func TestUsecaseUserInfo_SessionRevoked(t *testing.T) {
+	p := newTestProvider()
+	creds := p.createClient(t, oidc.CreateClientRequest{})
+	tokens, err := p.uc.Token(context.Background(), tokenRequest(p.authorize(t, creds.ID)), creds)
+	require.NoError(t, err)
+	p.sessions["session-"+aliceID] = true
+
+	_, err = p.uc.UserInfo(context.Background(), tokens.AccessToken)
+
+	assertOAuthError(t, err, oidc.ErrorInvalidToken)
+}
+
+func TestUsecaseRevoke_OtherClient(t *testing.T) {
+	p := newTestProvider()
+	creds := p.createClient(t, oidc.CreateClientRequest{})
+	other := p.createClient(t, oidc.CreateClientRequest{Name: "Other"})
+	tokens, err := p.uc.Token(context.Background(), tokenRequest(p.authorize(t, creds.ID)), creds)
+	require.NoError(t, err)
+
+	assert.NoError(t, p.uc.Revoke(context.Background(), other, tokens.AccessToken))
+
+	_, err = p.uc.UserInfo(context.Background(), tokens.AccessToken)
+	assert.NoError(t, err)
+}
+
+func TestUsecaseUserInfo_Scopes(t *testing.T) {
+	p := newTestProvider()
+	creds := p.createClient(t, oidc.CreateClientRequest{})
+	req := authorizeRequest(creds.ID)
+	req.Scope = "openid"
+	result, err := p.uc.CompleteAuthorization(context.Background(), req, oidc.LoginForm{
+		Email:    "alice@example.com",
+		Password: testPassword,
+		Decision: oidc.DecisionAllow,
+	})
+	require.NoError(t, err)
+	redirect, _ := url.Parse(result.RedirectURL)
+	tokens, err := p.uc.Token(context.Background(), tokenRequest(redirect.Query().Get("code")), creds)
+	require.NoError(t, err)
+
+	info, err := p.uc.UserInfo(context.Background(), tokens.AccessToken)
+
+	assert.NoError(t, err)
+	assert.Equal(t, oidc.UserInfo{Subject: aliceID}, info)
+}
+
+func TestUsecaseCreateClient_TenantAdmin(t *testing.T) {
+	p := newTestProvider()
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{UserID: "admin1", TenantID: "t1", Role: auth.RoleAdmin})
+
+	resp, err := p.uc.CreateClient(ctx, oidc.CreateClientRequest{TenantID: "t2", Name: "Wiki", RedirectURIs: []string{"https://wiki.example.com/callback"}})
+	require.NoError(t, err)
+	id := resp.Data.(oidc.CreateClientResponse).ClientID
+
+	resp, err = p.uc.FindClientById(ctx, id)
+	assert.NoError(t, err)
+	found := resp.Data.(oidc.FindClientResponse)
+	assert.Equal(t, "t1", found.TenantID)
+	assert.Equal(t, "admin1", found.CreatedBy)
+	assert.False(t, found.Public)
+
+	// Other tenants' admins cannot see it.
+	other := auth.WithClaims(context.Background(), &auth.Claims{TenantID: "t2", Role: auth.RoleAdmin})
+	resp, err = p.uc.FindClientById(other, id)
+	assert.NoError(t, err)
+	assert.Equal(t, response.ClientNotFound(), resp)
+}
+
+func TestUsecaseDeleteClient(t *testing.T) {
+	p := newTestProvider()
+	creds := p.createClient(t, oidc.CreateClientRequest{})
+	tokens, err := p.uc.Token(context.Background(), tokenRequest(p.authorize(t, creds.ID)), creds)
+	require.NoError(t, err)
+
+	resp, err := p.uc.DeleteClient(adminContext(), creds.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, response.Success(), resp)
+
+	_, err = p.uc.UserInfo(context.Background(), tokens.AccessToken)
+	assertOAuthError(t, err, oidc.ErrorInvalidToken)
+	resp, err = p.uc.DeleteClient(adminContext(), creds.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, response.ClientNotFound(), resp)
+}
+
+func TestUsecaseDiscovery(t *testing.T) {
+	p := newTestProvider()
+
+	d := p.uc.Discovery()
+
+	assert.Equal(t, "https://id.example.com", d.Issuer)
+	assert.Equal(t, "https://id.example.com/oauth2/authorize", d.AuthorizationEndpoint)
+	assert.Equal(t, "https://id.example.com/userinfo", d.UserinfoEndpoint)
+	assert.Equal(t, []string{"S256"}, d.CodeChallengeMethodsSupported)
+	assert.Len(t, p.uc.JWKS().Keys, 1)
+}
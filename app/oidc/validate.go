@@ -0,0 +1,59 @@
+package oidc
+
+import (
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+	"user-management/response"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// pkcePattern matches code challenges and verifiers (RFC 7636 section 4.1).
+var pkcePattern = regexp.MustCompile(`^[A-Za-z0-9\-._~]{43,128}$`)
+
+func (r CreateClientRequest) RequestValidation() *response.StdResp[any] {
+	if checkLen(r.Name) == 0 {
+		return response.MandatoryMissing("name")
+	}
+	if len(r.RedirectURIs) == 0 {
+		return response.MandatoryMissing("redirect_uris")
+	}
+	for _, uri := range r.RedirectURIs {
+		if !validRedirectURI(uri) {
+			return response.InvalidData("redirect_uris")
+		}
+	}
+	return response.Success()
+}
+
+func IdValidation(id string) *response.StdResp[any] {
+	_, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return response.InvalidData(ParamID)
+	}
+	return response.Success()
+}
+
+// validRedirectURI accepts absolute https URLs without a fragment, and http
+// ones for loopback hosts, which native and development apps listen on.
+func validRedirectURI(uri string) bool {
+	u, err := url.Parse(uri)
+	if err != nil || u.Host == "" || u.Fragment != "" || u.User != nil {
+		return false
+	}
+	switch u.Scheme {
+	case "https":
+		return true
+	case "http":
+		host := u.Hostname()
+		ip := net.ParseIP(host)
+		return host == "localhost" || (ip != nil && ip.IsLoopback())
+	}
+	return false
+}
+
+func checkLen(s string) int {
+	return len([]rune(strings.TrimSpace(s)))
+}
@@ -0,0 +1,17 @@
+package privacy
+
+const (
+	ParamID = "id"
+
+	// Kinds of data an erasure receipt accounts for.
+	DataUser             = "user"
+	DataLoginHistory     = "login_history"
+	DataSessions         = "sessions"
+	DataGroupMemberships = "group_memberships"
+	DataAvatar           = "avatar_images"
+	DataIdentityLinks    = "identity_links"
+	DataOIDCCodes        = "oidc_codes"
+	DataOIDCTokens       = "oidc_tokens"
+	DataInvites          = "invites"
+	DataStatusHistory    = "status_history"
+)
@@ -0,0 +1,101 @@
+package privacy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"user-management/logger"
+	"user-management/response"
+
+	echo "github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Usecase interface {
+	ExportMyData(ctx context.Context) (*response.StdResp[any], error)
+	ExportUserData(ctx context.Context, userID string) (*response.StdResp[any], error)
+	EraseUser(ctx context.Context, userID string) (*response.StdResp[any], error)
+}
+
+type Handler interface {
+	ExportMyData(c echo.Context) error
+	ExportUserData(c echo.Context) error
+	EraseUser(c echo.Context) error
+}
+
+type handler struct {
+	usecase Usecase
+}
+
+func NewHandler(u Usecase) *handler {
+	return &handler{
+		usecase: u,
+	}
+}
+
+// ExportMyData serves GET /me/data-export.
+func (h *handler) ExportMyData(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.ExportMyData(ctx)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return sendExport(c, resp)
+}
+
+// ExportUserData serves GET /users/:id/data-export.
+func (h *handler) ExportUserData(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	userID := c.Param(ParamID)
+	if !primitive.IsValidObjectID(userID) {
+		return c.JSON(response.InvalidData(ParamID).WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.ExportUserData(ctx, userID)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return sendExport(c, resp)
+}
+
+// EraseUser serves POST /users/:id/erasure.
+func (h *handler) EraseUser(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	userID := c.Param(ParamID)
+	if !primitive.IsValidObjectID(userID) {
+		return c.JSON(response.InvalidData(ParamID).WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.EraseUser(ctx, userID)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+// sendExport answers with the export itself, as a file to download, or with
+// the error response when there is none.
+func sendExport(c echo.Context, resp *response.StdResp[any]) error {
+	if !resp.IsSuccess() {
+		return c.JSON(resp.WithHTTPStatus())
+	}
+	export := resp.Data.(Export)
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="data-export-%s.json"`, export.User.Id))
+	return c.JSONPretty(http.StatusOK, export, "  ")
+}
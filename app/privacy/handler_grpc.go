@@ -0,0 +1,70 @@
+package privacy
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+	privacygrpc "user-management/app/privacy/grpc/gen/go/privacy/v1"
+	"user-management/response"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type GrpcHandler struct {
+	usecase Usecase
+}
+
+func NewGrpcHandler(u Usecase) *GrpcHandler {
+	return &GrpcHandler{usecase: u}
+}
+
+func (h *GrpcHandler) ExportUserData(ctx context.Context, req *privacygrpc.ExportUserDataRequest) (*privacygrpc.ExportUserDataResponse, error) {
+	if !primitive.IsValidObjectID(req.UserId) {
+		resp := response.InvalidData("user_id")
+		return &privacygrpc.ExportUserDataResponse{Code: resp.Code, Message: resp.Message}, nil
+	}
+	resp, err := h.usecase.ExportUserData(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	out := &privacygrpc.ExportUserDataResponse{Code: resp.Code, Message: resp.Message}
+	if resp.IsSuccess() {
+		data, err := json.Marshal(resp.Data)
+		if err != nil {
+			return nil, err
+		}
+		out.Data = string(data)
+	}
+	return out, nil
+}
+
+func (h *GrpcHandler) EraseUser(ctx context.Context, req *privacygrpc.EraseUserRequest) (*privacygrpc.EraseUserResponse, error) {
+	if !primitive.IsValidObjectID(req.UserId) {
+		resp := response.InvalidData("user_id")
+		return &privacygrpc.EraseUserResponse{Code: resp.Code, Message: resp.Message}, nil
+	}
+	resp, err := h.usecase.EraseUser(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	out := &privacygrpc.EraseUserResponse{Code: resp.Code, Message: resp.Message}
+	if resp.IsSuccess() {
+		out.Receipt = toGrpcReceipt(resp.Data.(Receipt))
+	}
+	return out, nil
+}
+
+func toGrpcReceipt(r Receipt) *privacygrpc.Receipt {
+	out := &privacygrpc.Receipt{
+		Id:        r.ID,
+		UserId:    r.UserID,
+		TenantId:  r.TenantID,
+		ErasedBy:  r.ErasedBy,
+		ErasedAt:  r.ErasedAt.Format(time.RFC3339),
+		Signature: r.Signature,
+	}
+	for _, e := range r.Erased {
+		out.Erased = append(out.Erased, &privacygrpc.Erased{Data: e.Data, Count: e.Count})
+	}
+	return out
+}
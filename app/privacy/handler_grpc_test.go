@@ -0,0 +1,79 @@
+package privacy_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+	"user-management/app/privacy"
+	privacygrpc "user-management/app/privacy/grpc/gen/go/privacy/v1"
+	"user-management/app/user"
+	"user-management/response"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGrpcHandler_ExportUserData(t *testing.T) {
+	mockUc := new(mockUsecase)
+	handler := privacy.NewGrpcHandler(mockUc)
+
+	userID := primitive.NewObjectID().Hex()
+	mockUc.On("ExportUserData", mock.Anything, userID).Return(response.SuccessWithData(privacy.Export{
+		User: user.FindUserResponse{Id: userID, Email: "alice@example.com"},
+	}), nil)
+
+	resp, err := handler.ExportUserData(context.Background(), &privacygrpc.ExportUserDataRequest{UserId: userID})
+	assert.NoError(t, err)
+	assert.Equal(t, response.Success().Code, resp.Code)
+	var got privacy.Export
+	assert.NoError(t, json.Unmarshal([]byte(resp.Data), &got))
+	assert.Equal(t, "alice@example.com", got.User.Email)
+}
+
+func TestGrpcHandler_ExportUserData_InvalidID(t *testing.T) {
+	mockUc := new(mockUsecase)
+	handler := privacy.NewGrpcHandler(mockUc)
+
+	resp, err := handler.ExportUserData(context.Background(), &privacygrpc.ExportUserDataRequest{UserId: "nope"})
+	assert.NoError(t, err)
+	assert.Equal(t, response.InvalidData("user_id").Code, resp.Code)
+	assert.Empty(t, resp.Data)
+	mockUc.AssertNotCalled(t, "ExportUserData", mock.Anything, mock.Anything)
+}
+
+func TestGrpcHandler_EraseUser(t *testing.T) {
+	mockUc := new(mockUsecase)
+	handler := privacy.NewGrpcHandler(mockUc)
+
+	userID := primitive.NewObjectID().Hex()
+	mockUc.On("EraseUser", mock.Anything, userID).Return(response.SuccessWithData(privacy.Receipt{
+		ID:        "r1",
+		UserID:    userID,
+		ErasedAt:  time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Erased:    []privacy.Erased{{Data: privacy.DataUser, Count: 1}},
+		Signature: "abc",
+	}), nil)
+
+	resp, err := handler.EraseUser(context.Background(), &privacygrpc.EraseUserRequest{UserId: userID})
+	assert.NoError(t, err)
+	assert.Equal(t, response.Success().Code, resp.Code)
+	assert.Equal(t, "2025-01-02T03:04:05Z", resp.Receipt.ErasedAt)
+	assert.Equal(t, "abc", resp.Receipt.Signature)
+	assert.Len(t, resp.Receipt.Erased, 1)
+	assert.Equal(t, int64(1), resp.Receipt.Erased[0].Count)
+}
+
+func TestGrpcHandler_EraseUser_Forbidden(t *testing.T) {
+	mockUc := new(mockUsecase)
+	handler := privacy.NewGrpcHandler(mockUc)
+
+	userID := primitive.NewObjectID().Hex()
+	mockUc.On("EraseUser", mock.Anything, userID).Return(response.Forbidden(), nil)
+
+	resp, err := handler.EraseUser(context.Background(), &privacygrpc.EraseUserRequest{UserId: userID})
+	assert.NoError(t, err)
+	assert.Equal(t, response.Forbidden().Code, resp.Code)
+	assert.Nil(t, resp.Receipt)
+}
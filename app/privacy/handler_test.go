@@ -0,0 +1,125 @@
+package privacy_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-management/app/privacy"
+	"user-management/app/user"
+	"user-management/logger"
+	"user-management/response"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockUsecase struct {
+	mock.Mock
+}
+
+func (m *mockUsecase) ExportMyData(ctx context.Context) (*response.StdResp[any], error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) ExportUserData(ctx context.Context, userID string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) EraseUser(ctx context.Context, userID string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func newTestContext(method, target string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(method, target, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	ctx := context.WithValue(c.Request().Context(), logger.LogContext, logger.NewZap())
+	c.SetRequest(req.WithContext(ctx))
+	return c, rec
+}
+
+func TestHandlerExportMyData(t *testing.T) {
+	c, rec := newTestContext(http.MethodGet, "/me/data-export")
+
+	mockUc := new(mockUsecase)
+	mockUc.On("ExportMyData", mock.Anything).Return(response.SuccessWithData(privacy.Export{
+		User:   user.FindUserResponse{Id: "u1", Email: "alice@example.com"},
+		Groups: []string{"ops"},
+	}), nil)
+
+	err := privacy.NewHandler(mockUc).ExportMyData(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `attachment; filename="data-export-u1.json"`, rec.Header().Get(echo.HeaderContentDisposition))
+	// The body is the export itself, not a response envelope.
+	var got privacy.Export
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.Equal(t, "alice@example.com", got.User.Email)
+	assert.Equal(t, []string{"ops"}, got.Groups)
+}
+
+func TestHandlerExportUserData_Forbidden(t *testing.T) {
+	userID := primitive.NewObjectID().Hex()
+	c, rec := newTestContext(http.MethodGet, "/users/"+userID+"/data-export")
+	c.SetParamNames(privacy.ParamID)
+	c.SetParamValues(userID)
+
+	mockUc := new(mockUsecase)
+	mockUc.On("ExportUserData", mock.Anything, userID).Return(response.Forbidden(), nil)
+
+	err := privacy.NewHandler(mockUc).ExportUserData(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Empty(t, rec.Header().Get(echo.HeaderContentDisposition))
+}
+
+func TestHandlerExportUserData_InvalidID(t *testing.T) {
+	c, rec := newTestContext(http.MethodGet, "/users/nope/data-export")
+	c.SetParamNames(privacy.ParamID)
+	c.SetParamValues("nope")
+
+	mockUc := new(mockUsecase)
+
+	err := privacy.NewHandler(mockUc).ExportUserData(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUc.AssertNotCalled(t, "ExportUserData", mock.Anything, mock.Anything)
+}
+
+func TestHandlerEraseUser(t *testing.T) {
+	userID := primitive.NewObjectID().Hex()
+	c, rec := newTestContext(http.MethodPost, "/users/"+userID+"/erasure")
+	c.SetParamNames(privacy.ParamID)
+	c.SetParamValues(userID)
+
+	mockUc := new(mockUsecase)
+	mockUc.On("EraseUser", mock.Anything, userID).Return(response.SuccessWithData(privacy.Receipt{ID: "r1", UserID: userID, Signature: "abc"}), nil)
+
+	err := privacy.NewHandler(mockUc).EraseUser(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"signature":"abc"`)
+}
+
+func TestHandlerEraseUser_Error(t *testing.T) {
+	userID := primitive.NewObjectID().Hex()
+	c, rec := newTestContext(http.MethodPost, "/users/"+userID+"/erasure")
+	c.SetParamNames(privacy.ParamID)
+	c.SetParamValues(userID)
+
+	mockUc := new(mockUsecase)
+	mockUc.On("EraseUser", mock.Anything, userID).Return((*response.StdResp[any])(nil), errors.New("db down"))
+
+	err := privacy.NewHandler(mockUc).EraseUser(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
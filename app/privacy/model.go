@@ -0,0 +1,42 @@
+package privacy
+
+import (
+	"time"
+	"user-management/app/federation"
+	"user-management/app/loginhistory"
+	"user-management/app/session"
+	"user-management/app/user"
+)
+
+// Export is everything held about one user, as handed out for a data access
+// request. Sessions lists the ones still active; status changes are the
+// audit trail kept about the account. Identities are the accounts at external
+// identity providers the user logs in with.
+type Export struct {
+	GeneratedAt   time.Time                 `json:"generated_at"`
+	User          user.FindUserResponse     `json:"user"`
+	StatusHistory []user.StatusChange       `json:"status_history"`
+	Groups        []string                  `json:"groups"`
+	Logins        []loginhistory.Attempt    `json:"logins"`
+	Sessions      []session.Session         `json:"sessions"`
+	Identities    []federation.IdentityLink `json:"identities"`
+}
+
+// Receipt records an erasure: whose data was erased, by whom, when and how
+// much of each kind. Signature is an HMAC-SHA256 of the other fields, see
+// SignReceipt. It names no personal data beyond the ids.
+type Receipt struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	TenantID  string    `json:"tenant_id,omitempty"`
+	ErasedBy  string    `json:"erased_by,omitempty"`
+	ErasedAt  time.Time `json:"erased_at"`
+	Erased    []Erased  `json:"erased"`
+	Signature string    `json:"signature"`
+}
+
+// Erased counts the records of one kind of data that were deleted.
+type Erased struct {
+	Data  string `json:"data"`
+	Count int64  `json:"count"`
+}
@@ -0,0 +1,52 @@
+package privacy
+
+import (
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"user-management/config"
+)
+
+// receiptKeyInfo labels the receipt key derived from the JWT key, so that the
+// two never coincide.
+const receiptKeyInfo = "user-management privacy receipt signing v1"
+
+// ReceiptKey returns the key receipts are signed with: PRIVACY_RECEIPT_KEY,
+// or when that is not set a key derived from the JWT key with HKDF-SHA256.
+func ReceiptKey(cfg *config.AppConfig) []byte {
+	if cfg.Privacy.ReceiptKey != "" {
+		return []byte(cfg.Privacy.ReceiptKey)
+	}
+	// HKDF only fails for keys longer than 255 hashes.
+	key, _ := hkdf.Key(sha256.New, []byte(cfg.Crypto.JwtKey), nil, receiptKeyInfo, sha256.Size)
+	return key
+}
+
+// SignReceipt returns the signature of r: the hex HMAC-SHA256, under key, of
+// r encoded as JSON with an empty signature.
+func SignReceipt(key []byte, r Receipt) (string, error) {
+	r.Signature = ""
+	payload, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// VerifyReceipt reports whether r carries a valid signature under key.
+func VerifyReceipt(key []byte, r Receipt) bool {
+	want, err := SignReceipt(key, r)
+	if err != nil {
+		return false
+	}
+	got, err := hex.DecodeString(r.Signature)
+	if err != nil {
+		return false
+	}
+	wantBytes, _ := hex.DecodeString(want)
+	return hmac.Equal(got, wantBytes)
+}
@@ -0,0 +1,51 @@
+package privacy_test
+
+import (
+	"testing"
+	"time"
+	"user-management/app/privacy"
+	"user-management/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignReceipt(t *testing.T) {
+	key := []byte("receipt-key")
+	r := privacy.Receipt{
+		ID:       "r1",
+		UserID:   "u1",
+		ErasedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Erased:   []privacy.Erased{{Data: privacy.DataUser, Count: 1}},
+	}
+
+	sig, err := privacy.SignReceipt(key, r)
+	require.NoError(t, err)
+	r.Signature = sig
+	assert.True(t, privacy.VerifyReceipt(key, r))
+
+	// The signature does not depend on the one already there.
+	again, err := privacy.SignReceipt(key, r)
+	require.NoError(t, err)
+	assert.Equal(t, sig, again)
+
+	assert.False(t, privacy.VerifyReceipt([]byte("other-key"), r))
+	tampered := r
+	tampered.Erased = []privacy.Erased{{Data: privacy.DataUser, Count: 0}}
+	assert.False(t, privacy.VerifyReceipt(key, tampered))
+	tampered = r
+	tampered.Signature = "not hex"
+	assert.False(t, privacy.VerifyReceipt(key, tampered))
+}
+
+func TestReceiptKey(t *testing.T) {
+	cfg := &config.AppConfig{Crypto: config.CryptoCredential{JwtKey: "jwt"}}
+	derived := privacy.ReceiptKey(cfg)
+	assert.Len(t, derived, 32)
+	assert.NotEqual(t, []byte("jwt"), derived)
+	assert.Equal(t, derived, privacy.ReceiptKey(cfg))
+	cfg.Crypto.JwtKey = "other"
+	assert.NotEqual(t, derived, privacy.ReceiptKey(cfg))
+	cfg.Privacy.ReceiptKey = "receipts"
+	assert.Equal(t, []byte("receipts"), privacy.ReceiptKey(cfg))
+}
@@ -0,0 +1,259 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+	"time"
+	"user-management/app/avatar"
+	"user-management/app/federation"
+	"user-management/app/loginhistory"
+	"user-management/app/session"
+	"user-management/app/user"
+	"user-management/auth"
+	"user-management/logger"
+	"user-management/response"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type UserRepository interface {
+	FindUserById(ctx context.Context, id string) (user.FindUserResponse, error)
+	FindStatusHistory(ctx context.Context, id string) ([]user.StatusChange, error)
+	DeleteUser(ctx context.Context, id string) (int64, error)
+}
+
+type LoginRepository interface {
+	FindAllAttemptsByUser(ctx context.Context, userID string) ([]loginhistory.Attempt, error)
+	DeleteAttemptsByUser(ctx context.Context, userID string) (int64, error)
+}
+
+type SessionRepository interface {
+	FindSessionsByUser(ctx context.Context, userID string, now time.Time) ([]session.Session, error)
+	DeleteSessionsByUser(ctx context.Context, userID string) (int64, error)
+}
+
+type GroupRepository interface {
+	FindGroupNamesByUser(ctx context.Context, userID string) ([]string, error)
+	RemoveUserMemberships(ctx context.Context, userID string) (int64, error)
+}
+
+type IdentityRepository interface {
+	FindLinksByUser(ctx context.Context, userID string) ([]federation.IdentityLink, error)
+	DeleteLinksByUser(ctx context.Context, userID string) (int64, error)
+}
+
+type OIDCRepository interface {
+	DeleteCodesByUser(ctx context.Context, userID string) (int64, error)
+	DeleteTokensByUser(ctx context.Context, userID string) (int64, error)
+}
+
+// InviteRepository deletes the invitations sent to an address in ctx's
+// tenant.
+type InviteRepository interface {
+	DeleteInvitesByEmail(ctx context.Context, email string) (int64, error)
+}
+
+type AvatarDeleter interface {
+	DeleteAvatar(ctx context.Context, id string) error
+}
+
+type usecase struct {
+	key        []byte
+	users      UserRepository
+	logins     LoginRepository
+	sessions   SessionRepository
+	groups     GroupRepository
+	identities IdentityRepository
+	oidc       OIDCRepository
+	invites    InviteRepository
+	avatars    AvatarDeleter
+}
+
+func NewUsecase(key []byte, ur UserRepository, lr LoginRepository, sr SessionRepository, gr GroupRepository, ir IdentityRepository, oc OIDCRepository, ivr InviteRepository, ad AvatarDeleter) *usecase {
+	return &usecase{
+		key:        key,
+		users:      ur,
+		logins:     lr,
+		sessions:   sr,
+		groups:     gr,
+		identities: ir,
+		oidc:       oc,
+		invites:    ivr,
+		avatars:    ad,
+	}
+}
+
+// ExportMyData assembles everything held about the caller.
+func (u *usecase) ExportMyData(ctx context.Context) (*response.StdResp[any], error) {
+	claims, err := auth.FromContext(ctx)
+	if err != nil {
+		return response.Unauthorized(), nil
+	}
+	return u.export(ctx, claims.UserID)
+}
+
+// ExportUserData assembles everything held about a user, for admins or the
+// user themselves.
+func (u *usecase) ExportUserData(ctx context.Context, userID string) (*response.StdResp[any], error) {
+	claims, err := auth.FromContext(ctx)
+	if err != nil {
+		return response.Unauthorized(), nil
+	}
+	if !claims.IsAdmin() && claims.UserID != userID {
+		return response.Forbidden(), nil
+	}
+	return u.export(ctx, userID)
+}
+
+func (u *usecase) export(ctx context.Context, userID string) (*response.StdResp[any], error) {
+	found, err := u.users.FindUserById(ctx, userID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return response.UserNotFound(), nil
+		}
+		return nil, err
+	}
+	found.Avatar = avatar.URLs(found.AvatarID)
+	history, err := u.users.FindStatusHistory(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := u.groups.FindGroupNamesByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	logins, err := u.logins.FindAllAttemptsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := u.sessions.FindSessionsByUser(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	identities, err := u.identities.FindLinksByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return response.SuccessWithData(Export{
+		GeneratedAt:   time.Now().UTC(),
+		User:          found,
+		StatusHistory: orEmpty(history),
+		Groups:        orEmpty(groups),
+		Logins:        orEmpty(logins),
+		Sessions:      orEmpty(sessions),
+		Identities:    orEmpty(identities),
+	}), nil
+}
+
+// EraseUser deletes a user and everything held about them, and returns a
+// signed receipt of what was deleted. Only admins can erase users, and not
+// themselves. The user record goes last, so that a failed erasure can be
+// retried until it completes.
+func (u *usecase) EraseUser(ctx context.Context, userID string) (*response.StdResp[any], error) {
+	claims, err := auth.FromContext(ctx)
+	if err != nil || !claims.IsAdmin() || claims.UserID == userID {
+		return response.Forbidden(), nil
+	}
+	found, err := u.users.FindUserById(ctx, userID)
+	if err != nil {
+		if errors.Is(err, user.ErrUserNotFound) {
+			return response.UserNotFound(), nil
+		}
+		return nil, err
+	}
+
+	receipt := Receipt{
+		ID:       primitive.NewObjectID().Hex(),
+		UserID:   userID,
+		TenantID: found.TenantID,
+		ErasedBy: claims.UserID,
+	}
+	count, err := u.sessions.DeleteSessionsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	receipt.Erased = append(receipt.Erased, Erased{Data: DataSessions, Count: count})
+	count, err = u.logins.DeleteAttemptsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	receipt.Erased = append(receipt.Erased, Erased{Data: DataLoginHistory, Count: count})
+	count, err = u.groups.RemoveUserMemberships(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	receipt.Erased = append(receipt.Erased, Erased{Data: DataGroupMemberships, Count: count})
+	count, err = u.identities.DeleteLinksByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	receipt.Erased = append(receipt.Erased, Erased{Data: DataIdentityLinks, Count: count})
+	// Codes and tokens go too, so that apps signed in through us lose
+	// access and /userinfo stops answering for the user.
+	count, err = u.oidc.DeleteCodesByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	receipt.Erased = append(receipt.Erased, Erased{Data: DataOIDCCodes, Count: count})
+	count, err = u.oidc.DeleteTokensByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	receipt.Erased = append(receipt.Erased, Erased{Data: DataOIDCTokens, Count: count})
+	// Invitations are not linked to users, only to their address.
+	count, err = u.invites.DeleteInvitesByEmail(auth.WithTenant(ctx, found.TenantID), found.Email)
+	if err != nil {
+		return nil, err
+	}
+	receipt.Erased = append(receipt.Erased, Erased{Data: DataInvites, Count: count})
+	count = 0
+	if found.AvatarID != "" {
+		if err := u.avatars.DeleteAvatar(ctx, found.AvatarID); err != nil {
+			return nil, err
+		}
+		count = int64(len(avatar.Sizes))
+	}
+	receipt.Erased = append(receipt.Erased, Erased{Data: DataAvatar, Count: count})
+	// The status history, the audit trail about the user, is part of the
+	// user record and goes with it.
+	history, err := u.users.FindStatusHistory(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	count, err = u.users.DeleteUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		// Erased concurrently.
+		return response.UserNotFound(), nil
+	}
+	receipt.Erased = append(receipt.Erased,
+		Erased{Data: DataStatusHistory, Count: int64(len(history))},
+		Erased{Data: DataUser, Count: count},
+	)
+
+	receipt.ErasedAt = time.Now().UTC().Truncate(time.Second)
+	receipt.Signature, err = SignReceipt(u.key, receipt)
+	if err != nil {
+		return nil, err
+	}
+	if zlog, err := logger.FromContext(ctx); err == nil {
+		zlog.Sugar().Infof("[Usecase] Erased user %s, receipt %s", userID, receipt.ID)
+	}
+	return response.SuccessWithData(receipt), nil
+}
+
+// VerifyReceipt reports whether r was signed by this service.
+func (u *usecase) VerifyReceipt(r Receipt) bool {
+	return VerifyReceipt(u.key, r)
+}
+
+// orEmpty turns nil into an empty slice, so that exports show [] rather
+// than null.
+func orEmpty[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
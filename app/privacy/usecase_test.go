@@ -0,0 +1,249 @@
+package privacy_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-management/app/avatar"
+	"user-management/app/federation"
+	"user-management/app/loginhistory"
+	"user-management/app/privacy"
+	"user-management/app/session"
+	"user-management/app/user"
+	"user-management/auth"
+	"user-management/response"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRepo stands in for every store the usecase reads and erases.
+type mockRepo struct {
+	mock.Mock
+}
+
+func (m *mockRepo) FindUserById(ctx context.Context, id string) (user.FindUserResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(user.FindUserResponse), args.Error(1)
+}
+
+func (m *mockRepo) FindStatusHistory(ctx context.Context, id string) ([]user.StatusChange, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]user.StatusChange), args.Error(1)
+}
+
+func (m *mockRepo) DeleteUser(ctx context.Context, id string) (int64, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockRepo) FindAllAttemptsByUser(ctx context.Context, userID string) ([]loginhistory.Attempt, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]loginhistory.Attempt), args.Error(1)
+}
+
+func (m *mockRepo) DeleteAttemptsByUser(ctx context.Context, userID string) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockRepo) FindSessionsByUser(ctx context.Context, userID string, now time.Time) ([]session.Session, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]session.Session), args.Error(1)
+}
+
+func (m *mockRepo) DeleteSessionsByUser(ctx context.Context, userID string) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockRepo) FindGroupNamesByUser(ctx context.Context, userID string) ([]string, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockRepo) RemoveUserMemberships(ctx context.Context, userID string) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockRepo) FindLinksByUser(ctx context.Context, userID string) ([]federation.IdentityLink, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]federation.IdentityLink), args.Error(1)
+}
+
+func (m *mockRepo) DeleteLinksByUser(ctx context.Context, userID string) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockRepo) DeleteCodesByUser(ctx context.Context, userID string) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockRepo) DeleteTokensByUser(ctx context.Context, userID string) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockRepo) DeleteInvitesByEmail(ctx context.Context, email string) (int64, error) {
+	args := m.Called(ctx, email)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockRepo) DeleteAvatar(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+var testKey = []byte("receipt-key")
+
+func newTestUsecase(repo *mockRepo) privacy.Usecase {
+	return privacy.NewUsecase(testKey, repo, repo, repo, repo, repo, repo, repo, repo)
+}
+
+func claimsContext(userID, role string) context.Context {
+	return auth.WithClaims(context.Background(), &auth.Claims{UserID: userID, TenantID: "t1", Role: role})
+}
+
+const avatarID = "0123456789abcdef0123456789abcdef"
+
+func TestUsecaseExportMyData(t *testing.T) {
+	repo := new(mockRepo)
+	uc := newTestUsecase(repo)
+	repo.On("FindUserById", mock.Anything, "u1").Return(user.FindUserResponse{Id: "u1", Email: "alice@example.com", AvatarID: avatarID}, nil)
+	repo.On("FindStatusHistory", mock.Anything, "u1").Return([]user.StatusChange{{From: user.StatusActive, To: user.StatusSuspended}}, nil)
+	repo.On("FindGroupNamesByUser", mock.Anything, "u1").Return([]string{"ops"}, nil)
+	repo.On("FindAllAttemptsByUser", mock.Anything, "u1").Return([]loginhistory.Attempt{{UserID: "u1", Success: true}}, nil)
+	repo.On("FindSessionsByUser", mock.Anything, "u1").Return([]session.Session(nil), nil)
+	repo.On("FindLinksByUser", mock.Anything, "u1").Return([]federation.IdentityLink{{Provider: "corp", Subject: "s1", UserID: "u1"}}, nil)
+
+	resp, err := uc.ExportMyData(claimsContext("u1", auth.RoleUser))
+
+	require.NoError(t, err)
+	require.True(t, resp.IsSuccess())
+	export := resp.Data.(privacy.Export)
+	assert.Equal(t, "alice@example.com", export.User.Email)
+	assert.Equal(t, avatar.URLs(avatarID), export.User.Avatar)
+	assert.Len(t, export.StatusHistory, 1)
+	assert.Equal(t, []string{"ops"}, export.Groups)
+	assert.Len(t, export.Logins, 1)
+	assert.NotNil(t, export.Sessions)
+	assert.Empty(t, export.Sessions)
+	assert.Len(t, export.Identities, 1)
+	assert.False(t, export.GeneratedAt.IsZero())
+}
+
+func TestUsecaseExportUserData_Forbidden(t *testing.T) {
+	repo := new(mockRepo)
+	uc := newTestUsecase(repo)
+
+	resp, err := uc.ExportUserData(claimsContext("u1", auth.RoleUser), "u2")
+	assert.NoError(t, err)
+	assert.Equal(t, response.Forbidden(), resp)
+	resp, err = uc.ExportUserData(context.Background(), "u2")
+	assert.NoError(t, err)
+	assert.Equal(t, response.Unauthorized(), resp)
+	repo.AssertNotCalled(t, "FindUserById", mock.Anything, mock.Anything)
+}
+
+func TestUsecaseExportUserData_NotFound(t *testing.T) {
+	repo := new(mockRepo)
+	uc := newTestUsecase(repo)
+	repo.On("FindUserById", mock.Anything, "u2").Return(user.FindUserResponse{}, user.ErrUserNotFound)
+
+	resp, err := uc.ExportUserData(claimsContext("u1", auth.RoleAdmin), "u2")
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.UserNotFound(), resp)
+}
+
+func eraseMocks(repo *mockRepo, found user.FindUserResponse) {
+	repo.On("FindUserById", mock.Anything, found.Id).Return(found, nil)
+	repo.On("DeleteSessionsByUser", mock.Anything, found.Id).Return(int64(2), nil)
+	repo.On("DeleteAttemptsByUser", mock.Anything, found.Id).Return(int64(5), nil)
+	repo.On("RemoveUserMemberships", mock.Anything, found.Id).Return(int64(1), nil)
+	repo.On("DeleteLinksByUser", mock.Anything, found.Id).Return(int64(1), nil)
+	repo.On("DeleteCodesByUser", mock.Anything, found.Id).Return(int64(1), nil)
+	repo.On("DeleteTokensByUser", mock.Anything, found.Id).Return(int64(3), nil)
+	repo.On("DeleteInvitesByEmail", mock.MatchedBy(func(ctx context.Context) bool {
+		tenantID, scoped := auth.TenantFromContext(ctx)
+		return scoped && tenantID == found.TenantID
+	}), found.Email).Return(int64(1), nil)
+}
+
+func TestUsecaseEraseUser(t *testing.T) {
+	repo := new(mockRepo)
+	uc := newTestUsecase(repo)
+	eraseMocks(repo, user.FindUserResponse{Id: "u2", TenantID: "t1", Email: "bob@example.com", AvatarID: avatarID})
+	repo.On("DeleteAvatar", mock.Anything, avatarID).Return(nil)
+	repo.On("FindStatusHistory", mock.Anything, "u2").Return([]user.StatusChange{{From: user.StatusActive, To: user.StatusSuspended}}, nil)
+	repo.On("DeleteUser", mock.Anything, "u2").Return(int64(1), nil)
+
+	resp, err := uc.EraseUser(claimsContext("u1", auth.RoleAdmin), "u2")
+
+	require.NoError(t, err)
+	require.True(t, resp.IsSuccess())
+	receipt := resp.Data.(privacy.Receipt)
+	assert.NotEmpty(t, receipt.ID)
+	assert.Equal(t, "u2", receipt.UserID)
+	assert.Equal(t, "t1", receipt.TenantID)
+	assert.Equal(t, "u1", receipt.ErasedBy)
+	assert.Equal(t, []privacy.Erased{
+		{Data: privacy.DataSessions, Count: 2},
+		{Data: privacy.DataLoginHistory, Count: 5},
+		{Data: privacy.DataGroupMemberships, Count: 1},
+		{Data: privacy.DataIdentityLinks, Count: 1},
+		{Data: privacy.DataOIDCCodes, Count: 1},
+		{Data: privacy.DataOIDCTokens, Count: 3},
+		{Data: privacy.DataInvites, Count: 1},
+		{Data: privacy.DataAvatar, Count: int64(len(avatar.Sizes))},
+		{Data: privacy.DataStatusHistory, Count: 1},
+		{Data: privacy.DataUser, Count: 1},
+	}, receipt.Erased)
+	assert.True(t, privacy.VerifyReceipt(testKey, receipt))
+	repo.AssertExpectations(t)
+}
+
+func TestUsecaseEraseUser_Forbidden(t *testing.T) {
+	repo := new(mockRepo)
+	uc := newTestUsecase(repo)
+
+	resp, err := uc.EraseUser(claimsContext("u1", auth.RoleUser), "u2")
+	assert.NoError(t, err)
+	assert.Equal(t, response.Forbidden(), resp)
+	// Admins cannot erase themselves.
+	resp, err = uc.EraseUser(claimsContext("u1", auth.RoleAdmin), "u1")
+	assert.NoError(t, err)
+	assert.Equal(t, response.Forbidden(), resp)
+	repo.AssertNotCalled(t, "FindUserById", mock.Anything, mock.Anything)
+}
+
+func TestUsecaseEraseUser_NotFound(t *testing.T) {
+	repo := new(mockRepo)
+	uc := newTestUsecase(repo)
+	repo.On("FindUserById", mock.Anything, "u2").Return(user.FindUserResponse{}, user.ErrUserNotFound)
+
+	resp, err := uc.EraseUser(claimsContext("u1", auth.RoleAdmin), "u2")
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.UserNotFound(), resp)
+	repo.AssertNotCalled(t, "DeleteUser", mock.Anything, mock.Anything)
+}
+
+func TestUsecaseEraseUser_KeepsUserOnFailure(t *testing.T) {
+	repo := new(mockRepo)
+	uc := newTestUsecase(repo)
+	eraseMocks(repo, user.FindUserResponse{Id: "u2", AvatarID: avatarID})
+	repo.On("DeleteAvatar", mock.Anything, avatarID).Return(errors.New("disk full"))
+
+	resp, err := uc.EraseUser(claimsContext("u1", auth.RoleAdmin), "u2")
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	// The user stays, so that the erasure can be retried.
+	repo.AssertNotCalled(t, "DeleteUser", mock.Anything, mock.Anything)
+}
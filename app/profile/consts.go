@@ -0,0 +1,36 @@
+package profile
+
+import "regexp"
+
+const (
+	QueryTenantID = "tenant_id"
+)
+
+// Attribute types.
+const (
+	TypeString  = "string"
+	TypeNumber  = "number"
+	TypeInteger = "integer"
+	TypeBoolean = "boolean"
+)
+
+// Rules reported in response.ProfileViolation.
+const (
+	RuleUnknown  = "unknown"
+	RuleRequired = "required"
+	RuleType     = "type"
+	RuleLength   = "length"
+	RulePattern  = "pattern"
+	RuleEnum     = "enum"
+)
+
+const (
+	// MaxAttributes caps how many attributes a schema may define.
+	MaxAttributes = 50
+	// MaxStringLength caps string values, in characters.
+	MaxStringLength = 1024
+)
+
+// namePattern keeps attribute names usable as query parameters, Mongo field
+// names and JSON paths without escaping.
+var namePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
@@ -0,0 +1,78 @@
+package profile
+
+import (
+	"context"
+	"user-management/logger"
+	"user-management/response"
+
+	echo "github.com/labstack/echo/v4"
+)
+
+type Usecase interface {
+	FindSchema(ctx context.Context, tenantID string) (*response.StdResp[any], error)
+	SaveSchema(ctx context.Context, tenantID string, req SchemaRequest) (*response.StdResp[any], error)
+}
+
+type Handler interface {
+	FindSchema(c echo.Context) error
+	SaveSchema(c echo.Context) error
+}
+
+type handler struct {
+	usecase Usecase
+}
+
+func NewHandler(u Usecase) *handler {
+	return &handler{
+		usecase: u,
+	}
+}
+
+// FindSchema serves GET /profile/schema.
+func (h *handler) FindSchema(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	tenantID := c.QueryParam(QueryTenantID)
+	if respValidate := TenantIdValidation(tenantID); !respValidate.IsSuccess() {
+		return c.JSON(respValidate.WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.FindSchema(ctx, tenantID)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+// SaveSchema serves PUT /profile/schema.
+func (h *handler) SaveSchema(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	tenantID := c.QueryParam(QueryTenantID)
+	if respValidate := TenantIdValidation(tenantID); !respValidate.IsSuccess() {
+		return c.JSON(respValidate.WithHTTPStatus())
+	}
+	var request SchemaRequest
+	err = c.Bind(&request)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Bind request error: %v", err)
+		return c.JSON(response.UnexpectedRequest().WithHTTPStatus())
+	}
+	if resp := request.RequestValidation(); !resp.IsSuccess() {
+		return c.JSON(resp.WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.SaveSchema(ctx, tenantID, request)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
@@ -0,0 +1,98 @@
+package profile_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-management/app/profile"
+	"user-management/logger"
+	"user-management/response"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockUsecase struct {
+	mock.Mock
+}
+
+func (m *mockUsecase) FindSchema(ctx context.Context, tenantID string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, tenantID)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) SaveSchema(ctx context.Context, tenantID string, req profile.SchemaRequest) (*response.StdResp[any], error) {
+	args := m.Called(ctx, tenantID, req)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func newTestContext(method, target string, body any) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	var reader *bytes.Reader
+	if body != nil {
+		b, _ := json.Marshal(body)
+		reader = bytes.NewReader(b)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	ctx := context.WithValue(c.Request().Context(), logger.LogContext, logger.NewZap())
+	c.SetRequest(req.WithContext(ctx))
+	return c, rec
+}
+
+func TestHandlerFindSchema(t *testing.T) {
+	c, rec := newTestContext(http.MethodGet, "/profile/schema", nil)
+
+	mockUc := new(mockUsecase)
+	mockUc.On("FindSchema", mock.Anything, "").Return(response.SuccessWithData(profile.Schema{Attributes: departments}), nil)
+
+	err := profile.NewHandler(mockUc).FindSchema(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"name":"department"`)
+}
+
+func TestHandlerFindSchema_InvalidTenant(t *testing.T) {
+	c, rec := newTestContext(http.MethodGet, "/profile/schema?tenant_id=acme", nil)
+
+	mockUc := new(mockUsecase)
+
+	err := profile.NewHandler(mockUc).FindSchema(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUc.AssertNotCalled(t, "FindSchema", mock.Anything, mock.Anything)
+}
+
+func TestHandlerSaveSchema(t *testing.T) {
+	tenantID := "60d5ec49f1f1c939b4f2f0c1"
+	reqBody := profile.SchemaRequest{Attributes: departments}
+	c, rec := newTestContext(http.MethodPut, "/profile/schema?tenant_id="+tenantID, reqBody)
+
+	mockUc := new(mockUsecase)
+	mockUc.On("SaveSchema", mock.Anything, tenantID, reqBody).Return(response.Success(), nil)
+
+	err := profile.NewHandler(mockUc).SaveSchema(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestHandlerSaveSchema_Invalid(t *testing.T) {
+	reqBody := profile.SchemaRequest{Attributes: []profile.Attribute{{Name: "department", Type: "date"}}}
+	c, rec := newTestContext(http.MethodPut, "/profile/schema", reqBody)
+
+	mockUc := new(mockUsecase)
+
+	err := profile.NewHandler(mockUc).SaveSchema(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), response.InvalidData("attributes[0].type").Message)
+	mockUc.AssertNotCalled(t, "SaveSchema", mock.Anything, mock.Anything, mock.Anything)
+}
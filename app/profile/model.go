@@ -0,0 +1,28 @@
+package profile
+
+import "time"
+
+// Schema lists the custom attributes the profiles of a tenant's users may
+// have. Platform users follow the schema stored with an empty TenantID.
+// UpdatedAt is nil until an admin saves the schema.
+type Schema struct {
+	TenantID   string      `bson:"tenant_id" json:"tenant_id,omitempty"`
+	Attributes []Attribute `bson:"attributes" json:"attributes"`
+	UpdatedAt  *time.Time  `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+}
+
+// Attribute describes one profile attribute. Pattern and Enum only apply to
+// strings. Indexed attributes can be used to filter the user list.
+type Attribute struct {
+	Name        string   `bson:"name" json:"name"`
+	Type        string   `bson:"type" json:"type"`
+	Description string   `bson:"description,omitempty" json:"description,omitempty"`
+	Required    bool     `bson:"required" json:"required"`
+	Pattern     string   `bson:"pattern,omitempty" json:"pattern,omitempty"`
+	Enum        []string `bson:"enum,omitempty" json:"enum,omitempty"`
+	Indexed     bool     `bson:"indexed" json:"indexed"`
+}
+
+type SchemaRequest struct {
+	Attributes []Attribute `json:"attributes"`
+}
@@ -0,0 +1,40 @@
+package profile
+
+import (
+	"context"
+	"user-management/config"
+	"user-management/storage"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type repository struct {
+	mc  storage.DatabaseConn
+	cfg config.MongoConfig
+}
+
+func NewRepository(mc storage.DatabaseConn, cfg config.MongoConfig) *repository {
+	return &repository{
+		mc:  mc,
+		cfg: cfg,
+	}
+}
+
+// FindSchema returns the schema of tenantID, or one without attributes if
+// none was saved.
+func (r *repository) FindSchema(ctx context.Context, tenantID string) (Schema, error) {
+	schema := Schema{TenantID: tenantID}
+	err := r.mc.Collection(r.cfg.ProfileSchemaCollection).FindOne(ctx, bson.M{"tenant_id": tenantID}).Decode(&schema)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return schema, err
+	}
+	return schema, nil
+}
+
+func (r *repository) SaveSchema(ctx context.Context, schema Schema) error {
+	update := bson.M{"$set": bson.M{"attributes": schema.Attributes, "updated_at": schema.UpdatedAt}}
+	_, err := r.mc.Collection(r.cfg.ProfileSchemaCollection).UpdateOne(ctx, bson.M{"tenant_id": schema.TenantID}, update, options.Update().SetUpsert(true))
+	return err
+}
@@ -0,0 +1,37 @@
+package profile
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// memoryRepository keeps schemas in process memory, for when the service
+// runs without a database.
+type memoryRepository struct {
+	mu      sync.RWMutex
+	schemas map[string]Schema
+}
+
+func NewMemoryRepository() *memoryRepository {
+	return &memoryRepository{schemas: map[string]Schema{}}
+}
+
+func (r *memoryRepository) FindSchema(ctx context.Context, tenantID string) (Schema, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	schema, ok := r.schemas[tenantID]
+	if !ok {
+		return Schema{TenantID: tenantID}, nil
+	}
+	schema.Attributes = slices.Clone(schema.Attributes)
+	return schema, nil
+}
+
+func (r *memoryRepository) SaveSchema(ctx context.Context, schema Schema) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	schema.Attributes = slices.Clone(schema.Attributes)
+	r.schemas[schema.TenantID] = schema
+	return nil
+}
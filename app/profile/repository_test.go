@@ -0,0 +1,63 @@
+package profile_test
+
+import (
+	"context"
+	"testing"
+	"user-management/app/profile"
+	"user-management/config"
+	"user-management/storage"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func newTestRepository(mt *mtest.T) profile.Repository {
+	dbConn := storage.NewMongoConn(mt.Client, mt.Client.Database("testdb"))
+	return profile.NewRepository(dbConn, config.MongoConfig{
+		Database:                "testdb",
+		ProfileSchemaCollection: "profile_schemas",
+	})
+}
+
+func TestRepository_FindSchema(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("success", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(1, "testdb.profile_schemas", mtest.FirstBatch, bson.D{
+			bson.E{Key: "tenant_id", Value: "t1"},
+			bson.E{Key: "attributes", Value: bson.A{
+				bson.D{{Key: "name", Value: "department"}, {Key: "type", Value: "string"}, {Key: "indexed", Value: true}},
+			}},
+		}))
+
+		schema, err := repo.FindSchema(context.Background(), "t1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, profile.Schema{TenantID: "t1", Attributes: departments}, schema)
+	})
+
+	mt.Run("not found", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.profile_schemas", mtest.FirstBatch))
+
+		schema, err := repo.FindSchema(context.Background(), "t1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, profile.Schema{TenantID: "t1"}, schema)
+	})
+}
+
+func TestRepository_SaveSchema(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("success", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 0}))
+
+		err := repo.SaveSchema(context.Background(), profile.Schema{TenantID: "t1", Attributes: departments})
+
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,142 @@
+package profile
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+	"user-management/response"
+)
+
+// maxSafeInteger is the largest integer a JSON number holds exactly.
+const maxSafeInteger = 1 << 53
+
+// Attribute returns the attribute called name.
+func (s Schema) Attribute(name string) (Attribute, bool) {
+	i := slices.IndexFunc(s.Attributes, func(a Attribute) bool { return a.Name == name })
+	if i < 0 {
+		return Attribute{}, false
+	}
+	return s.Attributes[i], true
+}
+
+// Validate checks attrs against the schema. It returns the attributes to
+// store, with values converted to the Go type of their attribute (string,
+// float64, int64 or bool), or the violations found. Nil values count as
+// unset.
+func (s Schema) Validate(attrs map[string]any) (map[string]any, []response.ProfileViolation) {
+	var violations []response.ProfileViolation
+	for _, name := range slices.Sorted(maps.Keys(attrs)) {
+		if _, ok := s.Attribute(name); !ok && attrs[name] != nil {
+			violations = append(violations, violation(name, RuleUnknown, "is not defined by the profile schema"))
+		}
+	}
+	valid := map[string]any{}
+	for _, a := range s.Attributes {
+		v := attrs[a.Name]
+		if v == nil {
+			if a.Required {
+				violations = append(violations, violation(a.Name, RuleRequired, "is required"))
+			}
+			continue
+		}
+		value, violated := a.check(v)
+		if violated != nil {
+			violations = append(violations, *violated)
+			continue
+		}
+		valid[a.Name] = value
+	}
+	if len(violations) > 0 {
+		return nil, violations
+	}
+	return valid, nil
+}
+
+// Parse converts a query parameter to the attribute's type, for filtering.
+func (a Attribute) Parse(raw string) (any, bool) {
+	switch a.Type {
+	case TypeString:
+		return raw, true
+	case TypeBoolean:
+		b, err := strconv.ParseBool(raw)
+		return b, err == nil
+	case TypeNumber:
+		f, err := strconv.ParseFloat(raw, 64)
+		return f, err == nil && !math.IsNaN(f) && !math.IsInf(f, 0)
+	case TypeInteger:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		return n, err == nil
+	}
+	return nil, false
+}
+
+func (a Attribute) check(v any) (any, *response.ProfileViolation) {
+	fail := func(rule, msg string) (any, *response.ProfileViolation) {
+		v := violation(a.Name, rule, msg)
+		return nil, &v
+	}
+	value, ok := convert(a.Type, v)
+	if !ok {
+		return fail(RuleType, "must be of type "+a.Type)
+	}
+	s, isString := value.(string)
+	if !isString {
+		return value, nil
+	}
+	if utf8.RuneCountInString(s) > MaxStringLength {
+		return fail(RuleLength, fmt.Sprintf("must be at most %d characters", MaxStringLength))
+	}
+	if a.Pattern != "" {
+		// Patterns are checked when the schema is saved.
+		if re, err := regexp.Compile(a.Pattern); err != nil || !re.MatchString(s) {
+			return fail(RulePattern, "does not match "+a.Pattern)
+		}
+	}
+	if len(a.Enum) > 0 && !slices.Contains(a.Enum, s) {
+		return fail(RuleEnum, "must be one of "+strings.Join(a.Enum, ", "))
+	}
+	return s, nil
+}
+
+// convert returns v as the Go type values of typ are stored with. Numbers
+// arrive as float64 from JSON and as int32 or int64 from BSON.
+func convert(typ string, v any) (any, bool) {
+	switch typ {
+	case TypeString:
+		s, ok := v.(string)
+		return s, ok
+	case TypeBoolean:
+		b, ok := v.(bool)
+		return b, ok
+	case TypeNumber:
+		switch n := v.(type) {
+		case float64:
+			return n, true
+		case int64:
+			return float64(n), true
+		case int32:
+			return float64(n), true
+		}
+	case TypeInteger:
+		switch n := v.(type) {
+		case float64:
+			if n == math.Trunc(n) && math.Abs(n) <= maxSafeInteger {
+				return int64(n), true
+			}
+		case int64:
+			return n, true
+		case int32:
+			return int64(n), true
+		}
+	}
+	return nil, false
+}
+
+func violation(name, rule, msg string) response.ProfileViolation {
+	return response.ProfileViolation{Attribute: name, Rule: rule, Message: name + " " + msg}
+}
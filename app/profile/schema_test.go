@@ -0,0 +1,102 @@
+package profile_test
+
+import (
+	"strings"
+	"testing"
+	"user-management/app/profile"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testSchema = profile.Schema{Attributes: []profile.Attribute{
+	{Name: "department", Type: profile.TypeString, Required: true, Enum: []string{"Sales", "Ops"}, Indexed: true},
+	{Name: "phone", Type: profile.TypeString, Pattern: `^\+[0-9]{6,15}$`},
+	{Name: "level", Type: profile.TypeInteger, Indexed: true},
+	{Name: "score", Type: profile.TypeNumber},
+	{Name: "remote", Type: profile.TypeBoolean, Indexed: true},
+}}
+
+func TestSchemaValidate(t *testing.T) {
+	attrs, violations := testSchema.Validate(map[string]any{
+		"department": "Sales",
+		"phone":      "+4412345678",
+		"level":      float64(3),
+		"score":      int32(7),
+		"remote":     true,
+	})
+
+	assert.Empty(t, violations)
+	assert.Equal(t, map[string]any{
+		"department": "Sales",
+		"phone":      "+4412345678",
+		"level":      int64(3),
+		"score":      float64(7),
+		"remote":     true,
+	}, attrs)
+}
+
+func TestSchemaValidate_NilIsUnset(t *testing.T) {
+	attrs, violations := testSchema.Validate(map[string]any{"department": "Ops", "phone": nil, "gone": nil})
+
+	assert.Empty(t, violations)
+	assert.Equal(t, map[string]any{"department": "Ops"}, attrs)
+}
+
+func TestSchemaValidate_Violations(t *testing.T) {
+	tests := []struct {
+		name  string
+		attrs map[string]any
+		rules map[string]string
+	}{
+		{"required", map[string]any{}, map[string]string{"department": profile.RuleRequired}},
+		{"unknown", map[string]any{"department": "Ops", "shoe_size": float64(42)}, map[string]string{"shoe_size": profile.RuleUnknown}},
+		{"enum", map[string]any{"department": "HR"}, map[string]string{"department": profile.RuleEnum}},
+		{"pattern", map[string]any{"department": "Ops", "phone": "12345"}, map[string]string{"phone": profile.RulePattern}},
+		{"length", map[string]any{"department": "Ops", "phone": strings.Repeat("1", profile.MaxStringLength+1)}, map[string]string{"phone": profile.RuleLength}},
+		{"fractional integer", map[string]any{"department": "Ops", "level": 2.5}, map[string]string{"level": profile.RuleType}},
+		{"string for number", map[string]any{"department": "Ops", "score": "7"}, map[string]string{"score": profile.RuleType}},
+		{"number for boolean", map[string]any{"department": "Ops", "remote": float64(1)}, map[string]string{"remote": profile.RuleType}},
+		{"several", map[string]any{"level": "3"}, map[string]string{"department": profile.RuleRequired, "level": profile.RuleType}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attrs, violations := testSchema.Validate(tt.attrs)
+
+			assert.Nil(t, attrs)
+			rules := map[string]string{}
+			for _, v := range violations {
+				rules[v.Attribute] = v.Rule
+				assert.True(t, strings.HasPrefix(v.Message, v.Attribute+" "), v.Message)
+			}
+			assert.Equal(t, tt.rules, rules)
+		})
+	}
+}
+
+func TestAttributeParse(t *testing.T) {
+	tests := []struct {
+		typ   string
+		raw   string
+		want  any
+		valid bool
+	}{
+		{profile.TypeString, "Sales", "Sales", true},
+		{profile.TypeInteger, "42", int64(42), true},
+		{profile.TypeInteger, "4.2", nil, false},
+		{profile.TypeNumber, "4.2", 4.2, true},
+		{profile.TypeNumber, "NaN", nil, false},
+		{profile.TypeBoolean, "true", true, true},
+		{profile.TypeBoolean, "yes", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typ+" "+tt.raw, func(t *testing.T) {
+			got, ok := profile.Attribute{Name: "a", Type: tt.typ}.Parse(tt.raw)
+			assert.Equal(t, tt.valid, ok)
+			if tt.valid {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
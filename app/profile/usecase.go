@@ -0,0 +1,66 @@
+package profile
+
+import (
+	"context"
+	"time"
+	"user-management/auth"
+	"user-management/response"
+)
+
+type Repository interface {
+	FindSchema(ctx context.Context, tenantID string) (Schema, error)
+	SaveSchema(ctx context.Context, schema Schema) error
+}
+
+type usecase struct {
+	repo Repository
+}
+
+func NewUsecase(r Repository) *usecase {
+	return &usecase{
+		repo: r,
+	}
+}
+
+// FindSchema returns the profile schema of the caller's tenant, so that any
+// signed-in user can tell which attributes a profile takes.
+func (u *usecase) FindSchema(ctx context.Context, tenantID string) (*response.StdResp[any], error) {
+	if _, err := auth.FromContext(ctx); err != nil {
+		return response.Forbidden(), nil
+	}
+	schema, err := u.repo.FindSchema(ctx, schemaTenant(ctx, tenantID))
+	if err != nil {
+		return nil, err
+	}
+	if schema.Attributes == nil {
+		schema.Attributes = []Attribute{}
+	}
+	return response.SuccessWithData(schema), nil
+}
+
+// SaveSchema replaces the profile schema of the caller's tenant. Profiles
+// already stored are checked against it the next time they are changed.
+func (u *usecase) SaveSchema(ctx context.Context, tenantID string, req SchemaRequest) (*response.StdResp[any], error) {
+	if claims, err := auth.FromContext(ctx); err != nil || !claims.IsAdmin() {
+		return response.Forbidden(), nil
+	}
+	now := time.Now()
+	err := u.repo.SaveSchema(ctx, Schema{
+		TenantID:   schemaTenant(ctx, tenantID),
+		Attributes: req.Attributes,
+		UpdatedAt:  &now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.Success(), nil
+}
+
+// schemaTenant returns the tenant whose schema the caller works on: their
+// own, or for platform callers the one they ask for.
+func schemaTenant(ctx context.Context, requested string) string {
+	if tenantID, scoped := auth.TenantFromContext(ctx); scoped {
+		return tenantID
+	}
+	return requested
+}
@@ -0,0 +1,107 @@
+package profile_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"user-management/app/profile"
+	"user-management/auth"
+	"user-management/response"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRepo struct {
+	mock.Mock
+}
+
+func (m *mockRepo) FindSchema(ctx context.Context, tenantID string) (profile.Schema, error) {
+	args := m.Called(ctx, tenantID)
+	return args.Get(0).(profile.Schema), args.Error(1)
+}
+
+func (m *mockRepo) SaveSchema(ctx context.Context, schema profile.Schema) error {
+	args := m.Called(ctx, schema)
+	return args.Error(0)
+}
+
+func claimsContext(tenantID, role string) context.Context {
+	return auth.WithClaims(context.Background(), &auth.Claims{UserID: "u1", TenantID: tenantID, Role: role})
+}
+
+var departments = []profile.Attribute{{Name: "department", Type: profile.TypeString, Indexed: true}}
+
+func TestUsecaseSaveSchema(t *testing.T) {
+	uc := profile.NewUsecase(profile.NewMemoryRepository())
+
+	resp, err := uc.SaveSchema(claimsContext("t1", auth.RoleAdmin), "", profile.SchemaRequest{Attributes: departments})
+	require.NoError(t, err)
+	assert.Equal(t, response.Success(), resp)
+
+	resp, err = uc.FindSchema(claimsContext("t1", auth.RoleUser), "")
+	require.NoError(t, err)
+	schema := resp.Data.(profile.Schema)
+	assert.Equal(t, "t1", schema.TenantID)
+	assert.Equal(t, departments, schema.Attributes)
+	assert.NotNil(t, schema.UpdatedAt)
+
+	// Other tenants and platform users have their own schema.
+	resp, err = uc.FindSchema(claimsContext("t2", auth.RoleUser), "")
+	require.NoError(t, err)
+	assert.Equal(t, profile.Schema{TenantID: "t2", Attributes: []profile.Attribute{}}, resp.Data)
+	resp, err = uc.FindSchema(claimsContext("", auth.RoleUser), "")
+	require.NoError(t, err)
+	assert.Empty(t, resp.Data.(profile.Schema).Attributes)
+}
+
+func TestUsecaseSaveSchema_TenantParam(t *testing.T) {
+	repo := new(mockRepo)
+	uc := profile.NewUsecase(repo)
+	// Tenant users always work on their own tenant; platform users pick one.
+	repo.On("SaveSchema", mock.Anything, mock.MatchedBy(func(s profile.Schema) bool { return s.TenantID == "t1" })).Return(nil).Once()
+	repo.On("SaveSchema", mock.Anything, mock.MatchedBy(func(s profile.Schema) bool { return s.TenantID == "t2" })).Return(nil).Once()
+
+	resp, err := uc.SaveSchema(claimsContext("t1", auth.RoleAdmin), "t2", profile.SchemaRequest{Attributes: departments})
+	require.NoError(t, err)
+	assert.Equal(t, response.Success(), resp)
+	resp, err = uc.SaveSchema(claimsContext("", auth.RoleAdmin), "t2", profile.SchemaRequest{Attributes: departments})
+	require.NoError(t, err)
+	assert.Equal(t, response.Success(), resp)
+
+	repo.AssertExpectations(t)
+}
+
+func TestUsecaseSaveSchema_Forbidden(t *testing.T) {
+	repo := new(mockRepo)
+	uc := profile.NewUsecase(repo)
+
+	resp, err := uc.SaveSchema(claimsContext("t1", auth.RoleUser), "", profile.SchemaRequest{Attributes: departments})
+	assert.NoError(t, err)
+	assert.Equal(t, response.Forbidden(), resp)
+	resp, err = uc.SaveSchema(context.Background(), "", profile.SchemaRequest{Attributes: departments})
+	assert.NoError(t, err)
+	assert.Equal(t, response.Forbidden(), resp)
+	repo.AssertNotCalled(t, "SaveSchema", mock.Anything, mock.Anything)
+}
+
+func TestUsecaseFindSchema_Error(t *testing.T) {
+	repo := new(mockRepo)
+	uc := profile.NewUsecase(repo)
+	repo.On("FindSchema", mock.Anything, "t1").Return(profile.Schema{}, errors.New("db down"))
+
+	resp, err := uc.FindSchema(claimsContext("t1", auth.RoleUser), "")
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+}
+
+func TestUsecaseFindSchema_Unauthenticated(t *testing.T) {
+	uc := profile.NewUsecase(new(mockRepo))
+
+	resp, err := uc.FindSchema(context.Background(), "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.Forbidden(), resp)
+}
@@ -0,0 +1,62 @@
+package profile
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"user-management/response"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func (r SchemaRequest) RequestValidation() *response.StdResp[any] {
+	if r.Attributes == nil {
+		return response.MandatoryMissing("attributes")
+	}
+	if len(r.Attributes) > MaxAttributes {
+		return response.InvalidData("attributes")
+	}
+	seen := map[string]bool{}
+	for i, a := range r.Attributes {
+		field := func(name string) string {
+			return fmt.Sprintf("attributes[%d].%s", i, name)
+		}
+		if !namePattern.MatchString(a.Name) || seen[a.Name] {
+			return response.InvalidData(field("name"))
+		}
+		seen[a.Name] = true
+		if !isValidType(a.Type) {
+			return response.InvalidData(field("type"))
+		}
+		if a.Pattern != "" {
+			if _, err := regexp.Compile(a.Pattern); err != nil || a.Type != TypeString {
+				return response.InvalidData(field("pattern"))
+			}
+		}
+		if a.Enum != nil {
+			if a.Type != TypeString || len(a.Enum) == 0 || slices.ContainsFunc(a.Enum, func(v string) bool { return strings.TrimSpace(v) == "" }) {
+				return response.InvalidData(field("enum"))
+			}
+		}
+	}
+	return response.Success()
+}
+
+func TenantIdValidation(id string) *response.StdResp[any] {
+	if id == "" {
+		return response.Success()
+	}
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return response.InvalidData(QueryTenantID)
+	}
+	return response.Success()
+}
+
+func isValidType(typ string) bool {
+	switch typ {
+	case TypeString, TypeNumber, TypeInteger, TypeBoolean:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package profile_test
+
+import (
+	"fmt"
+	"testing"
+	"user-management/app/profile"
+	"user-management/response"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaRequest_RequestValidation(t *testing.T) {
+	str := func(a profile.Attribute) profile.SchemaRequest {
+		if a.Type == "" {
+			a.Type = profile.TypeString
+		}
+		return profile.SchemaRequest{Attributes: []profile.Attribute{a}}
+	}
+	tooMany := profile.SchemaRequest{}
+	for i := 0; i <= profile.MaxAttributes; i++ {
+		tooMany.Attributes = append(tooMany.Attributes, profile.Attribute{Name: fmt.Sprintf("a%d", i), Type: profile.TypeString})
+	}
+
+	tests := []struct {
+		name  string
+		input profile.SchemaRequest
+		want  *response.StdResp[any]
+	}{
+		{"Valid", profile.SchemaRequest{Attributes: []profile.Attribute{
+			{Name: "department", Type: profile.TypeString, Required: true, Enum: []string{"Sales", "Ops"}, Indexed: true},
+			{Name: "employee_id", Type: profile.TypeString, Pattern: `^E[0-9]+$`},
+			{Name: "level", Type: profile.TypeInteger},
+		}}, response.Success()},
+		{"Empty schema", profile.SchemaRequest{Attributes: []profile.Attribute{}}, response.Success()},
+		{"Missing attributes", profile.SchemaRequest{}, response.MandatoryMissing("attributes")},
+		{"Too many", tooMany, response.InvalidData("attributes")},
+		{"Bad name", str(profile.Attribute{Name: "Department"}), response.InvalidData("attributes[0].name")},
+		{"Dotted name", str(profile.Attribute{Name: "a.b"}), response.InvalidData("attributes[0].name")},
+		{"Duplicate name", profile.SchemaRequest{Attributes: []profile.Attribute{
+			{Name: "a", Type: profile.TypeString},
+			{Name: "a", Type: profile.TypeNumber},
+		}}, response.InvalidData("attributes[1].name")},
+		{"Bad type", str(profile.Attribute{Name: "a", Type: "date"}), response.InvalidData("attributes[0].type")},
+		{"Bad pattern", str(profile.Attribute{Name: "a", Pattern: "("}), response.InvalidData("attributes[0].pattern")},
+		{"Pattern on number", str(profile.Attribute{Name: "a", Type: profile.TypeNumber, Pattern: "1"}), response.InvalidData("attributes[0].pattern")},
+		{"Empty enum", str(profile.Attribute{Name: "a", Enum: []string{}}), response.InvalidData("attributes[0].enum")},
+		{"Blank enum value", str(profile.Attribute{Name: "a", Enum: []string{"x", " "}}), response.InvalidData("attributes[0].enum")},
+		{"Enum on boolean", str(profile.Attribute{Name: "a", Type: profile.TypeBoolean, Enum: []string{"true"}}), response.InvalidData("attributes[0].enum")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.input.RequestValidation())
+		})
+	}
+}
+
+func TestTenantIdValidation(t *testing.T) {
+	assert.Equal(t, response.Success(), profile.TenantIdValidation(""))
+	assert.Equal(t, response.Success(), profile.TenantIdValidation("60d5ec49f1f1c939b4f2f0c1"))
+	assert.Equal(t, response.InvalidData(profile.QueryTenantID), profile.TenantIdValidation("acme"))
+}
@@ -0,0 +1,71 @@
+package scim
+
+import "errors"
+
+const (
+	ParamID = "id"
+
+	QueryFilter             = "filter"
+	QueryStartIndex         = "startIndex"
+	QueryCount              = "count"
+	QueryExcludedAttributes = "excludedAttributes"
+)
+
+// ContentType is the media type of SCIM requests and responses (RFC 7644
+// section 8.1).
+const ContentType = "application/scim+json"
+
+// Schema URIs (RFC 7643 and RFC 7644).
+const (
+	SchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SchemaResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+	SchemaSchema                = "urn:ietf:params:scim:schemas:core:2.0:Schema"
+	SchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+const (
+	ResourceUser  = "User"
+	ResourceGroup = "Group"
+)
+
+// SCIM error types (RFC 7644 section 3.12).
+const (
+	ErrorInvalidFilter = "invalidFilter"
+	ErrorInvalidSyntax = "invalidSyntax"
+	ErrorInvalidPath   = "invalidPath"
+	ErrorInvalidValue  = "invalidValue"
+	ErrorUniqueness    = "uniqueness"
+	ErrorMutability    = "mutability"
+	ErrorNoTarget      = "noTarget"
+)
+
+const (
+	OpAdd     = "add"
+	OpReplace = "replace"
+	OpRemove  = "remove"
+)
+
+// MaxResults caps the resources returned by one list request; it is also the
+// page size when the client does not ask for one.
+const MaxResults = 100
+
+// maxRequestSize bounds the body of a SCIM request.
+const maxRequestSize = 1 << 20
+
+// tokenPrefix starts every SCIM token, so that leaked ones are easy to
+// recognize.
+const tokenPrefix = "scim_"
+
+// Reasons recorded in the status history of users (de)activated over SCIM.
+const (
+	reasonActivated   = "Activated through SCIM"
+	reasonDeactivated = "Deactivated through SCIM"
+)
+
+var (
+	ErrTokenNotFound = errors.New("SCIM token not found")
+)
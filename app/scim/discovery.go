@@ -0,0 +1,192 @@
+package scim
+
+// ServiceProviderConfig tells clients which SCIM features are supported (RFC
+// 7643 section 5).
+type ServiceProviderConfig struct {
+	Schemas               []string               `json:"schemas"`
+	DocumentationURI      string                 `json:"documentationUri,omitempty"`
+	Patch                 Supported              `json:"patch"`
+	Bulk                  BulkSupport            `json:"bulk"`
+	Filter                FilterSupport          `json:"filter"`
+	ChangePassword        Supported              `json:"changePassword"`
+	Sort                  Supported              `json:"sort"`
+	ETag                  Supported              `json:"etag"`
+	AuthenticationSchemes []AuthenticationScheme `json:"authenticationSchemes"`
+	Meta                  Meta                   `json:"meta"`
+}
+
+type Supported struct {
+	Supported bool `json:"supported"`
+}
+
+type BulkSupport struct {
+	Supported      bool `json:"supported"`
+	MaxOperations  int  `json:"maxOperations"`
+	MaxPayloadSize int  `json:"maxPayloadSize"`
+}
+
+type FilterSupport struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults"`
+}
+
+type AuthenticationScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Primary     bool   `json:"primary"`
+}
+
+// ResourceType describes an endpoint and the schema of its resources (RFC
+// 7643 section 6).
+type ResourceType struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Endpoint    string   `json:"endpoint"`
+	Description string   `json:"description"`
+	Schema      string   `json:"schema"`
+	Meta        Meta     `json:"meta"`
+}
+
+// Schema lists the attributes of a resource that are supported (RFC 7643
+// section 7).
+type Schema struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Attributes  []Attribute `json:"attributes"`
+	Meta        Meta        `json:"meta"`
+}
+
+type Attribute struct {
+	Name           string      `json:"name"`
+	Type           string      `json:"type"`
+	MultiValued    bool        `json:"multiValued"`
+	Description    string      `json:"description,omitempty"`
+	Required       bool        `json:"required"`
+	CaseExact      bool        `json:"caseExact"`
+	Mutability     string      `json:"mutability"`
+	Returned       string      `json:"returned"`
+	Uniqueness     string      `json:"uniqueness"`
+	ReferenceTypes []string    `json:"referenceTypes,omitempty"`
+	SubAttributes  []Attribute `json:"subAttributes,omitempty"`
+}
+
+// attr is a single valued, optional, read-write string attribute that is
+// returned by default, which most attributes are.
+func attr(name, description string) Attribute {
+	return Attribute{
+		Name:        name,
+		Type:        "string",
+		Description: description,
+		Mutability:  "readWrite",
+		Returned:    "default",
+		Uniqueness:  "none",
+	}
+}
+
+func serviceProviderConfig(baseURL string) ServiceProviderConfig {
+	return ServiceProviderConfig{
+		Schemas:        []string{SchemaServiceProviderConfig},
+		Patch:          Supported{Supported: true},
+		Bulk:           BulkSupport{},
+		Filter:         FilterSupport{Supported: true, MaxResults: MaxResults},
+		ChangePassword: Supported{Supported: true},
+		AuthenticationSchemes: []AuthenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "Bearer token",
+			Description: "A SCIM token created by an admin through POST /scim/tokens.",
+			Primary:     true,
+		}},
+		Meta: Meta{ResourceType: "ServiceProviderConfig", Location: baseURL + "/ServiceProviderConfig"},
+	}
+}
+
+func resourceTypes(baseURL string) []ResourceType {
+	return []ResourceType{
+		{
+			Schemas:     []string{SchemaResourceType},
+			ID:          ResourceUser,
+			Name:        ResourceUser,
+			Endpoint:    "/Users",
+			Description: "User accounts",
+			Schema:      SchemaUser,
+			Meta:        Meta{ResourceType: "ResourceType", Location: baseURL + "/ResourceTypes/" + ResourceUser},
+		},
+		{
+			Schemas:     []string{SchemaResourceType},
+			ID:          ResourceGroup,
+			Name:        ResourceGroup,
+			Endpoint:    "/Groups",
+			Description: "Groups of users",
+			Schema:      SchemaGroup,
+			Meta:        Meta{ResourceType: "ResourceType", Location: baseURL + "/ResourceTypes/" + ResourceGroup},
+		},
+	}
+}
+
+// schemas describes the attributes that are stored; others sent by clients are
+// ignored.
+func schemas(baseURL string) []Schema {
+	userName := attr("userName", "The user's email address, unique within the tenant.")
+	userName.Required = true
+	userName.Uniqueness = "server"
+	password := attr("password", "Sets the user's password; it must meet the password policy.")
+	password.Mutability = "writeOnly"
+	password.Returned = "never"
+	active := attr("active", "Whether the user can log in.")
+	active.Type = "boolean"
+	name := attr("name", "The user's name; only one name is stored.")
+	name.Type = "complex"
+	name.SubAttributes = []Attribute{
+		attr("formatted", "The full name."),
+		attr("givenName", "Used with familyName when formatted and displayName are absent."),
+		attr("familyName", "Used with givenName when formatted and displayName are absent."),
+	}
+	emails := attr("emails", "The user's email address, the same as userName.")
+	emails.Type = "complex"
+	emails.MultiValued = true
+	emails.SubAttributes = []Attribute{
+		attr("value", "The email address."),
+		attr("type", "Always work."),
+		attr("primary", "Always true."),
+	}
+	emails.SubAttributes[2].Type = "boolean"
+
+	displayName := attr("displayName", "The group's name, unique within the tenant.")
+	displayName.Required = true
+	displayName.Uniqueness = "server"
+	members := attr("members", "The users in the group.")
+	members.Type = "complex"
+	members.MultiValued = true
+	value := attr("value", "The id of the user.")
+	value.Mutability = "immutable"
+	ref := attr("$ref", "The location of the user.")
+	ref.Type = "reference"
+	ref.Mutability = "immutable"
+	ref.ReferenceTypes = []string{ResourceUser}
+	memberType := attr("type", "Always User.")
+	memberType.Mutability = "immutable"
+	members.SubAttributes = []Attribute{value, ref, attr("display", "The user's name."), memberType}
+
+	return []Schema{
+		{
+			Schemas:     []string{SchemaSchema},
+			ID:          SchemaUser,
+			Name:        ResourceUser,
+			Description: "User account",
+			Attributes:  []Attribute{userName, name, attr("displayName", "The user's name."), emails, active, password},
+			Meta:        Meta{ResourceType: "Schema", Location: baseURL + "/Schemas/" + SchemaUser},
+		},
+		{
+			Schemas:     []string{SchemaSchema},
+			ID:          SchemaGroup,
+			Name:        ResourceGroup,
+			Description: "Group of users",
+			Attributes:  []Attribute{displayName, members},
+			Meta:        Meta{ResourceType: "Schema", Location: baseURL + "/Schemas/" + SchemaGroup},
+		},
+	}
+}
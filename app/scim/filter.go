@@ -0,0 +1,45 @@
+package scim
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// eqFilter matches the one kind of filter supported: an attribute compared
+// for equality with a string, as in userName eq "alice@example.com".
+var eqFilter = regexp.MustCompile(`(?i)^\s*([a-z][a-z0-9._:]*)\s+eq\s+("(?:[^"\\]|\\.)*")\s*$`)
+
+// memberFilter matches the path that picks one member of a group, as in
+// members[value eq "60d5..."].
+var memberFilter = regexp.MustCompile(`(?i)^members\[\s*value\s+eq\s+("(?:[^"\\]|\\.)*")\s*\]$`)
+
+// parseFilter reads filter as an equality test on attribute, whose name is
+// matched without regard to case. An empty filter matches everything and
+// returns ok false.
+func parseFilter(filter, attribute string) (value string, ok bool, err error) {
+	if strings.TrimSpace(filter) == "" {
+		return "", false, nil
+	}
+	m := eqFilter.FindStringSubmatch(filter)
+	if m == nil || !strings.EqualFold(m[1], attribute) {
+		return "", false, NewError(http.StatusBadRequest, ErrorInvalidFilter, "Only filters of the form "+attribute+` eq "value" are supported.`)
+	}
+	value, err = strconv.Unquote(m[2])
+	if err != nil {
+		return "", false, NewError(http.StatusBadRequest, ErrorInvalidFilter, "The filter value is not a valid string.")
+	}
+	return value, true, nil
+}
+
+// parseMemberPath returns the user id picked by a members[value eq "..."]
+// path.
+func parseMemberPath(path string) (string, bool) {
+	m := memberFilter.FindStringSubmatch(strings.TrimSpace(path))
+	if m == nil {
+		return "", false
+	}
+	id, err := strconv.Unquote(m[1])
+	return id, err == nil
+}
@@ -0,0 +1,287 @@
+package scim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"user-management/app/group"
+	"user-management/pagination"
+	"user-management/response"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FindGroups lists the groups of the token's tenant by name. The only filter
+// supported is displayName eq, which finds the group with that name.
+func (u *usecase) FindGroups(ctx context.Context, req ListRequest) (ListResponse, error) {
+	name, filtered, err := parseFilter(req.Filter, "displayName")
+	if err != nil {
+		return ListResponse{}, err
+	}
+	var found []group.FindGroupResponse
+	if filtered {
+		g, err := u.groupRepo.FindGroupByName(ctx, name)
+		switch {
+		case err == nil:
+			found = append(found, g)
+		case !errors.Is(err, group.ErrGroupNotFound):
+			return ListResponse{}, err
+		}
+	} else if found, err = u.allGroups(ctx); err != nil {
+		return ListResponse{}, err
+	}
+	return listResponse(found, req, func(g group.FindGroupResponse) (any, error) {
+		return u.toGroup(ctx, g, !req.ExcludeMembers)
+	})
+}
+
+func (u *usecase) FindGroup(ctx context.Context, id string, withMembers bool) (Group, error) {
+	found, err := u.findGroup(ctx, id)
+	if err != nil {
+		return Group{}, err
+	}
+	return u.toGroup(ctx, found, withMembers)
+}
+
+func (u *usecase) CreateGroup(ctx context.Context, req Group) (Group, error) {
+	name := strings.TrimSpace(req.DisplayName)
+	if name == "" {
+		return Group{}, NewError(http.StatusBadRequest, ErrorInvalidValue, "displayName is required.")
+	}
+	members := memberValues(req.Members)
+	if err := u.checkMembers(ctx, members); err != nil {
+		return Group{}, err
+	}
+	resp, err := u.groups.CreateGroup(ctx, group.CreateRequest{Name: name})
+	if err != nil {
+		return Group{}, err
+	}
+	if !resp.IsSuccess() {
+		return Group{}, fromResponse(resp)
+	}
+	id := resp.Data.(group.CreateResponse).Id
+	created, err := u.findGroup(ctx, id)
+	if err != nil {
+		return Group{}, err
+	}
+	if err := u.saveGroup(ctx, created, nil, groupState{name: name, members: members}); err != nil {
+		return Group{}, err
+	}
+	return u.FindGroup(ctx, id, true)
+}
+
+// ReplaceGroup sets the group's name and members; members left out are
+// removed.
+func (u *usecase) ReplaceGroup(ctx context.Context, id string, req Group) (Group, error) {
+	found, err := u.findGroup(ctx, id)
+	if err != nil {
+		return Group{}, err
+	}
+	current, err := u.memberIDs(ctx, found.Id)
+	if err != nil {
+		return Group{}, err
+	}
+	if err := u.saveGroup(ctx, found, current, groupState{name: req.DisplayName, members: memberValues(req.Members)}); err != nil {
+		return Group{}, err
+	}
+	return u.FindGroup(ctx, id, true)
+}
+
+// PatchGroup applies the operations of req to the group in turn, then saves
+// the result.
+func (u *usecase) PatchGroup(ctx context.Context, id string, req PatchRequest) (Group, error) {
+	found, err := u.findGroup(ctx, id)
+	if err != nil {
+		return Group{}, err
+	}
+	current, err := u.memberIDs(ctx, found.Id)
+	if err != nil {
+		return Group{}, err
+	}
+	state := groupState{name: found.Name, members: slices.Clone(current)}
+	if err := state.apply(req); err != nil {
+		return Group{}, err
+	}
+	if err := u.saveGroup(ctx, found, current, state); err != nil {
+		return Group{}, err
+	}
+	return u.FindGroup(ctx, id, true)
+}
+
+func (u *usecase) DeleteGroup(ctx context.Context, id string) error {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return notFound(ResourceGroup, id)
+	}
+	resp, err := u.groups.DeleteGroup(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !resp.IsSuccess() {
+		return fromResponse(resp)
+	}
+	return nil
+}
+
+// saveGroup renames found and changes its members from current to those of
+// state. Members are checked before anything is changed.
+func (u *usecase) saveGroup(ctx context.Context, found group.FindGroupResponse, current []string, state groupState) error {
+	name := strings.TrimSpace(state.name)
+	if name == "" {
+		return NewError(http.StatusBadRequest, ErrorInvalidValue, "displayName is required.")
+	}
+	var added, removed []string
+	for _, m := range state.members {
+		if !slices.Contains(current, m) {
+			added = append(added, m)
+		}
+	}
+	for _, m := range current {
+		if !slices.Contains(state.members, m) {
+			removed = append(removed, m)
+		}
+	}
+	if err := u.checkMembers(ctx, added); err != nil {
+		return err
+	}
+
+	gid, err := primitive.ObjectIDFromHex(found.Id)
+	if err != nil {
+		return err
+	}
+	if name != found.Name {
+		resp, err := u.groups.UpdateGroup(ctx, gid, group.UpdateRequest{Name: name})
+		if err != nil {
+			return err
+		}
+		if !resp.IsSuccess() {
+			return fromResponse(resp)
+		}
+	}
+	for _, m := range added {
+		uid, _ := primitive.ObjectIDFromHex(m)
+		resp, err := u.groups.AddMember(ctx, gid, uid)
+		if err != nil {
+			return err
+		}
+		if !resp.IsSuccess() && resp.Code != response.DuplicatedMember().Code {
+			return fromResponse(resp)
+		}
+	}
+	for _, m := range removed {
+		uid, err := primitive.ObjectIDFromHex(m)
+		if err != nil {
+			continue
+		}
+		resp, err := u.groups.RemoveMember(ctx, gid, uid)
+		if err != nil {
+			return err
+		}
+		if !resp.IsSuccess() && resp.Code != response.MemberNotFound().Code {
+			return fromResponse(resp)
+		}
+	}
+	return nil
+}
+
+// checkMembers makes sure every id names a user of the tenant.
+func (u *usecase) checkMembers(ctx context.Context, ids []string) error {
+	for _, id := range ids {
+		if _, err := u.findUser(ctx, id); err != nil {
+			var scimErr *Error
+			if errors.As(err, &scimErr) {
+				return NewError(http.StatusBadRequest, ErrorInvalidValue, "Member "+id+" is not a user.")
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *usecase) findGroup(ctx context.Context, id string) (group.FindGroupResponse, error) {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return group.FindGroupResponse{}, notFound(ResourceGroup, id)
+	}
+	found, err := u.groupRepo.FindGroupById(ctx, id)
+	if errors.Is(err, group.ErrGroupNotFound) {
+		return found, notFound(ResourceGroup, id)
+	}
+	return found, err
+}
+
+// allGroups reads every group of the tenant, a page at a time.
+func (u *usecase) allGroups(ctx context.Context) ([]group.FindGroupResponse, error) {
+	var all []group.FindGroupResponse
+	for page := (pagination.Request{Page: 1, Limit: pagination.MaxLimit}); ; page.Page++ {
+		groups, total, err := u.groupRepo.FindGroups(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, groups...)
+		if len(groups) == 0 || int64(len(all)) >= total {
+			return all, nil
+		}
+	}
+}
+
+// memberIDs reads the ids of every member of group id, a page at a time.
+func (u *usecase) memberIDs(ctx context.Context, id string) ([]string, error) {
+	gid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for page := (pagination.Request{Page: 1, Limit: pagination.MaxLimit}); ; page.Page++ {
+		members, total, err := u.groupRepo.FindMembers(ctx, gid, page)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range members {
+			ids = append(ids, m.UserID)
+		}
+		if len(members) == 0 || int64(len(ids)) >= total {
+			return ids, nil
+		}
+	}
+}
+
+func (u *usecase) toGroup(ctx context.Context, g group.FindGroupResponse, withMembers bool) (Group, error) {
+	created := g.CreatedAt
+	result := Group{
+		Schemas:     []string{SchemaGroup},
+		ID:          g.Id,
+		DisplayName: g.Name,
+		Meta: &Meta{
+			ResourceType: ResourceGroup,
+			Created:      &created,
+			Location:     u.location("Groups", g.Id),
+		},
+	}
+	if !withMembers {
+		return result, nil
+	}
+	ids, err := u.memberIDs(ctx, g.Id)
+	if err != nil {
+		return Group{}, err
+	}
+	result.Members = make([]Member, 0, len(ids))
+	for _, id := range ids {
+		result.Members = append(result.Members, Member{
+			Value: id,
+			Ref:   u.location("Users", id),
+			Type:  ResourceUser,
+		})
+	}
+	return result, nil
+}
+
+func memberValues(members []Member) []string {
+	var ids []string
+	for _, m := range members {
+		if !slices.Contains(ids, m.Value) {
+			ids = append(ids, m.Value)
+		}
+	}
+	return ids
+}
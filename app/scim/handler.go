@@ -0,0 +1,376 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"user-management/logger"
+	"user-management/response"
+
+	echo "github.com/labstack/echo/v4"
+)
+
+type Usecase interface {
+	CreateToken(ctx context.Context, req CreateTokenRequest) (*response.StdResp[any], error)
+	FindTokens(ctx context.Context) (*response.StdResp[any], error)
+	DeleteToken(ctx context.Context, id string) (*response.StdResp[any], error)
+	FindUsers(ctx context.Context, req ListRequest) (ListResponse, error)
+	FindUser(ctx context.Context, id string) (User, error)
+	CreateUser(ctx context.Context, req User) (User, error)
+	ReplaceUser(ctx context.Context, id string, req User) (User, error)
+	PatchUser(ctx context.Context, id string, req PatchRequest) (User, error)
+	DeleteUser(ctx context.Context, id string) error
+	FindGroups(ctx context.Context, req ListRequest) (ListResponse, error)
+	FindGroup(ctx context.Context, id string, withMembers bool) (Group, error)
+	CreateGroup(ctx context.Context, req Group) (Group, error)
+	ReplaceGroup(ctx context.Context, id string, req Group) (Group, error)
+	PatchGroup(ctx context.Context, id string, req PatchRequest) (Group, error)
+	DeleteGroup(ctx context.Context, id string) error
+	ServiceProviderConfig() ServiceProviderConfig
+	ResourceTypes() []ResourceType
+	Schemas() []Schema
+}
+
+type Handler interface {
+	CreateToken(c echo.Context) error
+	FindTokens(c echo.Context) error
+	DeleteToken(c echo.Context) error
+	FindUsers(c echo.Context) error
+	FindUser(c echo.Context) error
+	CreateUser(c echo.Context) error
+	ReplaceUser(c echo.Context) error
+	PatchUser(c echo.Context) error
+	DeleteUser(c echo.Context) error
+	FindGroups(c echo.Context) error
+	FindGroup(c echo.Context) error
+	CreateGroup(c echo.Context) error
+	ReplaceGroup(c echo.Context) error
+	PatchGroup(c echo.Context) error
+	DeleteGroup(c echo.Context) error
+	ServiceProviderConfig(c echo.Context) error
+	ResourceTypes(c echo.Context) error
+	ResourceType(c echo.Context) error
+	Schemas(c echo.Context) error
+	Schema(c echo.Context) error
+}
+
+type handler struct {
+	usecase Usecase
+}
+
+func NewHandler(u Usecase) *handler {
+	return &handler{
+		usecase: u,
+	}
+}
+
+func (h *handler) CreateToken(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	var request CreateTokenRequest
+	err = c.Bind(&request)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Bind request error: %v", err.Error())
+		return c.JSON(response.UnexpectedRequest().WithHTTPStatus())
+	}
+
+	if resp := request.RequestValidation(); !resp.IsSuccess() {
+		return c.JSON(resp.WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.CreateToken(ctx, request)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+func (h *handler) FindTokens(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.FindTokens(ctx)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+func (h *handler) DeleteToken(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	paramId := c.Param(ParamID)
+	if respValidate := IdValidation(paramId); !respValidate.IsSuccess() {
+		return c.JSON(respValidate.WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.DeleteToken(ctx, paramId)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+func (h *handler) FindUsers(c echo.Context) error {
+	req, err := listRequest(c)
+	if err != nil {
+		return h.scimError(c, err)
+	}
+	resp, err := h.usecase.FindUsers(c.Request().Context(), req)
+	if err != nil {
+		return h.scimError(c, err)
+	}
+	return scimJSON(c, http.StatusOK, resp)
+}
+
+func (h *handler) FindUser(c echo.Context) error {
+	resp, err := h.usecase.FindUser(c.Request().Context(), c.Param(ParamID))
+	if err != nil {
+		return h.scimError(c, err)
+	}
+	return scimJSON(c, http.StatusOK, resp)
+}
+
+func (h *handler) CreateUser(c echo.Context) error {
+	var request User
+	if err := decode(c, &request); err != nil {
+		return h.scimError(c, err)
+	}
+	resp, err := h.usecase.CreateUser(c.Request().Context(), request)
+	if err != nil {
+		return h.scimError(c, err)
+	}
+	c.Response().Header().Set(echo.HeaderLocation, resp.Meta.Location)
+	return scimJSON(c, http.StatusCreated, resp)
+}
+
+func (h *handler) ReplaceUser(c echo.Context) error {
+	var request User
+	if err := decode(c, &request); err != nil {
+		return h.scimError(c, err)
+	}
+	resp, err := h.usecase.ReplaceUser(c.Request().Context(), c.Param(ParamID), request)
+	if err != nil {
+		return h.scimError(c, err)
+	}
+	return scimJSON(c, http.StatusOK, resp)
+}
+
+func (h *handler) PatchUser(c echo.Context) error {
+	var request PatchRequest
+	if err := decode(c, &request); err != nil {
+		return h.scimError(c, err)
+	}
+	resp, err := h.usecase.PatchUser(c.Request().Context(), c.Param(ParamID), request)
+	if err != nil {
+		return h.scimError(c, err)
+	}
+	return scimJSON(c, http.StatusOK, resp)
+}
+
+func (h *handler) DeleteUser(c echo.Context) error {
+	if err := h.usecase.DeleteUser(c.Request().Context(), c.Param(ParamID)); err != nil {
+		return h.scimError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *handler) FindGroups(c echo.Context) error {
+	req, err := listRequest(c)
+	if err != nil {
+		return h.scimError(c, err)
+	}
+	resp, err := h.usecase.FindGroups(c.Request().Context(), req)
+	if err != nil {
+		return h.scimError(c, err)
+	}
+	return scimJSON(c, http.StatusOK, resp)
+}
+
+func (h *handler) FindGroup(c echo.Context) error {
+	resp, err := h.usecase.FindGroup(c.Request().Context(), c.Param(ParamID), !excludesMembers(c))
+	if err != nil {
+		return h.scimError(c, err)
+	}
+	return scimJSON(c, http.StatusOK, resp)
+}
+
+func (h *handler) CreateGroup(c echo.Context) error {
+	var request Group
+	if err := decode(c, &request); err != nil {
+		return h.scimError(c, err)
+	}
+	resp, err := h.usecase.CreateGroup(c.Request().Context(), request)
+	if err != nil {
+		return h.scimError(c, err)
+	}
+	c.Response().Header().Set(echo.HeaderLocation, resp.Meta.Location)
+	return scimJSON(c, http.StatusCreated, resp)
+}
+
+func (h *handler) ReplaceGroup(c echo.Context) error {
+	var request Group
+	if err := decode(c, &request); err != nil {
+		return h.scimError(c, err)
+	}
+	resp, err := h.usecase.ReplaceGroup(c.Request().Context(), c.Param(ParamID), request)
+	if err != nil {
+		return h.scimError(c, err)
+	}
+	return scimJSON(c, http.StatusOK, resp)
+}
+
+func (h *handler) PatchGroup(c echo.Context) error {
+	var request PatchRequest
+	if err := decode(c, &request); err != nil {
+		return h.scimError(c, err)
+	}
+	resp, err := h.usecase.PatchGroup(c.Request().Context(), c.Param(ParamID), request)
+	if err != nil {
+		return h.scimError(c, err)
+	}
+	return scimJSON(c, http.StatusOK, resp)
+}
+
+func (h *handler) DeleteGroup(c echo.Context) error {
+	if err := h.usecase.DeleteGroup(c.Request().Context(), c.Param(ParamID)); err != nil {
+		return h.scimError(c, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *handler) ServiceProviderConfig(c echo.Context) error {
+	return scimJSON(c, http.StatusOK, h.usecase.ServiceProviderConfig())
+}
+
+func (h *handler) ResourceTypes(c echo.Context) error {
+	types := h.usecase.ResourceTypes()
+	resources := make([]any, 0, len(types))
+	for _, t := range types {
+		resources = append(resources, t)
+	}
+	return scimJSON(c, http.StatusOK, discoveryList(resources))
+}
+
+func (h *handler) ResourceType(c echo.Context) error {
+	for _, t := range h.usecase.ResourceTypes() {
+		if t.ID == c.Param(ParamID) {
+			return scimJSON(c, http.StatusOK, t)
+		}
+	}
+	return h.scimError(c, notFound("ResourceType", c.Param(ParamID)))
+}
+
+func (h *handler) Schemas(c echo.Context) error {
+	schemas := h.usecase.Schemas()
+	resources := make([]any, 0, len(schemas))
+	for _, s := range schemas {
+		resources = append(resources, s)
+	}
+	return scimJSON(c, http.StatusOK, discoveryList(resources))
+}
+
+func (h *handler) Schema(c echo.Context) error {
+	for _, s := range h.usecase.Schemas() {
+		if s.ID == c.Param(ParamID) {
+			return scimJSON(c, http.StatusOK, s)
+		}
+	}
+	return h.scimError(c, notFound("Schema", c.Param(ParamID)))
+}
+
+// scimError answers with err as a SCIM error response. Other errors are
+// logged and hidden behind a bare 500.
+func (h *handler) scimError(c echo.Context, err error) error {
+	var scimErr *Error
+	if errors.As(err, &scimErr) {
+		return scimJSON(c, scimErr.HTTPStatus(), scimErr)
+	}
+	if zlog, logErr := logger.FromContext(c.Request().Context()); logErr == nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+	}
+	return scimJSON(c, http.StatusInternalServerError, NewError(http.StatusInternalServerError, "", response.InternalServerError().Message))
+}
+
+// scimJSON writes v with the SCIM media type, which c.JSON would replace.
+func scimJSON(c echo.Context, status int, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.Blob(status, ContentType, body)
+}
+
+// decode reads a SCIM request body. Clients send it as application/scim+json,
+// which echo's binder does not accept, so it is decoded here whatever the
+// content type.
+func decode(c echo.Context, v any) error {
+	if err := json.NewDecoder(io.LimitReader(c.Request().Body, maxRequestSize)).Decode(v); err != nil {
+		return NewError(http.StatusBadRequest, ErrorInvalidSyntax, "The request body is not valid JSON.")
+	}
+	return nil
+}
+
+// listRequest reads the query parameters of a list request. startIndex is
+// 1-based and below 1 means 1; count is capped at MaxResults and below 0
+// means 0 (RFC 7644 section 3.4.2.4).
+func listRequest(c echo.Context) (ListRequest, error) {
+	req := ListRequest{
+		Filter:         c.QueryParam(QueryFilter),
+		StartIndex:     1,
+		Count:          MaxResults,
+		ExcludeMembers: excludesMembers(c),
+	}
+	if s := c.QueryParam(QueryStartIndex); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return req, NewError(http.StatusBadRequest, ErrorInvalidValue, QueryStartIndex+" must be a number.")
+		}
+		req.StartIndex = max(n, 1)
+	}
+	if s := c.QueryParam(QueryCount); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return req, NewError(http.StatusBadRequest, ErrorInvalidValue, QueryCount+" must be a number.")
+		}
+		req.Count = min(max(n, 0), MaxResults)
+	}
+	return req, nil
+}
+
+// excludesMembers reports whether the client left members out of the
+// attributes it wants, as identity providers do to keep group reads cheap.
+func excludesMembers(c echo.Context) bool {
+	for _, a := range strings.Split(c.QueryParam(QueryExcludedAttributes), ",") {
+		if strings.EqualFold(strings.TrimSpace(a), "members") {
+			return true
+		}
+	}
+	return false
+}
+
+func discoveryList(resources []any) ListResponse {
+	return ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: len(resources),
+		StartIndex:   1,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}
+}
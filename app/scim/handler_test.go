@@ -0,0 +1,319 @@
+package scim_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-management/app/scim"
+	"user-management/logger"
+	"user-management/response"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockUsecase struct {
+	mock.Mock
+}
+
+func (m *mockUsecase) CreateToken(ctx context.Context, req scim.CreateTokenRequest) (*response.StdResp[any], error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) FindTokens(ctx context.Context) (*response.StdResp[any], error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) DeleteToken(ctx context.Context, id string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) FindUsers(ctx context.Context, req scim.ListRequest) (scim.ListResponse, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(scim.ListResponse), args.Error(1)
+}
+
+func (m *mockUsecase) FindUser(ctx context.Context, id string) (scim.User, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(scim.User), args.Error(1)
+}
+
+func (m *mockUsecase) CreateUser(ctx context.Context, req scim.User) (scim.User, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(scim.User), args.Error(1)
+}
+
+func (m *mockUsecase) ReplaceUser(ctx context.Context, id string, req scim.User) (scim.User, error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(scim.User), args.Error(1)
+}
+
+func (m *mockUsecase) PatchUser(ctx context.Context, id string, req scim.PatchRequest) (scim.User, error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(scim.User), args.Error(1)
+}
+
+func (m *mockUsecase) DeleteUser(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *mockUsecase) FindGroups(ctx context.Context, req scim.ListRequest) (scim.ListResponse, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(scim.ListResponse), args.Error(1)
+}
+
+func (m *mockUsecase) FindGroup(ctx context.Context, id string, withMembers bool) (scim.Group, error) {
+	args := m.Called(ctx, id, withMembers)
+	return args.Get(0).(scim.Group), args.Error(1)
+}
+
+func (m *mockUsecase) CreateGroup(ctx context.Context, req scim.Group) (scim.Group, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(scim.Group), args.Error(1)
+}
+
+func (m *mockUsecase) ReplaceGroup(ctx context.Context, id string, req scim.Group) (scim.Group, error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(scim.Group), args.Error(1)
+}
+
+func (m *mockUsecase) PatchGroup(ctx context.Context, id string, req scim.PatchRequest) (scim.Group, error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(scim.Group), args.Error(1)
+}
+
+func (m *mockUsecase) DeleteGroup(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *mockUsecase) ServiceProviderConfig() scim.ServiceProviderConfig {
+	return m.Called().Get(0).(scim.ServiceProviderConfig)
+}
+
+func (m *mockUsecase) ResourceTypes() []scim.ResourceType {
+	return m.Called().Get(0).([]scim.ResourceType)
+}
+
+func (m *mockUsecase) Schemas() []scim.Schema {
+	return m.Called().Get(0).([]scim.Schema)
+}
+
+// newTestContext sends body as SCIM clients do, with the SCIM media type.
+func newTestContext(method, target string, body any) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	var req *http.Request
+	switch b := body.(type) {
+	case nil:
+		req = httptest.NewRequest(method, target, nil)
+	case string:
+		req = httptest.NewRequest(method, target, bytes.NewBufferString(b))
+		req.Header.Set(echo.HeaderContentType, scim.ContentType)
+	default:
+		j, _ := json.Marshal(body)
+		req = httptest.NewRequest(method, target, bytes.NewReader(j))
+		req.Header.Set(echo.HeaderContentType, scim.ContentType)
+	}
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	ctx := context.WithValue(c.Request().Context(), logger.LogContext, logger.NewZap())
+	c.SetRequest(req.WithContext(ctx))
+	return c, rec
+}
+
+func decodeError(t *testing.T, rec *httptest.ResponseRecorder) scim.Error {
+	t.Helper()
+	var scimErr scim.Error
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &scimErr))
+	return scimErr
+}
+
+func TestHandlerFindUsers(t *testing.T) {
+	c, rec := newTestContext(http.MethodGet, `/scim/v2/Users?filter=userName+eq+"alice@acme.com"&startIndex=0&count=500`, nil)
+	mockUc := new(mockUsecase)
+	mockUc.On("FindUsers", mock.Anything, scim.ListRequest{Filter: `userName eq "alice@acme.com"`, StartIndex: 1, Count: scim.MaxResults}).
+		Return(scim.ListResponse{Schemas: []string{scim.SchemaListResponse}, TotalResults: 1}, nil)
+
+	err := scim.NewHandler(mockUc).FindUsers(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, scim.ContentType, rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Body.String(), `"totalResults":1`)
+	mockUc.AssertExpectations(t)
+}
+
+func TestHandlerFindUsers_InvalidCount(t *testing.T) {
+	c, rec := newTestContext(http.MethodGet, "/scim/v2/Users?count=ten", nil)
+	mockUc := new(mockUsecase)
+
+	err := scim.NewHandler(mockUc).FindUsers(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, scim.ErrorInvalidValue, decodeError(t, rec).ScimType)
+	mockUc.AssertNotCalled(t, "FindUsers")
+}
+
+func TestHandlerFindGroups_ExcludeMembers(t *testing.T) {
+	c, rec := newTestContext(http.MethodGet, "/scim/v2/Groups?excludedAttributes=members&count=5", nil)
+	mockUc := new(mockUsecase)
+	mockUc.On("FindGroups", mock.Anything, scim.ListRequest{StartIndex: 1, Count: 5, ExcludeMembers: true}).Return(scim.ListResponse{}, nil)
+
+	err := scim.NewHandler(mockUc).FindGroups(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUc.AssertExpectations(t)
+}
+
+func TestHandlerCreateUser(t *testing.T) {
+	c, rec := newTestContext(http.MethodPost, "/scim/v2/Users", `{"schemas":["`+scim.SchemaUser+`"],"userName":"alice@acme.com"}`)
+	mockUc := new(mockUsecase)
+	mockUc.On("CreateUser", mock.Anything, scim.User{Schemas: []string{scim.SchemaUser}, UserName: "alice@acme.com"}).
+		Return(scim.User{ID: "u1", UserName: "alice@acme.com", Meta: &scim.Meta{Location: "https://users.example.com/scim/v2/Users/u1"}}, nil)
+
+	err := scim.NewHandler(mockUc).CreateUser(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "https://users.example.com/scim/v2/Users/u1", rec.Header().Get(echo.HeaderLocation))
+	assert.Contains(t, rec.Body.String(), `"userName":"alice@acme.com"`)
+}
+
+func TestHandlerCreateUser_InvalidBody(t *testing.T) {
+	c, rec := newTestContext(http.MethodPost, "/scim/v2/Users", `{"userName":`)
+	mockUc := new(mockUsecase)
+
+	err := scim.NewHandler(mockUc).CreateUser(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, scim.ErrorInvalidSyntax, decodeError(t, rec).ScimType)
+	mockUc.AssertNotCalled(t, "CreateUser")
+}
+
+func TestHandlerCreateUser_Conflict(t *testing.T) {
+	c, rec := newTestContext(http.MethodPost, "/scim/v2/Users", scim.User{UserName: "alice@acme.com"})
+	mockUc := new(mockUsecase)
+	mockUc.On("CreateUser", mock.Anything, mock.Anything).Return(scim.User{}, scim.NewError(http.StatusConflict, scim.ErrorUniqueness, "taken"))
+
+	err := scim.NewHandler(mockUc).CreateUser(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	scimErr := decodeError(t, rec)
+	assert.Equal(t, []string{scim.SchemaError}, scimErr.Schemas)
+	assert.Equal(t, "409", scimErr.Status)
+	assert.Equal(t, scim.ErrorUniqueness, scimErr.ScimType)
+}
+
+func TestHandlerPatchUser(t *testing.T) {
+	body := `{"schemas":["` + scim.SchemaPatchOp + `"],"Operations":[{"op":"replace","path":"active","value":false}]}`
+	c, rec := newTestContext(http.MethodPatch, "/scim/v2/Users/u1", body)
+	c.SetParamNames(scim.ParamID)
+	c.SetParamValues("u1")
+	mockUc := new(mockUsecase)
+	mockUc.On("PatchUser", mock.Anything, "u1", mock.MatchedBy(func(req scim.PatchRequest) bool {
+		return len(req.Operations) == 1 && req.Operations[0].Path == "active" && string(req.Operations[0].Value) == "false"
+	})).Return(scim.User{ID: "u1"}, nil)
+
+	err := scim.NewHandler(mockUc).PatchUser(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUc.AssertExpectations(t)
+}
+
+func TestHandlerDeleteUser(t *testing.T) {
+	c, rec := newTestContext(http.MethodDelete, "/scim/v2/Users/u1", nil)
+	c.SetParamNames(scim.ParamID)
+	c.SetParamValues("u1")
+	mockUc := new(mockUsecase)
+	mockUc.On("DeleteUser", mock.Anything, "u1").Return(nil)
+
+	err := scim.NewHandler(mockUc).DeleteUser(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestHandlerDeleteUser_ServiceError(t *testing.T) {
+	c, rec := newTestContext(http.MethodDelete, "/scim/v2/Users/u1", nil)
+	c.SetParamNames(scim.ParamID)
+	c.SetParamValues("u1")
+	mockUc := new(mockUsecase)
+	mockUc.On("DeleteUser", mock.Anything, "u1").Return(errors.New("db down"))
+
+	err := scim.NewHandler(mockUc).DeleteUser(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "db down")
+}
+
+func TestHandlerSchema(t *testing.T) {
+	mockUc := new(mockUsecase)
+	mockUc.On("Schemas").Return([]scim.Schema{{ID: scim.SchemaUser, Name: "User"}})
+
+	c, rec := newTestContext(http.MethodGet, "/scim/v2/Schemas/"+scim.SchemaUser, nil)
+	c.SetParamNames(scim.ParamID)
+	c.SetParamValues(scim.SchemaUser)
+	assert.NoError(t, scim.NewHandler(mockUc).Schema(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"name":"User"`)
+
+	c, rec = newTestContext(http.MethodGet, "/scim/v2/Schemas/nope", nil)
+	c.SetParamNames(scim.ParamID)
+	c.SetParamValues("nope")
+	assert.NoError(t, scim.NewHandler(mockUc).Schema(c))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHandlerResourceTypes(t *testing.T) {
+	c, rec := newTestContext(http.MethodGet, "/scim/v2/ResourceTypes", nil)
+	mockUc := new(mockUsecase)
+	mockUc.On("ResourceTypes").Return([]scim.ResourceType{{ID: "User"}, {ID: "Group"}})
+
+	err := scim.NewHandler(mockUc).ResourceTypes(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var list scim.ListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &list))
+	assert.Equal(t, 2, list.TotalResults)
+	assert.Len(t, list.Resources, 2)
+}
+
+func TestHandlerCreateToken_MissingName(t *testing.T) {
+	c, rec := newTestContext(http.MethodPost, "/scim/tokens", nil)
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	mockUc := new(mockUsecase)
+
+	err := scim.NewHandler(mockUc).CreateToken(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUc.AssertNotCalled(t, "CreateToken")
+}
+
+func TestHandlerDeleteToken_InvalidId(t *testing.T) {
+	c, rec := newTestContext(http.MethodDelete, "/scim/tokens/bad", nil)
+	c.SetParamNames(scim.ParamID)
+	c.SetParamValues("bad")
+	mockUc := new(mockUsecase)
+
+	err := scim.NewHandler(mockUc).DeleteToken(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockUc.AssertNotCalled(t, "DeleteToken")
+}
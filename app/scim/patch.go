@@ -0,0 +1,225 @@
+package scim
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// userPatch collects the changes of a PATCH request to a user. Nil fields are
+// left unchanged.
+type userPatch struct {
+	email      *string
+	name       *string
+	givenName  *string
+	familyName *string
+	password   *string
+	active     *bool
+}
+
+// fullName is the name the patch sets, empty when it sets none. Given and
+// family names are only used without a full name, since only one is stored.
+func (p userPatch) fullName() string {
+	if p.name != nil && strings.TrimSpace(*p.name) != "" {
+		return strings.TrimSpace(*p.name)
+	}
+	var given, family string
+	if p.givenName != nil {
+		given = *p.givenName
+	}
+	if p.familyName != nil {
+		family = *p.familyName
+	}
+	return joinName(given, family)
+}
+
+func parseUserPatch(req PatchRequest) (userPatch, error) {
+	var p userPatch
+	err := eachOperation(req, SchemaUser, func(op, path string, value json.RawMessage) error {
+		if op == OpRemove {
+			switch {
+			case path == "username", path == "name", path == "name.formatted", path == "displayname", path == "emails", strings.HasPrefix(path, "emails["):
+				return NewError(http.StatusBadRequest, ErrorMutability, path+" cannot be removed.")
+			}
+			return nil
+		}
+		return p.set(path, value)
+	})
+	return p, err
+}
+
+// set records the value of one attribute. Attributes that are not stored are
+// ignored, as identity providers send many of them.
+func (p *userPatch) set(path string, value json.RawMessage) error {
+	var err error
+	switch {
+	case path == "username":
+		p.email, err = stringValue(path, value)
+	case path == "displayname", path == "name.formatted":
+		p.name, err = stringValue(path, value)
+	case path == "name.givenname":
+		p.givenName, err = stringValue(path, value)
+	case path == "name.familyname":
+		p.familyName, err = stringValue(path, value)
+	case path == "name":
+		var name Name
+		if err := json.Unmarshal(value, &name); err != nil {
+			return invalidValue(path)
+		}
+		if name.Formatted != "" {
+			p.name = &name.Formatted
+		}
+		p.givenName, p.familyName = &name.GivenName, &name.FamilyName
+	case path == "active":
+		p.active, err = boolValue(path, value)
+	case path == "password":
+		p.password, err = stringValue(path, value)
+	case path == "emails":
+		var emails []Email
+		if err := json.Unmarshal(value, &emails); err != nil {
+			return invalidValue(path)
+		}
+		if i := slices.IndexFunc(emails, func(e Email) bool { return e.Primary }); i >= 0 {
+			p.email = &emails[i].Value
+		} else if len(emails) > 0 {
+			p.email = &emails[0].Value
+		}
+	case strings.HasPrefix(path, "emails[") && strings.HasSuffix(path, "].value"):
+		// Only one email is stored, so any of them sets it.
+		p.email, err = stringValue(path, value)
+	}
+	return err
+}
+
+// groupState is a group as a PATCH request leaves it.
+type groupState struct {
+	name    string
+	members []string
+}
+
+func (g *groupState) apply(req PatchRequest) error {
+	return eachOperation(req, SchemaGroup, func(op, path string, value json.RawMessage) error {
+		switch {
+		case path == "displayname":
+			if op == OpRemove {
+				return NewError(http.StatusBadRequest, ErrorMutability, "displayName cannot be removed.")
+			}
+			name, err := stringValue(path, value)
+			if err != nil {
+				return err
+			}
+			g.name = *name
+		case path == "members":
+			var members []Member
+			if len(value) > 0 && string(value) != "null" {
+				if err := json.Unmarshal(value, &members); err != nil {
+					return invalidValue(path)
+				}
+			}
+			switch {
+			case op == OpReplace:
+				g.members = nil
+				g.add(members)
+			case op == OpAdd:
+				g.add(members)
+			case len(members) == 0:
+				g.members = nil
+			default:
+				for _, m := range members {
+					g.remove(m.Value)
+				}
+			}
+		default:
+			id, ok := parseMemberPath(path)
+			if !ok {
+				return nil
+			}
+			if op != OpRemove {
+				return NewError(http.StatusBadRequest, ErrorInvalidPath, "A filtered members path can only be removed.")
+			}
+			g.remove(id)
+		}
+		return nil
+	})
+}
+
+func (g *groupState) add(members []Member) {
+	for _, m := range members {
+		if !slices.Contains(g.members, m.Value) {
+			g.members = append(g.members, m.Value)
+		}
+	}
+}
+
+func (g *groupState) remove(id string) {
+	g.members = slices.DeleteFunc(g.members, func(m string) bool { return m == id })
+}
+
+// eachOperation calls fn for each attribute an operation of req changes, with
+// the op and the path lower-cased and the schema prefix removed. An add or
+// replace without a path changes every attribute of its value.
+func eachOperation(req PatchRequest, schema string, fn func(op, path string, value json.RawMessage) error) error {
+	if len(req.Operations) == 0 {
+		return NewError(http.StatusBadRequest, ErrorInvalidValue, "Operations is required.")
+	}
+	for _, operation := range req.Operations {
+		op := strings.ToLower(operation.Op)
+		if op != OpAdd && op != OpReplace && op != OpRemove {
+			return NewError(http.StatusBadRequest, ErrorInvalidSyntax, "Unsupported op "+operation.Op+".")
+		}
+		path := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(operation.Path)), strings.ToLower(schema)+":")
+		if path != "" {
+			if err := fn(op, path, operation.Value); err != nil {
+				return err
+			}
+			continue
+		}
+		if op == OpRemove {
+			return NewError(http.StatusBadRequest, ErrorNoTarget, "A remove operation needs a path.")
+		}
+		var attrs map[string]json.RawMessage
+		if err := json.Unmarshal(operation.Value, &attrs); err != nil {
+			return NewError(http.StatusBadRequest, ErrorInvalidSyntax, "An operation without a path needs an object value.")
+		}
+		for _, name := range slices.Sorted(maps.Keys(attrs)) {
+			if err := fn(op, strings.ToLower(name), attrs[name]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func stringValue(path string, value json.RawMessage) (*string, error) {
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return nil, invalidValue(path)
+	}
+	return &s, nil
+}
+
+// boolValue also accepts "true" and "false" strings, which some identity
+// providers send.
+func boolValue(path string, value json.RawMessage) (*bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return &b, nil
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		switch strings.ToLower(s) {
+		case "true":
+			b = true
+			return &b, nil
+		case "false":
+			return &b, nil
+		}
+	}
+	return nil, invalidValue(path)
+}
+
+func invalidValue(path string) *Error {
+	return NewError(http.StatusBadRequest, ErrorInvalidValue, "The value of "+path+" is invalid.")
+}
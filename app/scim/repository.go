@@ -0,0 +1,65 @@
+package scim
+
+import (
+	"context"
+	"time"
+	"user-management/config"
+	"user-management/storage"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type repository struct {
+	mc  storage.DatabaseConn
+	cfg config.MongoConfig
+}
+
+func NewRepository(mc storage.DatabaseConn, cfg config.MongoConfig) *repository {
+	return &repository{
+		mc:  mc,
+		cfg: cfg,
+	}
+}
+
+func (r *repository) CreateToken(ctx context.Context, token Token) (string, error) {
+	token.CreatedAt = time.Now()
+	ior, err := r.mc.Collection(r.cfg.SCIMTokenCollection).InsertOne(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	return ior.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+// FindTokenByHash looks a token up in every tenant, since the token is what
+// tells the tenant.
+func (r *repository) FindTokenByHash(ctx context.Context, tokenHash string) (Token, error) {
+	var token Token
+	err := r.mc.Collection(r.cfg.SCIMTokenCollection).FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&token)
+	if err == mongo.ErrNoDocuments {
+		return token, ErrTokenNotFound
+	}
+	return token, err
+}
+
+// FindTokens lists the tokens of ctx's tenant, oldest first.
+func (r *repository) FindTokens(ctx context.Context) ([]Token, error) {
+	opts := options.Find().SetSort(bson.D{bson.E{Key: "created_at", Value: 1}})
+	cursor, err := r.mc.Collection(r.cfg.SCIMTokenCollection).Find(ctx, storage.TenantFilter(ctx, bson.M{}), opts)
+	if err != nil {
+		return nil, err
+	}
+	var tokens []Token
+	err = cursor.All(ctx, &tokens)
+	return tokens, err
+}
+
+func (r *repository) DeleteToken(ctx context.Context, id primitive.ObjectID) (int64, error) {
+	result, err := r.mc.Collection(r.cfg.SCIMTokenCollection).DeleteOne(ctx, storage.TenantFilter(ctx, bson.M{"_id": id}))
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,75 @@
+package scim
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+	"user-management/auth"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryRepository keeps tokens in process memory, for when the service runs
+// without a database.
+type memoryRepository struct {
+	mu     sync.Mutex
+	tokens []Token
+}
+
+func NewMemoryRepository() *memoryRepository {
+	return &memoryRepository{}
+}
+
+func (r *memoryRepository) CreateToken(ctx context.Context, token Token) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if token.ID.IsZero() {
+		token.ID = primitive.NewObjectID()
+	}
+	token.CreatedAt = time.Now()
+	r.tokens = append(r.tokens, token)
+	return token.ID.Hex(), nil
+}
+
+func (r *memoryRepository) FindTokenByHash(ctx context.Context, tokenHash string) (Token, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.TokenHash == tokenHash {
+			return t, nil
+		}
+	}
+	return Token{}, ErrTokenNotFound
+}
+
+func (r *memoryRepository) FindTokens(ctx context.Context) ([]Token, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var tokens []Token
+	for _, t := range r.tokens {
+		if inTenant(ctx, t) {
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens, nil
+}
+
+func (r *memoryRepository) DeleteToken(ctx context.Context, id primitive.ObjectID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := slices.IndexFunc(r.tokens, func(t Token) bool {
+		return t.ID == id && inTenant(ctx, t)
+	})
+	if i < 0 {
+		return 0, nil
+	}
+	r.tokens = slices.Delete(r.tokens, i, i+1)
+	return 1, nil
+}
+
+// inTenant mirrors storage.TenantFilter for a single token.
+func inTenant(ctx context.Context, t Token) bool {
+	tenantID, scoped := auth.TenantFromContext(ctx)
+	return !scoped || t.TenantID == tenantID
+}
@@ -0,0 +1,72 @@
+package scim_test
+
+import (
+	"context"
+	"testing"
+	"user-management/app/scim"
+	"user-management/auth"
+	"user-management/config"
+	"user-management/storage"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func newTestRepository(mt *mtest.T) scim.Repository {
+	dbConn := storage.NewMongoConn(mt.Client, mt.Client.Database("testdb"))
+	return scim.NewRepository(dbConn, config.MongoConfig{
+		Database:            "testdb",
+		SCIMTokenCollection: "scim_tokens",
+	})
+}
+
+func TestRepository_FindTokenByHash(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("found in any tenant", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(1, "testdb.scim_tokens", mtest.FirstBatch, bson.D{
+			bson.E{Key: "_id", Value: primitive.NewObjectID()},
+			bson.E{Key: "tenant_id", Value: "t1"},
+			bson.E{Key: "name", Value: "Okta"},
+			bson.E{Key: "token_hash", Value: "h1"},
+		}))
+
+		token, err := repo.FindTokenByHash(auth.WithTenant(context.Background(), "t2"), "h1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "t1", token.TenantID)
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		assert.Equal(t, "h1", filter.Lookup("token_hash").StringValue())
+		_, err = filter.LookupErr("tenant_id")
+		assert.Error(t, err)
+	})
+
+	mt.Run("not found", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.scim_tokens", mtest.FirstBatch))
+
+		_, err := repo.FindTokenByHash(context.Background(), "h1")
+
+		assert.ErrorIs(t, err, scim.ErrTokenNotFound)
+	})
+}
+
+func TestRepository_DeleteToken(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("tenant scoped", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}})
+
+		deleted, err := repo.DeleteToken(auth.WithTenant(context.Background(), "t1"), primitive.NewObjectID())
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+		deletes := mt.GetStartedEvent().Command.Lookup("deletes").Array()
+		filter := deletes.Index(0).Value().Document().Lookup("q").Document()
+		assert.Equal(t, "t1", filter.Lookup("tenant_id").StringValue())
+	})
+}
@@ -0,0 +1,232 @@
+package scim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"user-management/app/group"
+	"user-management/app/tenant"
+	"user-management/app/user"
+	"user-management/auth"
+	"user-management/config"
+	"user-management/opaque"
+	"user-management/pagination"
+	"user-management/response"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Repository interface {
+	CreateToken(ctx context.Context, token Token) (string, error)
+	FindTokenByHash(ctx context.Context, tokenHash string) (Token, error)
+	FindTokens(ctx context.Context) ([]Token, error)
+	DeleteToken(ctx context.Context, id primitive.ObjectID) (int64, error)
+}
+
+// UserUsecase makes the changes SCIM requests ask for, with the same checks as
+// the REST API.
+type UserUsecase interface {
+	CreateUser(ctx context.Context, req user.CreateRequest) (*response.StdResp[any], error)
+	UpdateUser(ctx context.Context, user user.User) (*response.StdResp[any], error)
+	ResetPassword(ctx context.Context, id, password string) (*response.StdResp[any], error)
+	ChangeStatus(ctx context.Context, id string, req user.StatusRequest) (*response.StdResp[any], error)
+	DeleteUser(ctx context.Context, id string) (*response.StdResp[any], error)
+}
+
+type UserRepository interface {
+	FindUserByEmail(ctx context.Context, email string) (user.User, error)
+	FindUserById(ctx context.Context, id string) (user.FindUserResponse, error)
+	FindUsers(ctx context.Context, profile map[string]any) ([]user.FindUserResponse, error)
+}
+
+type GroupUsecase interface {
+	CreateGroup(ctx context.Context, req group.CreateRequest) (*response.StdResp[any], error)
+	UpdateGroup(ctx context.Context, id primitive.ObjectID, req group.UpdateRequest) (*response.StdResp[any], error)
+	DeleteGroup(ctx context.Context, id string) (*response.StdResp[any], error)
+	AddMember(ctx context.Context, groupID, userID primitive.ObjectID) (*response.StdResp[any], error)
+	RemoveMember(ctx context.Context, groupID, userID primitive.ObjectID) (*response.StdResp[any], error)
+}
+
+type GroupRepository interface {
+	FindGroupById(ctx context.Context, id string) (group.FindGroupResponse, error)
+	FindGroupByName(ctx context.Context, name string) (group.FindGroupResponse, error)
+	FindGroups(ctx context.Context, page pagination.Request) ([]group.FindGroupResponse, int64, error)
+	FindMembers(ctx context.Context, groupID primitive.ObjectID, page pagination.Request) ([]group.MemberResponse, int64, error)
+}
+
+type TenantRepository interface {
+	FindTenantById(ctx context.Context, id string) (tenant.FindTenantResponse, error)
+}
+
+type usecase struct {
+	cfg       config.SCIMConfig
+	repo      Repository
+	users     UserUsecase
+	userRepo  UserRepository
+	groups    GroupUsecase
+	groupRepo GroupRepository
+	tenants   TenantRepository
+}
+
+func NewUsecase(cfg config.SCIMConfig, r Repository, uu UserUsecase, ur UserRepository, gu GroupUsecase, gr GroupRepository, tr TenantRepository) *usecase {
+	return &usecase{
+		cfg:       cfg,
+		repo:      r,
+		users:     uu,
+		userRepo:  ur,
+		groups:    gu,
+		groupRepo: gr,
+		tenants:   tr,
+	}
+}
+
+// CreateToken issues a token for a provisioning client. Tenant admins create
+// tokens for their own tenant; platform admins choose one or leave it empty to
+// provision platform users.
+func (u *usecase) CreateToken(ctx context.Context, req CreateTokenRequest) (*response.StdResp[any], error) {
+	tenantID, scoped := auth.TenantFromContext(ctx)
+	if !scoped {
+		tenantID = req.TenantID
+	}
+	raw, err := opaque.New()
+	if err != nil {
+		return nil, err
+	}
+	raw = tokenPrefix + raw
+	token := Token{
+		TenantID:  tenantID,
+		Name:      strings.TrimSpace(req.Name),
+		TokenHash: opaque.Hash(raw),
+	}
+	if claims, err := auth.FromContext(ctx); err == nil {
+		token.CreatedBy = claims.UserID
+	}
+	id, err := u.repo.CreateToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return response.SuccessWithData(CreateTokenResponse{ID: id, Token: raw}), nil
+}
+
+func (u *usecase) FindTokens(ctx context.Context) (*response.StdResp[any], error) {
+	tokens, err := u.repo.FindTokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]FindTokenResponse, 0, len(tokens))
+	for _, t := range tokens {
+		items = append(items, FindTokenResponse{
+			ID:        t.ID.Hex(),
+			TenantID:  t.TenantID,
+			Name:      t.Name,
+			CreatedBy: t.CreatedBy,
+			CreatedAt: t.CreatedAt,
+		})
+	}
+	return response.SuccessWithData(items), nil
+}
+
+func (u *usecase) DeleteToken(ctx context.Context, id string) (*response.StdResp[any], error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return response.TokenNotFound(), nil
+	}
+	deleted, err := u.repo.DeleteToken(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+	if deleted == 0 {
+		return response.TokenNotFound(), nil
+	}
+	return response.Success(), nil
+}
+
+// AuthenticateSCIM returns the claims a SCIM request made with raw acts
+// under: an admin of the token's tenant. It returns nil for unknown tokens and
+// for tokens whose tenant is gone or disabled.
+func (u *usecase) AuthenticateSCIM(ctx context.Context, raw string) (*auth.Claims, error) {
+	if !strings.HasPrefix(raw, tokenPrefix) {
+		return nil, nil
+	}
+	token, err := u.repo.FindTokenByHash(ctx, opaque.Hash(raw))
+	if err != nil {
+		if errors.Is(err, ErrTokenNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if token.TenantID != "" {
+		t, err := u.tenants.FindTenantById(ctx, token.TenantID)
+		if err != nil {
+			if errors.Is(err, tenant.ErrTenantNotFound) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		if t.Disabled {
+			return nil, nil
+		}
+	}
+	return &auth.Claims{TenantID: token.TenantID, Role: auth.RoleAdmin}, nil
+}
+
+func (u *usecase) ServiceProviderConfig() ServiceProviderConfig {
+	return serviceProviderConfig(u.baseURL())
+}
+
+func (u *usecase) ResourceTypes() []ResourceType {
+	return resourceTypes(u.baseURL())
+}
+
+func (u *usecase) Schemas() []Schema {
+	return schemas(u.baseURL())
+}
+
+// baseURL is where the SCIM endpoints are served.
+func (u *usecase) baseURL() string {
+	return strings.TrimSuffix(u.cfg.BaseURL, "/") + "/scim/v2"
+}
+
+func (u *usecase) location(endpoint, id string) string {
+	return u.baseURL() + "/" + endpoint + "/" + id
+}
+
+// listResponse returns the page of resources that req asks for, out of all
+// of them.
+func listResponse[T any](all []T, req ListRequest, resource func(T) (any, error)) (ListResponse, error) {
+	from := min(req.StartIndex-1, len(all))
+	to := min(from+req.Count, len(all))
+	resources := make([]any, 0, to-from)
+	for _, item := range all[from:to] {
+		r, err := resource(item)
+		if err != nil {
+			return ListResponse{}, err
+		}
+		resources = append(resources, r)
+	}
+	return ListResponse{
+		Schemas:      []string{SchemaListResponse},
+		TotalResults: len(all),
+		StartIndex:   req.StartIndex,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}, nil
+}
+
+// fromResponse turns a failed usecase response into a SCIM error.
+func fromResponse(resp *response.StdResp[any]) *Error {
+	switch resp.Code {
+	case response.DuplicatedRegistration().Code, response.DuplicatedGroup().Code:
+		return NewError(http.StatusConflict, ErrorUniqueness, resp.Message)
+	}
+	status, _ := resp.WithHTTPStatus()
+	if status == http.StatusBadRequest {
+		return NewError(status, ErrorInvalidValue, resp.Message)
+	}
+	return NewError(status, "", resp.Message)
+}
+
+func notFound(resource, id string) *Error {
+	return NewError(http.StatusNotFound, "", resource+" "+id+" not found.")
+}
@@ -0,0 +1,608 @@
+package scim_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+	"user-management/app/group"
+	"user-management/app/scim"
+	"user-management/app/tenant"
+	"user-management/app/user"
+	"user-management/auth"
+	"user-management/config"
+	"user-management/middleware"
+	"user-management/pagination"
+	"user-management/response"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const acmeTenantID = "60d5ec49f1a2c8b1f8e4e1b2"
+
+var testCfg = config.SCIMConfig{BaseURL: "https://users.example.com"}
+
+// fakeUsers makes user changes on a repository like user.Usecase, without
+// its policy checks.
+type fakeUsers struct {
+	repo      user.Repository
+	passwords map[string]string
+}
+
+func (f *fakeUsers) CreateUser(ctx context.Context, req user.CreateRequest) (*response.StdResp[any], error) {
+	if _, err := f.repo.FindUserByEmail(ctx, req.Email); err == nil {
+		return response.DuplicatedRegistration(), nil
+	}
+	tenantID, _ := auth.TenantFromContext(ctx)
+	id, err := f.repo.CreateUser(ctx, user.User{TenantID: tenantID, Name: req.Name, Email: req.Email, Role: auth.RoleUser, CreatedAt: time.Now()})
+	if err != nil {
+		return nil, err
+	}
+	f.passwords[id] = req.Password
+	return response.SuccessWithData(user.CreateResponse{Id: id}), nil
+}
+
+func (f *fakeUsers) UpdateUser(ctx context.Context, u user.User) (*response.StdResp[any], error) {
+	updated, err := f.repo.UpdateUser(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	if updated == 0 {
+		return response.UserNotFound(), nil
+	}
+	return response.Success(), nil
+}
+
+func (f *fakeUsers) ResetPassword(ctx context.Context, id, password string) (*response.StdResp[any], error) {
+	f.passwords[id] = password
+	return response.Success(), nil
+}
+
+func (f *fakeUsers) ChangeStatus(ctx context.Context, id string, req user.StatusRequest) (*response.StdResp[any], error) {
+	found, err := f.repo.FindUserById(ctx, id)
+	if err != nil {
+		return response.UserNotFound(), nil
+	}
+	oid, _ := primitive.ObjectIDFromHex(id)
+	_, err = f.repo.UpdateStatus(ctx, oid, found.Status, user.StatusChange{From: found.Status, To: req.Status, Reason: req.Reason})
+	return response.Success(), err
+}
+
+func (f *fakeUsers) DeleteUser(ctx context.Context, id string) (*response.StdResp[any], error) {
+	deleted, err := f.repo.DeleteUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if deleted == 0 {
+		return response.UserNotFound(), nil
+	}
+	return response.Success(), nil
+}
+
+// fakeGroups keeps groups and their members in memory, for one tenant.
+type fakeGroups struct {
+	groups  []group.FindGroupResponse
+	members map[string][]string
+}
+
+func (f *fakeGroups) CreateGroup(ctx context.Context, req group.CreateRequest) (*response.StdResp[any], error) {
+	if _, err := f.FindGroupByName(ctx, req.Name); err == nil {
+		return response.DuplicatedGroup(), nil
+	}
+	id := primitive.NewObjectID().Hex()
+	f.groups = append(f.groups, group.FindGroupResponse{Id: id, Name: req.Name, CreatedAt: time.Now()})
+	return response.SuccessWithData(group.CreateResponse{Id: id}), nil
+}
+
+func (f *fakeGroups) UpdateGroup(ctx context.Context, id primitive.ObjectID, req group.UpdateRequest) (*response.StdResp[any], error) {
+	i := slices.IndexFunc(f.groups, func(g group.FindGroupResponse) bool { return g.Id == id.Hex() })
+	if i < 0 {
+		return response.GroupNotFound(), nil
+	}
+	f.groups[i].Name = req.Name
+	return response.Success(), nil
+}
+
+func (f *fakeGroups) DeleteGroup(ctx context.Context, id string) (*response.StdResp[any], error) {
+	n := len(f.groups)
+	f.groups = slices.DeleteFunc(f.groups, func(g group.FindGroupResponse) bool { return g.Id == id })
+	if len(f.groups) == n {
+		return response.GroupNotFound(), nil
+	}
+	return response.Success(), nil
+}
+
+func (f *fakeGroups) AddMember(ctx context.Context, groupID, userID primitive.ObjectID) (*response.StdResp[any], error) {
+	if slices.Contains(f.members[groupID.Hex()], userID.Hex()) {
+		return response.DuplicatedMember(), nil
+	}
+	f.members[groupID.Hex()] = append(f.members[groupID.Hex()], userID.Hex())
+	return response.Success(), nil
+}
+
+func (f *fakeGroups) RemoveMember(ctx context.Context, groupID, userID primitive.ObjectID) (*response.StdResp[any], error) {
+	members := f.members[groupID.Hex()]
+	if !slices.Contains(members, userID.Hex()) {
+		return response.MemberNotFound(), nil
+	}
+	f.members[groupID.Hex()] = slices.DeleteFunc(members, func(m string) bool { return m == userID.Hex() })
+	return response.Success(), nil
+}
+
+func (f *fakeGroups) FindGroupById(ctx context.Context, id string) (group.FindGroupResponse, error) {
+	for _, g := range f.groups {
+		if g.Id == id {
+			return g, nil
+		}
+	}
+	return group.FindGroupResponse{}, group.ErrGroupNotFound
+}
+
+func (f *fakeGroups) FindGroupByName(ctx context.Context, name string) (group.FindGroupResponse, error) {
+	for _, g := range f.groups {
+		if g.Name == name {
+			return g, nil
+		}
+	}
+	return group.FindGroupResponse{}, group.ErrGroupNotFound
+}
+
+func (f *fakeGroups) FindGroups(ctx context.Context, page pagination.Request) ([]group.FindGroupResponse, int64, error) {
+	from := min(int((page.Page-1)*page.Limit), len(f.groups))
+	to := min(from+int(page.Limit), len(f.groups))
+	return f.groups[from:to], int64(len(f.groups)), nil
+}
+
+func (f *fakeGroups) FindMembers(ctx context.Context, groupID primitive.ObjectID, page pagination.Request) ([]group.MemberResponse, int64, error) {
+	ids := f.members[groupID.Hex()]
+	from := min(int((page.Page-1)*page.Limit), len(ids))
+	to := min(from+int(page.Limit), len(ids))
+	var members []group.MemberResponse
+	for _, id := range ids[from:to] {
+		members = append(members, group.MemberResponse{UserID: id})
+	}
+	return members, int64(len(ids)), nil
+}
+
+type fakeTenants map[string]tenant.FindTenantResponse
+
+func (f fakeTenants) FindTenantById(ctx context.Context, id string) (tenant.FindTenantResponse, error) {
+	t, ok := f[id]
+	if !ok {
+		return t, tenant.ErrTenantNotFound
+	}
+	return t, nil
+}
+
+type fixture struct {
+	uc interface {
+		scim.Usecase
+		middleware.SCIMAuthenticator
+	}
+	repo    scim.Repository
+	users   *fakeUsers
+	groups  *fakeGroups
+	tenants fakeTenants
+}
+
+func newFixture() *fixture {
+	f := &fixture{
+		repo:    scim.NewMemoryRepository(),
+		users:   &fakeUsers{repo: user.NewMemoryRepository(), passwords: map[string]string{}},
+		groups:  &fakeGroups{members: map[string][]string{}},
+		tenants: fakeTenants{acmeTenantID: {Id: acmeTenantID, Slug: "acme"}},
+	}
+	f.uc = scim.NewUsecase(testCfg, f.repo, f.users, f.users.repo, f.groups, f.groups, f.tenants)
+	return f
+}
+
+// scimContext is the context of a request made with a SCIM token of tenant.
+func scimContext(tenantID string) context.Context {
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{TenantID: tenantID, Role: auth.RoleAdmin})
+	return auth.WithTenant(ctx, tenantID)
+}
+
+func (f *fixture) createUser(t *testing.T, ctx context.Context, email string) scim.User {
+	t.Helper()
+	created, err := f.uc.CreateUser(ctx, scim.User{UserName: email, Name: &scim.Name{GivenName: "Test", FamilyName: "User"}})
+	require.NoError(t, err)
+	return created
+}
+
+func patch(op, path string, value any) scim.PatchRequest {
+	raw, _ := json.Marshal(value)
+	return scim.PatchRequest{Schemas: []string{scim.SchemaPatchOp}, Operations: []scim.PatchOperation{{Op: op, Path: path, Value: raw}}}
+}
+
+func requireError(t *testing.T, err error, status int, scimType string) {
+	t.Helper()
+	var scimErr *scim.Error
+	require.ErrorAs(t, err, &scimErr)
+	assert.Equal(t, status, scimErr.HTTPStatus())
+	assert.Equal(t, scimType, scimErr.ScimType)
+}
+
+func TestUsecaseCreateToken(t *testing.T) {
+	f := newFixture()
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{UserID: "admin-1", TenantID: acmeTenantID, Role: auth.RoleAdmin})
+
+	resp, err := f.uc.CreateToken(ctx, scim.CreateTokenRequest{Name: " Okta ", TenantID: "ignored"})
+	require.NoError(t, err)
+	created := resp.Data.(scim.CreateTokenResponse)
+	assert.True(t, strings.HasPrefix(created.Token, "scim_"))
+
+	resp, err = f.uc.FindTokens(ctx)
+	require.NoError(t, err)
+	tokens := resp.Data.([]scim.FindTokenResponse)
+	require.Len(t, tokens, 1)
+	assert.Equal(t, created.ID, tokens[0].ID)
+	assert.Equal(t, "Okta", tokens[0].Name)
+	assert.Equal(t, acmeTenantID, tokens[0].TenantID)
+	assert.Equal(t, "admin-1", tokens[0].CreatedBy)
+}
+
+func TestUsecaseAuthenticateSCIM(t *testing.T) {
+	f := newFixture()
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{TenantID: acmeTenantID, Role: auth.RoleAdmin})
+	resp, err := f.uc.CreateToken(ctx, scim.CreateTokenRequest{Name: "Okta"})
+	require.NoError(t, err)
+	token := resp.Data.(scim.CreateTokenResponse).Token
+
+	claims, err := f.uc.AuthenticateSCIM(context.Background(), token)
+	require.NoError(t, err)
+	require.NotNil(t, claims)
+	assert.Equal(t, acmeTenantID, claims.TenantID)
+	assert.Equal(t, auth.RoleAdmin, claims.Role)
+
+	claims, err = f.uc.AuthenticateSCIM(context.Background(), token+"x")
+	assert.NoError(t, err)
+	assert.Nil(t, claims)
+
+	// A user JWT is not a SCIM token.
+	claims, err = f.uc.AuthenticateSCIM(context.Background(), "eyJhbGciOiJIUzI1NiJ9.e30.x")
+	assert.NoError(t, err)
+	assert.Nil(t, claims)
+}
+
+func TestUsecaseAuthenticateSCIM_DisabledTenant(t *testing.T) {
+	f := newFixture()
+	f.tenants[acmeTenantID] = tenant.FindTenantResponse{Id: acmeTenantID, Disabled: true}
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{TenantID: acmeTenantID, Role: auth.RoleAdmin})
+	resp, err := f.uc.CreateToken(ctx, scim.CreateTokenRequest{Name: "Okta"})
+	require.NoError(t, err)
+
+	claims, err := f.uc.AuthenticateSCIM(context.Background(), resp.Data.(scim.CreateTokenResponse).Token)
+
+	assert.NoError(t, err)
+	assert.Nil(t, claims)
+}
+
+func TestUsecaseDeleteToken(t *testing.T) {
+	f := newFixture()
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{TenantID: acmeTenantID, Role: auth.RoleAdmin})
+	resp, err := f.uc.CreateToken(ctx, scim.CreateTokenRequest{Name: "Okta"})
+	require.NoError(t, err)
+	created := resp.Data.(scim.CreateTokenResponse)
+
+	// Admins of other tenants cannot see the token.
+	other := auth.WithClaims(context.Background(), &auth.Claims{TenantID: "60d5ec49f1a2c8b1f8e4e1b3", Role: auth.RoleAdmin})
+	resp, err = f.uc.DeleteToken(other, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, response.TokenNotFound(), resp)
+
+	resp, err = f.uc.DeleteToken(ctx, created.ID)
+	require.NoError(t, err)
+	assert.True(t, resp.IsSuccess())
+
+	claims, err := f.uc.AuthenticateSCIM(context.Background(), created.Token)
+	assert.NoError(t, err)
+	assert.Nil(t, claims)
+}
+
+func TestUsecaseCreateUser(t *testing.T) {
+	f := newFixture()
+	ctx := scimContext(acmeTenantID)
+
+	created := f.createUser(t, ctx, "alice@acme.com")
+
+	assert.Equal(t, []string{scim.SchemaUser}, created.Schemas)
+	assert.Equal(t, "alice@acme.com", created.UserName)
+	assert.Equal(t, "Test User", created.DisplayName)
+	assert.True(t, *created.Active)
+	assert.Equal(t, "https://users.example.com/scim/v2/Users/"+created.ID, created.Meta.Location)
+	assert.Equal(t, "", f.users.passwords[created.ID])
+	found, err := f.users.repo.FindUserById(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, acmeTenantID, found.TenantID)
+}
+
+func TestUsecaseCreateUser_Inactive(t *testing.T) {
+	f := newFixture()
+	ctx := scimContext(acmeTenantID)
+	active := false
+
+	created, err := f.uc.CreateUser(ctx, scim.User{UserName: "alice@acme.com", Active: &active})
+
+	require.NoError(t, err)
+	assert.False(t, *created.Active)
+	assert.Equal(t, "alice", created.DisplayName)
+}
+
+func TestUsecaseCreateUser_Invalid(t *testing.T) {
+	f := newFixture()
+	ctx := scimContext(acmeTenantID)
+
+	_, err := f.uc.CreateUser(ctx, scim.User{UserName: "alice"})
+	requireError(t, err, http.StatusBadRequest, scim.ErrorInvalidValue)
+
+	f.createUser(t, ctx, "alice@acme.com")
+	_, err = f.uc.CreateUser(ctx, scim.User{UserName: "alice@acme.com"})
+	requireError(t, err, http.StatusConflict, scim.ErrorUniqueness)
+}
+
+func TestUsecaseFindUsers_Filter(t *testing.T) {
+	f := newFixture()
+	ctx := scimContext(acmeTenantID)
+	alice := f.createUser(t, ctx, "alice@acme.com")
+	f.createUser(t, ctx, "bob@acme.com")
+
+	list, err := f.uc.FindUsers(ctx, scim.ListRequest{Filter: `userName eq "alice@acme.com"`, StartIndex: 1, Count: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 1, list.TotalResults)
+	require.Len(t, list.Resources, 1)
+	assert.Equal(t, alice.ID, list.Resources[0].(scim.User).ID)
+
+	list, err = f.uc.FindUsers(ctx, scim.ListRequest{Filter: `userName eq "carol@acme.com"`, StartIndex: 1, Count: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 0, list.TotalResults)
+	assert.Empty(t, list.Resources)
+
+	_, err = f.uc.FindUsers(ctx, scim.ListRequest{Filter: `displayName co "a"`, StartIndex: 1, Count: 10})
+	requireError(t, err, http.StatusBadRequest, scim.ErrorInvalidFilter)
+}
+
+func TestUsecaseFindUsers_Pagination(t *testing.T) {
+	f := newFixture()
+	ctx := scimContext(acmeTenantID)
+	for _, email := range []string{"a@acme.com", "b@acme.com", "c@acme.com"} {
+		f.createUser(t, ctx, email)
+	}
+
+	list, err := f.uc.FindUsers(ctx, scim.ListRequest{StartIndex: 2, Count: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 3, list.TotalResults)
+	assert.Equal(t, 2, list.StartIndex)
+	assert.Equal(t, 1, list.ItemsPerPage)
+	assert.Equal(t, "b@acme.com", list.Resources[0].(scim.User).UserName)
+
+	list, err = f.uc.FindUsers(ctx, scim.ListRequest{StartIndex: 5, Count: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 3, list.TotalResults)
+	assert.Empty(t, list.Resources)
+}
+
+func TestUsecaseFindUsers_OtherTenant(t *testing.T) {
+	f := newFixture()
+	f.createUser(t, scimContext(acmeTenantID), "alice@acme.com")
+
+	list, err := f.uc.FindUsers(scimContext(""), scim.ListRequest{StartIndex: 1, Count: 10})
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, list.TotalResults)
+}
+
+func TestUsecaseFindUser_NotFound(t *testing.T) {
+	f := newFixture()
+	ctx := scimContext(acmeTenantID)
+
+	_, err := f.uc.FindUser(ctx, "not-an-id")
+	requireError(t, err, http.StatusNotFound, "")
+
+	_, err = f.uc.FindUser(ctx, primitive.NewObjectID().Hex())
+	requireError(t, err, http.StatusNotFound, "")
+}
+
+func TestUsecaseReplaceUser(t *testing.T) {
+	f := newFixture()
+	ctx := scimContext(acmeTenantID)
+	created := f.createUser(t, ctx, "alice@acme.com")
+	active := false
+
+	replaced, err := f.uc.ReplaceUser(ctx, created.ID, scim.User{UserName: "alice.smith@acme.com", DisplayName: "Alice Smith", Password: "s3cret!", Active: &active})
+
+	require.NoError(t, err)
+	assert.Equal(t, "alice.smith@acme.com", replaced.UserName)
+	assert.Equal(t, "Alice Smith", replaced.DisplayName)
+	assert.False(t, *replaced.Active)
+	assert.Equal(t, "s3cret!", f.users.passwords[created.ID])
+}
+
+func TestUsecasePatchUser(t *testing.T) {
+	f := newFixture()
+	ctx := scimContext(acmeTenantID)
+	created := f.createUser(t, ctx, "alice@acme.com")
+
+	patched, err := f.uc.PatchUser(ctx, created.ID, patch(scim.OpReplace, "", map[string]any{"active": false, "name": map[string]string{"givenName": "Alice", "familyName": "Smith"}}))
+	require.NoError(t, err)
+	assert.False(t, *patched.Active)
+	assert.Equal(t, "Alice Smith", patched.DisplayName)
+	found, err := f.users.repo.FindUserById(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, user.StatusDisabled, found.Status)
+
+	// Azure AD sends booleans as strings.
+	patched, err = f.uc.PatchUser(ctx, created.ID, patch("Replace", "active", "True"))
+	require.NoError(t, err)
+	assert.True(t, *patched.Active)
+
+	patched, err = f.uc.PatchUser(ctx, created.ID, patch(scim.OpReplace, `emails[type eq "work"].value`, "alice.smith@acme.com"))
+	require.NoError(t, err)
+	assert.Equal(t, "alice.smith@acme.com", patched.UserName)
+}
+
+func TestUsecasePatchUser_Invalid(t *testing.T) {
+	f := newFixture()
+	ctx := scimContext(acmeTenantID)
+	created := f.createUser(t, ctx, "alice@acme.com")
+
+	_, err := f.uc.PatchUser(ctx, created.ID, patch(scim.OpRemove, "userName", nil))
+	requireError(t, err, http.StatusBadRequest, scim.ErrorMutability)
+
+	_, err = f.uc.PatchUser(ctx, created.ID, patch("move", "active", false))
+	requireError(t, err, http.StatusBadRequest, scim.ErrorInvalidSyntax)
+
+	_, err = f.uc.PatchUser(ctx, created.ID, patch(scim.OpReplace, "userName", "alice"))
+	requireError(t, err, http.StatusBadRequest, scim.ErrorInvalidValue)
+
+	_, err = f.uc.PatchUser(ctx, created.ID, patch(scim.OpRemove, "", nil))
+	requireError(t, err, http.StatusBadRequest, scim.ErrorNoTarget)
+}
+
+func TestUsecaseDeleteUser(t *testing.T) {
+	f := newFixture()
+	ctx := scimContext(acmeTenantID)
+	created := f.createUser(t, ctx, "alice@acme.com")
+
+	require.NoError(t, f.uc.DeleteUser(ctx, created.ID))
+
+	requireError(t, f.uc.DeleteUser(ctx, created.ID), http.StatusNotFound, "")
+}
+
+func TestUsecaseCreateGroup(t *testing.T) {
+	f := newFixture()
+	ctx := scimContext(acmeTenantID)
+	alice := f.createUser(t, ctx, "alice@acme.com")
+
+	created, err := f.uc.CreateGroup(ctx, scim.Group{DisplayName: "Engineering", Members: []scim.Member{{Value: alice.ID}}})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Engineering", created.DisplayName)
+	require.Len(t, created.Members, 1)
+	assert.Equal(t, alice.ID, created.Members[0].Value)
+	assert.Equal(t, "https://users.example.com/scim/v2/Users/"+alice.ID, created.Members[0].Ref)
+	assert.Equal(t, "https://users.example.com/scim/v2/Groups/"+created.ID, created.Meta.Location)
+}
+
+func TestUsecaseCreateGroup_UnknownMember(t *testing.T) {
+	f := newFixture()
+	ctx := scimContext(acmeTenantID)
+
+	_, err := f.uc.CreateGroup(ctx, scim.Group{DisplayName: "Engineering", Members: []scim.Member{{Value: primitive.NewObjectID().Hex()}}})
+
+	requireError(t, err, http.StatusBadRequest, scim.ErrorInvalidValue)
+	assert.Empty(t, f.groups.groups)
+}
+
+func TestUsecaseFindGroups(t *testing.T) {
+	f := newFixture()
+	ctx := scimContext(acmeTenantID)
+	alice := f.createUser(t, ctx, "alice@acme.com")
+	_, err := f.uc.CreateGroup(ctx, scim.Group{DisplayName: "Engineering", Members: []scim.Member{{Value: alice.ID}}})
+	require.NoError(t, err)
+	_, err = f.uc.CreateGroup(ctx, scim.Group{DisplayName: "Sales"})
+	require.NoError(t, err)
+
+	list, err := f.uc.FindGroups(ctx, scim.ListRequest{StartIndex: 1, Count: 10, ExcludeMembers: true})
+	require.NoError(t, err)
+	assert.Equal(t, 2, list.TotalResults)
+	assert.Nil(t, list.Resources[0].(scim.Group).Members)
+
+	list, err = f.uc.FindGroups(ctx, scim.ListRequest{Filter: `displayName eq "Engineering"`, StartIndex: 1, Count: 10})
+	require.NoError(t, err)
+	require.Len(t, list.Resources, 1)
+	assert.Len(t, list.Resources[0].(scim.Group).Members, 1)
+}
+
+func TestUsecaseReplaceGroup(t *testing.T) {
+	f := newFixture()
+	ctx := scimContext(acmeTenantID)
+	alice := f.createUser(t, ctx, "alice@acme.com")
+	bob := f.createUser(t, ctx, "bob@acme.com")
+	created, err := f.uc.CreateGroup(ctx, scim.Group{DisplayName: "Engineering", Members: []scim.Member{{Value: alice.ID}}})
+	require.NoError(t, err)
+
+	replaced, err := f.uc.ReplaceGroup(ctx, created.ID, scim.Group{DisplayName: "Platform", Members: []scim.Member{{Value: bob.ID}}})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Platform", replaced.DisplayName)
+	require.Len(t, replaced.Members, 1)
+	assert.Equal(t, bob.ID, replaced.Members[0].Value)
+}
+
+func TestUsecasePatchGroup(t *testing.T) {
+	f := newFixture()
+	ctx := scimContext(acmeTenantID)
+	alice := f.createUser(t, ctx, "alice@acme.com")
+	bob := f.createUser(t, ctx, "bob@acme.com")
+	created, err := f.uc.CreateGroup(ctx, scim.Group{DisplayName: "Engineering", Members: []scim.Member{{Value: alice.ID}}})
+	require.NoError(t, err)
+
+	patched, err := f.uc.PatchGroup(ctx, created.ID, patch(scim.OpAdd, "members", []scim.Member{{Value: bob.ID}}))
+	require.NoError(t, err)
+	assert.Len(t, patched.Members, 2)
+
+	patched, err = f.uc.PatchGroup(ctx, created.ID, patch(scim.OpRemove, `members[value eq "`+alice.ID+`"]`, nil))
+	require.NoError(t, err)
+	require.Len(t, patched.Members, 1)
+	assert.Equal(t, bob.ID, patched.Members[0].Value)
+
+	patched, err = f.uc.PatchGroup(ctx, created.ID, patch(scim.OpReplace, "", map[string]any{"displayName": "Platform"}))
+	require.NoError(t, err)
+	assert.Equal(t, "Platform", patched.DisplayName)
+
+	patched, err = f.uc.PatchGroup(ctx, created.ID, patch(scim.OpRemove, "members", nil))
+	require.NoError(t, err)
+	assert.Empty(t, patched.Members)
+}
+
+func TestUsecasePatchGroup_Invalid(t *testing.T) {
+	f := newFixture()
+	ctx := scimContext(acmeTenantID)
+	created, err := f.uc.CreateGroup(ctx, scim.Group{DisplayName: "Engineering"})
+	require.NoError(t, err)
+
+	_, err = f.uc.PatchGroup(ctx, created.ID, patch(scim.OpAdd, "members", []scim.Member{{Value: "nobody"}}))
+	requireError(t, err, http.StatusBadRequest, scim.ErrorInvalidValue)
+
+	_, err = f.uc.PatchGroup(ctx, created.ID, patch(scim.OpRemove, "displayName", nil))
+	requireError(t, err, http.StatusBadRequest, scim.ErrorMutability)
+
+	_, err = f.uc.PatchGroup(ctx, created.ID, scim.PatchRequest{})
+	requireError(t, err, http.StatusBadRequest, scim.ErrorInvalidValue)
+}
+
+func TestUsecaseDeleteGroup(t *testing.T) {
+	f := newFixture()
+	ctx := scimContext(acmeTenantID)
+	created, err := f.uc.CreateGroup(ctx, scim.Group{DisplayName: "Engineering"})
+	require.NoError(t, err)
+
+	require.NoError(t, f.uc.DeleteGroup(ctx, created.ID))
+
+	_, err = f.uc.FindGroup(ctx, created.ID, true)
+	requireError(t, err, http.StatusNotFound, "")
+}
+
+func TestUsecaseDiscovery(t *testing.T) {
+	f := newFixture()
+
+	spc := f.uc.ServiceProviderConfig()
+	assert.True(t, spc.Patch.Supported)
+	assert.True(t, spc.Filter.Supported)
+	assert.Equal(t, scim.MaxResults, spc.Filter.MaxResults)
+	assert.False(t, spc.Bulk.Supported)
+
+	types := f.uc.ResourceTypes()
+	require.Len(t, types, 2)
+	assert.Equal(t, "/Users", types[0].Endpoint)
+	assert.Equal(t, scim.SchemaUser, types[0].Schema)
+
+	schemas := f.uc.Schemas()
+	require.Len(t, schemas, 2)
+	assert.Equal(t, scim.SchemaGroup, schemas[1].ID)
+}
@@ -0,0 +1,254 @@
+package scim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/mail"
+	"strings"
+	"user-management/app/user"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FindUsers lists the users of the token's tenant, oldest first. The only
+// filter supported is userName eq, which finds the user with that email.
+func (u *usecase) FindUsers(ctx context.Context, req ListRequest) (ListResponse, error) {
+	email, filtered, err := parseFilter(req.Filter, "userName")
+	if err != nil {
+		return ListResponse{}, err
+	}
+	var found []user.FindUserResponse
+	if filtered {
+		usr, err := u.userRepo.FindUserByEmail(ctx, email)
+		switch {
+		case err == nil:
+			one, err := u.userRepo.FindUserById(ctx, usr.ID.Hex())
+			if err != nil {
+				return ListResponse{}, err
+			}
+			found = append(found, one)
+		case !errors.Is(err, user.ErrUserOrPasswordIsWrong):
+			return ListResponse{}, err
+		}
+	} else if found, err = u.userRepo.FindUsers(ctx, nil); err != nil {
+		return ListResponse{}, err
+	}
+	return listResponse(found, req, func(f user.FindUserResponse) (any, error) {
+		return u.toUser(f), nil
+	})
+}
+
+func (u *usecase) FindUser(ctx context.Context, id string) (User, error) {
+	found, err := u.findUser(ctx, id)
+	if err != nil {
+		return User{}, err
+	}
+	return u.toUser(found), nil
+}
+
+// CreateUser creates a user with the default role. Users created without a
+// password can only sign in through an identity provider.
+func (u *usecase) CreateUser(ctx context.Context, req User) (User, error) {
+	email, name, err := userFields(req.UserName, req.DisplayName, req.Name)
+	if err != nil {
+		return User{}, err
+	}
+	resp, err := u.users.CreateUser(ctx, user.CreateRequest{Name: name, Email: email, Password: req.Password})
+	if err != nil {
+		return User{}, err
+	}
+	if !resp.IsSuccess() {
+		return User{}, fromResponse(resp)
+	}
+	id := resp.Data.(user.CreateResponse).Id
+	if req.Active != nil && !*req.Active {
+		found, err := u.findUser(ctx, id)
+		if err != nil {
+			return User{}, err
+		}
+		if err := u.setActive(ctx, found, false); err != nil {
+			return User{}, err
+		}
+	}
+	return u.FindUser(ctx, id)
+}
+
+// ReplaceUser sets the user's name, email and, when given, password and
+// active flag.
+func (u *usecase) ReplaceUser(ctx context.Context, id string, req User) (User, error) {
+	found, err := u.findUser(ctx, id)
+	if err != nil {
+		return User{}, err
+	}
+	email, name, err := userFields(req.UserName, req.DisplayName, req.Name)
+	if err != nil {
+		return User{}, err
+	}
+	if err := u.updateUser(ctx, found, userPatch{email: &email, name: &name, password: optional(req.Password), active: req.Active}); err != nil {
+		return User{}, err
+	}
+	return u.FindUser(ctx, id)
+}
+
+// PatchUser applies the operations of req together. Attributes that are not
+// stored are ignored.
+func (u *usecase) PatchUser(ctx context.Context, id string, req PatchRequest) (User, error) {
+	found, err := u.findUser(ctx, id)
+	if err != nil {
+		return User{}, err
+	}
+	patch, err := parseUserPatch(req)
+	if err != nil {
+		return User{}, err
+	}
+	if patch.email != nil {
+		if *patch.email, _, err = userFields(*patch.email, found.Name, nil); err != nil {
+			return User{}, err
+		}
+	}
+	if err := u.updateUser(ctx, found, patch); err != nil {
+		return User{}, err
+	}
+	return u.FindUser(ctx, id)
+}
+
+func (u *usecase) DeleteUser(ctx context.Context, id string) error {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return notFound(ResourceUser, id)
+	}
+	resp, err := u.users.DeleteUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !resp.IsSuccess() {
+		return fromResponse(resp)
+	}
+	return nil
+}
+
+// updateUser makes the changes in patch to found: name and email first, then
+// the password and last whether the user is active.
+func (u *usecase) updateUser(ctx context.Context, found user.FindUserResponse, patch userPatch) error {
+	oid, err := primitive.ObjectIDFromHex(found.Id)
+	if err != nil {
+		return err
+	}
+	if name, email := patch.fullName(), patch.email; name != "" || email != nil {
+		update := user.User{ID: oid, Name: name}
+		if email != nil {
+			update.Email = *email
+		}
+		resp, err := u.users.UpdateUser(ctx, update)
+		if err != nil {
+			return err
+		}
+		if !resp.IsSuccess() {
+			return fromResponse(resp)
+		}
+	}
+	if patch.password != nil {
+		resp, err := u.users.ResetPassword(ctx, found.Id, *patch.password)
+		if err != nil {
+			return err
+		}
+		if !resp.IsSuccess() {
+			return fromResponse(resp)
+		}
+	}
+	if patch.active != nil {
+		return u.setActive(ctx, found, *patch.active)
+	}
+	return nil
+}
+
+// setActive activates found, or disables it when it is active or pending.
+// Suspended users are already inactive and stay suspended.
+func (u *usecase) setActive(ctx context.Context, found user.FindUserResponse, active bool) error {
+	status := found.Status
+	if status == "" {
+		status = user.StatusActive
+	}
+	var req user.StatusRequest
+	switch {
+	case active && status != user.StatusActive:
+		req = user.StatusRequest{Status: user.StatusActive, Reason: reasonActivated}
+	case !active && (status == user.StatusActive || status == user.StatusPending):
+		req = user.StatusRequest{Status: user.StatusDisabled, Reason: reasonDeactivated}
+	default:
+		return nil
+	}
+	resp, err := u.users.ChangeStatus(ctx, found.Id, req)
+	if err != nil {
+		return err
+	}
+	if !resp.IsSuccess() {
+		return fromResponse(resp)
+	}
+	return nil
+}
+
+func (u *usecase) findUser(ctx context.Context, id string) (user.FindUserResponse, error) {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return user.FindUserResponse{}, notFound(ResourceUser, id)
+	}
+	found, err := u.userRepo.FindUserById(ctx, id)
+	if errors.Is(err, user.ErrUserNotFound) {
+		return found, notFound(ResourceUser, id)
+	}
+	return found, err
+}
+
+func (u *usecase) toUser(f user.FindUserResponse) User {
+	active := f.Status == "" || f.Status == user.StatusActive
+	created := f.CreatedAt
+	return User{
+		Schemas:     []string{SchemaUser},
+		ID:          f.Id,
+		UserName:    f.Email,
+		Name:        &Name{Formatted: f.Name},
+		DisplayName: f.Name,
+		Emails:      []Email{{Value: f.Email, Type: "work", Primary: true}},
+		Active:      &active,
+		Meta: &Meta{
+			ResourceType: ResourceUser,
+			Created:      &created,
+			Location:     u.location("Users", f.Id),
+		},
+	}
+}
+
+// userFields checks that userName is an email and picks the user's name:
+// displayName, else the formatted name, else the given and family names, else
+// the local part of the email.
+func userFields(userName, displayName string, name *Name) (email, fullName string, err error) {
+	email = strings.TrimSpace(userName)
+	if email == "" {
+		return "", "", NewError(http.StatusBadRequest, ErrorInvalidValue, "userName is required.")
+	}
+	if _, err := mail.ParseAddress(email); err != nil {
+		return "", "", NewError(http.StatusBadRequest, ErrorInvalidValue, "userName must be an email address.")
+	}
+	candidates := []string{displayName}
+	if name != nil {
+		candidates = append(candidates, name.Formatted, joinName(name.GivenName, name.FamilyName))
+	}
+	for _, c := range candidates {
+		if c = strings.TrimSpace(c); c != "" {
+			return email, c, nil
+		}
+	}
+	local, _, _ := strings.Cut(email, "@")
+	return email, local, nil
+}
+
+func joinName(given, family string) string {
+	return strings.TrimSpace(strings.TrimSpace(given) + " " + strings.TrimSpace(family))
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
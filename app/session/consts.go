@@ -0,0 +1,6 @@
+package session
+
+const (
+	ParamID        = "id"
+	ParamSessionID = "sessionId"
+)
@@ -0,0 +1,114 @@
+package session
+
+import (
+	"context"
+	"user-management/logger"
+	"user-management/response"
+
+	echo "github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Usecase interface {
+	FindMySessions(ctx context.Context) (*response.StdResp[any], error)
+	RevokeMySession(ctx context.Context, id string) (*response.StdResp[any], error)
+	FindUserSessions(ctx context.Context, userID string) (*response.StdResp[any], error)
+	RevokeUserSession(ctx context.Context, userID, id string) (*response.StdResp[any], error)
+}
+
+type Handler interface {
+	FindMySessions(c echo.Context) error
+	RevokeMySession(c echo.Context) error
+	FindUserSessions(c echo.Context) error
+	RevokeUserSession(c echo.Context) error
+}
+
+type handler struct {
+	usecase Usecase
+}
+
+func NewHandler(u Usecase) *handler {
+	return &handler{
+		usecase: u,
+	}
+}
+
+// FindMySessions serves GET /me/sessions.
+func (h *handler) FindMySessions(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.FindMySessions(ctx)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+// RevokeMySession serves DELETE /me/sessions/:id.
+func (h *handler) RevokeMySession(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	id := c.Param(ParamID)
+	if !primitive.IsValidObjectID(id) {
+		return c.JSON(response.InvalidData(ParamID).WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.RevokeMySession(ctx, id)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+// FindUserSessions serves GET /users/:id/sessions.
+func (h *handler) FindUserSessions(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	userID := c.Param(ParamID)
+	if !primitive.IsValidObjectID(userID) {
+		return c.JSON(response.InvalidData(ParamID).WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.FindUserSessions(ctx, userID)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+// RevokeUserSession serves DELETE /users/:id/sessions/:sessionId.
+func (h *handler) RevokeUserSession(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	userID := c.Param(ParamID)
+	if !primitive.IsValidObjectID(userID) {
+		return c.JSON(response.InvalidData(ParamID).WithHTTPStatus())
+	}
+	id := c.Param(ParamSessionID)
+	if !primitive.IsValidObjectID(id) {
+		return c.JSON(response.InvalidData(ParamSessionID).WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.RevokeUserSession(ctx, userID, id)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
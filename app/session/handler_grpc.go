@@ -0,0 +1,91 @@
+package session
+
+import (
+	"context"
+	"time"
+	sessiongrpc "user-management/app/session/grpc/gen/go/session/v1"
+	"user-management/response"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type GrpcHandler struct {
+	usecase Usecase
+}
+
+func NewGrpcHandler(u Usecase) *GrpcHandler {
+	return &GrpcHandler{usecase: u}
+}
+
+func (h *GrpcHandler) ListMySessions(ctx context.Context, req *sessiongrpc.ListMySessionsRequest) (*sessiongrpc.ListSessionsResponse, error) {
+	resp, err := h.usecase.FindMySessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toGrpcSessionList(resp), nil
+}
+
+func (h *GrpcHandler) RevokeMySession(ctx context.Context, req *sessiongrpc.RevokeMySessionRequest) (*sessiongrpc.StatusResponse, error) {
+	if !primitive.IsValidObjectID(req.Id) {
+		return toGrpcStatus(response.InvalidData(ParamID)), nil
+	}
+	resp, err := h.usecase.RevokeMySession(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return toGrpcStatus(resp), nil
+}
+
+func (h *GrpcHandler) ListUserSessions(ctx context.Context, req *sessiongrpc.ListUserSessionsRequest) (*sessiongrpc.ListSessionsResponse, error) {
+	if !primitive.IsValidObjectID(req.UserId) {
+		resp := response.InvalidData("user_id")
+		return &sessiongrpc.ListSessionsResponse{Code: resp.Code, Message: resp.Message}, nil
+	}
+	resp, err := h.usecase.FindUserSessions(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return toGrpcSessionList(resp), nil
+}
+
+func (h *GrpcHandler) RevokeUserSession(ctx context.Context, req *sessiongrpc.RevokeUserSessionRequest) (*sessiongrpc.StatusResponse, error) {
+	if !primitive.IsValidObjectID(req.UserId) {
+		return toGrpcStatus(response.InvalidData("user_id")), nil
+	}
+	if !primitive.IsValidObjectID(req.Id) {
+		return toGrpcStatus(response.InvalidData(ParamID)), nil
+	}
+	resp, err := h.usecase.RevokeUserSession(ctx, req.UserId, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return toGrpcStatus(resp), nil
+}
+
+func toGrpcStatus(resp *response.StdResp[any]) *sessiongrpc.StatusResponse {
+	return &sessiongrpc.StatusResponse{
+		Code:    resp.Code,
+		Message: resp.Message,
+	}
+}
+
+func toGrpcSessionList(resp *response.StdResp[any]) *sessiongrpc.ListSessionsResponse {
+	out := &sessiongrpc.ListSessionsResponse{Code: resp.Code, Message: resp.Message}
+	if !resp.IsSuccess() {
+		return out
+	}
+	for _, s := range resp.Data.([]FindSessionResponse) {
+		out.Data = append(out.Data, &sessiongrpc.Session{
+			Id:         s.Id,
+			UserId:     s.UserID,
+			Ip:         s.IP,
+			UserAgent:  s.UserAgent,
+			Protocol:   s.Protocol,
+			CreatedAt:  s.CreatedAt.Format(time.RFC3339),
+			LastSeenAt: s.LastSeenAt.Format(time.RFC3339),
+			ExpiresAt:  s.ExpiresAt.Format(time.RFC3339),
+			Current:    s.Current,
+		})
+	}
+	return out
+}
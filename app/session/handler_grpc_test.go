@@ -0,0 +1,68 @@
+package session_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-management/app/session"
+	sessiongrpc "user-management/app/session/grpc/gen/go/session/v1"
+	"user-management/response"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGrpcHandler_ListMySessions(t *testing.T) {
+	mockUc := new(mockUsecase)
+	handler := session.NewGrpcHandler(mockUc)
+
+	lastSeen := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockUc.On("FindMySessions", mock.Anything).Return(response.SuccessWithData([]session.FindSessionResponse{
+		{Id: "s1", IP: "198.51.100.1", LastSeenAt: lastSeen, Current: true},
+	}), nil)
+
+	resp, err := handler.ListMySessions(context.Background(), &sessiongrpc.ListMySessionsRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, response.Success().Code, resp.Code)
+	assert.Len(t, resp.Data, 1)
+	assert.Equal(t, "198.51.100.1", resp.Data[0].Ip)
+	assert.Equal(t, "2025-01-01T00:00:00Z", resp.Data[0].LastSeenAt)
+	assert.True(t, resp.Data[0].Current)
+}
+
+func TestGrpcHandler_ListUserSessions_Forbidden(t *testing.T) {
+	mockUc := new(mockUsecase)
+	handler := session.NewGrpcHandler(mockUc)
+
+	userID := primitive.NewObjectID().Hex()
+	mockUc.On("FindUserSessions", mock.Anything, userID).Return(response.Forbidden(), nil)
+
+	resp, err := handler.ListUserSessions(context.Background(), &sessiongrpc.ListUserSessionsRequest{UserId: userID})
+	assert.NoError(t, err)
+	assert.Equal(t, response.Forbidden().Code, resp.Code)
+	assert.Empty(t, resp.Data)
+}
+
+func TestGrpcHandler_RevokeUserSession(t *testing.T) {
+	mockUc := new(mockUsecase)
+	handler := session.NewGrpcHandler(mockUc)
+
+	userID := primitive.NewObjectID().Hex()
+	id := primitive.NewObjectID().Hex()
+	mockUc.On("RevokeUserSession", mock.Anything, userID, id).Return(response.Success(), nil)
+
+	resp, err := handler.RevokeUserSession(context.Background(), &sessiongrpc.RevokeUserSessionRequest{UserId: userID, Id: id})
+	assert.NoError(t, err)
+	assert.Equal(t, response.Success().Code, resp.Code)
+}
+
+func TestGrpcHandler_RevokeMySession_InvalidID(t *testing.T) {
+	mockUc := new(mockUsecase)
+	handler := session.NewGrpcHandler(mockUc)
+
+	resp, err := handler.RevokeMySession(context.Background(), &sessiongrpc.RevokeMySessionRequest{Id: "nope"})
+	assert.NoError(t, err)
+	assert.Equal(t, response.InvalidData(session.ParamID).Code, resp.Code)
+	mockUc.AssertNotCalled(t, "RevokeMySession", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,127 @@
+package session_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-management/app/session"
+	"user-management/logger"
+	"user-management/response"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockUsecase struct {
+	mock.Mock
+}
+
+func (m *mockUsecase) FindMySessions(ctx context.Context) (*response.StdResp[any], error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) RevokeMySession(ctx context.Context, id string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) FindUserSessions(ctx context.Context, userID string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) RevokeUserSession(ctx context.Context, userID, id string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, userID, id)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func newTestContext(method, target string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(method, target, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	ctx := context.WithValue(c.Request().Context(), logger.LogContext, logger.NewZap())
+	c.SetRequest(req.WithContext(ctx))
+	return c, rec
+}
+
+func TestHandlerFindMySessions(t *testing.T) {
+	c, rec := newTestContext(http.MethodGet, "/me/sessions")
+
+	mockUc := new(mockUsecase)
+	mockUc.On("FindMySessions", mock.Anything).Return(response.SuccessWithData([]session.FindSessionResponse{}), nil)
+
+	err := session.NewHandler(mockUc).FindMySessions(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUc.AssertExpectations(t)
+}
+
+func TestHandlerFindMySessions_Error(t *testing.T) {
+	c, rec := newTestContext(http.MethodGet, "/me/sessions")
+
+	mockUc := new(mockUsecase)
+	mockUc.On("FindMySessions", mock.Anything).Return((*response.StdResp[any])(nil), errors.New("db down"))
+
+	err := session.NewHandler(mockUc).FindMySessions(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestHandlerRevokeMySession(t *testing.T) {
+	id := primitive.NewObjectID().Hex()
+	c, rec := newTestContext(http.MethodDelete, "/me/sessions/"+id)
+	c.SetParamNames(session.ParamID)
+	c.SetParamValues(id)
+
+	mockUc := new(mockUsecase)
+	mockUc.On("RevokeMySession", mock.Anything, id).Return(response.SessionNotFound(), nil)
+
+	err := session.NewHandler(mockUc).RevokeMySession(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	mockUc.AssertExpectations(t)
+}
+
+func TestHandlerFindUserSessions_InvalidID(t *testing.T) {
+	c, rec := newTestContext(http.MethodGet, "/users/nope/sessions")
+	c.SetParamNames(session.ParamID)
+	c.SetParamValues("nope")
+
+	err := session.NewHandler(new(mockUsecase)).FindUserSessions(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestHandlerRevokeUserSession(t *testing.T) {
+	userID := primitive.NewObjectID().Hex()
+	id := primitive.NewObjectID().Hex()
+	c, rec := newTestContext(http.MethodDelete, "/users/"+userID+"/sessions/"+id)
+	c.SetParamNames(session.ParamID, session.ParamSessionID)
+	c.SetParamValues(userID, id)
+
+	mockUc := new(mockUsecase)
+	mockUc.On("RevokeUserSession", mock.Anything, userID, id).Return(response.Success(), nil)
+
+	err := session.NewHandler(mockUc).RevokeUserSession(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUc.AssertExpectations(t)
+}
+
+func TestHandlerRevokeUserSession_InvalidSessionID(t *testing.T) {
+	userID := primitive.NewObjectID().Hex()
+	c, rec := newTestContext(http.MethodDelete, "/users/"+userID+"/sessions/nope")
+	c.SetParamNames(session.ParamID, session.ParamSessionID)
+	c.SetParamValues(userID, "nope")
+
+	err := session.NewHandler(new(mockUsecase)).RevokeUserSession(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), response.InvalidData(session.ParamSessionID).Message)
+}
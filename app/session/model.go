@@ -0,0 +1,35 @@
+package session
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Session is started by a successful login and lasts as long as the token
+// issued with it, unless it is revoked first.
+type Session struct {
+	ID         primitive.ObjectID `bson:"_id" json:"id"`
+	TenantID   string             `bson:"tenant_id,omitempty" json:"tenant_id,omitempty"`
+	UserID     string             `bson:"user_id" json:"user_id"`
+	IP         string             `bson:"ip,omitempty" json:"ip,omitempty"`
+	UserAgent  string             `bson:"user_agent,omitempty" json:"user_agent,omitempty"`
+	Protocol   string             `bson:"protocol,omitempty" json:"protocol,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+	LastSeenAt time.Time          `bson:"last_seen_at" json:"last_seen_at"`
+	ExpiresAt  time.Time          `bson:"expires_at" json:"expires_at"`
+}
+
+// FindSessionResponse describes a session. Current marks the one the caller
+// is using.
+type FindSessionResponse struct {
+	Id         string    `json:"id"`
+	UserID     string    `json:"user_id"`
+	IP         string    `json:"ip,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	Protocol   string    `json:"protocol,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
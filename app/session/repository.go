@@ -0,0 +1,73 @@
+package session
+
+import (
+	"context"
+	"time"
+	"user-management/config"
+	"user-management/storage"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type repository struct {
+	mc  storage.DatabaseConn
+	cfg config.MongoConfig
+}
+
+func NewRepository(mc storage.DatabaseConn, cfg config.MongoConfig) *repository {
+	return &repository{
+		mc:  mc,
+		cfg: cfg,
+	}
+}
+
+func (r *repository) CreateSession(ctx context.Context, session Session) error {
+	_, err := r.mc.Collection(r.cfg.SessionCollection).InsertOne(ctx, session)
+	return err
+}
+
+// FindSessionsByUser lists the user's sessions that have not expired at now,
+// most recently seen first.
+func (r *repository) FindSessionsByUser(ctx context.Context, userID string, now time.Time) ([]Session, error) {
+	filter := storage.TenantFilter(ctx, bson.M{"user_id": userID, "expires_at": bson.M{"$gt": now}})
+	opts := options.Find().SetSort(bson.D{bson.E{Key: "last_seen_at", Value: -1}})
+	cursor, err := r.mc.Collection(r.cfg.SessionCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	var sessions []Session
+	err = cursor.All(ctx, &sessions)
+	return sessions, err
+}
+
+func (r *repository) DeleteSession(ctx context.Context, userID string, id primitive.ObjectID) (int64, error) {
+	filter := storage.TenantFilter(ctx, bson.M{"_id": id, "user_id": userID})
+	result, err := r.mc.Collection(r.cfg.SessionCollection).DeleteOne(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// DeleteSessionsByUser ends every session of the user, expired or not.
+func (r *repository) DeleteSessionsByUser(ctx context.Context, userID string) (int64, error) {
+	result, err := r.mc.Collection(r.cfg.SessionCollection).DeleteMany(ctx, storage.TenantFilter(ctx, bson.M{"user_id": userID}))
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// TouchSession sets the last-seen time of a session that has not expired at
+// now and reports whether there was one. It is not tenant scoped: the id comes
+// from a verified token.
+func (r *repository) TouchSession(ctx context.Context, id primitive.ObjectID, now time.Time) (int64, error) {
+	filter := bson.M{"_id": id, "expires_at": bson.M{"$gt": now}}
+	result, err := r.mc.Collection(r.cfg.SessionCollection).UpdateOne(ctx, filter, bson.M{"$set": bson.M{"last_seen_at": now}})
+	if err != nil {
+		return 0, err
+	}
+	return result.MatchedCount, nil
+}
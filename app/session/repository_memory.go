@@ -0,0 +1,86 @@
+package session
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+	"user-management/auth"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryRepository keeps sessions in process memory, for when the service
+// runs without a database.
+type memoryRepository struct {
+	mu       sync.Mutex
+	sessions map[primitive.ObjectID]Session
+}
+
+func NewMemoryRepository() *memoryRepository {
+	return &memoryRepository{sessions: map[primitive.ObjectID]Session{}}
+}
+
+func (r *memoryRepository) CreateSession(ctx context.Context, session Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions[session.ID] = session
+	return nil
+}
+
+func (r *memoryRepository) FindSessionsByUser(ctx context.Context, userID string, now time.Time) ([]Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var sessions []Session
+	for _, s := range r.sessions {
+		if s.UserID == userID && s.ExpiresAt.After(now) && inTenant(ctx, s) {
+			sessions = append(sessions, s)
+		}
+	}
+	slices.SortFunc(sessions, func(a, b Session) int {
+		return b.LastSeenAt.Compare(a.LastSeenAt)
+	})
+	return sessions, nil
+}
+
+func (r *memoryRepository) DeleteSession(ctx context.Context, userID string, id primitive.ObjectID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.sessions[id]
+	if !ok || s.UserID != userID || !inTenant(ctx, s) {
+		return 0, nil
+	}
+	delete(r.sessions, id)
+	return 1, nil
+}
+
+func (r *memoryRepository) DeleteSessionsByUser(ctx context.Context, userID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deleted int64
+	for id, s := range r.sessions {
+		if s.UserID == userID && inTenant(ctx, s) {
+			delete(r.sessions, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (r *memoryRepository) TouchSession(ctx context.Context, id primitive.ObjectID, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.sessions[id]
+	if !ok || !s.ExpiresAt.After(now) {
+		return 0, nil
+	}
+	s.LastSeenAt = now
+	r.sessions[id] = s
+	return 1, nil
+}
+
+// inTenant mirrors storage.TenantFilter for a single session.
+func inTenant(ctx context.Context, s Session) bool {
+	tenantID, scoped := auth.TenantFromContext(ctx)
+	return !scoped || s.TenantID == tenantID
+}
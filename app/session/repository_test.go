@@ -0,0 +1,119 @@
+package session_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-management/app/session"
+	"user-management/auth"
+	"user-management/config"
+	"user-management/storage"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func newTestRepository(mt *mtest.T) session.Repository {
+	dbConn := storage.NewMongoConn(mt.Client, mt.Client.Database("testdb"))
+	return session.NewRepository(dbConn, config.MongoConfig{
+		Database:          "testdb",
+		SessionCollection: "sessions",
+	})
+}
+
+func TestRepository_CreateSession(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("success", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		err := repo.CreateSession(context.Background(), session.Session{ID: primitive.NewObjectID(), UserID: "u1", ExpiresAt: time.Now()})
+
+		assert.NoError(t, err)
+		doc := mt.GetStartedEvent().Command.Lookup("documents").Array().Index(0).Value().Document()
+		assert.Equal(t, "u1", doc.Lookup("user_id").StringValue())
+		assert.Equal(t, bson.TypeDateTime, doc.Lookup("expires_at").Type)
+	})
+}
+
+func TestRepository_FindSessionsByUser(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("active only, last seen first and tenant scoped", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		oid := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.sessions", mtest.FirstBatch, bson.D{
+			bson.E{Key: "_id", Value: oid},
+			bson.E{Key: "tenant_id", Value: "t1"},
+			bson.E{Key: "user_id", Value: "u1"},
+			bson.E{Key: "ip", Value: "198.51.100.1"},
+		}))
+
+		ctx := auth.WithTenant(context.Background(), "t1")
+		sessions, err := repo.FindSessionsByUser(ctx, "u1", time.Now())
+
+		assert.NoError(t, err)
+		assert.Len(t, sessions, 1)
+		assert.Equal(t, oid, sessions[0].ID)
+		assert.Equal(t, "198.51.100.1", sessions[0].IP)
+		find := mt.GetStartedEvent().Command
+		filter := find.Lookup("filter").Document()
+		assert.Equal(t, "t1", filter.Lookup("tenant_id").StringValue())
+		assert.Equal(t, "u1", filter.Lookup("user_id").StringValue())
+		assert.Equal(t, bson.TypeDateTime, filter.Lookup("expires_at", "$gt").Type)
+		assert.Equal(t, int32(-1), find.Lookup("sort").Document().Lookup("last_seen_at").Int32())
+	})
+}
+
+func TestRepository_DeleteSession(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("tenant scoped", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 1}})
+
+		deleted, err := repo.DeleteSession(auth.WithTenant(context.Background(), "t1"), "u1", primitive.NewObjectID())
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+		filter := mt.GetStartedEvent().Command.Lookup("deletes").Array().Index(0).Value().Document().Lookup("q").Document()
+		assert.Equal(t, "t1", filter.Lookup("tenant_id").StringValue())
+		assert.Equal(t, "u1", filter.Lookup("user_id").StringValue())
+	})
+}
+
+func TestRepository_TouchSession(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("not found", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 0}, {Key: "nModified", Value: 0}})
+
+		matched, err := repo.TouchSession(context.Background(), primitive.NewObjectID(), time.Now())
+
+		assert.NoError(t, err)
+		assert.Zero(t, matched)
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		assert.Equal(t, bson.TypeDateTime, update.Lookup("u", "$set", "last_seen_at").Type)
+	})
+}
+
+func TestRepository_DeleteSessionsByUser(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("tenant scoped", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 2}})
+
+		deleted, err := repo.DeleteSessionsByUser(auth.WithTenant(context.Background(), "t1"), "u1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+		filter := mt.GetStartedEvent().Command.Lookup("deletes").Array().Index(0).Value().Document().Lookup("q").Document()
+		assert.Equal(t, "t1", filter.Lookup("tenant_id").StringValue())
+		assert.Equal(t, "u1", filter.Lookup("user_id").StringValue())
+	})
+}
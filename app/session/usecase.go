@@ -0,0 +1,194 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"time"
+	"user-management/auth"
+	"user-management/clientinfo"
+	"user-management/config"
+	"user-management/response"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Repository interface {
+	CreateSession(ctx context.Context, session Session) error
+	FindSessionsByUser(ctx context.Context, userID string, now time.Time) ([]Session, error)
+	DeleteSession(ctx context.Context, userID string, id primitive.ObjectID) (int64, error)
+	TouchSession(ctx context.Context, id primitive.ObjectID, now time.Time) (int64, error)
+	DeleteSessionsByUser(ctx context.Context, userID string) (int64, error)
+}
+
+type usecase struct {
+	cfg  config.SessionConfig
+	repo Repository
+
+	// touched holds when each session's last-seen time was last written.
+	mu      sync.Mutex
+	touched map[string]time.Time
+	swept   time.Time
+}
+
+func NewUsecase(cfg config.SessionConfig, r Repository) *usecase {
+	return &usecase{
+		cfg:     cfg,
+		repo:    r,
+		touched: map[string]time.Time{},
+	}
+}
+
+// StartSession records a session for a user who just logged in, with the
+// caller's details, ending at expiresAt. It returns the session id.
+func (u *usecase) StartSession(ctx context.Context, userID, tenantID string, expiresAt time.Time) (string, error) {
+	client := clientinfo.FromContext(ctx)
+	now := time.Now()
+	session := Session{
+		ID:         primitive.NewObjectID(),
+		TenantID:   tenantID,
+		UserID:     userID,
+		IP:         client.IP,
+		UserAgent:  client.UserAgent,
+		Protocol:   client.Protocol,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  expiresAt,
+	}
+	if err := u.repo.CreateSession(ctx, session); err != nil {
+		return "", err
+	}
+	return session.ID.Hex(), nil
+}
+
+// TouchSession reports whether the session is still active and updates its
+// last-seen time, at most once per cfg.TouchInterval.
+// EndSessions removes every session of userID, for when the user is deleted.
+func (u *usecase) EndSessions(ctx context.Context, userID string) (int64, error) {
+	return u.repo.DeleteSessionsByUser(ctx, userID)
+}
+
+func (u *usecase) TouchSession(ctx context.Context, id string) (bool, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, nil
+	}
+	now := time.Now()
+	u.mu.Lock()
+	last, ok := u.touched[id]
+	u.mu.Unlock()
+	if ok && now.Sub(last) < u.cfg.TouchInterval {
+		return true, nil
+	}
+
+	matched, err := u.repo.TouchSession(ctx, oid, now)
+	if err != nil {
+		return false, err
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if matched == 0 {
+		delete(u.touched, id)
+		return false, nil
+	}
+	u.touched[id] = now
+	u.sweep(now)
+	return true, nil
+}
+
+// sweep forgets sessions not touched within the interval, so the map does not
+// grow with every session ever seen. u.mu must be held.
+func (u *usecase) sweep(now time.Time) {
+	if now.Sub(u.swept) < u.cfg.TouchInterval {
+		return
+	}
+	for id, last := range u.touched {
+		if now.Sub(last) >= u.cfg.TouchInterval {
+			delete(u.touched, id)
+		}
+	}
+	u.swept = now
+}
+
+func (u *usecase) FindMySessions(ctx context.Context) (*response.StdResp[any], error) {
+	claims, err := auth.FromContext(ctx)
+	if err != nil || claims.UserID == "" {
+		return response.Unauthorized(), nil
+	}
+	return u.findSessions(ctx, claims, claims.UserID)
+}
+
+func (u *usecase) RevokeMySession(ctx context.Context, id string) (*response.StdResp[any], error) {
+	claims, err := auth.FromContext(ctx)
+	if err != nil || claims.UserID == "" {
+		return response.Unauthorized(), nil
+	}
+	return u.revokeSession(ctx, claims.UserID, id)
+}
+
+// FindUserSessions lists another user's sessions for admins, or the caller's
+// own for anyone.
+func (u *usecase) FindUserSessions(ctx context.Context, userID string) (*response.StdResp[any], error) {
+	claims, err := auth.FromContext(ctx)
+	if err != nil {
+		return response.Unauthorized(), nil
+	}
+	if !claims.IsAdmin() && claims.UserID != userID {
+		return response.Forbidden(), nil
+	}
+	return u.findSessions(ctx, claims, userID)
+}
+
+func (u *usecase) RevokeUserSession(ctx context.Context, userID, id string) (*response.StdResp[any], error) {
+	claims, err := auth.FromContext(ctx)
+	if err != nil {
+		return response.Unauthorized(), nil
+	}
+	if !claims.IsAdmin() && claims.UserID != userID {
+		return response.Forbidden(), nil
+	}
+	return u.revokeSession(ctx, userID, id)
+}
+
+func (u *usecase) findSessions(ctx context.Context, claims *auth.Claims, userID string) (*response.StdResp[any], error) {
+	sessions, err := u.repo.FindSessionsByUser(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	items := make([]FindSessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		items = append(items, toFindSessionResponse(s, claims.SessionID))
+	}
+	return response.SuccessWithData(items), nil
+}
+
+func (u *usecase) revokeSession(ctx context.Context, userID, id string) (*response.StdResp[any], error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return response.SessionNotFound(), nil
+	}
+	deleted, err := u.repo.DeleteSession(ctx, userID, oid)
+	if err != nil {
+		return nil, err
+	}
+	if deleted == 0 {
+		return response.SessionNotFound(), nil
+	}
+	u.mu.Lock()
+	delete(u.touched, id)
+	u.mu.Unlock()
+	return response.Success(), nil
+}
+
+func toFindSessionResponse(s Session, currentID string) FindSessionResponse {
+	return FindSessionResponse{
+		Id:         s.ID.Hex(),
+		UserID:     s.UserID,
+		IP:         s.IP,
+		UserAgent:  s.UserAgent,
+		Protocol:   s.Protocol,
+		CreatedAt:  s.CreatedAt,
+		LastSeenAt: s.LastSeenAt,
+		ExpiresAt:  s.ExpiresAt,
+		Current:    s.ID.Hex() == currentID,
+	}
+}
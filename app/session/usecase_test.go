@@ -0,0 +1,244 @@
+package session_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-management/app/session"
+	"user-management/auth"
+	"user-management/clientinfo"
+	"user-management/config"
+	"user-management/response"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockRepo struct {
+	mock.Mock
+}
+
+func (m *mockRepo) CreateSession(ctx context.Context, s session.Session) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *mockRepo) FindSessionsByUser(ctx context.Context, userID string, now time.Time) ([]session.Session, error) {
+	args := m.Called(ctx, userID, now)
+	return args.Get(0).([]session.Session), args.Error(1)
+}
+
+func (m *mockRepo) DeleteSession(ctx context.Context, userID string, id primitive.ObjectID) (int64, error) {
+	args := m.Called(ctx, userID, id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockRepo) TouchSession(ctx context.Context, id primitive.ObjectID, now time.Time) (int64, error) {
+	args := m.Called(ctx, id, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockRepo) DeleteSessionsByUser(ctx context.Context, userID string) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+var testConfig = config.SessionConfig{TouchInterval: time.Hour}
+
+func claimsContext(userID, role, sessionID string) context.Context {
+	return auth.WithClaims(context.Background(), &auth.Claims{UserID: userID, TenantID: "t1", Role: role, SessionID: sessionID})
+}
+
+func TestUsecaseStartSession(t *testing.T) {
+	repo := session.NewMemoryRepository()
+	uc := session.NewUsecase(testConfig, repo)
+	ctx := clientinfo.WithInfo(context.Background(), clientinfo.Info{IP: "198.51.100.1", UserAgent: "curl/8", Protocol: clientinfo.ProtocolREST})
+	expiresAt := time.Now().Add(time.Hour)
+
+	id, err := uc.StartSession(ctx, "u1", "t1", expiresAt)
+	require.NoError(t, err)
+
+	resp, err := uc.FindMySessions(claimsContext("u1", auth.RoleUser, id))
+	require.NoError(t, err)
+	sessions := resp.Data.([]session.FindSessionResponse)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, id, sessions[0].Id)
+	assert.Equal(t, "198.51.100.1", sessions[0].IP)
+	assert.Equal(t, "curl/8", sessions[0].UserAgent)
+	assert.Equal(t, clientinfo.ProtocolREST, sessions[0].Protocol)
+	assert.True(t, sessions[0].ExpiresAt.Equal(expiresAt))
+	assert.True(t, sessions[0].Current)
+}
+
+func TestUsecaseTouchSession_Throttled(t *testing.T) {
+	repo := new(mockRepo)
+	uc := session.NewUsecase(testConfig, repo)
+	id := primitive.NewObjectID()
+	repo.On("TouchSession", mock.Anything, id, mock.Anything).Return(int64(1), nil).Once()
+
+	for range 3 {
+		active, err := uc.TouchSession(context.Background(), id.Hex())
+		assert.NoError(t, err)
+		assert.True(t, active)
+	}
+	repo.AssertExpectations(t)
+}
+
+func TestUsecaseTouchSession_NoInterval(t *testing.T) {
+	repo := new(mockRepo)
+	uc := session.NewUsecase(config.SessionConfig{}, repo)
+	id := primitive.NewObjectID()
+	repo.On("TouchSession", mock.Anything, id, mock.Anything).Return(int64(1), nil).Times(2)
+
+	for range 2 {
+		_, err := uc.TouchSession(context.Background(), id.Hex())
+		assert.NoError(t, err)
+	}
+	repo.AssertExpectations(t)
+}
+
+func TestUsecaseTouchSession_Inactive(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		matched int64
+	}{
+		{"ended", primitive.NewObjectID().Hex(), 0},
+		{"malformed id", "nope", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mockRepo)
+			repo.On("TouchSession", mock.Anything, mock.Anything, mock.Anything).Return(tt.matched, nil)
+
+			active, err := session.NewUsecase(testConfig, repo).TouchSession(context.Background(), tt.id)
+
+			assert.NoError(t, err)
+			assert.False(t, active)
+		})
+	}
+}
+
+func TestUsecaseTouchSession_Error(t *testing.T) {
+	repo := new(mockRepo)
+	repo.On("TouchSession", mock.Anything, mock.Anything, mock.Anything).Return(int64(0), errors.New("db down"))
+
+	_, err := session.NewUsecase(testConfig, repo).TouchSession(context.Background(), primitive.NewObjectID().Hex())
+
+	assert.Error(t, err)
+}
+
+func TestUsecaseRevokeMySession_EndsSession_MemoryRepository(t *testing.T) {
+	uc := session.NewUsecase(testConfig, session.NewMemoryRepository())
+	id, err := uc.StartSession(context.Background(), "u1", "t1", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	active, err := uc.TouchSession(context.Background(), id)
+	require.NoError(t, err)
+	require.True(t, active)
+
+	resp, err := uc.RevokeMySession(claimsContext("u1", auth.RoleUser, ""), id)
+	require.NoError(t, err)
+	assert.Equal(t, response.Success(), resp)
+
+	active, err = uc.TouchSession(context.Background(), id)
+	assert.NoError(t, err)
+	assert.False(t, active, "a revoked session must not stay active until the touch interval passes")
+
+	resp, err = uc.RevokeMySession(claimsContext("u1", auth.RoleUser, ""), id)
+	assert.NoError(t, err)
+	assert.Equal(t, response.SessionNotFound(), resp)
+}
+
+func TestUsecaseRevokeMySession_OtherUsersSession(t *testing.T) {
+	uc := session.NewUsecase(testConfig, session.NewMemoryRepository())
+	id, err := uc.StartSession(context.Background(), "u2", "t1", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	resp, err := uc.RevokeMySession(claimsContext("u1", auth.RoleUser, ""), id)
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.SessionNotFound(), resp)
+}
+
+func TestUsecaseFindUserSessions(t *testing.T) {
+	sessions := []session.Session{{ID: primitive.NewObjectID(), UserID: "u2"}}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"admin", claimsContext("u1", auth.RoleAdmin, ""), response.Success().Code},
+		{"self", claimsContext("u2", auth.RoleUser, ""), response.Success().Code},
+		{"other user", claimsContext("u1", auth.RoleUser, ""), response.Forbidden().Code},
+		{"anonymous", context.Background(), response.Unauthorized().Code},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mockRepo)
+			repo.On("FindSessionsByUser", mock.Anything, "u2", mock.Anything).Return(sessions, nil)
+
+			resp, err := session.NewUsecase(testConfig, repo).FindUserSessions(tt.ctx, "u2")
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, resp.Code)
+		})
+	}
+}
+
+func TestUsecaseRevokeUserSession(t *testing.T) {
+	id := primitive.NewObjectID()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want *response.StdResp[any]
+	}{
+		{"admin", claimsContext("u1", auth.RoleAdmin, ""), response.Success()},
+		{"self", claimsContext("u2", auth.RoleUser, ""), response.Success()},
+		{"other user", claimsContext("u1", auth.RoleUser, ""), response.Forbidden()},
+		{"anonymous", context.Background(), response.Unauthorized()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mockRepo)
+			repo.On("DeleteSession", mock.Anything, "u2", id).Return(int64(1), nil)
+
+			resp, err := session.NewUsecase(testConfig, repo).RevokeUserSession(tt.ctx, "u2", id.Hex())
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, resp)
+		})
+	}
+}
+
+func TestUsecaseFindMySessions_Unauthorized(t *testing.T) {
+	resp, err := session.NewUsecase(testConfig, new(mockRepo)).FindMySessions(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.Unauthorized(), resp)
+}
+
+func TestMemoryRepository_ExpiredAndTenant(t *testing.T) {
+	repo := session.NewMemoryRepository()
+	now := time.Now()
+	active := session.Session{ID: primitive.NewObjectID(), TenantID: "t1", UserID: "u1", ExpiresAt: now.Add(time.Hour)}
+	expired := session.Session{ID: primitive.NewObjectID(), TenantID: "t1", UserID: "u1", ExpiresAt: now.Add(-time.Minute)}
+	require.NoError(t, repo.CreateSession(context.Background(), active))
+	require.NoError(t, repo.CreateSession(context.Background(), expired))
+
+	sessions, err := repo.FindSessionsByUser(auth.WithTenant(context.Background(), "t1"), "u1", now)
+	assert.NoError(t, err)
+	assert.Equal(t, []session.Session{active}, sessions)
+
+	sessions, err = repo.FindSessionsByUser(auth.WithTenant(context.Background(), "t2"), "u1", now)
+	assert.NoError(t, err)
+	assert.Empty(t, sessions)
+
+	matched, err := repo.TouchSession(context.Background(), expired.ID, now)
+	assert.NoError(t, err)
+	assert.Zero(t, matched)
+}
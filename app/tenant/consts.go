@@ -0,0 +1,12 @@
+package tenant
+
+import "errors"
+
+const (
+	ParamID = "id"
+)
+
+var (
+	ErrSlugAlreadyExists = errors.New("Tenant slug already exists")
+	ErrTenantNotFound    = errors.New("Tenant not found")
+)
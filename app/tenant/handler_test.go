@@ -0,0 +1,119 @@
+package tenant_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-management/app/tenant"
+	"user-management/logger"
+	"user-management/response"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockUsecase struct {
+	mock.Mock
+}
+
+func (m *mockUsecase) CreateTenant(ctx context.Context, req tenant.CreateRequest) (*response.StdResp[any], error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) FindTenants(ctx context.Context) (*response.StdResp[any], error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) FindTenantById(ctx context.Context, id string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) UpdateTenant(ctx context.Context, id primitive.ObjectID, req tenant.UpdateRequest) (*response.StdResp[any], error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) DeleteTenant(ctx context.Context, id string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func newTestContext(method, target string, body any) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	var reader *bytes.Reader
+	if body != nil {
+		b, _ := json.Marshal(body)
+		reader = bytes.NewReader(b)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	ctx := context.WithValue(c.Request().Context(), logger.LogContext, logger.NewZap())
+	c.SetRequest(req.WithContext(ctx))
+	return c, rec
+}
+
+func TestHandlerCreateTenant(t *testing.T) {
+	reqBody := tenant.CreateRequest{Name: "Acme", Slug: "acme"}
+	c, rec := newTestContext(http.MethodPost, "/tenants", reqBody)
+
+	mockUc := new(mockUsecase)
+	mockUc.On("CreateTenant", mock.Anything, reqBody).Return(response.SuccessWithData(tenant.CreateResponse{Id: "abc123"}), nil)
+
+	err := tenant.NewHandler(mockUc).CreateTenant(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "abc123")
+}
+
+func TestHandlerCreateTenant_InvalidSlug(t *testing.T) {
+	c, rec := newTestContext(http.MethodPost, "/tenants", tenant.CreateRequest{Name: "Acme", Slug: "Not A Slug"})
+
+	mockUc := new(mockUsecase)
+
+	err := tenant.NewHandler(mockUc).CreateTenant(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), response.InvalidData("slug").Message)
+	mockUc.AssertNotCalled(t, "CreateTenant", mock.Anything, mock.Anything)
+}
+
+func TestHandlerFindTenantById_NotFound(t *testing.T) {
+	id := primitive.NewObjectID().Hex()
+	c, rec := newTestContext(http.MethodGet, "/tenants/"+id, nil)
+	c.SetParamNames(tenant.ParamID)
+	c.SetParamValues(id)
+
+	mockUc := new(mockUsecase)
+	mockUc.On("FindTenantById", mock.Anything, id).Return(response.TenantNotFound(), nil)
+
+	err := tenant.NewHandler(mockUc).FindTenantById(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHandlerUpdateTenant(t *testing.T) {
+	id := primitive.NewObjectID()
+	reqBody := tenant.UpdateRequest{Name: "Renamed"}
+	c, rec := newTestContext(http.MethodPut, "/tenants/"+id.Hex(), reqBody)
+	c.SetParamNames(tenant.ParamID)
+	c.SetParamValues(id.Hex())
+
+	mockUc := new(mockUsecase)
+	mockUc.On("UpdateTenant", mock.Anything, id, reqBody).Return(response.Success(), nil)
+
+	err := tenant.NewHandler(mockUc).UpdateTenant(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
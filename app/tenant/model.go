@@ -0,0 +1,37 @@
+package tenant
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Tenant struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name      string             `bson:"name" json:"name"`
+	Slug      string             `bson:"slug" json:"slug"`
+	Disabled  bool               `bson:"disabled" json:"disabled"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+type CreateRequest struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+type CreateResponse struct {
+	Id string `json:"id"`
+}
+
+type UpdateRequest struct {
+	Name     string `json:"name"`
+	Disabled *bool  `json:"disabled"`
+}
+
+type FindTenantResponse struct {
+	Id        string    `bson:"_id" json:"id"`
+	Name      string    `bson:"name" json:"name"`
+	Slug      string    `bson:"slug" json:"slug"`
+	Disabled  bool      `bson:"disabled" json:"disabled"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,102 @@
+package tenant
+
+import (
+	"context"
+	"time"
+	"user-management/config"
+	"user-management/storage"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type repository struct {
+	mc  storage.DatabaseConn
+	cfg config.MongoConfig
+}
+
+func NewRepository(mc storage.DatabaseConn, cfg config.MongoConfig) *repository {
+	return &repository{
+		mc:  mc,
+		cfg: cfg,
+	}
+}
+
+func (r *repository) CreateTenant(ctx context.Context, tenant Tenant) (string, error) {
+	tenant.CreatedAt = time.Now()
+	ior, err := r.mc.Collection(r.cfg.TenantCollection).InsertOne(ctx, tenant)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return "", ErrSlugAlreadyExists
+		}
+		return "", err
+	}
+	return ior.InsertedID.(primitive.ObjectID).Hex(), err
+}
+
+func (r *repository) FindTenantById(ctx context.Context, id string) (FindTenantResponse, error) {
+	var tenant FindTenantResponse
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return tenant, err
+	}
+	err = r.mc.Collection(r.cfg.TenantCollection).FindOne(ctx, bson.M{"_id": oid}).Decode(&tenant)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return tenant, ErrTenantNotFound
+		}
+		return tenant, err
+	}
+	return tenant, err
+}
+
+func (r *repository) FindTenantBySlug(ctx context.Context, slug string) (FindTenantResponse, error) {
+	var tenant FindTenantResponse
+	err := r.mc.Collection(r.cfg.TenantCollection).FindOne(ctx, bson.M{"slug": slug}).Decode(&tenant)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return tenant, ErrTenantNotFound
+		}
+		return tenant, err
+	}
+	return tenant, err
+}
+
+func (r *repository) FindTenants(ctx context.Context) ([]FindTenantResponse, error) {
+	sort := bson.D{bson.E{Key: "created_at", Value: 1}}
+	opts := options.Find().SetSort(sort)
+	cursor, err := r.mc.Collection(r.cfg.TenantCollection).Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var tenants []FindTenantResponse
+	err = cursor.All(ctx, &tenants)
+	return tenants, err
+}
+
+func (r *repository) UpdateTenant(ctx context.Context, id primitive.ObjectID, req UpdateRequest) (int64, error) {
+	updateFields := bson.M{}
+	if req.Name != "" {
+		updateFields["name"] = req.Name
+	}
+	if req.Disabled != nil {
+		updateFields["disabled"] = *req.Disabled
+	}
+	update := bson.M{"$set": updateFields}
+	result, err := r.mc.Collection(r.cfg.TenantCollection).UpdateByID(ctx, id, update)
+	if err != nil {
+		return 0, err
+	}
+	return result.MatchedCount, nil
+}
+
+func (r *repository) DeleteTenant(ctx context.Context, id string) (int64, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return 0, err
+	}
+	result, err := r.mc.Collection(r.cfg.TenantCollection).DeleteOne(ctx, bson.M{"_id": oid})
+	return result.DeletedCount, err
+}
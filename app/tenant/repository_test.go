@@ -0,0 +1,95 @@
+package tenant_test
+
+import (
+	"context"
+	"testing"
+	"user-management/app/tenant"
+	"user-management/config"
+	"user-management/storage"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func newTestRepository(mt *mtest.T) tenant.Repository {
+	dbConn := storage.NewMongoConn(mt.Client, mt.Client.Database("testdb"))
+	return tenant.NewRepository(dbConn, config.MongoConfig{
+		Database:         "testdb",
+		TenantCollection: "tenants",
+	})
+}
+
+func TestRepository_CreateTenant(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("success", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		id, err := repo.CreateTenant(context.Background(), tenant.Tenant{ID: primitive.NewObjectID(), Name: "Acme", Slug: "acme"})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, id)
+	})
+
+	mt.Run("duplicate slug", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+			Index:   0,
+			Code:    11000,
+			Message: "duplicate key error",
+		}))
+
+		_, err := repo.CreateTenant(context.Background(), tenant.Tenant{Name: "Acme", Slug: "acme"})
+
+		assert.Equal(t, tenant.ErrSlugAlreadyExists, err)
+	})
+}
+
+func TestRepository_FindTenantBySlug(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("success", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		oid := primitive.NewObjectID()
+		mt.AddMockResponses(mtest.CreateCursorResponse(1, "testdb.tenants", mtest.FirstBatch, bson.D{
+			bson.E{Key: "_id", Value: oid.Hex()},
+			bson.E{Key: "name", Value: "Acme"},
+			bson.E{Key: "slug", Value: "acme"},
+		}))
+
+		result, err := repo.FindTenantBySlug(context.Background(), "acme")
+
+		assert.NoError(t, err)
+		assert.Equal(t, tenant.FindTenantResponse{Id: oid.Hex(), Name: "Acme", Slug: "acme"}, result)
+	})
+
+	mt.Run("not found", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.tenants", mtest.FirstBatch))
+
+		_, err := repo.FindTenantBySlug(context.Background(), "missing")
+
+		assert.Equal(t, tenant.ErrTenantNotFound, err)
+	})
+}
+
+func TestRepository_UpdateTenant(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("success", func(mt *mtest.T) {
+		repo := newTestRepository(mt)
+		mt.AddMockResponses(bson.D{
+			bson.E{Key: "ok", Value: 1},
+			bson.E{Key: "n", Value: 1},
+		})
+		disabled := true
+
+		count, err := repo.UpdateTenant(context.Background(), primitive.NewObjectID(), tenant.UpdateRequest{Disabled: &disabled})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+}
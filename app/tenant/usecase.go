@@ -0,0 +1,83 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"user-management/response"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Repository interface {
+	CreateTenant(ctx context.Context, tenant Tenant) (string, error)
+	FindTenantById(ctx context.Context, id string) (FindTenantResponse, error)
+	FindTenantBySlug(ctx context.Context, slug string) (FindTenantResponse, error)
+	FindTenants(ctx context.Context) ([]FindTenantResponse, error)
+	UpdateTenant(ctx context.Context, id primitive.ObjectID, req UpdateRequest) (int64, error)
+	DeleteTenant(ctx context.Context, id string) (int64, error)
+}
+
+type usecase struct {
+	repo Repository
+}
+
+func NewUsecase(r Repository) *usecase {
+	return &usecase{
+		repo: r,
+	}
+}
+
+func (u *usecase) CreateTenant(ctx context.Context, req CreateRequest) (*response.StdResp[any], error) {
+	tid, err := u.repo.CreateTenant(ctx, Tenant{
+		Name: req.Name,
+		Slug: req.Slug,
+	})
+	if err != nil {
+		if errors.Is(err, ErrSlugAlreadyExists) {
+			return response.DuplicatedTenant(), nil
+		}
+		return nil, err
+	}
+	return response.SuccessWithData(CreateResponse{tid}), nil
+}
+
+func (u *usecase) FindTenants(ctx context.Context) (*response.StdResp[any], error) {
+	tenants, err := u.repo.FindTenants(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return response.SuccessWithData(tenants), nil
+}
+
+func (u *usecase) FindTenantById(ctx context.Context, id string) (*response.StdResp[any], error) {
+	tenant, err := u.repo.FindTenantById(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrTenantNotFound) {
+			return response.TenantNotFound(), nil
+		}
+		return nil, err
+	}
+	return response.SuccessWithData(tenant), nil
+}
+
+func (u *usecase) UpdateTenant(ctx context.Context, id primitive.ObjectID, req UpdateRequest) (*response.StdResp[any], error) {
+	updateCount, err := u.repo.UpdateTenant(ctx, id, req)
+	if err != nil {
+		return nil, err
+	}
+	if updateCount == 0 {
+		return response.TenantNotFound(), nil
+	}
+	return response.Success(), nil
+}
+
+func (u *usecase) DeleteTenant(ctx context.Context, id string) (*response.StdResp[any], error) {
+	delCount, err := u.repo.DeleteTenant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if delCount == 0 {
+		return response.TenantNotFound(), nil
+	}
+	return response.Success(), nil
+}
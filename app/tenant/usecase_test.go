@@ -0,0 +1,115 @@
+package tenant_test
+
+import (
+	"context"
+	"testing"
+	"user-management/app/tenant"
+	"user-management/response"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockRepo struct {
+	mock.Mock
+}
+
+func (m *mockRepo) CreateTenant(ctx context.Context, t tenant.Tenant) (string, error) {
+	args := m.Called(ctx, t)
+	return args.String(0), args.Error(1)
+}
+
+func (m *mockRepo) FindTenantById(ctx context.Context, id string) (tenant.FindTenantResponse, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(tenant.FindTenantResponse), args.Error(1)
+}
+
+func (m *mockRepo) FindTenantBySlug(ctx context.Context, slug string) (tenant.FindTenantResponse, error) {
+	args := m.Called(ctx, slug)
+	return args.Get(0).(tenant.FindTenantResponse), args.Error(1)
+}
+
+func (m *mockRepo) FindTenants(ctx context.Context) ([]tenant.FindTenantResponse, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]tenant.FindTenantResponse), args.Error(1)
+}
+
+func (m *mockRepo) UpdateTenant(ctx context.Context, id primitive.ObjectID, req tenant.UpdateRequest) (int64, error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockRepo) DeleteTenant(ctx context.Context, id string) (int64, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestUsecaseCreateTenant(t *testing.T) {
+	repo := new(mockRepo)
+	uc := tenant.NewUsecase(repo)
+
+	repo.On("CreateTenant", mock.Anything, mock.MatchedBy(func(t tenant.Tenant) bool {
+		return t.Slug == "acme"
+	})).Return("abc123", nil)
+
+	resp, err := uc.CreateTenant(context.Background(), tenant.CreateRequest{Name: "Acme", Slug: "acme"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "abc123", resp.Data.(tenant.CreateResponse).Id)
+	repo.AssertExpectations(t)
+}
+
+func TestUsecaseCreateTenant_Duplicated(t *testing.T) {
+	repo := new(mockRepo)
+	uc := tenant.NewUsecase(repo)
+
+	repo.On("CreateTenant", mock.Anything, mock.Anything).Return("", tenant.ErrSlugAlreadyExists)
+
+	resp, err := uc.CreateTenant(context.Background(), tenant.CreateRequest{Name: "Acme", Slug: "acme"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.DuplicatedTenant(), resp)
+	repo.AssertExpectations(t)
+}
+
+func TestUsecaseFindTenantById_NotFound(t *testing.T) {
+	repo := new(mockRepo)
+	uc := tenant.NewUsecase(repo)
+
+	repo.On("FindTenantById", mock.Anything, "notfound").Return(tenant.FindTenantResponse{}, tenant.ErrTenantNotFound)
+
+	resp, err := uc.FindTenantById(context.Background(), "notfound")
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.TenantNotFound(), resp)
+	repo.AssertExpectations(t)
+}
+
+func TestUsecaseUpdateTenant_NotFound(t *testing.T) {
+	repo := new(mockRepo)
+	uc := tenant.NewUsecase(repo)
+
+	id := primitive.NewObjectID()
+	req := tenant.UpdateRequest{Name: "Renamed"}
+	repo.On("UpdateTenant", mock.Anything, id, req).Return(int64(0), nil)
+
+	resp, err := uc.UpdateTenant(context.Background(), id, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.TenantNotFound(), resp)
+	repo.AssertExpectations(t)
+}
+
+func TestUsecaseDeleteTenant(t *testing.T) {
+	repo := new(mockRepo)
+	uc := tenant.NewUsecase(repo)
+
+	repo.On("DeleteTenant", mock.Anything, "abc123").Return(int64(1), nil)
+
+	resp, err := uc.DeleteTenant(context.Background(), "abc123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.Success(), resp)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,43 @@
+package tenant
+
+import (
+	"regexp"
+	"strings"
+	"user-management/response"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+func (r CreateRequest) RequestValidation() *response.StdResp[any] {
+	if checkLen(r.Name) == 0 {
+		return response.MandatoryMissing("name")
+	}
+	if checkLen(r.Slug) == 0 {
+		return response.MandatoryMissing("slug")
+	}
+	if !slugPattern.MatchString(r.Slug) {
+		return response.InvalidData("slug")
+	}
+	return response.Success()
+}
+
+func (r UpdateRequest) RequestValidation() *response.StdResp[any] {
+	if checkLen(r.Name) == 0 && r.Disabled == nil {
+		return response.MandatoryMissing("name or disabled")
+	}
+	return response.Success()
+}
+
+func IdValidation(id string) *response.StdResp[any] {
+	_, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return response.InvalidData(ParamID)
+	}
+	return response.Success()
+}
+
+func checkLen(s string) int {
+	return len([]rune(strings.TrimSpace(s)))
+}
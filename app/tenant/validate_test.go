@@ -0,0 +1,47 @@
+package tenant_test
+
+import (
+	"testing"
+	"user-management/app/tenant"
+	"user-management/response"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateRequest_RequestValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    tenant.CreateRequest
+		wantCode *response.StdResp[any]
+	}{
+		{"Valid", tenant.CreateRequest{Name: "Acme", Slug: "acme-corp"}, response.Success()},
+		{"Missing name", tenant.CreateRequest{Slug: "acme"}, response.MandatoryMissing("name")},
+		{"Missing slug", tenant.CreateRequest{Name: "Acme"}, response.MandatoryMissing("slug")},
+		{"Invalid slug", tenant.CreateRequest{Name: "Acme", Slug: "Acme Corp"}, response.InvalidData("slug")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantCode, tt.input.RequestValidation())
+		})
+	}
+}
+
+func TestUpdateRequest_RequestValidation(t *testing.T) {
+	disabled := true
+	tests := []struct {
+		name     string
+		input    tenant.UpdateRequest
+		wantCode string
+	}{
+		{"Valid name", tenant.UpdateRequest{Name: "Acme"}, "0000"},
+		{"Valid disabled", tenant.UpdateRequest{Disabled: &disabled}, "0000"},
+		{"Empty", tenant.UpdateRequest{}, "4001"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantCode, tt.input.RequestValidation().Code)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"user-management/auth"
+	"user-management/config"
+	"user-management/passhash"
+)
+
+type BootstrapRepository interface {
+	CountUsers(ctx context.Context) (int64, error)
+	CreateUser(ctx context.Context, user User) (string, error)
+}
+
+// Bootstrap creates the configured platform admin when the user collection is
+// empty and reports whether it did. Replicas starting together may all see an
+// empty collection; the unique {tenant_id, email} index lets only one insert
+// succeed, and the others treat the duplicate as already seeded. The password
+// must pass the policy, but only when the admin is actually created.
+func Bootstrap(ctx context.Context, cfg config.BootstrapConfig, policy *PasswordPolicy, hasher passhash.Hasher, repo BootstrapRepository) (bool, error) {
+	if cfg.AdminEmail == "" {
+		return false, nil
+	}
+	if !isValidEmail(cfg.AdminEmail) {
+		return false, ErrBootstrapEmail
+	}
+	password := cfg.AdminPassword
+	if cfg.AdminPasswordFile != "" {
+		password = strings.TrimRight(cfg.AdminPasswordFile, "\r\n")
+	}
+	if password == "" {
+		return false, ErrBootstrapPassword
+	}
+
+	count, err := repo.CountUsers(ctx)
+	if err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, nil
+	}
+	violations, err := policy.Check(password, cfg.AdminName, cfg.AdminEmail)
+	if err != nil {
+		return false, err
+	}
+	if len(violations) > 0 {
+		rules := make([]string, len(violations))
+		for i, v := range violations {
+			rules[i] = v.Rule
+		}
+		return false, fmt.Errorf("%w: %s", ErrBootstrapWeakPassword, strings.Join(rules, ", "))
+	}
+
+	hashedPassword, err := hasher.Hash(password)
+	if err != nil {
+		return false, err
+	}
+	_, err = repo.CreateUser(ctx, User{
+		Name:     cfg.AdminName,
+		Email:    cfg.AdminEmail,
+		Password: hashedPassword,
+		Role:     auth.RoleAdmin,
+	})
+	if err != nil {
+		if errors.Is(err, ErrEmailAlreadyExists) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
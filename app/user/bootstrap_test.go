@@ -0,0 +1,107 @@
+package user_test
+
+import (
+	"context"
+	"testing"
+	"user-management/app/user"
+	"user-management/auth"
+	"user-management/config"
+	"user-management/passhash"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var bootstrapConfig = config.BootstrapConfig{
+	AdminName:     "Admin",
+	AdminEmail:    "admin@example.com",
+	AdminPassword: "passwordstring",
+}
+
+func TestBootstrap_CreatesAdmin(t *testing.T) {
+	repo := new(mockRepo)
+	repo.On("CountUsers", mock.Anything).Return(int64(0), nil)
+	repo.On("CreateUser", mock.Anything, mock.MatchedBy(func(u user.User) bool {
+		return u.Email == "admin@example.com" && u.Role == auth.RoleAdmin && u.TenantID == "" &&
+			passhash.Verify(u.Password, "passwordstring")
+	})).Return("abc123", nil)
+
+	created, err := user.Bootstrap(context.Background(), bootstrapConfig, openPolicy, testHasher, repo)
+
+	assert.NoError(t, err)
+	assert.True(t, created)
+	repo.AssertExpectations(t)
+}
+
+func TestBootstrap_PasswordFile(t *testing.T) {
+	repo := new(mockRepo)
+	cfg := bootstrapConfig
+	cfg.AdminPasswordFile = "fromfile\n"
+	repo.On("CountUsers", mock.Anything).Return(int64(0), nil)
+	repo.On("CreateUser", mock.Anything, mock.MatchedBy(func(u user.User) bool {
+		return passhash.Verify(u.Password, "fromfile")
+	})).Return("abc123", nil)
+
+	created, err := user.Bootstrap(context.Background(), cfg, openPolicy, testHasher, repo)
+
+	assert.NoError(t, err)
+	assert.True(t, created)
+}
+
+func TestBootstrap_AlreadySeeded(t *testing.T) {
+	repo := new(mockRepo)
+	repo.On("CountUsers", mock.Anything).Return(int64(3), nil)
+
+	created, err := user.Bootstrap(context.Background(), bootstrapConfig, openPolicy, testHasher, repo)
+
+	assert.NoError(t, err)
+	assert.False(t, created)
+	repo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+}
+
+func TestBootstrap_LostRace(t *testing.T) {
+	repo := new(mockRepo)
+	repo.On("CountUsers", mock.Anything).Return(int64(0), nil)
+	repo.On("CreateUser", mock.Anything, mock.Anything).Return("", user.ErrEmailAlreadyExists)
+
+	created, err := user.Bootstrap(context.Background(), bootstrapConfig, openPolicy, testHasher, repo)
+
+	assert.NoError(t, err)
+	assert.False(t, created)
+}
+
+func TestBootstrap_WeakPassword(t *testing.T) {
+	repo := new(mockRepo)
+	repo.On("CountUsers", mock.Anything).Return(int64(0), nil)
+
+	created, err := user.Bootstrap(context.Background(), bootstrapConfig, newPolicy(config.PasswordPolicyConfig{MinLength: 20}), testHasher, repo)
+
+	assert.ErrorIs(t, err, user.ErrBootstrapWeakPassword)
+	assert.ErrorContains(t, err, user.RuleMinLength)
+	assert.False(t, created)
+	repo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+}
+
+func TestBootstrap_Config(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     config.BootstrapConfig
+		wantErr error
+	}{
+		{"Disabled", config.BootstrapConfig{}, nil},
+		{"Invalid email", config.BootstrapConfig{AdminEmail: "admin", AdminPassword: "x"}, user.ErrBootstrapEmail},
+		{"Missing password", config.BootstrapConfig{AdminEmail: "admin@example.com"}, user.ErrBootstrapPassword},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mockRepo)
+
+			created, err := user.Bootstrap(context.Background(), tt.cfg, openPolicy, testHasher, repo)
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.False(t, created)
+			repo.AssertNotCalled(t, "CountUsers", mock.Anything)
+		})
+	}
+}
@@ -3,11 +3,51 @@ package user
 import "errors"
 
 const (
-	ParamID = "id"
+	ParamID     = "id"
+	QueryFormat = "format"
+	QueryDryRun = "dry_run"
+	QueryFields = "fields"
+
+	// QueryProfilePrefix starts the query parameters that filter users by
+	// profile attribute, as in profile.department=Sales.
+	QueryProfilePrefix = "profile."
+)
+
+const (
+	ImportFormatCSV   = "csv"
+	ImportFormatJSONL = "jsonl"
+
+	// ImportMaxRows caps a single import so one request cannot hold the
+	// whole file in memory indefinitely.
+	ImportMaxRows = 50000
+
+	ImportStatusCreated = "created"
+	ImportStatusValid   = "valid"
+	ImportStatusFailed  = "failed"
+
+	importChunkSize = 500
+)
+
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatJSONL  = "jsonl"
+	ExportFormatNDJSON = "ndjson"
+
+	exportFlushRows = 500
 )
 
+// ExportFields lists the user fields that can be exported, in output order.
+// The password hash is deliberately not exportable.
+var ExportFields = []string{"id", "tenant_id", "name", "email", "role", "created_at"}
+
 var (
 	ErrEmailAlreadyExists    = errors.New("Email already exists")
 	ErrUserOrPasswordIsWrong = errors.New("User or password is wrong")
 	ErrUserNotFound          = errors.New("User not found")
+	ErrBootstrapEmail        = errors.New("Bootstrap admin email is invalid")
+	ErrBootstrapPassword     = errors.New("Bootstrap admin password is not set")
+	ErrBootstrapWeakPassword = errors.New("Bootstrap admin password does not meet the policy")
+	ErrImportFormat          = errors.New("Unsupported import format")
+	ErrImportHeader          = errors.New("Import header must contain an email column")
+	ErrImportTooLarge        = errors.New("Import has too many rows")
 )
@@ -0,0 +1,121 @@
+package user
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// ExportWriter writes users one at a time in the chosen format and keeps
+// nothing but the current row in memory.
+type ExportWriter interface {
+	Write(user FindUserResponse) error
+	Flush() error
+}
+
+// NewExportWriter returns a writer for format with the given fields, which must
+// already be validated with ExportFieldsValidation.
+func NewExportWriter(w io.Writer, format string, fields []string) ExportWriter {
+	if format == ExportFormatCSV {
+		return &csvExportWriter{w: csv.NewWriter(w), fields: fields}
+	}
+	return &jsonlExportWriter{w: w, fields: fields}
+}
+
+type csvExportWriter struct {
+	w           *csv.Writer
+	fields      []string
+	wroteHeader bool
+}
+
+func (e *csvExportWriter) writeHeader() error {
+	if e.wroteHeader {
+		return nil
+	}
+	e.wroteHeader = true
+	return e.w.Write(e.fields)
+}
+
+func (e *csvExportWriter) Write(user FindUserResponse) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	record := make([]string, len(e.fields))
+	for i, f := range e.fields {
+		record[i] = exportValue(user, f)
+	}
+	return e.w.Write(record)
+}
+
+func (e *csvExportWriter) Flush() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type jsonlExportWriter struct {
+	w      io.Writer
+	fields []string
+	buf    bytes.Buffer
+}
+
+// Write encodes the object by hand so keys keep the requested field order.
+func (e *jsonlExportWriter) Write(user FindUserResponse) error {
+	e.buf.Reset()
+	e.buf.WriteByte('{')
+	for i, f := range e.fields {
+		if i > 0 {
+			e.buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(f)
+		value, _ := json.Marshal(exportValue(user, f))
+		e.buf.Write(key)
+		e.buf.WriteByte(':')
+		e.buf.Write(value)
+	}
+	e.buf.WriteString("}\n")
+	_, err := e.w.Write(e.buf.Bytes())
+	return err
+}
+
+func (e *jsonlExportWriter) Flush() error {
+	return nil
+}
+
+func exportValue(user FindUserResponse, field string) string {
+	switch field {
+	case "id":
+		return user.Id
+	case "tenant_id":
+		return user.TenantID
+	case "name":
+		return user.Name
+	case "email":
+		return user.Email
+	case "role":
+		return user.Role
+	case "created_at":
+		return user.CreatedAt.Format(time.RFC3339)
+	}
+	return ""
+}
+
+// exportProjection maps export fields to stored document keys.
+func exportProjection(fields []string) map[string]int {
+	projection := map[string]int{}
+	for _, f := range fields {
+		if f == "id" {
+			projection["_id"] = 1
+			continue
+		}
+		projection[f] = 1
+	}
+	if _, ok := projection["_id"]; !ok {
+		projection["_id"] = 0
+	}
+	return projection
+}
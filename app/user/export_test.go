@@ -0,0 +1,52 @@
+package user_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+	"user-management/app/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func exportUsers() []user.FindUserResponse {
+	created := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	return []user.FindUserResponse{
+		{Id: "u1", Name: "Alice", Email: "a@example.com", Role: "admin", CreatedAt: created},
+		{Id: "u2", Name: "Bob, Jr.", Email: "b@example.com", CreatedAt: created},
+	}
+}
+
+func TestExportWriter_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	w := user.NewExportWriter(&buf, user.ExportFormatCSV, []string{"id", "name", "created_at"})
+
+	for _, u := range exportUsers() {
+		assert.NoError(t, w.Write(u))
+	}
+	assert.NoError(t, w.Flush())
+
+	assert.Equal(t, "id,name,created_at\nu1,Alice,2024-05-01T10:00:00Z\nu2,\"Bob, Jr.\",2024-05-01T10:00:00Z\n", buf.String())
+}
+
+func TestExportWriter_CSVEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	w := user.NewExportWriter(&buf, user.ExportFormatCSV, []string{"id", "email"})
+
+	assert.NoError(t, w.Flush())
+
+	assert.Equal(t, "id,email\n", buf.String())
+}
+
+func TestExportWriter_JSONL(t *testing.T) {
+	var buf bytes.Buffer
+	w := user.NewExportWriter(&buf, user.ExportFormatNDJSON, []string{"id", "email", "role"})
+
+	for _, u := range exportUsers() {
+		assert.NoError(t, w.Write(u))
+	}
+	assert.NoError(t, w.Flush())
+
+	assert.Equal(t, `{"id":"u1","email":"a@example.com","role":"admin"}`+"\n"+`{"id":"u2","email":"b@example.com","role":""}`+"\n", buf.String())
+	assert.NotContains(t, buf.String(), "password")
+}
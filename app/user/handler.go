@@ -2,7 +2,12 @@ package user
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
 	"user-management/logger"
 	"user-management/response"
 
@@ -12,19 +17,29 @@ import (
 
 type Usecase interface {
 	CreateUser(ctx context.Context, req CreateRequest) (*response.StdResp[any], error)
+	ImportUsers(ctx context.Context, rows []ImportRow, dryRun bool) (*response.StdResp[any], error)
 	Login(ctx context.Context, req SignInRequest) (*response.StdResp[any], error)
-	FindUsers(ctx context.Context) (*response.StdResp[any], error)
+	FindUsers(ctx context.Context, filter map[string]string) (*response.StdResp[any], error)
+	ExportUsers(ctx context.Context, filter map[string]string, fields []string, fn func(FindUserResponse) error) (*response.StdResp[any], error)
 	FindUserById(ctx context.Context, id string) (*response.StdResp[any], error)
 	UpdateUser(ctx context.Context, user User) (*response.StdResp[any], error)
+	ResetPassword(ctx context.Context, id, password string) (*response.StdResp[any], error)
+	ChangeStatus(ctx context.Context, id string, req StatusRequest) (*response.StdResp[any], error)
+	FindStatusHistory(ctx context.Context, id string) (*response.StdResp[any], error)
 	DeleteUser(ctx context.Context, id string) (*response.StdResp[any], error)
+	ReencryptUsers(ctx context.Context, dryRun bool) (*response.StdResp[any], error)
 }
 
 type Handler interface {
 	Login(c echo.Context) error
 	CreateUser(c echo.Context) error
+	ImportUsers(c echo.Context) error
 	FindUsers(c echo.Context) error
+	ExportUsers(c echo.Context) error
 	FindUserById(c echo.Context) error
 	UpdateUser(c echo.Context) error
+	ChangeStatus(c echo.Context) error
+	FindStatusHistory(c echo.Context) error
 	DeleteUser(c echo.Context) error
 }
 
@@ -63,7 +78,7 @@ func (h *handler) Login(c echo.Context) error {
 		return c.JSON(sr.WithHTTPStatus())
 	}
 
-	c.SetCookie(newCookie(sr.Data.(*SignInResponse)))
+	c.SetCookie(NewTokenCookie(sr.Data.(*SignInResponse)))
 	return c.JSON(sr.WithHTTPStatus())
 }
 
@@ -93,13 +108,66 @@ func (h *handler) CreateUser(c echo.Context) error {
 	return c.JSON(resp.WithHTTPStatus())
 }
 
+// ImportUsers reads a CSV or JSONL body. The format comes from the format
+// query parameter, or from the Content-Type when the parameter is omitted.
+func (h *handler) ImportUsers(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	format := c.QueryParam(QueryFormat)
+	if format == "" {
+		format = importFormatFromContentType(c.Request().Header.Get(echo.HeaderContentType))
+	}
+	if respValidate := ImportFormatValidation(format); !respValidate.IsSuccess() {
+		return c.JSON(respValidate.WithHTTPStatus())
+	}
+	dryRun := false
+	if v := c.QueryParam(QueryDryRun); v != "" {
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			return c.JSON(response.InvalidData(QueryDryRun).WithHTTPStatus())
+		}
+	}
+
+	rows, err := ParseImport(c.Request().Body, format)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Parse import error: %v", err.Error())
+		switch {
+		case errors.Is(err, ErrImportHeader):
+			return c.JSON(response.MandatoryMissing("email column").WithHTTPStatus())
+		case errors.Is(err, ErrImportTooLarge):
+			return c.JSON(response.InvalidData("row count").WithHTTPStatus())
+		}
+		return c.JSON(response.UnexpectedRequest().WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.ImportUsers(ctx, rows, dryRun)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+func importFormatFromContentType(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/csv":
+		return ImportFormatCSV
+	case "application/jsonl", "application/x-ndjson":
+		return ImportFormatJSONL
+	}
+	return ""
+}
+
 func (h *handler) FindUsers(c echo.Context) error {
 	ctx := c.Request().Context()
 	zlog, err := logger.FromContext(ctx)
 	if err != nil {
 		return c.JSON(response.InternalServerError().WithHTTPStatus())
 	}
-	resp, err := h.usecase.FindUsers(ctx)
+	resp, err := h.usecase.FindUsers(ctx, profileQuery(c))
 	if err != nil {
 		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
 		return c.JSON(response.InternalServerError().WithHTTPStatus())
@@ -107,6 +175,92 @@ func (h *handler) FindUsers(c echo.Context) error {
 	return c.JSON(resp.WithHTTPStatus())
 }
 
+// profileQuery collects the profile.<name> query parameters that filter
+// listings and exports.
+func profileQuery(c echo.Context) map[string]string {
+	filter := map[string]string{}
+	for name, values := range c.QueryParams() {
+		if attr, ok := strings.CutPrefix(name, QueryProfilePrefix); ok {
+			filter[attr] = values[0]
+		}
+	}
+	return filter
+}
+
+// ExportUsers streams users as CSV or JSON lines, filtered like FindUsers.
+// Headers are sent with the first row, so errors before that still get a
+// JSON error response.
+func (h *handler) ExportUsers(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	format := c.QueryParam(QueryFormat)
+	if format == "" {
+		format = ExportFormatJSONL
+	}
+	if respValidate := ExportFormatValidation(format); !respValidate.IsSuccess() {
+		return c.JSON(respValidate.WithHTTPStatus())
+	}
+	var requested []string
+	if v := c.QueryParam(QueryFields); v != "" {
+		requested = strings.Split(v, ",")
+	}
+	fields, respValidate := ExportFieldsValidation(requested)
+	if !respValidate.IsSuccess() {
+		return c.JSON(respValidate.WithHTTPStatus())
+	}
+
+	res := c.Response()
+	writer := NewExportWriter(res, format, fields)
+	started := false
+	start := func() {
+		started = true
+		contentType, ext := "application/x-ndjson", format
+		if format == ExportFormatCSV {
+			contentType = "text/csv"
+		}
+		res.Header().Set(echo.HeaderContentType, contentType)
+		res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=users.%s", ext))
+		res.WriteHeader(http.StatusOK)
+	}
+	rows := 0
+	resp, err := h.usecase.ExportUsers(ctx, profileQuery(c), fields, func(u FindUserResponse) error {
+		if !started {
+			start()
+		}
+		if err := writer.Write(u); err != nil {
+			return err
+		}
+		if rows++; rows%exportFlushRows == 0 {
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+			res.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		if started {
+			// The status line is already out; cutting the body short is all we can do.
+			return nil
+		}
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	if !resp.IsSuccess() {
+		return c.JSON(resp.WithHTTPStatus())
+	}
+	if !started {
+		start()
+	}
+	if err := writer.Flush(); err != nil {
+		zlog.Sugar().Infof("[Handler] Export flush error: %v", err.Error())
+	}
+	return nil
+}
+
 func (h *handler) FindUserById(c echo.Context) error {
 	ctx := c.Request().Context()
 	zlog, err := logger.FromContext(ctx)
@@ -150,9 +304,10 @@ func (h *handler) UpdateUser(c echo.Context) error {
 	}
 
 	resp, err := h.usecase.UpdateUser(ctx, User{
-		ID:    userID,
-		Name:  request.Name,
-		Email: request.Email,
+		ID:      userID,
+		Name:    request.Name,
+		Email:   request.Email,
+		Profile: request.Profile,
 	})
 	if err != nil {
 		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
@@ -161,6 +316,53 @@ func (h *handler) UpdateUser(c echo.Context) error {
 	return c.JSON(resp.WithHTTPStatus())
 }
 
+func (h *handler) ChangeStatus(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	paramId := c.Param(ParamID)
+	if respValidate := IdValidation(paramId); !respValidate.IsSuccess() {
+		return c.JSON(respValidate.WithHTTPStatus())
+	}
+	var request StatusRequest
+	err = c.Bind(&request)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Bind request error: %v", err)
+		return c.JSON(response.UnexpectedRequest().WithHTTPStatus())
+	}
+	if resp := request.RequestValidation(); !resp.IsSuccess() {
+		return c.JSON(resp.WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.ChangeStatus(ctx, paramId, request)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
+func (h *handler) FindStatusHistory(c echo.Context) error {
+	ctx := c.Request().Context()
+	zlog, err := logger.FromContext(ctx)
+	if err != nil {
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	paramId := c.Param(ParamID)
+	if respValidate := IdValidation(paramId); !respValidate.IsSuccess() {
+		return c.JSON(respValidate.WithHTTPStatus())
+	}
+
+	resp, err := h.usecase.FindStatusHistory(ctx, paramId)
+	if err != nil {
+		zlog.Sugar().Infof("[Handler] Service error: %v", err.Error())
+		return c.JSON(response.InternalServerError().WithHTTPStatus())
+	}
+	return c.JSON(resp.WithHTTPStatus())
+}
+
 func (h *handler) DeleteUser(c echo.Context) error {
 	ctx := c.Request().Context()
 	zlog, err := logger.FromContext(ctx)
@@ -180,7 +382,9 @@ func (h *handler) DeleteUser(c echo.Context) error {
 	return c.JSON(resp.WithHTTPStatus())
 }
 
-func newCookie(s *SignInResponse) *http.Cookie {
+// NewTokenCookie carries a login token for browsers, which the auth middleware
+// accepts in place of the Authorization header.
+func NewTokenCookie(s *SignInResponse) *http.Cookie {
 	return &http.Cookie{
 		Name:     "token",
 		Value:    s.Token,
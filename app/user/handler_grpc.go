@@ -2,9 +2,12 @@ package user
 
 import (
 	"context"
+	"io"
 	"time"
 	usergrpc "user-management/app/user/grpc/gen/go/user/v1"
 	"user-management/response"
+
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 type GrpcHandler struct {
@@ -15,12 +18,50 @@ func NewGrpcHandler(u Usecase) *GrpcHandler {
 	return &GrpcHandler{usecase: u}
 }
 
+func (h *GrpcHandler) Login(ctx context.Context, req *usergrpc.LoginRequest) (*usergrpc.LoginResponse, error) {
+	request := SignInRequest{
+		Tenant:   req.Tenant,
+		Email:    req.Email,
+		Password: req.Password,
+	}
+	if resp := request.RequestValidation(); !resp.IsSuccess() {
+		return &usergrpc.LoginResponse{
+			Code:    resp.Code,
+			Message: resp.Message,
+		}, nil
+	}
+
+	resp, err := h.usecase.Login(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccess() {
+		return &usergrpc.LoginResponse{
+			Code:    resp.Code,
+			Message: resp.Message,
+		}, nil
+	}
+
+	signIn := resp.Data.(*SignInResponse)
+	return &usergrpc.LoginResponse{
+		Code:    response.Success().Code,
+		Message: response.Success().Message,
+		Data: &usergrpc.LoginResponse_Data{
+			Token:     signIn.Token,
+			ExpiresAt: signIn.ExpiresAt.Format(time.RFC3339),
+		},
+	}, nil
+}
+
 func (h *GrpcHandler) CreateUser(ctx context.Context, req *usergrpc.CreateUserRequest) (*usergrpc.CreateUserResponse, error) {
 	request := CreateRequest{
 		Name:     req.Name,
 		Email:    req.Email,
 		Password: req.Password,
 	}
+	if req.Profile != nil {
+		request.Profile = req.Profile.AsMap()
+	}
 	if resp := request.RequestValidation(); !resp.IsSuccess() {
 		return &usergrpc.CreateUserResponse{
 			Code:    resp.Code,
@@ -74,6 +115,15 @@ func (h *GrpcHandler) GetUser(ctx context.Context, req *usergrpc.GetUserRequest)
 	}
 
 	user := resp.Data.(FindUserResponse)
+	var profile *structpb.Struct
+	if len(user.Profile) > 0 {
+		if profile, err = structpb.NewStruct(user.Profile); err != nil {
+			return &usergrpc.GetUserResponse{
+				Code:    response.InternalServerError().Code,
+				Message: response.InternalServerError().Message,
+			}, err
+		}
+	}
 	return &usergrpc.GetUserResponse{
 		Code:    response.Success().Code,
 		Message: response.Success().Message,
@@ -82,6 +132,171 @@ func (h *GrpcHandler) GetUser(ctx context.Context, req *usergrpc.GetUserRequest)
 			Name:  user.Name,
 			Email: user.Email,
 			CreatedAt: user.CreatedAt.Format(time.RFC3339),
+			Status:    user.Status,
+			Profile:   profile,
+			Avatar:    user.Avatar,
 		},
 	}, nil
 }
+
+func (h *GrpcHandler) ImportUsers(stream usergrpc.UserService_ImportUsersServer) error {
+	var rows []ImportRow
+	dryRun := false
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			dryRun = req.DryRun
+		}
+		if len(rows) == ImportMaxRows {
+			resp := response.InvalidData("row count")
+			return stream.SendAndClose(&usergrpc.ImportUsersResponse{
+				Code:    resp.Code,
+				Message: resp.Message,
+			})
+		}
+		rows = append(rows, ImportRow{
+			Line: len(rows) + 1,
+			Request: CreateRequest{
+				Name:     req.Name,
+				Email:    req.Email,
+				Password: req.Password,
+				Role:     req.Role,
+				TenantID: req.TenantId,
+			},
+		})
+	}
+
+	resp, err := h.usecase.ImportUsers(stream.Context(), rows, dryRun)
+	if err != nil {
+		return err
+	}
+	if !resp.IsSuccess() {
+		return stream.SendAndClose(&usergrpc.ImportUsersResponse{
+			Code:    resp.Code,
+			Message: resp.Message,
+		})
+	}
+
+	report := resp.Data.(ImportReport)
+	data := &usergrpc.ImportUsersResponse_Data{
+		Total:     int32(report.Total),
+		Succeeded: int32(report.Succeeded),
+		Failed:    int32(report.Failed),
+		DryRun:    report.DryRun,
+		Rows:      make([]*usergrpc.ImportUsersResponse_Row, 0, len(report.Rows)),
+	}
+	for _, r := range report.Rows {
+		data.Rows = append(data.Rows, &usergrpc.ImportUsersResponse_Row{
+			Line:   int32(r.Line),
+			Email:  r.Email,
+			Id:     r.Id,
+			Status: r.Status,
+			Error:  r.Error,
+		})
+	}
+	return stream.SendAndClose(&usergrpc.ImportUsersResponse{
+		Code:    response.Success().Code,
+		Message: response.Success().Message,
+		Data:    data,
+	})
+}
+
+func (h *GrpcHandler) ExportUsers(req *usergrpc.ExportUsersRequest, stream usergrpc.UserService_ExportUsersServer) error {
+	fields, respValidate := ExportFieldsValidation(req.Fields)
+	if !respValidate.IsSuccess() {
+		return stream.Send(&usergrpc.ExportUsersResponse{
+			Code:    respValidate.Code,
+			Message: respValidate.Message,
+		})
+	}
+
+	success := response.Success()
+	resp, err := h.usecase.ExportUsers(stream.Context(), nil, fields, func(u FindUserResponse) error {
+		data := &usergrpc.ExportUsersResponse_Data{}
+		for _, f := range fields {
+			value := exportValue(u, f)
+			switch f {
+			case "id":
+				data.Id = value
+			case "tenant_id":
+				data.TenantId = value
+			case "name":
+				data.Name = value
+			case "email":
+				data.Email = value
+			case "role":
+				data.Role = value
+			case "created_at":
+				data.CreatedAt = value
+			}
+		}
+		return stream.Send(&usergrpc.ExportUsersResponse{
+			Code:    success.Code,
+			Message: success.Message,
+			Data:    data,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	if !resp.IsSuccess() {
+		return stream.Send(&usergrpc.ExportUsersResponse{
+			Code:    resp.Code,
+			Message: resp.Message,
+		})
+	}
+	return nil
+}
+
+func (h *GrpcHandler) ChangeUserStatus(ctx context.Context, req *usergrpc.ChangeUserStatusRequest) (*usergrpc.ChangeUserStatusResponse, error) {
+	if respValidate := IdValidation(req.Id); !respValidate.IsSuccess() {
+		return &usergrpc.ChangeUserStatusResponse{Code: respValidate.Code, Message: respValidate.Message}, nil
+	}
+	request := StatusRequest{Status: req.Status, Reason: req.Reason}
+	if respValidate := request.RequestValidation(); !respValidate.IsSuccess() {
+		return &usergrpc.ChangeUserStatusResponse{Code: respValidate.Code, Message: respValidate.Message}, nil
+	}
+
+	resp, err := h.usecase.ChangeStatus(ctx, req.Id, request)
+	if err != nil {
+		return &usergrpc.ChangeUserStatusResponse{
+			Code:    response.InternalServerError().Code,
+			Message: response.InternalServerError().Message,
+		}, err
+	}
+	return &usergrpc.ChangeUserStatusResponse{Code: resp.Code, Message: resp.Message}, nil
+}
+
+func (h *GrpcHandler) GetUserStatusHistory(ctx context.Context, req *usergrpc.GetUserStatusHistoryRequest) (*usergrpc.GetUserStatusHistoryResponse, error) {
+	if respValidate := IdValidation(req.Id); !respValidate.IsSuccess() {
+		return &usergrpc.GetUserStatusHistoryResponse{Code: respValidate.Code, Message: respValidate.Message}, nil
+	}
+
+	resp, err := h.usecase.FindStatusHistory(ctx, req.Id)
+	if err != nil {
+		return &usergrpc.GetUserStatusHistoryResponse{
+			Code:    response.InternalServerError().Code,
+			Message: response.InternalServerError().Message,
+		}, err
+	}
+	out := &usergrpc.GetUserStatusHistoryResponse{Code: resp.Code, Message: resp.Message}
+	if !resp.IsSuccess() {
+		return out, nil
+	}
+	for _, change := range resp.Data.([]StatusChange) {
+		out.Data = append(out.Data, &usergrpc.StatusChange{
+			From:      change.From,
+			To:        change.To,
+			Reason:    change.Reason,
+			ChangedBy: change.ChangedBy,
+			ChangedAt: change.ChangedAt.Format(time.RFC3339),
+		})
+	}
+	return out, nil
+}
@@ -3,7 +3,9 @@ package user_test
 import (
 	"context"
 	"errors"
+	"io"
 	"testing"
+	"time"
 	"user-management/app/user"
 	usergrpc "user-management/app/user/grpc/gen/go/user/v1"
 	"user-management/response"
@@ -11,6 +13,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 func TestGrpcHandler_CreateUser(t *testing.T) {
@@ -36,6 +40,31 @@ func TestGrpcHandler_CreateUser(t *testing.T) {
 	mockUc.AssertExpectations(t)
 }
 
+func TestGrpcHandler_CreateUser_Profile(t *testing.T) {
+	mockUc := new(mockUsecase)
+	handler := user.NewGrpcHandler(mockUc)
+
+	ctx := context.Background()
+	profile, err := structpb.NewStruct(map[string]any{"department": "Sales", "level": 3})
+	assert.NoError(t, err)
+	req := &usergrpc.CreateUserRequest{
+		Name:     "John Doe",
+		Email:    "john.doe@example.com",
+		Password: "password123",
+		Profile:  profile,
+	}
+
+	mockUc.On("CreateUser", ctx, mock.MatchedBy(func(r user.CreateRequest) bool {
+		return r.Profile["department"] == "Sales" && r.Profile["level"] == float64(3)
+	})).Return(response.SuccessWithData(user.CreateResponse{Id: "12345"}), nil)
+
+	resp, err := handler.CreateUser(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, "0000", resp.Code)
+
+	mockUc.AssertExpectations(t)
+}
+
 func TestGrpcHandler_CreateUser_InvalidRequest(t *testing.T) {
 	mockUc := new(mockUsecase)
 	handler := user.NewGrpcHandler(mockUc)
@@ -116,10 +145,72 @@ func TestGrpcHandler_GetUser(t *testing.T) {
 	assert.Equal(t, oid.Hex(), resp.Data.Id)
 	assert.Equal(t, "John Doe", resp.Data.Name)
 	assert.Equal(t, "john.doe@example.com", resp.Data.Email)
+	assert.Nil(t, resp.Data.Profile)
 
 	mockUc.AssertExpectations(t)
 }
 
+func TestGrpcHandler_GetUser_Profile(t *testing.T) {
+	mockUc := new(mockUsecase)
+	handler := user.NewGrpcHandler(mockUc)
+
+	ctx := context.Background()
+	oid := primitive.NewObjectID()
+	mockUc.On("FindUserById", ctx, oid.Hex()).Return(response.SuccessWithData(user.FindUserResponse{
+		Id:      oid.Hex(),
+		Profile: map[string]any{"department": "Sales", "level": int64(3), "remote": true},
+	}), nil)
+
+	resp, err := handler.GetUser(ctx, &usergrpc.GetUserRequest{Id: oid.Hex()})
+	assert.NoError(t, err)
+	assert.Equal(t, "0000", resp.Code)
+	assert.Equal(t, map[string]any{"department": "Sales", "level": float64(3), "remote": true}, resp.Data.Profile.AsMap())
+
+	mockUc.AssertExpectations(t)
+}
+
+func TestGrpcHandler_ChangeUserStatus(t *testing.T) {
+	mockUc := new(mockUsecase)
+	handler := user.NewGrpcHandler(mockUc)
+
+	oid := primitive.NewObjectID()
+	mockUc.On("ChangeStatus", mock.Anything, oid.Hex(), user.StatusRequest{Status: user.StatusSuspended, Reason: "spam"}).
+		Return(response.Success(), nil)
+
+	resp, err := handler.ChangeUserStatus(context.Background(), &usergrpc.ChangeUserStatusRequest{Id: oid.Hex(), Status: user.StatusSuspended, Reason: "spam"})
+	assert.NoError(t, err)
+	assert.Equal(t, response.Success().Code, resp.Code)
+	mockUc.AssertExpectations(t)
+}
+
+func TestGrpcHandler_ChangeUserStatus_Invalid(t *testing.T) {
+	mockUc := new(mockUsecase)
+	handler := user.NewGrpcHandler(mockUc)
+
+	resp, err := handler.ChangeUserStatus(context.Background(), &usergrpc.ChangeUserStatusRequest{Id: primitive.NewObjectID().Hex(), Status: "banned"})
+	assert.NoError(t, err)
+	assert.Equal(t, response.InvalidData("status").Code, resp.Code)
+	mockUc.AssertNotCalled(t, "ChangeStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGrpcHandler_GetUserStatusHistory(t *testing.T) {
+	mockUc := new(mockUsecase)
+	handler := user.NewGrpcHandler(mockUc)
+
+	oid := primitive.NewObjectID()
+	changedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockUc.On("FindStatusHistory", mock.Anything, oid.Hex()).Return(response.SuccessWithData([]user.StatusChange{
+		{From: user.StatusActive, To: user.StatusDisabled, Reason: "left", ChangedBy: "a1", ChangedAt: changedAt},
+	}), nil)
+
+	resp, err := handler.GetUserStatusHistory(context.Background(), &usergrpc.GetUserStatusHistoryRequest{Id: oid.Hex()})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Data, 1)
+	assert.Equal(t, user.StatusDisabled, resp.Data[0].To)
+	assert.Equal(t, "a1", resp.Data[0].ChangedBy)
+	assert.Equal(t, "2025-01-01T00:00:00Z", resp.Data[0].ChangedAt)
+}
+
 func TestGrpcHandler_GetUser_InvalidId(t *testing.T) {
 	mockUc := new(mockUsecase)
 	handler := user.NewGrpcHandler(mockUc)
@@ -168,3 +259,147 @@ func TestGrpcHandler_GetUser_InternalError(t *testing.T) {
 
 	mockUc.AssertExpectations(t)
 }
+
+// fakeImportStream feeds requests to ImportUsers and captures the response.
+type fakeImportStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	reqs []*usergrpc.ImportUsersRequest
+	resp *usergrpc.ImportUsersResponse
+}
+
+func (s *fakeImportStream) Context() context.Context { return s.ctx }
+
+func (s *fakeImportStream) Recv() (*usergrpc.ImportUsersRequest, error) {
+	if len(s.reqs) == 0 {
+		return nil, io.EOF
+	}
+	req := s.reqs[0]
+	s.reqs = s.reqs[1:]
+	return req, nil
+}
+
+func (s *fakeImportStream) SendAndClose(resp *usergrpc.ImportUsersResponse) error {
+	s.resp = resp
+	return nil
+}
+
+func TestGrpcHandler_ImportUsers(t *testing.T) {
+	mockUc := new(mockUsecase)
+	handler := user.NewGrpcHandler(mockUc)
+
+	stream := &fakeImportStream{
+		ctx: context.Background(),
+		reqs: []*usergrpc.ImportUsersRequest{
+			{Name: "Alice", Email: "a@example.com", Password: "pass1", DryRun: true},
+			{Name: "Bob", Email: "b@example.com", Password: "pass2", Role: "admin"},
+		},
+	}
+	rows := []user.ImportRow{
+		{Line: 1, Request: user.CreateRequest{Name: "Alice", Email: "a@example.com", Password: "pass1"}},
+		{Line: 2, Request: user.CreateRequest{Name: "Bob", Email: "b@example.com", Password: "pass2", Role: "admin"}},
+	}
+	report := user.ImportReport{Total: 2, Succeeded: 1, Failed: 1, DryRun: true, Rows: []user.ImportRowResult{
+		{Line: 1, Email: "a@example.com", Status: user.ImportStatusValid},
+		{Line: 2, Email: "b@example.com", Status: user.ImportStatusFailed, Error: "boom"},
+	}}
+	mockUc.On("ImportUsers", stream.ctx, rows, true).Return(response.SuccessWithData(report), nil)
+
+	err := handler.ImportUsers(stream)
+	assert.NoError(t, err)
+	assert.Equal(t, response.Success().Code, stream.resp.Code)
+	assert.Equal(t, int32(1), stream.resp.Data.Failed)
+	assert.True(t, stream.resp.Data.DryRun)
+	assert.Equal(t, "boom", stream.resp.Data.Rows[1].Error)
+	mockUc.AssertExpectations(t)
+}
+
+func TestGrpcHandler_ImportUsers_Forbidden(t *testing.T) {
+	mockUc := new(mockUsecase)
+	handler := user.NewGrpcHandler(mockUc)
+
+	stream := &fakeImportStream{ctx: context.Background()}
+	mockUc.On("ImportUsers", stream.ctx, []user.ImportRow(nil), false).Return(response.Forbidden(), nil)
+
+	err := handler.ImportUsers(stream)
+	assert.NoError(t, err)
+	assert.Equal(t, response.Forbidden().Code, stream.resp.Code)
+	assert.Nil(t, stream.resp.Data)
+}
+
+// fakeExportStream collects the messages sent by ExportUsers.
+type fakeExportStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*usergrpc.ExportUsersResponse
+}
+
+func (s *fakeExportStream) Context() context.Context { return s.ctx }
+
+func (s *fakeExportStream) Send(resp *usergrpc.ExportUsersResponse) error {
+	s.sent = append(s.sent, resp)
+	return nil
+}
+
+func TestGrpcHandler_ExportUsers(t *testing.T) {
+	mockUc := new(mockUsecase)
+	handler := user.NewGrpcHandler(mockUc)
+
+	stream := &fakeExportStream{ctx: context.Background()}
+	mockUc.On("ExportUsers", stream.ctx, map[string]string(nil), []string{"id", "email"}).
+		Return([]user.FindUserResponse{{Id: "u1", Name: "Alice", Email: "a@example.com"}, {Id: "u2", Email: "b@example.com"}}, response.Success(), nil)
+
+	err := handler.ExportUsers(&usergrpc.ExportUsersRequest{Fields: []string{"email", "id"}}, stream)
+	assert.NoError(t, err)
+	assert.Len(t, stream.sent, 2)
+	assert.Equal(t, "u1", stream.sent[0].Data.Id)
+	assert.Equal(t, "a@example.com", stream.sent[0].Data.Email)
+	assert.Empty(t, stream.sent[0].Data.Name)
+}
+
+func TestGrpcHandler_ExportUsers_Forbidden(t *testing.T) {
+	mockUc := new(mockUsecase)
+	handler := user.NewGrpcHandler(mockUc)
+
+	stream := &fakeExportStream{ctx: context.Background()}
+	mockUc.On("ExportUsers", stream.ctx, map[string]string(nil), user.ExportFields).Return([]user.FindUserResponse{}, response.Forbidden(), nil)
+
+	err := handler.ExportUsers(&usergrpc.ExportUsersRequest{}, stream)
+	assert.NoError(t, err)
+	assert.Len(t, stream.sent, 1)
+	assert.Equal(t, response.Forbidden().Code, stream.sent[0].Code)
+	assert.Nil(t, stream.sent[0].Data)
+}
+
+func TestGrpcHandler_Login(t *testing.T) {
+	mockUc := new(mockUsecase)
+	handler := user.NewGrpcHandler(mockUc)
+
+	ctx := context.Background()
+	expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockUc.On("Login", ctx, user.SignInRequest{Tenant: "acme", Email: "john.doe@example.com", Password: "password123"}).Return(
+		response.SuccessWithData(&user.SignInResponse{Token: "tok", ExpiresAt: expiresAt}), nil)
+
+	resp, err := handler.Login(ctx, &usergrpc.LoginRequest{Tenant: "acme", Email: "john.doe@example.com", Password: "password123"})
+	assert.NoError(t, err)
+	assert.Equal(t, "0000", resp.Code)
+	assert.Equal(t, "tok", resp.Data.Token)
+	assert.Equal(t, "2030-01-02T03:04:05Z", resp.Data.ExpiresAt)
+
+	mockUc.AssertExpectations(t)
+}
+
+func TestGrpcHandler_Login_Fail(t *testing.T) {
+	mockUc := new(mockUsecase)
+	handler := user.NewGrpcHandler(mockUc)
+
+	ctx := context.Background()
+	mockUc.On("Login", ctx, mock.AnythingOfType("user.SignInRequest")).Return(response.LoginFail(), nil)
+
+	resp, err := handler.Login(ctx, &usergrpc.LoginRequest{Email: "john.doe@example.com", Password: "wrong"})
+	assert.NoError(t, err)
+	assert.Equal(t, response.LoginFail().Code, resp.Code)
+	assert.Nil(t, resp.Data)
+
+	mockUc.AssertExpectations(t)
+}
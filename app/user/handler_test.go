@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 	"user-management/app/user"
@@ -28,13 +30,29 @@ func (m *mockUsecase) CreateUser(ctx context.Context, req user.CreateRequest) (*
 	return args.Get(0).(*response.StdResp[any]), args.Error(1)
 }
 
+func (m *mockUsecase) ImportUsers(ctx context.Context, rows []user.ImportRow, dryRun bool) (*response.StdResp[any], error) {
+	args := m.Called(ctx, rows, dryRun)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+// ExportUsers feeds the users given to Return(users, resp, err) to fn.
+func (m *mockUsecase) ExportUsers(ctx context.Context, filter map[string]string, fields []string, fn func(user.FindUserResponse) error) (*response.StdResp[any], error) {
+	args := m.Called(ctx, filter, fields)
+	for _, u := range args.Get(0).([]user.FindUserResponse) {
+		if err := fn(u); err != nil {
+			return nil, err
+		}
+	}
+	return args.Get(1).(*response.StdResp[any]), args.Error(2)
+}
+
 func (m *mockUsecase) Login(ctx context.Context, req user.SignInRequest) (*response.StdResp[any], error) {
 	args := m.Called(ctx, req)
 	return args.Get(0).(*response.StdResp[any]), args.Error(1)
 }
 
-func (m *mockUsecase) FindUsers(ctx context.Context) (*response.StdResp[any], error) {
-	args := m.Called(ctx)
+func (m *mockUsecase) FindUsers(ctx context.Context, filter map[string]string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, filter)
 	return args.Get(0).(*response.StdResp[any]), args.Error(1)
 }
 
@@ -48,11 +66,31 @@ func (m *mockUsecase) UpdateUser(ctx context.Context, user user.User) (*response
 	return args.Get(0).(*response.StdResp[any]), args.Error(1)
 }
 
+func (m *mockUsecase) ResetPassword(ctx context.Context, id, password string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, id, password)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) ChangeStatus(ctx context.Context, id string, req user.StatusRequest) (*response.StdResp[any], error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) FindStatusHistory(ctx context.Context, id string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
 func (m *mockUsecase) DeleteUser(ctx context.Context, id string) (*response.StdResp[any], error) {
 	args := m.Called(ctx, id)
 	return args.Get(0).(*response.StdResp[any]), args.Error(1)
 }
 
+func (m *mockUsecase) ReencryptUsers(ctx context.Context, dryRun bool) (*response.StdResp[any], error) {
+	args := m.Called(ctx, dryRun)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
 func TestHandlerLogin(t *testing.T) {
 	e := echo.New()
 	e.Use(middleware.NewLogging)
@@ -185,6 +223,41 @@ func TestHandlerRegister_InvalidRequest(t *testing.T) {
 	assert.Contains(t, rec.Body.String(), response.MandatoryMissing("name").Message)
 }
 
+func TestHandlerRegister_WeakPassword(t *testing.T) {
+	e := echo.New()
+	e.Use(middleware.NewLogging)
+	e.Use(middleware.LoggingMiddleware)
+	reqBody := user.CreateRequest{Name: "New", Email: "new@example.com", Password: "abc123"}
+	body, _ := json.Marshal(reqBody)
+
+	req := httptest.NewRequest(http.MethodPost, "/register", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	ctx := context.WithValue(c.Request().Context(), logger.LogContext, logger.NewZap())
+	c.SetRequest(req.WithContext(ctx))
+
+	mockUc := new(mockUsecase)
+	handler := user.NewHandler(mockUc)
+
+	mockUc.On("CreateUser", mock.Anything, reqBody).Return(response.WeakPassword([]response.Violation{
+		{Rule: user.RuleMinLength, Message: "must be at least 8 characters"},
+		{Rule: user.RuleBreached, Message: "appears in a list of breached passwords"},
+	}), nil)
+
+	err := handler.CreateUser(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{
+		"code": "4016",
+		"message": "Password does not meet the policy: min_length, breached",
+		"data": [
+			{"rule": "min_length", "message": "must be at least 8 characters"},
+			{"rule": "breached", "message": "appears in a list of breached passwords"}
+		]
+	}`, rec.Body.String())
+}
+
 func TestHandlerFindUsers(t *testing.T) {
 	e := echo.New()
 	e.Use(middleware.NewLogging)
@@ -201,7 +274,7 @@ func TestHandlerFindUsers(t *testing.T) {
 		user.FindUserResponse{Id: "60d5ec49f1f1c939b4f2f0c1", Name: "Test1", Email: "test1@example.com", CreatedAt: createdAt},
 		user.FindUserResponse{Id: "60d5ec49f1f1c939b4f2f0c2", Name: "Test2", Email: "test2@example.com", CreatedAt: createdAt},
 	}
-	mockUc.On("FindUsers", mock.Anything).Return(response.SuccessWithData(result), nil)
+	mockUc.On("FindUsers", mock.Anything, map[string]string{}).Return(response.SuccessWithData(result), nil)
 
 	err := handler.FindUsers(c)
 	assert.NoError(t, err)
@@ -209,6 +282,25 @@ func TestHandlerFindUsers(t *testing.T) {
 	// assert.Contains(t, response.SuccessWithData(result), rec.Body.String())
 }
 
+func TestHandlerFindUsers_ProfileFilter(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/users?profile.department=Sales&profile.level=3&page=1", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	ctx := context.WithValue(c.Request().Context(), logger.LogContext, logger.NewZap())
+	c.SetRequest(req.WithContext(ctx))
+	mockUc := new(mockUsecase)
+	handler := user.NewHandler(mockUc)
+	mockUc.On("FindUsers", mock.Anything, map[string]string{"department": "Sales", "level": "3"}).
+		Return(response.SuccessWithData([]user.FindUserResponse{}), nil)
+
+	err := handler.FindUsers(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUc.AssertExpectations(t)
+}
+
 func TestHandlerFindUserById_Invalid(t *testing.T) {
 	e := echo.New()
 	e.Use(middleware.NewLogging)
@@ -288,6 +380,97 @@ func TestHandlerUpdateUser(t *testing.T) {
 	assert.Contains(t, rec.Body.String(), response.Success().Message)
 }
 
+func newStatusContext(t *testing.T, method, id string, body any) (echo.Context, *httptest.ResponseRecorder) {
+	t.Helper()
+	e := echo.New()
+	var b []byte
+	if body != nil {
+		var err error
+		b, err = json.Marshal(body)
+		assert.NoError(t, err)
+	}
+	req := httptest.NewRequest(method, "/users/"+id+"/status", bytes.NewReader(b))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	ctx := context.WithValue(c.Request().Context(), logger.LogContext, logger.NewZap())
+	c.SetRequest(req.WithContext(ctx))
+	c.SetParamNames(user.ParamID)
+	c.SetParamValues(id)
+	return c, rec
+}
+
+func TestHandlerChangeStatus(t *testing.T) {
+	id := primitive.NewObjectID().Hex()
+	reqBody := user.StatusRequest{Status: user.StatusSuspended, Reason: "spam"}
+	c, rec := newStatusContext(t, http.MethodPut, id, reqBody)
+
+	mockUc := new(mockUsecase)
+	mockUc.On("ChangeStatus", mock.Anything, id, reqBody).Return(response.InvalidStatusTransition(), nil)
+
+	err := user.NewHandler(mockUc).ChangeStatus(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), response.InvalidStatusTransition().Code)
+	mockUc.AssertExpectations(t)
+}
+
+func TestHandlerChangeStatus_MissingReason(t *testing.T) {
+	id := primitive.NewObjectID().Hex()
+	c, rec := newStatusContext(t, http.MethodPut, id, user.StatusRequest{Status: user.StatusDisabled})
+
+	mockUc := new(mockUsecase)
+	err := user.NewHandler(mockUc).ChangeStatus(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), response.MandatoryMissing("reason").Message)
+	mockUc.AssertNotCalled(t, "ChangeStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandlerFindStatusHistory(t *testing.T) {
+	id := primitive.NewObjectID().Hex()
+	c, rec := newStatusContext(t, http.MethodGet, id, nil)
+
+	mockUc := new(mockUsecase)
+	mockUc.On("FindStatusHistory", mock.Anything, id).Return(response.SuccessWithData([]user.StatusChange{
+		{From: user.StatusActive, To: user.StatusSuspended, Reason: "spam"},
+	}), nil)
+
+	err := user.NewHandler(mockUc).FindStatusHistory(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"reason":"spam"`)
+}
+
+func TestHandlerUpdateUser_ProfileOnly(t *testing.T) {
+	e := echo.New()
+	validID := primitive.NewObjectID()
+	body := `{"profile": {"department": "Ops", "employee_id": null}}`
+	req := httptest.NewRequest(http.MethodPut, "/users/"+validID.Hex(), strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	ctx := context.WithValue(c.Request().Context(), logger.LogContext, logger.NewZap())
+	c.SetRequest(req.WithContext(ctx))
+	c.SetParamNames("id")
+	c.SetParamValues(validID.Hex())
+	mockUc := new(mockUsecase)
+	handler := user.NewHandler(mockUc)
+	mockUc.On("UpdateUser", mock.Anything, user.User{
+		ID:      validID,
+		Profile: map[string]any{"department": "Ops", "employee_id": nil},
+	}).Return(response.Success(), nil)
+
+	err := handler.UpdateUser(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUc.AssertExpectations(t)
+}
+
 func TestHandlerUpdateUser_InvalidId(t *testing.T) {
 	e := echo.New()
 	e.Use(middleware.NewLogging)
@@ -347,7 +530,7 @@ func TestHandlerUpdateUser_InvalidBody(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
-	assert.Contains(t, rec.Body.String(), response.MandatoryMissing("name or email").Message)
+	assert.Contains(t, rec.Body.String(), response.MandatoryMissing("name, email or profile").Message)
 }
 
 func TestHandlerDeleteUser(t *testing.T) {
@@ -394,3 +577,195 @@ func TestHandlerDeleteUser_InvalidId(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, rec.Code)
 	assert.Contains(t, rec.Body.String(), response.InvalidData(user.ParamID).Message)
 }
+
+func newImportContext(target, contentType, body string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, contentType)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	ctx := context.WithValue(c.Request().Context(), logger.LogContext, logger.NewZap())
+	c.SetRequest(req.WithContext(ctx))
+	return c, rec
+}
+
+func TestHandlerImportUsers(t *testing.T) {
+	c, rec := newImportContext("/users/import?dry_run=true", "text/csv", "email,name,password\na@example.com,Alice,pass1\n")
+
+	mockUc := new(mockUsecase)
+	rows := []user.ImportRow{{Line: 2, Request: user.CreateRequest{Name: "Alice", Email: "a@example.com", Password: "pass1"}}}
+	report := user.ImportReport{Total: 1, Succeeded: 1, DryRun: true, Rows: []user.ImportRowResult{{Line: 2, Email: "a@example.com", Status: user.ImportStatusValid}}}
+	mockUc.On("ImportUsers", mock.Anything, rows, true).Return(response.SuccessWithData(report), nil)
+
+	err := user.NewHandler(mockUc).ImportUsers(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"succeeded":1`)
+	mockUc.AssertExpectations(t)
+}
+
+func TestHandlerImportUsers_FormatQuery(t *testing.T) {
+	c, rec := newImportContext("/users/import?format=jsonl", echo.MIMETextPlain, `{"name":"Alice","email":"a@example.com","password":"pass1"}`)
+
+	mockUc := new(mockUsecase)
+	mockUc.On("ImportUsers", mock.Anything, mock.MatchedBy(func(rows []user.ImportRow) bool {
+		return len(rows) == 1 && rows[0].Request.Email == "a@example.com"
+	}), false).Return(response.SuccessWithData(user.ImportReport{Total: 1}), nil)
+
+	err := user.NewHandler(mockUc).ImportUsers(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUc.AssertExpectations(t)
+}
+
+func TestHandlerImportUsers_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      string
+		contentType string
+		body        string
+		wantMessage string
+	}{
+		{"Missing format", "/users/import", echo.MIMEApplicationJSON, "", response.MandatoryMissing(user.QueryFormat).Message},
+		{"Unknown format", "/users/import?format=xml", "text/csv", "", response.InvalidData(user.QueryFormat).Message},
+		{"Invalid dry run", "/users/import?dry_run=maybe", "text/csv", "", response.InvalidData(user.QueryDryRun).Message},
+		{"Missing email column", "/users/import", "text/csv", "name\nAlice\n", response.MandatoryMissing("email column").Message},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newImportContext(tt.target, tt.contentType, tt.body)
+			mockUc := new(mockUsecase)
+
+			err := user.NewHandler(mockUc).ImportUsers(c)
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantMessage)
+			mockUc.AssertNotCalled(t, "ImportUsers", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestHandlerExportUsers_CSV(t *testing.T) {
+	c, rec := newImportContext("/users/export?format=csv&fields=email,id", "", "")
+
+	mockUc := new(mockUsecase)
+	mockUc.On("ExportUsers", mock.Anything, map[string]string{}, []string{"id", "email"}).
+		Return([]user.FindUserResponse{{Id: "u1", Email: "a@example.com"}}, response.Success(), nil)
+
+	err := user.NewHandler(mockUc).ExportUsers(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/csv", rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, "id,email\nu1,a@example.com\n", rec.Body.String())
+}
+
+func TestHandlerExportUsers_ProfileFilter(t *testing.T) {
+	c, rec := newImportContext("/users/export?profile.department=Sales", "", "")
+
+	mockUc := new(mockUsecase)
+	mockUc.On("ExportUsers", mock.Anything, map[string]string{"department": "Sales"}, user.ExportFields).
+		Return([]user.FindUserResponse{}, response.Success(), nil)
+
+	err := user.NewHandler(mockUc).ExportUsers(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockUc.AssertExpectations(t)
+}
+
+// TestHandlerExportUsers_Middleware runs a export large enough to be flushed
+// through the logging middleware, both capturing the body and redacting it.
+func TestHandlerExportUsers_Middleware(t *testing.T) {
+	users := make([]user.FindUserResponse, 1200)
+	for i := range users {
+		users[i] = user.FindUserResponse{Id: fmt.Sprintf("u%d", i), Email: fmt.Sprintf("user%d@example.com", i)}
+	}
+	tests := []struct {
+		name    string
+		logging echo.MiddlewareFunc
+	}{
+		{"logged", middleware.LoggingMiddleware},
+		{"redacted", middleware.LoggingWithRedactions(middleware.Redactions{"GET /users/export": middleware.RedactResponse})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUc := new(mockUsecase)
+			mockUc.On("ExportUsers", mock.Anything, map[string]string{}, []string{"id", "email"}).Return(users, response.Success(), nil)
+			e := echo.New()
+			e.Use(middleware.NewLogging)
+			e.Use(tt.logging)
+			e.GET("/users/export", user.NewHandler(mockUc).ExportUsers)
+
+			req := httptest.NewRequest(http.MethodGet, "/users/export?format=csv&fields=id,email", nil)
+			rec := httptest.NewRecorder()
+			assert.NotPanics(t, func() { e.ServeHTTP(rec, req) })
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.True(t, rec.Flushed)
+			lines := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n"), "\n")
+			assert.Len(t, lines, len(users)+1)
+			assert.Equal(t, "u1199,user1199@example.com", lines[len(lines)-1])
+		})
+	}
+}
+
+func TestHandlerExportUsers_Empty(t *testing.T) {
+	c, rec := newImportContext("/users/export", "", "")
+
+	mockUc := new(mockUsecase)
+	mockUc.On("ExportUsers", mock.Anything, map[string]string{}, user.ExportFields).Return([]user.FindUserResponse{}, response.Success(), nil)
+
+	err := user.NewHandler(mockUc).ExportUsers(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/x-ndjson", rec.Header().Get(echo.HeaderContentType))
+	assert.Empty(t, rec.Body.String())
+}
+
+func TestHandlerExportUsers_Forbidden(t *testing.T) {
+	c, rec := newImportContext("/users/export", "", "")
+
+	mockUc := new(mockUsecase)
+	mockUc.On("ExportUsers", mock.Anything, map[string]string{}, user.ExportFields).Return([]user.FindUserResponse{}, response.Forbidden(), nil)
+
+	err := user.NewHandler(mockUc).ExportUsers(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestHandlerExportUsers_InvalidFields(t *testing.T) {
+	c, rec := newImportContext("/users/export?fields=password", "", "")
+
+	err := user.NewHandler(new(mockUsecase)).ExportUsers(c)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), response.InvalidData(user.QueryFields).Message)
+}
+
+func TestHandlerRegisterThenFind_MemoryRepository(t *testing.T) {
+	handler := user.NewHandler(newUsecaseWithMemory())
+	newContext := func(method, target string, body []byte) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(method, target, bytes.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+		ctx := context.WithValue(adminContext(), logger.LogContext, logger.NewZap())
+		c.SetRequest(req.WithContext(ctx))
+		return c, rec
+	}
+
+	body, _ := json.Marshal(user.CreateRequest{Name: "New", Email: "new@example.com", Password: "abc123"})
+	c, rec := newContext(http.MethodPost, "/register", body)
+	assert.NoError(t, handler.CreateUser(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var created response.StdResp[user.CreateResponse]
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+
+	c, rec = newContext(http.MethodGet, "/users/"+created.Data.Id, nil)
+	c.SetParamNames(user.ParamID)
+	c.SetParamValues(created.Data.Id)
+	assert.NoError(t, handler.FindUserById(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "new@example.com")
+	assert.NotContains(t, rec.Body.String(), "password")
+}
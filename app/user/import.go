@@ -0,0 +1,108 @@
+package user
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+)
+
+const importMaxLineBytes = 1 << 20
+
+// ParseImport reads users from a CSV file with a header row (name, email,
+// password, role, tenant_id) or from JSON lines shaped like CreateRequest.
+// Malformed lines are returned with Error set so they show up in the report.
+func ParseImport(r io.Reader, format string) ([]ImportRow, error) {
+	switch format {
+	case ImportFormatCSV:
+		return parseCSV(r)
+	case ImportFormatJSONL:
+		return parseJSONL(r)
+	}
+	return nil, ErrImportFormat
+}
+
+func parseCSV(r io.Reader) ([]ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, ErrImportHeader
+		}
+		return nil, err
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["email"]; !ok {
+		return nil, ErrImportHeader
+	}
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []ImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if len(rows) == ImportMaxRows {
+			return nil, ErrImportTooLarge
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			rows = append(rows, ImportRow{Line: parseErr.Line, Error: "Malformed CSV line"})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, ImportRow{
+			Line: line,
+			Request: CreateRequest{
+				Name:     field(record, "name"),
+				Email:    field(record, "email"),
+				Password: field(record, "password"),
+				Role:     field(record, "role"),
+				TenantID: field(record, "tenant_id"),
+			},
+		})
+	}
+}
+
+func parseJSONL(r io.Reader) ([]ImportRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), importMaxLineBytes)
+
+	var rows []ImportRow
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		if len(rows) == ImportMaxRows {
+			return nil, ErrImportTooLarge
+		}
+		var req CreateRequest
+		if err := json.Unmarshal([]byte(text), &req); err != nil {
+			rows = append(rows, ImportRow{Line: line, Error: "Malformed JSON line"})
+			continue
+		}
+		rows = append(rows, ImportRow{Line: line, Request: req})
+	}
+	return rows, scanner.Err()
+}
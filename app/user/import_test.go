@@ -0,0 +1,62 @@
+package user_test
+
+import (
+	"strings"
+	"testing"
+	"user-management/app/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseImport_CSV(t *testing.T) {
+	input := "email,name,password,role\n" +
+		"a@example.com,Alice,pass1,admin\n" +
+		"b@example.com, Bob ,pass2\n" +
+		"\"broken,line\n"
+
+	rows, err := user.ParseImport(strings.NewReader(input), user.ImportFormatCSV)
+
+	assert.NoError(t, err)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, user.ImportRow{Line: 2, Request: user.CreateRequest{Name: "Alice", Email: "a@example.com", Password: "pass1", Role: "admin"}}, rows[0])
+	assert.Equal(t, user.ImportRow{Line: 3, Request: user.CreateRequest{Name: "Bob", Email: "b@example.com", Password: "pass2"}}, rows[1])
+	assert.Equal(t, 4, rows[2].Line)
+	assert.NotEmpty(t, rows[2].Error)
+}
+
+func TestParseImport_CSVMissingEmailColumn(t *testing.T) {
+	_, err := user.ParseImport(strings.NewReader("name,password\nAlice,pass1\n"), user.ImportFormatCSV)
+
+	assert.Equal(t, user.ErrImportHeader, err)
+}
+
+func TestParseImport_JSONL(t *testing.T) {
+	input := `{"name":"Alice","email":"a@example.com","password":"pass1"}` + "\n\n" +
+		`{"name":` + "\n" +
+		`{"name":"Bob","email":"b@example.com","password":"pass2","tenant_id":"t1"}` + "\n"
+
+	rows, err := user.ParseImport(strings.NewReader(input), user.ImportFormatJSONL)
+
+	assert.NoError(t, err)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, 1, rows[0].Line)
+	assert.Equal(t, "a@example.com", rows[0].Request.Email)
+	assert.Equal(t, 3, rows[1].Line)
+	assert.NotEmpty(t, rows[1].Error)
+	assert.Equal(t, 4, rows[2].Line)
+	assert.Equal(t, "t1", rows[2].Request.TenantID)
+}
+
+func TestParseImport_TooLarge(t *testing.T) {
+	input := strings.Repeat(`{"email":"a@example.com"}`+"\n", user.ImportMaxRows+1)
+
+	_, err := user.ParseImport(strings.NewReader(input), user.ImportFormatJSONL)
+
+	assert.Equal(t, user.ErrImportTooLarge, err)
+}
+
+func TestParseImport_UnknownFormat(t *testing.T) {
+	_, err := user.ParseImport(strings.NewReader(""), "xml")
+
+	assert.Equal(t, user.ErrImportFormat, err)
+}
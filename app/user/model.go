@@ -6,15 +6,33 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// User is a stored account. Email keeps the address as the user typed it;
+// EmailNormalized (see emailaddr.Normalize), or its blind index when fields
+// are encrypted, is what lookups and the unique index use. PasswordHistory
+// holds the hashes of earlier passwords, newest first, so that they cannot be
+// reused. LastLoginAt is nil until the first
+// successful login. StatusHistory records every status change, oldest first.
+// Profile holds the custom attributes defined by the tenant's profile schema.
+// AvatarID names the user's current avatar, empty when they have none.
 type User struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Name      string             `bson:"name" json:"name"`
-	Email     string             `bson:"email" json:"email"`
-	Password  string             `bson:"password" json:"password"`
-	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TenantID        string             `bson:"tenant_id,omitempty" json:"tenant_id,omitempty"`
+	Name            string             `bson:"name" json:"name"`
+	Email           string             `bson:"email" json:"email"`
+	EmailNormalized string             `bson:"email_normalized" json:"-"`
+	Password        string             `bson:"password" json:"password"`
+	PasswordHistory []string           `bson:"password_history,omitempty" json:"-"`
+	Role            string             `bson:"role,omitempty" json:"role,omitempty"`
+	Status          string             `bson:"status,omitempty" json:"status,omitempty"`
+	StatusHistory   []StatusChange     `bson:"status_history,omitempty" json:"-"`
+	Profile         map[string]any     `bson:"profile,omitempty" json:"profile,omitempty"`
+	AvatarID        string             `bson:"avatar_id,omitempty" json:"-"`
+	CreatedAt       time.Time          `bson:"created_at" json:"created_at"`
+	LastLoginAt     *time.Time         `bson:"last_login_at,omitempty" json:"last_login_at,omitempty"`
 }
 
 type SignInRequest struct {
+	Tenant   string `json:"tenant"`
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
@@ -25,23 +43,80 @@ type SignInResponse struct {
 }
 
 type CreateRequest struct {
+	TenantID string `json:"tenant_id"`
 	Name     string `json:"name"`
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	Role     string `json:"role"`
+	// Status is active or pending; empty means active.
+	Status  string         `json:"status"`
+	Profile map[string]any `json:"profile"`
 }
 
 type CreateResponse struct {
 	Id string `json:"id"`
 }
 
+// FindUserResponse is a user as the API shows it. Avatar holds the avatar
+// URLs made from AvatarID, keyed by size.
 type FindUserResponse struct {
-	Id        string `bson:"_id" json:"id"`
-	Name      string `bson:"name" json:"name"`
-	Email     string `bson:"email" json:"email"`
-	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	Id          string            `bson:"_id" json:"id"`
+	TenantID    string            `bson:"tenant_id,omitempty" json:"tenant_id,omitempty"`
+	Name        string            `bson:"name" json:"name"`
+	Email       string            `bson:"email" json:"email"`
+	Role        string            `bson:"role,omitempty" json:"role,omitempty"`
+	Status      string            `bson:"status,omitempty" json:"status,omitempty"`
+	Profile     map[string]any    `bson:"profile,omitempty" json:"profile,omitempty"`
+	AvatarID    string            `bson:"avatar_id,omitempty" json:"-"`
+	Avatar      map[string]string `bson:"-" json:"avatar,omitempty"`
+	CreatedAt   time.Time         `bson:"created_at" json:"created_at"`
+	LastLoginAt *time.Time        `bson:"last_login_at,omitempty" json:"last_login_at,omitempty"`
 }
 
+// StatusRequest changes a user's status. Reason is required when suspending
+// or disabling.
+type StatusRequest struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// UpdateRequest changes the given fields. Profile attributes are merged into
+// the stored profile; a null value removes the attribute.
 type UpdateRequest struct {
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	Name    string         `json:"name"`
+	Email   string         `json:"email"`
+	Profile map[string]any `json:"profile"`
+}
+
+// ImportRow is one parsed line of an import file. Error is set when the line
+// could not be parsed into a CreateRequest.
+type ImportRow struct {
+	Line    int
+	Request CreateRequest
+	Error   string
+}
+
+type ImportRowResult struct {
+	Line   int    `json:"line"`
+	Email  string `json:"email,omitempty"`
+	Id     string `json:"id,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type ImportReport struct {
+	Total     int               `json:"total"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	DryRun    bool              `json:"dry_run"`
+	Rows      []ImportRowResult `json:"rows"`
+}
+
+// ReencryptReport sums up a re-encryption run: how many users were looked at
+// and how many were, or with DryRun would be, rewritten under KeyID.
+type ReencryptReport struct {
+	KeyID       string `json:"key_id"`
+	Scanned     int    `json:"scanned"`
+	Reencrypted int    `json:"reencrypted"`
+	DryRun      bool   `json:"dry_run"`
 }
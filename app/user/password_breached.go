@@ -0,0 +1,135 @@
+package user
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+// breachedSearchBlock is the span below which the binary search stops and
+// the remaining lines are scanned.
+const breachedSearchBlock = 4096
+
+// BreachedList answers k-anonymity range queries: given the first five hex
+// characters of a password's SHA-1, it returns the remaining 35 characters of
+// every breached hash with that prefix.
+type BreachedList interface {
+	Range(prefix string) ([]string, error)
+}
+
+// breachedFile serves range queries from a local, offline copy of a breached
+// password list: one "SHA1:COUNT" line per hash, sorted by hash, as published
+// by Pwned Passwords. The file is binary searched in place, so its size does
+// not matter.
+type breachedFile struct {
+	f    *os.File
+	size int64
+}
+
+func OpenBreachedFile(path string) (*breachedFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &breachedFile{f: f, size: info.Size()}, nil
+}
+
+func (b *breachedFile) Close() error {
+	return b.f.Close()
+}
+
+func (b *breachedFile) Range(prefix string) ([]string, error) {
+	prefix = strings.ToUpper(prefix)
+	lo, hi := int64(0), b.size
+	for hi-lo > breachedSearchBlock {
+		mid := lo + (hi-lo)/2
+		r := b.readerAt(mid)
+		hash, err := readBreachedLine(r)
+		if err == nil {
+			hash, err = readBreachedLine(r)
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		if err == nil && hash[:min(len(hash), len(prefix))] < prefix {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+
+	r := b.readerAt(lo)
+	if lo > 0 {
+		// lo may fall inside a line; skip to the start of the next one.
+		if _, err := readBreachedLine(r); err != nil {
+			return nil, ignoreEOF(err)
+		}
+	}
+	var suffixes []string
+	for {
+		hash, err := readBreachedLine(r)
+		if err != nil {
+			return suffixes, ignoreEOF(err)
+		}
+		head := hash[:min(len(hash), len(prefix))]
+		if head > prefix {
+			return suffixes, nil
+		}
+		if head == prefix {
+			suffixes = append(suffixes, hash[len(prefix):])
+		}
+	}
+}
+
+// readerAt returns a reader starting one byte before off, so that skipping
+// the first line read lands on the line containing off when off is already a
+// line start.
+func (b *breachedFile) readerAt(off int64) *bufio.Reader {
+	if off > 0 {
+		off--
+	}
+	return bufio.NewReader(io.NewSectionReader(b.f, off, b.size-off))
+}
+
+// readBreachedLine returns the upper-cased hash of the next line.
+func readBreachedLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", err
+	}
+	hash, _, _ := strings.Cut(strings.TrimRight(line, "\r\n"), ":")
+	return strings.ToUpper(hash), nil
+}
+
+func ignoreEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
+
+// isBreached reports whether password is in list. Only the hash prefix is
+// passed to the list.
+func isBreached(list BreachedList, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	suffixes, err := list.Range(hash[:5])
+	if err != nil {
+		return false, err
+	}
+	for _, s := range suffixes {
+		if s == hash[5:] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,152 @@
+package user
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+	"user-management/config"
+	"user-management/passhash"
+	"user-management/response"
+)
+
+// Password policy rules, as reported in response.Violation.Rule.
+const (
+	RuleMinLength    = "min_length"
+	RuleLower        = "lower"
+	RuleUpper        = "upper"
+	RuleDigit        = "digit"
+	RuleSymbol       = "symbol"
+	RulePersonalInfo = "personal_info"
+	RuleBreached     = "breached"
+	RuleReused       = "reused"
+)
+
+// personalInfoMinLength is the shortest part of a name or email that a
+// password may not contain; shorter parts would reject too much.
+const personalInfoMinLength = 3
+
+var passwordClasses = map[string]struct {
+	match   func(rune) bool
+	message string
+}{
+	RuleLower:  {unicode.IsLower, "must contain a lowercase letter"},
+	RuleUpper:  {unicode.IsUpper, "must contain an uppercase letter"},
+	RuleDigit:  {unicode.IsDigit, "must contain a digit"},
+	RuleSymbol: {isSymbol, "must contain a symbol"},
+}
+
+// PasswordPolicy checks new passwords against config.PasswordPolicyConfig.
+type PasswordPolicy struct {
+	cfg      config.PasswordPolicyConfig
+	breached BreachedList
+	close    func() error
+}
+
+func NewPasswordPolicy(cfg config.PasswordPolicyConfig) (*PasswordPolicy, error) {
+	for _, class := range cfg.RequiredClasses {
+		if _, ok := passwordClasses[class]; !ok {
+			return nil, fmt.Errorf("unknown password character class %q", class)
+		}
+	}
+	p := &PasswordPolicy{cfg: cfg, close: func() error { return nil }}
+	if cfg.BreachedFile != "" {
+		f, err := OpenBreachedFile(cfg.BreachedFile)
+		if err != nil {
+			return nil, err
+		}
+		p.breached, p.close = f, f.Close
+	}
+	return p, nil
+}
+
+// Close releases the breached password file.
+func (p *PasswordPolicy) Close() error {
+	return p.close()
+}
+
+// Check returns every rule password breaks for the user with the given name
+// and email. An error means the breached list could not be read.
+func (p *PasswordPolicy) Check(password, name, email string) ([]response.Violation, error) {
+	var violations []response.Violation
+	if utf8.RuneCountInString(password) < p.cfg.MinLength {
+		violations = append(violations, response.Violation{
+			Rule:    RuleMinLength,
+			Message: fmt.Sprintf("must be at least %d characters", p.cfg.MinLength),
+		})
+	}
+	for _, class := range p.cfg.RequiredClasses {
+		if !strings.ContainsFunc(password, passwordClasses[class].match) {
+			violations = append(violations, response.Violation{Rule: class, Message: passwordClasses[class].message})
+		}
+	}
+	if p.cfg.BanPersonalInfo && containsPersonalInfo(password, name, email) {
+		violations = append(violations, response.Violation{
+			Rule:    RulePersonalInfo,
+			Message: "must not contain the name or email",
+		})
+	}
+	if p.breached != nil {
+		breached, err := isBreached(p.breached, password)
+		if err != nil {
+			return nil, err
+		}
+		if breached {
+			violations = append(violations, response.Violation{
+				Rule:    RuleBreached,
+				Message: "appears in a list of breached passwords",
+			})
+		}
+	}
+	return violations, nil
+}
+
+// CheckReuse reports a violation when password matches the user's current
+// password or one kept in their history.
+func (p *PasswordPolicy) CheckReuse(password string, user User) *response.Violation {
+	if p.cfg.HistorySize <= 0 {
+		return nil
+	}
+	hashes := append([]string{user.Password}, user.PasswordHistory...)
+	for _, hash := range hashes[:min(len(hashes), p.cfg.HistorySize)] {
+		if passhash.Verify(hash, password) {
+			return &response.Violation{
+				Rule:    RuleReused,
+				Message: fmt.Sprintf("must not match any of the last %d passwords", p.cfg.HistorySize),
+			}
+		}
+	}
+	return nil
+}
+
+// History returns the password history to store once user's current password
+// is replaced: the current hash followed by the older ones, keeping what
+// CheckReuse needs.
+func (p *PasswordPolicy) History(user User) []string {
+	if p.cfg.HistorySize <= 1 {
+		return []string{}
+	}
+	history := append([]string{user.Password}, user.PasswordHistory...)
+	return history[:min(len(history), p.cfg.HistorySize-1)]
+}
+
+func containsPersonalInfo(password, name, email string) bool {
+	password = strings.ToLower(password)
+	local, _, _ := strings.Cut(email, "@")
+	for _, part := range append(splitWords(name), splitWords(local)...) {
+		if utf8.RuneCountInString(part) >= personalInfoMinLength && strings.Contains(password, strings.ToLower(part)) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitWords(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func isSymbol(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r)
+}
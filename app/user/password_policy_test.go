@@ -0,0 +1,149 @@
+package user_test
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"user-management/app/user"
+	"user-management/config"
+	"user-management/passhash"
+	"user-management/response"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// openPolicy accepts any password, for tests that are not about the policy.
+var openPolicy = newPolicy(config.PasswordPolicyConfig{})
+
+// testHasher uses the cheapest bcrypt cost to keep tests fast.
+var testHasher, _ = passhash.NewHasher(config.PasswordHashConfig{Algorithm: passhash.AlgorithmBcrypt, BcryptCost: bcrypt.MinCost})
+
+func newPolicy(cfg config.PasswordPolicyConfig) *user.PasswordPolicy {
+	p, err := user.NewPasswordPolicy(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// writeBreachedFile writes the passwords as a sorted "SHA1:COUNT" file, padded
+// with filler hashes so that the binary search has something to do.
+func writeBreachedFile(t *testing.T, passwords ...string) string {
+	var lines []string
+	for _, p := range passwords {
+		sum := sha1.Sum([]byte(p))
+		lines = append(lines, strings.ToUpper(hex.EncodeToString(sum[:]))+":42")
+	}
+	for i := 0; i < 2000; i++ {
+		sum := sha1.Sum([]byte{byte(i), byte(i >> 8), 'x'})
+		lines = append(lines, strings.ToUpper(hex.EncodeToString(sum[:]))+":1")
+	}
+	sort.Strings(lines)
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\r\n")+"\r\n"), 0o600))
+	return path
+}
+
+func rules(violations []response.Violation) []string {
+	var r []string
+	for _, v := range violations {
+		r = append(r, v.Rule)
+	}
+	return r
+}
+
+func TestPasswordPolicyCheck(t *testing.T) {
+	policy := newPolicy(config.PasswordPolicyConfig{
+		MinLength:       10,
+		RequiredClasses: []string{user.RuleLower, user.RuleUpper, user.RuleDigit, user.RuleSymbol},
+		BanPersonalInfo: true,
+	})
+
+	tests := []struct {
+		name     string
+		password string
+		want     []string
+	}{
+		{"Strong", "Tr0ub4dor&3x", nil},
+		{"Too short", "Tr0ub&3", []string{user.RuleMinLength}},
+		{"Length counts characters", "Tr0ub4dör&", nil},
+		{"Missing classes", "troubadoursandbards", []string{user.RuleUpper, user.RuleDigit, user.RuleSymbol}},
+		{"Contains name", "Xx-Alice-2024", []string{user.RulePersonalInfo}},
+		{"Contains email local part", "Smith.J0nes!", []string{user.RulePersonalInfo}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations, err := policy.Check(tt.password, "Alice Liddell", "jones.smith@example.com")
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, rules(violations))
+		})
+	}
+}
+
+func TestPasswordPolicyCheck_Breached(t *testing.T) {
+	policy := newPolicy(config.PasswordPolicyConfig{BreachedFile: writeBreachedFile(t, "password123", "letmein")})
+	defer policy.Close()
+
+	for _, p := range []string{"password123", "letmein"} {
+		violations, err := policy.Check(p, "", "")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{user.RuleBreached}, rules(violations), p)
+	}
+	violations, err := policy.Check("correct horse battery staple", "", "")
+	assert.NoError(t, err)
+	assert.Empty(t, violations)
+}
+
+func TestBreachedFileRange(t *testing.T) {
+	path := writeBreachedFile(t, "password123")
+	f, err := user.OpenBreachedFile(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	sum := sha1.Sum([]byte("password123"))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	suffixes, err := f.Range(strings.ToLower(hash[:5]))
+
+	assert.NoError(t, err)
+	assert.Contains(t, suffixes, hash[5:])
+	for _, s := range suffixes {
+		assert.Len(t, s, 35)
+	}
+}
+
+func TestNewPasswordPolicy_UnknownClass(t *testing.T) {
+	_, err := user.NewPasswordPolicy(config.PasswordPolicyConfig{RequiredClasses: []string{"emoji"}})
+
+	assert.Error(t, err)
+}
+
+func TestPasswordPolicyHistory(t *testing.T) {
+	policy := newPolicy(config.PasswordPolicyConfig{HistorySize: 3})
+	hash := func(p string) string {
+		h, err := testHasher.Hash(p)
+		require.NoError(t, err)
+		return h
+	}
+	u := user.User{Password: hash("current"), PasswordHistory: []string{hash("previous"), hash("older"), hash("oldest")}}
+
+	for _, p := range []string{"current", "previous", "older"} {
+		v := policy.CheckReuse(p, u)
+		if assert.NotNil(t, v, p) {
+			assert.Equal(t, user.RuleReused, v.Rule)
+		}
+	}
+	assert.Nil(t, policy.CheckReuse("oldest", u))
+	assert.Equal(t, []string{u.Password, u.PasswordHistory[0]}, policy.History(u))
+
+	off := newPolicy(config.PasswordPolicyConfig{})
+	assert.Nil(t, off.CheckReuse("current", u))
+	assert.Empty(t, off.History(u))
+}
@@ -2,8 +2,10 @@ package user
 
 import (
 	"context"
+	"errors"
 	"time"
 	"user-management/config"
+	"user-management/emailaddr"
 	"user-management/storage"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -29,6 +31,8 @@ func NewRepository(mc storage.DatabaseConn, cfg config.MongoConfig) *repository
 }
 
 func (r *repository) CreateUser(ctx context.Context, user User) (string, error) {
+	user.EmailNormalized = emailKey(user)
+	user.Status = statusOf(user.Status)
 	user.CreatedAt = time.Now()
 	ior, err := r.mc.Collection(r.cfg.UserCollection).InsertOne(ctx, user)
 	if err != nil {
@@ -40,9 +44,39 @@ func (r *repository) CreateUser(ctx context.Context, user User) (string, error)
 	return ior.InsertedID.(primitive.ObjectID).Hex(), err
 }
 
+// CreateUsers inserts users in one unordered batch so a bad row does not stop
+// the rest. It returns one error per user, nil for those that were inserted.
+func (r *repository) CreateUsers(ctx context.Context, users []User) ([]error, error) {
+	now := time.Now()
+	docs := make([]interface{}, len(users))
+	for i := range users {
+		users[i].EmailNormalized = emailKey(users[i])
+		users[i].Status = statusOf(users[i].Status)
+		users[i].CreatedAt = now
+		docs[i] = users[i]
+	}
+	errs := make([]error, len(users))
+	_, err := r.mc.Collection(r.cfg.UserCollection).InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	if err == nil {
+		return errs, nil
+	}
+	var bwe mongo.BulkWriteException
+	if !errors.As(err, &bwe) || bwe.WriteConcernError != nil || len(bwe.WriteErrors) == 0 {
+		return nil, err
+	}
+	for _, we := range bwe.WriteErrors {
+		if mongo.IsDuplicateKeyError(we.WriteError) {
+			errs[we.Index] = ErrEmailAlreadyExists
+		} else {
+			errs[we.Index] = errors.New(we.Message)
+		}
+	}
+	return errs, nil
+}
+
 func (r *repository) FindUserByEmail(ctx context.Context, email string) (User, error) {
 	var user User
-	err := r.mc.Collection(r.cfg.UserCollection).FindOne(ctx, bson.M{"email": email}).Decode(&user)
+	err := r.mc.Collection(r.cfg.UserCollection).FindOne(ctx, storage.TenantFilter(ctx, bson.M{"email_normalized": emailaddr.Normalize(email)})).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return user, ErrUserOrPasswordIsWrong
@@ -58,7 +92,7 @@ func (r *repository) FindUserById(ctx context.Context, id string) (FindUserRespo
 	if err != nil {
 		return user, err
 	}
-	err = r.mc.Collection(r.cfg.UserCollection).FindOne(ctx, bson.M{"_id": oid}).Decode(&user)
+	err = r.mc.Collection(r.cfg.UserCollection).FindOne(ctx, storage.TenantFilter(ctx, bson.M{"_id": oid})).Decode(&user)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return user, ErrUserNotFound
@@ -68,10 +102,17 @@ func (r *repository) FindUserById(ctx context.Context, id string) (FindUserRespo
 	return user, err
 }
 
-func (r *repository) FindUsers(ctx context.Context) ([]FindUserResponse, error) {
-	sort := bson.D{bson.E{Key: "created_at", Value: 1}}
-	opts := options.Find().SetSort(sort)
-	cursor, err := r.mc.Collection(r.cfg.UserCollection).Find(ctx, bson.M{}, opts)
+func (r *repository) FindUsersByIds(ctx context.Context, ids []string) ([]FindUserResponse, error) {
+	oids := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+			oids = append(oids, oid)
+		}
+	}
+	if len(oids) == 0 {
+		return nil, nil
+	}
+	cursor, err := r.findUsersCursor(ctx, bson.M{"_id": bson.M{"$in": oids}}, options.Find())
 	if err != nil {
 		return nil, err
 	}
@@ -80,6 +121,50 @@ func (r *repository) FindUsers(ctx context.Context) ([]FindUserResponse, error)
 	return users, err
 }
 
+// profileMatch is the filter for users whose profile has the given values.
+func profileMatch(profile map[string]any) bson.M {
+	filter := bson.M{}
+	for name, value := range profile {
+		filter["profile."+name] = value
+	}
+	return filter
+}
+
+func (r *repository) FindUsers(ctx context.Context, profile map[string]any) ([]FindUserResponse, error) {
+	cursor, err := r.findUsersCursor(ctx, profileMatch(profile), options.Find())
+	if err != nil {
+		return nil, err
+	}
+	var users []FindUserResponse
+	err = cursor.All(ctx, &users)
+	return users, err
+}
+
+// ExportUsers walks the same users as FindUsers one document at a time,
+// fetching only the requested fields.
+func (r *repository) ExportUsers(ctx context.Context, profile map[string]any, fields []string, fn func(FindUserResponse) error) error {
+	cursor, err := r.findUsersCursor(ctx, profileMatch(profile), options.Find().SetProjection(exportProjection(fields)))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+	for cursor.Next(ctx) {
+		var user FindUserResponse
+		if err := cursor.Decode(&user); err != nil {
+			return err
+		}
+		if err := fn(user); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func (r *repository) findUsersCursor(ctx context.Context, filter bson.M, opts *options.FindOptions) (*mongo.Cursor, error) {
+	sort := bson.D{bson.E{Key: "created_at", Value: 1}}
+	return r.mc.Collection(r.cfg.UserCollection).Find(ctx, storage.TenantFilter(ctx, filter), opts.SetSort(sort))
+}
+
 func (r *repository) UpdateUser(ctx context.Context, user User) (int64, error) {
 	updateFields := bson.M{}
 	if user.Name != "" {
@@ -87,9 +172,20 @@ func (r *repository) UpdateUser(ctx context.Context, user User) (int64, error) {
 	}
 	if user.Email != "" {
 		updateFields["email"] = user.Email
+		updateFields["email_normalized"] = emailKey(user)
+	}
+	if user.Password != "" {
+		updateFields["password"] = user.Password
+		updateFields["password_history"] = user.PasswordHistory
+	}
+	if user.Profile != nil {
+		updateFields["profile"] = user.Profile
+	}
+	if user.AvatarID != "" {
+		updateFields["avatar_id"] = user.AvatarID
 	}
 	update := bson.M{"$set": updateFields}
-	result, err := r.mc.Collection(r.cfg.UserCollection).UpdateByID(ctx, user.ID, update)
+	result, err := r.mc.Collection(r.cfg.UserCollection).UpdateOne(ctx, storage.TenantFilter(ctx, bson.M{"_id": user.ID}), update)
 	if err != nil {
 		if mongo.IsDuplicateKeyError(err) {
 			return 0, ErrEmailAlreadyExists
@@ -99,15 +195,60 @@ func (r *repository) UpdateUser(ctx context.Context, user User) (int64, error) {
 	return result.MatchedCount, nil
 }
 
+func (r *repository) RehashPassword(ctx context.Context, id primitive.ObjectID, oldHash, newHash string) (int64, error) {
+	filter := storage.TenantFilter(ctx, bson.M{"_id": id, "password": oldHash})
+	result, err := r.mc.Collection(r.cfg.UserCollection).UpdateOne(ctx, filter, bson.M{"$set": bson.M{"password": newHash}})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *repository) UpdateLastLogin(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	filter := storage.TenantFilter(ctx, bson.M{"_id": id})
+	_, err := r.mc.Collection(r.cfg.UserCollection).UpdateOne(ctx, filter, bson.M{"$set": bson.M{"last_login_at": at}})
+	return err
+}
+
+func (r *repository) UpdateStatus(ctx context.Context, id primitive.ObjectID, from string, change StatusChange) (int64, error) {
+	filter := storage.TenantFilter(ctx, bson.M{"_id": id, "status": from})
+	update := bson.M{
+		"$set":  bson.M{"status": change.To},
+		"$push": bson.M{"status_history": change},
+	}
+	result, err := r.mc.Collection(r.cfg.UserCollection).UpdateOne(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *repository) FindStatusHistory(ctx context.Context, id string) ([]StatusChange, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var user User
+	opts := options.FindOne().SetProjection(bson.M{"status_history": 1})
+	err = r.mc.Collection(r.cfg.UserCollection).FindOne(ctx, storage.TenantFilter(ctx, bson.M{"_id": oid}), opts).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user.StatusHistory, nil
+}
+
 func (r *repository) DeleteUser(ctx context.Context, id string) (int64, error) {
 	oid, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return 0, err
 	}
-	result, err := r.mc.Collection(r.cfg.UserCollection).DeleteOne(ctx, bson.M{"_id": oid})
+	result, err := r.mc.Collection(r.cfg.UserCollection).DeleteOne(ctx, storage.TenantFilter(ctx, bson.M{"_id": oid}))
 	return result.DeletedCount, err
 }
 
 func (r *repository) CountUsers(ctx context.Context) (int64, error) {
-	return r.mc.Collection(r.cfg.UserCollection).CountDocuments(ctx, bson.M{})
+	return r.mc.Collection(r.cfg.UserCollection).CountDocuments(ctx, storage.TenantFilter(ctx, bson.M{}))
 }
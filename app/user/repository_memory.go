@@ -0,0 +1,278 @@
+package user
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+	"user-management/auth"
+	"user-management/emailaddr"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryRepository keeps users in process memory. It follows the same rules as
+// the database backends (emails unique per tenant, oldest first, tenant
+// scoping) and is used for local development and as a fake in tests.
+type memoryRepository struct {
+	mu    sync.RWMutex
+	users []User
+}
+
+func NewMemoryRepository() *memoryRepository {
+	return &memoryRepository{}
+}
+
+func (r *memoryRepository) CreateUser(ctx context.Context, user User) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.insert(&user, time.Now()); err != nil {
+		return "", err
+	}
+	return user.ID.Hex(), nil
+}
+
+func (r *memoryRepository) CreateUsers(ctx context.Context, users []User) ([]error, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	errs := make([]error, len(users))
+	for i := range users {
+		errs[i] = r.insert(&users[i], now)
+	}
+	return errs, nil
+}
+
+// insert appends user, keeping users ordered by creation time. The caller
+// must hold the write lock.
+func (r *memoryRepository) insert(user *User, now time.Time) error {
+	if user.ID.IsZero() {
+		user.ID = primitive.NewObjectID()
+	}
+	user.EmailNormalized = emailKey(*user)
+	user.Status = statusOf(user.Status)
+	user.Profile = maps.Clone(user.Profile)
+	for _, u := range r.users {
+		if u.ID == user.ID || (u.TenantID == user.TenantID && u.EmailNormalized == user.EmailNormalized) {
+			return ErrEmailAlreadyExists
+		}
+	}
+	user.CreatedAt = now
+	r.users = append(r.users, *user)
+	return nil
+}
+
+func (r *memoryRepository) FindUserByEmail(ctx context.Context, email string) (User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	email = emailaddr.Normalize(email)
+	for _, u := range r.users {
+		if u.EmailNormalized == email && inTenant(ctx, u) {
+			return u, nil
+		}
+	}
+	return User{}, ErrUserOrPasswordIsWrong
+}
+
+func (r *memoryRepository) FindUserById(ctx context.Context, id string) (FindUserResponse, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return FindUserResponse{}, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if i := r.indexOf(ctx, oid); i >= 0 {
+		return toFindUserResponse(r.users[i]), nil
+	}
+	return FindUserResponse{}, ErrUserNotFound
+}
+
+func (r *memoryRepository) FindUsersByIds(ctx context.Context, ids []string) ([]FindUserResponse, error) {
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	var users []FindUserResponse
+	for _, u := range r.snapshot(ctx) {
+		if wanted[u.Id] {
+			users = append(users, u)
+		}
+	}
+	return users, nil
+}
+
+func (r *memoryRepository) FindUsers(ctx context.Context, profile map[string]any) ([]FindUserResponse, error) {
+	var users []FindUserResponse
+	for _, u := range r.snapshot(ctx) {
+		if matchProfile(u.Profile, profile) {
+			users = append(users, u)
+		}
+	}
+	return users, nil
+}
+
+// ExportUsers calls fn on a snapshot so fn may use the repository itself.
+func (r *memoryRepository) ExportUsers(ctx context.Context, profile map[string]any, fields []string, fn func(FindUserResponse) error) error {
+	for _, u := range r.snapshot(ctx) {
+		if !matchProfile(u.Profile, profile) {
+			continue
+		}
+		if err := fn(u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepository) UpdateUser(ctx context.Context, user User) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.indexOf(ctx, user.ID)
+	if i < 0 {
+		return 0, nil
+	}
+	updated := r.users[i]
+	if user.Name != "" {
+		updated.Name = user.Name
+	}
+	if user.Email != "" {
+		updated.Email = user.Email
+		updated.EmailNormalized = emailKey(user)
+	}
+	if user.Password != "" {
+		updated.Password = user.Password
+		updated.PasswordHistory = slices.Clone(user.PasswordHistory)
+	}
+	if user.Profile != nil {
+		updated.Profile = maps.Clone(user.Profile)
+	}
+	if user.AvatarID != "" {
+		updated.AvatarID = user.AvatarID
+	}
+	for j, u := range r.users {
+		if j != i && u.TenantID == updated.TenantID && u.EmailNormalized == updated.EmailNormalized {
+			return 0, ErrEmailAlreadyExists
+		}
+	}
+	r.users[i] = updated
+	return 1, nil
+}
+
+func (r *memoryRepository) RehashPassword(ctx context.Context, id primitive.ObjectID, oldHash, newHash string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.indexOf(ctx, id)
+	if i < 0 || r.users[i].Password != oldHash {
+		return 0, nil
+	}
+	r.users[i].Password = newHash
+	return 1, nil
+}
+
+func (r *memoryRepository) UpdateLastLogin(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i := r.indexOf(ctx, id); i >= 0 {
+		r.users[i].LastLoginAt = &at
+	}
+	return nil
+}
+
+func (r *memoryRepository) UpdateStatus(ctx context.Context, id primitive.ObjectID, from string, change StatusChange) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.indexOf(ctx, id)
+	if i < 0 || r.users[i].Status != from {
+		return 0, nil
+	}
+	r.users[i].Status = change.To
+	r.users[i].StatusHistory = append(slices.Clip(r.users[i].StatusHistory), change)
+	return 1, nil
+}
+
+func (r *memoryRepository) FindStatusHistory(ctx context.Context, id string) ([]StatusChange, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if i := r.indexOf(ctx, oid); i >= 0 {
+		return slices.Clone(r.users[i].StatusHistory), nil
+	}
+	return nil, ErrUserNotFound
+}
+
+func (r *memoryRepository) DeleteUser(ctx context.Context, id string) (int64, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return 0, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.indexOf(ctx, oid)
+	if i < 0 {
+		return 0, nil
+	}
+	r.users = slices.Delete(r.users, i, i+1)
+	return 1, nil
+}
+
+func (r *memoryRepository) CountUsers(ctx context.Context) (int64, error) {
+	return int64(len(r.snapshot(ctx))), nil
+}
+
+// indexOf returns the position of the user with id in ctx's tenant, or -1.
+// The caller must hold the lock.
+func (r *memoryRepository) indexOf(ctx context.Context, id primitive.ObjectID) int {
+	return slices.IndexFunc(r.users, func(u User) bool {
+		return u.ID == id && inTenant(ctx, u)
+	})
+}
+
+// snapshot copies the users visible in ctx, oldest first.
+func (r *memoryRepository) snapshot(ctx context.Context) []FindUserResponse {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var users []FindUserResponse
+	for _, u := range r.users {
+		if inTenant(ctx, u) {
+			users = append(users, toFindUserResponse(u))
+		}
+	}
+	return users
+}
+
+// matchProfile reports whether profile has every attribute of filter set to
+// the given value. Values are compared as stored, so a filter value must have
+// the Go type the schema validation gives the attribute.
+func matchProfile(profile, filter map[string]any) bool {
+	for name, value := range filter {
+		if v, ok := profile[name]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+// inTenant mirrors storage.TenantFilter for a single user.
+func inTenant(ctx context.Context, u User) bool {
+	tenantID, scoped := auth.TenantFromContext(ctx)
+	return !scoped || u.TenantID == tenantID
+}
+
+func toFindUserResponse(u User) FindUserResponse {
+	return FindUserResponse{
+		Id:          u.ID.Hex(),
+		TenantID:    u.TenantID,
+		Name:        u.Name,
+		Email:       u.Email,
+		Role:        u.Role,
+		Status:      u.Status,
+		Profile:     maps.Clone(u.Profile),
+		AvatarID:    u.AvatarID,
+		CreatedAt:   u.CreatedAt,
+		LastLoginAt: u.LastLoginAt,
+	}
+}
@@ -0,0 +1,381 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+	"user-management/auth"
+	"user-management/emailaddr"
+	"user-management/storage"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const userColumns = "id, tenant_id, name, email, role, status, profile, avatar_id, created_at, last_login_at"
+
+// sqlRepository stores users in a PostgreSQL or SQLite table. Ids keep the
+// ObjectID hex format so they look the same whichever backend is in use.
+type sqlRepository struct {
+	db      *sql.DB
+	dialect storage.Dialect
+}
+
+func NewSQLRepository(conn *storage.SQLConn) *sqlRepository {
+	return &sqlRepository{
+		db:      conn.DB,
+		dialect: conn.Dialect,
+	}
+}
+
+func (r *sqlRepository) CreateUser(ctx context.Context, user User) (string, error) {
+	if user.ID.IsZero() {
+		user.ID = primitive.NewObjectID()
+	}
+	user.CreatedAt = sqlNow()
+	profile, err := profileJSON(user.Profile)
+	if err != nil {
+		return "", err
+	}
+	_, err = r.db.ExecContext(ctx, r.dialect.Rebind("INSERT INTO users (id, tenant_id, name, email, email_normalized, password, role, status, profile, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"),
+		user.ID.Hex(), user.TenantID, user.Name, user.Email, emailKey(user), user.Password, user.Role, statusOf(user.Status), profile, user.CreatedAt)
+	if err != nil {
+		if r.dialect.IsUniqueViolation(err) {
+			return "", ErrEmailAlreadyExists
+		}
+		return "", err
+	}
+	return user.ID.Hex(), nil
+}
+
+// CreateUsers inserts users with one statement per chunk. Rows that clash with
+// an existing email are skipped by ON CONFLICT and reported as duplicates.
+func (r *sqlRepository) CreateUsers(ctx context.Context, users []User) ([]error, error) {
+	now := sqlNow()
+	errs := make([]error, len(users))
+	for start := 0; start < len(users); start += importChunkSize {
+		chunk := users[start:min(start+importChunkSize, len(users))]
+		values := make([]string, len(chunk))
+		args := make([]any, 0, len(chunk)*10)
+		for i := range chunk {
+			if chunk[i].ID.IsZero() {
+				chunk[i].ID = primitive.NewObjectID()
+			}
+			chunk[i].CreatedAt = now
+			chunk[i].EmailNormalized = emailKey(chunk[i])
+			chunk[i].Status = statusOf(chunk[i].Status)
+			profile, err := profileJSON(chunk[i].Profile)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+			args = append(args, chunk[i].ID.Hex(), chunk[i].TenantID, chunk[i].Name, chunk[i].Email, chunk[i].EmailNormalized, chunk[i].Password, chunk[i].Role, chunk[i].Status, profile, now)
+		}
+		query := "INSERT INTO users (id, tenant_id, name, email, email_normalized, password, role, status, profile, created_at) VALUES " +
+			strings.Join(values, ", ") + " ON CONFLICT DO NOTHING RETURNING id"
+		rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), args...)
+		if err != nil {
+			return nil, err
+		}
+		inserted := map[string]bool{}
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			inserted[id] = true
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		for i := range chunk {
+			if !inserted[chunk[i].ID.Hex()] {
+				errs[start+i] = ErrEmailAlreadyExists
+			}
+		}
+	}
+	return errs, nil
+}
+
+func (r *sqlRepository) FindUserByEmail(ctx context.Context, email string) (User, error) {
+	var user User
+	var id string
+	where, args := tenantWhere(ctx, "email_normalized = ?", emailaddr.Normalize(email))
+	var history string
+	row := r.db.QueryRowContext(ctx, r.dialect.Rebind("SELECT id, tenant_id, name, email, email_normalized, password, password_history, role, status, created_at FROM users"+where), args...)
+	err := row.Scan(&id, &user.TenantID, &user.Name, &user.Email, &user.EmailNormalized, &user.Password, &history, &user.Role, &user.Status, &user.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return user, ErrUserOrPasswordIsWrong
+		}
+		return user, err
+	}
+	if err := json.Unmarshal([]byte(history), &user.PasswordHistory); err != nil {
+		return user, err
+	}
+	user.ID, err = primitive.ObjectIDFromHex(id)
+	return user, err
+}
+
+func (r *sqlRepository) FindUserById(ctx context.Context, id string) (FindUserResponse, error) {
+	var user FindUserResponse
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return user, err
+	}
+	where, args := tenantWhere(ctx, "id = ?", id)
+	err := scanUser(r.db.QueryRowContext(ctx, r.dialect.Rebind("SELECT "+userColumns+" FROM users"+where), args...), &user)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return user, ErrUserNotFound
+		}
+		return user, err
+	}
+	return user, nil
+}
+
+func (r *sqlRepository) FindUsersByIds(ctx context.Context, ids []string) ([]FindUserResponse, error) {
+	var args []any
+	for _, id := range ids {
+		if _, err := primitive.ObjectIDFromHex(id); err == nil {
+			args = append(args, id)
+		}
+	}
+	if len(args) == 0 {
+		return nil, nil
+	}
+	in := "id IN (?" + strings.Repeat(", ?", len(args)-1) + ")"
+	where, args := tenantWhere(ctx, in, args...)
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind("SELECT "+userColumns+" FROM users"+where), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var users []FindUserResponse
+	for rows.Next() {
+		var user FindUserResponse
+		if err := scanUser(rows, &user); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+func (r *sqlRepository) FindUsers(ctx context.Context, profile map[string]any) ([]FindUserResponse, error) {
+	var users []FindUserResponse
+	err := r.queryUsers(ctx, profile, func(user FindUserResponse) error {
+		users = append(users, user)
+		return nil
+	})
+	return users, err
+}
+
+// ExportUsers streams the users FindUsers would return. Every non-secret
+// column is read; the export writer picks the requested fields.
+func (r *sqlRepository) ExportUsers(ctx context.Context, profile map[string]any, fields []string, fn func(FindUserResponse) error) error {
+	return r.queryUsers(ctx, profile, fn)
+}
+
+func (r *sqlRepository) queryUsers(ctx context.Context, profile map[string]any, fn func(FindUserResponse) error) error {
+	cond, condArgs := "", []any(nil)
+	if len(profile) > 0 {
+		var err error
+		if cond, condArgs, err = r.dialect.JSONMatch("profile", profile); err != nil {
+			return err
+		}
+	}
+	where, args := tenantWhere(ctx, cond, condArgs...)
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind("SELECT "+userColumns+" FROM users"+where+" ORDER BY created_at, id"), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var user FindUserResponse
+		if err := scanUser(rows, &user); err != nil {
+			return err
+		}
+		if err := fn(user); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *sqlRepository) UpdateUser(ctx context.Context, user User) (int64, error) {
+	var sets []string
+	var args []any
+	if user.Name != "" {
+		sets, args = append(sets, "name = ?"), append(args, user.Name)
+	}
+	if user.Email != "" {
+		sets, args = append(sets, "email = ?", "email_normalized = ?"), append(args, user.Email, emailKey(user))
+	}
+	if user.Password != "" {
+		history, err := json.Marshal(user.PasswordHistory)
+		if err != nil {
+			return 0, err
+		}
+		sets, args = append(sets, "password = ?", "password_history = ?"), append(args, user.Password, string(history))
+	}
+	if user.Profile != nil {
+		profile, err := profileJSON(user.Profile)
+		if err != nil {
+			return 0, err
+		}
+		sets, args = append(sets, "profile = ?"), append(args, profile)
+	}
+	if user.AvatarID != "" {
+		sets, args = append(sets, "avatar_id = ?"), append(args, user.AvatarID)
+	}
+	if len(sets) == 0 {
+		// Nothing to change, but still report whether the user exists.
+		sets = append(sets, "id = id")
+	}
+	where, whereArgs := tenantWhere(ctx, "id = ?", user.ID.Hex())
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind("UPDATE users SET "+strings.Join(sets, ", ")+where), append(args, whereArgs...)...)
+	if err != nil {
+		if r.dialect.IsUniqueViolation(err) {
+			return 0, ErrEmailAlreadyExists
+		}
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *sqlRepository) RehashPassword(ctx context.Context, id primitive.ObjectID, oldHash, newHash string) (int64, error) {
+	where, args := tenantWhere(ctx, "id = ? AND password = ?", id.Hex(), oldHash)
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind("UPDATE users SET password = ?"+where), append([]any{newHash}, args...)...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *sqlRepository) UpdateLastLogin(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	where, args := tenantWhere(ctx, "id = ?", id.Hex())
+	_, err := r.db.ExecContext(ctx, r.dialect.Rebind("UPDATE users SET last_login_at = ?"+where), append([]any{at.UTC().Truncate(time.Microsecond)}, args...)...)
+	return err
+}
+
+// UpdateStatus appends to the JSON history in Go, so the update also checks
+// that the history is still the one read; a concurrent change that went from
+// and back to the same status cannot be lost.
+func (r *sqlRepository) UpdateStatus(ctx context.Context, id primitive.ObjectID, from string, change StatusChange) (int64, error) {
+	where, args := tenantWhere(ctx, "id = ? AND status = ?", id.Hex(), from)
+	var history string
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind("SELECT status_history FROM users"+where), args...).Scan(&history)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	var changes []StatusChange
+	if err := json.Unmarshal([]byte(history), &changes); err != nil {
+		return 0, err
+	}
+	change.ChangedAt = change.ChangedAt.UTC().Truncate(time.Microsecond)
+	updated, err := json.Marshal(append(changes, change))
+	if err != nil {
+		return 0, err
+	}
+	where, args = tenantWhere(ctx, "id = ? AND status = ? AND status_history = ?", id.Hex(), from, history)
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind("UPDATE users SET status = ?, status_history = ?"+where),
+		append([]any{change.To, string(updated)}, args...)...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *sqlRepository) FindStatusHistory(ctx context.Context, id string) ([]StatusChange, error) {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return nil, err
+	}
+	where, args := tenantWhere(ctx, "id = ?", id)
+	var history string
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind("SELECT status_history FROM users"+where), args...).Scan(&history)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	var changes []StatusChange
+	err = json.Unmarshal([]byte(history), &changes)
+	return changes, err
+}
+
+func (r *sqlRepository) DeleteUser(ctx context.Context, id string) (int64, error) {
+	if _, err := primitive.ObjectIDFromHex(id); err != nil {
+		return 0, err
+	}
+	where, args := tenantWhere(ctx, "id = ?", id)
+	result, err := r.db.ExecContext(ctx, r.dialect.Rebind("DELETE FROM users"+where), args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *sqlRepository) CountUsers(ctx context.Context) (int64, error) {
+	var count int64
+	where, args := tenantWhere(ctx, "")
+	err := r.db.QueryRowContext(ctx, r.dialect.Rebind("SELECT COUNT(*) FROM users"+where), args...).Scan(&count)
+	return count, err
+}
+
+// tenantWhere builds a WHERE clause from cond and the tenant ctx is scoped to,
+// the SQL counterpart of storage.TenantFilter. Platform users are stored with
+// an empty tenant_id.
+func tenantWhere(ctx context.Context, cond string, args ...any) (string, []any) {
+	var conds []string
+	if cond != "" {
+		conds = append(conds, cond)
+	}
+	if tenantID, scoped := auth.TenantFromContext(ctx); scoped {
+		conds = append(conds, "tenant_id = ?")
+		args = append(args, tenantID)
+	}
+	if len(conds) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+func scanUser(row interface{ Scan(...any) error }, user *FindUserResponse) error {
+	var lastLoginAt sql.NullTime
+	var profile string
+	if err := row.Scan(&user.Id, &user.TenantID, &user.Name, &user.Email, &user.Role, &user.Status, &profile, &user.AvatarID, &user.CreatedAt, &lastLoginAt); err != nil {
+		return err
+	}
+	if lastLoginAt.Valid {
+		user.LastLoginAt = &lastLoginAt.Time
+	}
+	if err := json.Unmarshal([]byte(profile), &user.Profile); err != nil {
+		return err
+	}
+	if len(user.Profile) == 0 {
+		user.Profile = nil
+	}
+	return nil
+}
+
+// profileJSON encodes a profile for the profile column, which is never NULL.
+func profileJSON(profile map[string]any) (string, error) {
+	if profile == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(profile)
+	return string(b), err
+}
+
+// sqlNow returns the current time at the precision both SQL backends keep.
+func sqlNow() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
@@ -3,7 +3,9 @@ package user_test
 import (
 	"context"
 	"testing"
+	"time"
 	"user-management/app/user"
+	"user-management/auth"
 	"user-management/config"
 	"user-management/storage"
 
@@ -67,6 +69,69 @@ func TestRepository_FindUserByEmail(t *testing.T) {
 	})
 }
 
+func TestRepository_FindUserByEmail_TenantScoped(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("scoped by tenant", func(mt *mtest.T) {
+		dbConn := storage.NewMongoConn(mt.Client, mt.Client.Database("testdb"))
+		repo := user.NewRepository(dbConn, config.MongoConfig{
+			Database:       "testdb",
+			UserCollection: "users",
+		})
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(1, "testdb.users", mtest.FirstBatch, bson.D{
+			bson.E{Key: "_id", Value: primitive.NewObjectID()},
+			bson.E{Key: "tenant_id", Value: "t1"},
+			bson.E{Key: "email", Value: "test@example.com"},
+		}))
+
+		ctx := auth.WithTenant(context.Background(), "t1")
+		result, err := repo.FindUserByEmail(ctx, "test@example.com")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "t1", result.TenantID)
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		assert.Equal(t, "t1", filter.Lookup("tenant_id").StringValue())
+	})
+
+	mt.Run("matches the normalized email", func(mt *mtest.T) {
+		dbConn := storage.NewMongoConn(mt.Client, mt.Client.Database("testdb"))
+		repo := user.NewRepository(dbConn, config.MongoConfig{
+			Database:       "testdb",
+			UserCollection: "users",
+		})
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(1, "testdb.users", mtest.FirstBatch, bson.D{
+			bson.E{Key: "_id", Value: primitive.NewObjectID()},
+			bson.E{Key: "email", Value: "Test@Example.com"},
+		}))
+
+		result, err := repo.FindUserByEmail(context.Background(), " TEST@example.com")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Test@Example.com", result.Email)
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		assert.Equal(t, "test@example.com", filter.Lookup("email_normalized").StringValue())
+	})
+
+	mt.Run("platform scope", func(mt *mtest.T) {
+		dbConn := storage.NewMongoConn(mt.Client, mt.Client.Database("testdb"))
+		repo := user.NewRepository(dbConn, config.MongoConfig{
+			Database:       "testdb",
+			UserCollection: "users",
+		})
+
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.users", mtest.FirstBatch))
+
+		ctx := auth.WithTenant(context.Background(), "")
+		_, err := repo.FindUserByEmail(ctx, "test@example.com")
+
+		assert.Equal(t, user.ErrUserOrPasswordIsWrong, err)
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		assert.Equal(t, bson.TypeNull, filter.Lookup("tenant_id").Type)
+	})
+}
+
 func TestRepository_FindUserById(t *testing.T) {
 	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
 
@@ -130,7 +195,7 @@ func TestRepository_FindUsers(t *testing.T) {
 			),
 		)
 
-		result, err := repo.FindUsers(context.Background())
+		result, err := repo.FindUsers(context.Background(), nil)
 
 		assert.NoError(t, err)
 		assert.Equal(t, expectedUsers, result)
@@ -193,6 +258,35 @@ func TestRepository_UpdateUser(t *testing.T) {
 	})
 }
 
+func TestRepository_UpdateStatus(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("only from the expected status", func(mt *mtest.T) {
+		dbConn := storage.NewMongoConn(mt.Client, mt.Client.Database("testdb"))
+		repo := user.NewRepository(dbConn, config.MongoConfig{
+			Database:       "testdb",
+			UserCollection: "users",
+		})
+		mt.AddMockResponses(bson.D{
+			bson.E{Key: "ok", Value: 1},
+			bson.E{Key: "n", Value: 1},
+			bson.E{Key: "nModified", Value: 1},
+		})
+
+		count, err := repo.UpdateStatus(auth.WithTenant(context.Background(), "t1"), primitive.NewObjectID(), user.StatusActive,
+			user.StatusChange{From: user.StatusActive, To: user.StatusSuspended, Reason: "spam", ChangedAt: time.Now()})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		filter := update.Lookup("q").Document()
+		assert.Equal(t, user.StatusActive, filter.Lookup("status").StringValue())
+		assert.Equal(t, "t1", filter.Lookup("tenant_id").StringValue())
+		assert.Equal(t, user.StatusSuspended, update.Lookup("u", "$set", "status").StringValue())
+		assert.Equal(t, "spam", update.Lookup("u", "$push", "status_history", "reason").StringValue())
+	})
+}
+
 func TestRepository_DeleteUser(t *testing.T) {
 	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
 
@@ -276,3 +370,100 @@ func TestRepository_CountUsers(t *testing.T) {
 		assert.Equal(t, int64(0), count)
 	})
 }
+
+func TestRepository_CreateUsers(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("partial failure", func(mt *mtest.T) {
+		dbConn := storage.NewMongoConn(mt.Client, mt.Client.Database("testdb"))
+		repo := user.NewRepository(dbConn, config.MongoConfig{
+			Database:       "testdb",
+			UserCollection: "users",
+		})
+
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+			Index:   1,
+			Code:    11000,
+			Message: "duplicate key error",
+		}))
+		users := []user.User{
+			{ID: primitive.NewObjectID(), Email: "a@example.com"},
+			{ID: primitive.NewObjectID(), Email: "b@example.com"},
+		}
+
+		errs, err := repo.CreateUsers(context.Background(), users)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []error{nil, user.ErrEmailAlreadyExists}, errs)
+		assert.False(t, mt.GetStartedEvent().Command.Lookup("ordered").Boolean())
+	})
+
+	mt.Run("command error", func(mt *mtest.T) {
+		dbConn := storage.NewMongoConn(mt.Client, mt.Client.Database("testdb"))
+		repo := user.NewRepository(dbConn, config.MongoConfig{
+			Database:       "testdb",
+			UserCollection: "users",
+		})
+
+		mt.AddMockResponses(bson.D{
+			bson.E{Key: "ok", Value: 0},
+			bson.E{Key: "errmsg", Value: "insert error"},
+		})
+
+		errs, err := repo.CreateUsers(context.Background(), []user.User{{Email: "a@example.com"}})
+
+		assert.Error(t, err)
+		assert.Nil(t, errs)
+	})
+}
+
+func TestRepository_ExportUsers(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("streams projected users", func(mt *mtest.T) {
+		dbConn := storage.NewMongoConn(mt.Client, mt.Client.Database("testdb"))
+		repo := user.NewRepository(dbConn, config.MongoConfig{
+			Database:       "testdb",
+			UserCollection: "users",
+		})
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(1, "testdb.users", mtest.FirstBatch, bson.D{
+				bson.E{Key: "email", Value: "a@example.com"},
+			}),
+			mtest.CreateCursorResponse(0, "testdb.users", mtest.NextBatch, bson.D{
+				bson.E{Key: "email", Value: "b@example.com"},
+			}),
+		)
+
+		var emails []string
+		err := repo.ExportUsers(context.Background(), nil, []string{"email"}, func(u user.FindUserResponse) error {
+			emails = append(emails, u.Email)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a@example.com", "b@example.com"}, emails)
+		projection := mt.GetStartedEvent().Command.Lookup("projection").Document()
+		assert.Equal(t, int32(1), projection.Lookup("email").Int32())
+		assert.Equal(t, int32(0), projection.Lookup("_id").Int32())
+		_, err = projection.LookupErr("password")
+		assert.Error(t, err)
+	})
+
+	mt.Run("filters on profile attributes", func(mt *mtest.T) {
+		dbConn := storage.NewMongoConn(mt.Client, mt.Client.Database("testdb"))
+		repo := user.NewRepository(dbConn, config.MongoConfig{
+			Database:       "testdb",
+			UserCollection: "users",
+		})
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, "testdb.users", mtest.FirstBatch))
+
+		err := repo.ExportUsers(context.Background(), map[string]any{"department": "Sales"}, []string{"email"}, func(user.FindUserResponse) error {
+			return nil
+		})
+
+		assert.NoError(t, err)
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		assert.Equal(t, "Sales", filter.Lookup("profile.department").StringValue())
+	})
+}
@@ -0,0 +1,59 @@
+package user
+
+import (
+	"slices"
+	"time"
+)
+
+// Account statuses. Only active users can log in or use their tokens.
+const (
+	StatusPending   = "pending"
+	StatusActive    = "active"
+	StatusSuspended = "suspended"
+	StatusDisabled  = "disabled"
+)
+
+// statusTransitions lists the statuses each status may change to. A pending
+// account is activated or turned down; a suspended one is reinstated or
+// disabled for good unless an admin reactivates it.
+var statusTransitions = map[string][]string{
+	StatusPending:   {StatusActive, StatusDisabled},
+	StatusActive:    {StatusSuspended, StatusDisabled},
+	StatusSuspended: {StatusActive, StatusDisabled},
+	StatusDisabled:  {StatusActive},
+}
+
+// StatusChange is one entry of a user's status history.
+type StatusChange struct {
+	From      string    `bson:"from" json:"from"`
+	To        string    `bson:"to" json:"to"`
+	Reason    string    `bson:"reason,omitempty" json:"reason,omitempty"`
+	ChangedBy string    `bson:"changed_by,omitempty" json:"changed_by,omitempty"`
+	ChangedAt time.Time `bson:"changed_at" json:"changed_at"`
+}
+
+// canChangeStatus reports whether an account may go from one status to
+// another.
+func canChangeStatus(from, to string) bool {
+	return slices.Contains(statusTransitions[from], to)
+}
+
+// isValidStatus reports whether status is one of the known statuses.
+func isValidStatus(status string) bool {
+	_, ok := statusTransitions[status]
+	return ok
+}
+
+// reasonRequired reports whether a change to status must say why.
+func reasonRequired(status string) bool {
+	return status == StatusSuspended || status == StatusDisabled
+}
+
+// statusOf returns status, treating accounts stored before statuses existed
+// as active.
+func statusOf(status string) string {
+	if status == "" {
+		return StatusActive
+	}
+	return status
+}
@@ -0,0 +1,63 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"user-management/config"
+	"user-management/emailaddr"
+	"user-management/fieldcrypt"
+	"user-management/storage"
+)
+
+// Store is the user storage the server and CLI run on.
+type Store interface {
+	Repository
+	CountUsersRepository
+}
+
+// NewStore returns the user repository for the configured storage backend and
+// a function that releases it. SQL backends are migrated before use; the Mongo
+// backend shares mc, which the caller keeps owning. When encCfg has keys, names
+// and emails are encrypted at rest, see NewEncryptedStore.
+func NewStore(ctx context.Context, cfg config.StorageConfig, encCfg config.EncryptionConfig, mc storage.DatabaseConn, mongoCfg config.MongoConfig) (Store, func(), error) {
+	keys, err := fieldcrypt.NewKeyring(encCfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	store, closeFn, err := newBackendStore(ctx, cfg, mc, mongoCfg)
+	if err != nil || keys == nil {
+		return store, closeFn, err
+	}
+	return NewEncryptedStore(store, keys), closeFn, nil
+}
+
+func newBackendStore(ctx context.Context, cfg config.StorageConfig, mc storage.DatabaseConn, mongoCfg config.MongoConfig) (Store, func(), error) {
+	switch cfg.Backend {
+	case "", storage.BackendMongo:
+		return NewRepository(mc, mongoCfg), func() {}, nil
+	case storage.BackendPostgres, storage.BackendSQLite:
+		conn, err := storage.OpenSQL(ctx, cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		return NewSQLRepository(conn), func() { conn.Close() }, nil
+	case storage.BackendMemory:
+		return NewMemoryRepository(), func() {}, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+	}
+}
+
+// emailKey returns what user is looked up and kept unique by: EmailNormalized
+// when the caller already set it, as the encrypted store does with its blind
+// index, or else the normalized email. Every write goes through it, and the
+// unique indexes of all backends rely on it rather than on a collation: the
+// key is normalized either way, so callers cannot store a key that differs
+// from another only in case or spacing. Blind indexes are lowercase hex,
+// which normalization leaves as it is.
+func emailKey(user User) string {
+	if user.EmailNormalized != "" {
+		return emailaddr.Normalize(user.EmailNormalized)
+	}
+	return emailaddr.Normalize(user.Email)
+}
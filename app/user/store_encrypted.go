@@ -0,0 +1,238 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"user-management/auth"
+	"user-management/emailaddr"
+	"user-management/fieldcrypt"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Reencrypter is implemented by stores that encrypt user fields.
+type Reencrypter interface {
+	// Reencrypt rewrites the fields that are stored in plaintext or under
+	// another key than the active one. With dryRun it only counts them.
+	Reencrypt(ctx context.Context, dryRun bool) (ReencryptReport, error)
+}
+
+// encryptedStore encrypts names and emails before they reach the wrapped store
+// and decrypts them on the way back. Emails are looked up and kept unique
+// through a blind index of the normalized address, kept in EmailNormalized.
+type encryptedStore struct {
+	Store
+	keys *fieldcrypt.Keyring
+}
+
+// NewEncryptedStore wraps s so that names and emails are encrypted at rest
+// with keys. Users stored before encryption was turned on stay readable and
+// can be found by email until Reencrypt has rewritten them.
+func NewEncryptedStore(s Store, keys *fieldcrypt.Keyring) *encryptedStore {
+	return &encryptedStore{Store: s, keys: keys}
+}
+
+func (s *encryptedStore) CreateUser(ctx context.Context, user User) (string, error) {
+	taken, err := s.takenByLegacyUser(ctx, user)
+	if err != nil {
+		return "", err
+	}
+	if taken {
+		return "", ErrEmailAlreadyExists
+	}
+	encrypted, err := s.encrypt(user)
+	if err != nil {
+		return "", err
+	}
+	return s.Store.CreateUser(ctx, encrypted)
+}
+
+func (s *encryptedStore) CreateUsers(ctx context.Context, users []User) ([]error, error) {
+	errs := make([]error, len(users))
+	var encrypted []User
+	var pending []int
+	for i, user := range users {
+		taken, err := s.takenByLegacyUser(ctx, user)
+		if err != nil {
+			return nil, err
+		}
+		if taken {
+			errs[i] = ErrEmailAlreadyExists
+			continue
+		}
+		e, err := s.encrypt(user)
+		if err != nil {
+			return nil, err
+		}
+		encrypted, pending = append(encrypted, e), append(pending, i)
+	}
+	if len(encrypted) == 0 {
+		return errs, nil
+	}
+	created, err := s.Store.CreateUsers(ctx, encrypted)
+	if err != nil {
+		return nil, err
+	}
+	for j, i := range pending {
+		errs[i] = created[j]
+	}
+	return errs, nil
+}
+
+// takenByLegacyUser reports whether another user, stored before encryption
+// was turned on, already has user's email in user's tenant. Such users are
+// keyed by their plaintext address, which the unique index cannot compare with
+// a blind index, until Reencrypt has rewritten them.
+func (s *encryptedStore) takenByLegacyUser(ctx context.Context, user User) (bool, error) {
+	found, err := s.Store.FindUserByEmail(auth.WithTenant(ctx, user.TenantID), user.Email)
+	if errors.Is(err, ErrUserOrPasswordIsWrong) {
+		return false, nil
+	}
+	return err == nil && found.ID != user.ID, err
+}
+
+func (s *encryptedStore) FindUserByEmail(ctx context.Context, email string) (User, error) {
+	// Blind indexes are lowercase hex, which the wrapped store's own
+	// normalization leaves as it is.
+	user, err := s.Store.FindUserByEmail(ctx, s.keys.BlindIndex(emailaddr.Normalize(email)))
+	if errors.Is(err, ErrUserOrPasswordIsWrong) {
+		user, err = s.Store.FindUserByEmail(ctx, email)
+	}
+	if err != nil {
+		return user, err
+	}
+	return user, s.decrypt(&user.Name, &user.Email)
+}
+
+func (s *encryptedStore) FindUserById(ctx context.Context, id string) (FindUserResponse, error) {
+	user, err := s.Store.FindUserById(ctx, id)
+	if err != nil {
+		return user, err
+	}
+	return user, s.decrypt(&user.Name, &user.Email)
+}
+
+func (s *encryptedStore) FindUsersByIds(ctx context.Context, ids []string) ([]FindUserResponse, error) {
+	users, err := s.Store.FindUsersByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range users {
+		if err := s.decrypt(&users[i].Name, &users[i].Email); err != nil {
+			return nil, err
+		}
+	}
+	return users, nil
+}
+
+func (s *encryptedStore) FindUsers(ctx context.Context, profile map[string]any) ([]FindUserResponse, error) {
+	users, err := s.Store.FindUsers(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+	for i := range users {
+		if err := s.decrypt(&users[i].Name, &users[i].Email); err != nil {
+			return nil, err
+		}
+	}
+	return users, nil
+}
+
+func (s *encryptedStore) ExportUsers(ctx context.Context, profile map[string]any, fields []string, fn func(FindUserResponse) error) error {
+	return s.Store.ExportUsers(ctx, profile, fields, func(user FindUserResponse) error {
+		if err := s.decrypt(&user.Name, &user.Email); err != nil {
+			return err
+		}
+		return fn(user)
+	})
+}
+
+func (s *encryptedStore) UpdateUser(ctx context.Context, user User) (int64, error) {
+	if user.Email != "" {
+		// Updates carry no tenant; the check needs the stored one.
+		current, err := s.Store.FindUserById(ctx, user.ID.Hex())
+		if errors.Is(err, ErrUserNotFound) {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		check := user
+		check.TenantID = current.TenantID
+		taken, err := s.takenByLegacyUser(ctx, check)
+		if err != nil {
+			return 0, err
+		}
+		if taken {
+			return 0, ErrEmailAlreadyExists
+		}
+	}
+	encrypted, err := s.encrypt(user)
+	if err != nil {
+		return 0, err
+	}
+	return s.Store.UpdateUser(ctx, encrypted)
+}
+
+// Reencrypt rewrites every user whose name or email is in plaintext or under
+// an older key. The users to rewrite are collected first, so the wrapped store
+// is not written to while it is being read. A rename made between the two
+// steps can be overwritten with the previous name.
+func (s *encryptedStore) Reencrypt(ctx context.Context, dryRun bool) (ReencryptReport, error) {
+	report := ReencryptReport{KeyID: s.keys.ActiveKeyID(), DryRun: dryRun}
+	var stale []FindUserResponse
+	err := s.Store.ExportUsers(ctx, nil, []string{"id", "name", "email"}, func(user FindUserResponse) error {
+		report.Scanned++
+		if s.keys.NeedsReencrypt(user.Name) || s.keys.NeedsReencrypt(user.Email) {
+			stale = append(stale, user)
+		}
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+	if dryRun {
+		report.Reencrypted = len(stale)
+		return report, nil
+	}
+	for _, user := range stale {
+		oid, err := primitive.ObjectIDFromHex(user.Id)
+		if err != nil {
+			return report, err
+		}
+		if err := s.decrypt(&user.Name, &user.Email); err != nil {
+			return report, err
+		}
+		updated, err := s.UpdateUser(ctx, User{ID: oid, Name: user.Name, Email: user.Email})
+		if err != nil {
+			return report, err
+		}
+		report.Reencrypted += int(updated)
+	}
+	return report, nil
+}
+
+// encrypt returns user with its name and email encrypted and its email
+// replaced by its blind index for lookups.
+func (s *encryptedStore) encrypt(user User) (User, error) {
+	var err error
+	if user.Name, err = s.keys.Encrypt(user.Name); err != nil {
+		return user, err
+	}
+	if user.Email != "" {
+		user.EmailNormalized = s.keys.BlindIndex(emailaddr.Normalize(user.Email))
+		if user.Email, err = s.keys.Encrypt(user.Email); err != nil {
+			return user, err
+		}
+	}
+	return user, nil
+}
+
+func (s *encryptedStore) decrypt(name, email *string) error {
+	var err error
+	if *name, err = s.keys.Decrypt(*name); err != nil {
+		return err
+	}
+	*email, err = s.keys.Decrypt(*email)
+	return err
+}
@@ -0,0 +1,160 @@
+package user_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"path/filepath"
+	"testing"
+	"user-management/app/user"
+	"user-management/app/user/usertest"
+	"user-management/auth"
+	"user-management/config"
+	"user-management/fieldcrypt"
+	"user-management/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encryptionConfig(keys, active string) config.EncryptionConfig {
+	key := func(b byte) string {
+		return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, 32))
+	}
+	cfg := config.EncryptionConfig{ActiveKeyID: active, BlindIndexKey: base64.StdEncoding.EncodeToString([]byte("blind-index-key-for-tests"))}
+	for i, id := range keys {
+		if i > 0 {
+			cfg.Keys += ","
+		}
+		cfg.Keys += string(id) + ":" + key(byte(id))
+	}
+	return cfg
+}
+
+func newKeyring(t *testing.T, keys, active string) *fieldcrypt.Keyring {
+	k, err := fieldcrypt.NewKeyring(encryptionConfig(keys, active))
+	require.NoError(t, err)
+	return k
+}
+
+func TestStoreConformance_EncryptedMemory(t *testing.T) {
+	usertest.RunStoreConformance(t, func(t *testing.T) user.Store {
+		return user.NewEncryptedStore(user.NewMemoryRepository(), newKeyring(t, "a", ""))
+	})
+}
+
+func TestStoreConformance_EncryptedSQLite(t *testing.T) {
+	usertest.RunStoreConformance(t, func(t *testing.T) user.Store {
+		store, closeStore, err := user.NewStore(context.Background(), config.StorageConfig{
+			Backend:        storage.BackendSQLite,
+			SQLDSN:         filepath.Join(t.TempDir(), "users.db"),
+			MigrateOnStart: true,
+		}, encryptionConfig("a", ""), nil, config.MongoConfig{})
+		require.NoError(t, err)
+		t.Cleanup(closeStore)
+		return store
+	})
+}
+
+func TestEncryptedStore_EncryptsAtRest(t *testing.T) {
+	ctx := context.Background()
+	inner := user.NewMemoryRepository()
+	store := user.NewEncryptedStore(inner, newKeyring(t, "a", ""))
+
+	id, err := store.CreateUser(ctx, user.User{Name: "Alice", Email: "Alice@Example.com"})
+	require.NoError(t, err)
+
+	raw, err := inner.FindUserById(ctx, id)
+	require.NoError(t, err)
+	assert.True(t, fieldcrypt.IsEncrypted(raw.Name))
+	assert.True(t, fieldcrypt.IsEncrypted(raw.Email))
+	_, err = inner.FindUserByEmail(ctx, "alice@example.com")
+	assert.ErrorIs(t, err, user.ErrUserOrPasswordIsWrong)
+
+	found, err := store.FindUserByEmail(ctx, "ALICE@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", found.Name)
+	assert.Equal(t, "Alice@Example.com", found.Email)
+}
+
+func TestEncryptedStore_LegacyEmailStaysUnique(t *testing.T) {
+	ctx := context.Background()
+	inner := user.NewMemoryRepository()
+	// Stored before encryption was turned on, so keyed by the plaintext address.
+	_, err := inner.CreateUser(ctx, user.User{TenantID: "t1", Name: "Alice", Email: "alice@example.com"})
+	require.NoError(t, err)
+	store := user.NewEncryptedStore(inner, newKeyring(t, "a", ""))
+
+	_, err = store.CreateUser(ctx, user.User{TenantID: "t1", Name: "Alice", Email: "Alice@Example.com"})
+	assert.ErrorIs(t, err, user.ErrEmailAlreadyExists)
+
+	errs, err := store.CreateUsers(ctx, []user.User{
+		{TenantID: "t1", Name: "Alice", Email: "ALICE@example.com"},
+		{TenantID: "t1", Name: "Bob", Email: "bob@example.com"},
+		{TenantID: "t2", Name: "Alice", Email: "alice@example.com"},
+	})
+	require.NoError(t, err)
+	require.Len(t, errs, 3)
+	assert.ErrorIs(t, errs[0], user.ErrEmailAlreadyExists)
+	assert.NoError(t, errs[1])
+	assert.NoError(t, errs[2])
+
+	bob, err := store.FindUserByEmail(auth.WithTenant(ctx, "t1"), "bob@example.com")
+	require.NoError(t, err)
+	_, err = store.UpdateUser(ctx, user.User{ID: bob.ID, Email: "Alice@example.com"})
+	assert.ErrorIs(t, err, user.ErrEmailAlreadyExists)
+
+	// The legacy user may keep, or respell, their own address.
+	alice, err := inner.FindUserByEmail(auth.WithTenant(ctx, "t1"), "alice@example.com")
+	require.NoError(t, err)
+	matched, err := store.UpdateUser(ctx, user.User{ID: alice.ID, Email: "Alice@example.com"})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, matched)
+}
+
+func TestEncryptedStore_Reencrypt(t *testing.T) {
+	ctx := context.Background()
+	inner := user.NewMemoryRepository()
+	_, err := inner.CreateUser(ctx, user.User{Name: "Legacy", Email: "legacy@example.com"})
+	require.NoError(t, err)
+	_, err = user.NewEncryptedStore(inner, newKeyring(t, "a", "")).CreateUser(ctx, user.User{Name: "Old", Email: "old@example.com"})
+	require.NoError(t, err)
+
+	keys := newKeyring(t, "ab", "b")
+	store := user.NewEncryptedStore(inner, keys)
+
+	// Plaintext users stay reachable until they are rewritten.
+	legacy, err := store.FindUserByEmail(ctx, "legacy@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "Legacy", legacy.Name)
+
+	report, err := store.Reencrypt(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, user.ReencryptReport{KeyID: "b", Scanned: 2, Reencrypted: 2, DryRun: true}, report)
+
+	report, err = store.Reencrypt(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, user.ReencryptReport{KeyID: "b", Scanned: 2, Reencrypted: 2}, report)
+
+	err = inner.ExportUsers(ctx, nil, nil, func(u user.FindUserResponse) error {
+		assert.Equal(t, "b", fieldcrypt.KeyID(u.Name))
+		assert.Equal(t, "b", fieldcrypt.KeyID(u.Email))
+		return nil
+	})
+	require.NoError(t, err)
+
+	// The old key is no longer needed.
+	store = user.NewEncryptedStore(inner, newKeyring(t, "b", ""))
+	for _, email := range []string{"legacy@example.com", "old@example.com"} {
+		_, err := store.FindUserByEmail(ctx, email)
+		assert.NoError(t, err, email)
+	}
+	report, err = store.Reencrypt(ctx, false)
+	require.NoError(t, err)
+	assert.Zero(t, report.Reencrypted)
+}
+
+func TestNewStore_InvalidEncryptionKeys(t *testing.T) {
+	_, _, err := user.NewStore(context.Background(), config.StorageConfig{Backend: storage.BackendMemory}, config.EncryptionConfig{Keys: "a:short"}, nil, config.MongoConfig{})
+	require.Error(t, err)
+}
@@ -0,0 +1,143 @@
+package user_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+	"user-management/app/user"
+	"user-management/app/user/usertest"
+	"user-management/auth"
+	"user-management/config"
+	"user-management/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestStoreConformance_Memory(t *testing.T) {
+	usertest.RunStoreConformance(t, func(t *testing.T) user.Store {
+		return user.NewMemoryRepository()
+	})
+}
+
+func TestMemoryRepository_ConcurrentCreate(t *testing.T) {
+	repo := user.NewMemoryRepository()
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	created := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := repo.CreateUser(context.Background(), user.User{Name: "Racer", Email: "race@example.com"}); err == nil {
+				mu.Lock()
+				created++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, created)
+}
+
+func TestStoreConformance_SQLite(t *testing.T) {
+	usertest.RunStoreConformance(t, func(t *testing.T) user.Store {
+		return newSQLStore(t, config.StorageConfig{
+			Backend:        storage.BackendSQLite,
+			SQLDSN:         filepath.Join(t.TempDir(), "users.db"),
+			MigrateOnStart: true,
+		})
+	})
+}
+
+// TestStoreConformance_Postgres runs against the database in
+// TEST_POSTGRES_DSN. Every subtest truncates the users table.
+func TestStoreConformance_Postgres(t *testing.T) {
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+	usertest.RunStoreConformance(t, func(t *testing.T) user.Store {
+		cfg := config.StorageConfig{Backend: storage.BackendPostgres, SQLDSN: dsn, MigrateOnStart: true}
+		conn, err := storage.OpenSQL(context.Background(), cfg)
+		require.NoError(t, err)
+		_, err = conn.DB.Exec("TRUNCATE users")
+		require.NoError(t, err)
+		conn.Close()
+		return newSQLStore(t, cfg)
+	})
+}
+
+// TestStoreConformance_Mongo runs against the server in TEST_MONGO_URI, using
+// a throwaway, freshly migrated database per subtest.
+func TestStoreConformance_Mongo(t *testing.T) {
+	uri := os.Getenv("TEST_MONGO_URI")
+	if uri == "" {
+		t.Skip("TEST_MONGO_URI is not set")
+	}
+	ctx := context.Background()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Disconnect(ctx) })
+
+	usertest.RunStoreConformance(t, func(t *testing.T) user.Store {
+		db := client.Database("usertest_" + primitive.NewObjectID().Hex())
+		t.Cleanup(func() { db.Drop(ctx) })
+		cfg := usertest.MongoConfig(t)
+		_, err := storage.NewMongoMigrator(db, storage.MongoMigrations(cfg)).Up(ctx)
+		require.NoError(t, err)
+		return user.NewRepository(storage.NewMongoConn(client, db), cfg)
+	})
+}
+
+// TestMongoMigrations_FromInitJS migrates a database set up by the old
+// mongo-init/init.js: a global unique email index and a seeded admin without
+// a role.
+func TestMongoMigrations_FromInitJS(t *testing.T) {
+	uri := os.Getenv("TEST_MONGO_URI")
+	if uri == "" {
+		t.Skip("TEST_MONGO_URI is not set")
+	}
+	ctx := context.Background()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Disconnect(ctx) })
+	db := client.Database("usertest_" + primitive.NewObjectID().Hex())
+	t.Cleanup(func() { db.Drop(ctx) })
+	cfg := usertest.MongoConfig(t)
+	users := db.Collection(cfg.UserCollection)
+	_, err = users.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "email", Value: 1}}, Options: options.Index().SetUnique(true)})
+	require.NoError(t, err)
+	_, err = users.InsertOne(ctx, bson.M{"name": "Admin", "email": "admin@example.com", "password": "hash", "createdAt": time.Now()})
+	require.NoError(t, err)
+
+	_, err = storage.NewMongoMigrator(db, storage.MongoMigrations(cfg)).Up(ctx)
+	require.NoError(t, err)
+
+	store := user.NewRepository(storage.NewMongoConn(client, db), cfg)
+	admin, err := store.FindUserByEmail(ctx, "admin@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, auth.RoleAdmin, admin.Role)
+	// Emails are unique per tenant only.
+	_, err = store.CreateUser(ctx, user.User{TenantID: primitive.NewObjectID().Hex(), Name: "A", Email: "admin@example.com", Password: "hash"})
+	assert.NoError(t, err)
+}
+
+func newSQLStore(t *testing.T, cfg config.StorageConfig) user.Store {
+	store, closeStore, err := user.NewStore(context.Background(), cfg, config.EncryptionConfig{}, nil, config.MongoConfig{})
+	require.NoError(t, err)
+	t.Cleanup(closeStore)
+	return store
+}
+
+func TestNewStore_UnknownBackend(t *testing.T) {
+	_, _, err := user.NewStore(context.Background(), config.StorageConfig{Backend: "cassandra"}, config.EncryptionConfig{}, nil, config.MongoConfig{})
+	require.Error(t, err)
+}
@@ -3,45 +3,161 @@ package user
 import (
 	"context"
 	"errors"
+	"fmt"
+	"maps"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
 	"time"
+	"user-management/app/avatar"
+	"user-management/app/loginhistory"
+	"user-management/app/profile"
+	"user-management/app/tenant"
+	"user-management/auth"
+	"user-management/clientinfo"
 	"user-management/config"
+	"user-management/emailaddr"
+	"user-management/logger"
+	"user-management/passhash"
 	"user-management/response"
 
 	jwt "github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type Repository interface {
 	CreateUser(ctx context.Context, user User) (string, error)
+	CreateUsers(ctx context.Context, users []User) ([]error, error)
 	FindUserByEmail(ctx context.Context, email string) (User, error)
 	FindUserById(ctx context.Context, id string) (FindUserResponse, error)
-	FindUsers(ctx context.Context) ([]FindUserResponse, error)
+	// FindUsersByIds returns the users with the given ids in no particular
+	// order. Ids that match no user are left out.
+	FindUsersByIds(ctx context.Context, ids []string) ([]FindUserResponse, error)
+	// FindUsers lists the users whose profile has every attribute of profile
+	// set to the given value; a nil profile lists them all.
+	FindUsers(ctx context.Context, profile map[string]any) ([]FindUserResponse, error)
+	ExportUsers(ctx context.Context, profile map[string]any, fields []string, fn func(FindUserResponse) error) error
+	// UpdateUser sets the non-empty fields of user. A non-nil Profile replaces
+	// the stored one.
 	UpdateUser(ctx context.Context, user User) (int64, error)
+	// RehashPassword replaces the user's password hash with newHash only while
+	// it is still oldHash, so a password changed meanwhile is not overwritten.
+	RehashPassword(ctx context.Context, id primitive.ObjectID, oldHash, newHash string) (int64, error)
+	UpdateLastLogin(ctx context.Context, id primitive.ObjectID, at time.Time) error
+	// UpdateStatus moves the user to change.To and appends change to the
+	// status history, but only while the status is still from.
+	UpdateStatus(ctx context.Context, id primitive.ObjectID, from string, change StatusChange) (int64, error)
+	FindStatusHistory(ctx context.Context, id string) ([]StatusChange, error)
 	DeleteUser(ctx context.Context, id string) (int64, error)
 }
 
+type TenantRepository interface {
+	FindTenantBySlug(ctx context.Context, slug string) (tenant.FindTenantResponse, error)
+}
+
+type GroupRepository interface {
+	FindGroupNamesByUser(ctx context.Context, userID string) ([]string, error)
+	RemoveUserMemberships(ctx context.Context, userID string) (int64, error)
+}
+
+type LoginRecorder interface {
+	RecordLogin(ctx context.Context, attempt loginhistory.Attempt) error
+}
+
+// ProfileSchemaRepository gives the profile schema of a tenant.
+type ProfileSchemaRepository interface {
+	FindSchema(ctx context.Context, tenantID string) (profile.Schema, error)
+}
+
+// SessionStarter records the session a login token is issued for, and ends
+// a user's sessions when the user is deleted.
+type SessionStarter interface {
+	StartSession(ctx context.Context, userID, tenantID string, expiresAt time.Time) (string, error)
+	EndSessions(ctx context.Context, userID string) (int64, error)
+}
+
 type usecase struct {
-	cfgCrypto config.CryptoCredential
-	repo      Repository
+	cfgCrypto  config.CryptoCredential
+	policy     *PasswordPolicy
+	hasher     passhash.Hasher
+	repo       Repository
+	tenantRepo TenantRepository
+	groupRepo  GroupRepository
+	logins     LoginRecorder
+	sessions   SessionStarter
+	profiles   ProfileSchemaRepository
 }
 
-func NewUsecase(cfg config.CryptoCredential, r Repository) *usecase {
+func NewUsecase(cfg config.CryptoCredential, policy *PasswordPolicy, hasher passhash.Hasher, r Repository, tr TenantRepository, gr GroupRepository, lr LoginRecorder, ss SessionStarter, pr ProfileSchemaRepository) *usecase {
 	return &usecase{
-		cfgCrypto: cfg,
-		repo:      r,
+		cfgCrypto:  cfg,
+		policy:     policy,
+		hasher:     hasher,
+		repo:       r,
+		tenantRepo: tr,
+		groupRepo:  gr,
+		logins:     lr,
+		sessions:   ss,
+		profiles:   pr,
 	}
 }
 
 func (u *usecase) CreateUser(ctx context.Context, req CreateRequest) (*response.StdResp[any], error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	// Only admins create accounts directly; everyone else joins through an invite.
+	claims, err := auth.FromContext(ctx)
+	if err != nil || !claims.IsAdmin() {
+		return response.Forbidden(), nil
+	}
+	// Callers bound to a tenant can only create users inside it; only platform
+	// callers may pick the tenant explicitly.
+	tenantID, scoped := auth.TenantFromContext(ctx)
+	if !scoped {
+		tenantID = req.TenantID
+	}
+	role := req.Role
+	if role == "" {
+		role = auth.RoleUser
+	}
+	// A scoped credential making an unscoped admin would escape its scopes.
+	if role == auth.RoleAdmin && claims.IsScoped() {
+		return response.Forbidden(), nil
+	}
+	// The REST and gRPC APIs require a password. Provisioning clients such
+	// as SCIM may leave it empty; such users sign in through an identity
+	// provider until an admin sets one.
+	if req.Password != "" {
+		violations, err := u.policy.Check(req.Password, req.Name, req.Email)
+		if err != nil {
+			return nil, err
+		}
+		if len(violations) > 0 {
+			return response.WeakPassword(violations), nil
+		}
+	}
+	schema, err := u.profiles.FindSchema(ctx, tenantID)
 	if err != nil {
 		return nil, err
 	}
+	attrs, profileViolations := schema.Validate(req.Profile)
+	if len(profileViolations) > 0 {
+		return response.InvalidProfile(profileViolations), nil
+	}
+	hashedPassword := ""
+	if req.Password != "" {
+		if hashedPassword, err = u.hasher.Hash(req.Password); err != nil {
+			return nil, err
+		}
+	}
 
 	uid, err := u.repo.CreateUser(ctx, User{
+		TenantID: tenantID,
 		Name:     req.Name,
-		Email:    req.Email,
-		Password: string(hashedPassword),
+		Email:    strings.TrimSpace(req.Email),
+		Password: hashedPassword,
+		Role:     role,
+		Status:   statusOf(req.Status),
+		Profile:  attrs,
 	})
 	if err != nil {
 		if errors.Is(err, ErrEmailAlreadyExists) {
@@ -52,24 +168,270 @@ func (u *usecase) CreateUser(ctx context.Context, req CreateRequest) (*response.
 	return response.SuccessWithData(CreateResponse{uid}), nil
 }
 
+// ImportUsers validates every row, hashes passwords with bounded workers and
+// inserts the valid rows in chunks. Row problems end up in the report; only
+// infrastructure failures are returned as errors.
+func (u *usecase) ImportUsers(ctx context.Context, rows []ImportRow, dryRun bool) (*response.StdResp[any], error) {
+	claims, err := auth.FromContext(ctx)
+	if err != nil || !claims.IsAdmin() {
+		return response.Forbidden(), nil
+	}
+	tenantID, scoped := auth.TenantFromContext(ctx)
+
+	report := ImportReport{Total: len(rows), DryRun: dryRun, Rows: make([]ImportRowResult, len(rows))}
+	fail := func(i int, msg string) {
+		report.Rows[i].Status = ImportStatusFailed
+		report.Rows[i].Error = msg
+		report.Failed++
+	}
+
+	var valid []int
+	users := make([]User, len(rows))
+	seen := map[string]int{}
+	schemas := map[string]profile.Schema{}
+	for i, row := range rows {
+		report.Rows[i].Line = row.Line
+		report.Rows[i].Email = row.Request.Email
+		if row.Error != "" {
+			fail(i, row.Error)
+			continue
+		}
+		req := row.Request
+		if scoped {
+			req.TenantID = tenantID
+		}
+		if resp := req.RequestValidation(); !resp.IsSuccess() {
+			fail(i, resp.Message)
+			continue
+		}
+		violations, err := u.policy.Check(req.Password, req.Name, req.Email)
+		if err != nil {
+			return nil, err
+		}
+		if len(violations) > 0 {
+			fail(i, response.WeakPassword(violations).Message)
+			continue
+		}
+		schema, ok := schemas[req.TenantID]
+		if !ok {
+			if schema, err = u.profiles.FindSchema(ctx, req.TenantID); err != nil {
+				return nil, err
+			}
+			schemas[req.TenantID] = schema
+		}
+		attrs, profileViolations := schema.Validate(req.Profile)
+		if len(profileViolations) > 0 {
+			fail(i, response.InvalidProfile(profileViolations).Message)
+			continue
+		}
+		key := req.TenantID + "/" + emailaddr.Normalize(req.Email)
+		if line, ok := seen[key]; ok {
+			fail(i, fmt.Sprintf("Email already used on line %d", line))
+			continue
+		}
+		seen[key] = row.Line
+
+		role := req.Role
+		if role == "" {
+			role = auth.RoleUser
+		}
+		if role == auth.RoleAdmin && claims.IsScoped() {
+			fail(i, response.Forbidden().Message)
+			continue
+		}
+		users[i] = User{
+			ID:       primitive.NewObjectID(),
+			TenantID: req.TenantID,
+			Name:     req.Name,
+			Email:    strings.TrimSpace(req.Email),
+			Password: req.Password,
+			Role:     role,
+			Status:   statusOf(req.Status),
+			Profile:  attrs,
+		}
+		valid = append(valid, i)
+	}
+
+	if dryRun {
+		for _, i := range valid {
+			report.Rows[i].Status = ImportStatusValid
+		}
+		report.Succeeded = len(valid)
+		return response.SuccessWithData(report), nil
+	}
+
+	if err := u.hashPasswords(ctx, users, valid); err != nil {
+		return nil, err
+	}
+	for start := 0; start < len(valid); start += importChunkSize {
+		chunk := valid[start:min(start+importChunkSize, len(valid))]
+		batch := make([]User, len(chunk))
+		for j, i := range chunk {
+			batch[j] = users[i]
+		}
+		errs, err := u.repo.CreateUsers(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		for j, i := range chunk {
+			switch {
+			case errs[j] == nil:
+				report.Rows[i].Status = ImportStatusCreated
+				report.Rows[i].Id = users[i].ID.Hex()
+				report.Succeeded++
+			case errors.Is(errs[j], ErrEmailAlreadyExists):
+				fail(i, response.DuplicatedRegistration().Message)
+			default:
+				fail(i, errs[j].Error())
+			}
+		}
+	}
+	return response.SuccessWithData(report), nil
+}
+
+// hashPasswords replaces the plain passwords of users[idx] with hashes.
+// Hashing is CPU bound, so the number of workers follows GOMAXPROCS.
+func (u *usecase) hashPasswords(ctx context.Context, users []User, idx []int) error {
+	jobs := make(chan int)
+	errs := make([]error, len(users))
+	var wg sync.WaitGroup
+	for w := 0; w < min(runtime.GOMAXPROCS(0), len(idx)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				users[i].Password, errs[i] = u.hasher.Hash(users[i].Password)
+			}
+		}()
+	}
+
+	var err error
+feed:
+	for _, i := range idx {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			err = ctx.Err()
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if err != nil {
+		return err
+	}
+	return errors.Join(errs...)
+}
+
+// Login checks the credentials and issues a token. Every attempt that gets as
+// far as a verdict is recorded in the login history.
 func (u *usecase) Login(ctx context.Context, req SignInRequest) (*response.StdResp[any], error) {
+	attempt := loginhistory.Attempt{Email: strings.TrimSpace(req.Email)}
+	fail := func(reason string) (*response.StdResp[any], error) {
+		attempt.Reason = reason
+		u.recordLogin(ctx, attempt)
+		return response.LoginFail(), nil
+	}
+
+	tenantID := ""
+	if req.Tenant != "" {
+		t, err := u.tenantRepo.FindTenantBySlug(ctx, req.Tenant)
+		if err != nil {
+			if errors.Is(err, tenant.ErrTenantNotFound) {
+				return fail(loginhistory.ReasonUnknownTenant)
+			}
+			return nil, err
+		}
+		if t.Disabled {
+			attempt.TenantID = t.Id
+			return fail(loginhistory.ReasonTenantDisabled)
+		}
+		tenantID = t.Id
+	}
+	ctx = auth.WithTenant(ctx, tenantID)
+	attempt.TenantID = tenantID
+
 	result, err := u.repo.FindUserByEmail(ctx, req.Email)
 	if err != nil {
 		if errors.Is(err, ErrUserOrPasswordIsWrong) {
+			return fail(loginhistory.ReasonUnknownUser)
+		}
+		return nil, err
+	}
+	attempt.UserID = result.ID.Hex()
+
+	if !passhash.Verify(result.Password, req.Password) {
+		return fail(loginhistory.ReasonWrongPassword)
+	}
+	// The password was right, so telling the caller why is safe.
+	if statusOf(result.Status) != StatusActive {
+		attempt.Reason = loginhistory.ReasonAccountInactive
+		u.recordLogin(ctx, attempt)
+		return response.AccountInactive(), nil
+	}
+	if u.hasher.NeedsRehash(result.Password) {
+		u.rehashPassword(ctx, result, req.Password)
+	}
+
+	return u.issueToken(ctx, result, attempt)
+}
+
+// LoginFederated issues a token to a user an external identity provider has
+// already authenticated, without a password. ctx must be scoped to the
+// provider's tenant. The attempt is recorded with the provider's id.
+func (u *usecase) LoginFederated(ctx context.Context, userID, provider string) (*response.StdResp[any], error) {
+	tenantID, _ := auth.TenantFromContext(ctx)
+	attempt := loginhistory.Attempt{TenantID: tenantID, UserID: userID, Provider: provider}
+	found, err := u.repo.FindUserById(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			attempt.Reason = loginhistory.ReasonUnknownUser
+			u.recordLogin(ctx, attempt)
 			return response.LoginFail(), nil
 		}
 		return nil, err
 	}
+	attempt.Email = found.Email
+	if statusOf(found.Status) != StatusActive {
+		attempt.Reason = loginhistory.ReasonAccountInactive
+		u.recordLogin(ctx, attempt)
+		return response.AccountInactive(), nil
+	}
+	oid, err := primitive.ObjectIDFromHex(found.Id)
+	if err != nil {
+		return nil, err
+	}
+	return u.issueToken(ctx, User{ID: oid, TenantID: found.TenantID, Email: found.Email, Role: found.Role}, attempt)
+}
 
-	if !ValidPassword(result.Password, req.Password) {
-		return response.LoginFail(), nil
+// issueToken starts a session for a user whose credentials were verified and
+// signs a login token for it, recording attempt as successful.
+func (u *usecase) issueToken(ctx context.Context, result User, attempt loginhistory.Attempt) (*response.StdResp[any], error) {
+	var groups []string
+	var err error
+	if u.cfgCrypto.JwtEmbedGroups {
+		groups, err = u.groupRepo.FindGroupNamesByUser(ctx, result.ID.Hex())
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	expAt := time.Now().Add(u.cfgCrypto.JwtExpireDuration)
+	sessionID, err := u.sessions.StartSession(ctx, result.ID.Hex(), result.TenantID, expAt)
+	if err != nil {
+		return nil, err
+	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256,
-		jwt.RegisteredClaims{
-			Subject:   result.Email,
-			ExpiresAt: jwt.NewNumericDate(expAt),
+		auth.Claims{
+			UserID:    result.ID.Hex(),
+			TenantID:  result.TenantID,
+			Role:      result.Role,
+			Groups:    groups,
+			SessionID: sessionID,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   result.Email,
+				ExpiresAt: jwt.NewNumericDate(expAt),
+			},
 		},
 	)
 
@@ -77,20 +439,81 @@ func (u *usecase) Login(ctx context.Context, req SignInRequest) (*response.StdRe
 	if err != nil {
 		return nil, err
 	}
+	attempt.Success = true
+	u.recordLogin(ctx, attempt)
+	// The token is already issued; a stale last login time is not worth
+	// failing the login over, so the error is only logged.
+	if err := u.repo.UpdateLastLogin(ctx, result.ID, time.Now()); err != nil {
+		if zlog, logErr := logger.FromContext(ctx); logErr == nil {
+			zlog.Sugar().Warnf("[Usecase] Update last login of user %s: %v", result.ID.Hex(), err)
+		}
+	}
 	return response.SuccessWithData(&SignInResponse{
 		Token:     signedToken,
 		ExpiresAt: expAt,
-	}), err
+	}), nil
 }
 
-func (u *usecase) FindUsers(ctx context.Context) (*response.StdResp[any], error) {
-	users, err := u.repo.FindUsers(ctx)
+// FindUsers lists users, keeping those whose profile attributes have the
+// values in filter. Only attributes that the profile schema of the caller's
+// tenant marks as indexed can be filtered on.
+func (u *usecase) FindUsers(ctx context.Context, filter map[string]string) (*response.StdResp[any], error) {
+	attrs, resp, err := u.profileFilter(ctx, filter)
+	if resp != nil || err != nil {
+		return resp, err
+	}
+	users, err := u.repo.FindUsers(ctx, attrs)
 	if err != nil {
 		return nil, err
 	}
+	for i := range users {
+		users[i].Avatar = avatar.URLs(users[i].AvatarID)
+	}
 	return response.SuccessWithData(users), nil
 }
 
+// ExportUsers streams the users FindUsers would list for filter to fn. fn is
+// only called once the caller is known to be allowed to export and the filter
+// is valid, so a non-success response means nothing was written.
+func (u *usecase) ExportUsers(ctx context.Context, filter map[string]string, fields []string, fn func(FindUserResponse) error) (*response.StdResp[any], error) {
+	if claims, err := auth.FromContext(ctx); err != nil || !claims.IsAdmin() {
+		return response.Forbidden(), nil
+	}
+	attrs, resp, err := u.profileFilter(ctx, filter)
+	if resp != nil || err != nil {
+		return resp, err
+	}
+	if err := u.repo.ExportUsers(ctx, attrs, fields, fn); err != nil {
+		return nil, err
+	}
+	return response.Success(), nil
+}
+
+// profileFilter parses filter into the profile attributes to match. Only
+// attributes that the profile schema of the caller's tenant marks as indexed
+// can be filtered on; anything else gives an error response.
+func (u *usecase) profileFilter(ctx context.Context, filter map[string]string) (map[string]any, *response.StdResp[any], error) {
+	if len(filter) == 0 {
+		return nil, nil, nil
+	}
+	tenantID, _ := auth.TenantFromContext(ctx)
+	schema, err := u.profiles.FindSchema(ctx, tenantID)
+	if err != nil {
+		return nil, nil, err
+	}
+	attrs := map[string]any{}
+	for _, name := range slices.Sorted(maps.Keys(filter)) {
+		a, ok := schema.Attribute(name)
+		if !ok || !a.Indexed {
+			return nil, response.InvalidData(QueryProfilePrefix + name), nil
+		}
+		if attrs[name], ok = a.Parse(filter[name]); !ok {
+			return nil, response.InvalidData(QueryProfilePrefix + name), nil
+		}
+	}
+	return attrs, nil, nil
+}
+
 func (u *usecase) FindUserById(ctx context.Context, id string) (*response.StdResp[any], error) {
 	user, err := u.repo.FindUserById(ctx, id)
 	if err != nil {
@@ -99,10 +522,79 @@ func (u *usecase) FindUserById(ctx context.Context, id string) (*response.StdRes
 		}
 		return nil, err
 	}
+	user.Avatar = avatar.URLs(user.AvatarID)
 	return response.SuccessWithData(user), nil
 }
 
+// SetAvatar makes avatarID the avatar of user id and returns the one it
+// replaces, so that the caller can delete its images.
+func (u *usecase) SetAvatar(ctx context.Context, id, avatarID string) (string, error) {
+	found, err := u.repo.FindUserById(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	oid, err := primitive.ObjectIDFromHex(found.Id)
+	if err != nil {
+		return "", err
+	}
+	updateCount, err := u.repo.UpdateUser(ctx, User{ID: oid, AvatarID: avatarID})
+	if err != nil {
+		return "", err
+	}
+	if updateCount == 0 {
+		return "", ErrUserNotFound
+	}
+	return found.AvatarID, nil
+}
+
+// UpdateUser changes the non-empty fields of user. Profile attributes are
+// merged into the stored ones, nil values removing them, and the result is
+// checked against the tenant's profile schema. Attributes the schema no longer
+// defines are dropped. Users may update themselves; anyone else needs an admin.
+// API keys and service clients cannot update admins.
 func (u *usecase) UpdateUser(ctx context.Context, user User) (*response.StdResp[any], error) {
+	if !adminOrSelf(ctx, user.ID.Hex()) {
+		return response.Forbidden(), nil
+	}
+	user.Email = strings.TrimSpace(user.Email)
+	var found FindUserResponse
+	if user.Profile != nil || isScoped(ctx) {
+		var err error
+		found, err = u.repo.FindUserById(ctx, user.ID.Hex())
+		if err != nil {
+			if errors.Is(err, ErrUserNotFound) {
+				return response.UserNotFound(), nil
+			}
+			return nil, err
+		}
+		if scopedOnAdmin(ctx, found) {
+			return response.Forbidden(), nil
+		}
+	}
+	if user.Profile != nil {
+		schema, err := u.profiles.FindSchema(ctx, found.TenantID)
+		if err != nil {
+			return nil, err
+		}
+		merged := map[string]any{}
+		for name, value := range found.Profile {
+			if _, ok := schema.Attribute(name); ok {
+				merged[name] = value
+			}
+		}
+		for name, value := range user.Profile {
+			if value == nil {
+				delete(merged, name)
+			} else {
+				merged[name] = value
+			}
+		}
+		attrs, violations := schema.Validate(merged)
+		if len(violations) > 0 {
+			return response.InvalidProfile(violations), nil
+		}
+		user.Profile = attrs
+	}
 	updateCount, err := u.repo.UpdateUser(ctx, user)
 	if err != nil {
 		if errors.Is(err, ErrEmailAlreadyExists) {
@@ -116,7 +608,179 @@ func (u *usecase) UpdateUser(ctx context.Context, user User) (*response.StdResp[
 	return response.Success(), err
 }
 
+// ResetPassword replaces a user's password after checking it against the
+// policy and the user's password history. API keys and service clients cannot
+// reset the passwords of admins.
+func (u *usecase) ResetPassword(ctx context.Context, id, password string) (*response.StdResp[any], error) {
+	if claims, err := auth.FromContext(ctx); err != nil || !claims.IsAdmin() {
+		return response.Forbidden(), nil
+	}
+	found, err := u.repo.FindUserById(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return response.UserNotFound(), nil
+		}
+		return nil, err
+	}
+	if scopedOnAdmin(ctx, found) {
+		return response.Forbidden(), nil
+	}
+	// FindUserById leaves out the password hashes; emails are unique within a
+	// tenant, so this finds the same user with them.
+	current, err := u.repo.FindUserByEmail(auth.WithTenant(ctx, found.TenantID), found.Email)
+	if err != nil {
+		if errors.Is(err, ErrUserOrPasswordIsWrong) {
+			return response.UserNotFound(), nil
+		}
+		return nil, err
+	}
+
+	violations, err := u.policy.Check(password, current.Name, current.Email)
+	if err != nil {
+		return nil, err
+	}
+	if v := u.policy.CheckReuse(password, current); v != nil {
+		violations = append(violations, *v)
+	}
+	if len(violations) > 0 {
+		return response.WeakPassword(violations), nil
+	}
+
+	hashedPassword, err := u.hasher.Hash(password)
+	if err != nil {
+		return nil, err
+	}
+	updateCount, err := u.repo.UpdateUser(ctx, User{
+		ID:              current.ID,
+		Password:        hashedPassword,
+		PasswordHistory: u.policy.History(current),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if updateCount == 0 {
+		return response.UserNotFound(), nil
+	}
+	return response.Success(), nil
+}
+
+// ChangeStatus moves a user to req.Status if the transition is allowed and
+// records the change. Admins cannot change their own status, so they cannot
+// lock themselves out, and API keys and service clients cannot change the
+// status of admins.
+func (u *usecase) ChangeStatus(ctx context.Context, id string, req StatusRequest) (*response.StdResp[any], error) {
+	claims, err := auth.FromContext(ctx)
+	if err != nil || !claims.IsAdmin() || claims.UserID == id {
+		return response.Forbidden(), nil
+	}
+	found, err := u.repo.FindUserById(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return response.UserNotFound(), nil
+		}
+		return nil, err
+	}
+	if scopedOnAdmin(ctx, found) {
+		return response.Forbidden(), nil
+	}
+	from := statusOf(found.Status)
+	if !canChangeStatus(from, req.Status) {
+		return response.InvalidStatusTransition(), nil
+	}
+	oid, err := primitive.ObjectIDFromHex(found.Id)
+	if err != nil {
+		return nil, err
+	}
+	updateCount, err := u.repo.UpdateStatus(ctx, oid, from, StatusChange{
+		From:      from,
+		To:        req.Status,
+		Reason:    strings.TrimSpace(req.Reason),
+		ChangedBy: claims.UserID,
+		ChangedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if updateCount == 0 {
+		// The status changed since it was read.
+		return response.InvalidStatusTransition(), nil
+	}
+	return response.Success(), nil
+}
+
+// FindStatusHistory lists a user's status changes, oldest first, for admins.
+func (u *usecase) FindStatusHistory(ctx context.Context, id string) (*response.StdResp[any], error) {
+	if claims, err := auth.FromContext(ctx); err != nil || !claims.IsAdmin() {
+		return response.Forbidden(), nil
+	}
+	history, err := u.repo.FindStatusHistory(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return response.UserNotFound(), nil
+		}
+		return nil, err
+	}
+	if history == nil {
+		history = []StatusChange{}
+	}
+	return response.SuccessWithData(history), nil
+}
+
+// UserActive reports whether the user a token was issued to still exists and
+// is active. The auth middlewares call it on every request.
+func (u *usecase) UserActive(ctx context.Context, userID string) (bool, error) {
+	found, err := u.repo.FindUserById(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return statusOf(found.Status) == StatusActive, nil
+}
+
+// ReencryptUsers rewrites the stored names and emails under the active
+// encryption key. It spans every tenant, so it is for platform admins only.
+func (u *usecase) ReencryptUsers(ctx context.Context, dryRun bool) (*response.StdResp[any], error) {
+	if claims, err := auth.FromContext(ctx); err != nil || !claims.IsPlatformAdmin() {
+		return response.Forbidden(), nil
+	}
+	re, ok := u.repo.(Reencrypter)
+	if !ok {
+		return response.EncryptionNotConfigured(), nil
+	}
+	report, err := re.Reencrypt(ctx, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	return response.SuccessWithData(report), nil
+}
+
+// DeleteUser deletes a user with their sessions and group memberships. Users
+// may delete themselves; anyone else needs an admin, and API keys and service
+// clients cannot delete admins. The user record goes last, so that a failed
+// delete can be retried.
 func (u *usecase) DeleteUser(ctx context.Context, id string) (*response.StdResp[any], error) {
+	if !adminOrSelf(ctx, id) {
+		return response.Forbidden(), nil
+	}
+	// Only users the caller can see are cleaned up.
+	found, err := u.repo.FindUserById(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return response.UserNotFound(), nil
+		}
+		return nil, err
+	}
+	if scopedOnAdmin(ctx, found) {
+		return response.Forbidden(), nil
+	}
+	if _, err := u.sessions.EndSessions(ctx, id); err != nil {
+		return nil, err
+	}
+	if _, err := u.groupRepo.RemoveUserMemberships(ctx, id); err != nil {
+		return nil, err
+	}
 	delCount, err := u.repo.DeleteUser(ctx, id)
 	if err != nil {
 		return nil, err
@@ -127,7 +791,50 @@ func (u *usecase) DeleteUser(ctx context.Context, id string) (*response.StdResp[
 	return response.Success(), nil
 }
 
-func ValidPassword(hashedPassword, plainPassword string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(plainPassword))
-	return err == nil
+// isScoped reports whether the caller is an API key or service client.
+func isScoped(ctx context.Context) bool {
+	claims, err := auth.FromContext(ctx)
+	return err == nil && claims.IsScoped()
+}
+
+// scopedOnAdmin reports whether a scoped caller is acting on an admin. Scopes
+// grant an admin's access to users, but not power over admins themselves.
+func scopedOnAdmin(ctx context.Context, target FindUserResponse) bool {
+	return isScoped(ctx) && target.Role == auth.RoleAdmin
+}
+
+// adminOrSelf reports whether the caller is an admin or the user with id.
+func adminOrSelf(ctx context.Context, id string) bool {
+	claims, err := auth.FromContext(ctx)
+	return err == nil && (claims.IsAdmin() || (claims.UserID != "" && claims.UserID == id))
+}
+
+// rehashPassword upgrades the stored hash of a user who just logged in with
+// plain to the current algorithm and parameters. Failing to do so does not
+// fail the login; the next one tries again.
+func (u *usecase) rehashPassword(ctx context.Context, user User, plain string) {
+	hashedPassword, err := u.hasher.Hash(plain)
+	if err == nil {
+		_, err = u.repo.RehashPassword(ctx, user.ID, user.Password, hashedPassword)
+	}
+	if err != nil {
+		if zlog, logErr := logger.FromContext(ctx); logErr == nil {
+			zlog.Sugar().Warnf("[Usecase] Rehash password of user %s: %v", user.ID.Hex(), err)
+		}
+	}
+}
+
+// recordLogin adds attempt, with the caller's details, to the login history.
+// Like rehashPassword, a failure is logged and does not change the outcome of
+// the login.
+func (u *usecase) recordLogin(ctx context.Context, attempt loginhistory.Attempt) {
+	client := clientinfo.FromContext(ctx)
+	attempt.IP = client.IP
+	attempt.UserAgent = client.UserAgent
+	attempt.Protocol = client.Protocol
+	if err := u.logins.RecordLogin(ctx, attempt); err != nil {
+		if zlog, logErr := logger.FromContext(ctx); logErr == nil {
+			zlog.Sugar().Warnf("[Usecase] Record login of %s: %v", attempt.Email, err)
+		}
+	}
 }
@@ -4,17 +4,27 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"testing"
 	"time"
+	"user-management/app/avatar"
+	"user-management/app/loginhistory"
+	"user-management/app/profile"
+	"user-management/app/tenant"
 	"user-management/app/user"
+	"user-management/auth"
+	"user-management/clientinfo"
 	"user-management/config"
+	"user-management/passhash"
 	"user-management/response"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -28,6 +38,12 @@ func (m *mockRepo) CreateUser(ctx context.Context, user user.User) (string, erro
 	return args.String(0), args.Error(1)
 }
 
+func (m *mockRepo) CreateUsers(ctx context.Context, users []user.User) ([]error, error) {
+	args := m.Called(ctx, users)
+	errs, _ := args.Get(0).([]error)
+	return errs, args.Error(1)
+}
+
 func (m *mockRepo) FindUserByEmail(ctx context.Context, email string) (user.User, error) {
 	args := m.Called(ctx, email)
 	return args.Get(0).(user.User), args.Error(1)
@@ -38,26 +54,148 @@ func (m *mockRepo) FindUserById(ctx context.Context, id string) (user.FindUserRe
 	return args.Get(0).(user.FindUserResponse), args.Error(1)
 }
 
-func (m *mockRepo) FindUsers(ctx context.Context) ([]user.FindUserResponse, error) {
-	args := m.Called(ctx)
+func (m *mockRepo) FindUsersByIds(ctx context.Context, ids []string) ([]user.FindUserResponse, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]user.FindUserResponse), args.Error(1)
+}
+
+func (m *mockRepo) FindUsers(ctx context.Context, profile map[string]any) ([]user.FindUserResponse, error) {
+	args := m.Called(ctx, profile)
 	return args.Get(0).([]user.FindUserResponse), args.Error(1)
 }
 
+func (m *mockRepo) ExportUsers(ctx context.Context, profile map[string]any, fields []string, fn func(user.FindUserResponse) error) error {
+	args := m.Called(ctx, profile, fields)
+	for _, u := range args.Get(0).([]user.FindUserResponse) {
+		if err := fn(u); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
 func (m *mockRepo) UpdateUser(ctx context.Context, user user.User) (int64, error) {
 	args := m.Called(ctx, user)
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *mockRepo) RehashPassword(ctx context.Context, id primitive.ObjectID, oldHash, newHash string) (int64, error) {
+	args := m.Called(ctx, id, oldHash, newHash)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockRepo) UpdateLastLogin(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *mockRepo) UpdateStatus(ctx context.Context, id primitive.ObjectID, from string, change user.StatusChange) (int64, error) {
+	args := m.Called(ctx, id, from, change)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *mockRepo) FindStatusHistory(ctx context.Context, id string) ([]user.StatusChange, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]user.StatusChange), args.Error(1)
+}
+
 func (m *mockRepo) DeleteUser(ctx context.Context, id string) (int64, error) {
 	args := m.Called(ctx, id)
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *mockRepo) CountUsers(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type mockTenantRepo struct {
+	mock.Mock
+}
+
+func (m *mockTenantRepo) FindTenantBySlug(ctx context.Context, slug string) (tenant.FindTenantResponse, error) {
+	args := m.Called(ctx, slug)
+	return args.Get(0).(tenant.FindTenantResponse), args.Error(1)
+}
+
+type mockGroupRepo struct {
+	mock.Mock
+}
+
+func (m *mockGroupRepo) FindGroupNamesByUser(ctx context.Context, userID string) ([]string, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *mockGroupRepo) RemoveUserMemberships(ctx context.Context, userID string) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// loginLog keeps recorded login attempts in memory and fails with err when set.
+type loginLog struct {
+	attempts []loginhistory.Attempt
+	err      error
+}
+
+func (l *loginLog) RecordLogin(ctx context.Context, attempt loginhistory.Attempt) error {
+	l.attempts = append(l.attempts, attempt)
+	return l.err
+}
+
+// sessionLog starts sessions numbered from 1 unless err is set, and records
+// the users whose sessions were ended.
+type sessionLog struct {
+	users []string
+	ended []string
+	err   error
+}
+
+func (s *sessionLog) StartSession(ctx context.Context, userID, tenantID string, expiresAt time.Time) (string, error) {
+	if s.err != nil {
+		return "", s.err
+	}
+	s.users = append(s.users, userID)
+	return fmt.Sprintf("session-%d", len(s.users)), nil
+}
+
+func (s *sessionLog) EndSessions(ctx context.Context, userID string) (int64, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	s.ended = append(s.ended, userID)
+	return 1, nil
+}
+
 func newUsecaseWithMock(repo *mockRepo) user.Usecase {
+	return newUsecaseWithTenantMock(repo, new(mockTenantRepo))
+}
+
+func newUsecaseWithTenantMock(repo *mockRepo, tenantRepo *mockTenantRepo) user.Usecase {
+	return user.NewUsecase(config.CryptoCredential{
+		JwtKey:            "testsecret",
+		JwtExpireDuration: time.Minute,
+	}, openPolicy, testHasher, repo, tenantRepo, new(mockGroupRepo), new(loginLog), new(sessionLog), profile.NewMemoryRepository())
+}
+
+func newUsecaseWithPolicy(repo *mockRepo, cfg config.PasswordPolicyConfig) user.Usecase {
+	return user.NewUsecase(config.CryptoCredential{
+		JwtKey:            "testsecret",
+		JwtExpireDuration: time.Minute,
+	}, newPolicy(cfg), testHasher, repo, new(mockTenantRepo), new(mockGroupRepo), new(loginLog), new(sessionLog), profile.NewMemoryRepository())
+}
+
+// newUsecaseWithMemory runs the usecase on the in-memory repository, for tests
+// that care about the result of several calls rather than the calls themselves.
+func newUsecaseWithMemory() user.Usecase {
 	return user.NewUsecase(config.CryptoCredential{
 		JwtKey:            "testsecret",
 		JwtExpireDuration: time.Minute,
-	}, repo)
+	}, openPolicy, testHasher, user.NewMemoryRepository(), new(mockTenantRepo), new(mockGroupRepo), new(loginLog), new(sessionLog), profile.NewMemoryRepository())
+}
+
+func adminContext() context.Context {
+	return auth.WithClaims(context.Background(), &auth.Claims{Role: auth.RoleAdmin})
 }
 
 func TestUsecaseCreateUser(t *testing.T) {
@@ -69,13 +207,29 @@ func TestUsecaseCreateUser(t *testing.T) {
 		return u.Email == input.Email
 	})).Return("abc123", nil)
 
-	resp, err := uc.CreateUser(context.Background(), input)
+	resp, err := uc.CreateUser(adminContext(), input)
 
 	assert.NoError(t, err)
 	assert.Equal(t, "abc123", resp.Data.(user.CreateResponse).Id)
 	repo.AssertExpectations(t)
 }
 
+func TestUsecaseCreateUser_WeakPassword(t *testing.T) {
+	repo := new(mockRepo)
+	uc := newUsecaseWithPolicy(repo, config.PasswordPolicyConfig{
+		MinLength:       8,
+		RequiredClasses: []string{user.RuleDigit},
+		BanPersonalInfo: true,
+	})
+
+	resp, err := uc.CreateUser(adminContext(), user.CreateRequest{Name: "Test", Email: "test@example.com", Password: "testing"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "4016", resp.Code)
+	assert.Equal(t, []string{user.RuleMinLength, user.RuleDigit, user.RulePersonalInfo}, rules(resp.Data.([]response.Violation)))
+	repo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+}
+
 func TestUsecaseCreateUser_DuplicatedRegistration(t *testing.T) {
 	repo := new(mockRepo)
 	uc := newUsecaseWithMock(repo)
@@ -85,21 +239,118 @@ func TestUsecaseCreateUser_DuplicatedRegistration(t *testing.T) {
 		return u.Email == input.Email
 	})).Return("", user.ErrEmailAlreadyExists)
 
-	resp, err := uc.CreateUser(context.Background(), input)
+	resp, err := uc.CreateUser(adminContext(), input)
 
 	assert.NoError(t, err)
 	assert.Equal(t, response.DuplicatedRegistration(), resp)
 	repo.AssertExpectations(t)
 }
 
+func TestUsecaseCreateUser_TenantFromClaims(t *testing.T) {
+	repo := new(mockRepo)
+	uc := newUsecaseWithMock(repo)
+
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{TenantID: "tenant-a", Role: auth.RoleAdmin})
+	input := user.CreateRequest{TenantID: "tenant-b", Name: "Test", Email: "test@example.com", Password: "pass123"}
+	repo.On("CreateUser", mock.Anything, mock.MatchedBy(func(u user.User) bool {
+		return u.TenantID == "tenant-a" && u.Role == auth.RoleUser
+	})).Return("abc123", nil)
+
+	resp, err := uc.CreateUser(ctx, input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "abc123", resp.Data.(user.CreateResponse).Id)
+	repo.AssertExpectations(t)
+}
+
+func TestUsecaseCreateUser_Forbidden(t *testing.T) {
+	repo := new(mockRepo)
+	uc := newUsecaseWithMock(repo)
+
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{TenantID: "tenant-a", Role: auth.RoleUser})
+	input := user.CreateRequest{Name: "Test", Email: "test@example.com", Password: "pass123"}
+
+	resp, err := uc.CreateUser(ctx, input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.Forbidden(), resp)
+	repo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+}
+
+func TestUsecaseCreateUser_ScopedCannotCreateAdmin(t *testing.T) {
+	repo := new(mockRepo)
+	uc := newUsecaseWithMock(repo)
+
+	// A platform API key: an admin without a tenant, limited to users:write.
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{Role: auth.RoleAdmin, Scopes: []string{auth.ScopeUsersWrite}})
+	input := user.CreateRequest{Name: "Test", Email: "test@example.com", Password: "pass123", Role: auth.RoleAdmin}
+
+	resp, err := uc.CreateUser(ctx, input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.Forbidden(), resp)
+	repo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+}
+
+func TestUsecase_ScopedCannotManageAdmins(t *testing.T) {
+	groups := new(mockGroupRepo)
+	groups.On("RemoveUserMemberships", mock.Anything, mock.Anything).Return(int64(0), nil)
+	uc := user.NewUsecase(config.CryptoCredential{JwtKey: "testsecret", JwtExpireDuration: time.Minute},
+		openPolicy, testHasher, user.NewMemoryRepository(), new(mockTenantRepo), groups, new(loginLog), new(sessionLog), profile.NewMemoryRepository())
+	create := func(email, role string) string {
+		resp, err := uc.CreateUser(adminContext(), user.CreateRequest{Name: "Test", Email: email, Password: "pass123", Role: role})
+		require.NoError(t, err)
+		return resp.Data.(user.CreateResponse).Id
+	}
+	// A platform API key: an admin without a tenant, limited to users:write.
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{Role: auth.RoleAdmin, Scopes: []string{auth.ScopeUsersWrite}})
+
+	tests := []struct {
+		name string
+		call func(id string) (*response.StdResp[any], error)
+	}{
+		{"update", func(id string) (*response.StdResp[any], error) {
+			oid, _ := primitive.ObjectIDFromHex(id)
+			return uc.UpdateUser(ctx, user.User{ID: oid, Email: "taken-over@example.com"})
+		}},
+		{"reset password", func(id string) (*response.StdResp[any], error) {
+			return uc.ResetPassword(ctx, id, "new-pass123")
+		}},
+		{"change status", func(id string) (*response.StdResp[any], error) {
+			return uc.ChangeStatus(ctx, id, user.StatusRequest{Status: user.StatusSuspended})
+		}},
+		{"delete", func(id string) (*response.StdResp[any], error) {
+			return uc.DeleteUser(ctx, id)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adminID := create("admin-"+strings.ReplaceAll(tt.name, " ", "-")+"@example.com", auth.RoleAdmin)
+			userID := create("user-"+strings.ReplaceAll(tt.name, " ", "-")+"@example.com", auth.RoleUser)
+
+			resp, err := tt.call(adminID)
+			require.NoError(t, err)
+			assert.Equal(t, response.Forbidden(), resp)
+			found, err := uc.FindUserById(adminContext(), adminID)
+			require.NoError(t, err)
+			assert.Equal(t, user.StatusActive, found.Data.(user.FindUserResponse).Status)
+
+			resp, err = tt.call(userID)
+			require.NoError(t, err)
+			assert.True(t, resp.IsSuccess(), resp.Message)
+		})
+	}
+}
+
 func TestUsecaseLoginSuccess(t *testing.T) {
 	repo := new(mockRepo)
 	uc := newUsecaseWithMock(repo)
 
-	hashed, _ := bcrypt.GenerateFromPassword([]byte("pass123"), bcrypt.DefaultCost)
-	userData := user.User{Email: "test@example.com", Password: string(hashed)}
+	hashed, _ := testHasher.Hash("pass123")
+	userData := user.User{Email: "test@example.com", Password: hashed}
 
 	repo.On("FindUserByEmail", mock.Anything, "test@example.com").Return(userData, nil)
+	repo.On("UpdateLastLogin", mock.Anything, userData.ID, mock.Anything).Return(nil)
 
 	resp, err := uc.Login(context.Background(), user.SignInRequest{
 		Email:    "test@example.com",
@@ -116,6 +367,104 @@ func TestUsecaseLoginSuccess(t *testing.T) {
 	repo.AssertExpectations(t)
 }
 
+func TestUsecaseLogin_LastLoginFailureIsNotFatal(t *testing.T) {
+	repo := new(mockRepo)
+	uc := newUsecaseWithMock(repo)
+
+	hashed, _ := testHasher.Hash("pass123")
+	userData := user.User{Email: "test@example.com", Password: hashed}
+	repo.On("FindUserByEmail", mock.Anything, "test@example.com").Return(userData, nil)
+	repo.On("UpdateLastLogin", mock.Anything, userData.ID, mock.Anything).Return(errors.New("db down"))
+
+	resp, err := uc.Login(context.Background(), user.SignInRequest{Email: "test@example.com", Password: "pass123"})
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.Data.(*user.SignInResponse).Token)
+	repo.AssertExpectations(t)
+}
+
+func TestUsecaseLogin_Tenant(t *testing.T) {
+	repo := new(mockRepo)
+	tenantRepo := new(mockTenantRepo)
+	uc := newUsecaseWithTenantMock(repo, tenantRepo)
+
+	hashed, _ := testHasher.Hash("pass123")
+	userData := user.User{ID: primitive.NewObjectID(), TenantID: "t1", Email: "test@example.com", Password: hashed, Role: auth.RoleAdmin}
+
+	tenantRepo.On("FindTenantBySlug", mock.Anything, "acme").Return(tenant.FindTenantResponse{Id: "t1", Slug: "acme"}, nil)
+	repo.On("FindUserByEmail", mock.MatchedBy(func(ctx context.Context) bool {
+		tenantID, scoped := auth.TenantFromContext(ctx)
+		return scoped && tenantID == "t1"
+	}), "test@example.com").Return(userData, nil)
+	repo.On("UpdateLastLogin", mock.Anything, userData.ID, mock.Anything).Return(nil)
+
+	resp, err := uc.Login(context.Background(), user.SignInRequest{
+		Tenant:   "acme",
+		Email:    "test@example.com",
+		Password: "pass123",
+	})
+
+	assert.NoError(t, err)
+	claims := &auth.Claims{}
+	_, err = jwt.ParseWithClaims(resp.Data.(*user.SignInResponse).Token, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte("testsecret"), nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "t1", claims.TenantID)
+	assert.Equal(t, auth.RoleAdmin, claims.Role)
+	assert.Equal(t, userData.ID.Hex(), claims.UserID)
+	repo.AssertExpectations(t)
+	tenantRepo.AssertExpectations(t)
+}
+
+func TestUsecaseLogin_EmbedGroups(t *testing.T) {
+	repo := new(mockRepo)
+	groupRepo := new(mockGroupRepo)
+	uc := user.NewUsecase(config.CryptoCredential{
+		JwtKey:            "testsecret",
+		JwtExpireDuration: time.Minute,
+		JwtEmbedGroups:    true,
+	}, openPolicy, testHasher, repo, new(mockTenantRepo), groupRepo, new(loginLog), new(sessionLog), profile.NewMemoryRepository())
+
+	hashed, _ := testHasher.Hash("pass123")
+	userData := user.User{ID: primitive.NewObjectID(), Email: "test@example.com", Password: hashed}
+	repo.On("FindUserByEmail", mock.Anything, "test@example.com").Return(userData, nil)
+	groupRepo.On("FindGroupNamesByUser", mock.Anything, userData.ID.Hex()).Return([]string{"engineering", "ops"}, nil)
+	repo.On("UpdateLastLogin", mock.Anything, userData.ID, mock.Anything).Return(nil)
+
+	resp, err := uc.Login(context.Background(), user.SignInRequest{
+		Email:    "test@example.com",
+		Password: "pass123",
+	})
+
+	assert.NoError(t, err)
+	claims := &auth.Claims{}
+	_, err = jwt.ParseWithClaims(resp.Data.(*user.SignInResponse).Token, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte("testsecret"), nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"engineering", "ops"}, claims.Groups)
+	groupRepo.AssertExpectations(t)
+}
+
+func TestUsecaseLogin_DisabledTenant(t *testing.T) {
+	repo := new(mockRepo)
+	tenantRepo := new(mockTenantRepo)
+	uc := newUsecaseWithTenantMock(repo, tenantRepo)
+
+	tenantRepo.On("FindTenantBySlug", mock.Anything, "acme").Return(tenant.FindTenantResponse{Id: "t1", Slug: "acme", Disabled: true}, nil)
+
+	resp, err := uc.Login(context.Background(), user.SignInRequest{
+		Tenant:   "acme",
+		Email:    "test@example.com",
+		Password: "pass123",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.LoginFail(), resp)
+	repo.AssertNotCalled(t, "FindUserByEmail", mock.Anything, mock.Anything)
+}
+
 func TestUsecaseLogin_Fail(t *testing.T) {
 	repo := new(mockRepo)
 	uc := newUsecaseWithMock(repo)
@@ -131,12 +480,75 @@ func TestUsecaseLogin_Fail(t *testing.T) {
 	repo.AssertExpectations(t)
 }
 
+func TestUsecaseLogin_RehashesOutdatedHash(t *testing.T) {
+	repo := new(mockRepo)
+	uc := newUsecaseWithMock(repo)
+
+	// A hash made before the cost changed.
+	old, _ := bcrypt.GenerateFromPassword([]byte("pass123"), bcrypt.MinCost+1)
+	userData := user.User{ID: primitive.NewObjectID(), Email: "test@example.com", Password: string(old)}
+	repo.On("FindUserByEmail", mock.Anything, "test@example.com").Return(userData, nil)
+	repo.On("RehashPassword", mock.Anything, userData.ID, string(old), mock.MatchedBy(func(hash string) bool {
+		return !testHasher.NeedsRehash(hash) && passhash.Verify(hash, "pass123")
+	})).Return(int64(1), nil)
+	repo.On("UpdateLastLogin", mock.Anything, userData.ID, mock.Anything).Return(nil)
+
+	resp, err := uc.Login(context.Background(), user.SignInRequest{Email: "test@example.com", Password: "pass123"})
+
+	assert.NoError(t, err)
+	assert.True(t, resp.IsSuccess())
+	repo.AssertExpectations(t)
+}
+
+func TestUsecaseLogin_RehashFailureStillLogsIn(t *testing.T) {
+	repo := new(mockRepo)
+	uc := newUsecaseWithMock(repo)
+
+	old, _ := bcrypt.GenerateFromPassword([]byte("pass123"), bcrypt.MinCost+1)
+	userData := user.User{ID: primitive.NewObjectID(), Email: "test@example.com", Password: string(old)}
+	repo.On("FindUserByEmail", mock.Anything, "test@example.com").Return(userData, nil)
+	repo.On("RehashPassword", mock.Anything, userData.ID, string(old), mock.Anything).Return(int64(0), errors.New("db down"))
+	repo.On("UpdateLastLogin", mock.Anything, userData.ID, mock.Anything).Return(nil)
+
+	resp, err := uc.Login(context.Background(), user.SignInRequest{Email: "test@example.com", Password: "pass123"})
+
+	assert.NoError(t, err)
+	assert.True(t, resp.IsSuccess())
+}
+
+func TestUsecaseLogin_RehashToOtherAlgorithm_MemoryRepository(t *testing.T) {
+	repo := user.NewMemoryRepository()
+	ctx := adminContext()
+	bcryptUc := user.NewUsecase(config.CryptoCredential{JwtKey: "testsecret", JwtExpireDuration: time.Minute},
+		openPolicy, testHasher, repo, new(mockTenantRepo), new(mockGroupRepo), new(loginLog), new(sessionLog), profile.NewMemoryRepository())
+	_, err := bcryptUc.CreateUser(ctx, user.CreateRequest{Name: "Test", Email: "test@example.com", Password: "pass123"})
+	require.NoError(t, err)
+
+	argon, err := passhash.NewHasher(config.PasswordHashConfig{Algorithm: passhash.AlgorithmArgon2id, Argon2Time: 1, Argon2Memory: 64, Argon2Threads: 1})
+	require.NoError(t, err)
+	argonUc := user.NewUsecase(config.CryptoCredential{JwtKey: "testsecret", JwtExpireDuration: time.Minute},
+		openPolicy, argon, repo, new(mockTenantRepo), new(mockGroupRepo), new(loginLog), new(sessionLog), profile.NewMemoryRepository())
+	resp, err := argonUc.Login(context.Background(), user.SignInRequest{Email: "test@example.com", Password: "pass123"})
+	require.NoError(t, err)
+	require.True(t, resp.IsSuccess())
+
+	stored, err := repo.FindUserByEmail(context.Background(), "test@example.com")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(stored.Password, "$argon2id$"))
+	assert.False(t, argon.NeedsRehash(stored.Password))
+
+	// The old configuration still accepts the new hash.
+	resp, err = bcryptUc.Login(context.Background(), user.SignInRequest{Email: "test@example.com", Password: "pass123"})
+	require.NoError(t, err)
+	assert.True(t, resp.IsSuccess())
+}
+
 func TestUsecaseLoginInvalidPassword(t *testing.T) {
 	repo := new(mockRepo)
 	uc := newUsecaseWithMock(repo)
 
-	hashed, _ := bcrypt.GenerateFromPassword([]byte("pass123"), bcrypt.DefaultCost)
-	userData := user.User{Email: "test@example.com", Password: string(hashed)}
+	hashed, _ := testHasher.Hash("pass123")
+	userData := user.User{Email: "test@example.com", Password: hashed}
 
 	repo.On("FindUserByEmail", mock.Anything, "test@example.com").Return(userData, nil)
 
@@ -150,14 +562,311 @@ func TestUsecaseLoginInvalidPassword(t *testing.T) {
 	repo.AssertExpectations(t)
 }
 
+func TestUsecaseLogin_RecordsFailures(t *testing.T) {
+	hashed, _ := testHasher.Hash("pass123")
+	userData := user.User{ID: primitive.NewObjectID(), TenantID: "t1", Email: "test@example.com", Password: hashed}
+
+	tests := []struct {
+		name     string
+		req      user.SignInRequest
+		tenantID string
+		userID   string
+		reason   string
+	}{
+		{"unknown tenant", user.SignInRequest{Tenant: "nope", Email: "test@example.com", Password: "pass123"}, "", "", loginhistory.ReasonUnknownTenant},
+		{"disabled tenant", user.SignInRequest{Tenant: "off", Email: "test@example.com", Password: "pass123"}, "t2", "", loginhistory.ReasonTenantDisabled},
+		{"unknown user", user.SignInRequest{Tenant: "acme", Email: "ghost@example.com", Password: "pass123"}, "t1", "", loginhistory.ReasonUnknownUser},
+		{"wrong password", user.SignInRequest{Tenant: "acme", Email: "test@example.com", Password: "wrong"}, "t1", userData.ID.Hex(), loginhistory.ReasonWrongPassword},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mockRepo)
+			tenantRepo := new(mockTenantRepo)
+			logins := new(loginLog)
+			uc := user.NewUsecase(config.CryptoCredential{JwtKey: "testsecret", JwtExpireDuration: time.Minute},
+				openPolicy, testHasher, repo, tenantRepo, new(mockGroupRepo), logins, new(sessionLog), profile.NewMemoryRepository())
+			tenantRepo.On("FindTenantBySlug", mock.Anything, "nope").Return(tenant.FindTenantResponse{}, tenant.ErrTenantNotFound)
+			tenantRepo.On("FindTenantBySlug", mock.Anything, "off").Return(tenant.FindTenantResponse{Id: "t2", Disabled: true}, nil)
+			tenantRepo.On("FindTenantBySlug", mock.Anything, "acme").Return(tenant.FindTenantResponse{Id: "t1"}, nil)
+			repo.On("FindUserByEmail", mock.Anything, "ghost@example.com").Return(user.User{}, user.ErrUserOrPasswordIsWrong)
+			repo.On("FindUserByEmail", mock.Anything, "test@example.com").Return(userData, nil)
+
+			ctx := clientinfo.WithInfo(context.Background(), clientinfo.Info{IP: "203.0.113.7", UserAgent: "curl/8.0", Protocol: clientinfo.ProtocolREST})
+			resp, err := uc.Login(ctx, tt.req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, response.LoginFail(), resp)
+			assert.Equal(t, []loginhistory.Attempt{{
+				TenantID:  tt.tenantID,
+				UserID:    tt.userID,
+				Email:     tt.req.Email,
+				Reason:    tt.reason,
+				IP:        "203.0.113.7",
+				UserAgent: "curl/8.0",
+				Protocol:  clientinfo.ProtocolREST,
+			}}, logins.attempts)
+			repo.AssertNotCalled(t, "UpdateLastLogin", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestUsecaseLogin_RecordsSuccessAndLastLogin_MemoryRepository(t *testing.T) {
+	repo := user.NewMemoryRepository()
+	logins := new(loginLog)
+	uc := user.NewUsecase(config.CryptoCredential{JwtKey: "testsecret", JwtExpireDuration: time.Minute},
+		openPolicy, testHasher, repo, new(mockTenantRepo), new(mockGroupRepo), logins, new(sessionLog), profile.NewMemoryRepository())
+	resp, err := uc.CreateUser(adminContext(), user.CreateRequest{Name: "Test", Email: "test@example.com", Password: "pass123"})
+	require.NoError(t, err)
+	id := resp.Data.(user.CreateResponse).Id
+
+	found, err := repo.FindUserById(context.Background(), id)
+	require.NoError(t, err)
+	assert.Nil(t, found.LastLoginAt)
+
+	before := time.Now()
+	ctx := clientinfo.WithInfo(context.Background(), clientinfo.Info{IP: "198.51.100.1", Protocol: clientinfo.ProtocolGRPC})
+	resp, err = uc.Login(ctx, user.SignInRequest{Email: "test@example.com", Password: "pass123"})
+	require.NoError(t, err)
+	require.True(t, resp.IsSuccess())
+
+	found, err = repo.FindUserById(context.Background(), id)
+	require.NoError(t, err)
+	require.NotNil(t, found.LastLoginAt)
+	assert.False(t, found.LastLoginAt.Before(before))
+	require.Len(t, logins.attempts, 1)
+	assert.True(t, logins.attempts[0].Success)
+	assert.Equal(t, id, logins.attempts[0].UserID)
+	assert.Empty(t, logins.attempts[0].Reason)
+	assert.Equal(t, clientinfo.ProtocolGRPC, logins.attempts[0].Protocol)
+	assert.Equal(t, "198.51.100.1", logins.attempts[0].IP)
+}
+
+func TestUsecaseLogin_RecordFailureStillLogsIn(t *testing.T) {
+	repo := user.NewMemoryRepository()
+	logins := &loginLog{err: errors.New("db down")}
+	uc := user.NewUsecase(config.CryptoCredential{JwtKey: "testsecret", JwtExpireDuration: time.Minute},
+		openPolicy, testHasher, repo, new(mockTenantRepo), new(mockGroupRepo), logins, new(sessionLog), profile.NewMemoryRepository())
+	_, err := uc.CreateUser(adminContext(), user.CreateRequest{Name: "Test", Email: "test@example.com", Password: "pass123"})
+	require.NoError(t, err)
+
+	resp, err := uc.Login(context.Background(), user.SignInRequest{Email: "test@example.com", Password: "pass123"})
+
+	assert.NoError(t, err)
+	assert.True(t, resp.IsSuccess())
+	assert.Len(t, logins.attempts, 1)
+}
+
+func TestUsecaseLogin_StartsSession(t *testing.T) {
+	repo := user.NewMemoryRepository()
+	sessions := new(sessionLog)
+	uc := user.NewUsecase(config.CryptoCredential{JwtKey: "testsecret", JwtExpireDuration: time.Minute},
+		openPolicy, testHasher, repo, new(mockTenantRepo), new(mockGroupRepo), new(loginLog), sessions, profile.NewMemoryRepository())
+	resp, err := uc.CreateUser(adminContext(), user.CreateRequest{Name: "Test", Email: "test@example.com", Password: "pass123"})
+	require.NoError(t, err)
+	id := resp.Data.(user.CreateResponse).Id
+
+	resp, err = uc.Login(context.Background(), user.SignInRequest{Email: "test@example.com", Password: "pass123"})
+	require.NoError(t, err)
+
+	claims := &auth.Claims{}
+	_, err = jwt.ParseWithClaims(resp.Data.(*user.SignInResponse).Token, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte("testsecret"), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "session-1", claims.SessionID)
+	assert.Equal(t, []string{id}, sessions.users)
+}
+
+func TestUsecaseLogin_SessionFailure(t *testing.T) {
+	repo := user.NewMemoryRepository()
+	uc := user.NewUsecase(config.CryptoCredential{JwtKey: "testsecret", JwtExpireDuration: time.Minute},
+		openPolicy, testHasher, repo, new(mockTenantRepo), new(mockGroupRepo), new(loginLog), &sessionLog{err: errors.New("db down")}, profile.NewMemoryRepository())
+	_, err := uc.CreateUser(adminContext(), user.CreateRequest{Name: "Test", Email: "test@example.com", Password: "pass123"})
+	require.NoError(t, err)
+
+	resp, err := uc.Login(context.Background(), user.SignInRequest{Email: "test@example.com", Password: "pass123"})
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+}
+
+func TestUsecaseLoginFederated(t *testing.T) {
+	repo := user.NewMemoryRepository()
+	logins := new(loginLog)
+	sessions := new(sessionLog)
+	uc := user.NewUsecase(config.CryptoCredential{JwtKey: "testsecret", JwtExpireDuration: time.Minute},
+		openPolicy, testHasher, repo, new(mockTenantRepo), new(mockGroupRepo), logins, sessions, profile.NewMemoryRepository())
+	resp, err := uc.CreateUser(adminContext(), user.CreateRequest{Name: "Test", Email: "test@example.com", Password: "pass123"})
+	require.NoError(t, err)
+	id := resp.Data.(user.CreateResponse).Id
+
+	resp, err = uc.LoginFederated(auth.WithTenant(context.Background(), ""), id, "acme")
+	require.NoError(t, err)
+	require.True(t, resp.IsSuccess())
+
+	claims := &auth.Claims{}
+	_, err = jwt.ParseWithClaims(resp.Data.(*user.SignInResponse).Token, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte("testsecret"), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, id, claims.UserID)
+	assert.Equal(t, "session-1", claims.SessionID)
+	require.Len(t, logins.attempts, 1)
+	assert.True(t, logins.attempts[0].Success)
+	assert.Equal(t, "acme", logins.attempts[0].Provider)
+	assert.Equal(t, "test@example.com", logins.attempts[0].Email)
+	found, err := repo.FindUserById(context.Background(), id)
+	require.NoError(t, err)
+	assert.NotNil(t, found.LastLoginAt)
+
+	_, err = uc.ChangeStatus(adminContext(), id, user.StatusRequest{Status: user.StatusSuspended, Reason: "Spam"})
+	require.NoError(t, err)
+	resp, err = uc.LoginFederated(context.Background(), id, "acme")
+	require.NoError(t, err)
+	assert.Equal(t, response.AccountInactive(), resp)
+	assert.Equal(t, loginhistory.ReasonAccountInactive, logins.attempts[1].Reason)
+
+	resp, err = uc.LoginFederated(context.Background(), primitive.NewObjectID().Hex(), "acme")
+	require.NoError(t, err)
+	assert.Equal(t, response.LoginFail(), resp)
+	assert.Equal(t, loginhistory.ReasonUnknownUser, logins.attempts[2].Reason)
+}
+
+// newStatusUsecase returns a usecase over a memory repository holding one
+// active user, and that user's id.
+func newStatusUsecase(t *testing.T) (user.Usecase, string) {
+	t.Helper()
+	uc := user.NewUsecase(config.CryptoCredential{JwtKey: "testsecret", JwtExpireDuration: time.Minute},
+		openPolicy, testHasher, user.NewMemoryRepository(), new(mockTenantRepo), new(mockGroupRepo), new(loginLog), new(sessionLog), profile.NewMemoryRepository())
+	resp, err := uc.CreateUser(adminContext(), user.CreateRequest{Name: "Test", Email: "test@example.com", Password: "pass123"})
+	require.NoError(t, err)
+	return uc, resp.Data.(user.CreateResponse).Id
+}
+
+func adminContextAs(userID string) context.Context {
+	return auth.WithClaims(context.Background(), &auth.Claims{UserID: userID, Role: auth.RoleAdmin})
+}
+
+func userContext(userID string) context.Context {
+	return auth.WithClaims(context.Background(), &auth.Claims{UserID: userID, Role: auth.RoleUser})
+}
+
+func TestUsecaseChangeStatus_SuspendBlocksLoginAndToken(t *testing.T) {
+	logins := new(loginLog)
+	uc := user.NewUsecase(config.CryptoCredential{JwtKey: "testsecret", JwtExpireDuration: time.Minute},
+		openPolicy, testHasher, user.NewMemoryRepository(), new(mockTenantRepo), new(mockGroupRepo), logins, new(sessionLog), profile.NewMemoryRepository())
+	resp, err := uc.CreateUser(adminContext(), user.CreateRequest{Name: "Test", Email: "test@example.com", Password: "pass123"})
+	require.NoError(t, err)
+	id := resp.Data.(user.CreateResponse).Id
+	admin := primitive.NewObjectID().Hex()
+
+	resp, err = uc.ChangeStatus(adminContextAs(admin), id, user.StatusRequest{Status: user.StatusSuspended, Reason: " spam "})
+	require.NoError(t, err)
+	assert.Equal(t, response.Success(), resp)
+
+	resp, err = uc.Login(context.Background(), user.SignInRequest{Email: "test@example.com", Password: "pass123"})
+	assert.NoError(t, err)
+	assert.Equal(t, response.AccountInactive(), resp)
+	require.Len(t, logins.attempts, 1)
+	assert.Equal(t, loginhistory.ReasonAccountInactive, logins.attempts[0].Reason)
+	active, err := uc.UserActive(context.Background(), id)
+	assert.NoError(t, err)
+	assert.False(t, active)
+
+	resp, err = uc.FindStatusHistory(adminContext(), id)
+	require.NoError(t, err)
+	history := resp.Data.([]user.StatusChange)
+	require.Len(t, history, 1)
+	assert.Equal(t, user.StatusActive, history[0].From)
+	assert.Equal(t, user.StatusSuspended, history[0].To)
+	assert.Equal(t, "spam", history[0].Reason)
+	assert.Equal(t, admin, history[0].ChangedBy)
+
+	resp, err = uc.ChangeStatus(adminContextAs(admin), id, user.StatusRequest{Status: user.StatusActive})
+	require.NoError(t, err)
+	assert.Equal(t, response.Success(), resp)
+	resp, err = uc.Login(context.Background(), user.SignInRequest{Email: "test@example.com", Password: "pass123"})
+	assert.NoError(t, err)
+	assert.True(t, resp.IsSuccess())
+}
+
+func TestUsecaseChangeStatus_Rules(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  func(id string) context.Context
+		to   string
+		want *response.StdResp[any]
+	}{
+		{"not allowed", func(string) context.Context { return adminContext() }, user.StatusPending, response.InvalidStatusTransition()},
+		{"same status", func(string) context.Context { return adminContext() }, user.StatusActive, response.InvalidStatusTransition()},
+		{"not admin", func(string) context.Context { return userContext("u1") }, user.StatusDisabled, response.Forbidden()},
+		{"own account", func(id string) context.Context { return adminContextAs(id) }, user.StatusDisabled, response.Forbidden()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, id := newStatusUsecase(t)
+
+			resp, err := uc.ChangeStatus(tt.ctx(id), id, user.StatusRequest{Status: tt.to, Reason: "test"})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, resp)
+		})
+	}
+}
+
+func TestUsecaseChangeStatus_NotFound(t *testing.T) {
+	uc, _ := newStatusUsecase(t)
+
+	resp, err := uc.ChangeStatus(adminContext(), primitive.NewObjectID().Hex(), user.StatusRequest{Status: user.StatusActive})
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.UserNotFound(), resp)
+}
+
+func TestUsecaseChangeStatus_Concurrent(t *testing.T) {
+	repo := new(mockRepo)
+	uc := newUsecaseWithMock(repo)
+	oid := primitive.NewObjectID()
+	repo.On("FindUserById", mock.Anything, oid.Hex()).Return(user.FindUserResponse{Id: oid.Hex(), Status: user.StatusActive}, nil)
+	repo.On("UpdateStatus", mock.Anything, oid, user.StatusActive, mock.Anything).Return(int64(0), nil)
+
+	resp, err := uc.ChangeStatus(adminContext(), oid.Hex(), user.StatusRequest{Status: user.StatusDisabled, Reason: "left"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.InvalidStatusTransition(), resp)
+}
+
+func TestUsecaseCreateUser_Pending(t *testing.T) {
+	uc, _ := newStatusUsecase(t)
+	resp, err := uc.CreateUser(adminContext(), user.CreateRequest{Name: "New", Email: "new@example.com", Password: "pass123", Status: user.StatusPending})
+	require.NoError(t, err)
+	id := resp.Data.(user.CreateResponse).Id
+
+	resp, err = uc.FindUserById(adminContext(), id)
+	require.NoError(t, err)
+	assert.Equal(t, user.StatusPending, resp.Data.(user.FindUserResponse).Status)
+	resp, err = uc.Login(context.Background(), user.SignInRequest{Email: "new@example.com", Password: "pass123"})
+	assert.NoError(t, err)
+	assert.Equal(t, response.AccountInactive(), resp)
+}
+
+func TestUsecaseFindStatusHistory_Forbidden(t *testing.T) {
+	uc, id := newStatusUsecase(t)
+
+	resp, err := uc.FindStatusHistory(userContext(id), id)
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.Forbidden(), resp)
+}
+
 func TestUsecaseFindUsers(t *testing.T) {
 	repo := new(mockRepo)
 	uc := newUsecaseWithMock(repo)
 
 	users := []user.FindUserResponse{{Name: "User1"}, {Name: "User2"}}
-	repo.On("FindUsers", mock.Anything).Return(users, nil)
+	repo.On("FindUsers", mock.Anything, map[string]any(nil)).Return(users, nil)
 
-	resp, err := uc.FindUsers(context.Background())
+	resp, err := uc.FindUsers(context.Background(), nil)
 
 	assert.NoError(t, err)
 	assert.Len(t, resp.Data.([]user.FindUserResponse), 2)
@@ -184,7 +893,7 @@ func TestUsecaseUpdateUser(t *testing.T) {
 	input := user.User{ID: primitive.NewObjectID(), Name: "Updated Name"}
 	repo.On("UpdateUser", mock.Anything, input).Return(int64(1), nil)
 
-	resp, err := uc.UpdateUser(context.Background(), input)
+	resp, err := uc.UpdateUser(adminContext(), input)
 
 	assert.NoError(t, err)
 	assert.Equal(t, response.Success(), resp)
@@ -198,7 +907,7 @@ func TestUsecaseUpdateUser_NotFound(t *testing.T) {
 	input := user.User{ID: primitive.NewObjectID(), Name: "Updated Name"}
 	repo.On("UpdateUser", mock.Anything, input).Return(int64(0), nil)
 
-	resp, err := uc.UpdateUser(context.Background(), input)
+	resp, err := uc.UpdateUser(adminContext(), input)
 
 	assert.NoError(t, err)
 	assert.Equal(t, response.UserNotFound(), resp)
@@ -212,39 +921,97 @@ func TestUsecaseUpdateUser_DuplicatedEmail(t *testing.T) {
 	input := user.User{ID: primitive.NewObjectID(), Email: "duplicate@example.com", Name: "Updated Name"}
 	repo.On("UpdateUser", mock.Anything, input).Return(int64(0), user.ErrEmailAlreadyExists)
 
-	resp, err := uc.UpdateUser(context.Background(), input)
+	resp, err := uc.UpdateUser(adminContext(), input)
 
 	assert.NoError(t, err)
 	assert.Equal(t, response.DuplicatedRegistration(), resp)
 	repo.AssertExpectations(t)
 }
 
-func TestUsecaseDeleteUser(t *testing.T) {
+func TestUsecaseUpdateUser_Forbidden(t *testing.T) {
 	repo := new(mockRepo)
 	uc := newUsecaseWithMock(repo)
+	input := user.User{ID: primitive.NewObjectID(), Email: "taken@example.com"}
+
+	resp, err := uc.UpdateUser(userContext(primitive.NewObjectID().Hex()), input)
+	assert.NoError(t, err)
+	assert.Equal(t, response.Forbidden(), resp)
+	repo.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
+
+	// Users may update themselves.
+	repo.On("UpdateUser", mock.Anything, input).Return(int64(1), nil)
+	resp, err = uc.UpdateUser(userContext(input.ID.Hex()), input)
+	assert.NoError(t, err)
+	assert.Equal(t, response.Success(), resp)
+}
+
+// newDeleteUsecase returns a usecase whose group and session fakes can be
+// inspected after a delete.
+func newDeleteUsecase(repo *mockRepo) (user.Usecase, *mockGroupRepo, *sessionLog) {
+	groups, sessions := new(mockGroupRepo), new(sessionLog)
+	uc := user.NewUsecase(config.CryptoCredential{JwtKey: "testsecret", JwtExpireDuration: time.Minute},
+		openPolicy, testHasher, repo, new(mockTenantRepo), groups, new(loginLog), sessions, profile.NewMemoryRepository())
+	return uc, groups, sessions
+}
+
+func TestUsecaseDeleteUser(t *testing.T) {
+	repo := new(mockRepo)
+	uc, groups, sessions := newDeleteUsecase(repo)
 
 	id := "abc123"
+	repo.On("FindUserById", mock.Anything, id).Return(user.FindUserResponse{Id: id}, nil)
+	groups.On("RemoveUserMemberships", mock.Anything, id).Return(int64(2), nil)
 	repo.On("DeleteUser", mock.Anything, id).Return(int64(1), nil)
 
-	resp, err := uc.DeleteUser(context.Background(), id)
+	resp, err := uc.DeleteUser(adminContext(), id)
 
 	assert.NoError(t, err)
 	assert.Equal(t, response.Success(), resp)
+	assert.Equal(t, []string{id}, sessions.ended)
 	repo.AssertExpectations(t)
+	groups.AssertExpectations(t)
+}
+
+func TestUsecaseDeleteUser_Self(t *testing.T) {
+	repo := new(mockRepo)
+	uc, groups, _ := newDeleteUsecase(repo)
+
+	id := primitive.NewObjectID().Hex()
+	repo.On("FindUserById", mock.Anything, id).Return(user.FindUserResponse{Id: id}, nil)
+	groups.On("RemoveUserMemberships", mock.Anything, id).Return(int64(0), nil)
+	repo.On("DeleteUser", mock.Anything, id).Return(int64(1), nil)
+
+	resp, err := uc.DeleteUser(userContext(id), id)
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.Success(), resp)
+}
+
+func TestUsecaseDeleteUser_Forbidden(t *testing.T) {
+	repo := new(mockRepo)
+	uc, _, sessions := newDeleteUsecase(repo)
+
+	resp, err := uc.DeleteUser(userContext(primitive.NewObjectID().Hex()), "abc123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.Forbidden(), resp)
+	assert.Empty(t, sessions.ended)
+	repo.AssertNotCalled(t, "DeleteUser", mock.Anything, mock.Anything)
 }
 
 func TestUsecaseDeleteUser_NotFound(t *testing.T) {
 	repo := new(mockRepo)
-	uc := newUsecaseWithMock(repo)
+	uc, _, sessions := newDeleteUsecase(repo)
 
 	id := "abc1234"
-	repo.On("DeleteUser", mock.Anything, id).Return(int64(0), nil)
+	repo.On("FindUserById", mock.Anything, id).Return(user.FindUserResponse{}, user.ErrUserNotFound)
 
-	resp, err := uc.DeleteUser(context.Background(), id)
+	resp, err := uc.DeleteUser(adminContext(), id)
 
 	assert.NoError(t, err)
 	assert.Equal(t, response.UserNotFound(), resp)
-	repo.AssertExpectations(t)
+	assert.Empty(t, sessions.ended)
+	repo.AssertNotCalled(t, "DeleteUser", mock.Anything, mock.Anything)
 }
 
 func TestUsecaseCheckPassword(t *testing.T) {
@@ -266,3 +1033,468 @@ func TestUsecaseGenerateHMAC256Key(t *testing.T) {
 	secret := base64.StdEncoding.EncodeToString(key)
 	fmt.Println("Generated HMAC256 Key:", secret)
 }
+
+func importRows() []user.ImportRow {
+	return []user.ImportRow{
+		{Line: 2, Request: user.CreateRequest{Name: "Alice", Email: "a@example.com", Password: "pass1"}},
+		{Line: 3, Request: user.CreateRequest{Name: "Bob", Email: "bad-email", Password: "pass2"}},
+		{Line: 4, Request: user.CreateRequest{Name: "Alice again", Email: "a@example.com", Password: "pass3"}},
+		{Line: 5, Error: "Malformed CSV line"},
+		{Line: 6, Request: user.CreateRequest{Name: "Carol", Email: "c@example.com", Password: "pass4", Role: auth.RoleAdmin}},
+	}
+}
+
+func TestUsecaseImportUsers(t *testing.T) {
+	repo := new(mockRepo)
+	uc := newUsecaseWithMock(repo)
+
+	repo.On("CreateUsers", mock.Anything, mock.MatchedBy(func(users []user.User) bool {
+		return len(users) == 2 &&
+			users[0].Email == "a@example.com" && users[0].Role == auth.RoleUser && passhash.Verify(users[0].Password, "pass1") &&
+			users[1].Email == "c@example.com" && users[1].Role == auth.RoleAdmin && passhash.Verify(users[1].Password, "pass4")
+	})).Return([]error{nil, user.ErrEmailAlreadyExists}, nil)
+
+	resp, err := uc.ImportUsers(adminContext(), importRows(), false)
+
+	assert.NoError(t, err)
+	report := resp.Data.(user.ImportReport)
+	assert.Equal(t, 5, report.Total)
+	assert.Equal(t, 1, report.Succeeded)
+	assert.Equal(t, 4, report.Failed)
+	assert.Equal(t, user.ImportStatusCreated, report.Rows[0].Status)
+	assert.NotEmpty(t, report.Rows[0].Id)
+	assert.Equal(t, response.InvalidData("email").Message, report.Rows[1].Error)
+	assert.Equal(t, "Email already used on line 2", report.Rows[2].Error)
+	assert.Equal(t, "Malformed CSV line", report.Rows[3].Error)
+	assert.Equal(t, response.DuplicatedRegistration().Message, report.Rows[4].Error)
+	repo.AssertExpectations(t)
+}
+
+func TestUsecaseImportUsers_ScopedCannotCreateAdmin(t *testing.T) {
+	repo := new(mockRepo)
+	uc := newUsecaseWithMock(repo)
+
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{Role: auth.RoleAdmin, Scopes: []string{auth.ScopeUsersWrite}})
+	resp, err := uc.ImportUsers(ctx, importRows(), true)
+
+	assert.NoError(t, err)
+	report := resp.Data.(user.ImportReport)
+	assert.Equal(t, 1, report.Succeeded)
+	assert.Equal(t, response.Forbidden().Message, report.Rows[4].Error)
+}
+
+func TestUsecaseImportUsers_WeakPassword(t *testing.T) {
+	repo := new(mockRepo)
+	uc := newUsecaseWithPolicy(repo, config.PasswordPolicyConfig{MinLength: 6})
+
+	rows := []user.ImportRow{
+		{Line: 2, Request: user.CreateRequest{Name: "Alice", Email: "a@example.com", Password: "pass1"}},
+		{Line: 3, Request: user.CreateRequest{Name: "Bob", Email: "b@example.com", Password: "longer1"}},
+	}
+	resp, err := uc.ImportUsers(adminContext(), rows, true)
+
+	assert.NoError(t, err)
+	report := resp.Data.(user.ImportReport)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, "Password does not meet the policy: min_length", report.Rows[0].Error)
+	assert.Equal(t, user.ImportStatusValid, report.Rows[1].Status)
+}
+
+func TestUsecaseImportUsers_DryRun(t *testing.T) {
+	repo := new(mockRepo)
+	uc := newUsecaseWithMock(repo)
+
+	resp, err := uc.ImportUsers(adminContext(), importRows(), true)
+
+	assert.NoError(t, err)
+	report := resp.Data.(user.ImportReport)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 2, report.Succeeded)
+	assert.Equal(t, user.ImportStatusValid, report.Rows[0].Status)
+	assert.Empty(t, report.Rows[0].Id)
+	repo.AssertNotCalled(t, "CreateUsers", mock.Anything, mock.Anything)
+}
+
+func TestUsecaseImportUsers_TenantScoped(t *testing.T) {
+	repo := new(mockRepo)
+	uc := newUsecaseWithMock(repo)
+
+	tenantID := primitive.NewObjectID().Hex()
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{TenantID: tenantID, Role: auth.RoleAdmin})
+	rows := []user.ImportRow{{Line: 1, Request: user.CreateRequest{Name: "Alice", Email: "a@example.com", Password: "pass1", TenantID: primitive.NewObjectID().Hex()}}}
+	repo.On("CreateUsers", mock.Anything, mock.MatchedBy(func(users []user.User) bool {
+		return len(users) == 1 && users[0].TenantID == tenantID
+	})).Return([]error{nil}, nil)
+
+	resp, err := uc.ImportUsers(ctx, rows, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resp.Data.(user.ImportReport).Succeeded)
+	repo.AssertExpectations(t)
+}
+
+func TestUsecaseImportUsers_Forbidden(t *testing.T) {
+	repo := new(mockRepo)
+	uc := newUsecaseWithMock(repo)
+
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{Role: auth.RoleUser})
+	resp, err := uc.ImportUsers(ctx, importRows(), false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.Forbidden(), resp)
+}
+
+func TestUsecaseResetPassword_MemoryRepository(t *testing.T) {
+	repo := user.NewMemoryRepository()
+	uc := user.NewUsecase(config.CryptoCredential{JwtKey: "testsecret", JwtExpireDuration: time.Minute},
+		newPolicy(config.PasswordPolicyConfig{MinLength: 8, HistorySize: 2}), testHasher, repo, new(mockTenantRepo), new(mockGroupRepo), new(loginLog), new(sessionLog), profile.NewMemoryRepository())
+	ctx := adminContext()
+
+	resp, err := uc.CreateUser(ctx, user.CreateRequest{Name: "Test", Email: "test@example.com", Password: "first-pass"})
+	require.NoError(t, err)
+	id := resp.Data.(user.CreateResponse).Id
+
+	resp, err = uc.ResetPassword(ctx, id, "second-pass")
+	require.NoError(t, err)
+	assert.Equal(t, response.Success(), resp)
+
+	for _, p := range []string{"first-pass", "second-pass"} {
+		resp, err = uc.ResetPassword(ctx, id, p)
+		require.NoError(t, err)
+		assert.Equal(t, "4016", resp.Code, p)
+		assert.Equal(t, []string{user.RuleReused}, rules(resp.Data.([]response.Violation)))
+	}
+
+	resp, err = uc.ResetPassword(ctx, id, "short")
+	require.NoError(t, err)
+	assert.Equal(t, []string{user.RuleMinLength}, rules(resp.Data.([]response.Violation)))
+
+	resp, err = uc.ResetPassword(ctx, id, "third-pass")
+	require.NoError(t, err)
+	assert.Equal(t, response.Success(), resp)
+	// Only the last two passwords are remembered.
+	resp, err = uc.ResetPassword(ctx, id, "first-pass")
+	require.NoError(t, err)
+	assert.Equal(t, response.Success(), resp)
+
+	resp, err = uc.Login(context.Background(), user.SignInRequest{Email: "test@example.com", Password: "first-pass"})
+	require.NoError(t, err)
+	assert.True(t, resp.IsSuccess())
+}
+
+func TestUsecaseResetPassword_NotFound(t *testing.T) {
+	repo := new(mockRepo)
+	uc := newUsecaseWithMock(repo)
+	id := primitive.NewObjectID().Hex()
+	repo.On("FindUserById", mock.Anything, id).Return(user.FindUserResponse{}, user.ErrUserNotFound)
+
+	resp, err := uc.ResetPassword(adminContext(), id, "new-password")
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.UserNotFound(), resp)
+	repo.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
+}
+
+func TestUsecaseResetPassword_Forbidden(t *testing.T) {
+	repo := new(mockRepo)
+	uc := newUsecaseWithMock(repo)
+
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{Role: auth.RoleUser})
+	resp, err := uc.ResetPassword(ctx, primitive.NewObjectID().Hex(), "new-password")
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.Forbidden(), resp)
+}
+
+func TestUsecaseExportUsers(t *testing.T) {
+	repo := new(mockRepo)
+	uc := newUsecaseWithMock(repo)
+
+	fields := []string{"id", "email"}
+	repo.On("ExportUsers", mock.Anything, map[string]any(nil), fields).Return([]user.FindUserResponse{{Id: "u1"}, {Id: "u2"}}, nil)
+
+	var got []string
+	resp, err := uc.ExportUsers(adminContext(), nil, fields, func(u user.FindUserResponse) error {
+		got = append(got, u.Id)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.Success(), resp)
+	assert.Equal(t, []string{"u1", "u2"}, got)
+}
+
+func TestUsecaseExportUsers_Forbidden(t *testing.T) {
+	repo := new(mockRepo)
+	uc := newUsecaseWithMock(repo)
+
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{Role: auth.RoleUser})
+	resp, err := uc.ExportUsers(ctx, nil, user.ExportFields, func(user.FindUserResponse) error { return nil })
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.Forbidden(), resp)
+	repo.AssertNotCalled(t, "ExportUsers", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUsecaseCreateUserThenLogin_MemoryRepository(t *testing.T) {
+	uc := newUsecaseWithMemory()
+
+	resp, err := uc.CreateUser(adminContext(), user.CreateRequest{Name: "Test", Email: "test@example.com", Password: "pass123"})
+	assert.NoError(t, err)
+	assert.True(t, resp.IsSuccess())
+
+	resp, err = uc.CreateUser(adminContext(), user.CreateRequest{Name: "Again", Email: "test@example.com", Password: "pass123"})
+	assert.NoError(t, err)
+	assert.Equal(t, response.DuplicatedRegistration(), resp)
+
+	resp, err = uc.Login(context.Background(), user.SignInRequest{Email: "test@example.com", Password: "pass123"})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, resp.Data.(*user.SignInResponse).Token)
+
+	resp, err = uc.Login(context.Background(), user.SignInRequest{Email: "test@example.com", Password: "wrong"})
+	assert.NoError(t, err)
+	assert.Equal(t, response.LoginFail(), resp)
+}
+
+func TestUsecaseCreateUser_WithoutPassword(t *testing.T) {
+	uc := newUsecaseWithMemory()
+
+	resp, err := uc.CreateUser(adminContext(), user.CreateRequest{Name: "Test", Email: "test@example.com"})
+	require.NoError(t, err)
+	require.True(t, resp.IsSuccess())
+
+	// The account has no password to log in with.
+	resp, err = uc.Login(context.Background(), user.SignInRequest{Email: "test@example.com", Password: ""})
+	assert.NoError(t, err)
+	assert.Equal(t, response.LoginFail(), resp)
+}
+
+func TestUsecaseImportUsers_MemoryRepository(t *testing.T) {
+	uc := newUsecaseWithMemory()
+	_, err := uc.CreateUser(adminContext(), user.CreateRequest{Name: "C", Email: "c@example.com", Password: "pass123"})
+	assert.NoError(t, err)
+
+	resp, err := uc.ImportUsers(adminContext(), importRows(), false)
+
+	assert.NoError(t, err)
+	report := resp.Data.(user.ImportReport)
+	assert.Equal(t, 1, report.Succeeded)
+	assert.Equal(t, response.DuplicatedRegistration().Message, report.Rows[4].Error)
+
+	resp, err = uc.FindUsers(adminContext(), nil)
+	assert.NoError(t, err)
+	users := resp.Data.([]user.FindUserResponse)
+	assert.Len(t, users, 2)
+	assert.Equal(t, "c@example.com", users[0].Email)
+	assert.Equal(t, "a@example.com", users[1].Email)
+}
+
+var testProfileSchema = []profile.Attribute{
+	{Name: "department", Type: profile.TypeString, Required: true, Enum: []string{"Sales", "Ops"}, Indexed: true},
+	{Name: "employee_id", Type: profile.TypeString, Pattern: `^E[0-9]{4}$`},
+	{Name: "level", Type: profile.TypeInteger, Indexed: true},
+}
+
+// newProfileUsecase returns a usecase over a memory repository whose platform
+// users follow testProfileSchema.
+func newProfileUsecase(t *testing.T) user.Usecase {
+	t.Helper()
+	profiles := profile.NewMemoryRepository()
+	require.NoError(t, profiles.SaveSchema(context.Background(), profile.Schema{Attributes: testProfileSchema}))
+	return user.NewUsecase(config.CryptoCredential{JwtKey: "testsecret", JwtExpireDuration: time.Minute},
+		openPolicy, testHasher, user.NewMemoryRepository(), new(mockTenantRepo), new(mockGroupRepo), new(loginLog), new(sessionLog), profiles)
+}
+
+func TestUsecaseCreateUser_Profile(t *testing.T) {
+	uc := newProfileUsecase(t)
+
+	resp, err := uc.CreateUser(adminContext(), user.CreateRequest{Name: "Test", Email: "test@example.com", Password: "pass123",
+		Profile: map[string]any{"department": "Sales", "level": float64(3)}})
+	require.NoError(t, err)
+	require.True(t, resp.IsSuccess(), resp.Message)
+
+	resp, err = uc.FindUserById(adminContext(), resp.Data.(user.CreateResponse).Id)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"department": "Sales", "level": int64(3)}, resp.Data.(user.FindUserResponse).Profile)
+}
+
+func TestUsecaseCreateUser_InvalidProfile(t *testing.T) {
+	uc := newProfileUsecase(t)
+
+	resp, err := uc.CreateUser(adminContext(), user.CreateRequest{Name: "Test", Email: "test@example.com", Password: "pass123",
+		Profile: map[string]any{"employee_id": "X1", "level": 2.5, "phone": "123"}})
+
+	require.NoError(t, err)
+	violations := []response.ProfileViolation{
+		{Attribute: "phone", Rule: profile.RuleUnknown, Message: "phone is not defined by the profile schema"},
+		{Attribute: "department", Rule: profile.RuleRequired, Message: "department is required"},
+		{Attribute: "employee_id", Rule: profile.RulePattern, Message: "employee_id does not match ^E[0-9]{4}$"},
+		{Attribute: "level", Rule: profile.RuleType, Message: "level must be of type integer"},
+	}
+	assert.Equal(t, response.InvalidProfile(violations), resp)
+	resp, err = uc.FindUsers(adminContext(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, resp.Data)
+}
+
+func TestUsecaseImportUsers_Profile(t *testing.T) {
+	uc := newProfileUsecase(t)
+	rows := []user.ImportRow{
+		{Line: 1, Request: user.CreateRequest{Name: "A", Email: "a@example.com", Password: "pass123", Profile: map[string]any{"department": "Ops"}}},
+		{Line: 2, Request: user.CreateRequest{Name: "B", Email: "b@example.com", Password: "pass123"}},
+	}
+
+	resp, err := uc.ImportUsers(adminContext(), rows, false)
+
+	require.NoError(t, err)
+	report := resp.Data.(user.ImportReport)
+	assert.Equal(t, 1, report.Succeeded)
+	assert.Equal(t, user.ImportStatusCreated, report.Rows[0].Status)
+	assert.Equal(t, "Profile is invalid: department", report.Rows[1].Error)
+}
+
+func TestUsecaseUpdateUser_MergesProfile(t *testing.T) {
+	uc := newProfileUsecase(t)
+	resp, err := uc.CreateUser(adminContext(), user.CreateRequest{Name: "Test", Email: "test@example.com", Password: "pass123",
+		Profile: map[string]any{"department": "Sales", "employee_id": "E0001"}})
+	require.NoError(t, err)
+	id := resp.Data.(user.CreateResponse).Id
+	oid, _ := primitive.ObjectIDFromHex(id)
+
+	resp, err = uc.UpdateUser(adminContext(), user.User{ID: oid, Profile: map[string]any{"level": float64(4), "employee_id": nil}})
+	require.NoError(t, err)
+	assert.Equal(t, response.Success(), resp)
+	resp, err = uc.FindUserById(adminContext(), id)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"department": "Sales", "level": int64(4)}, resp.Data.(user.FindUserResponse).Profile)
+
+	// Removing a required attribute is refused and leaves the profile alone.
+	resp, err = uc.UpdateUser(adminContext(), user.User{ID: oid, Profile: map[string]any{"department": nil}})
+	require.NoError(t, err)
+	assert.Equal(t, profile.RuleRequired, resp.Data.([]response.ProfileViolation)[0].Rule)
+	resp, err = uc.FindUserById(adminContext(), id)
+	require.NoError(t, err)
+	assert.Equal(t, "Sales", resp.Data.(user.FindUserResponse).Profile["department"])
+}
+
+func TestUsecaseUpdateUser_ProfileNotFound(t *testing.T) {
+	uc := newProfileUsecase(t)
+
+	resp, err := uc.UpdateUser(adminContext(), user.User{ID: primitive.NewObjectID(), Profile: map[string]any{"level": float64(1)}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.UserNotFound(), resp)
+}
+
+func TestUsecaseFindUsers_ProfileFilter(t *testing.T) {
+	uc := newProfileUsecase(t)
+	for i, department := range []string{"Sales", "Ops", "Sales"} {
+		resp, err := uc.CreateUser(adminContext(), user.CreateRequest{Name: "U", Email: fmt.Sprintf("%d@example.com", i), Password: "pass123",
+			Profile: map[string]any{"department": department, "level": float64(i)}})
+		require.NoError(t, err)
+		require.True(t, resp.IsSuccess(), resp.Message)
+	}
+
+	resp, err := uc.FindUsers(adminContext(), map[string]string{"department": "Sales"})
+	require.NoError(t, err)
+	assert.Len(t, resp.Data, 2)
+	resp, err = uc.FindUsers(adminContext(), map[string]string{"department": "Sales", "level": "2"})
+	require.NoError(t, err)
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, "2@example.com", resp.Data.([]user.FindUserResponse)[0].Email)
+
+	tests := map[string]map[string]string{
+		"not indexed": {"employee_id": "E0001"},
+		"unknown":     {"phone": "123"},
+		"wrong type":  {"level": "high"},
+	}
+	for name, filter := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp, err := uc.FindUsers(adminContext(), filter)
+			require.NoError(t, err)
+			for attr := range filter {
+				assert.Equal(t, response.InvalidData(user.QueryProfilePrefix+attr), resp)
+			}
+		})
+	}
+}
+
+func TestUsecaseExportUsers_ProfileFilter(t *testing.T) {
+	uc := newProfileUsecase(t)
+	for i, department := range []string{"Sales", "Ops", "Sales"} {
+		resp, err := uc.CreateUser(adminContext(), user.CreateRequest{Name: "U", Email: fmt.Sprintf("%d@example.com", i), Password: "pass123",
+			Profile: map[string]any{"department": department, "level": float64(i)}})
+		require.NoError(t, err)
+		require.True(t, resp.IsSuccess(), resp.Message)
+	}
+
+	var got []string
+	resp, err := uc.ExportUsers(adminContext(), map[string]string{"department": "Sales"}, user.ExportFields, func(u user.FindUserResponse) error {
+		got = append(got, u.Email)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, response.Success(), resp)
+	assert.Equal(t, []string{"0@example.com", "2@example.com"}, got)
+
+	resp, err = uc.ExportUsers(adminContext(), map[string]string{"employee_id": "E0001"}, user.ExportFields, func(user.FindUserResponse) error {
+		t.Fatal("rows written for an invalid filter")
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, response.InvalidData(user.QueryProfilePrefix+"employee_id"), resp)
+}
+
+func TestUsecaseSetAvatar(t *testing.T) {
+	repo := user.NewMemoryRepository()
+	uc := user.NewUsecase(config.CryptoCredential{}, openPolicy, testHasher, repo, new(mockTenantRepo), new(mockGroupRepo), new(loginLog), new(sessionLog), profile.NewMemoryRepository())
+	ctx := context.Background()
+	id, err := repo.CreateUser(ctx, user.User{Name: "Test", Email: "test@example.com"})
+	require.NoError(t, err)
+
+	previous, err := uc.SetAvatar(ctx, id, "a1")
+	require.NoError(t, err)
+	assert.Empty(t, previous)
+	previous, err = uc.SetAvatar(ctx, id, "a2")
+	require.NoError(t, err)
+	assert.Equal(t, "a1", previous)
+
+	resp, err := uc.FindUserById(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, avatar.URLs("a2"), resp.Data.(user.FindUserResponse).Avatar)
+	resp, err = uc.FindUsers(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, avatar.URLs("a2"), resp.Data.([]user.FindUserResponse)[0].Avatar)
+
+	_, err = uc.SetAvatar(ctx, primitive.NewObjectID().Hex(), "a3")
+	assert.ErrorIs(t, err, user.ErrUserNotFound)
+}
+
+func TestUsecaseReencryptUsers(t *testing.T) {
+	store := user.NewEncryptedStore(user.NewMemoryRepository(), newKeyring(t, "a", ""))
+	uc := user.NewUsecase(config.CryptoCredential{JwtKey: "testsecret"}, openPolicy, testHasher, store, new(mockTenantRepo), new(mockGroupRepo), new(loginLog), new(sessionLog), profile.NewMemoryRepository())
+
+	resp, err := uc.ReencryptUsers(adminContext(), true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.SuccessWithData(user.ReencryptReport{KeyID: "a", DryRun: true}), resp)
+}
+
+func TestUsecaseReencryptUsers_NotConfigured(t *testing.T) {
+	uc := newUsecaseWithMock(new(mockRepo))
+
+	resp, err := uc.ReencryptUsers(adminContext(), false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.EncryptionNotConfigured(), resp)
+}
+
+func TestUsecaseReencryptUsers_TenantAdmin(t *testing.T) {
+	uc := newUsecaseWithMock(new(mockRepo))
+	ctx := auth.WithClaims(context.Background(), &auth.Claims{Role: auth.RoleAdmin, TenantID: "t1"})
+
+	resp, err := uc.ReencryptUsers(ctx, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, response.Forbidden(), resp)
+}
@@ -0,0 +1,476 @@
+// Package usertest holds a conformance suite that every user.Store
+// implementation must pass, so the storage backends stay interchangeable.
+package usertest
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-management/app/user"
+	"user-management/auth"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RunStoreConformance runs the suite against stores built by newStore. Each
+// subtest gets a fresh, empty store.
+func RunStoreConformance(t *testing.T, newStore func(t *testing.T) user.Store) {
+	tenantA := primitive.NewObjectID().Hex()
+	tenantB := primitive.NewObjectID().Hex()
+
+	t.Run("create and find", func(t *testing.T) {
+		store := newStore(t)
+		ctx := auth.WithTenant(context.Background(), tenantA)
+
+		id, err := store.CreateUser(ctx, user.User{TenantID: tenantA, Name: "Alice", Email: "alice@example.com", Password: "hash", Role: auth.RoleAdmin})
+		require.NoError(t, err)
+		_, err = primitive.ObjectIDFromHex(id)
+		require.NoError(t, err)
+
+		found, err := store.FindUserById(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, id, found.Id)
+		assert.Equal(t, tenantA, found.TenantID)
+		assert.Equal(t, "Alice", found.Name)
+		assert.Equal(t, "alice@example.com", found.Email)
+		assert.Equal(t, auth.RoleAdmin, found.Role)
+		assert.WithinDuration(t, time.Now(), found.CreatedAt, time.Minute)
+
+		byEmail, err := store.FindUserByEmail(ctx, "alice@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, id, byEmail.ID.Hex())
+		assert.Equal(t, "hash", byEmail.Password)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		store := newStore(t)
+		ctx := context.Background()
+
+		_, err := store.FindUserById(ctx, primitive.NewObjectID().Hex())
+		assert.ErrorIs(t, err, user.ErrUserNotFound)
+		_, err = store.FindUserByEmail(ctx, "nobody@example.com")
+		assert.ErrorIs(t, err, user.ErrUserOrPasswordIsWrong)
+		_, err = store.FindUserById(ctx, "not-an-id")
+		assert.Error(t, err)
+	})
+
+	t.Run("email unique per tenant", func(t *testing.T) {
+		store := newStore(t)
+		ctx := context.Background()
+
+		_, err := store.CreateUser(ctx, user.User{TenantID: tenantA, Name: "A", Email: "same@example.com", Password: "hash"})
+		require.NoError(t, err)
+		_, err = store.CreateUser(ctx, user.User{TenantID: tenantA, Name: "A2", Email: "same@example.com", Password: "hash"})
+		assert.ErrorIs(t, err, user.ErrEmailAlreadyExists)
+		_, err = store.CreateUser(ctx, user.User{TenantID: tenantB, Name: "B", Email: "same@example.com", Password: "hash"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("emails match case-insensitively", func(t *testing.T) {
+		store := newStore(t)
+		ctx := context.Background()
+		id := mustCreate(t, store, user.User{TenantID: tenantA, Name: "Alice", Email: "Alice@Example.com", Password: "hash"})
+
+		found, err := store.FindUserByEmail(ctx, " alice@EXAMPLE.com ")
+		require.NoError(t, err)
+		assert.Equal(t, id, found.ID.Hex())
+		assert.Equal(t, "Alice@Example.com", found.Email, "the typed form is kept for display")
+
+		_, err = store.CreateUser(ctx, user.User{TenantID: tenantA, Name: "Dup", Email: "alice@example.com", Password: "hash"})
+		assert.ErrorIs(t, err, user.ErrEmailAlreadyExists)
+		errs, err := store.CreateUsers(ctx, []user.User{{ID: primitive.NewObjectID(), TenantID: tenantA, Name: "Dup", Email: "ALICE@example.com", Password: "hash"}})
+		require.NoError(t, err)
+		assert.ErrorIs(t, errs[0], user.ErrEmailAlreadyExists)
+		// A key set by the caller is normalized as well.
+		errs, err = store.CreateUsers(ctx, []user.User{{ID: primitive.NewObjectID(), TenantID: tenantA, Name: "Dup", Email: "ALICE@example.com", EmailNormalized: " Alice@Example.com", Password: "hash"}})
+		require.NoError(t, err)
+		assert.ErrorIs(t, errs[0], user.ErrEmailAlreadyExists)
+
+		otherID := mustCreate(t, store, user.User{TenantID: tenantA, Name: "Bob", Email: "bob@example.com", Password: "hash"})
+		other, _ := primitive.ObjectIDFromHex(otherID)
+		_, err = store.UpdateUser(ctx, user.User{ID: other, Email: "ALICE@EXAMPLE.COM"})
+		assert.ErrorIs(t, err, user.ErrEmailAlreadyExists)
+
+		_, err = store.UpdateUser(ctx, user.User{ID: other, Email: "Robert@Example.com"})
+		require.NoError(t, err)
+		found, err = store.FindUserByEmail(ctx, "robert@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "Robert@Example.com", found.Email)
+	})
+
+	t.Run("tenant scoping", func(t *testing.T) {
+		store := newStore(t)
+		ctx := context.Background()
+		idA := mustCreate(t, store, user.User{TenantID: tenantA, Name: "A", Email: "a@example.com", Password: "hash"})
+		mustCreate(t, store, user.User{TenantID: tenantB, Name: "B", Email: "b@example.com", Password: "hash"})
+		mustCreate(t, store, user.User{Name: "Platform", Email: "p@example.com", Password: "hash"})
+
+		ctxA := auth.WithTenant(ctx, tenantA)
+		ctxB := auth.WithTenant(ctx, tenantB)
+		ctxPlatform := auth.WithTenant(ctx, "")
+
+		users, err := store.FindUsers(ctxA, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a@example.com"}, emails(users))
+		users, err = store.FindUsers(ctxPlatform, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"p@example.com"}, emails(users))
+		users, err = store.FindUsers(ctx, nil)
+		require.NoError(t, err)
+		assert.Len(t, users, 3)
+
+		_, err = store.FindUserById(ctxB, idA)
+		assert.ErrorIs(t, err, user.ErrUserNotFound)
+		_, err = store.FindUserByEmail(ctxB, "a@example.com")
+		assert.ErrorIs(t, err, user.ErrUserOrPasswordIsWrong)
+
+		count, err := store.CountUsers(ctxA)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, count)
+		count, err = store.CountUsers(ctx)
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, count)
+
+		oid, _ := primitive.ObjectIDFromHex(idA)
+		matched, err := store.UpdateUser(ctxB, user.User{ID: oid, Name: "Hijacked"})
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, matched)
+		deleted, err := store.DeleteUser(ctxB, idA)
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, deleted)
+	})
+
+	t.Run("find by ids", func(t *testing.T) {
+		store := newStore(t)
+		id1 := mustCreate(t, store, user.User{TenantID: tenantA, Name: "One", Email: "1@example.com", Password: "hash"})
+		id2 := mustCreate(t, store, user.User{TenantID: tenantA, Name: "Two", Email: "2@example.com", Password: "hash"})
+		idB := mustCreate(t, store, user.User{TenantID: tenantB, Name: "B", Email: "b@example.com", Password: "hash"})
+		ctx := auth.WithTenant(context.Background(), tenantA)
+
+		users, err := store.FindUsersByIds(ctx, []string{id2, idB, primitive.NewObjectID().Hex(), "malformed", id1})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"1@example.com", "2@example.com"}, emails(users))
+
+		users, err = store.FindUsersByIds(ctx, nil)
+		require.NoError(t, err)
+		assert.Empty(t, users)
+	})
+
+	t.Run("list and export in creation order", func(t *testing.T) {
+		store := newStore(t)
+		ctx := auth.WithTenant(context.Background(), tenantA)
+		for _, email := range []string{"1@example.com", "2@example.com", "3@example.com"} {
+			mustCreate(t, store, user.User{TenantID: tenantA, Name: "U", Email: email, Password: "hash"})
+			// Keep creation times distinct on backends with millisecond precision.
+			time.Sleep(2 * time.Millisecond)
+		}
+		want := []string{"1@example.com", "2@example.com", "3@example.com"}
+
+		users, err := store.FindUsers(ctx, nil)
+		require.NoError(t, err)
+		assert.Equal(t, want, emails(users))
+
+		var exported []user.FindUserResponse
+		err = store.ExportUsers(ctx, nil, user.ExportFields, func(u user.FindUserResponse) error {
+			exported = append(exported, u)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, users, exported)
+
+		stop := assert.AnError
+		calls := 0
+		err = store.ExportUsers(ctx, nil, user.ExportFields, func(user.FindUserResponse) error {
+			calls++
+			return stop
+		})
+		assert.ErrorIs(t, err, stop)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		store := newStore(t)
+		ctx := context.Background()
+		id := mustCreate(t, store, user.User{Name: "Old", Email: "old@example.com", Password: "hash"})
+		mustCreate(t, store, user.User{Name: "Other", Email: "other@example.com", Password: "hash"})
+		oid, _ := primitive.ObjectIDFromHex(id)
+
+		matched, err := store.UpdateUser(ctx, user.User{ID: oid, Name: "New", Email: "new@example.com", Password: "newhash"})
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, matched)
+		found, err := store.FindUserByEmail(ctx, "new@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "New", found.Name)
+		assert.Equal(t, "newhash", found.Password)
+
+		_, err = store.UpdateUser(ctx, user.User{ID: oid, Email: "other@example.com"})
+		assert.ErrorIs(t, err, user.ErrEmailAlreadyExists)
+
+		matched, err = store.UpdateUser(ctx, user.User{ID: primitive.NewObjectID(), Name: "Ghost"})
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, matched)
+	})
+
+	t.Run("password history", func(t *testing.T) {
+		store := newStore(t)
+		ctx := context.Background()
+		id := mustCreate(t, store, user.User{Name: "Hist", Email: "hist@example.com", Password: "hash1"})
+		oid, _ := primitive.ObjectIDFromHex(id)
+
+		found, err := store.FindUserByEmail(ctx, "hist@example.com")
+		require.NoError(t, err)
+		assert.Empty(t, found.PasswordHistory)
+
+		_, err = store.UpdateUser(ctx, user.User{ID: oid, Password: "hash2", PasswordHistory: []string{"hash1"}})
+		require.NoError(t, err)
+		found, err = store.FindUserByEmail(ctx, "hist@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "hash2", found.Password)
+		assert.Equal(t, []string{"hash1"}, found.PasswordHistory)
+
+		_, err = store.UpdateUser(ctx, user.User{ID: oid, Name: "Renamed"})
+		require.NoError(t, err)
+		found, err = store.FindUserByEmail(ctx, "hist@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"hash1"}, found.PasswordHistory)
+	})
+
+	t.Run("rehash password", func(t *testing.T) {
+		store := newStore(t)
+		ctx := context.Background()
+		id := mustCreate(t, store, user.User{Name: "Re", Email: "re@example.com", Password: "old"})
+		oid, _ := primitive.ObjectIDFromHex(id)
+
+		changed, err := store.RehashPassword(ctx, oid, "stale", "new")
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, changed)
+
+		changed, err = store.RehashPassword(ctx, oid, "old", "new")
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, changed)
+		found, err := store.FindUserByEmail(ctx, "re@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "new", found.Password)
+	})
+
+	t.Run("last login", func(t *testing.T) {
+		store := newStore(t)
+		ctx := context.Background()
+		id := mustCreate(t, store, user.User{Name: "Seen", Email: "seen@example.com", Password: "hash"})
+		oid, _ := primitive.ObjectIDFromHex(id)
+
+		found, err := store.FindUserById(ctx, id)
+		require.NoError(t, err)
+		assert.Nil(t, found.LastLoginAt)
+
+		at := time.Now()
+		require.NoError(t, store.UpdateLastLogin(ctx, oid, at))
+		found, err = store.FindUserById(ctx, id)
+		require.NoError(t, err)
+		require.NotNil(t, found.LastLoginAt)
+		assert.WithinDuration(t, at, *found.LastLoginAt, time.Millisecond)
+		users, err := store.FindUsers(ctx, nil)
+		require.NoError(t, err)
+		require.Len(t, users, 1)
+		assert.NotNil(t, users[0].LastLoginAt)
+	})
+
+	t.Run("status", func(t *testing.T) {
+		store := newStore(t)
+		ctx := context.Background()
+		id := mustCreate(t, store, user.User{TenantID: tenantA, Name: "Status", Email: "status@example.com", Password: "hash"})
+		pending := mustCreate(t, store, user.User{TenantID: tenantA, Name: "Pending", Email: "pending@example.com", Password: "hash", Status: user.StatusPending})
+		oid, _ := primitive.ObjectIDFromHex(id)
+
+		found, err := store.FindUserById(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, user.StatusActive, found.Status)
+		found, err = store.FindUserById(ctx, pending)
+		require.NoError(t, err)
+		assert.Equal(t, user.StatusPending, found.Status)
+		history, err := store.FindStatusHistory(ctx, id)
+		require.NoError(t, err)
+		assert.Empty(t, history)
+
+		at := time.Now()
+		suspend := user.StatusChange{From: user.StatusActive, To: user.StatusSuspended, Reason: "spam", ChangedBy: "admin", ChangedAt: at}
+		updated, err := store.UpdateStatus(ctx, oid, user.StatusActive, suspend)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, updated)
+		// The status is no longer active, so the same change does nothing.
+		updated, err = store.UpdateStatus(ctx, oid, user.StatusActive, suspend)
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, updated)
+		updated, err = store.UpdateStatus(auth.WithTenant(ctx, tenantB), oid, user.StatusSuspended,
+			user.StatusChange{From: user.StatusSuspended, To: user.StatusActive, ChangedAt: at})
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, updated)
+		updated, err = store.UpdateStatus(ctx, oid, user.StatusSuspended,
+			user.StatusChange{From: user.StatusSuspended, To: user.StatusActive, ChangedAt: at})
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, updated)
+
+		found, err = store.FindUserById(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, user.StatusActive, found.Status)
+		byEmail, err := store.FindUserByEmail(auth.WithTenant(ctx, tenantA), "status@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, user.StatusActive, byEmail.Status)
+		history, err = store.FindStatusHistory(ctx, id)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Equal(t, "spam", history[0].Reason)
+		assert.Equal(t, "admin", history[0].ChangedBy)
+		assert.WithinDuration(t, at, history[0].ChangedAt, time.Millisecond)
+		assert.Equal(t, user.StatusActive, history[1].To)
+		_, err = store.FindStatusHistory(auth.WithTenant(ctx, tenantB), id)
+		assert.ErrorIs(t, err, user.ErrUserNotFound)
+	})
+
+	t.Run("profile", func(t *testing.T) {
+		store := newStore(t)
+		ctx := auth.WithTenant(context.Background(), tenantA)
+		// Values have the Go types profile.Schema.Validate gives them.
+		alice := mustCreate(t, store, user.User{TenantID: tenantA, Name: "Alice", Email: "alice@example.com", Password: "hash",
+			Profile: map[string]any{"department": "Sales", "level": int64(3), "remote": true, "score": 4.5}})
+		mustCreate(t, store, user.User{TenantID: tenantA, Name: "Bob", Email: "bob@example.com", Password: "hash",
+			Profile: map[string]any{"department": "Ops", "level": int64(3), "remote": false}})
+		mustCreate(t, store, user.User{TenantID: tenantA, Name: "Carol", Email: "carol@example.com", Password: "hash"})
+		mustCreate(t, store, user.User{TenantID: tenantB, Name: "Dave", Email: "dave@example.com", Password: "hash",
+			Profile: map[string]any{"department": "Sales"}})
+
+		found, err := store.FindUserById(ctx, alice)
+		require.NoError(t, err)
+		assert.Equal(t, "Sales", found.Profile["department"])
+		assert.EqualValues(t, 3, found.Profile["level"])
+		assert.Equal(t, true, found.Profile["remote"])
+		assert.EqualValues(t, 4.5, found.Profile["score"])
+
+		filters := []struct {
+			filter map[string]any
+			want   []string
+		}{
+			{map[string]any{"department": "Sales"}, []string{"alice@example.com"}},
+			{map[string]any{"level": int64(3)}, []string{"alice@example.com", "bob@example.com"}},
+			{map[string]any{"level": int64(3), "remote": false}, []string{"bob@example.com"}},
+			{map[string]any{"score": 4.5}, []string{"alice@example.com"}},
+			{map[string]any{"department": "Sales", "level": int64(4)}, []string{}},
+		}
+		for _, f := range filters {
+			users, err := store.FindUsers(ctx, f.filter)
+			require.NoError(t, err)
+			assert.Equal(t, f.want, emails(users), "filter %v", f.filter)
+		}
+		users, err := store.FindUsers(context.Background(), map[string]any{"department": "Sales"})
+		require.NoError(t, err)
+		assert.Len(t, users, 2)
+
+		// Exports filter like listings.
+		var exported []user.FindUserResponse
+		err = store.ExportUsers(ctx, map[string]any{"level": int64(3), "remote": false}, user.ExportFields, func(u user.FindUserResponse) error {
+			exported = append(exported, u)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"bob@example.com"}, emails(exported))
+
+		// A profile given to UpdateUser replaces the stored one.
+		oid, _ := primitive.ObjectIDFromHex(alice)
+		updated, err := store.UpdateUser(ctx, user.User{ID: oid, Profile: map[string]any{"department": "Ops"}})
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, updated)
+		found, err = store.FindUserById(ctx, alice)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"department": "Ops"}, found.Profile)
+		assert.Equal(t, "Alice", found.Name)
+		updated, err = store.UpdateUser(ctx, user.User{ID: oid, Name: "Alice B"})
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, updated)
+		found, err = store.FindUserById(ctx, alice)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"department": "Ops"}, found.Profile)
+		users, err = store.FindUsers(ctx, map[string]any{"department": "Ops"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"alice@example.com", "bob@example.com"}, emails(users))
+	})
+
+	t.Run("avatar", func(t *testing.T) {
+		store := newStore(t)
+		ctx := auth.WithTenant(context.Background(), tenantA)
+		id, err := store.CreateUser(ctx, user.User{TenantID: tenantA, Name: "Alice", Email: "alice@example.com", Password: "hash"})
+		require.NoError(t, err)
+		found, err := store.FindUserById(ctx, id)
+		require.NoError(t, err)
+		assert.Empty(t, found.AvatarID)
+
+		oid, _ := primitive.ObjectIDFromHex(id)
+		updated, err := store.UpdateUser(ctx, user.User{ID: oid, AvatarID: "a1"})
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, updated)
+		found, err = store.FindUserById(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, "a1", found.AvatarID)
+		assert.Equal(t, "Alice", found.Name)
+		users, err := store.FindUsers(ctx, nil)
+		require.NoError(t, err)
+		require.Len(t, users, 1)
+		assert.Equal(t, "a1", users[0].AvatarID)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		store := newStore(t)
+		ctx := context.Background()
+		id := mustCreate(t, store, user.User{Name: "Gone", Email: "gone@example.com", Password: "hash"})
+
+		deleted, err := store.DeleteUser(ctx, id)
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, deleted)
+		deleted, err = store.DeleteUser(ctx, id)
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, deleted)
+		_, err = store.FindUserById(ctx, id)
+		assert.ErrorIs(t, err, user.ErrUserNotFound)
+	})
+
+	t.Run("create many", func(t *testing.T) {
+		store := newStore(t)
+		ctx := context.Background()
+		mustCreate(t, store, user.User{TenantID: tenantA, Name: "Existing", Email: "taken@example.com", Password: "hash"})
+
+		users := []user.User{
+			{ID: primitive.NewObjectID(), TenantID: tenantA, Name: "One", Email: "one@example.com", Password: "hash"},
+			{ID: primitive.NewObjectID(), TenantID: tenantA, Name: "Dup", Email: "taken@example.com", Password: "hash"},
+			{ID: primitive.NewObjectID(), TenantID: tenantA, Name: "Two", Email: "two@example.com", Password: "hash"},
+		}
+		errs, err := store.CreateUsers(ctx, users)
+		require.NoError(t, err)
+		require.Len(t, errs, 3)
+		assert.NoError(t, errs[0])
+		assert.ErrorIs(t, errs[1], user.ErrEmailAlreadyExists)
+		assert.NoError(t, errs[2])
+
+		found, err := store.FindUserById(ctx, users[2].ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, "two@example.com", found.Email)
+		count, err := store.CountUsers(auth.WithTenant(ctx, tenantA))
+		require.NoError(t, err)
+		assert.EqualValues(t, 3, count)
+	})
+}
+
+func mustCreate(t *testing.T, store user.Store, u user.User) string {
+	t.Helper()
+	id, err := store.CreateUser(context.Background(), u)
+	require.NoError(t, err)
+	return id
+}
+
+func emails(users []user.FindUserResponse) []string {
+	out := make([]string, len(users))
+	for i, u := range users {
+		out[i] = u.Email
+	}
+	return out
+}
@@ -0,0 +1,21 @@
+package usertest
+
+import (
+	"testing"
+	"user-management/config"
+
+	env "github.com/caarlos0/env/v10"
+	"github.com/stretchr/testify/require"
+)
+
+// MongoConfig names every collection the Mongo migrations touch, using the
+// configured defaults, so that migrating a test database creates all their
+// indexes. Collections added later get their names from their defaults.
+func MongoConfig(t *testing.T) config.MongoConfig {
+	t.Helper()
+	var cfg config.MongoConfig
+	// An empty environment keeps the caller's MONGO_CONFIG_* variables out.
+	require.NoError(t, env.ParseWithOptions(&cfg, env.Options{Environment: map[string]string{}}))
+	cfg.UserCollection = "users"
+	return cfg
+}
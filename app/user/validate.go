@@ -2,7 +2,9 @@ package user
 
 import (
 	"net/mail"
+	"slices"
 	"strings"
+	"user-management/auth"
 	"user-management/response"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -21,6 +23,17 @@ func (r CreateRequest) RequestValidation() *response.StdResp[any] {
 	if checkLen(r.Password) == 0 {
 		return response.MandatoryMissing("password")
 	}
+	if r.Role != "" && !isValidRole(r.Role) {
+		return response.InvalidData("role")
+	}
+	if r.Status != "" && r.Status != StatusActive && r.Status != StatusPending {
+		return response.InvalidData("status")
+	}
+	if r.TenantID != "" {
+		if _, err := primitive.ObjectIDFromHex(r.TenantID); err != nil {
+			return response.InvalidData("tenant_id")
+		}
+	}
 	return response.Success()
 }
 
@@ -38,8 +51,8 @@ func (r SignInRequest) RequestValidation() *response.StdResp[any] {
 }
 
 func (r UpdateRequest) RequestValidation() *response.StdResp[any] {
-	if checkLen(r.Email) == 0 && checkLen(r.Name) == 0 {
-		return response.MandatoryMissing("name or email")
+	if checkLen(r.Email) == 0 && checkLen(r.Name) == 0 && r.Profile == nil {
+		return response.MandatoryMissing("name, email or profile")
 	}
 	if checkLen(r.Email) != 0 && !isValidEmail(r.Email) {
 		return response.InvalidData("email")
@@ -47,6 +60,63 @@ func (r UpdateRequest) RequestValidation() *response.StdResp[any] {
 	return response.Success()
 }
 
+func (r StatusRequest) RequestValidation() *response.StdResp[any] {
+	if checkLen(r.Status) == 0 {
+		return response.MandatoryMissing("status")
+	}
+	if !isValidStatus(r.Status) {
+		return response.InvalidData("status")
+	}
+	if reasonRequired(r.Status) && checkLen(r.Reason) == 0 {
+		return response.MandatoryMissing("reason")
+	}
+	return response.Success()
+}
+
+func ImportFormatValidation(format string) *response.StdResp[any] {
+	if format == "" {
+		return response.MandatoryMissing(QueryFormat)
+	}
+	if format != ImportFormatCSV && format != ImportFormatJSONL {
+		return response.InvalidData(QueryFormat)
+	}
+	return response.Success()
+}
+
+func ExportFormatValidation(format string) *response.StdResp[any] {
+	switch format {
+	case ExportFormatCSV, ExportFormatJSONL, ExportFormatNDJSON:
+		return response.Success()
+	}
+	return response.InvalidData(QueryFormat)
+}
+
+// ExportFieldsValidation checks the requested fields and returns them in
+// ExportFields order without duplicates. No fields means all of them.
+func ExportFieldsValidation(fields []string) ([]string, *response.StdResp[any]) {
+	requested := map[string]bool{}
+	for _, f := range fields {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		if !slices.Contains(ExportFields, f) {
+			return nil, response.InvalidData(QueryFields)
+		}
+		requested[f] = true
+	}
+	if len(requested) == 0 {
+		return ExportFields, response.Success()
+	}
+	ordered := make([]string, 0, len(requested))
+	for _, f := range ExportFields {
+		if requested[f] {
+			ordered = append(ordered, f)
+		}
+	}
+	return ordered, response.Success()
+}
+
 func IdValidation(id string) *response.StdResp[any] {
 	_, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -59,6 +129,10 @@ func checkLen(s string) int {
 	return len([]rune(strings.TrimSpace(s)))
 }
 
+func isValidRole(role string) bool {
+	return role == auth.RoleAdmin || role == auth.RoleUser
+}
+
 func isValidEmail(email string) bool {
 	_, err := mail.ParseAddress(email)
 	return err == nil
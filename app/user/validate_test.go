@@ -20,6 +20,8 @@ func TestUser_RequestValidation(t *testing.T) {
 		{"Valid input 1", user.CreateRequest{Name: "Alice", Password: "pass123"}, response.MandatoryMissing("email")},
 		{"Invalid email", user.CreateRequest{Name: "Alice", Email: "bad-email", Password: "pass123"}, response.InvalidData("email")},
 		{"Missing password", user.CreateRequest{Name: "Alice", Email: "alice@example.com"}, response.MandatoryMissing("password")},
+		{"Pending", user.CreateRequest{Name: "Alice", Email: "alice@example.com", Password: "pass123", Status: user.StatusPending}, response.Success()},
+		{"Created suspended", user.CreateRequest{Name: "Alice", Email: "alice@example.com", Password: "pass123", Status: user.StatusSuspended}, response.InvalidData("status")},
 	}
 
 	for _, tt := range tests {
@@ -70,6 +72,27 @@ func TestUpdateRequest_RequestValidation(t *testing.T) {
 	}
 }
 
+func TestStatusRequest_RequestValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		input user.StatusRequest
+		want  *response.StdResp[any]
+	}{
+		{"Activate", user.StatusRequest{Status: user.StatusActive}, response.Success()},
+		{"Suspend with reason", user.StatusRequest{Status: user.StatusSuspended, Reason: "spam"}, response.Success()},
+		{"Suspend without reason", user.StatusRequest{Status: user.StatusSuspended, Reason: " "}, response.MandatoryMissing("reason")},
+		{"Disable without reason", user.StatusRequest{Status: user.StatusDisabled}, response.MandatoryMissing("reason")},
+		{"Missing status", user.StatusRequest{}, response.MandatoryMissing("status")},
+		{"Unknown status", user.StatusRequest{Status: "banned", Reason: "spam"}, response.InvalidData("status")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.input.RequestValidation())
+		})
+	}
+}
+
 func TestIdValidation(t *testing.T) {
 	validID := primitive.NewObjectID().Hex()
 	invalidID := "not_a_valid_id"
@@ -77,3 +100,32 @@ func TestIdValidation(t *testing.T) {
 	assert.Equal(t, "0000", user.IdValidation(validID).Code)
 	assert.Equal(t, "4004", user.IdValidation(invalidID).Code)
 }
+
+func TestExportFieldsValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      []string
+		wantFields []string
+		wantResp   *response.StdResp[any]
+	}{
+		{"All by default", nil, user.ExportFields, response.Success()},
+		{"Ordered and deduplicated", []string{"email", " id", "email"}, []string{"id", "email"}, response.Success()},
+		{"Password is not exportable", []string{"id", "password"}, nil, response.InvalidData(user.QueryFields)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, resp := user.ExportFieldsValidation(tt.input)
+			assert.Equal(t, tt.wantFields, fields)
+			assert.Equal(t, tt.wantResp, resp)
+		})
+	}
+}
+
+func TestImportExportFormatValidation(t *testing.T) {
+	assert.Equal(t, response.Success(), user.ImportFormatValidation(user.ImportFormatCSV))
+	assert.Equal(t, response.MandatoryMissing(user.QueryFormat), user.ImportFormatValidation(""))
+	assert.Equal(t, response.InvalidData(user.QueryFormat), user.ImportFormatValidation(user.ExportFormatNDJSON))
+	assert.Equal(t, response.Success(), user.ExportFormatValidation(user.ExportFormatNDJSON))
+	assert.Equal(t, response.InvalidData(user.QueryFormat), user.ExportFormatValidation("xml"))
+}
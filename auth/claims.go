@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	ClaimsContext = "authClaimsContext"
+	TenantContext = "authTenantContext"
+)
+
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+// Claims is the JWT payload issued by Login and verified by the auth middlewares.
+// An empty TenantID marks a platform-level account that is not bound to a tenant.
+// SessionID names the session Login started; tokens issued before sessions
+// existed have none. Scopes is set for machine credentials such as API keys,
+// which may only call what their scopes allow; user logins have none.
+// ClientID names the OAuth client a client credentials token was issued to.
+type Claims struct {
+	UserID    string   `json:"uid,omitempty"`
+	TenantID  string   `json:"tid,omitempty"`
+	SessionID string   `json:"sid,omitempty"`
+	Role      string   `json:"role,omitempty"`
+	Groups    []string `json:"groups,omitempty"`
+	Scopes    []string `json:"scopes,omitempty"`
+	ClientID  string   `json:"cid,omitempty"`
+	jwt.RegisteredClaims
+}
+
+func (c *Claims) IsAdmin() bool {
+	return c.Role == RoleAdmin
+}
+
+// IsScoped reports whether the caller is limited to its scopes. Client tokens
+// always are, even if their scopes were lost on the way.
+func (c *Claims) IsScoped() bool {
+	return c.Scopes != nil || c.ClientID != ""
+}
+
+// IsPlatformAdmin reports whether the caller is an admin that is not scoped to a tenant.
+// Scoped callers never are, even without a tenant: their scopes would not hold.
+func (c *Claims) IsPlatformAdmin() bool {
+	return c.IsAdmin() && c.TenantID == "" && !c.IsScoped()
+}
+
+func WithClaims(ctx context.Context, c *Claims) context.Context {
+	return context.WithValue(ctx, ClaimsContext, c)
+}
+
+func FromContext(ctx context.Context) (*Claims, error) {
+	c, ok := ctx.Value(ClaimsContext).(*Claims)
+	if !ok {
+		return nil, errors.New("unable get claims from context")
+	}
+	return c, nil
+}
+
+// WithTenant scopes ctx to tenantID, overriding the tenant carried by the claims.
+// An empty tenantID scopes ctx to platform-level accounts only.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, TenantContext, tenantID)
+}
+
+// TenantFromContext returns the tenant the request is scoped to. scoped is false for
+// platform requests, which are allowed to see every tenant.
+func TenantFromContext(ctx context.Context) (tenantID string, scoped bool) {
+	if tenantID, ok := ctx.Value(TenantContext).(string); ok {
+		return tenantID, true
+	}
+	if c, err := FromContext(ctx); err == nil && c.TenantID != "" {
+		return c.TenantID, true
+	}
+	return "", false
+}
@@ -0,0 +1,31 @@
+package auth
+
+import "slices"
+
+// Scopes machine credentials can be granted. Each covers the routes and RPCs
+// that read or change one kind of resource.
+const (
+	ScopeUsersRead   = "users:read"
+	ScopeUsersWrite  = "users:write"
+	ScopeGroupsRead  = "groups:read"
+	ScopeGroupsWrite = "groups:write"
+)
+
+// Scopes lists every scope, in the order they are documented.
+var Scopes = []string{ScopeUsersRead, ScopeUsersWrite, ScopeGroupsRead, ScopeGroupsWrite}
+
+// ValidScope reports whether scope is one of Scopes.
+func ValidScope(scope string) bool {
+	return slices.Contains(Scopes, scope)
+}
+
+// UniqueScopes drops repeated scopes, keeping the first of each.
+func UniqueScopes(scopes []string) []string {
+	var unique []string
+	for _, s := range scopes {
+		if !slices.Contains(unique, s) {
+			unique = append(unique, s)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,44 @@
+// Package blob keeps uploaded files, such as avatar images, under string keys
+// in GridFS or on the local filesystem. Keys are slash separated paths like
+// "avatars/<id>/64.jpg"; callers write each key once and never rewrite it.
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"user-management/config"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	BackendGridFS = "gridfs"
+	BackendFile   = "file"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+type Store interface {
+	Put(ctx context.Context, key string, data []byte) error
+	// Get returns ErrNotFound when nothing is stored under key.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete removes key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// NewStore returns the store selected by cfg.Backend. db is only used by the
+// gridfs backend.
+func NewStore(cfg config.BlobConfig, db *mongo.Database) (Store, error) {
+	switch cfg.Backend {
+	case BackendGridFS:
+		if db == nil {
+			return nil, errors.New("the gridfs blob store needs MongoDB")
+		}
+		return NewGridFSStore(db, cfg.GridFSBucket), nil
+	case BackendFile:
+		return NewFileStore(cfg.Dir)
+	default:
+		return nil, fmt.Errorf("unknown blob store backend %q", cfg.Backend)
+	}
+}
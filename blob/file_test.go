@@ -0,0 +1,47 @@
+package blob_test
+
+import (
+	"context"
+	"testing"
+	"user-management/blob"
+	"user-management/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := blob.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put(ctx, "avatars/a1/64.jpg", []byte("image")))
+	data, err := store.Get(ctx, "avatars/a1/64.jpg")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("image"), data)
+
+	require.NoError(t, store.Delete(ctx, "avatars/a1/64.jpg"))
+	_, err = store.Get(ctx, "avatars/a1/64.jpg")
+	assert.ErrorIs(t, err, blob.ErrNotFound)
+	assert.NoError(t, store.Delete(ctx, "avatars/a1/64.jpg"), "deleting a missing blob")
+}
+
+func TestFileStore_KeyOutsideDir(t *testing.T) {
+	store, err := blob.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"../escape", "/etc/passwd", ""} {
+		assert.Error(t, store.Put(context.Background(), key, []byte("x")), key)
+	}
+}
+
+func TestNewStore(t *testing.T) {
+	store, err := blob.NewStore(config.BlobConfig{Backend: blob.BackendFile, Dir: t.TempDir()}, nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, store)
+
+	_, err = blob.NewStore(config.BlobConfig{Backend: blob.BackendGridFS}, nil)
+	assert.Error(t, err)
+	_, err = blob.NewStore(config.BlobConfig{Backend: "s3"}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,73 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// gridfsStore keeps blobs in a GridFS bucket, using the key as the file id.
+type gridfsStore struct {
+	db     *mongo.Database
+	bucket string
+}
+
+func NewGridFSStore(db *mongo.Database, bucket string) *gridfsStore {
+	return &gridfsStore{db: db, bucket: bucket}
+}
+
+func (s *gridfsStore) Put(ctx context.Context, key string, data []byte) error {
+	b, err := s.open(ctx)
+	if err != nil {
+		return err
+	}
+	return b.UploadFromStreamWithID(key, key, bytes.NewReader(data))
+}
+
+func (s *gridfsStore) Get(ctx context.Context, key string) ([]byte, error) {
+	b, err := s.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if _, err := b.DownloadToStream(key, &buf); err != nil {
+		if errors.Is(err, gridfs.ErrFileNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *gridfsStore) Delete(ctx context.Context, key string) error {
+	b, err := s.open(ctx)
+	if err != nil {
+		return err
+	}
+	if err := b.DeleteContext(ctx, key); err != nil && !errors.Is(err, gridfs.ErrFileNotFound) {
+		return err
+	}
+	return nil
+}
+
+// open returns a bucket for one operation. Buckets keep their deadlines as
+// state, so they are not shared between requests.
+func (s *gridfsStore) open(ctx context.Context) (*gridfs.Bucket, error) {
+	b, err := gridfs.NewBucket(s.db, options.GridFSBucket().SetName(s.bucket))
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := b.SetReadDeadline(deadline); err != nil {
+			return nil, err
+		}
+		if err := b.SetWriteDeadline(deadline); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
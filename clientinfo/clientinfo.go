@@ -0,0 +1,29 @@
+// Package clientinfo carries what is known about the caller of a request,
+// whichever transport it arrived on.
+package clientinfo
+
+import "context"
+
+const InfoContext = "clientInfoContext"
+
+const (
+	ProtocolREST = "rest"
+	ProtocolGRPC = "grpc"
+)
+
+type Info struct {
+	IP        string
+	UserAgent string
+	Protocol  string
+}
+
+func WithInfo(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, InfoContext, info)
+}
+
+// FromContext returns the caller stored in ctx, or a zero Info when the
+// request did not come through a server middleware, e.g. from the CLI.
+func FromContext(ctx context.Context) Info {
+	info, _ := ctx.Value(InfoContext).(Info)
+	return info
+}
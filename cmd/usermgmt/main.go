@@ -0,0 +1,120 @@
+// Command usermgmt is the operator CLI for user management tasks. It reads the
+// same environment configuration as the server and talks to the database
+// directly, acting as a platform admin.
+package main
+
+import (
+	"context"
+	"os"
+	"user-management/app/avatar"
+	"user-management/app/federation"
+	"user-management/app/group"
+	"user-management/app/invite"
+	"user-management/app/loginhistory"
+	"user-management/app/oidc"
+	"user-management/app/privacy"
+	"user-management/app/profile"
+	"user-management/app/session"
+	"user-management/app/tenant"
+	"user-management/app/user"
+	"user-management/blob"
+	"user-management/config"
+	"user-management/passhash"
+	"user-management/storage"
+)
+
+func main() {
+	if err := newRootCmd(connectUsecase, connectMigrators, connectPrivacy).Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func connectUsecase(ctx context.Context) (user.Usecase, func(), error) {
+	cfg, err := config.NewAppConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	mongo := storage.InitMongoConnection(ctx, cfg.MongoDB)
+
+	repo, closeRepo, err := user.NewStore(ctx, cfg.Storage, cfg.Encryption, mongo, cfg.MongoDB)
+	if err != nil {
+		mongo.Disconnect(context.Background())
+		return nil, nil, err
+	}
+	hasher, err := passhash.NewHasher(cfg.PasswordHash)
+	if err != nil {
+		closeRepo()
+		mongo.Disconnect(context.Background())
+		return nil, nil, err
+	}
+	policy, err := user.NewPasswordPolicy(cfg.PasswordPolicy)
+	if err != nil {
+		closeRepo()
+		mongo.Disconnect(context.Background())
+		return nil, nil, err
+	}
+	logins := loginhistory.NewUsecase(cfg.LoginHistory, loginhistory.NewRepository(mongo, cfg.MongoDB))
+	sessions := session.NewUsecase(cfg.Session, session.NewRepository(mongo, cfg.MongoDB))
+	uc := user.NewUsecase(cfg.Crypto, policy, hasher, repo, tenant.NewRepository(mongo, cfg.MongoDB), group.NewRepository(mongo, cfg.MongoDB), logins, sessions, profile.NewRepository(mongo, cfg.MongoDB))
+	return uc, func() {
+		policy.Close()
+		closeRepo()
+		mongo.Disconnect(context.Background())
+	}, nil
+}
+
+func connectPrivacy(ctx context.Context) (privacyUsecase, func(), error) {
+	cfg, err := config.NewAppConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	mongo := storage.InitMongoConnection(ctx, cfg.MongoDB)
+
+	repo, closeRepo, err := user.NewStore(ctx, cfg.Storage, cfg.Encryption, mongo, cfg.MongoDB)
+	if err != nil {
+		mongo.Disconnect(context.Background())
+		return nil, nil, err
+	}
+	blobStore, err := blob.NewStore(cfg.Blob, mongo.Database())
+	if err != nil {
+		closeRepo()
+		mongo.Disconnect(context.Background())
+		return nil, nil, err
+	}
+	// Erasure only deletes avatar images, which does not go through users.
+	avatars := avatar.NewUsecase(cfg.Avatar, blobStore, nil)
+	uc := privacy.NewUsecase(privacy.ReceiptKey(cfg), repo, loginhistory.NewRepository(mongo, cfg.MongoDB), session.NewRepository(mongo, cfg.MongoDB), group.NewRepository(mongo, cfg.MongoDB), federation.NewRepository(mongo, cfg.MongoDB), oidc.NewRepository(mongo, cfg.MongoDB), invite.NewRepository(mongo, cfg.MongoDB), avatars)
+	return uc, func() {
+		closeRepo()
+		mongo.Disconnect(context.Background())
+	}, nil
+}
+
+// connectMigrators returns the MongoDB migrator and, for SQL storage backends,
+// the SQL one. The SQL database is opened without migrating so that status
+// and --dry-run leave it untouched.
+func connectMigrators(ctx context.Context) ([]storage.Migrator, func(), error) {
+	cfg, err := config.NewAppConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	mongo := storage.InitMongoConnection(ctx, cfg.MongoDB)
+	migrators := []storage.Migrator{storage.NewMongoMigrator(mongo.Database(), storage.MongoMigrations(cfg.MongoDB))}
+	closeFn := func() { mongo.Disconnect(context.Background()) }
+
+	if cfg.Storage.Backend == storage.BackendPostgres || cfg.Storage.Backend == storage.BackendSQLite {
+		sqlCfg := cfg.Storage
+		sqlCfg.MigrateOnStart = false
+		conn, err := storage.OpenSQL(ctx, sqlCfg)
+		if err != nil {
+			closeFn()
+			return nil, nil, err
+		}
+		migrators = append(migrators, storage.NewSQLMigrator(conn))
+		closeFn = func() {
+			conn.Close()
+			mongo.Disconnect(context.Background())
+		}
+	}
+	return migrators, closeFn, nil
+}
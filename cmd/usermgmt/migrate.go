@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+	"user-management/storage"
+
+	"github.com/spf13/cobra"
+)
+
+func newMigrateCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply pending database migrations",
+		Long: "Apply pending migrations to MongoDB and, when STORAGE_BACKEND is a SQL backend, to the user database.\n" +
+			"With --dry-run the pending migrations are listed without being applied.",
+		Args: cobra.NoArgs,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := c.validate(); err != nil {
+				return err
+			}
+			migrators, closeFn, err := c.newMigrators(cmd.Context())
+			if err != nil {
+				return err
+			}
+			c.migrators, c.close = migrators, closeFn
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var result []storage.MigrationStatus
+			for _, m := range c.migrators {
+				var done []storage.MigrationStatus
+				var err error
+				if c.dryRun {
+					var status []storage.MigrationStatus
+					status, err = m.Status(cmd.Context())
+					done = storage.Pending(status)
+				} else {
+					done, err = m.Up(cmd.Context())
+				}
+				result = append(result, done...)
+				if err != nil {
+					writeMigrations(cmd.OutOrStdout(), c.output, result)
+					return err
+				}
+			}
+			return writeMigrations(cmd.OutOrStdout(), c.output, result)
+		},
+	}
+	cmd.AddCommand(newMigrateStatusCmd(c))
+	return cmd
+}
+
+func newMigrateStatusCmd(c *cli) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "List migrations and whether they have been applied",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var result []storage.MigrationStatus
+			for _, m := range c.migrators {
+				status, err := m.Status(cmd.Context())
+				if err != nil {
+					return err
+				}
+				result = append(result, status...)
+			}
+			return writeMigrations(cmd.OutOrStdout(), c.output, result)
+		},
+	}
+}
+
+func writeMigrations(w io.Writer, format string, migrations []storage.MigrationStatus) error {
+	if format == outputJSON {
+		if migrations == nil {
+			migrations = []storage.MigrationStatus{}
+		}
+		return writeJSON(w, migrations)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TARGET\tVERSION\tDESCRIPTION\tAPPLIED")
+	for _, m := range migrations {
+		applied := "pending"
+		if m.AppliedAt != nil {
+			applied = m.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", m.Target, m.Version, m.Description, applied)
+	}
+	return tw.Flush()
+}
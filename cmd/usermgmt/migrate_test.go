@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+	"user-management/storage"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeMigrator struct {
+	status []storage.MigrationStatus
+	err    error
+	ups    int
+}
+
+func (f *fakeMigrator) Status(ctx context.Context) ([]storage.MigrationStatus, error) {
+	return f.status, nil
+}
+
+func (f *fakeMigrator) Up(ctx context.Context) ([]storage.MigrationStatus, error) {
+	f.ups++
+	pending := storage.Pending(f.status)
+	now := time.Now()
+	for i := range pending {
+		pending[i].AppliedAt = &now
+	}
+	return pending, f.err
+}
+
+func newFakeMigrator() *fakeMigrator {
+	applied := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	return &fakeMigrator{status: []storage.MigrationStatus{
+		{Target: storage.BackendMongo, Version: 1, Description: "create user indexes", AppliedAt: &applied},
+		{Target: storage.BackendMongo, Version: 2, Description: "create tenant indexes"},
+	}}
+}
+
+func runMigrate(m storage.Migrator, args ...string) (string, error) {
+	cmd := newRootCmd(nil, func(ctx context.Context) ([]storage.Migrator, func(), error) {
+		return []storage.Migrator{m}, func() {}, nil
+	}, nil)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestMigrate(t *testing.T) {
+	m := newFakeMigrator()
+
+	out, err := runMigrate(m, "migrate", "-o", "json")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, m.ups)
+	var applied []storage.MigrationStatus
+	assert.NoError(t, json.Unmarshal([]byte(out), &applied))
+	assert.Len(t, applied, 1)
+	assert.Equal(t, 2, applied[0].Version)
+	assert.NotNil(t, applied[0].AppliedAt)
+}
+
+func TestMigrate_DryRun(t *testing.T) {
+	m := newFakeMigrator()
+
+	out, err := runMigrate(m, "migrate", "--dry-run")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, m.ups)
+	assert.Contains(t, out, "create tenant indexes")
+	assert.Contains(t, out, "pending")
+	assert.NotContains(t, out, "create user indexes")
+}
+
+func TestMigrate_Error(t *testing.T) {
+	m := newFakeMigrator()
+	m.err = errors.New("boom")
+
+	_, err := runMigrate(m, "migrate")
+
+	assert.EqualError(t, err, "boom")
+}
+
+func TestMigrateStatus(t *testing.T) {
+	out, err := runMigrate(newFakeMigrator(), "migrate", "status")
+
+	assert.NoError(t, err)
+	assert.Contains(t, out, "2025-01-02T03:04:05Z")
+	assert.Contains(t, out, "pending")
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+	"user-management/app/user"
+	"user-management/response"
+)
+
+// respError turns an unsuccessful usecase response into a command error.
+func respError(resp *response.StdResp[any]) error {
+	if resp.IsSuccess() {
+		return nil
+	}
+	return fmt.Errorf("%s: %s", resp.Code, resp.Message)
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func writeUsers(w io.Writer, format string, users []user.FindUserResponse) error {
+	if format == outputJSON {
+		if users == nil {
+			users = []user.FindUserResponse{}
+		}
+		return writeJSON(w, users)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTENANT\tNAME\tEMAIL\tROLE\tCREATED")
+	for _, u := range users {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", u.Id, orDash(u.TenantID), u.Name, u.Email, orDash(u.Role), u.CreatedAt.Format(time.RFC3339))
+	}
+	return tw.Flush()
+}
+
+func writeImportReport(w io.Writer, format string, report user.ImportReport) error {
+	if format == outputJSON {
+		return writeJSON(w, report)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "LINE\tEMAIL\tSTATUS\tID\tERROR")
+	for _, r := range report.Rows {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", r.Line, orDash(r.Email), r.Status, orDash(r.Id), orDash(r.Error))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "total: %d, succeeded: %d, failed: %d, dry run: %t\n", report.Total, report.Succeeded, report.Failed, report.DryRun)
+	return err
+}
+
+// writeResult prints a single result, as JSON or as key/value lines.
+func writeResult(w io.Writer, format string, fields map[string]any, keys ...string) error {
+	if format == outputJSON {
+		return writeJSON(w, fields)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, k := range keys {
+		fmt.Fprintf(tw, "%s:\t%v\n", k, fields[k])
+	}
+	return tw.Flush()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+	"user-management/app/privacy"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newPrivacyCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "privacy",
+		Short: "Export and erase personal data",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := c.validate(); err != nil {
+				return err
+			}
+			uc, closeFn, err := c.newPrivacy(cmd.Context())
+			if err != nil {
+				return err
+			}
+			c.privacy, c.close = uc, closeFn
+			return nil
+		},
+	}
+	cmd.AddCommand(
+		newPrivacyExportCmd(c),
+		newPrivacyEraseCmd(c),
+		newPrivacyVerifyReceiptCmd(c),
+	)
+	return cmd
+}
+
+func newPrivacyExportCmd(c *cli) *cobra.Command {
+	var file string
+	cmd := &cobra.Command{
+		Use:   "export <user-id>",
+		Short: "Write everything held about a user as JSON",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			oid, err := primitive.ObjectIDFromHex(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid user id %q", args[0])
+			}
+			resp, err := c.privacy.ExportUserData(c.context(cmd.Context()), oid.Hex())
+			if err != nil {
+				return err
+			}
+			if err := respError(resp); err != nil {
+				return err
+			}
+			if file == "" {
+				return writeJSON(cmd.OutOrStdout(), resp.Data)
+			}
+			f, err := os.Create(file)
+			if err != nil {
+				return err
+			}
+			if err := writeJSON(f, resp.Data); err != nil {
+				f.Close()
+				return err
+			}
+			return f.Close()
+		},
+	}
+	cmd.Flags().StringVar(&file, "file", "", "write the export to this file instead of stdout")
+	return cmd
+}
+
+func newPrivacyEraseCmd(c *cli) *cobra.Command {
+	var file string
+	cmd := &cobra.Command{
+		Use:   "erase <user-id>",
+		Short: "Delete a user and everything held about them",
+		Long: "Delete a user with their sessions, login history, group memberships, OpenID Connect tokens, invitations and avatar, and print a signed receipt.\n" +
+			"With --dry-run the data that would be erased is counted instead; sessions count only the active ones.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			oid, err := primitive.ObjectIDFromHex(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid user id %q", args[0])
+			}
+			ctx := c.context(cmd.Context())
+			if c.dryRun {
+				resp, err := c.privacy.ExportUserData(ctx, oid.Hex())
+				if err != nil {
+					return err
+				}
+				if err := respError(resp); err != nil {
+					return err
+				}
+				export := resp.Data.(privacy.Export)
+				result := map[string]any{
+					"id":                         export.User.Id,
+					"email":                      export.User.Email,
+					privacy.DataSessions:         len(export.Sessions),
+					privacy.DataLoginHistory:     len(export.Logins),
+					privacy.DataGroupMemberships: len(export.Groups),
+					privacy.DataIdentityLinks:    len(export.Identities),
+					privacy.DataAvatar:           len(export.User.Avatar),
+					"dry_run":                    true,
+				}
+				return writeResult(cmd.OutOrStdout(), c.output, result, "id", "email", privacy.DataSessions, privacy.DataLoginHistory, privacy.DataGroupMemberships, privacy.DataIdentityLinks, privacy.DataAvatar, "dry_run")
+			}
+
+			resp, err := c.privacy.EraseUser(ctx, oid.Hex())
+			if err != nil {
+				return err
+			}
+			if err := respError(resp); err != nil {
+				return err
+			}
+			receipt := resp.Data.(privacy.Receipt)
+			if file != "" {
+				f, err := os.Create(file)
+				if err != nil {
+					return err
+				}
+				if err := writeJSON(f, receipt); err != nil {
+					f.Close()
+					return err
+				}
+				if err := f.Close(); err != nil {
+					return err
+				}
+			}
+			return writeReceipt(cmd.OutOrStdout(), c.output, receipt)
+		},
+	}
+	cmd.Flags().StringVar(&file, "receipt", "", "also write the receipt to this file")
+	return cmd
+}
+
+func newPrivacyVerifyReceiptCmd(c *cli) *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify-receipt <file>",
+		Short: "Check the signature of an erasure receipt",
+		Long:  "Check the signature of an erasure receipt read from a file, or from stdin when the file is -.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var r io.Reader = cmd.InOrStdin()
+			if args[0] != "-" {
+				f, err := os.Open(args[0])
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				r = f
+			}
+			var receipt privacy.Receipt
+			if err := json.NewDecoder(r).Decode(&receipt); err != nil {
+				return fmt.Errorf("invalid receipt: %w", err)
+			}
+			if !c.privacy.VerifyReceipt(receipt) {
+				return fmt.Errorf("receipt %s: invalid signature", receipt.ID)
+			}
+			result := map[string]any{"id": receipt.ID, "user_id": receipt.UserID, "valid": true}
+			return writeResult(cmd.OutOrStdout(), c.output, result, "id", "user_id", "valid")
+		},
+	}
+}
+
+func writeReceipt(w io.Writer, format string, receipt privacy.Receipt) error {
+	if format == outputJSON {
+		return writeJSON(w, receipt)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "receipt:\t%s\n", receipt.ID)
+	fmt.Fprintf(tw, "user_id:\t%s\n", receipt.UserID)
+	fmt.Fprintf(tw, "tenant_id:\t%s\n", orDash(receipt.TenantID))
+	fmt.Fprintf(tw, "erased_at:\t%s\n", receipt.ErasedAt.Format(time.RFC3339))
+	for _, e := range receipt.Erased {
+		fmt.Fprintf(tw, "%s:\t%d\n", e.Data, e.Count)
+	}
+	fmt.Fprintf(tw, "signature:\t%s\n", receipt.Signature)
+	return tw.Flush()
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"user-management/app/avatar"
+	"user-management/app/privacy"
+	"user-management/app/user"
+	"user-management/response"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockPrivacy struct {
+	mock.Mock
+}
+
+func (m *mockPrivacy) ExportMyData(ctx context.Context) (*response.StdResp[any], error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockPrivacy) ExportUserData(ctx context.Context, userID string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockPrivacy) EraseUser(ctx context.Context, userID string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockPrivacy) VerifyReceipt(r privacy.Receipt) bool {
+	return m.Called(r).Bool(0)
+}
+
+func runPrivacy(uc privacyUsecase, stdin string, args ...string) (string, error) {
+	cmd := newRootCmd(nil, nil, func(ctx context.Context) (privacyUsecase, func(), error) {
+		return uc, func() {}, nil
+	})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetIn(strings.NewReader(stdin))
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestPrivacyExport_File(t *testing.T) {
+	uc := new(mockPrivacy)
+	oid := primitive.NewObjectID()
+	uc.On("ExportUserData", mock.MatchedBy(isPlatformAdmin), oid.Hex()).Return(response.SuccessWithData(privacy.Export{
+		User: user.FindUserResponse{Id: oid.Hex(), Email: "alice@example.com"},
+	}), nil)
+	file := filepath.Join(t.TempDir(), "export.json")
+
+	_, err := runPrivacy(uc, "", "privacy", "export", oid.Hex(), "--file", file)
+
+	assert.NoError(t, err)
+	data, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	var got privacy.Export
+	assert.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, "alice@example.com", got.User.Email)
+}
+
+func TestPrivacyErase(t *testing.T) {
+	uc := new(mockPrivacy)
+	oid := primitive.NewObjectID()
+	receipt := privacy.Receipt{
+		ID:        "r1",
+		UserID:    oid.Hex(),
+		ErasedAt:  time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Erased:    []privacy.Erased{{Data: privacy.DataUser, Count: 1}},
+		Signature: "abc",
+	}
+	uc.On("EraseUser", mock.MatchedBy(isPlatformAdmin), oid.Hex()).Return(response.SuccessWithData(receipt), nil)
+	file := filepath.Join(t.TempDir(), "receipt.json")
+
+	out, err := runPrivacy(uc, "", "privacy", "erase", oid.Hex(), "--receipt", file)
+
+	assert.NoError(t, err)
+	assert.Contains(t, out, "2025-01-02T03:04:05Z")
+	assert.Contains(t, out, "abc")
+	data, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	var got privacy.Receipt
+	assert.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, receipt, got)
+}
+
+func TestPrivacyErase_DryRun(t *testing.T) {
+	uc := new(mockPrivacy)
+	oid := primitive.NewObjectID()
+	uc.On("ExportUserData", mock.Anything, oid.Hex()).Return(response.SuccessWithData(privacy.Export{
+		User:   user.FindUserResponse{Id: oid.Hex(), Email: "alice@example.com", Avatar: avatar.URLs("0123456789abcdef0123456789abcdef")},
+		Groups: []string{"ops", "sales"},
+	}), nil)
+
+	out, err := runPrivacy(uc, "", "privacy", "erase", oid.Hex(), "--dry-run", "-o", "json")
+
+	assert.NoError(t, err)
+	var got map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(out), &got))
+	assert.Equal(t, true, got["dry_run"])
+	assert.Equal(t, float64(2), got[privacy.DataGroupMemberships])
+	assert.Equal(t, float64(len(avatar.Sizes)), got[privacy.DataAvatar])
+	uc.AssertNotCalled(t, "EraseUser", mock.Anything, mock.Anything)
+}
+
+func TestPrivacyErase_NotFound(t *testing.T) {
+	uc := new(mockPrivacy)
+	oid := primitive.NewObjectID()
+	uc.On("EraseUser", mock.Anything, oid.Hex()).Return(response.UserNotFound(), nil)
+
+	_, err := runPrivacy(uc, "", "privacy", "erase", oid.Hex())
+
+	assert.EqualError(t, err, response.UserNotFound().Code+": "+response.UserNotFound().Message)
+}
+
+func TestPrivacyVerifyReceipt(t *testing.T) {
+	uc := new(mockPrivacy)
+	uc.On("VerifyReceipt", mock.MatchedBy(func(r privacy.Receipt) bool { return r.ID == "r1" })).Return(true)
+	uc.On("VerifyReceipt", mock.MatchedBy(func(r privacy.Receipt) bool { return r.ID == "r2" })).Return(false)
+
+	out, err := runPrivacy(uc, `{"id":"r1","user_id":"u1"}`, "privacy", "verify-receipt", "-")
+	assert.NoError(t, err)
+	assert.Contains(t, out, "true")
+
+	_, err = runPrivacy(uc, `{"id":"r2","user_id":"u1"}`, "privacy", "verify-receipt", "-")
+	assert.EqualError(t, err, "receipt r2: invalid signature")
+
+	_, err = runPrivacy(uc, `nope`, "privacy", "verify-receipt", "-")
+	assert.Error(t, err)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"user-management/app/privacy"
+	"user-management/app/user"
+	"user-management/auth"
+	"user-management/storage"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+type usecaseFactory func(ctx context.Context) (user.Usecase, func(), error)
+
+type migratorFactory func(ctx context.Context) ([]storage.Migrator, func(), error)
+
+// privacyUsecase is what the privacy commands need: the usecase, and checking
+// receipts against the key it signs them with.
+type privacyUsecase interface {
+	privacy.Usecase
+	VerifyReceipt(r privacy.Receipt) bool
+}
+
+type privacyFactory func(ctx context.Context) (privacyUsecase, func(), error)
+
+// cli holds the global flags and the dependencies of the command being run.
+// Each command group connects what it needs in its PersistentPreRunE.
+type cli struct {
+	newUsecase   usecaseFactory
+	newMigrators migratorFactory
+	newPrivacy   privacyFactory
+	usecase      user.Usecase
+	migrators    []storage.Migrator
+	privacy      privacyUsecase
+	close        func()
+	output       string
+	dryRun       bool
+	tenantID     string
+}
+
+func newRootCmd(newUsecase usecaseFactory, newMigrators migratorFactory, newPrivacy privacyFactory) *cobra.Command {
+	c := &cli{newUsecase: newUsecase, newMigrators: newMigrators, newPrivacy: newPrivacy}
+	cmd := &cobra.Command{
+		Use:          "usermgmt",
+		Short:        "Operator tools for user management",
+		SilenceUsage: true,
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			if c.close != nil {
+				c.close()
+			}
+		},
+	}
+	cmd.PersistentFlags().StringVarP(&c.output, "output", "o", outputTable, "output format: table or json")
+	cmd.PersistentFlags().BoolVar(&c.dryRun, "dry-run", false, "validate and print what would change without writing")
+	cmd.PersistentFlags().StringVar(&c.tenantID, "tenant-id", "", "scope the command to a tenant; platform users when empty")
+
+	cmd.AddCommand(newUserCmd(c), newMigrateCmd(c), newPrivacyCmd(c))
+	return cmd
+}
+
+// validate checks the global flags.
+func (c *cli) validate() error {
+	if c.output != outputTable && c.output != outputJSON {
+		return fmt.Errorf("invalid --output %q, expected %s or %s", c.output, outputTable, outputJSON)
+	}
+	if c.tenantID != "" {
+		if _, err := primitive.ObjectIDFromHex(c.tenantID); err != nil {
+			return fmt.Errorf("invalid --tenant-id %q", c.tenantID)
+		}
+	}
+	return nil
+}
+
+// context returns the context commands run with: a platform admin, optionally
+// scoped to the tenant given by --tenant-id.
+func (c *cli) context(ctx context.Context) context.Context {
+	ctx = auth.WithClaims(ctx, &auth.Claims{Role: auth.RoleAdmin})
+	if c.tenantID != "" {
+		ctx = auth.WithTenant(ctx, c.tenantID)
+	}
+	return ctx
+}
@@ -0,0 +1,368 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"user-management/app/user"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newUserCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Manage users",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := c.validate(); err != nil {
+				return err
+			}
+			uc, closeFn, err := c.newUsecase(cmd.Context())
+			if err != nil {
+				return err
+			}
+			c.usecase, c.close = uc, closeFn
+			return nil
+		},
+	}
+	cmd.AddCommand(
+		newUserCreateCmd(c),
+		newUserResetPasswordCmd(c),
+		newUserSetStatusCmd(c),
+		newUserListCmd(c),
+		newUserExportCmd(c),
+		newUserImportCmd(c),
+		newUserReencryptCmd(c),
+	)
+	return cmd
+}
+
+func newUserCreateCmd(c *cli) *cobra.Command {
+	var req user.CreateRequest
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a user",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if req.Password == "" {
+				password, err := readPassword(cmd.InOrStdin())
+				if err != nil {
+					return err
+				}
+				req.Password = password
+			}
+			req.TenantID = c.tenantID
+			if err := respError(req.RequestValidation()); err != nil {
+				return err
+			}
+
+			result := map[string]any{"email": req.Email, "role": orDash(req.Role), "tenant_id": orDash(req.TenantID)}
+			if c.dryRun {
+				result["dry_run"] = true
+				return writeResult(cmd.OutOrStdout(), c.output, result, "email", "role", "tenant_id", "dry_run")
+			}
+			resp, err := c.usecase.CreateUser(c.context(cmd.Context()), req)
+			if err != nil {
+				return err
+			}
+			if err := respError(resp); err != nil {
+				return err
+			}
+			result["id"] = resp.Data.(user.CreateResponse).Id
+			return writeResult(cmd.OutOrStdout(), c.output, result, "id", "email", "role", "tenant_id")
+		},
+	}
+	cmd.Flags().StringVar(&req.Name, "name", "", "display name")
+	cmd.Flags().StringVar(&req.Email, "email", "", "email address")
+	cmd.Flags().StringVar(&req.Role, "role", "", "role: user (default) or admin")
+	cmd.Flags().StringVar(&req.Password, "password", "", "password; read from stdin when omitted")
+	return cmd
+}
+
+func newUserResetPasswordCmd(c *cli) *cobra.Command {
+	var password string
+	cmd := &cobra.Command{
+		Use:   "reset-password <id>",
+		Short: "Set a new password for a user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			oid, err := primitive.ObjectIDFromHex(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid user id %q", args[0])
+			}
+			if password == "" {
+				if password, err = readPassword(cmd.InOrStdin()); err != nil {
+					return err
+				}
+			}
+			if strings.TrimSpace(password) == "" {
+				return fmt.Errorf("password must not be empty")
+			}
+
+			ctx := c.context(cmd.Context())
+			resp, err := c.usecase.FindUserById(ctx, oid.Hex())
+			if err != nil {
+				return err
+			}
+			if err := respError(resp); err != nil {
+				return err
+			}
+			found := resp.Data.(user.FindUserResponse)
+
+			result := map[string]any{"id": found.Id, "email": found.Email}
+			if c.dryRun {
+				result["dry_run"] = true
+				return writeResult(cmd.OutOrStdout(), c.output, result, "id", "email", "dry_run")
+			}
+			resp, err = c.usecase.ResetPassword(ctx, oid.Hex(), password)
+			if err != nil {
+				return err
+			}
+			if err := respError(resp); err != nil {
+				return err
+			}
+			return writeResult(cmd.OutOrStdout(), c.output, result, "id", "email")
+		},
+	}
+	cmd.Flags().StringVar(&password, "password", "", "new password; read from stdin when omitted")
+	return cmd
+}
+
+func newUserSetStatusCmd(c *cli) *cobra.Command {
+	var reason string
+	cmd := &cobra.Command{
+		Use:   "set-status <id> <status>",
+		Short: "Activate, suspend or disable a user",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			oid, err := primitive.ObjectIDFromHex(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid user id %q", args[0])
+			}
+			req := user.StatusRequest{Status: args[1], Reason: reason}
+			if err := respError(req.RequestValidation()); err != nil {
+				return err
+			}
+
+			ctx := c.context(cmd.Context())
+			resp, err := c.usecase.FindUserById(ctx, oid.Hex())
+			if err != nil {
+				return err
+			}
+			if err := respError(resp); err != nil {
+				return err
+			}
+			found := resp.Data.(user.FindUserResponse)
+
+			result := map[string]any{"id": found.Id, "email": found.Email, "from": orDash(found.Status), "to": req.Status}
+			if c.dryRun {
+				result["dry_run"] = true
+				return writeResult(cmd.OutOrStdout(), c.output, result, "id", "email", "from", "to", "dry_run")
+			}
+			resp, err = c.usecase.ChangeStatus(ctx, oid.Hex(), req)
+			if err != nil {
+				return err
+			}
+			if err := respError(resp); err != nil {
+				return err
+			}
+			return writeResult(cmd.OutOrStdout(), c.output, result, "id", "email", "from", "to")
+		},
+	}
+	cmd.Flags().StringVar(&reason, "reason", "", "why the status changes; required to suspend or disable")
+	return cmd
+}
+
+func newUserListCmd(c *cli) *cobra.Command {
+	var profile map[string]string
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List users",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			users, err := c.findUsers(cmd, profile)
+			if err != nil {
+				return err
+			}
+			return writeUsers(cmd.OutOrStdout(), c.output, users)
+		},
+	}
+	cmd.Flags().StringToStringVar(&profile, "profile", nil, "only list users with these indexed profile attributes, as name=value")
+	return cmd
+}
+
+func newUserExportCmd(c *cli) *cobra.Command {
+	var file, format, fields string
+	var profile map[string]string
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export users as CSV or JSON lines",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format == "" {
+				format = formatFromExt(file)
+			}
+			if format == "" {
+				format = user.ExportFormatJSONL
+			}
+			if err := respError(user.ExportFormatValidation(format)); err != nil {
+				return err
+			}
+			var requested []string
+			if fields != "" {
+				requested = strings.Split(fields, ",")
+			}
+			selected, respValidate := user.ExportFieldsValidation(requested)
+			if err := respError(respValidate); err != nil {
+				return err
+			}
+
+			ctx := c.context(cmd.Context())
+			if c.dryRun {
+				count := 0
+				resp, err := c.usecase.ExportUsers(ctx, profile, selected, func(user.FindUserResponse) error {
+					count++
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+				if err := respError(resp); err != nil {
+					return err
+				}
+				return writeResult(cmd.OutOrStdout(), c.output, map[string]any{"users": count, "file": orDash(file), "dry_run": true}, "users", "file", "dry_run")
+			}
+
+			w := cmd.OutOrStdout()
+			if file != "" {
+				f, err := os.Create(file)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				w = f
+			}
+			writer := user.NewExportWriter(w, format, selected)
+			resp, err := c.usecase.ExportUsers(ctx, profile, selected, writer.Write)
+			if err != nil {
+				return err
+			}
+			if err := respError(resp); err != nil {
+				return err
+			}
+			return writer.Flush()
+		},
+	}
+	cmd.Flags().StringVarP(&file, "file", "f", "", "write to this file instead of stdout")
+	cmd.Flags().StringVar(&format, "format", "", "csv, jsonl or ndjson; guessed from the file extension, jsonl otherwise")
+	cmd.Flags().StringVar(&fields, "fields", "", "comma separated fields to export; all when omitted")
+	cmd.Flags().StringToStringVar(&profile, "profile", nil, "only export users with these indexed profile attributes, as name=value")
+	return cmd
+}
+
+func newUserImportCmd(c *cli) *cobra.Command {
+	var file, format string
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import users from a CSV or JSONL file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format == "" {
+				format = formatFromExt(file)
+			}
+			if err := respError(user.ImportFormatValidation(format)); err != nil {
+				return err
+			}
+			f, err := os.Open(file)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			rows, err := user.ParseImport(f, format)
+			if err != nil {
+				return err
+			}
+
+			resp, err := c.usecase.ImportUsers(c.context(cmd.Context()), rows, c.dryRun)
+			if err != nil {
+				return err
+			}
+			if err := respError(resp); err != nil {
+				return err
+			}
+			report := resp.Data.(user.ImportReport)
+			if err := writeImportReport(cmd.OutOrStdout(), c.output, report); err != nil {
+				return err
+			}
+			if report.Failed > 0 {
+				return fmt.Errorf("%d of %d rows failed", report.Failed, report.Total)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&file, "file", "f", "", "CSV or JSONL file to import")
+	cmd.Flags().StringVar(&format, "format", "", "csv or jsonl; guessed from the file extension when omitted")
+	cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+func newUserReencryptCmd(c *cli) *cobra.Command {
+	return &cobra.Command{
+		Use:   "reencrypt",
+		Short: "Re-encrypt user names and emails with the active encryption key",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resp, err := c.usecase.ReencryptUsers(c.context(cmd.Context()), c.dryRun)
+			if err != nil {
+				return err
+			}
+			if err := respError(resp); err != nil {
+				return err
+			}
+			report := resp.Data.(user.ReencryptReport)
+			return writeResult(cmd.OutOrStdout(), c.output, map[string]any{
+				"key_id":      report.KeyID,
+				"scanned":     report.Scanned,
+				"reencrypted": report.Reencrypted,
+				"dry_run":     report.DryRun,
+			}, "key_id", "scanned", "reencrypted", "dry_run")
+		},
+	}
+}
+
+// formatFromExt guesses the import or export format from a file name.
+func formatFromExt(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".csv":
+		return user.ImportFormatCSV
+	case ".jsonl", ".ndjson":
+		return user.ImportFormatJSONL
+	}
+	return ""
+}
+
+func (c *cli) findUsers(cmd *cobra.Command, profile map[string]string) ([]user.FindUserResponse, error) {
+	resp, err := c.usecase.FindUsers(c.context(cmd.Context()), profile)
+	if err != nil {
+		return nil, err
+	}
+	if err := respError(resp); err != nil {
+		return nil, err
+	}
+	users, _ := resp.Data.([]user.FindUserResponse)
+	return users, nil
+}
+
+// readPassword reads a single line so passwords can be piped in instead of
+// showing up in shell history.
+func readPassword(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
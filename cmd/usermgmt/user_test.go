@@ -0,0 +1,327 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"user-management/app/user"
+	"user-management/auth"
+	"user-management/response"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type mockUsecase struct {
+	mock.Mock
+}
+
+func (m *mockUsecase) CreateUser(ctx context.Context, req user.CreateRequest) (*response.StdResp[any], error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) ImportUsers(ctx context.Context, rows []user.ImportRow, dryRun bool) (*response.StdResp[any], error) {
+	args := m.Called(ctx, rows, dryRun)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+// ExportUsers feeds the users given to Return(users, resp, err) to fn.
+func (m *mockUsecase) ExportUsers(ctx context.Context, filter map[string]string, fields []string, fn func(user.FindUserResponse) error) (*response.StdResp[any], error) {
+	args := m.Called(ctx, filter, fields)
+	for _, u := range args.Get(0).([]user.FindUserResponse) {
+		if err := fn(u); err != nil {
+			return nil, err
+		}
+	}
+	return args.Get(1).(*response.StdResp[any]), args.Error(2)
+}
+
+func (m *mockUsecase) Login(ctx context.Context, req user.SignInRequest) (*response.StdResp[any], error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) FindUsers(ctx context.Context, filter map[string]string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) FindUserById(ctx context.Context, id string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) ResetPassword(ctx context.Context, id, password string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, id, password)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) UpdateUser(ctx context.Context, u user.User) (*response.StdResp[any], error) {
+	args := m.Called(ctx, u)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) ChangeStatus(ctx context.Context, id string, req user.StatusRequest) (*response.StdResp[any], error) {
+	args := m.Called(ctx, id, req)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) FindStatusHistory(ctx context.Context, id string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) DeleteUser(ctx context.Context, id string) (*response.StdResp[any], error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func (m *mockUsecase) ReencryptUsers(ctx context.Context, dryRun bool) (*response.StdResp[any], error) {
+	args := m.Called(ctx, dryRun)
+	return args.Get(0).(*response.StdResp[any]), args.Error(1)
+}
+
+func run(uc user.Usecase, stdin string, args ...string) (string, error) {
+	cmd := newRootCmd(func(ctx context.Context) (user.Usecase, func(), error) {
+		return uc, func() {}, nil
+	}, nil, nil)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetIn(strings.NewReader(stdin))
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func isPlatformAdmin(ctx context.Context) bool {
+	claims, err := auth.FromContext(ctx)
+	return err == nil && claims.IsPlatformAdmin()
+}
+
+func TestUserCreate(t *testing.T) {
+	uc := new(mockUsecase)
+	uc.On("CreateUser", mock.MatchedBy(isPlatformAdmin), user.CreateRequest{
+		Name: "Alice", Email: "alice@example.com", Password: "secret", Role: auth.RoleAdmin,
+	}).Return(response.SuccessWithData(user.CreateResponse{Id: "abc123"}), nil)
+
+	out, err := run(uc, "secret\n", "user", "create", "--name", "Alice", "--email", "alice@example.com", "--role", "admin", "-o", "json")
+
+	assert.NoError(t, err)
+	var got map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(out), &got))
+	assert.Equal(t, "abc123", got["id"])
+	uc.AssertExpectations(t)
+}
+
+func TestUserCreate_DryRun(t *testing.T) {
+	uc := new(mockUsecase)
+
+	out, err := run(uc, "", "user", "create", "--name", "Alice", "--email", "alice@example.com", "--password", "secret", "--dry-run")
+
+	assert.NoError(t, err)
+	assert.Contains(t, out, "dry_run")
+	uc.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+}
+
+func TestUserCreate_Invalid(t *testing.T) {
+	uc := new(mockUsecase)
+
+	_, err := run(uc, "", "user", "create", "--name", "Alice", "--email", "nope", "--password", "secret")
+
+	assert.EqualError(t, err, "4004: "+response.InvalidData("email").Message)
+	uc.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+}
+
+func TestUserResetPassword(t *testing.T) {
+	uc := new(mockUsecase)
+	oid := primitive.NewObjectID()
+	uc.On("FindUserById", mock.Anything, oid.Hex()).Return(response.SuccessWithData(user.FindUserResponse{Id: oid.Hex(), Email: "alice@example.com"}), nil)
+	uc.On("ResetPassword", mock.Anything, oid.Hex(), "n3w").Return(response.Success(), nil)
+
+	out, err := run(uc, "n3w\n", "user", "reset-password", oid.Hex())
+
+	assert.NoError(t, err)
+	assert.Contains(t, out, "alice@example.com")
+	uc.AssertExpectations(t)
+}
+
+func TestUserResetPassword_WeakPassword(t *testing.T) {
+	uc := new(mockUsecase)
+	oid := primitive.NewObjectID()
+	uc.On("FindUserById", mock.Anything, oid.Hex()).Return(response.SuccessWithData(user.FindUserResponse{Id: oid.Hex(), Email: "alice@example.com"}), nil)
+	resp := response.WeakPassword([]response.Violation{{Rule: user.RuleMinLength, Message: "must be at least 8 characters"}})
+	uc.On("ResetPassword", mock.Anything, oid.Hex(), "n3w").Return(resp, nil)
+
+	_, err := run(uc, "", "user", "reset-password", oid.Hex(), "--password", "n3w")
+
+	assert.EqualError(t, err, "4016: "+resp.Message)
+}
+
+func TestUserResetPassword_NotFound(t *testing.T) {
+	uc := new(mockUsecase)
+	oid := primitive.NewObjectID()
+	uc.On("FindUserById", mock.Anything, oid.Hex()).Return(response.UserNotFound(), nil)
+
+	_, err := run(uc, "", "user", "reset-password", oid.Hex(), "--password", "n3w")
+
+	assert.Error(t, err)
+	uc.AssertNotCalled(t, "ResetPassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUserSetStatus(t *testing.T) {
+	uc := new(mockUsecase)
+	oid := primitive.NewObjectID()
+	req := user.StatusRequest{Status: user.StatusSuspended, Reason: "spam"}
+	uc.On("FindUserById", mock.Anything, oid.Hex()).Return(response.SuccessWithData(user.FindUserResponse{Id: oid.Hex(), Email: "alice@example.com", Status: user.StatusActive}), nil)
+	uc.On("ChangeStatus", mock.MatchedBy(isPlatformAdmin), oid.Hex(), req).Return(response.Success(), nil)
+
+	out, err := run(uc, "", "user", "set-status", oid.Hex(), user.StatusSuspended, "--reason", "spam")
+
+	assert.NoError(t, err)
+	assert.Contains(t, out, user.StatusSuspended)
+	uc.AssertExpectations(t)
+}
+
+func TestUserSetStatus_ReasonRequired(t *testing.T) {
+	uc := new(mockUsecase)
+
+	_, err := run(uc, "", "user", "set-status", primitive.NewObjectID().Hex(), user.StatusDisabled)
+
+	assert.EqualError(t, err, "4001: "+response.MandatoryMissing("reason").Message)
+	uc.AssertNotCalled(t, "FindUserById", mock.Anything, mock.Anything)
+}
+
+func TestUserSetStatus_NotAllowed(t *testing.T) {
+	uc := new(mockUsecase)
+	oid := primitive.NewObjectID()
+	req := user.StatusRequest{Status: user.StatusSuspended, Reason: "spam"}
+	uc.On("FindUserById", mock.Anything, oid.Hex()).Return(response.SuccessWithData(user.FindUserResponse{Id: oid.Hex(), Status: user.StatusPending}), nil)
+	uc.On("ChangeStatus", mock.Anything, oid.Hex(), req).Return(response.InvalidStatusTransition(), nil)
+
+	_, err := run(uc, "", "user", "set-status", oid.Hex(), user.StatusSuspended, "--reason", "spam")
+
+	assert.EqualError(t, err, "4018: "+response.InvalidStatusTransition().Message)
+}
+
+func TestUserList_Tenant(t *testing.T) {
+	uc := new(mockUsecase)
+	tenantID := primitive.NewObjectID().Hex()
+	uc.On("FindUsers", mock.MatchedBy(func(ctx context.Context) bool {
+		id, scoped := auth.TenantFromContext(ctx)
+		return scoped && id == tenantID
+	}), map[string]string(nil)).Return(response.SuccessWithData([]user.FindUserResponse{{Id: "u1", Name: "Alice", Email: "alice@example.com"}}), nil)
+
+	out, err := run(uc, "", "user", "list", "--tenant-id", tenantID)
+
+	assert.NoError(t, err)
+	assert.Contains(t, out, "EMAIL")
+	assert.Contains(t, out, "alice@example.com")
+}
+
+func TestUserList_Profile(t *testing.T) {
+	uc := new(mockUsecase)
+	uc.On("FindUsers", mock.MatchedBy(isPlatformAdmin), map[string]string{"department": "Sales", "level": "3"}).
+		Return(response.SuccessWithData([]user.FindUserResponse{{Id: "u1", Email: "alice@example.com"}}), nil)
+
+	out, err := run(uc, "", "user", "list", "--profile", "department=Sales", "--profile", "level=3")
+
+	assert.NoError(t, err)
+	assert.Contains(t, out, "alice@example.com")
+}
+
+func TestUserList_ProfileNotIndexed(t *testing.T) {
+	uc := new(mockUsecase)
+	uc.On("FindUsers", mock.Anything, map[string]string{"phone": "1"}).Return(response.InvalidData("profile.phone"), nil)
+
+	_, err := run(uc, "", "user", "list", "--profile", "phone=1")
+
+	assert.EqualError(t, err, "4004: "+response.InvalidData("profile.phone").Message)
+}
+
+func TestUserExport_File(t *testing.T) {
+	uc := new(mockUsecase)
+	uc.On("ExportUsers", mock.MatchedBy(isPlatformAdmin), map[string]string(nil), []string{"id", "email"}).
+		Return([]user.FindUserResponse{{Id: "u1", Email: "alice@example.com"}}, response.Success(), nil)
+	file := filepath.Join(t.TempDir(), "users.csv")
+
+	_, err := run(uc, "", "user", "export", "--file", file, "--fields", "email,id")
+
+	assert.NoError(t, err)
+	b, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, "id,email\nu1,alice@example.com\n", string(b))
+}
+
+func TestUserExport_DryRun(t *testing.T) {
+	uc := new(mockUsecase)
+	uc.On("ExportUsers", mock.Anything, map[string]string(nil), user.ExportFields).
+		Return([]user.FindUserResponse{{Id: "u1"}, {Id: "u2"}}, response.Success(), nil)
+
+	out, err := run(uc, "", "user", "export", "--dry-run", "-o", "json")
+
+	assert.NoError(t, err)
+	assert.Contains(t, out, `"users": 2`)
+}
+
+func TestInvalidOutput(t *testing.T) {
+	_, err := run(new(mockUsecase), "", "user", "list", "-o", "yaml")
+
+	assert.Error(t, err)
+}
+
+func TestUserImport(t *testing.T) {
+	uc := new(mockUsecase)
+	file := filepath.Join(t.TempDir(), "users.jsonl")
+	assert.NoError(t, os.WriteFile(file, []byte(`{"name":"Alice","email":"a@example.com","password":"pass1"}`+"\n"), 0o600))
+	report := user.ImportReport{Total: 1, Succeeded: 1, DryRun: true, Rows: []user.ImportRowResult{{Line: 1, Email: "a@example.com", Status: user.ImportStatusValid}}}
+	uc.On("ImportUsers", mock.MatchedBy(isPlatformAdmin), []user.ImportRow{
+		{Line: 1, Request: user.CreateRequest{Name: "Alice", Email: "a@example.com", Password: "pass1"}},
+	}, true).Return(response.SuccessWithData(report), nil)
+
+	out, err := run(uc, "", "user", "import", "--file", file, "--dry-run")
+
+	assert.NoError(t, err)
+	assert.Contains(t, out, "succeeded: 1")
+	uc.AssertExpectations(t)
+}
+
+func TestUserImport_FailedRows(t *testing.T) {
+	uc := new(mockUsecase)
+	file := filepath.Join(t.TempDir(), "users.csv")
+	assert.NoError(t, os.WriteFile(file, []byte("email\nnope\n"), 0o600))
+	report := user.ImportReport{Total: 1, Failed: 1, Rows: []user.ImportRowResult{{Line: 2, Email: "nope", Status: user.ImportStatusFailed, Error: "bad"}}}
+	uc.On("ImportUsers", mock.Anything, mock.Anything, false).Return(response.SuccessWithData(report), nil)
+
+	_, err := run(uc, "", "user", "import", "--file", file)
+
+	assert.EqualError(t, err, "1 of 1 rows failed")
+}
+
+func TestUserReencrypt(t *testing.T) {
+	uc := new(mockUsecase)
+	report := user.ReencryptReport{KeyID: "k2", Scanned: 3, Reencrypted: 2, DryRun: true}
+	uc.On("ReencryptUsers", mock.MatchedBy(isPlatformAdmin), true).Return(response.SuccessWithData(report), nil)
+
+	out, err := run(uc, "", "user", "reencrypt", "--dry-run")
+
+	assert.NoError(t, err)
+	assert.Regexp(t, `key_id:\s+k2\n`, out)
+	assert.Regexp(t, `reencrypted:\s+2\n`, out)
+	uc.AssertExpectations(t)
+}
+
+func TestUserReencrypt_NotConfigured(t *testing.T) {
+	uc := new(mockUsecase)
+	uc.On("ReencryptUsers", mock.Anything, false).Return(response.EncryptionNotConfigured(), nil)
+
+	_, err := run(uc, "", "user", "reencrypt")
+
+	assert.EqualError(t, err, "4023: Encryption at rest is not configured")
+}
@@ -10,7 +10,23 @@ type AppConfig struct {
 	HttpServer        HttpServer
 	GrpcServer        GrpcServer
 	Crypto            CryptoCredential
+	PasswordPolicy    PasswordPolicyConfig
+	PasswordHash      PasswordHashConfig
+	Storage           StorageConfig
 	MongoDB           MongoConfig
+	Invite            InviteConfig
+	LoginHistory      LoginHistoryConfig
+	Session           SessionConfig
+	Blob              BlobConfig
+	Avatar            AvatarConfig
+	Privacy           PrivacyConfig
+	Encryption        EncryptionConfig
+	OIDC              OIDCConfig
+	Federation        FederationConfig
+	SCIM              SCIMConfig
+	APIKey            APIKeyConfig
+	OAuth             OAuthConfig
+	Bootstrap         BootstrapConfig
 	UserCountInterval time.Duration `env:"USER_COUNT_INTERVAL" envDefault:"10s"`
 }
 
@@ -25,14 +41,182 @@ type GrpcServer struct {
 type CryptoCredential struct {
 	JwtKey            string        `env:"CRYPTO_JWT_KEY"`
 	JwtExpireDuration time.Duration `env:"CRYPTO_JWT_EXPIRE_DURATION"`
+	JwtEmbedGroups    bool          `env:"CRYPTO_JWT_EMBED_GROUPS" envDefault:"false"`
+}
+
+// PasswordPolicyConfig sets the rules new passwords must pass. RequiredClasses
+// lists character classes a password needs: lower, upper, digit and symbol.
+// BreachedFile is an optional sorted "SHA1:COUNT" file of known breached
+// passwords. HistorySize is how many of the most recent passwords, the
+// current one included, cannot be reused; 0 turns the history off.
+type PasswordPolicyConfig struct {
+	MinLength       int      `env:"PASSWORD_MIN_LENGTH" envDefault:"8"`
+	RequiredClasses []string `env:"PASSWORD_REQUIRED_CLASSES" envSeparator:","`
+	BanPersonalInfo bool     `env:"PASSWORD_BAN_PERSONAL_INFO" envDefault:"true"`
+	BreachedFile    string   `env:"PASSWORD_BREACHED_FILE"`
+	HistorySize     int      `env:"PASSWORD_HISTORY_SIZE" envDefault:"5"`
+}
+
+// PasswordHashConfig selects the algorithm new password hashes are made
+// with: argon2id, bcrypt or scrypt, and the parameters of each. Argon2Memory is
+// in KiB and ScryptN must be a power of two. Hashes made with other settings
+// keep working and are replaced on the user's next login.
+type PasswordHashConfig struct {
+	Algorithm     string `env:"PASSWORD_HASH_ALGORITHM" envDefault:"argon2id"`
+	Argon2Time    uint32 `env:"PASSWORD_HASH_ARGON2_TIME" envDefault:"3"`
+	Argon2Memory  uint32 `env:"PASSWORD_HASH_ARGON2_MEMORY" envDefault:"65536"`
+	Argon2Threads uint8  `env:"PASSWORD_HASH_ARGON2_THREADS" envDefault:"4"`
+	BcryptCost    int    `env:"PASSWORD_HASH_BCRYPT_COST" envDefault:"10"`
+	ScryptN       int    `env:"PASSWORD_HASH_SCRYPT_N" envDefault:"32768"`
+	ScryptR       int    `env:"PASSWORD_HASH_SCRYPT_R" envDefault:"8"`
+	ScryptP       int    `env:"PASSWORD_HASH_SCRYPT_P" envDefault:"1"`
+}
+
+// StorageConfig selects where users are stored: mongo, postgres, sqlite or
+// memory. SQLDSN is the connection string for the SQL backends. Tenants,
+// groups, invites, login history and sessions always live in MongoDB.
+// MigrateOnStart applies pending schema migrations when the server starts;
+// turn it off to run them with `usermgmt migrate` instead.
+type StorageConfig struct {
+	Backend        string `env:"STORAGE_BACKEND" envDefault:"mongo"`
+	SQLDSN         string `env:"STORAGE_SQL_DSN"`
+	MigrateOnStart bool   `env:"STORAGE_MIGRATE_ON_START" envDefault:"true"`
 }
 
 type MongoConfig struct {
-	Uri            string `env:"MONGO_CONFIG_URI"`
-	Username       string `env:"MONGO_CONFIG_USERNAME"`
-	Password       string `env:"MONGO_CONFIG_PASSWORD"`
-	Database       string `env:"MONGO_CONFIG_DATABASE"`
-	UserCollection string `env:"MONGO_CONFIG_USER_COLLECTION"`
+	Uri                       string `env:"MONGO_CONFIG_URI"`
+	Username                  string `env:"MONGO_CONFIG_USERNAME"`
+	Password                  string `env:"MONGO_CONFIG_PASSWORD"`
+	Database                  string `env:"MONGO_CONFIG_DATABASE"`
+	UserCollection            string `env:"MONGO_CONFIG_USER_COLLECTION"`
+	TenantCollection          string `env:"MONGO_CONFIG_TENANT_COLLECTION" envDefault:"tenants"`
+	GroupCollection           string `env:"MONGO_CONFIG_GROUP_COLLECTION" envDefault:"groups"`
+	GroupMemberCollection     string `env:"MONGO_CONFIG_GROUP_MEMBER_COLLECTION" envDefault:"group_members"`
+	InviteCollection          string `env:"MONGO_CONFIG_INVITE_COLLECTION" envDefault:"invites"`
+	LoginHistoryCollection    string `env:"MONGO_CONFIG_LOGIN_HISTORY_COLLECTION" envDefault:"login_history"`
+	SessionCollection         string `env:"MONGO_CONFIG_SESSION_COLLECTION" envDefault:"sessions"`
+	ProfileSchemaCollection   string `env:"MONGO_CONFIG_PROFILE_SCHEMA_COLLECTION" envDefault:"profile_schemas"`
+	OIDCClientCollection      string `env:"MONGO_CONFIG_OIDC_CLIENT_COLLECTION" envDefault:"oidc_clients"`
+	OIDCCodeCollection        string `env:"MONGO_CONFIG_OIDC_CODE_COLLECTION" envDefault:"oidc_codes"`
+	OIDCTokenCollection       string `env:"MONGO_CONFIG_OIDC_TOKEN_COLLECTION" envDefault:"oidc_tokens"`
+	IdentityLinkCollection    string `env:"MONGO_CONFIG_IDENTITY_LINK_COLLECTION" envDefault:"identity_links"`
+	FederationStateCollection string `env:"MONGO_CONFIG_FEDERATION_STATE_COLLECTION" envDefault:"federation_states"`
+	SCIMTokenCollection       string `env:"MONGO_CONFIG_SCIM_TOKEN_COLLECTION" envDefault:"scim_tokens"`
+	APIKeyCollection          string `env:"MONGO_CONFIG_API_KEY_COLLECTION" envDefault:"api_keys"`
+	OAuthClientCollection     string `env:"MONGO_CONFIG_OAUTH_CLIENT_COLLECTION" envDefault:"oauth_clients"`
+}
+
+type InviteConfig struct {
+	TTL       time.Duration `env:"INVITE_TTL" envDefault:"72h"`
+	AcceptURL string        `env:"INVITE_ACCEPT_URL" envDefault:"http://localhost:8080/invites/accept"`
+}
+
+// LoginHistoryConfig sets how long login attempts are kept. Each attempt is
+// stamped with its expiry when recorded, so a new Retention applies to new
+// attempts only.
+type LoginHistoryConfig struct {
+	Retention time.Duration `env:"LOGIN_HISTORY_RETENTION" envDefault:"2160h"`
+}
+
+// SessionConfig throttles how often a session's last-seen time is written:
+// requests within TouchInterval of the last write skip it. A session revoked
+// on another instance keeps working there for up to TouchInterval.
+type SessionConfig struct {
+	TouchInterval time.Duration `env:"SESSION_TOUCH_INTERVAL" envDefault:"1m"`
+}
+
+// BlobConfig selects where uploaded files such as avatars are kept: gridfs
+// stores them in the MongoDB bucket GridFSBucket, file under the directory Dir.
+type BlobConfig struct {
+	Backend      string `env:"BLOB_STORE_BACKEND" envDefault:"gridfs"`
+	Dir          string `env:"BLOB_STORE_DIR" envDefault:"data/blobs"`
+	GridFSBucket string `env:"BLOB_STORE_GRIDFS_BUCKET" envDefault:"blobs"`
+}
+
+// AvatarConfig limits avatar uploads. MaxSize is in bytes and MaxPixels caps
+// the decoded width times height, so small files cannot expand into huge images.
+type AvatarConfig struct {
+	MaxSize   int64 `env:"AVATAR_MAX_SIZE" envDefault:"5242880"`
+	MaxPixels int   `env:"AVATAR_MAX_PIXELS" envDefault:"25000000"`
+}
+
+// PrivacyConfig holds the key erasure receipts are signed with. When it is
+// empty a key is derived from the JWT key instead.
+type PrivacyConfig struct {
+	ReceiptKey string `env:"PRIVACY_RECEIPT_KEY"`
+}
+
+// EncryptionConfig holds the keys user names and emails are encrypted with at
+// rest; encryption is off when there are none. Keys lists key-encryption keys
+// as comma or newline separated id:base64 pairs. KeysFile holds the content of
+// the file named by ENCRYPTION_KEYS_FILE and wins over Keys. ActiveKeyID names
+// the key new values are encrypted with and may only be left empty when there
+// is a single key. BlindIndexKey (base64) keys the email lookup index and
+// cannot be changed once users are stored.
+type EncryptionConfig struct {
+	Keys          string `env:"ENCRYPTION_KEYS"`
+	KeysFile      string `env:"ENCRYPTION_KEYS_FILE,file"`
+	ActiveKeyID   string `env:"ENCRYPTION_ACTIVE_KEY_ID"`
+	BlindIndexKey string `env:"ENCRYPTION_BLIND_INDEX_KEY"`
+}
+
+// OIDCConfig configures the OpenID Connect provider. Issuer is the public base
+// URL of the service: the iss of every ID token and the prefix of the endpoints
+// in the discovery document. SigningKey holds the content of the PEM file named
+// by OIDC_SIGNING_KEY_FILE, the RSA private key ID tokens are signed with.
+// Without it a key is generated on start, so ID tokens cannot be verified
+// after a restart or across replicas. CodeTTL bounds how long an authorization
+// code can be redeemed and TokenTTL how long access and ID tokens last.
+type OIDCConfig struct {
+	Issuer     string        `env:"OIDC_ISSUER" envDefault:"http://localhost:8080"`
+	SigningKey string        `env:"OIDC_SIGNING_KEY_FILE,file"`
+	CodeTTL    time.Duration `env:"OIDC_CODE_TTL" envDefault:"1m"`
+	TokenTTL   time.Duration `env:"OIDC_TOKEN_TTL" envDefault:"1h"`
+}
+
+// FederationConfig configures login through external OpenID Connect
+// providers. Providers holds the content of the JSON file named by
+// FEDERATION_PROVIDERS_FILE; federated login is off without it. BaseURL is the
+// public base URL of the service, which the callback URL registered with each
+// provider starts with. StateTTL bounds how long a user may take to sign in at
+// the provider, and HTTPTimeout each call made to it.
+type FederationConfig struct {
+	Providers   string        `env:"FEDERATION_PROVIDERS_FILE,file"`
+	BaseURL     string        `env:"FEDERATION_BASE_URL" envDefault:"http://localhost:8080"`
+	StateTTL    time.Duration `env:"FEDERATION_STATE_TTL" envDefault:"10m"`
+	HTTPTimeout time.Duration `env:"FEDERATION_HTTP_TIMEOUT" envDefault:"10s"`
+}
+
+// SCIMConfig configures the SCIM provisioning API. BaseURL is the public base
+// URL of the service, which the locations of SCIM resources start with.
+type SCIMConfig struct {
+	BaseURL string `env:"SCIM_BASE_URL" envDefault:"http://localhost:8080"`
+}
+
+// APIKeyConfig limits how long API keys live: DefaultTTL when the admin gives
+// no expiry, and at most MaxTTL. A key's last use is written at most once per
+// TouchInterval.
+type APIKeyConfig struct {
+	DefaultTTL    time.Duration `env:"APIKEY_DEFAULT_TTL" envDefault:"2160h"`
+	MaxTTL        time.Duration `env:"APIKEY_MAX_TTL" envDefault:"8760h"`
+	TouchInterval time.Duration `env:"APIKEY_TOUCH_INTERVAL" envDefault:"1m"`
+}
+
+// OAuthConfig configures the client credentials grant at /oauth/token.
+// Issued access tokens cannot be revoked and live for TokenTTL, so keep it
+// short.
+type OAuthConfig struct {
+	TokenTTL time.Duration `env:"OAUTH_TOKEN_TTL" envDefault:"15m"`
+}
+
+// BootstrapConfig describes the platform admin created on first start. Seeding
+// is skipped when AdminEmail is empty. AdminPasswordFile holds the content of
+// the file named by BOOTSTRAP_ADMIN_PASSWORD_FILE and wins over AdminPassword.
+type BootstrapConfig struct {
+	AdminName         string `env:"BOOTSTRAP_ADMIN_NAME" envDefault:"Admin"`
+	AdminEmail        string `env:"BOOTSTRAP_ADMIN_EMAIL"`
+	AdminPassword     string `env:"BOOTSTRAP_ADMIN_PASSWORD"`
+	AdminPasswordFile string `env:"BOOTSTRAP_ADMIN_PASSWORD_FILE,file"`
 }
 
 func NewAppConfig() (*AppConfig, error) {
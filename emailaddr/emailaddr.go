@@ -0,0 +1,26 @@
+// Package emailaddr normalizes email addresses so that the same mailbox typed
+// in different ways compares, and is indexed, as one value.
+package emailaddr
+
+import (
+	"strings"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalize trims addr, applies Unicode NFC and lowercases it. An
+// internationalized domain is converted to its ASCII (punycode) form, so
+// "Alice@Bücher.Example" and "alice@xn--bcher-kva.example" are equal.
+func Normalize(addr string) string {
+	addr = norm.NFC.String(strings.TrimSpace(addr))
+	at := strings.LastIndex(addr, "@")
+	if at < 0 {
+		return strings.ToLower(addr)
+	}
+	local, domain := addr[:at], addr[at+1:]
+	if ascii, err := idna.Lookup.ToASCII(domain); err == nil {
+		domain = ascii
+	}
+	return strings.ToLower(local) + "@" + strings.ToLower(domain)
+}
@@ -0,0 +1,26 @@
+package emailaddr_test
+
+import (
+	"testing"
+	"user-management/emailaddr"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"alice@example.com", "alice@example.com"},
+		{"  Alice@Example.COM ", "alice@example.com"},
+		{"Alice@Bücher.Example", "alice@xn--bcher-kva.example"},
+		{"alice@xn--bcher-kva.example", "alice@xn--bcher-kva.example"},
+		// "é" typed as e + combining acute accent, and precomposed.
+		{"Re\u0301my@example.com", "r\u00e9my@example.com"},
+		{"r\u00e9my@example.com", "r\u00e9my@example.com"},
+		{"not-an-email", "not-an-email"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, emailaddr.Normalize(tt.in), tt.in)
+	}
+}
@@ -0,0 +1,214 @@
+// Package fieldcrypt encrypts single field values with envelope encryption.
+// Every value gets a fresh data key, which is sealed with a key-encryption key
+// (KEK) from the keyring. The KEK's id is stored with the value, so KEKs can
+// be rotated while older values stay readable. Lookups on encrypted fields go
+// through blind indexes: keyed hashes that are equal for equal inputs and
+// reveal nothing else.
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"user-management/config"
+)
+
+// prefix marks encrypted values, which read
+// enc:v1:<key id>:<sealed data key>:<sealed value>, both sealed parts being
+// base64url nonce||ciphertext.
+const prefix = "enc:v1:"
+
+const (
+	keyLength           = 32
+	minBlindIndexLength = 16
+)
+
+var (
+	ErrUnknownKey = errors.New("value is encrypted with an unknown key")
+	ErrMalformed  = errors.New("malformed encrypted value")
+)
+
+// Keyring holds the KEKs values can be encrypted with and the blind index key.
+type Keyring struct {
+	keks     map[string]cipher.AEAD
+	active   string
+	blindKey []byte
+}
+
+// NewKeyring parses the keys in cfg. It returns nil when no keys are
+// configured, meaning values are stored as they are.
+func NewKeyring(cfg config.EncryptionConfig) (*Keyring, error) {
+	list := cfg.Keys
+	if cfg.KeysFile != "" {
+		list = cfg.KeysFile
+	}
+	if strings.TrimSpace(list) == "" {
+		return nil, nil
+	}
+	k := &Keyring{keks: map[string]cipher.AEAD{}}
+	var ids []string
+	for _, entry := range strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == '\n' }) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("encryption key %q is not id:base64", entry)
+		}
+		if _, dup := k.keks[id]; dup {
+			return nil, fmt.Errorf("encryption key %q is listed twice", id)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != keyLength {
+			return nil, fmt.Errorf("encryption key %q must be %d bytes, base64 encoded", id, keyLength)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		k.keks[id] = aead
+		ids = append(ids, id)
+	}
+
+	k.active = cfg.ActiveKeyID
+	if k.active == "" && len(ids) == 1 {
+		k.active = ids[0]
+	}
+	if _, ok := k.keks[k.active]; !ok {
+		return nil, fmt.Errorf("active encryption key %q is not among the configured keys", k.active)
+	}
+	blindKey, err := base64.StdEncoding.DecodeString(cfg.BlindIndexKey)
+	if err != nil || len(blindKey) < minBlindIndexLength {
+		return nil, fmt.Errorf("blind index key must be at least %d bytes, base64 encoded", minBlindIndexLength)
+	}
+	k.blindKey = blindKey
+	return k, nil
+}
+
+// ActiveKeyID returns the id of the KEK new values are encrypted with.
+func (k *Keyring) ActiveKeyID() string {
+	return k.active
+}
+
+// Encrypt seals plain under a new data key, itself sealed with the active
+// KEK. Empty values stay empty.
+func (k *Keyring) Encrypt(plain string) (string, error) {
+	if plain == "" {
+		return "", nil
+	}
+	dataKey := make([]byte, keyLength)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	sealedKey, err := seal(k.keks[k.active], dataKey, []byte(k.active))
+	if err != nil {
+		return "", err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	sealedValue, err := seal(aead, []byte(plain), nil)
+	if err != nil {
+		return "", err
+	}
+	return prefix + k.active + ":" + sealedKey + ":" + sealedValue, nil
+}
+
+// Decrypt opens a value made by Encrypt. Values that are not encrypted, such
+// as those stored before encryption was turned on, are returned unchanged.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	parts := strings.Split(strings.TrimPrefix(value, prefix), ":")
+	if len(parts) != 3 {
+		return "", ErrMalformed
+	}
+	kek, ok := k.keks[parts[0]]
+	if !ok {
+		return "", ErrUnknownKey
+	}
+	dataKey, err := open(kek, parts[1], []byte(parts[0]))
+	if err != nil {
+		return "", err
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return "", ErrMalformed
+	}
+	plain, err := open(aead, parts[2], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// NeedsReencrypt reports whether value is not encrypted yet or is encrypted
+// with another KEK than the active one.
+func (k *Keyring) NeedsReencrypt(value string) bool {
+	if value == "" {
+		return false
+	}
+	return KeyID(value) != k.active
+}
+
+// BlindIndex returns the lowercase hex HMAC-SHA256 of s. Callers normalize s
+// first, so that equal values have equal indexes.
+func (k *Keyring) BlindIndex(s string) string {
+	mac := hmac.New(sha256.New, k.blindKey)
+	mac.Write([]byte(s))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsEncrypted reports whether value was made by Encrypt.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// KeyID returns the id of the KEK value is encrypted with, or "" when it is
+// not encrypted.
+func KeyID(value string) string {
+	if !IsEncrypted(value) {
+		return ""
+	}
+	id, _, _ := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+	return id
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func seal(aead cipher.AEAD, plain, additional []byte) (string, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, plain, additional)), nil
+}
+
+func open(aead cipher.AEAD, sealed string, additional []byte) ([]byte, error) {
+	data, err := base64.RawURLEncoding.DecodeString(sealed)
+	if err != nil || len(data) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ciphertext, additional)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	return plain, nil
+}
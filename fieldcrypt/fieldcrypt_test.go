@@ -0,0 +1,133 @@
+package fieldcrypt_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"user-management/config"
+	"user-management/fieldcrypt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, 32))
+}
+
+var blindKey = base64.StdEncoding.EncodeToString([]byte("blind-index-key-for-tests"))
+
+func newKeyring(t *testing.T, keys, active string) *fieldcrypt.Keyring {
+	k, err := fieldcrypt.NewKeyring(config.EncryptionConfig{Keys: keys, ActiveKeyID: active, BlindIndexKey: blindKey})
+	require.NoError(t, err)
+	require.NotNil(t, k)
+	return k
+}
+
+func TestKeyring_EncryptDecrypt(t *testing.T) {
+	k := newKeyring(t, "k1:"+testKey(1), "")
+	assert.Equal(t, "k1", k.ActiveKeyID())
+
+	enc, err := k.Encrypt("alice@example.com")
+	require.NoError(t, err)
+	assert.True(t, fieldcrypt.IsEncrypted(enc))
+	assert.Equal(t, "k1", fieldcrypt.KeyID(enc))
+	assert.NotContains(t, enc, "alice")
+
+	again, err := k.Encrypt("alice@example.com")
+	require.NoError(t, err)
+	assert.NotEqual(t, enc, again, "every value gets its own data key and nonce")
+
+	plain, err := k.Decrypt(enc)
+	require.NoError(t, err)
+	assert.Equal(t, "alice@example.com", plain)
+
+	// Empty and plaintext values pass through.
+	enc, err = k.Encrypt("")
+	require.NoError(t, err)
+	assert.Empty(t, enc)
+	plain, err = k.Decrypt("legacy@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "legacy@example.com", plain)
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	old := newKeyring(t, "k1:"+testKey(1), "")
+	enc, err := old.Encrypt("Alice")
+	require.NoError(t, err)
+
+	rotated := newKeyring(t, "k1:"+testKey(1)+"\nk2:"+testKey(2), "k2")
+	plain, err := rotated.Decrypt(enc)
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", plain)
+	assert.True(t, rotated.NeedsReencrypt(enc))
+	assert.True(t, rotated.NeedsReencrypt("plaintext"))
+	assert.False(t, rotated.NeedsReencrypt(""))
+
+	enc2, err := rotated.Encrypt("Alice")
+	require.NoError(t, err)
+	assert.Equal(t, "k2", fieldcrypt.KeyID(enc2))
+	assert.False(t, rotated.NeedsReencrypt(enc2))
+
+	_, err = old.Decrypt(enc2)
+	assert.ErrorIs(t, err, fieldcrypt.ErrUnknownKey)
+}
+
+func TestKeyring_Tampered(t *testing.T) {
+	k := newKeyring(t, "k1:"+testKey(1), "")
+	enc, err := k.Encrypt("Alice")
+	require.NoError(t, err)
+
+	// A different key under the same id cannot open the data key.
+	other := newKeyring(t, "k1:"+testKey(9), "")
+	_, err = other.Decrypt(enc)
+	assert.ErrorIs(t, err, fieldcrypt.ErrMalformed)
+
+	parts := strings.Split(enc, ":")
+	parts[len(parts)-1] = "AAAA" + parts[len(parts)-1][4:]
+	_, err = k.Decrypt(strings.Join(parts, ":"))
+	assert.ErrorIs(t, err, fieldcrypt.ErrMalformed)
+	_, err = k.Decrypt("enc:v1:k1:only")
+	assert.ErrorIs(t, err, fieldcrypt.ErrMalformed)
+}
+
+func TestKeyring_BlindIndex(t *testing.T) {
+	k := newKeyring(t, "k1:"+testKey(1), "")
+	rotated := newKeyring(t, "k1:"+testKey(1)+",k2:"+testKey(2), "k2")
+
+	idx := k.BlindIndex("alice@example.com")
+	assert.Len(t, idx, 64)
+	assert.Equal(t, strings.ToLower(idx), idx)
+	assert.Equal(t, idx, rotated.BlindIndex("alice@example.com"), "rotating KEKs keeps the index")
+	assert.NotEqual(t, idx, k.BlindIndex("bob@example.com"))
+}
+
+func TestNewKeyring(t *testing.T) {
+	k, err := fieldcrypt.NewKeyring(config.EncryptionConfig{})
+	assert.NoError(t, err)
+	assert.Nil(t, k, "no keys turns encryption off")
+
+	// The file wins over the variable.
+	k, err = fieldcrypt.NewKeyring(config.EncryptionConfig{Keys: "bad", KeysFile: "k1:" + testKey(1) + "\n", BlindIndexKey: blindKey})
+	assert.NoError(t, err)
+	assert.Equal(t, "k1", k.ActiveKeyID())
+
+	tests := []struct {
+		name string
+		cfg  config.EncryptionConfig
+	}{
+		{"No id", config.EncryptionConfig{Keys: testKey(1), BlindIndexKey: blindKey}},
+		{"Short key", config.EncryptionConfig{Keys: "k1:" + base64.StdEncoding.EncodeToString([]byte("short")), BlindIndexKey: blindKey}},
+		{"Duplicate id", config.EncryptionConfig{Keys: "k1:" + testKey(1) + ",k1:" + testKey(2), ActiveKeyID: "k1", BlindIndexKey: blindKey}},
+		{"No active key", config.EncryptionConfig{Keys: "k1:" + testKey(1) + ",k2:" + testKey(2), BlindIndexKey: blindKey}},
+		{"Unknown active key", config.EncryptionConfig{Keys: "k1:" + testKey(1), ActiveKeyID: "k3", BlindIndexKey: blindKey}},
+		{"No blind index key", config.EncryptionConfig{Keys: "k1:" + testKey(1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := fieldcrypt.NewKeyring(tt.cfg)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -6,12 +6,29 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	"user-management/app/apikey"
+	"user-management/app/avatar"
+	"user-management/app/federation"
+	"user-management/app/group"
+	"user-management/app/invite"
+	"user-management/app/loginhistory"
+	"user-management/app/oauth"
+	"user-management/app/oidc"
+	"user-management/app/privacy"
+	"user-management/app/profile"
+	"user-management/app/scim"
+	"user-management/app/session"
+	"user-management/app/tenant"
 	"user-management/app/user"
+	"user-management/blob"
 	"user-management/config"
 	"user-management/logger"
+	"user-management/notifier"
+	"user-management/passhash"
 	"user-management/server"
 	"user-management/storage"
 
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
 	"go.uber.org/zap"
 )
 
@@ -25,18 +42,150 @@ func main() {
 	zlog := logger.NewZap()
 	defer zlog.Sync()
 
-	mongo := storage.InitMongoConnection(ctx, cfg.MongoDB)
+	var mongo storage.DatabaseConn
+	var sessionRepo session.Repository
+	var profileRepo profile.Repository
+	var oidcRepo oidc.Repository
+	var federationRepo federation.Repository
+	var scimRepo scim.Repository
+	var apikeyRepo apikey.Repository
+	var oauthRepo oauth.Repository
+	var blobDB *mongodriver.Database
+	if cfg.Storage.Backend == storage.BackendMemory && cfg.MongoDB.Uri == "" {
+		zlog.Warn("No database configured: data is kept in memory and lost on restart",
+			zap.Strings("unsupported", []string{"tenants", "groups", "invites", "login history"}))
+		mongo = storage.NewOfflineConn()
+		sessionRepo = session.NewMemoryRepository()
+		profileRepo = profile.NewMemoryRepository()
+		oidcRepo = oidc.NewMemoryRepository()
+		federationRepo = federation.NewMemoryRepository()
+		scimRepo = scim.NewMemoryRepository()
+		apikeyRepo = apikey.NewMemoryRepository()
+		oauthRepo = oauth.NewMemoryRepository()
+		if cfg.Blob.Backend == blob.BackendGridFS {
+			zlog.Sugar().Warnf("No database configured: avatars are stored under %s", cfg.Blob.Dir)
+			cfg.Blob.Backend = blob.BackendFile
+		}
+	} else {
+		mc := storage.InitMongoConnection(ctx, cfg.MongoDB)
+		if cfg.Storage.MigrateOnStart {
+			migrate(ctx, zlog, storage.NewMongoMigrator(mc.Database(), storage.MongoMigrations(cfg.MongoDB)))
+		}
+		mongo = mc
+		sessionRepo = session.NewRepository(mc, cfg.MongoDB)
+		profileRepo = profile.NewRepository(mc, cfg.MongoDB)
+		oidcRepo = oidc.NewRepository(mc, cfg.MongoDB)
+		federationRepo = federation.NewRepository(mc, cfg.MongoDB)
+		scimRepo = scim.NewRepository(mc, cfg.MongoDB)
+		apikeyRepo = apikey.NewRepository(mc, cfg.MongoDB)
+		oauthRepo = oauth.NewRepository(mc, cfg.MongoDB)
+		blobDB = mc.Database()
+	}
 	defer mongo.Disconnect(ctx)
 
-	repo := user.NewRepository(mongo, cfg.MongoDB)
-	uc := user.NewUsecase(cfg.Crypto, repo)
+	tenantRepo := tenant.NewRepository(mongo, cfg.MongoDB)
+	tenantHandler := tenant.NewHandler(tenant.NewUsecase(tenantRepo))
+
+	groupRepo := group.NewRepository(mongo, cfg.MongoDB)
+
+	repo, closeRepo, err := user.NewStore(ctx, cfg.Storage, cfg.Encryption, mongo, cfg.MongoDB)
+	if err != nil {
+		zlog.Sugar().Fatalf("Failed to open user storage: %v", err)
+	}
+	defer closeRepo()
+	policy, err := user.NewPasswordPolicy(cfg.PasswordPolicy)
+	if err != nil {
+		zlog.Sugar().Fatalf("Failed to load password policy: %v", err)
+	}
+	defer policy.Close()
+	hasher, err := passhash.NewHasher(cfg.PasswordHash)
+	if err != nil {
+		zlog.Sugar().Fatalf("Failed to configure password hashing: %v", err)
+	}
+	seeded, err := user.Bootstrap(ctx, cfg.Bootstrap, policy, hasher, repo)
+	if err != nil {
+		zlog.Sugar().Fatalf("Failed to bootstrap admin user: %v", err)
+	}
+	if seeded {
+		zlog.Sugar().Infof("Created bootstrap admin %s", cfg.Bootstrap.AdminEmail)
+	}
+	loginRepo := loginhistory.NewRepository(mongo, cfg.MongoDB)
+	loginUc := loginhistory.NewUsecase(cfg.LoginHistory, loginRepo)
+	loginHandler := loginhistory.NewHandler(loginUc)
+
+	sessionUc := session.NewUsecase(cfg.Session, sessionRepo)
+	sessionHandler := session.NewHandler(sessionUc)
+
+	profileHandler := profile.NewHandler(profile.NewUsecase(profileRepo))
+
+	uc := user.NewUsecase(cfg.Crypto, policy, hasher, repo, tenantRepo, groupRepo, loginUc, sessionUc, profileRepo)
 	handler := user.NewHandler(uc)
 
-	grpcServer, err := server.NewGRPCServer(uc, zlog, cfg)
+	blobStore, err := blob.NewStore(cfg.Blob, blobDB)
+	if err != nil {
+		zlog.Sugar().Fatalf("Failed to open blob storage: %v", err)
+	}
+	avatarUc := avatar.NewUsecase(cfg.Avatar, blobStore, uc)
+	avatarHandler := avatar.NewHandler(avatarUc)
+
+	inviteRepo := invite.NewRepository(mongo, cfg.MongoDB)
+	privacyUc := privacy.NewUsecase(privacy.ReceiptKey(cfg), repo, loginRepo, sessionRepo, groupRepo, federationRepo, oidcRepo, inviteRepo, avatarUc)
+	privacyHandler := privacy.NewHandler(privacyUc)
+
+	if cfg.OIDC.SigningKey == "" {
+		zlog.Warn("No OIDC signing key configured: ID tokens are signed with a key generated on start")
+	}
+	signer, err := oidc.NewSigner(cfg.OIDC.SigningKey)
+	if err != nil {
+		zlog.Sugar().Fatalf("Failed to load OIDC signing key: %v", err)
+	}
+	oidcHandler := oidc.NewHandler(oidc.NewUsecase(cfg.OIDC, cfg.Crypto.JwtKey, signer, oidcRepo, repo, uc, sessionUc, tenantRepo))
+
+	providers, err := federation.LoadProviders(cfg.Federation)
+	if err != nil {
+		zlog.Sugar().Fatalf("Failed to load federation providers: %v", err)
+	}
+	federationHandler := federation.NewHandler(federation.NewUsecase(cfg.Federation, providers, federationRepo, repo, uc, tenantRepo))
+
+	groupUc := group.NewUsecase(groupRepo, repo)
+	groupHandler := group.NewHandler(groupUc)
+
+	scimUc := scim.NewUsecase(cfg.SCIM, scimRepo, uc, repo, groupUc, groupRepo, tenantRepo)
+	scimHandler := scim.NewHandler(scimUc)
+
+	apikeyUc := apikey.NewUsecase(cfg.APIKey, apikeyRepo, tenantRepo)
+	apikeyHandler := apikey.NewHandler(apikeyUc)
+
+	oauthUc := oauth.NewUsecase(cfg.OAuth, cfg.Crypto.JwtKey, oauthRepo, tenantRepo)
+	oauthHandler := oauth.NewHandler(oauthUc)
+
+	inviteHandler := invite.NewHandler(invite.NewUsecase(cfg.Invite, policy, hasher, inviteRepo, repo, notifier.NewLogNotifier(zlog)))
+
+	grpcServer, err := server.NewGRPCServer(uc, groupUc, sessionUc, privacyUc, sessionUc, uc, oauthUc, apikeyUc, zlog, cfg)
 	if err != nil {
 		panic(err)
 	}
-	httpServer := server.NewEchoHTTPServer(ctx, zlog, handler, cfg)
+	httpServer := server.NewEchoHTTPServer(ctx, zlog, server.HTTPDeps{
+		User:         handler,
+		Tenant:       tenantHandler,
+		Group:        groupHandler,
+		Invite:       inviteHandler,
+		LoginHistory: loginHandler,
+		Session:      sessionHandler,
+		Profile:      profileHandler,
+		Avatar:       avatarHandler,
+		Privacy:      privacyHandler,
+		OIDC:         oidcHandler,
+		Federation:   federationHandler,
+		SCIM:         scimHandler,
+		APIKey:       apikeyHandler,
+		OAuth:        oauthHandler,
+		SCIMTokens:   scimUc,
+		APIKeys:      apikeyUc,
+		Sessions:     sessionUc,
+		Users:        uc,
+		Clients:      oauthUc,
+	}, cfg)
 	// Start HTTP server
 	go httpServer.Start()
 	// Start gRPC server
@@ -55,6 +204,19 @@ func main() {
 	grpcServer.Stop(shutdownCtx)
 }
 
+// migrate applies pending migrations before the server starts serving.
+func migrate(ctx context.Context, zlog *zap.Logger, m storage.Migrator) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+	applied, err := m.Up(ctx)
+	for _, a := range applied {
+		zlog.Sugar().Infof("Applied %s migration %d: %s", a.Target, a.Version, a.Description)
+	}
+	if err != nil {
+		zlog.Sugar().Fatalf("Failed to migrate database: %v", err)
+	}
+}
+
 func countTotalUserIntervalTicker(ctx context.Context, zlog *zap.Logger, repo user.CountUsersRepository, internval time.Duration) {
 	ticker := time.NewTicker(internval)
 	defer ticker.Stop()
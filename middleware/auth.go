@@ -1,15 +1,74 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
+	"slices"
 	"strings"
+	"user-management/auth"
 	"user-management/response"
 
 	jwt "github.com/golang-jwt/jwt/v5"
 	echo "github.com/labstack/echo/v4"
 )
 
-func AuthMiddleware(secret string) echo.MiddlewareFunc {
+// SessionChecker reports whether the session a token was issued for is still
+// active, noting that it was just used.
+type SessionChecker interface {
+	TouchSession(ctx context.Context, id string) (bool, error)
+}
+
+// StatusChecker reports whether the user a token was issued to may still use
+// it.
+type StatusChecker interface {
+	UserActive(ctx context.Context, userID string) (bool, error)
+}
+
+// APIKeyAuthenticator resolves an API key to the claims requests made with it
+// act under. It returns nil claims for anything that is not a valid key.
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (*auth.Claims, error)
+}
+
+// ClientChecker reports whether the OAuth client a token was issued to may
+// still use it.
+type ClientChecker interface {
+	ClientActive(ctx context.Context, clientID string) (bool, error)
+}
+
+// Scopes maps what scoped credentials may call to the scope each needs:
+// routes as "GET /users/:id" and RPCs by full method name. Anything missing is
+// closed to them. Credentials without scopes, such as user logins, are not
+// restricted by it.
+type Scopes map[string]string
+
+// Allows reports whether claims may call name.
+func (s Scopes) Allows(claims *auth.Claims, name string) bool {
+	if !claims.IsScoped() {
+		return true
+	}
+	scope, ok := s[name]
+	return ok && slices.Contains(claims.Scopes, scope)
+}
+
+// checkActive reports whether a valid token may still be used: its session
+// has not been revoked and its user is active. Tokens without a session id,
+// issued before sessions existed, skip the session check. Client tokens have
+// neither; their client must still exist.
+func checkActive(ctx context.Context, sessions SessionChecker, users StatusChecker, clients ClientChecker, claims *auth.Claims) (bool, error) {
+	if claims.ClientID != "" {
+		return clients.ClientActive(ctx, claims.ClientID)
+	}
+	if claims.SessionID != "" {
+		active, err := sessions.TouchSession(ctx, claims.SessionID)
+		if err != nil || !active {
+			return false, err
+		}
+	}
+	return users.UserActive(ctx, claims.UserID)
+}
+
+func AuthMiddleware(secret string, sessions SessionChecker, users StatusChecker, clients ClientChecker, keys APIKeyAuthenticator, scopes Scopes) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			var tokenStr string
@@ -29,17 +88,107 @@ func AuthMiddleware(secret string) echo.MiddlewareFunc {
 				return echo.NewHTTPError(response.Unauthorized().WithHTTPStatus())
 			}
 
-			// 3. Parse and verify token
-			token, err := jwt.ParseWithClaims(tokenStr, &jwt.RegisteredClaims{}, func(token *jwt.Token) (interface{}, error) {
-				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, echo.NewHTTPError(http.StatusUnauthorized, "Unexpected signing method")
+			// 3. API keys are looked up rather than verified
+			claims, err := keys.AuthenticateAPIKey(c.Request().Context(), tokenStr)
+			if err != nil {
+				return echo.NewHTTPError(response.InternalServerError().WithHTTPStatus())
+			}
+			if claims == nil {
+				// 4. Parse and verify token
+				claims = &auth.Claims{}
+				token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
+					if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+						return nil, echo.NewHTTPError(http.StatusUnauthorized, "Unexpected signing method")
+					}
+					return []byte(secret), nil
+				})
+				if err != nil || !token.Valid {
+					return echo.NewHTTPError(response.Unauthorized().WithHTTPStatus())
+				}
+
+				// 5. Reject tokens whose session was revoked, whose user
+				// is no longer active or whose client was deleted
+				active, err := checkActive(c.Request().Context(), sessions, users, clients, claims)
+				if err != nil {
+					return echo.NewHTTPError(response.InternalServerError().WithHTTPStatus())
+				}
+				if !active {
+					return echo.NewHTTPError(response.Unauthorized().WithHTTPStatus())
 				}
-				return []byte(secret), nil
-			})
-			if err != nil || !token.Valid {
+			}
+
+			// 6. Keep scoped credentials to the routes their scopes allow
+			if !scopes.Allows(claims, c.Request().Method+" "+c.Path()) {
+				return echo.NewHTTPError(response.Forbidden().WithHTTPStatus())
+			}
+
+			// 7. Expose claims (and the tenant they carry) to handlers
+			ctx := auth.WithClaims(c.Request().Context(), claims)
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
+
+// RequireRole must be chained after AuthMiddleware.
+func RequireRole(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims, err := auth.FromContext(c.Request().Context())
+			if err != nil {
+				return echo.NewHTTPError(response.Unauthorized().WithHTTPStatus())
+			}
+			for _, role := range roles {
+				if claims.Role == role {
+					return next(c)
+				}
+			}
+			return echo.NewHTTPError(response.Forbidden().WithHTTPStatus())
+		}
+	}
+}
+
+// RequirePlatformAdmin allows only admins that are not bound to a tenant.
+// It must be chained after AuthMiddleware.
+func RequirePlatformAdmin(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := auth.FromContext(c.Request().Context())
+		if err != nil {
+			return echo.NewHTTPError(response.Unauthorized().WithHTTPStatus())
+		}
+		if !claims.IsPlatformAdmin() {
+			return echo.NewHTTPError(response.Forbidden().WithHTTPStatus())
+		}
+		return next(c)
+	}
+}
+
+// SCIMAuthenticator resolves a SCIM bearer token to the claims the request
+// acts under. It returns nil claims for tokens it does not accept.
+type SCIMAuthenticator interface {
+	AuthenticateSCIM(ctx context.Context, token string) (*auth.Claims, error)
+}
+
+// SCIMAuth accepts only SCIM tokens, not user JWTs, and scopes the request to
+// the token's tenant; for platform tokens that means platform users only.
+func SCIMAuth(tokens SCIMAuthenticator) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			tokenStr, ok := strings.CutPrefix(c.Request().Header.Get("Authorization"), "Bearer ")
+			if !ok || tokenStr == "" {
+				return echo.NewHTTPError(response.Unauthorized().WithHTTPStatus())
+			}
+
+			claims, err := tokens.AuthenticateSCIM(c.Request().Context(), tokenStr)
+			if err != nil {
+				return echo.NewHTTPError(response.InternalServerError().WithHTTPStatus())
+			}
+			if claims == nil {
 				return echo.NewHTTPError(response.Unauthorized().WithHTTPStatus())
 			}
 
+			ctx := auth.WithTenant(auth.WithClaims(c.Request().Context(), claims), claims.TenantID)
+			c.SetRequest(c.Request().WithContext(ctx))
 			return next(c)
 		}
 	}
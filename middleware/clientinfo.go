@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"user-management/clientinfo"
+
+	echo "github.com/labstack/echo/v4"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// ClientInfo stores the caller's IP and user agent in the request context.
+func ClientInfo(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		ctx := clientinfo.WithInfo(c.Request().Context(), clientinfo.Info{
+			IP:        c.RealIP(),
+			UserAgent: c.Request().UserAgent(),
+			Protocol:  clientinfo.ProtocolREST,
+		})
+		c.SetRequest(c.Request().WithContext(ctx))
+		return next(c)
+	}
+}
+
+// UnaryClientInfoInterceptor is the gRPC counterpart of ClientInfo.
+func UnaryClientInfoInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		return handler(clientinfo.WithInfo(ctx, grpcClientInfo(ctx)), req)
+	}
+}
+
+// StreamClientInfoInterceptor is the streaming counterpart of UnaryClientInfoInterceptor.
+func StreamClientInfoInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := clientinfo.WithInfo(ss.Context(), grpcClientInfo(ss.Context()))
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func grpcClientInfo(ctx context.Context) clientinfo.Info {
+	info := clientinfo.Info{Protocol: clientinfo.ProtocolGRPC}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		info.IP = p.Addr.String()
+		if host, _, err := net.SplitHostPort(info.IP); err == nil {
+			info.IP = host
+		}
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ua := md.Get("user-agent"); len(ua) > 0 {
+			info.UserAgent = ua[0]
+		}
+	}
+	return info
+}
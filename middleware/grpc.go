@@ -4,6 +4,7 @@ import (
 	"context"
 	"log"
 	"runtime/debug"
+	"slices"
 	"time"
 	"user-management/logger"
 
@@ -16,7 +17,10 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-func UnaryLoggingInterceptor() grpc.UnaryServerInterceptor {
+// UnaryLoggingInterceptor logs each call. The messages of redactedMethods,
+// given as full method names, are left out, and credentials in metadata are
+// never logged.
+func UnaryLoggingInterceptor(redactedMethods ...string) grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
 		req interface{},
@@ -37,11 +41,15 @@ func UnaryLoggingInterceptor() grpc.UnaryServerInterceptor {
 		// Call the handler
 		resp, err := handler(ctx, req)
 
+		var reqLog, respLog any = req, resp
+		if slices.Contains(redactedMethods, info.FullMethod) {
+			reqLog, respLog = redactedBody, redactedBody
+		}
 		zlog.Info("gRPC request",
 			zap.String("method", info.FullMethod),
-			zap.Any("metadata", md),
-			zap.Any("request", req),
-			zap.Any("response", resp),
+			zap.Any("metadata", redactMetadata(md)),
+			zap.Any("request", reqLog),
+			zap.Any("response", respLog),
 			zap.String("peer", p.Addr.String()),
 			zap.Duration("duration", time.Since(start)),
 			zap.Error(err),
@@ -51,6 +59,15 @@ func UnaryLoggingInterceptor() grpc.UnaryServerInterceptor {
 	}
 }
 
+// redactMetadata returns a copy of md without the authorization token.
+func redactMetadata(md metadata.MD) metadata.MD {
+	redacted := md.Copy()
+	if len(redacted.Get("authorization")) > 0 {
+		redacted.Set("authorization", redactedBody)
+	}
+	return redacted
+}
+
 func UnaryInterceptorRecovery() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
 		defer func() {
@@ -62,3 +79,35 @@ func UnaryInterceptorRecovery() grpc.UnaryServerInterceptor {
 		return handler(ctx, req)
 	}
 }
+
+// StreamLoggingInterceptor logs streaming calls. Messages are not logged, since
+// streams can carry large payloads such as bulk imports.
+func StreamLoggingInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		zlog := logger.NewZap().With(zap.String("request_id", uuid.New().String()))
+		p, _ := peer.FromContext(ss.Context())
+
+		err := handler(srv, ss)
+
+		zlog.Info("gRPC stream",
+			zap.String("method", info.FullMethod),
+			zap.String("peer", p.Addr.String()),
+			zap.Duration("duration", time.Since(start)),
+			zap.Error(err),
+		)
+		return err
+	}
+}
+
+func StreamInterceptorRecovery() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("Recovered from panic: %v\nStack Trace: %s", r, debug.Stack())
+				err = status.Errorf(codes.Internal, "internal server error")
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
@@ -3,7 +3,9 @@ package middleware
 import (
 	"context"
 	"errors"
+	"slices"
 	"strings"
+	"user-management/auth"
 
 	"github.com/golang-jwt/jwt/v5"
 	"google.golang.org/grpc"
@@ -12,38 +14,97 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-// GrpcAuthInterceptor returns a unary server interceptor that validates JWT tokens.
-func GrpcAuthInterceptor(secret string) grpc.UnaryServerInterceptor {
+// GrpcAuthInterceptor returns a unary server interceptor that validates JWT tokens
+// and API keys. Calls to publicMethods, given as full method names, skip validation.
+func GrpcAuthInterceptor(secret string, sessions SessionChecker, users StatusChecker, clients ClientChecker, keys APIKeyAuthenticator, scopes Scopes, publicMethods ...string) grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
 		req interface{},
 		info *grpc.UnaryServerInfo,
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
-
-		md, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			return nil, status.Errorf(codes.Unauthenticated, "Missing metadata")
+		if slices.Contains(publicMethods, info.FullMethod) {
+			return handler(ctx, req)
 		}
-
-		tokenStr := extractToken(md)
-		if tokenStr == "" {
-			return nil, status.Errorf(codes.Unauthenticated, "Authorization token required")
+		claims, err := authenticate(ctx, secret, sessions, users, clients, keys)
+		if err != nil {
+			return nil, err
 		}
-
-		token, err := jwt.ParseWithClaims(tokenStr, &jwt.RegisteredClaims{}, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, errors.New("Unexpected signing method")
-			}
-			return []byte(secret), nil
-		})
-		if err != nil || !token.Valid {
-			return nil, status.Errorf(codes.Unauthenticated, "Invalid or expired token")
+		if !scopes.Allows(claims, info.FullMethod) {
+			return nil, status.Errorf(codes.PermissionDenied, "Permission denied")
 		}
 
 		// Proceed to actual RPC
-		return handler(ctx, req)
+		return handler(auth.WithClaims(ctx, claims), req)
+	}
+}
+
+// GrpcAuthStreamInterceptor is the streaming counterpart of GrpcAuthInterceptor.
+func GrpcAuthStreamInterceptor(secret string, sessions SessionChecker, users StatusChecker, clients ClientChecker, keys APIKeyAuthenticator, scopes Scopes) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		claims, err := authenticate(ss.Context(), secret, sessions, users, clients, keys)
+		if err != nil {
+			return err
+		}
+		if !scopes.Allows(claims, info.FullMethod) {
+			return status.Errorf(codes.PermissionDenied, "Permission denied")
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: auth.WithClaims(ss.Context(), claims)})
+	}
+}
+
+// contextStream overrides the context of a server stream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+func authenticate(ctx context.Context, secret string, sessions SessionChecker, users StatusChecker, clients ClientChecker, keys APIKeyAuthenticator) (*auth.Claims, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "Missing metadata")
+	}
+
+	tokenStr := extractToken(md)
+	if tokenStr == "" {
+		return nil, status.Errorf(codes.Unauthenticated, "Authorization token required")
+	}
+
+	claims, err := keys.AuthenticateAPIKey(ctx, tokenStr)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "internal server error")
+	}
+	if claims != nil {
+		return claims, nil
+	}
+
+	claims = &auth.Claims{}
+	token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("Unexpected signing method")
+		}
+		return []byte(secret), nil
+	})
+	if err != nil || !token.Valid {
+		return nil, status.Errorf(codes.Unauthenticated, "Invalid or expired token")
+	}
+	active, err := checkActive(ctx, sessions, users, clients, claims)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "internal server error")
+	}
+	if !active {
+		return nil, status.Errorf(codes.Unauthenticated, "Session has ended or account is not active")
 	}
+	return claims, nil
 }
 
 func extractToken(md metadata.MD) string {
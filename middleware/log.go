@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
 	"time"
 	"user-management/logger"
 
@@ -13,6 +14,13 @@ import (
 	"go.uber.org/zap"
 )
 
+// redactedBody is logged in place of a body or header left out of the logs.
+const redactedBody = "[REDACTED]"
+
+// secretHeaders carry tokens, API keys or client credentials and are never
+// logged.
+var secretHeaders = []string{echo.HeaderAuthorization, echo.HeaderCookie, echo.HeaderSetCookie}
+
 func NewLogging(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		requestId := uuid.New().String()
@@ -25,37 +33,84 @@ func NewLogging(next echo.HandlerFunc) echo.HandlerFunc {
 	}
 }
 
+// Redact says which bodies of a route are left out of its log lines.
+type Redact int
+
+const (
+	// RedactRequest leaves out the request body.
+	RedactRequest Redact = 1 << iota
+	// RedactResponse leaves out the response body. It is not buffered either,
+	// so large responses stream straight to the client.
+	RedactResponse
+)
+
+// Redactions maps routes, as "GET /users/export", to the bodies left out of
+// their log lines.
+type Redactions map[string]Redact
+
 func LoggingMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(c echo.Context) error {
-		zlog, err := logger.FromContext(c.Request().Context())
-		start := time.Now()
-		reqBody := new(bytes.Buffer)
-		reqBody.ReadFrom(c.Request().Body)
-		zlog.Sugar().With(
-			zap.Any("header", c.Request().Header),
-			zap.Any("body", reqBody.String()),
-		).Infof("[REST API] %s, %s, Request", c.Request().Method, c.Path())
-		c.Request().Body = io.NopCloser(reqBody)
-		resBody := new(bytes.Buffer)
-		multiWriter := io.MultiWriter(c.Response().Writer, resBody)
-		writer := &CustomResponseWriter{Writer: multiWriter, ResponseWriter: c.Response().Writer}
-		c.Response().Writer = writer
-		err = next(c)
+	return LoggingWithRedactions(nil)(next)
+}
+
+// LoggingWithRedactions logs each request and response like
+// LoggingMiddleware, leaving out the bodies redactions lists.
+func LoggingWithRedactions(redactions Redactions) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			zlog, err := logger.FromContext(c.Request().Context())
+			start := time.Now()
+			redact := redactions[c.Request().Method+" "+c.Path()]
+			reqLog := redactedBody
+			if redact&RedactRequest == 0 {
+				reqBody := new(bytes.Buffer)
+				reqBody.ReadFrom(c.Request().Body)
+				c.Request().Body = io.NopCloser(reqBody)
+				reqLog = reqBody.String()
+			}
+			zlog.Sugar().With(
+				zap.Any("header", redactHeader(c.Request().Header)),
+				zap.Any("body", reqLog),
+			).Infof("[REST API] %s, %s, Request", c.Request().Method, c.Path())
+			var resBody *bytes.Buffer
+			if redact&RedactResponse == 0 {
+				resBody = new(bytes.Buffer)
+				multiWriter := io.MultiWriter(c.Response().Writer, resBody)
+				writer := &CustomResponseWriter{Writer: multiWriter, ResponseWriter: c.Response().Writer}
+				c.Response().Writer = writer
+			}
+			err = next(c)
+
+			httpPayload := logger.HTTPPayload{
+				RequestMethod: c.Request().Method,
+				RequestURL:    c.Request().URL.String(),
+				Status:        c.Response().Status,
+				Latency:       calLatency(start),
+				ResponseSize:  fmt.Sprintf("%d", c.Response().Size),
+			}
+			resLog := redactedBody
+			if resBody != nil {
+				resLog = resBody.String()
+			}
+			zlog.Sugar().With(
+				zap.Any("header", redactHeader(c.Response().Header())),
+				zap.Any("body", resLog),
+				zap.Any("httpRequest", httpPayload),
+			).Infof("[REST API] %s, %s, Response", c.Request().Method, c.Path())
+			return err
+		}
+	}
+}
 
-		httpPayload := logger.HTTPPayload{
-			RequestMethod: c.Request().Method,
-			RequestURL:    c.Request().URL.String(),
-			Status:        c.Response().Status,
-			Latency:       calLatency(start),
-			ResponseSize:  fmt.Sprintf("%d", c.Response().Size),
+// redactHeader returns a copy of h with the values of secretHeaders
+// replaced.
+func redactHeader(h http.Header) http.Header {
+	redacted := h.Clone()
+	for _, name := range secretHeaders {
+		if _, ok := redacted[name]; ok {
+			redacted[name] = []string{redactedBody}
 		}
-		zlog.Sugar().With(
-			zap.Any("header", c.Response().Header()),
-			zap.Any("body", resBody.String()),
-			zap.Any("httpRequest", httpPayload),
-		).Infof("[REST API] %s, %s, Response", c.Request().Method, c.Path())
-		return err
 	}
+	return redacted
 }
 
 func calLatency(t time.Time) (output string) {
@@ -13,3 +13,14 @@ type CustomResponseWriter struct {
 func (w CustomResponseWriter) Write(b []byte) (int, error) {
 	return w.Writer.Write(b)
 }
+
+// Flush passes flushes on to the wrapped writer, so streamed responses reach
+// the client while they are logged.
+func (w CustomResponseWriter) Flush() {
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the wrapped writer.
+func (w CustomResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,43 @@
+package notifier
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type Invitation struct {
+	Email     string
+	Role      string
+	TenantID  string
+	Token     string
+	AcceptURL string
+	ExpiresAt time.Time
+}
+
+type Notifier interface {
+	SendInvitation(ctx context.Context, inv Invitation) error
+}
+
+// LogNotifier writes notifications to the application log. It is meant for local
+// development, where no mail delivery is configured.
+type LogNotifier struct {
+	zlog *zap.Logger
+}
+
+func NewLogNotifier(zlog *zap.Logger) *LogNotifier {
+	return &LogNotifier{zlog: zlog}
+}
+
+func (n *LogNotifier) SendInvitation(ctx context.Context, inv Invitation) error {
+	n.zlog.Info("Invitation created",
+		zap.String("email", inv.Email),
+		zap.String("role", inv.Role),
+		zap.String("tenant_id", inv.TenantID),
+		zap.String("accept_url", inv.AcceptURL),
+		zap.String("token", inv.Token),
+		zap.Time("expires_at", inv.ExpiresAt),
+	)
+	return nil
+}
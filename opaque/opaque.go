@@ -0,0 +1,26 @@
+// Package opaque creates the random tokens handed out as secrets, codes and
+// keys, and the hashes stored in their place.
+package opaque
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// New returns a random token of 32 bytes, base64url encoded without padding.
+func New() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Hash returns the hex SHA-256 of token. Only hashes are stored, so a leaked
+// collection cannot be used to impersonate anyone.
+func Hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
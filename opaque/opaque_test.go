@@ -0,0 +1,27 @@
+package opaque_test
+
+import (
+	"encoding/base64"
+	"testing"
+	"user-management/opaque"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	a, err := opaque.New()
+	require.NoError(t, err)
+	b, err := opaque.New()
+	require.NoError(t, err)
+
+	assert.NotEqual(t, a, b)
+	raw, err := base64.RawURLEncoding.DecodeString(a)
+	require.NoError(t, err)
+	assert.Len(t, raw, 32)
+}
+
+func TestHash(t *testing.T) {
+	assert.Equal(t, "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", opaque.Hash("test"))
+	assert.NotEqual(t, opaque.Hash("test"), opaque.Hash("Test"))
+}
@@ -0,0 +1,78 @@
+package pagination
+
+import (
+	"strconv"
+	"user-management/response"
+)
+
+const (
+	QueryPage  = "page"
+	QueryLimit = "limit"
+
+	DefaultLimit int64 = 20
+	MaxLimit     int64 = 100
+)
+
+type Request struct {
+	Page  int64
+	Limit int64
+}
+
+type Result[T any] struct {
+	Items []T   `json:"items"`
+	Page  int64 `json:"page"`
+	Limit int64 `json:"limit"`
+	Total int64 `json:"total"`
+}
+
+// Parse builds a Request from raw query values. Empty values fall back to the
+// first page and DefaultLimit.
+func Parse(page, limit string) (Request, *response.StdResp[any]) {
+	req := Request{Page: 1, Limit: DefaultLimit}
+	if page != "" {
+		p, err := strconv.ParseInt(page, 10, 64)
+		if err != nil || p < 1 {
+			return req, response.InvalidData(QueryPage)
+		}
+		req.Page = p
+	}
+	if limit != "" {
+		l, err := strconv.ParseInt(limit, 10, 64)
+		if err != nil || l < 1 || l > MaxLimit {
+			return req, response.InvalidData(QueryLimit)
+		}
+		req.Limit = l
+	}
+	return req, response.Success()
+}
+
+// Normalize applies the same defaults as Parse to a Request that did not come
+// from a query string, such as a gRPC message.
+func (r Request) Normalize() Request {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+	if r.Limit < 1 {
+		r.Limit = DefaultLimit
+	}
+	if r.Limit > MaxLimit {
+		r.Limit = MaxLimit
+	}
+	return r
+}
+
+func (r Request) Skip() int64 {
+	return (r.Page - 1) * r.Limit
+}
+
+func NewResult[T any](items []T, req Request, total int64) Result[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return Result[T]{
+		Items: items,
+		Page:  req.Page,
+		Limit: req.Limit,
+		Total: total,
+	}
+}
@@ -0,0 +1,57 @@
+package passhash
+
+import (
+	"errors"
+	"fmt"
+	"user-management/config"
+
+	"golang.org/x/crypto/argon2"
+)
+
+type argon2idHasher struct {
+	time    uint32
+	memory  uint32
+	threads uint8
+}
+
+func newArgon2idHasher(cfg config.PasswordHashConfig) (*argon2idHasher, error) {
+	if cfg.Argon2Time < 1 || cfg.Argon2Threads < 1 || cfg.Argon2Memory < 8*uint32(cfg.Argon2Threads) {
+		return nil, errors.New("argon2id needs a time and threads of at least 1 and at least 8 KiB of memory per thread")
+	}
+	return &argon2idHasher{time: cfg.Argon2Time, memory: cfg.Argon2Memory, threads: cfg.Argon2Threads}, nil
+}
+
+func (h *argon2idHasher) Hash(plain string) (string, error) {
+	salt, err := newSalt()
+	if err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(plain), salt, h.time, h.memory, h.threads, keyLength)
+	return encode(AlgorithmArgon2id, h.params(), salt, key), nil
+}
+
+func (h *argon2idHasher) NeedsRehash(hash string) bool {
+	params, _, key, err := decode(hash, AlgorithmArgon2id)
+	return err != nil || params != h.params() || len(key) != keyLength
+}
+
+func (h *argon2idHasher) params() string {
+	return fmt.Sprintf("v=%d$m=%d,t=%d,p=%d", argon2.Version, h.memory, h.time, h.threads)
+}
+
+func verifyArgon2id(hash, plain string) (bool, error) {
+	params, salt, key, err := decode(hash, AlgorithmArgon2id)
+	if err != nil {
+		return false, err
+	}
+	var version int
+	var memory, time uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(params, "v=%d$m=%d,t=%d,p=%d", &version, &memory, &time, &threads); err != nil {
+		return false, ErrMalformedHash
+	}
+	if version != argon2.Version || time < 1 || threads < 1 {
+		return false, ErrMalformedHash
+	}
+	return equalKeys(argon2.IDKey([]byte(plain), salt, time, memory, threads, uint32(len(key))), key), nil
+}
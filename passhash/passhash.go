@@ -0,0 +1,98 @@
+// Package passhash hashes passwords with argon2id, bcrypt or scrypt. Every
+// hash carries its algorithm and parameters, so hashes made with any of them,
+// under any settings, can still be verified after the configuration changes.
+package passhash
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"user-management/config"
+)
+
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmScrypt   = "scrypt"
+
+	saltLength = 16
+	keyLength  = 32
+)
+
+var ErrMalformedHash = errors.New("malformed password hash")
+
+// Hasher makes hashes with the configured algorithm and parameters.
+type Hasher interface {
+	Hash(plain string) (string, error)
+	// NeedsRehash reports whether hash was made with another algorithm or
+	// other parameters than the ones Hash uses now.
+	NeedsRehash(hash string) bool
+}
+
+// NewHasher returns the hasher selected by cfg.Algorithm.
+func NewHasher(cfg config.PasswordHashConfig) (Hasher, error) {
+	switch cfg.Algorithm {
+	case AlgorithmArgon2id:
+		return newArgon2idHasher(cfg)
+	case AlgorithmBcrypt:
+		return newBcryptHasher(cfg)
+	case AlgorithmScrypt:
+		return newScryptHasher(cfg)
+	default:
+		return nil, fmt.Errorf("unknown password hash algorithm %q", cfg.Algorithm)
+	}
+}
+
+// Verify reports whether plain matches hash, whichever supported algorithm
+// made it. Malformed and unknown hashes never match.
+func Verify(hash, plain string) bool {
+	var ok bool
+	var err error
+	switch {
+	case strings.HasPrefix(hash, "$"+AlgorithmArgon2id+"$"):
+		ok, err = verifyArgon2id(hash, plain)
+	case strings.HasPrefix(hash, "$"+AlgorithmScrypt+"$"):
+		ok, err = verifyScrypt(hash, plain)
+	case isBcrypt(hash):
+		ok, err = verifyBcrypt(hash, plain)
+	}
+	return err == nil && ok
+}
+
+func newSalt() ([]byte, error) {
+	salt := make([]byte, saltLength)
+	_, err := rand.Read(salt)
+	return salt, err
+}
+
+// encode formats a hash in the PHC string format:
+// $<id>$<params>$<salt>$<key>.
+func encode(id, params string, salt, key []byte) string {
+	return fmt.Sprintf("$%s$%s$%s$%s", id, params,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key))
+}
+
+// decode splits a PHC string made by encode into its parameter section, salt
+// and key. Extra sections (such as argon2's version) are returned in params
+// joined by "$".
+func decode(hash, id string) (params string, salt, key []byte, err error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) < 5 || parts[0] != "" || parts[1] != id {
+		return "", nil, nil, ErrMalformedHash
+	}
+	n := len(parts)
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[n-2]); err != nil {
+		return "", nil, nil, ErrMalformedHash
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(parts[n-1]); err != nil || len(key) == 0 {
+		return "", nil, nil, ErrMalformedHash
+	}
+	return strings.Join(parts[2:n-2], "$"), salt, key, nil
+}
+
+func equalKeys(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
@@ -0,0 +1,112 @@
+package passhash_test
+
+import (
+	"strings"
+	"testing"
+	"user-management/config"
+	"user-management/passhash"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Cheap parameters keep the tests fast; NeedsRehash compares against them.
+var testConfig = config.PasswordHashConfig{
+	Argon2Time:    1,
+	Argon2Memory:  64,
+	Argon2Threads: 1,
+	BcryptCost:    bcrypt.MinCost,
+	ScryptN:       16,
+	ScryptR:       8,
+	ScryptP:       1,
+}
+
+func newHasher(t *testing.T, algorithm string, change func(*config.PasswordHashConfig)) passhash.Hasher {
+	cfg := testConfig
+	cfg.Algorithm = algorithm
+	if change != nil {
+		change(&cfg)
+	}
+	h, err := passhash.NewHasher(cfg)
+	require.NoError(t, err)
+	return h
+}
+
+func TestHasher(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		prefix    string
+		outdated  func(*config.PasswordHashConfig)
+	}{
+		{passhash.AlgorithmArgon2id, "$argon2id$v=19$m=64,t=1,p=1$", func(c *config.PasswordHashConfig) { c.Argon2Time = 2 }},
+		{passhash.AlgorithmBcrypt, "$2a$04$", func(c *config.PasswordHashConfig) { c.BcryptCost = bcrypt.MinCost + 1 }},
+		{passhash.AlgorithmScrypt, "$scrypt$ln=4,r=8,p=1$", func(c *config.PasswordHashConfig) { c.ScryptN = 32 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			h := newHasher(t, tt.algorithm, nil)
+
+			hash, err := h.Hash("correct horse")
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(hash, tt.prefix), hash)
+			assert.True(t, passhash.Verify(hash, "correct horse"))
+			assert.False(t, passhash.Verify(hash, "wrong horse"))
+			assert.False(t, h.NeedsRehash(hash))
+
+			again, err := h.Hash("correct horse")
+			require.NoError(t, err)
+			assert.NotEqual(t, hash, again, "hashes must be salted")
+
+			assert.True(t, newHasher(t, tt.algorithm, tt.outdated).NeedsRehash(hash))
+		})
+	}
+}
+
+func TestHasher_NeedsRehashOtherAlgorithm(t *testing.T) {
+	bcryptHash, err := newHasher(t, passhash.AlgorithmBcrypt, nil).Hash("pw")
+	require.NoError(t, err)
+	argonHash, err := newHasher(t, passhash.AlgorithmArgon2id, nil).Hash("pw")
+	require.NoError(t, err)
+
+	assert.True(t, newHasher(t, passhash.AlgorithmArgon2id, nil).NeedsRehash(bcryptHash))
+	assert.True(t, newHasher(t, passhash.AlgorithmBcrypt, nil).NeedsRehash(argonHash))
+	assert.True(t, newHasher(t, passhash.AlgorithmScrypt, nil).NeedsRehash(argonHash))
+}
+
+func TestVerify_Malformed(t *testing.T) {
+	for _, hash := range []string{
+		"",
+		"plain",
+		"$argon2id$v=19$m=64,t=1,p=1$",
+		"$argon2id$v=18$m=64,t=1,p=1$c2FsdHNhbHRzYWx0$a2V5",
+		"$scrypt$ln=x,r=8,p=1$c2FsdHNhbHRzYWx0$a2V5",
+		"$md5$abc",
+	} {
+		assert.False(t, passhash.Verify(hash, "pw"), hash)
+	}
+}
+
+func TestNewHasher_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(*config.PasswordHashConfig)
+	}{
+		{"Unknown algorithm", func(c *config.PasswordHashConfig) { c.Algorithm = "md5" }},
+		{"Argon2 without memory", func(c *config.PasswordHashConfig) { c.Algorithm = passhash.AlgorithmArgon2id; c.Argon2Memory = 4 }},
+		{"Bcrypt cost too high", func(c *config.PasswordHashConfig) { c.Algorithm = passhash.AlgorithmBcrypt; c.BcryptCost = 40 }},
+		{"Scrypt N not a power of two", func(c *config.PasswordHashConfig) { c.Algorithm = passhash.AlgorithmScrypt; c.ScryptN = 1000 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig
+			tt.change(&cfg)
+
+			_, err := passhash.NewHasher(cfg)
+
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package passhash
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"user-management/config"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// scryptHasher stores N as its base-2 logarithm, ln, like passlib does.
+type scryptHasher struct {
+	ln, r, p int
+}
+
+func newScryptHasher(cfg config.PasswordHashConfig) (*scryptHasher, error) {
+	if cfg.ScryptN < 2 || bits.OnesCount(uint(cfg.ScryptN)) != 1 || cfg.ScryptR < 1 || cfg.ScryptP < 1 {
+		return nil, errors.New("scrypt needs N to be a power of two greater than 1, and r and p of at least 1")
+	}
+	return &scryptHasher{ln: bits.TrailingZeros(uint(cfg.ScryptN)), r: cfg.ScryptR, p: cfg.ScryptP}, nil
+}
+
+func (h *scryptHasher) Hash(plain string) (string, error) {
+	salt, err := newSalt()
+	if err != nil {
+		return "", err
+	}
+	key, err := scrypt.Key([]byte(plain), salt, 1<<h.ln, h.r, h.p, keyLength)
+	if err != nil {
+		return "", err
+	}
+	return encode(AlgorithmScrypt, h.params(), salt, key), nil
+}
+
+func (h *scryptHasher) NeedsRehash(hash string) bool {
+	params, _, key, err := decode(hash, AlgorithmScrypt)
+	return err != nil || params != h.params() || len(key) != keyLength
+}
+
+func (h *scryptHasher) params() string {
+	return fmt.Sprintf("ln=%d,r=%d,p=%d", h.ln, h.r, h.p)
+}
+
+func verifyScrypt(hash, plain string) (bool, error) {
+	params, salt, key, err := decode(hash, AlgorithmScrypt)
+	if err != nil {
+		return false, err
+	}
+	var ln, r, p int
+	if _, err := fmt.Sscanf(params, "ln=%d,r=%d,p=%d", &ln, &r, &p); err != nil || ln < 1 || ln > 62 {
+		return false, ErrMalformedHash
+	}
+	derived, err := scrypt.Key([]byte(plain), salt, 1<<ln, r, p, len(key))
+	if err != nil {
+		return false, err
+	}
+	return equalKeys(derived, key), nil
+}
@@ -3,43 +3,107 @@ package response
 import (
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 // response
 const (
-	success                = "0000"
-	unexpectedRequest      = "4000"
-	mandatoryMissing       = "4001"
-	duplicatedRegistration = "4002"
-	loginFailed            = "4003"
-	invalidData            = "4004"
-	userNotFound           = "4005"
-	invalidAuthToken       = "4006"
-	internalServerError    = "5000"
+	success                 = "0000"
+	unexpectedRequest       = "4000"
+	mandatoryMissing        = "4001"
+	duplicatedRegistration  = "4002"
+	loginFailed             = "4003"
+	invalidData             = "4004"
+	userNotFound            = "4005"
+	invalidAuthToken        = "4006"
+	forbidden               = "4007"
+	tenantNotFound          = "4008"
+	duplicatedTenant        = "4009"
+	groupNotFound           = "4010"
+	duplicatedGroup         = "4011"
+	duplicatedMember        = "4012"
+	memberNotFound          = "4013"
+	inviteNotFound          = "4014"
+	invalidInvite           = "4015"
+	weakPassword            = "4016"
+	sessionNotFound         = "4017"
+	invalidStatusTransition = "4018"
+	accountInactive         = "4019"
+	invalidProfile          = "4020"
+	invalidAvatar           = "4021"
+	avatarNotFound          = "4022"
+	encryptionNotConfigured = "4023"
+	clientNotFound          = "4024"
+	providerNotFound        = "4025"
+	tokenNotFound           = "4026"
+	apiKeyNotFound          = "4027"
+	internalServerError     = "5000"
 )
 
 var message = map[string]string{
-	success:                "Success",
-	unexpectedRequest:      "Unexpected request",
-	mandatoryMissing:       "%s is required",
-	duplicatedRegistration: "An email has already been used",
-	loginFailed:            "Login failed",
-	invalidData:            "%s is invalid data",
-	userNotFound:           "User not found",
-	invalidAuthToken:       "Invalid authentication token",
-	internalServerError:    "Internal server error",
+	success:                 "Success",
+	unexpectedRequest:       "Unexpected request",
+	mandatoryMissing:        "%s is required",
+	duplicatedRegistration:  "An email has already been used",
+	loginFailed:             "Login failed",
+	invalidData:             "%s is invalid data",
+	userNotFound:            "User not found",
+	invalidAuthToken:        "Invalid authentication token",
+	forbidden:               "Permission denied",
+	tenantNotFound:          "Tenant not found",
+	duplicatedTenant:        "A tenant slug has already been used",
+	groupNotFound:           "Group not found",
+	duplicatedGroup:         "A group name has already been used",
+	duplicatedMember:        "User is already a member of the group",
+	memberNotFound:          "Member not found",
+	inviteNotFound:          "Invitation not found",
+	invalidInvite:           "Invitation is invalid or expired",
+	weakPassword:            "Password does not meet the policy: %s",
+	sessionNotFound:         "Session not found",
+	invalidStatusTransition: "Status change is not allowed",
+	accountInactive:         "Account is not active",
+	invalidProfile:          "Profile is invalid: %s",
+	invalidAvatar:           "Avatar is invalid: %s",
+	avatarNotFound:          "Avatar not found",
+	encryptionNotConfigured: "Encryption at rest is not configured",
+	clientNotFound:          "Client not found",
+	providerNotFound:        "Identity provider not found",
+	tokenNotFound:           "Token not found",
+	apiKeyNotFound:          "API key not found",
+	internalServerError:     "Internal server error",
 }
 
 var mapHTTPStatus = map[string]int{
-	success:                http.StatusOK,
-	unexpectedRequest:      http.StatusBadRequest,
-	mandatoryMissing:       http.StatusBadRequest,
-	duplicatedRegistration: http.StatusBadRequest,
-	loginFailed:            http.StatusBadRequest,
-	invalidData:            http.StatusBadRequest,
-	userNotFound:           http.StatusNotFound,
-	invalidAuthToken:       http.StatusUnauthorized,
-	internalServerError:    http.StatusInternalServerError,
+	success:                 http.StatusOK,
+	unexpectedRequest:       http.StatusBadRequest,
+	mandatoryMissing:        http.StatusBadRequest,
+	duplicatedRegistration:  http.StatusBadRequest,
+	loginFailed:             http.StatusBadRequest,
+	invalidData:             http.StatusBadRequest,
+	userNotFound:            http.StatusNotFound,
+	invalidAuthToken:        http.StatusUnauthorized,
+	forbidden:               http.StatusForbidden,
+	tenantNotFound:          http.StatusNotFound,
+	duplicatedTenant:        http.StatusBadRequest,
+	groupNotFound:           http.StatusNotFound,
+	duplicatedGroup:         http.StatusBadRequest,
+	duplicatedMember:        http.StatusBadRequest,
+	memberNotFound:          http.StatusNotFound,
+	inviteNotFound:          http.StatusNotFound,
+	invalidInvite:           http.StatusBadRequest,
+	weakPassword:            http.StatusBadRequest,
+	sessionNotFound:         http.StatusNotFound,
+	invalidStatusTransition: http.StatusBadRequest,
+	accountInactive:         http.StatusForbidden,
+	invalidProfile:          http.StatusBadRequest,
+	invalidAvatar:           http.StatusBadRequest,
+	avatarNotFound:          http.StatusNotFound,
+	encryptionNotConfigured: http.StatusBadRequest,
+	clientNotFound:          http.StatusNotFound,
+	providerNotFound:        http.StatusNotFound,
+	tokenNotFound:           http.StatusNotFound,
+	apiKeyNotFound:          http.StatusNotFound,
+	internalServerError:     http.StatusInternalServerError,
 }
 
 type StdResp[T any] struct {
@@ -120,6 +184,176 @@ func Unauthorized() *StdResp[any] {
 	}
 }
 
+func Forbidden() *StdResp[any] {
+	return &StdResp[any]{
+		Code:    forbidden,
+		Message: message[forbidden],
+	}
+}
+
+func TenantNotFound() *StdResp[any] {
+	return &StdResp[any]{
+		Code:    tenantNotFound,
+		Message: message[tenantNotFound],
+	}
+}
+
+func DuplicatedTenant() *StdResp[any] {
+	return &StdResp[any]{
+		Code:    duplicatedTenant,
+		Message: message[duplicatedTenant],
+	}
+}
+
+func GroupNotFound() *StdResp[any] {
+	return &StdResp[any]{
+		Code:    groupNotFound,
+		Message: message[groupNotFound],
+	}
+}
+
+func DuplicatedGroup() *StdResp[any] {
+	return &StdResp[any]{
+		Code:    duplicatedGroup,
+		Message: message[duplicatedGroup],
+	}
+}
+
+func DuplicatedMember() *StdResp[any] {
+	return &StdResp[any]{
+		Code:    duplicatedMember,
+		Message: message[duplicatedMember],
+	}
+}
+
+func MemberNotFound() *StdResp[any] {
+	return &StdResp[any]{
+		Code:    memberNotFound,
+		Message: message[memberNotFound],
+	}
+}
+
+func InviteNotFound() *StdResp[any] {
+	return &StdResp[any]{
+		Code:    inviteNotFound,
+		Message: message[inviteNotFound],
+	}
+}
+
+func InvalidInvite() *StdResp[any] {
+	return &StdResp[any]{
+		Code:    invalidInvite,
+		Message: message[invalidInvite],
+	}
+}
+
+// Violation is one password policy rule a password failed.
+type Violation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func WeakPassword(violations []Violation) *StdResp[any] {
+	rules := make([]string, len(violations))
+	for i, v := range violations {
+		rules[i] = v.Rule
+	}
+	return &StdResp[any]{
+		Code:    weakPassword,
+		Message: fmt.Sprintf(message[weakPassword], strings.Join(rules, ", ")),
+		Data:    violations,
+	}
+}
+
+func SessionNotFound() *StdResp[any] {
+	return &StdResp[any]{
+		Code:    sessionNotFound,
+		Message: message[sessionNotFound],
+	}
+}
+
+func InvalidStatusTransition() *StdResp[any] {
+	return &StdResp[any]{
+		Code:    invalidStatusTransition,
+		Message: message[invalidStatusTransition],
+	}
+}
+
+func AccountInactive() *StdResp[any] {
+	return &StdResp[any]{
+		Code:    accountInactive,
+		Message: message[accountInactive],
+	}
+}
+
+// ProfileViolation is one profile attribute that does not match the schema.
+type ProfileViolation struct {
+	Attribute string `json:"attribute"`
+	Rule      string `json:"rule"`
+	Message   string `json:"message"`
+}
+
+func InvalidProfile(violations []ProfileViolation) *StdResp[any] {
+	attributes := make([]string, len(violations))
+	for i, v := range violations {
+		attributes[i] = v.Attribute
+	}
+	return &StdResp[any]{
+		Code:    invalidProfile,
+		Message: fmt.Sprintf(message[invalidProfile], strings.Join(attributes, ", ")),
+		Data:    violations,
+	}
+}
+
+func InvalidAvatar(reason string) *StdResp[any] {
+	return &StdResp[any]{
+		Code:    invalidAvatar,
+		Message: fmt.Sprintf(message[invalidAvatar], reason),
+	}
+}
+
+func AvatarNotFound() *StdResp[any] {
+	return &StdResp[any]{
+		Code:    avatarNotFound,
+		Message: message[avatarNotFound],
+	}
+}
+
+func EncryptionNotConfigured() *StdResp[any] {
+	return &StdResp[any]{
+		Code:    encryptionNotConfigured,
+		Message: message[encryptionNotConfigured],
+	}
+}
+
+func ClientNotFound() *StdResp[any] {
+	return &StdResp[any]{
+		Code:    clientNotFound,
+		Message: message[clientNotFound],
+	}
+}
+
+func ProviderNotFound() *StdResp[any] {
+	return &StdResp[any]{
+		Code:    providerNotFound,
+		Message: message[providerNotFound],
+	}
+}
+
+func TokenNotFound() *StdResp[any] {
+	return &StdResp[any]{
+		Code:    tokenNotFound,
+		Message: message[tokenNotFound],
+	}
+}
+
+func APIKeyNotFound() *StdResp[any] {
+	return &StdResp[any]{
+		Code:    apiKeyNotFound,
+		Message: message[apiKeyNotFound],
+	}
+}
+
 func InternalServerError() *StdResp[any] {
 	return &StdResp[any]{
 		Code:    internalServerError,
@@ -4,6 +4,12 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"user-management/app/group"
+	groupgrpc "user-management/app/group/grpc/gen/go/group/v1"
+	"user-management/app/privacy"
+	privacygrpc "user-management/app/privacy/grpc/gen/go/privacy/v1"
+	"user-management/app/session"
+	sessiongrpc "user-management/app/session/grpc/gen/go/session/v1"
 	"user-management/app/user"
 	usergrpc "user-management/app/user/grpc/gen/go/user/v1"
 	"user-management/config"
@@ -19,24 +25,34 @@ type GRPC struct {
 	listener net.Listener
 }
 
-func NewGRPCServer(usecase user.Usecase, zlog *zap.Logger, cfg *config.AppConfig) (*GRPC, error) {
+func NewGRPCServer(usecase user.Usecase, groupUsecase group.Usecase, sessionUsecase session.Usecase, privacyUsecase privacy.Usecase, sessions middleware.SessionChecker, users middleware.StatusChecker, clients middleware.ClientChecker, keys middleware.APIKeyAuthenticator, zlog *zap.Logger, cfg *config.AppConfig) (*GRPC, error) {
 	grpcHandler := user.NewGrpcHandler(usecase)
 
 	grpcServer := grpc.NewServer(
 		grpc.ChainUnaryInterceptor(
 			middleware.UnaryInterceptorRecovery(),
-			middleware.UnaryLoggingInterceptor(),
-			middleware.GrpcAuthInterceptor(cfg.Crypto.JwtKey),
+			middleware.UnaryLoggingInterceptor(grpcRedactedMethods...),
+			middleware.UnaryClientInfoInterceptor(),
+			middleware.GrpcAuthInterceptor(cfg.Crypto.JwtKey, sessions, users, clients, keys, grpcScopes, usergrpc.UserService_Login_FullMethodName),
+		),
+		grpc.ChainStreamInterceptor(
+			middleware.StreamInterceptorRecovery(),
+			middleware.StreamLoggingInterceptor(),
+			middleware.StreamClientInfoInterceptor(),
+			middleware.GrpcAuthStreamInterceptor(cfg.Crypto.JwtKey, sessions, users, clients, keys, grpcScopes),
 		),
 	)
 
 	usergrpc.RegisterUserServiceServer(grpcServer, grpcHandler)
+	groupgrpc.RegisterGroupServiceServer(grpcServer, group.NewGrpcHandler(groupUsecase))
+	sessiongrpc.RegisterSessionServiceServer(grpcServer, session.NewGrpcHandler(sessionUsecase))
+	privacygrpc.RegisterPrivacyServiceServer(grpcServer, privacy.NewGrpcHandler(privacyUsecase))
 	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", cfg.GrpcServer.Port))
 	if err != nil {
 		zlog.Sugar().Errorf("Failed to listen on port %s: %v", cfg.GrpcServer.Port, err)
 		return nil, err
 	}
-	
+
 	return &GRPC{
 		server:   grpcServer,
 		listener: listener,
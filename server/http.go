@@ -4,7 +4,21 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"user-management/app/apikey"
+	"user-management/app/avatar"
+	"user-management/app/federation"
+	"user-management/app/group"
+	"user-management/app/invite"
+	"user-management/app/loginhistory"
+	"user-management/app/oauth"
+	"user-management/app/oidc"
+	"user-management/app/privacy"
+	"user-management/app/profile"
+	"user-management/app/scim"
+	"user-management/app/session"
+	"user-management/app/tenant"
 	"user-management/app/user"
+	"user-management/auth"
 	"user-management/config"
 	"user-management/logger"
 	"user-management/middleware"
@@ -18,7 +32,35 @@ type HTTP struct {
 	server *http.Server
 }
 
-func NewEchoHTTPServer(ctx context.Context, zlog *zap.Logger, handler user.Handler, cfg *config.AppConfig) *HTTP {
+// HTTPDeps holds what the HTTP server routes to: one handler per feature, and
+// the checks the auth middlewares run on every request.
+type HTTPDeps struct {
+	User         user.Handler
+	Tenant       tenant.Handler
+	Group        group.Handler
+	Invite       invite.Handler
+	LoginHistory loginhistory.Handler
+	Session      session.Handler
+	Profile      profile.Handler
+	Avatar       avatar.Handler
+	Privacy      privacy.Handler
+	OIDC         oidc.Handler
+	Federation   federation.Handler
+	SCIM         scim.Handler
+	APIKey       apikey.Handler
+	OAuth        oauth.Handler
+
+	// SCIMTokens authenticates requests under /scim/v2.
+	SCIMTokens middleware.SCIMAuthenticator
+	// APIKeys, Sessions, Users and Clients back the bearer token checks of
+	// the protected routes.
+	APIKeys  middleware.APIKeyAuthenticator
+	Sessions middleware.SessionChecker
+	Users    middleware.StatusChecker
+	Clients  middleware.ClientChecker
+}
+
+func NewEchoHTTPServer(ctx context.Context, zlog *zap.Logger, deps HTTPDeps, cfg *config.AppConfig) *HTTP {
 	server := echo.New()
 	server.Server.Addr = fmt.Sprintf(":%s", cfg.HttpServer.Port)
 	server.Use(echoMiddleware.Recover())
@@ -29,20 +71,136 @@ func NewEchoHTTPServer(ctx context.Context, zlog *zap.Logger, handler user.Handl
 	}))
 	server.Use(middleware.HealthCheck)
 	server.Use(middleware.NewLogging)
-	server.Use(middleware.LoggingMiddleware)
-	server.POST("/login", handler.Login)
+	server.Use(middleware.LoggingWithRedactions(logRedactions))
+	server.Use(middleware.ClientInfo)
+	server.POST("/login", deps.User.Login)
+	server.POST("/invites/accept", deps.Invite.AcceptInvite)
+	// Avatar ids cannot be guessed, so images are served without a token and
+	// can be used directly in <img> tags.
+	server.GET("/avatars/:id/:size", deps.Avatar.FindAvatar)
+	// OpenID Connect provider; clients authenticate with their own
+	// credentials, not user tokens.
+	server.GET("/.well-known/openid-configuration", deps.OIDC.Discovery)
+	server.GET("/oauth2/jwks", deps.OIDC.JWKS)
+	server.GET("/oauth2/authorize", deps.OIDC.Authorize)
+	server.POST("/oauth2/authorize", deps.OIDC.SubmitAuthorize)
+	server.POST("/oauth2/token", deps.OIDC.Token)
+	server.POST("/oauth2/introspect", deps.OIDC.Introspect)
+	server.POST("/oauth2/revoke", deps.OIDC.Revoke)
+	server.GET("/userinfo", deps.OIDC.UserInfo)
+	server.POST("/userinfo", deps.OIDC.UserInfo)
+	// OAuth 2.0 client credentials grant for service clients; the JWTs it
+	// issues are limited to their scopes like API keys.
+	server.POST("/oauth/token", deps.OAuth.Token)
+	// Login through external OpenID Connect providers.
+	server.GET("/federation/providers", deps.Federation.FindProviders)
+	server.GET("/federation/:provider/login", deps.Federation.Login)
+	server.GET("/federation/:provider/callback", deps.Federation.Callback)
+	// SCIM 2.0 provisioning. Discovery is public; resources need a SCIM
+	// token, which acts as an admin of its tenant.
+	server.GET("/scim/v2/ServiceProviderConfig", deps.SCIM.ServiceProviderConfig)
+	server.GET("/scim/v2/Schemas", deps.SCIM.Schemas)
+	server.GET("/scim/v2/Schemas/:id", deps.SCIM.Schema)
+	server.GET("/scim/v2/ResourceTypes", deps.SCIM.ResourceTypes)
+	server.GET("/scim/v2/ResourceTypes/:id", deps.SCIM.ResourceType)
+	sg := server.Group("/scim/v2", middleware.SCIMAuth(deps.SCIMTokens))
+	sg.GET("/Users", deps.SCIM.FindUsers)
+	sg.POST("/Users", deps.SCIM.CreateUser)
+	sg.GET("/Users/:id", deps.SCIM.FindUser)
+	sg.PUT("/Users/:id", deps.SCIM.ReplaceUser)
+	sg.PATCH("/Users/:id", deps.SCIM.PatchUser)
+	sg.DELETE("/Users/:id", deps.SCIM.DeleteUser)
+	sg.GET("/Groups", deps.SCIM.FindGroups)
+	sg.POST("/Groups", deps.SCIM.CreateGroup)
+	sg.GET("/Groups/:id", deps.SCIM.FindGroup)
+	sg.PUT("/Groups/:id", deps.SCIM.ReplaceGroup)
+	sg.PATCH("/Groups/:id", deps.SCIM.PatchGroup)
+	sg.DELETE("/Groups/:id", deps.SCIM.DeleteGroup)
 
-	g := server.Group("", middleware.AuthMiddleware(cfg.Crypto.JwtKey))
+	g := server.Group("", middleware.AuthMiddleware(cfg.Crypto.JwtKey, deps.Sessions, deps.Users, deps.Clients, deps.APIKeys, httpScopes))
 	//CreateUser
-	g.POST("/register", handler.CreateUser)
+	g.POST("/register", deps.User.CreateUser)
+	// ImportUsers
+	g.POST("/users/import", deps.User.ImportUsers)
 	// FindUsers
-	g.GET("/users", handler.FindUsers)
+	g.GET("/users", deps.User.FindUsers)
+	// ExportUsers
+	g.GET("/users/export", deps.User.ExportUsers)
 	// FindUserById
-	g.GET("/users/:id", handler.FindUserById)
+	g.GET("/users/:id", deps.User.FindUserById)
 	// UpdateUser
-	g.PUT("/users/:id", handler.UpdateUser)
+	g.PUT("/users/:id", deps.User.UpdateUser)
 	// DeleteUser
-	g.DELETE("/users/:id", handler.DeleteUser)
+	g.DELETE("/users/:id", deps.User.DeleteUser)
+	// ChangeStatus
+	g.PUT("/users/:id/status", deps.User.ChangeStatus)
+	// FindStatusHistory
+	g.GET("/users/:id/status/history", deps.User.FindStatusHistory)
+	// FindUserGroups
+	g.GET("/users/:id/groups", deps.Group.FindUserGroups)
+	// FindLogins
+	g.GET("/users/:id/logins", deps.LoginHistory.FindLogins)
+	// FindMyLogins
+	g.GET("/me/logins", deps.LoginHistory.FindMyLogins)
+	// Sessions
+	g.GET("/users/:id/sessions", deps.Session.FindUserSessions)
+	g.DELETE("/users/:id/sessions/:sessionId", deps.Session.RevokeUserSession)
+	g.GET("/me/sessions", deps.Session.FindMySessions)
+	g.DELETE("/me/sessions/:id", deps.Session.RevokeMySession)
+	// UploadAvatar
+	g.PUT("/me/avatar", deps.Avatar.UploadAvatar)
+	// Personal data
+	g.GET("/me/data-export", deps.Privacy.ExportMyData)
+	g.GET("/users/:id/data-export", deps.Privacy.ExportUserData)
+	g.POST("/users/:id/erasure", deps.Privacy.EraseUser)
+
+	// Profile schema
+	g.GET("/profile/schema", deps.Profile.FindSchema)
+	g.PUT("/profile/schema", deps.Profile.SaveSchema)
+
+	// Groups and membership
+	g.POST("/groups", deps.Group.CreateGroup)
+	g.GET("/groups", deps.Group.FindGroups)
+	g.GET("/groups/:id", deps.Group.FindGroupById)
+	g.PUT("/groups/:id", deps.Group.UpdateGroup)
+	g.DELETE("/groups/:id", deps.Group.DeleteGroup)
+	g.GET("/groups/:id/members", deps.Group.FindMembers)
+	g.POST("/groups/:id/members", deps.Group.AddMember)
+	g.DELETE("/groups/:id/members/:userId", deps.Group.RemoveMember)
+
+	i := g.Group("/invites", middleware.RequireRole(auth.RoleAdmin))
+	i.POST("", deps.Invite.CreateInvite)
+	i.GET("", deps.Invite.FindInvites)
+	i.DELETE("/:id", deps.Invite.RevokeInvite)
+
+	o := g.Group("/oauth2/clients", middleware.RequireRole(auth.RoleAdmin))
+	o.POST("", deps.OIDC.CreateClient)
+	o.GET("", deps.OIDC.FindClients)
+	o.GET("/:id", deps.OIDC.FindClientById)
+	o.DELETE("/:id", deps.OIDC.DeleteClient)
+
+	st := g.Group("/scim/tokens", middleware.RequireRole(auth.RoleAdmin))
+	st.POST("", deps.SCIM.CreateToken)
+	st.GET("", deps.SCIM.FindTokens)
+	st.DELETE("/:id", deps.SCIM.DeleteToken)
+
+	k := g.Group("/api-keys", middleware.RequireRole(auth.RoleAdmin))
+	k.POST("", deps.APIKey.CreateKey)
+	k.GET("", deps.APIKey.FindKeys)
+	k.DELETE("/:id", deps.APIKey.RevokeKey)
+
+	oc := g.Group("/oauth/clients", middleware.RequireRole(auth.RoleAdmin))
+	oc.POST("", deps.OAuth.CreateClient)
+	oc.GET("", deps.OAuth.FindClients)
+	oc.GET("/:id", deps.OAuth.FindClientById)
+	oc.DELETE("/:id", deps.OAuth.DeleteClient)
+
+	t := g.Group("/tenants", middleware.RequirePlatformAdmin)
+	t.POST("", deps.Tenant.CreateTenant)
+	t.GET("", deps.Tenant.FindTenants)
+	t.GET("/:id", deps.Tenant.FindTenantById)
+	t.PUT("/:id", deps.Tenant.UpdateTenant)
+	t.DELETE("/:id", deps.Tenant.DeleteTenant)
 
 	return &HTTP{server: server.Server}
 }
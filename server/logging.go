@@ -0,0 +1,44 @@
+package server
+
+import (
+	privacygrpc "user-management/app/privacy/grpc/gen/go/privacy/v1"
+	usergrpc "user-management/app/user/grpc/gen/go/user/v1"
+	"user-management/middleware"
+)
+
+// logRedactions lists the routes whose bodies must not be logged: those that
+// carry passwords, tokens or client secrets, those that return them, those
+// that return personal data in bulk, and avatar images, which are streamed
+// rather than buffered. Credentials in headers are never logged.
+var logRedactions = middleware.Redactions{
+	"POST /login":                        middleware.RedactRequest | middleware.RedactResponse,
+	"POST /register":                     middleware.RedactRequest,
+	"POST /users/import":                 middleware.RedactRequest,
+	"POST /invites/accept":               middleware.RedactRequest,
+	"POST /oauth2/authorize":             middleware.RedactRequest,
+	"POST /oauth2/token":                 middleware.RedactRequest | middleware.RedactResponse,
+	"POST /oauth2/introspect":            middleware.RedactRequest,
+	"POST /oauth2/revoke":                middleware.RedactRequest,
+	"POST /oauth2/clients":               middleware.RedactResponse,
+	"GET /federation/:provider/callback": middleware.RedactResponse,
+	"POST /scim/tokens":                  middleware.RedactResponse,
+	"POST /scim/v2/Users":                middleware.RedactRequest,
+	"PUT /scim/v2/Users/:id":             middleware.RedactRequest,
+	"PATCH /scim/v2/Users/:id":           middleware.RedactRequest,
+	"POST /api-keys":                     middleware.RedactResponse,
+	"POST /oauth/clients":                middleware.RedactResponse,
+	"POST /oauth/token":                  middleware.RedactRequest | middleware.RedactResponse,
+	"GET /users/export":                  middleware.RedactResponse,
+	"GET /me/data-export":                middleware.RedactResponse,
+	"GET /users/:id/data-export":         middleware.RedactResponse,
+	"PUT /me/avatar":                     middleware.RedactRequest,
+	"GET /avatars/:id/:size":             middleware.RedactResponse,
+}
+
+// grpcRedactedMethods is logRedactions for RPCs: their messages carry
+// passwords, tokens or a user's personal data.
+var grpcRedactedMethods = []string{
+	usergrpc.UserService_Login_FullMethodName,
+	usergrpc.UserService_CreateUser_FullMethodName,
+	privacygrpc.PrivacyService_ExportUserData_FullMethodName,
+}
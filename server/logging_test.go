@@ -0,0 +1,79 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-management/logger"
+	"user-management/middleware"
+
+	echo "github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// newLoggedServer serves routes behind the logging middlewares, with log
+// lines going to the returned observer.
+func newLoggedServer() (*echo.Echo, *observer.ObservedLogs) {
+	core, logs := observer.New(zap.InfoLevel)
+	e := echo.New()
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := context.WithValue(c.Request().Context(), logger.LogContext, zap.New(core))
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	})
+	e.Use(middleware.LoggingWithRedactions(logRedactions))
+	return e, logs
+}
+
+// assertImageNotLogged checks the request and response log lines: the body
+// of the redacted one, 0 or 1, is left out, and neither holds the image.
+func assertImageNotLogged(t *testing.T, logs *observer.ObservedLogs, redacted int, image []byte) {
+	t.Helper()
+	entries := logs.All()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "[REDACTED]", entries[redacted].ContextMap()["body"])
+	for _, entry := range entries {
+		assert.NotContains(t, entry.ContextMap()["body"], string(image))
+	}
+}
+
+func TestLogRedactions_AvatarUpload(t *testing.T) {
+	e, logs := newLoggedServer()
+	image := bytes.Repeat([]byte{0xff, 0xd8, 0xff}, 1024)
+	body := io.NopCloser(bytes.NewReader(image))
+	e.PUT("/me/avatar", func(c echo.Context) error {
+		// The handler streams the upload itself, under its own size cap.
+		assert.Equal(t, body, c.Request().Body, "the upload was buffered")
+		return c.NoContent(http.StatusOK)
+	})
+	req := httptest.NewRequest(http.MethodPut, "/me/avatar", nil)
+	req.Body = body
+	rec := httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assertImageNotLogged(t, logs, 0, image)
+}
+
+func TestLogRedactions_AvatarImage(t *testing.T) {
+	e, logs := newLoggedServer()
+	image := bytes.Repeat([]byte{0xff, 0xd8, 0xff}, 1024)
+	e.GET("/avatars/:id/:size", func(c echo.Context) error {
+		return c.Blob(http.StatusOK, "image/jpeg", image)
+	})
+	rec := httptest.NewRecorder()
+
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/avatars/a1/64", nil))
+
+	assert.Equal(t, image, rec.Body.Bytes())
+	assertImageNotLogged(t, logs, 1, image)
+}
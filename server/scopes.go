@@ -0,0 +1,51 @@
+package server
+
+import (
+	groupgrpc "user-management/app/group/grpc/gen/go/group/v1"
+	usergrpc "user-management/app/user/grpc/gen/go/user/v1"
+	"user-management/auth"
+	"user-management/middleware"
+)
+
+// httpScopes lists the routes scoped credentials may call and the scope each
+// needs. Everything else, such as sessions, personal data and /me, is closed
+// to them.
+var httpScopes = middleware.Scopes{
+	"POST /register":                     auth.ScopeUsersWrite,
+	"POST /users/import":                 auth.ScopeUsersWrite,
+	"GET /users":                         auth.ScopeUsersRead,
+	"GET /users/export":                  auth.ScopeUsersRead,
+	"GET /users/:id":                     auth.ScopeUsersRead,
+	"PUT /users/:id":                     auth.ScopeUsersWrite,
+	"DELETE /users/:id":                  auth.ScopeUsersWrite,
+	"PUT /users/:id/status":              auth.ScopeUsersWrite,
+	"GET /users/:id/status/history":      auth.ScopeUsersRead,
+	"GET /users/:id/groups":              auth.ScopeGroupsRead,
+	"POST /groups":                       auth.ScopeGroupsWrite,
+	"GET /groups":                        auth.ScopeGroupsRead,
+	"GET /groups/:id":                    auth.ScopeGroupsRead,
+	"PUT /groups/:id":                    auth.ScopeGroupsWrite,
+	"DELETE /groups/:id":                 auth.ScopeGroupsWrite,
+	"GET /groups/:id/members":            auth.ScopeGroupsRead,
+	"POST /groups/:id/members":           auth.ScopeGroupsWrite,
+	"DELETE /groups/:id/members/:userId": auth.ScopeGroupsWrite,
+}
+
+// grpcScopes is httpScopes for RPCs.
+var grpcScopes = middleware.Scopes{
+	usergrpc.UserService_CreateUser_FullMethodName:           auth.ScopeUsersWrite,
+	usergrpc.UserService_GetUser_FullMethodName:              auth.ScopeUsersRead,
+	usergrpc.UserService_ImportUsers_FullMethodName:          auth.ScopeUsersWrite,
+	usergrpc.UserService_ExportUsers_FullMethodName:          auth.ScopeUsersRead,
+	usergrpc.UserService_ChangeUserStatus_FullMethodName:     auth.ScopeUsersWrite,
+	usergrpc.UserService_GetUserStatusHistory_FullMethodName: auth.ScopeUsersRead,
+	groupgrpc.GroupService_CreateGroup_FullMethodName:        auth.ScopeGroupsWrite,
+	groupgrpc.GroupService_GetGroup_FullMethodName:           auth.ScopeGroupsRead,
+	groupgrpc.GroupService_ListGroups_FullMethodName:         auth.ScopeGroupsRead,
+	groupgrpc.GroupService_UpdateGroup_FullMethodName:        auth.ScopeGroupsWrite,
+	groupgrpc.GroupService_DeleteGroup_FullMethodName:        auth.ScopeGroupsWrite,
+	groupgrpc.GroupService_AddMember_FullMethodName:          auth.ScopeGroupsWrite,
+	groupgrpc.GroupService_RemoveMember_FullMethodName:       auth.ScopeGroupsWrite,
+	groupgrpc.GroupService_ListMembers_FullMethodName:        auth.ScopeGroupsRead,
+	groupgrpc.GroupService_ListUserGroups_FullMethodName:     auth.ScopeGroupsRead,
+}
@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EmailCollision is a set of users in one tenant whose emails are equal once
+// normalized, e.g. "Alice@Example.com" and "alice@example.com".
+type EmailCollision struct {
+	TenantID        string
+	EmailNormalized string
+	IDs             []string
+}
+
+// EmailCollisionError stops the email normalization migration until the
+// colliding accounts have been merged, renamed or removed.
+type EmailCollisionError struct {
+	Collisions []EmailCollision
+}
+
+func (e *EmailCollisionError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d email(s) are used by more than one user once normalized; resolve them and migrate again:", len(e.Collisions))
+	for _, c := range e.Collisions {
+		tenant := c.TenantID
+		if tenant == "" {
+			tenant = "platform"
+		}
+		fmt.Fprintf(&b, "\n  tenant %s, %s: users %s", tenant, c.EmailNormalized, strings.Join(c.IDs, ", "))
+	}
+	return b.String()
+}
@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// Migrator applies versioned schema changes to one database.
+type Migrator interface {
+	// Status lists every known migration and when it was applied.
+	Status(ctx context.Context) ([]MigrationStatus, error)
+	// Up applies the pending migrations in version order and returns them.
+	Up(ctx context.Context) ([]MigrationStatus, error)
+}
+
+type MigrationStatus struct {
+	Target      string     `json:"target"`
+	Version     int        `json:"version"`
+	Description string     `json:"description"`
+	AppliedAt   *time.Time `json:"applied_at,omitempty"`
+}
+
+// Pending returns the migrations in status that have not been applied.
+func Pending(status []MigrationStatus) []MigrationStatus {
+	var pending []MigrationStatus
+	for _, s := range status {
+		if s.AppliedAt == nil {
+			pending = append(pending, s)
+		}
+	}
+	return pending
+}
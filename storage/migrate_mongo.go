@@ -0,0 +1,207 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	migrationCollection     = "schema_migrations"
+	migrationLockCollection = "schema_migrations_lock"
+	migrationLockKey        = "migrations"
+
+	// A crashed runner leaves its lock behind; it is taken over once it is
+	// older than migrationLockTTL. A live runner renews it every
+	// migrationLockRenewal, so long migrations keep it.
+	migrationLockTTL     = 10 * time.Minute
+	migrationLockRenewal = migrationLockTTL / 3
+	migrationLockRetry   = time.Second
+)
+
+var errMigrationLockLost = errors.New("lost the migration lock to another runner")
+
+// MongoMigration is one versioned change to the MongoDB schema. Up must be
+// safe to run again if a previous attempt failed half way.
+type MongoMigration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database) error
+}
+
+type appliedMigration struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"applied_at"`
+}
+
+type MongoMigrator struct {
+	db         *mongo.Database
+	migrations []MongoMigration
+	owner      string
+}
+
+func NewMongoMigrator(db *mongo.Database, migrations []MongoMigration) *MongoMigrator {
+	sorted := append([]MongoMigration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	host, _ := os.Hostname()
+	return &MongoMigrator{
+		db:         db,
+		migrations: sorted,
+		owner:      host + "/" + uuid.NewString(),
+	}
+}
+
+func (m *MongoMigrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	cursor, err := m.db.Collection(migrationCollection).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	var applied []appliedMigration
+	if err := cursor.All(ctx, &applied); err != nil {
+		return nil, err
+	}
+	appliedAt := make(map[int]time.Time, len(applied))
+	for _, a := range applied {
+		appliedAt[a.Version] = a.AppliedAt
+	}
+	status := make([]MigrationStatus, len(m.migrations))
+	for i, mig := range m.migrations {
+		status[i] = MigrationStatus{Target: BackendMongo, Version: mig.Version, Description: mig.Description}
+		if at, ok := appliedAt[mig.Version]; ok {
+			status[i].AppliedAt = &at
+		}
+	}
+	return status, nil
+}
+
+// Up applies pending migrations while holding the migration lock, so only one
+// replica migrates at a time; the others wait and then find nothing to do.
+// Should the lock be lost anyway, the running migration is cancelled and
+// nothing more is recorded.
+func (m *MongoMigrator) Up(ctx context.Context) ([]MigrationStatus, error) {
+	if err := m.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer m.unlock()
+	ctx, stop := m.keepLock(ctx)
+	defer stop()
+
+	status, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var applied []MigrationStatus
+	for i, s := range status {
+		if s.AppliedAt != nil {
+			continue
+		}
+		mig := m.migrations[i]
+		if err := mig.Up(ctx, m.db); err != nil {
+			if cause := context.Cause(ctx); cause != nil {
+				err = cause
+			}
+			return applied, fmt.Errorf("migration %d (%s): %w", mig.Version, mig.Description, err)
+		}
+		if err := m.renew(ctx); err != nil {
+			return applied, fmt.Errorf("migration %d (%s): %w", mig.Version, mig.Description, err)
+		}
+		now := time.Now()
+		_, err := m.db.Collection(migrationCollection).InsertOne(ctx, appliedMigration{
+			Version:     mig.Version,
+			Description: mig.Description,
+			AppliedAt:   now,
+		})
+		if err != nil {
+			return applied, err
+		}
+		s.AppliedAt = &now
+		applied = append(applied, s)
+	}
+	return applied, nil
+}
+
+// lock takes the migration lock, waiting while another runner holds it. The
+// upsert only matches an expired lock, so a live one makes it fail with a
+// duplicate key error.
+func (m *MongoMigrator) lock(ctx context.Context) error {
+	coll := m.db.Collection(migrationLockCollection)
+	for {
+		now := time.Now()
+		_, err := coll.UpdateOne(ctx,
+			bson.M{"_id": migrationLockKey, "expires_at": bson.M{"$lt": now}},
+			bson.M{"$set": bson.M{"owner": m.owner, "locked_at": now, "expires_at": now.Add(migrationLockTTL)}},
+			options.Update().SetUpsert(true),
+		)
+		if err == nil {
+			return nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Join(errors.New("timed out waiting for the migration lock"), ctx.Err())
+		case <-time.After(migrationLockRetry):
+		}
+	}
+}
+
+// keepLock renews the lock every migrationLockRenewal until stop is called.
+// The returned context is cancelled with errMigrationLockLost once another
+// runner holds the lock.
+func (m *MongoMigrator) keepLock(ctx context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(migrationLockRenewal)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				// Other errors may be transient; the check before recording
+				// each migration catches them if they are not.
+				if err := m.renew(ctx); errors.Is(err, errMigrationLockLost) {
+					cancel(err)
+					return
+				}
+			}
+		}
+	}()
+	return ctx, func() {
+		close(done)
+		cancel(nil)
+	}
+}
+
+// renew extends the lock, failing with errMigrationLockLost when it no longer
+// belongs to this runner.
+func (m *MongoMigrator) renew(ctx context.Context) error {
+	res, err := m.db.Collection(migrationLockCollection).UpdateOne(ctx,
+		bson.M{"_id": migrationLockKey, "owner": m.owner},
+		bson.M{"$set": bson.M{"expires_at": time.Now().Add(migrationLockTTL)}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errMigrationLockLost
+	}
+	return nil
+}
+
+func (m *MongoMigrator) unlock() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	m.db.Collection(migrationLockCollection).DeleteOne(ctx, bson.M{"_id": migrationLockKey, "owner": m.owner})
+}
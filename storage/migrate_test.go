@@ -0,0 +1,242 @@
+package storage_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"user-management/config"
+	"user-management/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestMongoMigrator_Up(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("applies pending migrations under the lock", func(mt *mtest.T) {
+		var ran []int
+		step := func(v int) storage.MongoMigration {
+			return storage.MongoMigration{Version: v, Description: "step", Up: func(ctx context.Context, db *mongo.Database) error {
+				ran = append(ran, v)
+				return nil
+			}}
+		}
+		m := storage.NewMongoMigrator(mt.Client.Database("testdb"), []storage.MongoMigration{step(2), step(1)})
+
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(), // lock
+			mtest.CreateCursorResponse(0, "testdb.schema_migrations", mtest.FirstBatch, bson.D{
+				bson.E{Key: "_id", Value: 1},
+				bson.E{Key: "description", Value: "step"},
+			}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}), // still ours
+			mtest.CreateSuccessResponse(),                           // record version 2
+			mtest.CreateSuccessResponse(),                           // unlock
+		)
+
+		applied, err := m.Up(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, []int{2}, ran)
+		require.Len(t, applied, 1)
+		assert.Equal(t, 2, applied[0].Version)
+		assert.Equal(t, "update", mt.GetStartedEvent().CommandName)
+	})
+
+	mt.Run("records nothing once the lock is lost", func(mt *mtest.T) {
+		m := storage.NewMongoMigrator(mt.Client.Database("testdb"), []storage.MongoMigration{{
+			Version: 1, Description: "slow backfill", Up: func(ctx context.Context, db *mongo.Database) error { return nil },
+		}})
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(), // lock
+			mtest.CreateCursorResponse(0, "testdb.schema_migrations", mtest.FirstBatch),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}), // taken over
+			mtest.CreateSuccessResponse(),                           // unlock
+		)
+
+		applied, err := m.Up(context.Background())
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "lost the migration lock")
+		assert.Empty(t, applied)
+		var commands []string
+		for e := mt.GetStartedEvent(); e != nil; e = mt.GetStartedEvent() {
+			commands = append(commands, e.CommandName)
+		}
+		assert.NotContains(t, commands, "insert")
+	})
+
+	mt.Run("gives up waiting for a held lock", func(mt *mtest.T) {
+		m := storage.NewMongoMigrator(mt.Client.Database("testdb"), nil)
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Code: 11000, Message: "duplicate key"}))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := m.Up(ctx)
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestMongoMigrations_Versions(t *testing.T) {
+	seen := map[int]bool{}
+	for i, m := range storage.MongoMigrations(config.MongoConfig{}) {
+		assert.Equal(t, i+1, m.Version, "versions must be consecutive")
+		assert.False(t, seen[m.Version])
+		seen[m.Version] = true
+		assert.NotEmpty(t, m.Description)
+	}
+}
+
+// mongoMigration returns the migration with description.
+func mongoMigration(t *testing.T, description string) storage.MongoMigration {
+	t.Helper()
+	migrations := storage.MongoMigrations(config.MongoConfig{UserCollection: "users"})
+	i := slices.IndexFunc(migrations, func(m storage.MongoMigration) bool { return m.Description == description })
+	require.GreaterOrEqual(t, i, 0)
+	return migrations[i]
+}
+
+func TestMongoMigrations_NormalizeEmailsReportsCollisions(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("collisions", func(mt *mtest.T) {
+		normalize := mongoMigration(t, "normalize emails")
+
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "testdb.users", mtest.FirstBatch, bson.D{
+				bson.E{Key: "_id", Value: "u1"},
+				bson.E{Key: "email", Value: "alice@example.com"},
+				bson.E{Key: "email_normalized", Value: "alice@example.com"},
+			}),
+			mtest.CreateCursorResponse(0, "testdb.users", mtest.FirstBatch, bson.D{
+				bson.E{Key: "_id", Value: bson.D{
+					bson.E{Key: "tenant_id", Value: "t1"},
+					bson.E{Key: "email", Value: "alice@example.com"},
+				}},
+				bson.E{Key: "ids", Value: bson.A{"u1", "u2"}},
+				bson.E{Key: "count", Value: 2},
+			}),
+		)
+
+		err := normalize.Up(context.Background(), mt.Client.Database("testdb"))
+
+		var collisionErr *storage.EmailCollisionError
+		require.ErrorAs(t, err, &collisionErr)
+		assert.Equal(t, []storage.EmailCollision{{TenantID: "t1", EmailNormalized: "alice@example.com", IDs: []string{"u1", "u2"}}}, collisionErr.Collisions)
+		assert.Contains(t, err.Error(), "tenant t1, alice@example.com: users u1, u2")
+	})
+}
+
+func TestMongoMigrations_BackfillRolesPromotesSeededAdmin(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("seeded admin", func(mt *mtest.T) {
+		backfill := mongoMigration(t, "backfill missing user roles")
+		mt.AddMockResponses(mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse())
+
+		require.NoError(t, backfill.Up(context.Background(), mt.Client.Database("testdb")))
+
+		var updates []bson.Raw
+		for range 2 {
+			e := mt.GetStartedEvent()
+			require.Equal(t, "update", e.CommandName)
+			updates = append(updates, e.Command.Lookup("updates", "0").Document())
+		}
+		assert.Equal(t, "admin@example.com", updates[0].Lookup("q", "email").StringValue())
+		assert.Equal(t, "admin", updates[0].Lookup("u", "$set", "role").StringValue())
+		assert.Equal(t, "user", updates[1].Lookup("u", "$set", "role").StringValue())
+	})
+}
+
+func TestMongoMigrations_DropGlobalEmailIndex(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	drop := mongoMigration(t, "drop global email index")
+
+	mt.Run("index from init.js", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		require.NoError(t, drop.Up(context.Background(), mt.Client.Database("testdb")))
+
+		e := mt.GetStartedEvent()
+		assert.Equal(t, "dropIndexes", e.CommandName)
+		assert.Equal(t, "email_1", e.Command.Lookup("index").StringValue())
+	})
+
+	mt.Run("no such index", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 27, Message: "index not found with name [email_1]"}))
+
+		assert.NoError(t, drop.Up(context.Background(), mt.Client.Database("testdb")))
+	})
+}
+
+func TestSQLMigrator_NormalizeEmailsReportsCollisions(t *testing.T) {
+	conn, err := storage.OpenSQL(context.Background(), config.StorageConfig{
+		Backend: storage.BackendSQLite,
+		SQLDSN:  filepath.Join(t.TempDir(), "test.db"),
+	})
+	require.NoError(t, err)
+	defer conn.Close()
+	// Recreate a database that stopped at version 1, with emails that only
+	// differ by case.
+	schema, err := os.ReadFile("migrations/sqlite/0001_create_users.sql")
+	require.NoError(t, err)
+	_, err = conn.DB.Exec(string(schema))
+	require.NoError(t, err)
+	_, err = conn.DB.Exec(`CREATE TABLE schema_migrations (version INTEGER PRIMARY KEY, applied_at TIMESTAMP NOT NULL);
+		INSERT INTO schema_migrations VALUES (1, CURRENT_TIMESTAMP);
+		INSERT INTO users (id, name, email, password, created_at) VALUES
+			('u1', 'A', 'Alice@Example.com', 'x', '2025-01-01 00:00:00'),
+			('u2', 'B', 'alice@example.com', 'x', '2025-01-02 00:00:00'),
+			('u3', 'C', 'carol@example.com', 'x', '2025-01-03 00:00:00')`)
+	require.NoError(t, err)
+
+	_, err = storage.NewSQLMigrator(conn).Up(context.Background())
+
+	var collisionErr *storage.EmailCollisionError
+	require.ErrorAs(t, err, &collisionErr)
+	assert.Equal(t, []storage.EmailCollision{{EmailNormalized: "alice@example.com", IDs: []string{"u1", "u2"}}}, collisionErr.Collisions)
+	assert.Contains(t, err.Error(), "tenant platform, alice@example.com: users u1, u2")
+
+	// Nothing was applied, so fixing the data and migrating again works.
+	_, err = conn.DB.Exec("UPDATE users SET email = 'alice2@example.com' WHERE id = 'u2'")
+	require.NoError(t, err)
+	applied, err := storage.NewSQLMigrator(conn).Up(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, applied)
+	assert.Equal(t, 2, applied[0].Version)
+	assert.Equal(t, "normalize emails", applied[0].Description)
+}
+
+func TestSQLMigrator(t *testing.T) {
+	conn, err := storage.OpenSQL(context.Background(), config.StorageConfig{
+		Backend: storage.BackendSQLite,
+		SQLDSN:  filepath.Join(t.TempDir(), "test.db"),
+	})
+	require.NoError(t, err)
+	defer conn.Close()
+	m := storage.NewSQLMigrator(conn)
+
+	status, err := m.Status(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, status)
+	assert.Len(t, storage.Pending(status), len(status))
+
+	applied, err := m.Up(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, applied, len(status))
+	assert.Equal(t, "create users", applied[0].Description)
+
+	applied, err = m.Up(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+	status, err = m.Status(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, storage.Pending(status))
+}
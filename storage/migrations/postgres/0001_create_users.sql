@@ -0,0 +1,13 @@
+CREATE TABLE users (
+    id         TEXT PRIMARY KEY,
+    tenant_id  TEXT NOT NULL DEFAULT '',
+    name       TEXT NOT NULL,
+    email      TEXT NOT NULL,
+    password   TEXT NOT NULL,
+    role       TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+-- Emails are unique per tenant; platform users have an empty tenant_id.
+CREATE UNIQUE INDEX users_tenant_id_email ON users (tenant_id, email);
+CREATE INDEX users_tenant_id_created_at ON users (tenant_id, created_at);
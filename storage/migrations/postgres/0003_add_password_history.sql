@@ -0,0 +1,2 @@
+-- Hashes of earlier passwords as a JSON array, newest first.
+ALTER TABLE users ADD COLUMN password_history TEXT NOT NULL DEFAULT '[]';
@@ -0,0 +1,2 @@
+-- NULL until the user's first successful login.
+ALTER TABLE users ADD COLUMN last_login_at TIMESTAMPTZ;
@@ -0,0 +1,4 @@
+-- Existing accounts are active. status_history is a JSON array of changes,
+-- oldest first.
+ALTER TABLE users ADD COLUMN status TEXT NOT NULL DEFAULT 'active';
+ALTER TABLE users ADD COLUMN status_history TEXT NOT NULL DEFAULT '[]';
@@ -0,0 +1,4 @@
+-- profile is a JSON object of custom attributes. The GIN index serves
+-- containment filters on any attribute.
+ALTER TABLE users ADD COLUMN profile TEXT NOT NULL DEFAULT '{}';
+CREATE INDEX users_profile ON users USING gin ((profile::jsonb));
@@ -0,0 +1,2 @@
+-- profile is a JSON object of custom attributes.
+ALTER TABLE users ADD COLUMN profile TEXT NOT NULL DEFAULT '{}';
@@ -0,0 +1,2 @@
+-- avatar_id names the user's current avatar; empty when they have none.
+ALTER TABLE users ADD COLUMN avatar_id TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,369 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"user-management/auth"
+	"user-management/config"
+	"user-management/emailaddr"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// expiredInviteRetention is how long an expired invite stays listable before
+// the TTL monitor removes it.
+const expiredInviteRetention = 30 * 24 * 60 * 60
+
+// seededAdminEmail is the admin the old mongo-init/init.js inserted, without a
+// role or tenant.
+const seededAdminEmail = "admin@example.com"
+
+// MongoMigrations is the schema history of the application's collections.
+// Append new versions; never edit or renumber one that has shipped.
+func MongoMigrations(cfg config.MongoConfig) []MongoMigration {
+	return []MongoMigration{
+		{
+			Version:     1,
+			Description: "create user indexes",
+			Up: createIndexes(cfg.UserCollection,
+				// Emails are unique per tenant; platform users have no tenant_id.
+				mongo.IndexModel{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "email", Value: 1}}, Options: options.Index().SetUnique(true)},
+				mongo.IndexModel{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: 1}}},
+			),
+		},
+		{
+			Version:     2,
+			Description: "create tenant indexes",
+			Up: createIndexes(cfg.TenantCollection,
+				mongo.IndexModel{Keys: bson.D{{Key: "slug", Value: 1}}, Options: options.Index().SetUnique(true)},
+			),
+		},
+		{
+			Version:     3,
+			Description: "create group indexes",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				err := createIndexes(cfg.GroupCollection,
+					mongo.IndexModel{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "name", Value: 1}}, Options: options.Index().SetUnique(true)},
+				)(ctx, db)
+				if err != nil {
+					return err
+				}
+				return createIndexes(cfg.GroupMemberCollection,
+					mongo.IndexModel{Keys: bson.D{{Key: "group_id", Value: 1}, {Key: "user_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+					mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}}},
+				)(ctx, db)
+			},
+		},
+		{
+			Version:     4,
+			Description: "create invite indexes",
+			Up: createIndexes(cfg.InviteCollection,
+				mongo.IndexModel{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+				mongo.IndexModel{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: -1}}},
+			),
+		},
+		{
+			Version:     5,
+			Description: "purge invites 30 days after they expire",
+			Up: createIndexes(cfg.InviteCollection,
+				mongo.IndexModel{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(expiredInviteRetention)},
+			),
+		},
+		{
+			Version:     6,
+			Description: "create user text index",
+			Up: createIndexes(cfg.UserCollection,
+				mongo.IndexModel{Keys: bson.D{{Key: "name", Value: "text"}, {Key: "email", Value: "text"}}},
+			),
+		},
+		{
+			Version:     7,
+			Description: "backfill missing user roles",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				// Accounts from before roles existed become users, except the
+				// admin seeded by mongo-init/init.js, which is promoted. The
+				// bootstrap admin is only created in an empty collection, so
+				// demoting the seeded one would leave no admin behind.
+				users := db.Collection(cfg.UserCollection)
+				_, err := users.UpdateMany(ctx,
+					bson.M{"role": bson.M{"$exists": false}, "email": seededAdminEmail, "tenant_id": bson.M{"$exists": false}},
+					bson.M{"$set": bson.M{"role": auth.RoleAdmin}},
+				)
+				if err != nil {
+					return err
+				}
+				_, err = users.UpdateMany(ctx,
+					bson.M{"role": bson.M{"$exists": false}},
+					bson.M{"$set": bson.M{"role": auth.RoleUser}},
+				)
+				return err
+			},
+		},
+		{
+			Version:     8,
+			Description: "normalize emails",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				return normalizeMongoEmails(ctx, db.Collection(cfg.UserCollection))
+			},
+		},
+		{
+			Version:     9,
+			Description: "create login history indexes",
+			Up: createIndexes(cfg.LoginHistoryCollection,
+				mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+				// Every attempt carries its own expiry; see config.LoginHistoryConfig.
+				mongo.IndexModel{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+			),
+		},
+		{
+			Version:     10,
+			Description: "create session indexes",
+			Up: createIndexes(cfg.SessionCollection,
+				mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "last_seen_at", Value: -1}}},
+				// Sessions end with the token they were issued for.
+				mongo.IndexModel{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+			),
+		},
+		{
+			Version:     11,
+			Description: "backfill user statuses",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				// Accounts created before statuses existed are active
+				// (user.StatusActive).
+				_, err := db.Collection(cfg.UserCollection).UpdateMany(ctx,
+					bson.M{"status": bson.M{"$exists": false}},
+					bson.M{"$set": bson.M{"status": "active"}},
+				)
+				return err
+			},
+		},
+		{
+			Version:     12,
+			Description: "create profile indexes",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				err := createIndexes(cfg.ProfileSchemaCollection,
+					mongo.IndexModel{Keys: bson.D{{Key: "tenant_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+				)(ctx, db)
+				if err != nil {
+					return err
+				}
+				// One wildcard index serves filters on any profile attribute.
+				return createIndexes(cfg.UserCollection,
+					mongo.IndexModel{Keys: bson.D{{Key: "profile.$**", Value: 1}}},
+				)(ctx, db)
+			},
+		},
+		{
+			Version:     13,
+			Description: "create oidc indexes",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				err := createIndexes(cfg.OIDCClientCollection,
+					mongo.IndexModel{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: 1}}},
+				)(ctx, db)
+				if err != nil {
+					return err
+				}
+				// Codes and tokens are looked up by hash and dropped once
+				// they expire.
+				err = createIndexes(cfg.OIDCCodeCollection,
+					mongo.IndexModel{Keys: bson.D{{Key: "code_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+					mongo.IndexModel{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+				)(ctx, db)
+				if err != nil {
+					return err
+				}
+				return createIndexes(cfg.OIDCTokenCollection,
+					mongo.IndexModel{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+					mongo.IndexModel{Keys: bson.D{{Key: "client_id", Value: 1}}},
+					mongo.IndexModel{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+				)(ctx, db)
+			},
+		},
+		{
+			Version:     14,
+			Description: "create federation indexes",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				err := createIndexes(cfg.IdentityLinkCollection,
+					mongo.IndexModel{Keys: bson.D{{Key: "provider", Value: 1}, {Key: "subject", Value: 1}}, Options: options.Index().SetUnique(true)},
+					mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}}},
+				)(ctx, db)
+				if err != nil {
+					return err
+				}
+				return createIndexes(cfg.FederationStateCollection,
+					mongo.IndexModel{Keys: bson.D{{Key: "state_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+					mongo.IndexModel{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+				)(ctx, db)
+			},
+		},
+		{
+			Version:     15,
+			Description: "create scim token indexes",
+			Up: createIndexes(cfg.SCIMTokenCollection,
+				mongo.IndexModel{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+				mongo.IndexModel{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: 1}}},
+			),
+		},
+		{
+			Version:     16,
+			Description: "create api key indexes",
+			Up: createIndexes(cfg.APIKeyCollection,
+				mongo.IndexModel{Keys: bson.D{{Key: "key_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+				mongo.IndexModel{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: 1}}},
+			),
+		},
+		{
+			Version:     17,
+			Description: "create oauth client indexes",
+			Up: createIndexes(cfg.OAuthClientCollection,
+				mongo.IndexModel{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: 1}}},
+			),
+		},
+		{
+			Version:     18,
+			Description: "drop user text index",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				// Names and emails may be encrypted, which leaves nothing for
+				// the text index of version 6 to match.
+				_, err := db.Collection(cfg.UserCollection).Indexes().DropOne(ctx, "name_text_email_text")
+				if err != nil && !isIndexNotFound(err) {
+					return err
+				}
+				return nil
+			},
+		},
+		{
+			Version:     19,
+			Description: "drop global email index",
+			Up: func(ctx context.Context, db *mongo.Database) error {
+				// The old mongo-init/init.js made emails unique across all
+				// tenants, and on the typed rather than the normalized email.
+				_, err := db.Collection(cfg.UserCollection).Indexes().DropOne(ctx, "email_1")
+				if err != nil && !isIndexNotFound(err) {
+					return err
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// normalizeMongoEmails fills email_normalized for every user and moves the
+// unique index from email onto it. Users whose emails collide once normalized
+// are reported and the index is left unchanged.
+//
+// The index is a plain one on the normalized field rather than a collation
+// index on email: a collation (strength 2) only folds case, not whitespace,
+// Unicode forms or internationalized domains; it would not apply to encrypted
+// emails; and SQL backends have no portable equivalent. The user repositories
+// normalize email_normalized on every write instead.
+func normalizeMongoEmails(ctx context.Context, users *mongo.Collection) error {
+	cursor, err := users.Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"email": 1, "email_normalized": 1}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+	var updates []mongo.WriteModel
+	flush := func() error {
+		if len(updates) == 0 {
+			return nil
+		}
+		_, err := users.BulkWrite(ctx, updates, options.BulkWrite().SetOrdered(false))
+		updates = updates[:0]
+		return err
+	}
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID              interface{} `bson:"_id"`
+			Email           string      `bson:"email"`
+			EmailNormalized string      `bson:"email_normalized"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+		normalized := emailaddr.Normalize(doc.Email)
+		if normalized == doc.EmailNormalized {
+			continue
+		}
+		updates = append(updates, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": doc.ID}).
+			SetUpdate(bson.M{"$set": bson.M{"email_normalized": normalized}}))
+		if len(updates) == 500 {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	collisions, err := findMongoEmailCollisions(ctx, users)
+	if err != nil {
+		return err
+	}
+	if len(collisions) > 0 {
+		return &EmailCollisionError{Collisions: collisions}
+	}
+
+	if _, err := users.Indexes().DropOne(ctx, "tenant_id_1_email_1"); err != nil && !isIndexNotFound(err) {
+		return err
+	}
+	_, err = users.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "tenant_id", Value: 1}, {Key: "email_normalized", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+func findMongoEmailCollisions(ctx context.Context, users *mongo.Collection) ([]EmailCollision, error) {
+	cursor, err := users.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"tenant_id": "$tenant_id", "email": "$email_normalized"},
+			"ids":   bson.M{"$push": bson.M{"$toString": "$_id"}},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$match", Value: bson.M{"count": bson.M{"$gt": 1}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id.tenant_id", Value: 1}, {Key: "_id.email", Value: 1}}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var groups []struct {
+		Key struct {
+			TenantID string `bson:"tenant_id"`
+			Email    string `bson:"email"`
+		} `bson:"_id"`
+		IDs []string `bson:"ids"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+	collisions := make([]EmailCollision, len(groups))
+	for i, g := range groups {
+		collisions[i] = EmailCollision{TenantID: g.Key.TenantID, EmailNormalized: g.Key.Email, IDs: g.IDs}
+	}
+	return collisions, nil
+}
+
+// isIndexNotFound reports whether err says the index to drop does not exist,
+// e.g. on a database created before the index was managed by migrations.
+func isIndexNotFound(err error) bool {
+	var cmdErr mongo.CommandError
+	return errors.As(err, &cmdErr) && (cmdErr.Code == 27 || cmdErr.HasErrorMessage("index not found"))
+}
+
+// createIndexes returns a migration step creating models on collection.
+// Creating an index that already exists with the same definition is a no-op,
+// so a step that failed half way can run again.
+func createIndexes(collection string, models ...mongo.IndexModel) func(context.Context, *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection(collection).Indexes().CreateMany(ctx, models)
+		return err
+	}
+}
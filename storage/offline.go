@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrNoDatabase = errors.New("no database is configured")
+
+// OfflineConn stands in for MongoDB when the service runs without a database
+// (STORAGE_BACKEND=memory and no MONGO_CONFIG_URI). Every operation fails with
+// ErrNoDatabase.
+type OfflineConn struct{}
+
+func NewOfflineConn() *OfflineConn {
+	return &OfflineConn{}
+}
+
+func (OfflineConn) Disconnect(ctx context.Context) {}
+
+func (OfflineConn) Collection(name string) CollectionInterface {
+	return offlineCollection{}
+}
+
+type offlineCollection struct{}
+
+func (offlineCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	return nil, ErrNoDatabase
+}
+
+func (offlineCollection) FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) *mongo.SingleResult {
+	return mongo.NewSingleResultFromDocument(nil, ErrNoDatabase, nil)
+}
+
+func (offlineCollection) FindOneAndDelete(ctx context.Context, filter interface{}, opts ...*options.FindOneAndDeleteOptions) *mongo.SingleResult {
+	return mongo.NewSingleResultFromDocument(nil, ErrNoDatabase, nil)
+}
+
+func (offlineCollection) InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
+	return nil, ErrNoDatabase
+}
+
+func (offlineCollection) InsertMany(ctx context.Context, documents []interface{}, opts ...*options.InsertManyOptions) (*mongo.InsertManyResult, error) {
+	return nil, ErrNoDatabase
+}
+
+func (offlineCollection) UpdateByID(ctx context.Context, id interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	return nil, ErrNoDatabase
+}
+
+func (offlineCollection) UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	return nil, ErrNoDatabase
+}
+
+func (offlineCollection) DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	return nil, ErrNoDatabase
+}
+
+func (offlineCollection) DeleteMany(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	return nil, ErrNoDatabase
+}
+
+func (offlineCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	return 0, ErrNoDatabase
+}
+
+func (offlineCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	return nil, ErrNoDatabase
+}
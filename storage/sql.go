@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"user-management/config"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Storage backends selectable through STORAGE_BACKEND.
+const (
+	BackendMongo    = "mongo"
+	BackendPostgres = "postgres"
+	BackendSQLite   = "sqlite"
+	BackendMemory   = "memory"
+)
+
+// Dialect hides the differences between the SQL databases we support.
+// Queries are written with ? placeholders and rebound per dialect.
+type Dialect string
+
+const (
+	DialectPostgres Dialect = BackendPostgres
+	DialectSQLite   Dialect = BackendSQLite
+)
+
+// Rebind rewrites ? placeholders into the form the dialect expects.
+func (d Dialect) Rebind(query string) string {
+	if d != DialectPostgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// JSONMatch returns a condition, and its arguments, that holds when the JSON
+// object stored as text in column has each key of fields set to its value.
+// Keys must be plain identifiers. On PostgreSQL it is a containment test, which
+// a GIN index on the column cast to jsonb can serve.
+func (d Dialect) JSONMatch(column string, fields map[string]any) (string, []any, error) {
+	if d == DialectPostgres {
+		doc, err := json.Marshal(fields)
+		if err != nil {
+			return "", nil, err
+		}
+		return "(" + column + ")::jsonb @> CAST(? AS jsonb)", []any{string(doc)}, nil
+	}
+	var conds []string
+	var args []any
+	for _, key := range slices.Sorted(maps.Keys(fields)) {
+		conds = append(conds, "json_extract("+column+", ?) = ?")
+		args = append(args, "$."+key, fields[key])
+	}
+	return strings.Join(conds, " AND "), args, nil
+}
+
+// IsUniqueViolation reports whether err was caused by a unique constraint.
+func (d Dialect) IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == "23505"
+	}
+	return isSQLiteUniqueViolation(err)
+}
+
+type SQLConn struct {
+	DB      *sql.DB
+	Dialect Dialect
+}
+
+// OpenSQL connects to the SQL database named by cfg and, unless
+// cfg.MigrateOnStart is off, applies any pending schema migrations.
+func OpenSQL(ctx context.Context, cfg config.StorageConfig) (*SQLConn, error) {
+	var driver string
+	switch cfg.Backend {
+	case BackendPostgres:
+		driver = "pgx"
+	case BackendSQLite:
+		driver = "sqlite3"
+	default:
+		return nil, fmt.Errorf("storage backend %q is not a SQL backend", cfg.Backend)
+	}
+	if cfg.SQLDSN == "" {
+		return nil, errors.New("STORAGE_SQL_DSN is required for SQL storage backends")
+	}
+	db, err := sql.Open(driver, cfg.SQLDSN)
+	if err != nil {
+		return nil, err
+	}
+	if driver == "sqlite3" {
+		// SQLite allows one writer at a time, and every connection to an
+		// in-memory database is a separate database.
+		db.SetMaxOpenConns(1)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	conn := &SQLConn{DB: db, Dialect: Dialect(cfg.Backend)}
+	if !cfg.MigrateOnStart {
+		return conn, nil
+	}
+	if err := MigrateSQL(ctx, conn); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (c *SQLConn) Close() error {
+	return c.DB.Close()
+}
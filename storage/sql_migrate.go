@@ -0,0 +1,178 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed migrations
+var sqlMigrations embed.FS
+
+// migrationLockID keys the Postgres advisory lock that keeps concurrent
+// replicas from migrating at the same time.
+const migrationLockID = 7263105
+
+// sqlMigration is either an embedded SQL file (body) or a step written in Go
+// (up) for changes SQL alone cannot express.
+type sqlMigration struct {
+	version     int
+	description string
+	body        string
+	up          func(ctx context.Context, tx *sql.Tx, d Dialect) error
+}
+
+// SQLMigrator applies the embedded migrations for the connection's dialect
+// together with sqlCodeMigrations. Files are named <version>_<description>.sql
+// and each migration runs in its own transaction together with its
+// schema_migrations record.
+type SQLMigrator struct {
+	conn *SQLConn
+}
+
+func NewSQLMigrator(conn *SQLConn) *SQLMigrator {
+	return &SQLMigrator{conn: conn}
+}
+
+// MigrateSQL applies every pending migration on conn.
+func MigrateSQL(ctx context.Context, conn *SQLConn) error {
+	_, err := NewSQLMigrator(conn).Up(ctx)
+	return err
+}
+
+func (m *SQLMigrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	migrations, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := m.conn.DB.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	appliedAt := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		appliedAt[version] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	status := make([]MigrationStatus, len(migrations))
+	for i, mig := range migrations {
+		status[i] = MigrationStatus{Target: string(m.conn.Dialect), Version: mig.version, Description: mig.description}
+		if at, ok := appliedAt[mig.version]; ok {
+			status[i].AppliedAt = &at
+		}
+	}
+	return status, nil
+}
+
+func (m *SQLMigrator) Up(ctx context.Context) ([]MigrationStatus, error) {
+	migrations, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+	var applied []MigrationStatus
+	for _, mig := range migrations {
+		at, err := m.apply(ctx, mig)
+		if err != nil {
+			return applied, fmt.Errorf("migration %d (%s): %w", mig.version, mig.description, err)
+		}
+		if at != nil {
+			applied = append(applied, MigrationStatus{Target: string(m.conn.Dialect), Version: mig.version, Description: mig.description, AppliedAt: at})
+		}
+	}
+	return applied, nil
+}
+
+func (m *SQLMigrator) load() ([]sqlMigration, error) {
+	dir := path.Join("migrations", string(m.conn.Dialect))
+	entries, err := fs.ReadDir(sqlMigrations, dir)
+	if err != nil {
+		return nil, err
+	}
+	migrations := make([]sqlMigration, 0, len(entries))
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), ".sql")
+		version, description, _ := strings.Cut(name, "_")
+		v, err := strconv.Atoi(version)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: file name must start with a version number", e.Name())
+		}
+		body, err := sqlMigrations.ReadFile(path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, sqlMigration{version: v, description: strings.ReplaceAll(description, "_", " "), body: string(body)})
+	}
+	migrations = append(migrations, sqlCodeMigrations...)
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].version == migrations[i-1].version {
+			return nil, fmt.Errorf("migration version %d is defined twice", migrations[i].version)
+		}
+	}
+	return migrations, nil
+}
+
+func (m *SQLMigrator) ensureTable(ctx context.Context) error {
+	_, err := m.conn.DB.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		applied_at TIMESTAMP NOT NULL
+	)`)
+	return err
+}
+
+// apply runs mig unless it is already recorded, returning when it was applied
+// or nil if there was nothing to do.
+func (m *SQLMigrator) apply(ctx context.Context, mig sqlMigration) (*time.Time, error) {
+	tx, err := m.conn.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if m.conn.Dialect == DialectPostgres {
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", migrationLockID); err != nil {
+			return nil, err
+		}
+	}
+	var count int
+	if err := tx.QueryRowContext(ctx, m.conn.Dialect.Rebind("SELECT COUNT(*) FROM schema_migrations WHERE version = ?"), mig.version).Scan(&count); err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, nil
+	}
+	if mig.up != nil {
+		err = mig.up(ctx, tx, m.conn.Dialect)
+	} else {
+		_, err = tx.ExecContext(ctx, mig.body)
+	}
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	if _, err := tx.ExecContext(ctx, m.conn.Dialect.Rebind("INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)"), mig.version, now); err != nil {
+		return nil, err
+	}
+	return &now, tx.Commit()
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"user-management/emailaddr"
+)
+
+// sqlCodeMigrations are the SQL migrations written in Go. They share one
+// version sequence with the files in migrations/<dialect>.
+var sqlCodeMigrations = []sqlMigration{
+	{version: 2, description: "normalize emails", up: normalizeSQLEmails},
+}
+
+// normalizeSQLEmails adds email_normalized, fills it for existing users and
+// moves the unique index onto it. Users whose emails collide once normalized
+// are reported and the migration is not applied.
+func normalizeSQLEmails(ctx context.Context, tx *sql.Tx, d Dialect) error {
+	if _, err := tx.ExecContext(ctx, "ALTER TABLE users ADD COLUMN email_normalized TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	type row struct{ id, tenantID, email string }
+	rows, err := tx.QueryContext(ctx, "SELECT id, tenant_id, email FROM users ORDER BY created_at, id")
+	if err != nil {
+		return err
+	}
+	var users []row
+	for rows.Next() {
+		var r row
+		if err := rows.Scan(&r.id, &r.tenantID, &r.email); err != nil {
+			rows.Close()
+			return err
+		}
+		users = append(users, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	type key struct{ tenantID, email string }
+	ids := map[key][]string{}
+	var order []key
+	for _, u := range users {
+		k := key{u.tenantID, emailaddr.Normalize(u.email)}
+		if _, ok := ids[k]; !ok {
+			order = append(order, k)
+		}
+		ids[k] = append(ids[k], u.id)
+		if _, err := tx.ExecContext(ctx, d.Rebind("UPDATE users SET email_normalized = ? WHERE id = ?"), k.email, u.id); err != nil {
+			return err
+		}
+	}
+	var collisions []EmailCollision
+	for _, k := range order {
+		if len(ids[k]) > 1 {
+			collisions = append(collisions, EmailCollision{TenantID: k.tenantID, EmailNormalized: k.email, IDs: ids[k]})
+		}
+	}
+	if len(collisions) > 0 {
+		sort.SliceStable(collisions, func(i, j int) bool { return collisions[i].TenantID < collisions[j].TenantID })
+		return &EmailCollisionError{Collisions: collisions}
+	}
+
+	if _, err := tx.ExecContext(ctx, "DROP INDEX users_tenant_id_email"); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "CREATE UNIQUE INDEX users_tenant_id_email_normalized ON users (tenant_id, email_normalized)")
+	return err
+}
@@ -0,0 +1,9 @@
+//go:build !cgo
+
+package storage
+
+// The sqlite3 driver needs cgo. Without it the driver's stub fails every
+// open, so there are no SQLite errors to recognize.
+func isSQLiteUniqueViolation(err error) bool {
+	return false
+}
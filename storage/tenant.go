@@ -0,0 +1,23 @@
+package storage
+
+import (
+	"context"
+	"user-management/auth"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TenantFilter restricts filter to the tenant ctx is scoped to. Platform-scoped
+// contexts (empty tenant) match only documents that do not belong to any tenant.
+func TenantFilter(ctx context.Context, filter bson.M) bson.M {
+	tenantID, scoped := auth.TenantFromContext(ctx)
+	if !scoped {
+		return filter
+	}
+	if tenantID == "" {
+		filter["tenant_id"] = nil
+	} else {
+		filter["tenant_id"] = tenantID
+	}
+	return filter
+}